@@ -21,9 +21,12 @@ func main() {
 
 	admissionCmd.RunAdmissionServer(
 		&hivevalidatingwebhooks.DNSZoneValidatingAdmissionHook{},
+		&hivevalidatingwebhooks.ClusterClaimValidatingAdmissionHook{},
 		hivevalidatingwebhooks.NewClusterDeploymentValidatingAdmissionHook(),
 		&hivevalidatingwebhooks.ClusterImageSetValidatingAdmissionHook{},
 		&hivevalidatingwebhooks.ClusterProvisionValidatingAdmissionHook{},
+		&hivevalidatingwebhooks.ClusterUpgradePolicyValidatingAdmissionHook{},
+		&hivevalidatingwebhooks.InstallFailureRuleValidatingAdmissionHook{},
 		&hivevalidatingwebhooks.MachinePoolValidatingAdmissionHook{},
 		&hivevalidatingwebhooks.SyncSetValidatingAdmissionHook{},
 		&hivevalidatingwebhooks.SelectorSyncSetValidatingAdmissionHook{},
@@ -2,6 +2,7 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	golog "log"
 	"math/rand"
 	"net/http"
@@ -78,12 +79,21 @@ func newRootCommand() *cobra.Command {
 				log.Fatal(err)
 			}
 
+			// Each shard of the controllers elects its own leader. The shard count is part of the election ID so that,
+			// while the shard count changes, a pod of the old shard count does not hold the lease of a new shard with
+			// the same index, which owns other namespaces.
+			leaderElectionID := leaderElectionConfigMap
+			if shard := utils.GetShard(); shard.Sharded() {
+				log.WithField("shard", shard.Index).WithField("shardCount", shard.Count).Info("running controllers shard")
+				leaderElectionID = fmt.Sprintf("%s-shard-%d-of-%d", leaderElectionConfigMap, shard.Index, shard.Count)
+			}
+
 			// Create a new Cmd to provide shared dependencies and start components
 			mgr, err := manager.New(cfg, manager.Options{
 				MetricsBindAddress:      ":2112",
 				LeaderElection:          true,
 				LeaderElectionNamespace: constants.HiveNamespace,
-				LeaderElectionID:        leaderElectionConfigMap,
+				LeaderElectionID:        leaderElectionID,
 				LeaseDuration:           &leaseDuration,
 				RenewDeadline:           &renewDeadline,
 				RetryPeriod:             &retryPeriod,
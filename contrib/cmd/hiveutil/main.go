@@ -11,6 +11,7 @@ import (
 	"github.com/openshift/hive/contrib/pkg/certificate"
 	"github.com/openshift/hive/contrib/pkg/createcluster"
 	"github.com/openshift/hive/contrib/pkg/deprovision"
+	"github.com/openshift/hive/contrib/pkg/installlogs"
 	"github.com/openshift/hive/contrib/pkg/labelobjects"
 	"github.com/openshift/hive/contrib/pkg/report"
 	"github.com/openshift/hive/contrib/pkg/testresource"
@@ -47,6 +48,7 @@ func newHiveutilCommand() *cobra.Command {
 	cmd.AddCommand(verification.NewVerifyImportsCommand())
 	cmd.AddCommand(installmanager.NewInstallManagerCommand())
 	cmd.AddCommand(imageset.NewUpdateInstallerImageCommand())
+	cmd.AddCommand(imageset.NewUpdateClusterImageSetCommand())
 	cmd.AddCommand(testresource.NewTestResourceCommand())
 	cmd.AddCommand(createcluster.NewCreateClusterCommand())
 	cmd.AddCommand(report.NewClusterReportCommand())
@@ -54,6 +56,7 @@ func newHiveutilCommand() *cobra.Command {
 	cmd.AddCommand(certificate.NewCertificateCommand())
 	cmd.AddCommand(adm.NewAdmCommand())
 	cmd.AddCommand(labelobjects.NewLabelObjectsCommand())
+	cmd.AddCommand(installlogs.NewFetchInstallLogsCommand())
 
 	return cmd
 }
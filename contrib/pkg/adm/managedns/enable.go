@@ -3,6 +3,8 @@ package managedns
 import (
 	"context"
 	"fmt"
+	"io/ioutil"
+	"os"
 	"os/user"
 	"path/filepath"
 	"time"
@@ -49,6 +51,7 @@ the ExternalDNS section of HiveConfig.
 const (
 	cloudAWS                = "aws"
 	cloudGCP                = "gcp"
+	cloudAzure              = "azure"
 	hiveNamespace           = "hive"
 	hiveAdmissionDeployment = "hiveadmission"
 	hiveConfigName          = "hive"
@@ -57,9 +60,10 @@ const (
 
 // Options is the set of options to generate and apply a new cluster deployment
 type Options struct {
-	Cloud     string
-	CredsFile string
-	homeDir   string
+	Cloud                  string
+	CredsFile              string
+	AzureResourceGroupName string
+	homeDir                string
 
 	dynamicClient dynamic.Interface
 	hiveClient    *hiveclient.Clientset
@@ -95,8 +99,9 @@ func NewEnableManageDNSCommand() *cobra.Command {
 	}
 
 	flags := cmd.Flags()
-	flags.StringVar(&opt.Cloud, "cloud", cloudAWS, "Cloud provider: aws(default)|gcp)")
+	flags.StringVar(&opt.Cloud, "cloud", cloudAWS, "Cloud provider: aws(default)|gcp|azure)")
 	flags.StringVar(&opt.CredsFile, "creds-file", "", "Cloud credentials file (defaults vary depending on cloud)")
+	flags.StringVar(&opt.AzureResourceGroupName, "azure-resource-group-name", "", "Resource group where the azure DNS zones for the managed domains are found")
 	return cmd
 }
 
@@ -112,6 +117,11 @@ func (o *Options) Complete(cmd *cobra.Command, args []string) error {
 
 // Validate ensures that option values make sense
 func (o *Options) Validate(cmd *cobra.Command) error {
+	if o.Cloud == cloudAzure && o.AzureResourceGroupName == "" {
+		cmd.Usage()
+		log.Info("A resource group is required for Azure")
+		return fmt.Errorf("missing Azure option: --azure-resource-group-name")
+	}
 	return nil
 }
 
@@ -157,6 +167,16 @@ func (o *Options) Run(args []string) error {
 		dnsConf.GCP = &hivev1.ManageDNSGCPConfig{
 			CredentialsSecretRef: corev1.LocalObjectReference{Name: credsSecret.Name},
 		}
+	case cloudAzure:
+		// Apply a secret for credentials to manage the root domain:
+		credsSecret, err = o.generateAzureCredentialsSecret()
+		if err != nil {
+			log.WithError(err).Fatal("error generating manageDNS credentials secret")
+		}
+		dnsConf.Azure = &hivev1.ManageDNSAzureConfig{
+			CredentialsSecretRef: corev1.LocalObjectReference{Name: credsSecret.Name},
+			ResourceGroupName:    o.AzureResourceGroupName,
+		}
 	default:
 		log.WithField("cloud", o.Cloud).Fatal("unsupported cloud")
 	}
@@ -331,6 +351,36 @@ func (o *Options) generateGCPCredentialsSecret() (*corev1.Secret, error) {
 		},
 	}, nil
 }
+
+func (o *Options) generateAzureCredentialsSecret() (*corev1.Secret, error) {
+	credsFilePath := filepath.Join(o.homeDir, ".azure", constants.AzureCredentialsName)
+	if l := os.Getenv("AZURE_AUTH_LOCATION"); l != "" {
+		credsFilePath = l
+	}
+	if o.CredsFile != "" {
+		credsFilePath = o.CredsFile
+	}
+	log.Infof("Loading Azure service principal from: %s", credsFilePath)
+	spFileContents, err := ioutil.ReadFile(credsFilePath)
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Secret",
+			APIVersion: corev1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("azure-dns-creds-%s", uuid.New().String()[:5]),
+			Namespace: hiveNamespace,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			constants.AzureCredentialsName: spFileContents,
+		},
+	}, nil
+}
+
 func (o *Options) getResourceHelper() (*resource.Helper, error) {
 	cfg, err := config.GetConfig()
 	if err != nil {
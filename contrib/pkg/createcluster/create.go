@@ -69,6 +69,14 @@ AWS credentials. These are only relevant for creating a cluster on AWS. If
 --creds-file is used it will take precedence over these environment
 variables.
 
+OS_CLIENT_CONFIG_FILE - Is used to locate the clouds.yaml file with your
+OpenStack credentials. This is only relevant for creating a cluster on
+OpenStack. If --creds-file is used it will take precedence over this
+environment variable. Otherwise ~/.config/openstack/clouds.yaml is used.
+
+GOVC_USERNAME and GOVC_PASSWORD - Are used to determine your vSphere
+credentials. These are only relevant for creating a cluster on vSphere.
+
 RELEASE_IMAGE - Release image to use to install the cluster. If not specified,
 the --release-image flag is used. If that's not specified, a default image is
 obtained from a the following URL:
@@ -82,6 +90,8 @@ const (
 	cloudAWS                   = "aws"
 	cloudAzure                 = "azure"
 	cloudGCP                   = "gcp"
+	cloudOpenStack             = "openstack"
+	cloudVSphere               = "vsphere"
 
 	testFailureManifest = `apiVersion: v1
 kind: NotARealSecret
@@ -94,9 +104,11 @@ type: TestFailResource
 
 var (
 	validClouds = map[string]bool{
-		cloudAWS:   true,
-		cloudAzure: true,
-		cloudGCP:   true,
+		cloudAWS:       true,
+		cloudAzure:     true,
+		cloudGCP:       true,
+		cloudOpenStack: true,
+		cloudVSphere:   true,
 	}
 )
 
@@ -138,6 +150,24 @@ type Options struct {
 	// Azure
 	AzureBaseDomainResourceGroupName string
 
+	// OpenStack
+	OpenStackCloud           string
+	OpenStackExternalNetwork string
+	OpenStackMasterFlavor    string
+	OpenStackComputeFlavor   string
+	OpenStackAPIFloatingIP   string
+
+	// VSphere
+	VSphereVCenter          string
+	VSphereDatacenter       string
+	VSphereDefaultDataStore string
+	VSphereFolder           string
+	VSphereCluster          string
+	VSphereAPIVIP           string
+	VSphereIngressVIP       string
+	VSphereNetwork          string
+	VSphereCACerts          string
+
 	homeDir       string
 	cloudProvider cloudProvider
 }
@@ -185,7 +215,7 @@ create-cluster CLUSTER_DEPLOYMENT_NAME --cloud=gcp`,
 	}
 
 	flags := cmd.Flags()
-	flags.StringVar(&opt.Cloud, "cloud", cloudAWS, "Cloud provider: aws(default)|azure|gcp)")
+	flags.StringVar(&opt.Cloud, "cloud", cloudAWS, "Cloud provider: aws(default)|azure|gcp|openstack|vsphere)")
 	flags.StringVarP(&opt.Namespace, "namespace", "n", "", "Namespace to create cluster deployment in")
 	flags.StringVar(&opt.SSHPrivateKeyFile, "ssh-private-key-file", "", "file name containing private key contents")
 	flags.StringVar(&opt.SSHPublicKeyFile, "ssh-public-key-file", defaultSSHPublicKeyFile, "file name of SSH public key for cluster")
@@ -200,7 +230,7 @@ create-cluster CLUSTER_DEPLOYMENT_NAME --cloud=gcp`,
 	flags.StringVar(&opt.ReleaseImageSource, "release-image-source", "https://openshift-release.svc.ci.openshift.org/api/v1/releasestream/4-stable/latest", "URL to JSON describing the release image pull spec")
 	flags.StringVar(&opt.ServingCert, "serving-cert", "", "Serving certificate for control plane and routes")
 	flags.StringVar(&opt.ServingCertKey, "serving-cert-key", "", "Serving certificate key for control plane and routes")
-	flags.BoolVar(&opt.ManageDNS, "manage-dns", false, "Manage this cluster's DNS. This is only available for AWS.")
+	flags.BoolVar(&opt.ManageDNS, "manage-dns", false, "Manage this cluster's DNS. This is only available for AWS, GCP and Azure.")
 	flags.BoolVar(&opt.UseClusterImageSet, "use-image-set", true, "If true(default), use a cluster image set for this cluster")
 	flags.StringVarP(&opt.Output, "output", "o", "", "Output of this command (nothing will be created on cluster). Valid values: yaml,json")
 	flags.BoolVar(&opt.IncludeSecrets, "include-secrets", true, "Include secrets along with ClusterDeployment")
@@ -222,6 +252,24 @@ create-cluster CLUSTER_DEPLOYMENT_NAME --cloud=gcp`,
 	// Azure flags
 	flags.StringVar(&opt.AzureBaseDomainResourceGroupName, "azure-base-domain-resource-group-name", "os4-common", "Resource group where the azure DNS zone for the base domain is found")
 
+	// OpenStack flags
+	flags.StringVar(&opt.OpenStackCloud, "openstack-cloud", "openstack", "Section of clouds.yaml to use for API/auth")
+	flags.StringVar(&opt.OpenStackExternalNetwork, "openstack-external-network", "", "External OpenStack network name to deploy into")
+	flags.StringVar(&opt.OpenStackMasterFlavor, "openstack-master-flavor", "m1.xlarge", "Compute flavor to use for master nodes")
+	flags.StringVar(&opt.OpenStackComputeFlavor, "openstack-compute-flavor", "m1.xlarge", "Compute flavor to use for worker nodes")
+	flags.StringVar(&opt.OpenStackAPIFloatingIP, "openstack-api-floating-ip", "", "Floating IP address to use for cluster's API")
+
+	// vSphere flags
+	flags.StringVar(&opt.VSphereVCenter, "vsphere-vcenter", "", "Domain name or IP address of the vCenter")
+	flags.StringVar(&opt.VSphereDatacenter, "vsphere-datacenter", "", "Datacenter to use in the vCenter")
+	flags.StringVar(&opt.VSphereDefaultDataStore, "vsphere-default-datastore", "", "Default datastore to use for provisioning volumes")
+	flags.StringVar(&opt.VSphereFolder, "vsphere-folder", "", "Folder that will be used and/or created for virtual machines")
+	flags.StringVar(&opt.VSphereCluster, "vsphere-cluster", "", "Cluster virtual machines will be cloned into")
+	flags.StringVar(&opt.VSphereAPIVIP, "vsphere-api-vip", "", "Virtual IP address for the api endpoint")
+	flags.StringVar(&opt.VSphereIngressVIP, "vsphere-ingress-vip", "", "Virtual IP address for ingress application routing")
+	flags.StringVar(&opt.VSphereNetwork, "vsphere-network", "", "Name of the network to be used by the cluster")
+	flags.StringVar(&opt.VSphereCACerts, "vsphere-ca-certs", "", "Path to vSphere CA certificate, multiple CA paths can be : delimited")
+
 	return cmd
 }
 
@@ -253,6 +301,17 @@ func (o *Options) Validate(cmd *cobra.Command) error {
 		log.Infof("Unsupported cloud: %s", o.Cloud)
 		return fmt.Errorf("Unsupported cloud: %s", o.Cloud)
 	}
+	if o.Cloud == cloudOpenStack && (o.OpenStackExternalNetwork == "" || o.OpenStackAPIFloatingIP == "") {
+		cmd.Usage()
+		log.Info("An external network and an API floating IP are required for OpenStack")
+		return fmt.Errorf("missing OpenStack options: --openstack-external-network, --openstack-api-floating-ip")
+	}
+	if o.Cloud == cloudVSphere && (o.VSphereVCenter == "" || o.VSphereDatacenter == "" || o.VSphereDefaultDataStore == "" ||
+		o.VSphereNetwork == "" || o.VSphereAPIVIP == "" || o.VSphereIngressVIP == "" || o.VSphereCACerts == "") {
+		cmd.Usage()
+		log.Info("A vCenter, datacenter, default datastore, network, API VIP, ingress VIP and CA certificates are required for vSphere")
+		return fmt.Errorf("missing vSphere options: --vsphere-vcenter, --vsphere-datacenter, --vsphere-default-datastore, --vsphere-network, --vsphere-api-vip, --vsphere-ingress-vip, --vsphere-ca-certs")
+	}
 
 	if o.Adopt {
 		if o.AdoptAdminKubeConfig == "" || o.AdoptInfraID == "" || o.AdoptClusterID == "" {
@@ -299,6 +358,10 @@ func (o *Options) Run() error {
 		o.cloudProvider = &azureCloudProvider{}
 	case cloudGCP:
 		o.cloudProvider = &gcpCloudProvider{}
+	case cloudOpenStack:
+		o.cloudProvider = &openStackCloudProvider{}
+	case cloudVSphere:
+		o.cloudProvider = &vSphereCloudProvider{}
 	}
 
 	objs, err := o.GenerateObjects()
@@ -490,6 +553,15 @@ func (o *Options) GenerateObjects() ([]runtime.Object, error) {
 		}
 		result = append(result, creds)
 
+		// vSphere also needs the CA certificates of the vCenter.
+		if p, ok := o.cloudProvider.(*vSphereCloudProvider); ok {
+			certs, err := p.generateCertificatesSecret(o)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, certs)
+		}
+
 		if sshPrivateKeySecret != nil {
 			result = append(result, sshPrivateKeySecret)
 		}
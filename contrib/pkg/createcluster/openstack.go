@@ -0,0 +1,84 @@
+package createcluster
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	installertypes "github.com/openshift/installer/pkg/types"
+	installeropenstack "github.com/openshift/installer/pkg/types/openstack"
+
+	openstackutils "github.com/openshift/hive/contrib/pkg/utils/openstack"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	hivev1openstack "github.com/openshift/hive/pkg/apis/hive/v1/openstack"
+	"github.com/openshift/hive/pkg/constants"
+)
+
+var _ cloudProvider = (*openStackCloudProvider)(nil)
+
+type openStackCloudProvider struct {
+}
+
+func (p *openStackCloudProvider) generateCredentialsSecret(o *Options) (*corev1.Secret, error) {
+	cloudsYAMLContent, err := openstackutils.GetCreds(o.CredsFile)
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Secret",
+			APIVersion: corev1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      p.credsSecretName(o),
+			Namespace: o.Namespace,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			constants.OpenStackCredentialsName: cloudsYAMLContent,
+		},
+	}, nil
+}
+
+func (p *openStackCloudProvider) addPlatformDetails(
+	o *Options,
+	cd *hivev1.ClusterDeployment,
+	machinePool *hivev1.MachinePool,
+	installConfig *installertypes.InstallConfig,
+) error {
+	cd.Spec.Platform = hivev1.Platform{
+		OpenStack: &hivev1openstack.Platform{
+			CredentialsSecretRef: corev1.LocalObjectReference{
+				Name: p.credsSecretName(o),
+			},
+			Cloud: o.OpenStackCloud,
+		},
+	}
+
+	machinePool.Spec.Platform.OpenStack = &hivev1openstack.MachinePool{
+		Flavor: o.OpenStackComputeFlavor,
+	}
+
+	installConfig.Platform = installertypes.Platform{
+		OpenStack: &installeropenstack.Platform{
+			Cloud:           o.OpenStackCloud,
+			ExternalNetwork: o.OpenStackExternalNetwork,
+			FlavorName:      o.OpenStackComputeFlavor,
+			LbFloatingIP:    o.OpenStackAPIFloatingIP,
+		},
+	}
+
+	installConfig.ControlPlane.Platform.OpenStack = &installeropenstack.MachinePool{
+		FlavorName: o.OpenStackMasterFlavor,
+	}
+	installConfig.Compute[0].Platform.OpenStack = &installeropenstack.MachinePool{
+		FlavorName: o.OpenStackComputeFlavor,
+	}
+
+	return nil
+}
+
+func (p *openStackCloudProvider) credsSecretName(o *Options) string {
+	return fmt.Sprintf("%s-openstack-creds", o.Name)
+}
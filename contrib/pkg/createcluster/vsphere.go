@@ -0,0 +1,161 @@
+package createcluster
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	installertypes "github.com/openshift/installer/pkg/types"
+	installervsphere "github.com/openshift/installer/pkg/types/vsphere"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	hivev1vsphere "github.com/openshift/hive/pkg/apis/hive/v1/vsphere"
+	"github.com/openshift/hive/pkg/constants"
+)
+
+const (
+	vsphereDefaultNumCPUs           = 4
+	vsphereDefaultNumCoresPerSocket = 1
+	vsphereDefaultMemoryMiB         = 16384
+	vsphereDefaultDiskSizeGB        = 120
+)
+
+var _ cloudProvider = (*vSphereCloudProvider)(nil)
+
+type vSphereCloudProvider struct {
+}
+
+func (p *vSphereCloudProvider) generateCredentialsSecret(o *Options) (*corev1.Secret, error) {
+	username, password, err := getVSphereCreds()
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Secret",
+			APIVersion: corev1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      p.credsSecretName(o),
+			Namespace: o.Namespace,
+		},
+		Type: corev1.SecretTypeOpaque,
+		StringData: map[string]string{
+			constants.UsernameSecretKey: username,
+			constants.PasswordSecretKey: password,
+		},
+	}, nil
+}
+
+// generateCertificatesSecret creates a secret containing each of the CA certificate files in the
+// : delimited --vsphere-ca-certs option.
+func (p *vSphereCloudProvider) generateCertificatesSecret(o *Options) (*corev1.Secret, error) {
+	data := map[string][]byte{}
+	for _, certFile := range strings.Split(o.VSphereCACerts, ":") {
+		cert, err := ioutil.ReadFile(certFile)
+		if err != nil {
+			return nil, err
+		}
+		data[filepath.Base(certFile)] = cert
+	}
+	return &corev1.Secret{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Secret",
+			APIVersion: corev1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      p.certificatesSecretName(o),
+			Namespace: o.Namespace,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: data,
+	}, nil
+}
+
+func (p *vSphereCloudProvider) addPlatformDetails(
+	o *Options,
+	cd *hivev1.ClusterDeployment,
+	machinePool *hivev1.MachinePool,
+	installConfig *installertypes.InstallConfig,
+) error {
+	username, password, err := getVSphereCreds()
+	if err != nil {
+		return err
+	}
+
+	cd.Spec.Platform = hivev1.Platform{
+		VSphere: &hivev1vsphere.Platform{
+			VCenter: o.VSphereVCenter,
+			CredentialsSecretRef: corev1.LocalObjectReference{
+				Name: p.credsSecretName(o),
+			},
+			CertificatesSecretRef: corev1.LocalObjectReference{
+				Name: p.certificatesSecretName(o),
+			},
+			Datacenter:       o.VSphereDatacenter,
+			DefaultDatastore: o.VSphereDefaultDataStore,
+			Folder:           o.VSphereFolder,
+			Cluster:          o.VSphereCluster,
+			Network:          o.VSphereNetwork,
+		},
+	}
+
+	machinePool.Spec.Platform.VSphere = &hivev1vsphere.MachinePool{
+		NumCPUs:           vsphereDefaultNumCPUs,
+		NumCoresPerSocket: vsphereDefaultNumCoresPerSocket,
+		MemoryMiB:         vsphereDefaultMemoryMiB,
+		OSDisk: hivev1vsphere.OSDisk{
+			DiskSizeGB: vsphereDefaultDiskSizeGB,
+		},
+	}
+
+	installConfig.Platform = installertypes.Platform{
+		VSphere: &installervsphere.Platform{
+			VCenter:          o.VSphereVCenter,
+			Username:         username,
+			Password:         password,
+			Datacenter:       o.VSphereDatacenter,
+			DefaultDatastore: o.VSphereDefaultDataStore,
+			Folder:           o.VSphereFolder,
+			Cluster:          o.VSphereCluster,
+			APIVIP:           o.VSphereAPIVIP,
+			IngressVIP:       o.VSphereIngressVIP,
+			Network:          o.VSphereNetwork,
+		},
+	}
+
+	mpp := &installervsphere.MachinePool{
+		NumCPUs:           vsphereDefaultNumCPUs,
+		NumCoresPerSocket: vsphereDefaultNumCoresPerSocket,
+		MemoryMiB:         vsphereDefaultMemoryMiB,
+		OSDisk: installervsphere.OSDisk{
+			DiskSizeGB: vsphereDefaultDiskSizeGB,
+		},
+	}
+	installConfig.ControlPlane.Platform.VSphere = mpp
+	installConfig.Compute[0].Platform.VSphere = mpp
+
+	return nil
+}
+
+func (p *vSphereCloudProvider) credsSecretName(o *Options) string {
+	return fmt.Sprintf("%s-vsphere-creds", o.Name)
+}
+
+func (p *vSphereCloudProvider) certificatesSecretName(o *Options) string {
+	return fmt.Sprintf("%s-vsphere-certs", o.Name)
+}
+
+func getVSphereCreds() (string, string, error) {
+	username := os.Getenv(constants.VSphereUsernameEnvVar)
+	password := os.Getenv(constants.VSpherePasswordEnvVar)
+	if username == "" || password == "" {
+		return "", "", fmt.Errorf("%s and %s must be set for vSphere", constants.VSphereUsernameEnvVar, constants.VSpherePasswordEnvVar)
+	}
+	return username, password, nil
+}
@@ -15,5 +15,7 @@ func NewDeprovisionCommand() *cobra.Command {
 	}
 	cmd.AddCommand(NewDeprovisionAzureCommand())
 	cmd.AddCommand(NewDeprovisionGCPCommand())
+	cmd.AddCommand(NewDeprovisionOpenStackCommand())
+	cmd.AddCommand(NewDeprovisionVSphereCommand())
 	return cmd
 }
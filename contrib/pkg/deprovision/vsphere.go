@@ -0,0 +1,117 @@
+package deprovision
+
+import (
+	"fmt"
+	"os"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"github.com/openshift/installer/pkg/destroy/providers"
+	"github.com/openshift/installer/pkg/types"
+	typesvsphere "github.com/openshift/installer/pkg/types/vsphere"
+
+	"github.com/openshift/hive/pkg/constants"
+	_ "github.com/openshift/hive/pkg/destroy/vsphere" // registers the vSphere destroyer
+)
+
+// vSphereOptions is the set of options to deprovision a vSphere cluster
+type vSphereOptions struct {
+	logLevel string
+	infraID  string
+	vCenter  string
+	username string
+	password string
+}
+
+// NewDeprovisionVSphereCommand is the entrypoint to create the vSphere deprovision subcommand
+func NewDeprovisionVSphereCommand() *cobra.Command {
+	opt := &vSphereOptions{}
+	cmd := &cobra.Command{
+		Use:   "vsphere INFRAID --vsphere-vcenter=VCENTER",
+		Short: "Deprovision vSphere assets (as created by openshift-installer)",
+		Long: fmt.Sprintf(`Deprovision vSphere assets (as created by openshift-installer)
+The vSphere username and password are read from the %s and %s environment variables.`,
+			constants.VSphereUsernameEnvVar, constants.VSpherePasswordEnvVar),
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := opt.Complete(cmd, args); err != nil {
+				log.WithError(err).Fatal("failed to complete options")
+			}
+			if err := opt.Validate(cmd); err != nil {
+				log.WithError(err).Fatal("validation failed")
+			}
+			if err := opt.Run(); err != nil {
+				log.WithError(err).Fatal("Runtime error")
+			}
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&opt.logLevel, "loglevel", "info", "log level, one of: debug, info, warn, error, fatal, panic")
+	flags.StringVar(&opt.vCenter, "vsphere-vcenter", "", "Domain name or IP address of the vCenter")
+	return cmd
+}
+
+// Complete finishes parsing arguments for the command
+func (o *vSphereOptions) Complete(cmd *cobra.Command, args []string) error {
+	o.infraID = args[0]
+	o.username = os.Getenv(constants.VSphereUsernameEnvVar)
+	o.password = os.Getenv(constants.VSpherePasswordEnvVar)
+	return nil
+}
+
+// Validate ensures that option values make sense
+func (o *vSphereOptions) Validate(cmd *cobra.Command) error {
+	if o.vCenter == "" {
+		cmd.Usage()
+		log.Info("vCenter is required")
+		return fmt.Errorf("missing vCenter")
+	}
+	if o.username == "" || o.password == "" {
+		cmd.Usage()
+		log.Infof("%s and %s must be set", constants.VSphereUsernameEnvVar, constants.VSpherePasswordEnvVar)
+		return fmt.Errorf("missing vSphere credentials")
+	}
+	return nil
+}
+
+// Run executes the command
+func (o *vSphereOptions) Run() error {
+	// Set log level
+	level, err := log.ParseLevel(o.logLevel)
+	if err != nil {
+		log.WithError(err).Error("cannot parse log level")
+		return err
+	}
+
+	logger := log.NewEntry(&log.Logger{
+		Out: os.Stdout,
+		Formatter: &log.TextFormatter{
+			FullTimestamp: true,
+		},
+		Hooks: make(log.LevelHooks),
+		Level: level,
+	})
+
+	metadata := &types.ClusterMetadata{
+		InfraID: o.infraID,
+		ClusterPlatformMetadata: types.ClusterPlatformMetadata{
+			VSphere: &typesvsphere.Metadata{
+				VCenter:  o.vCenter,
+				Username: o.username,
+				Password: o.password,
+			},
+		},
+	}
+
+	newDestroyer, ok := providers.Registry[typesvsphere.Name]
+	if !ok {
+		return fmt.Errorf("no destroyer available for platform %q", typesvsphere.Name)
+	}
+	destroyer, err := newDestroyer(logger, metadata)
+	if err != nil {
+		return err
+	}
+
+	return destroyer.Run()
+}
@@ -0,0 +1,158 @@
+package installlogs
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	contributils "github.com/openshift/hive/contrib/pkg/utils"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+	"github.com/openshift/hive/pkg/installlogarchive"
+)
+
+const (
+	hiveConfigName = "hive"
+)
+
+// FetchOptions is the set of options for fetching the archived logs of a ClusterProvision.
+type FetchOptions struct {
+	// Namespace is the namespace of the ClusterProvision.
+	Namespace string
+	// ProvisionName is the name of the ClusterProvision.
+	ProvisionName string
+	// DestDir is the directory to which the logs are written.
+	DestDir string
+}
+
+// NewFetchInstallLogsCommand creates a command that downloads the logs of a ClusterProvision from the install log
+// archive.
+func NewFetchInstallLogsCommand() *cobra.Command {
+	opt := &FetchOptions{}
+	cmd := &cobra.Command{
+		Use:   "fetch-install-logs CLUSTER_PROVISION_NAME",
+		Short: "Downloads the logs of a ClusterProvision from the install log archive",
+		Long: `Downloads the installer logs and the logs gathered after a failed install of a ClusterProvision from the
+install log archive configured in HiveConfig. The credentials of the archive are read from the hive namespace.`,
+		Args: cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			log.SetLevel(log.InfoLevel)
+			if err := opt.Complete(cmd, args); err != nil {
+				log.WithError(err).Fatal("Error")
+			}
+
+			if err := opt.Validate(cmd); err != nil {
+				log.WithError(err).Fatal("Error")
+			}
+
+			dynClient, err := contributils.GetClient()
+			if err != nil {
+				log.WithError(err).Fatal("error creating kube clients")
+			}
+
+			if err := opt.Run(dynClient); err != nil {
+				log.WithError(err).Fatal("Error")
+			}
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringVarP(&opt.Namespace, "namespace", "n", "", "Namespace of the ClusterProvision")
+	flags.StringVar(&opt.DestDir, "dest-dir", ".", "Directory to which the logs are written")
+	return cmd
+}
+
+// Complete finishes parsing arguments for the command
+func (o *FetchOptions) Complete(cmd *cobra.Command, args []string) error {
+	o.ProvisionName = args[0]
+	if o.Namespace == "" {
+		rules := clientcmd.NewDefaultClientConfigLoadingRules()
+		kubeconfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{})
+		ns, _, err := kubeconfig.Namespace()
+		if err != nil {
+			return err
+		}
+		o.Namespace = ns
+	}
+	return nil
+}
+
+// Validate ensures that option values make sense
+func (o *FetchOptions) Validate(cmd *cobra.Command) error {
+	if info, err := os.Stat(o.DestDir); err != nil {
+		return err
+	} else if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", o.DestDir)
+	}
+	return nil
+}
+
+// Run executes the command
+func (o *FetchOptions) Run(dynClient client.Client) error {
+	provision := &hivev1.ClusterProvision{}
+	if err := dynClient.Get(context.Background(), types.NamespacedName{Namespace: o.Namespace, Name: o.ProvisionName}, provision); err != nil {
+		return errors.Wrap(err, "could not get ClusterProvision")
+	}
+	archive := provision.Spec.InstallLogArchive
+	if archive == nil || len(archive.Objects) == 0 {
+		return fmt.Errorf("no logs of ClusterProvision %s/%s have been archived", o.Namespace, o.ProvisionName)
+	}
+
+	hiveConfig := &hivev1.HiveConfig{}
+	if err := dynClient.Get(context.Background(), types.NamespacedName{Name: hiveConfigName}, hiveConfig); err != nil {
+		return errors.Wrap(err, "could not get HiveConfig")
+	}
+	if hiveConfig.Spec.InstallLogArchive == nil {
+		return errors.New("no install log archive is configured in HiveConfig")
+	}
+	config := hiveConfig.Spec.InstallLogArchive.DeepCopy()
+	// The objects are read from the bucket they were uploaded to, even if the archive has moved since.
+	config.Bucket = archive.Bucket
+	s3Client, err := installlogarchive.NewClient(dynClient, config, constants.HiveNamespace)
+	if err != nil {
+		return errors.Wrap(err, "could not create install log archive client")
+	}
+
+	for _, key := range archive.Objects {
+		dest := filepath.Join(o.DestDir, path.Base(key))
+		if err := download(s3Client, archive.Bucket, key, dest); err != nil {
+			return errors.Wrapf(err, "could not download %s", key)
+		}
+		fmt.Printf("Downloaded %s to %s\n", key, dest)
+	}
+	return nil
+}
+
+func download(s3Client s3iface.S3API, bucket, key, dest string) error {
+	out, err := s3Client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return err
+	}
+	defer out.Body.Close()
+	file, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(file, out.Body); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,126 @@
+package installlogs
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openshift/hive/pkg/apis"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+)
+
+const (
+	testNamespace     = "test-namespace"
+	testProvisionName = "test-provision"
+)
+
+func TestFetchInstallLogs(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/install-logs/test-namespace/test-provision/openshift_install.log":
+			w.Write([]byte("full log"))
+		case "/install-logs/test-namespace/test-provision/gather.tar.gz":
+			w.Write([]byte("gathered logs"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	hiveConfig := &hivev1.HiveConfig{
+		ObjectMeta: metav1.ObjectMeta{Name: hiveConfigName},
+		Spec: hivev1.HiveConfigSpec{
+			InstallLogArchive: &hivev1.InstallLogArchiveConfig{
+				Endpoint:             server.URL,
+				Bucket:               "install-logs",
+				CredentialsSecretRef: corev1.LocalObjectReference{Name: "install-log-archive-creds"},
+			},
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "install-log-archive-creds", Namespace: constants.HiveNamespace},
+		Data: map[string][]byte{
+			"aws_access_key_id":     []byte("access-key"),
+			"aws_secret_access_key": []byte("secret-key"),
+		},
+	}
+	provision := func(archive *hivev1.InstallLogArchiveReference) *hivev1.ClusterProvision {
+		return &hivev1.ClusterProvision{
+			ObjectMeta: metav1.ObjectMeta{Name: testProvisionName, Namespace: testNamespace},
+			Spec:       hivev1.ClusterProvisionSpec{InstallLogArchive: archive},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		existing      []runtime.Object
+		expectedFiles map[string]string
+		expectError   bool
+	}{
+		{
+			name: "archived logs",
+			existing: []runtime.Object{hiveConfig, secret, provision(&hivev1.InstallLogArchiveReference{
+				Bucket:  "install-logs",
+				Objects: []string{"test-namespace/test-provision/openshift_install.log", "test-namespace/test-provision/gather.tar.gz"},
+			})},
+			expectedFiles: map[string]string{
+				"openshift_install.log": "full log",
+				"gather.tar.gz":         "gathered logs",
+			},
+		},
+		{
+			name:        "no archived logs",
+			existing:    []runtime.Object{hiveConfig, secret, provision(nil)},
+			expectError: true,
+		},
+		{
+			name: "missing object",
+			existing: []runtime.Object{hiveConfig, secret, provision(&hivev1.InstallLogArchiveReference{
+				Bucket:  "install-logs",
+				Objects: []string{"test-namespace/test-provision/openshift-install-console.log"},
+			})},
+			expectError: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			destDir, err := ioutil.TempDir("", "fetchinstalllogs")
+			require.NoError(t, err, "unexpected error creating dest dir")
+			defer os.RemoveAll(destDir)
+
+			opt := &FetchOptions{
+				Namespace:     testNamespace,
+				ProvisionName: testProvisionName,
+				DestDir:       destDir,
+			}
+			err = opt.Run(fake.NewFakeClient(test.existing...))
+			if test.expectError {
+				assert.Error(t, err, "expected error fetching logs")
+				return
+			}
+			require.NoError(t, err, "unexpected error fetching logs")
+			for name, content := range test.expectedFiles {
+				actual, err := ioutil.ReadFile(filepath.Join(destDir, name))
+				if assert.NoError(t, err, "missing log %s", name) {
+					assert.Equal(t, content, string(actual), "unexpected content of log %s", name)
+				}
+			}
+		})
+	}
+}
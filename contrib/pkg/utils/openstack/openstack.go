@@ -0,0 +1,27 @@
+package openstack
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+
+	"github.com/openshift/hive/pkg/constants"
+)
+
+// GetCreds reads OpenStack credentials either from the specified credentials file,
+// the standard environment variable, or a default credentials file. (~/.config/openstack/clouds.yaml)
+// The default credentials file will only be used if credsFile is empty and the environment
+// variable is not set.
+func GetCreds(credsFile string) ([]byte, error) {
+	credsFilePath := filepath.Join(os.Getenv("HOME"), ".config", "openstack", constants.OpenStackCredentialsName)
+	if l := os.Getenv("OS_CLIENT_CONFIG_FILE"); l != "" {
+		credsFilePath = l
+	}
+	if credsFile != "" {
+		credsFilePath = credsFile
+	}
+	log.Infof("Loading OpenStack clouds.yaml from: %s", credsFilePath)
+	return ioutil.ReadFile(credsFilePath)
+}
@@ -18,5 +18,5 @@ go build -o ./bin/go-bindata ./vendor/github.com/jteeuwen/go-bindata/go-bindata
         -o "${OUTPUT_FILE}" \
         -ignore "OWNERS" \
         -ignore ".*\.sw.?" \
-        ./config/apiserver/... ./config/hiveadmission/... ./config/manager/... ./config/rbac/... ./config/crds/... ./config/configmaps/... && \
+        ./config/apiserver/... ./config/hiveadmission/... ./config/manager/... ./config/rbac/... ./config/crds/... ./config/installfailurerules/... && \
 gofmt -s -w "${OUTPUT_FILE}"
@@ -0,0 +1,227 @@
+package acmeclient
+
+import (
+	"context"
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/acme"
+)
+
+//go:generate mockgen -source=./client.go -destination=./mock/client_generated.go -package=mock
+
+const (
+	// challengeRecordPrefix is the label prepended to a domain to form the name of its DNS-01 challenge record.
+	challengeRecordPrefix = "_acme-challenge."
+)
+
+// Solver solves DNS-01 challenges by publishing TXT records in DNS.
+type Solver interface {
+	// Present publishes TXT records with the specified values at the specified challenge record name, replacing
+	// any existing records.
+	Present(name string, values []string) error
+
+	// CleanUp removes the TXT records with the specified values at the specified challenge record name.
+	CleanUp(name string, values []string) error
+}
+
+// Order is an order of a certificate from the ACME server.
+type Order struct {
+	// URL identifies the order on the ACME server.
+	URL string
+
+	// Status is the status of the order, such as acme.StatusPending while its challenges have to be solved and
+	// acme.StatusReady once it can be finalized.
+	Status string
+
+	// Domains are the domains that the certificate is ordered for.
+	Domains []string
+
+	// Records are the values of the DNS-01 challenge records of the authorizations of the order, by record name.
+	// Challenges for a domain and its wildcard share the same record.
+	Records map[string][]string
+}
+
+// Client is a wrapper object for obtaining certificates from an ACME server, to allow for easier mocking/testing.
+// Each call makes a bounded number of requests to the ACME server and does not wait for the order to change, so
+// that the order can be advanced across reconciles.
+type Client interface {
+	// CreateOrder creates an order of a certificate for the specified domains, registering the account key with
+	// the ACME server first if needed.
+	CreateOrder(ctx context.Context, domains []string) (*Order, error)
+
+	// GetOrder fetches the order with the specified URL.
+	GetOrder(ctx context.Context, url string) (*Order, error)
+
+	// AcceptChallenges tells the ACME server to validate the DNS-01 challenges of the pending authorizations of the
+	// order with the specified URL, once their records are presented.
+	AcceptChallenges(ctx context.Context, url string) error
+
+	// FinalizeOrder finalizes the ready order with the specified URL with the specified certificate request. It
+	// returns the PEM-encoded certificate chain.
+	FinalizeOrder(ctx context.Context, url string, csr []byte) ([]byte, error)
+}
+
+type acmeClient struct {
+	client *acme.Client
+	email  string
+}
+
+func (c *acmeClient) CreateOrder(ctx context.Context, domains []string) (*Order, error) {
+	if err := c.register(ctx); err != nil {
+		return nil, err
+	}
+	order, err := c.client.AuthorizeOrder(ctx, acme.DomainIDs(domains...))
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create order")
+	}
+	return c.order(ctx, order.URI, order)
+}
+
+func (c *acmeClient) GetOrder(ctx context.Context, url string) (*Order, error) {
+	// Orders fetched from their URL do not carry it, so the URL is kept by the caller.
+	order, err := c.client.GetOrder(ctx, url)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get order")
+	}
+	return c.order(ctx, url, order)
+}
+
+func (c *acmeClient) AcceptChallenges(ctx context.Context, url string) error {
+	order, err := c.client.GetOrder(ctx, url)
+	if err != nil {
+		return errors.Wrap(err, "could not get order")
+	}
+	for _, authzURL := range order.AuthzURLs {
+		authz, err := c.client.GetAuthorization(ctx, authzURL)
+		if err != nil {
+			return errors.Wrap(err, "could not get authorization")
+		}
+		if authz.Status != acme.StatusPending {
+			continue
+		}
+		challenge := dns01Challenge(authz)
+		// Challenges which are already being validated are not accepted again.
+		if challenge == nil || challenge.Status != acme.StatusPending {
+			continue
+		}
+		if _, err := c.client.Accept(ctx, challenge); err != nil {
+			return errors.Wrap(err, "could not accept challenge")
+		}
+	}
+	return nil
+}
+
+func (c *acmeClient) FinalizeOrder(ctx context.Context, url string, csr []byte) ([]byte, error) {
+	order, err := c.client.GetOrder(ctx, url)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get order")
+	}
+	chain, _, err := c.client.CreateOrderCert(ctx, order.FinalizeURL, csr, true)
+	if err != nil {
+		// Some ACME servers, such as Pebble, do not return the URL of an order which is still being processed
+		// after its finalization, so wait for the order through its known URL before giving up.
+		order, waitErr := c.client.WaitOrder(ctx, url)
+		if waitErr != nil || order.Status != acme.StatusValid {
+			return nil, errors.Wrap(err, "could not finalize order")
+		}
+		chain, err = c.client.FetchCert(ctx, order.CertURL, true)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not fetch certificate")
+		}
+	}
+	var certificate []byte
+	for _, der := range chain {
+		certificate = append(certificate, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})...)
+	}
+	return certificate, nil
+}
+
+// order converts the ACME order with the specified URL, gathering the DNS-01 challenge records of its
+// authorizations.
+func (c *acmeClient) order(ctx context.Context, url string, order *acme.Order) (*Order, error) {
+	result := &Order{
+		URL:     url,
+		Status:  order.Status,
+		Records: map[string][]string{},
+	}
+	for _, id := range order.Identifiers {
+		result.Domains = append(result.Domains, id.Value)
+	}
+	for _, authzURL := range order.AuthzURLs {
+		authz, err := c.client.GetAuthorization(ctx, authzURL)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not get authorization")
+		}
+		challenge := dns01Challenge(authz)
+		if challenge == nil {
+			return nil, errors.Errorf("no dns-01 challenge offered for %s", authz.Identifier.Value)
+		}
+		value, err := c.client.DNS01ChallengeRecord(challenge.Token)
+		if err != nil {
+			return nil, errors.Wrap(err, "could not compute challenge record")
+		}
+		name := ChallengeRecordName(authz.Identifier.Value)
+		result.Records[name] = append(result.Records[name], value)
+	}
+	return result, nil
+}
+
+// dns01Challenge returns the DNS-01 challenge of the authorization, or nil if it is not offered.
+func dns01Challenge(authz *acme.Authorization) *acme.Challenge {
+	for _, challenge := range authz.Challenges {
+		if challenge.Type == "dns-01" {
+			return challenge
+		}
+	}
+	return nil
+}
+
+// register registers the account key with the ACME server, unless it is already registered.
+func (c *acmeClient) register(ctx context.Context) error {
+	account := &acme.Account{}
+	if c.email != "" {
+		account.Contact = []string{"mailto:" + c.email}
+	}
+	_, err := c.client.Register(ctx, account, acme.AcceptTOS)
+	if err != nil && err != acme.ErrAccountAlreadyExists {
+		return errors.Wrap(err, "could not register account")
+	}
+	return nil
+}
+
+// ChallengeRecordName returns the name of the DNS-01 challenge record of the specified domain, which may be a
+// wildcard domain.
+func ChallengeRecordName(domain string) string {
+	return challengeRecordPrefix + strings.TrimPrefix(domain, "*.")
+}
+
+// NewClient creates our client wrapper object for obtaining certificates from the ACME server with the specified
+// directory URL, using the specified account key and contact email. When caBundle is not empty, the ACME server is
+// trusted only if its certificate is signed by one of the certificate authorities in the bundle.
+func NewClient(directoryURL, email string, accountKey crypto.Signer, caBundle []byte) (Client, error) {
+	httpClient := http.DefaultClient
+	if len(caBundle) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caBundle) {
+			return nil, errors.New("no certificates found in the ACME server certificate authority bundle")
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool}
+		httpClient = &http.Client{Transport: transport}
+	}
+	return &acmeClient{
+		client: &acme.Client{
+			Key:          accountKey,
+			DirectoryURL: directoryURL,
+			HTTPClient:   httpClient,
+			UserAgent:    "hive",
+		},
+		email: email,
+	}, nil
+}
@@ -0,0 +1,136 @@
+package acmeclient
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// This test will obtain a certificate from a local Pebble ACME server.
+// By default, this test will be skipped.
+// To enable the test, set the TEST_LIVE_PEBBLE environment variable to the directory URL of a Pebble server, such as
+// https://localhost:14000/dir, and the TEST_LIVE_PEBBLE_CHALLTESTSRV environment variable to the management URL of
+// the pebble-challtestsrv DNS server that Pebble uses to validate DNS-01 challenges, such as http://localhost:8055.
+// When Pebble serves its directory with its test certificate, set the TEST_LIVE_PEBBLE_CA environment variable to
+// the path of the certificate authority of that certificate, pebble.minica.pem.
+func TestLivePebble(t *testing.T) {
+	directoryURL := os.Getenv("TEST_LIVE_PEBBLE")
+	if directoryURL == "" {
+		t.SkipNow()
+	}
+	challTestSrvURL := os.Getenv("TEST_LIVE_PEBBLE_CHALLTESTSRV")
+	require.NotEmpty(t, challTestSrvURL, "TEST_LIVE_PEBBLE_CHALLTESTSRV must be set")
+	var caBundle []byte
+	if caFile := os.Getenv("TEST_LIVE_PEBBLE_CA"); caFile != "" {
+		var err error
+		caBundle, err = ioutil.ReadFile(caFile)
+		require.NoError(t, err, "could not read Pebble certificate authority")
+	}
+
+	accountKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err, "could not generate account key")
+	cut, err := NewClient(directoryURL, "hive@example.com", accountKey, caBundle)
+	require.NoError(t, err, "could not create client")
+
+	domains := []string{
+		fmt.Sprintf("api.live-pebble-%d.example.com", time.Now().Unix()),
+		fmt.Sprintf("*.apps.live-pebble-%d.example.com", time.Now().Unix()),
+	}
+	certificateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err, "could not generate certificate key")
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: domains[0]},
+		DNSNames: domains,
+	}, certificateKey)
+	require.NoError(t, err, "could not create certificate request")
+
+	solver := &challTestSrvSolver{url: challTestSrvURL}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	chain := obtainCertificate(ctx, t, cut, solver, domains, csr)
+
+	block, _ := pem.Decode(chain)
+	require.NotNil(t, block, "expected PEM-encoded certificate")
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err, "could not parse certificate")
+	assert.ElementsMatch(t, domains, certificate.DNSNames, "unexpected certificate domains")
+	assert.Len(t, solver.cleanedUp, 2, "expected challenge records to be cleaned up")
+
+	// The account is already registered for the second certificate.
+	obtainCertificate(ctx, t, cut, solver, domains, csr)
+}
+
+// obtainCertificate advances an order of a certificate for the domains until it is fulfilled, as the
+// certificatebundle controller does across reconciles.
+func obtainCertificate(ctx context.Context, t *testing.T, cut Client, solver Solver, domains []string, csr []byte) []byte {
+	order, err := cut.CreateOrder(ctx, domains)
+	require.NoError(t, err, "could not create order")
+	assert.ElementsMatch(t, domains, order.Domains, "unexpected order domains")
+	for name, values := range order.Records {
+		require.NoError(t, solver.Present(name, values), "could not present challenge record")
+	}
+	require.NoError(t, cut.AcceptChallenges(ctx, order.URL), "could not accept challenges")
+	for order.Status == "pending" {
+		time.Sleep(time.Second)
+		order, err = cut.GetOrder(ctx, order.URL)
+		require.NoError(t, err, "could not get order")
+	}
+	require.Equal(t, "ready", order.Status, "unexpected order status")
+	chain, err := cut.FinalizeOrder(ctx, order.URL, csr)
+	require.NoError(t, err, "could not finalize order")
+	for name, values := range order.Records {
+		require.NoError(t, solver.CleanUp(name, values), "could not clean up challenge record")
+	}
+	return chain
+}
+
+// challTestSrvSolver presents challenge records through the management API of pebble-challtestsrv.
+type challTestSrvSolver struct {
+	url       string
+	cleanedUp []string
+}
+
+func (s *challTestSrvSolver) Present(name string, values []string) error {
+	for _, value := range values {
+		if err := s.post("/set-txt", map[string]string{"host": name + ".", "value": value}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *challTestSrvSolver) CleanUp(name string, values []string) error {
+	s.cleanedUp = append(s.cleanedUp, name)
+	return s.post("/clear-txt", map[string]string{"host": name + "."})
+}
+
+func (s *challTestSrvSolver) post(path string, request map[string]string) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+	resp, err := http.Post(s.url+path, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s from %s", resp.Status, path)
+	}
+	return nil
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/openshift/hive/pkg/apis/hive/v1/azure"
 	"github.com/openshift/hive/pkg/apis/hive/v1/baremetal"
 	"github.com/openshift/hive/pkg/apis/hive/v1/gcp"
+	"github.com/openshift/hive/pkg/apis/hive/v1/openstack"
+	"github.com/openshift/hive/pkg/apis/hive/v1/vsphere"
 )
 
 // NOTE: json tags are required.  Any new fields you add must have json tags for the fields to be serialized.
@@ -86,6 +88,12 @@ type ClusterDeploymentSpec struct {
 	// +optional
 	ManageDNS bool `json:"manageDNS,omitempty"`
 
+	// RFC2136TSIGKeySecretRef references a secret containing the TSIG key used to update the zone of the cluster when
+	// DNS is managed in a domain hosted on a DNS server updated through RFC2136 dynamic updates. The key must only be
+	// allowed to update the zone of the cluster. It is required to manage DNS in such a domain.
+	// +optional
+	RFC2136TSIGKeySecretRef *corev1.LocalObjectReference `json:"rfc2136TSIGKeySecretRef,omitempty"`
+
 	// ClusterMetadata contains metadata information about the installed cluster.
 	ClusterMetadata *ClusterMetadata `json:"clusterMetadata,omitempty"`
 
@@ -95,6 +103,29 @@ type ClusterDeploymentSpec struct {
 	// Provisioning contains settings used only for initial cluster provisioning.
 	// May be unset in the case of adopted clusters.
 	Provisioning *Provisioning `json:"provisioning,omitempty"`
+
+	// ClusterPoolRef is a reference to the ClusterPool that this ClusterDeployment originated from.
+	// +optional
+	ClusterPoolRef *ClusterPoolReference `json:"clusterPoolRef,omitempty"`
+
+	// PowerState indicates whether a cluster should be running or hibernating. When omitted,
+	// PowerState defaults to the Running state.
+	// +kubebuilder:validation:Enum=Running;Hibernating
+	// +optional
+	PowerState ClusterPowerState `json:"powerState,omitempty"`
+
+	// KubeadminPassword configures the management of the kubeadmin user of the cluster once it is installed.
+	// +optional
+	KubeadminPassword *KubeadminPasswordSpec `json:"kubeadminPassword,omitempty"`
+
+	// Upgrade requests an upgrade of the installed cluster to another release.
+	// +optional
+	Upgrade *ClusterUpgrade `json:"upgrade,omitempty"`
+
+	// MaintenanceWindows are the recurring periods of time during which an upgrade of the cluster may be started.
+	// Upgrades may be started at any time when no maintenance window is specified.
+	// +optional
+	MaintenanceWindows []MaintenanceWindow `json:"maintenanceWindows,omitempty"`
 }
 
 // Provisioning contains settings used only for initial cluster provisioning.
@@ -136,6 +167,36 @@ type Provisioning struct {
 	InstallerEnv []corev1.EnvVar `json:"installerEnv,omitempty"`
 }
 
+// ClusterUpgrade specifies the release an installed cluster is upgraded to.
+type ClusterUpgrade struct {
+	// ImageSetRef is a reference to a ClusterImageSet with the release image to upgrade the cluster to.
+	// +optional
+	ImageSetRef *ClusterImageSetReference `json:"imageSetRef,omitempty"`
+
+	// ReleaseImage is the release image to upgrade the cluster to. It takes precedence over ImageSetRef.
+	// +optional
+	ReleaseImage string `json:"releaseImage,omitempty"`
+
+	// Force upgrades the cluster even if the release image fails verification, or the cluster reports that the
+	// upgrade is not supported.
+	// +optional
+	Force bool `json:"force,omitempty"`
+}
+
+// MaintenanceWindow is a recurring period of time during which the cluster may be disrupted.
+type MaintenanceWindow struct {
+	// Days are the days of the week on which the window starts, such as Saturday. The window starts every day when
+	// no day is specified.
+	// +optional
+	Days []string `json:"days,omitempty"`
+
+	// StartTime is the time of day at which the window starts, in UTC, formatted as HH:MM.
+	StartTime string `json:"startTime"`
+
+	// Duration is how long the window lasts.
+	Duration metav1.Duration `json:"duration"`
+}
+
 // ClusterImageSetReference is a reference to a ClusterImageSet
 type ClusterImageSetReference struct {
 	// Name is the name of the ClusterImageSet that this refers to
@@ -154,6 +215,12 @@ type ClusterMetadata struct {
 	// AdminKubeconfigSecretRef references the secret containing the admin kubeconfig for this cluster.
 	AdminKubeconfigSecretRef corev1.LocalObjectReference `json:"adminKubeconfigSecretRef"`
 
+	// HiveManagedKubeconfigSecretRef references the secret containing the kubeconfig minted by Hive for this cluster
+	// after install. When set, Hive connects to the cluster with this kubeconfig instead of the admin kubeconfig, so the
+	// admin kubeconfig secret may be stored away or deleted.
+	// +optional
+	HiveManagedKubeconfigSecretRef *corev1.LocalObjectReference `json:"hiveManagedKubeconfigSecretRef,omitempty"`
+
 	// AdminPasswordSecretRef references the secret containing the admin username/password which can be used to login to this cluster.
 	AdminPasswordSecretRef corev1.LocalObjectReference `json:"adminPasswordSecretRef"`
 }
@@ -195,8 +262,38 @@ type ClusterDeploymentStatus struct {
 	// ProvisionRef is a reference to the last ClusterProvision created for the deployment
 	// +optional
 	ProvisionRef *corev1.LocalObjectReference `json:"provisionRef,omitempty"`
+
+	// PowerState is the observed power state of the cluster. It is only set once a cluster has been
+	// hibernated.
+	// +optional
+	PowerState ClusterPowerState `json:"powerState,omitempty"`
+
+	// KubeadminPassword is the status of the management of the kubeadmin user of the cluster.
+	// +optional
+	KubeadminPassword *KubeadminPasswordStatus `json:"kubeadminPassword,omitempty"`
 }
 
+// ClusterPowerState is used to indicate whether a cluster is running or hibernating.
+type ClusterPowerState string
+
+const (
+	// RunningClusterPowerState is the default state of a cluster after it has
+	// been installed. All of its machines should be running.
+	RunningClusterPowerState ClusterPowerState = "Running"
+
+	// HibernatingClusterPowerState is used to stop the machines belonging to a cluster
+	// and move it to a hibernating state.
+	HibernatingClusterPowerState ClusterPowerState = "Hibernating"
+
+	// StoppingClusterPowerState is the observed state of a cluster while its machines
+	// are being stopped.
+	StoppingClusterPowerState ClusterPowerState = "Stopping"
+
+	// ResumingClusterPowerState is the observed state of a cluster while its machines
+	// are being started and its nodes are rejoining the cluster.
+	ResumingClusterPowerState ClusterPowerState = "Resuming"
+)
+
 // ClusterDeploymentCondition contains details for the current condition of a cluster deployment
 type ClusterDeploymentCondition struct {
 	// Type is the type of the condition.
@@ -258,6 +355,33 @@ const (
 
 	// SyncSetFailedCondition indicates if any syncset for a cluster deployment failed
 	SyncSetFailedCondition ClusterDeploymentConditionType = "SyncSetFailed"
+
+	// ClusterHibernatingCondition is set when the ClusterDeployment is either
+	// transitioning to/from a hibernating state or is in a hibernating state.
+	ClusterHibernatingCondition ClusterDeploymentConditionType = "Hibernating"
+
+	// ProvisionStoppedCondition is set when a provision failed with an error that will not be resolved by
+	// retrying, and no further provisions will be attempted for the ClusterDeployment.
+	ProvisionStoppedCondition ClusterDeploymentConditionType = "ProvisionStopped"
+
+	// CertificateExpiringCondition is set when a certificate served by the control plane or an ingress of the
+	// cluster expires within the certificate expiry warning window, or has expired.
+	CertificateExpiringCondition ClusterDeploymentConditionType = "CertificateExpiring"
+
+	// UpgradeBlockedCondition is set when the upgrade requested for the cluster cannot be started, because the
+	// cluster is unreachable, syncsets fail to apply to the cluster, or the release to upgrade to is not found.
+	UpgradeBlockedCondition ClusterDeploymentConditionType = "UpgradeBlocked"
+
+	// UpgradeProgressingCondition is set while the cluster is upgrading to the release requested for the cluster.
+	UpgradeProgressingCondition ClusterDeploymentConditionType = "UpgradeProgressing"
+
+	// UpgradeFailedCondition is set when the cluster reports a failure while upgrading to the release requested
+	// for the cluster.
+	UpgradeFailedCondition ClusterDeploymentConditionType = "UpgradeFailed"
+
+	// ReleaseImageNotAllowedCondition is set when the release image of the cluster is not allowed by the release
+	// image policy of Hive, preventing the cluster from being installed.
+	ReleaseImageNotAllowedCondition ClusterDeploymentConditionType = "ReleaseImageNotAllowed"
 )
 
 // AllClusterDeploymentConditions is a slice containing all condition types. This can be used for dealing with
@@ -273,6 +397,13 @@ var AllClusterDeploymentConditions = []ClusterDeploymentConditionType{
 	DNSNotReadyCondition,
 	ProvisionFailedCondition,
 	SyncSetFailedCondition,
+	ClusterHibernatingCondition,
+	ProvisionStoppedCondition,
+	CertificateExpiringCondition,
+	UpgradeBlockedCondition,
+	UpgradeProgressingCondition,
+	UpgradeFailedCondition,
+	ReleaseImageNotAllowedCondition,
 }
 
 // +genclient
@@ -286,6 +417,7 @@ var AllClusterDeploymentConditions = []ClusterDeploymentConditionType{
 // +kubebuilder:printcolumn:name="BaseDomain",type="string",JSONPath=".spec.baseDomain"
 // +kubebuilder:printcolumn:name="Installed",type="boolean",JSONPath=".spec.installed"
 // +kubebuilder:printcolumn:name="InfraID",type="string",JSONPath=".spec.clusterMetadata.infraID"
+// +kubebuilder:printcolumn:name="PowerState",type="string",JSONPath=".status.powerState"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:resource:path=clusterdeployments,shortName=cd
 type ClusterDeployment struct {
@@ -321,6 +453,14 @@ type Platform struct {
 
 	// BareMetal is the configuration used when installing on bare metal.
 	BareMetal *baremetal.Platform `json:"bareMetal,omitempty"`
+
+	// OpenStack is the configuration used when installing on OpenStack
+	// +optional
+	OpenStack *openstack.Platform `json:"openstack,omitempty"`
+
+	// VSphere is the configuration used when installing on vSphere
+	// +optional
+	VSphere *vsphere.Platform `json:"vsphere,omitempty"`
 }
 
 // ClusterIngress contains the configurable pieces for any ClusterIngress objects
@@ -364,6 +504,47 @@ type ControlPlaneConfigSpec struct {
 	// active, Hive will use the override URL for further communications with the API server of the remote cluster.
 	// +optional
 	APIURLOverride string `json:"apiURLOverride,omitempty"`
+
+	// Proxy is the optional proxy through which Hive will connect to the API server of the remote cluster. This allows
+	// Hive to manage clusters whose API server can only be reached through a corporate proxy, or through an SSH
+	// bastion host.
+	// +optional
+	Proxy *ControlPlaneProxy `json:"proxy,omitempty"`
+}
+
+// ControlPlaneProxy specifies a proxy through which Hive connects to the API server of the remote cluster. Exactly one
+// of URL or SSHBastion must be specified.
+type ControlPlaneProxy struct {
+	// URL is the URL of the proxy. The http, https and socks5 schemes are supported.
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// CredentialsSecretRef refers to a secret in the namespace of the ClusterDeployment with the "username" and
+	// "password" used to authenticate with the proxy at the URL.
+	// +optional
+	CredentialsSecretRef *corev1.LocalObjectReference `json:"credentialsSecretRef,omitempty"`
+
+	// SSHBastion is the SSH bastion host through which connections to the API server are tunneled.
+	// +optional
+	SSHBastion *SSHBastion `json:"sshBastion,omitempty"`
+}
+
+// SSHBastion specifies an SSH bastion host through which Hive tunnels connections to the API server of the remote
+// cluster.
+type SSHBastion struct {
+	// Host is the host of the bastion, with an optional port which defaults to 22.
+	Host string `json:"host"`
+
+	// User is the user to log in to the bastion as.
+	User string `json:"user"`
+
+	// PrivateKeySecretRef refers to a secret in the namespace of the ClusterDeployment with the SSH private key used
+	// to log in to the bastion. The SSH private key is expected to be in the secret data under the "ssh-privatekey" key.
+	PrivateKeySecretRef corev1.LocalObjectReference `json:"privateKeySecretRef"`
+
+	// KnownHosts are the known_hosts entries with the host keys of the bastion. Connections to the bastion are only
+	// made when the bastion presents one of these host keys. Hashed host names are not supported.
+	KnownHosts []string `json:"knownHosts"`
 }
 
 // ControlPlaneServingCertificateSpec specifies serving certificate settings for
@@ -398,6 +579,7 @@ type CertificateBundleSpec struct {
 	Name string `json:"name"`
 
 	// Generate indicates whether this bundle should have real certificates generated for it.
+	// Certificates are generated from the ACME server configured in HiveConfig.
 	// +optional
 	Generate bool `json:"generate,omitempty"`
 
@@ -416,6 +598,47 @@ type CertificateBundleStatus struct {
 
 	// Generated indicates whether the certificate bundle was generated
 	Generated bool `json:"generated"`
+
+	// NotAfter is the expiry time of the generated certificate.
+	// +optional
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+
+	// OrderURL is the URL of the pending order of a certificate from the ACME server while the certificate
+	// bundle is being generated.
+	// +optional
+	OrderURL string `json:"orderURL,omitempty"`
+}
+
+// KubeadminPasswordSpec configures the management of the kubeadmin user of a cluster. The kubeadmin password is
+// rotated by regenerating the kube-system/kubeadmin secret on the cluster, and the new password is saved in the
+// secret referenced by AdminPasswordSecretRef.
+type KubeadminPasswordSpec struct {
+	// RotationInterval is how often the kubeadmin password is rotated. The password is not rotated on a schedule
+	// when unset.
+	// +optional
+	RotationInterval *metav1.Duration `json:"rotationInterval,omitempty"`
+
+	// RotationRequestedAt requests a rotation of the kubeadmin password. The password is rotated once this time has
+	// passed, unless it has been rotated since.
+	// +optional
+	RotationRequestedAt *metav1.Time `json:"rotationRequestedAt,omitempty"`
+
+	// RemoveAfterIdentityProviders dictates if the kubeadmin user is removed from the cluster once the identity
+	// providers of the SyncIdentityProviders and SelectorSyncIdentityProviders matching the cluster have been
+	// applied successfully.
+	// +optional
+	RemoveAfterIdentityProviders bool `json:"removeAfterIdentityProviders,omitempty"`
+}
+
+// KubeadminPasswordStatus contains the status of the management of the kubeadmin user of a cluster.
+type KubeadminPasswordStatus struct {
+	// LastRotationTime is the last time the kubeadmin password was rotated.
+	// +optional
+	LastRotationTime *metav1.Time `json:"lastRotationTime,omitempty"`
+
+	// Removed is true once the kubeadmin user has been removed from the cluster.
+	// +optional
+	Removed bool `json:"removed,omitempty"`
 }
 
 func init() {
@@ -32,6 +32,10 @@ type ClusterDeprovisionPlatform struct {
 	Azure *AzureClusterDeprovision `json:"azure,omitempty"`
 	// GCP contains GCP-specific deprovision settings
 	GCP *GCPClusterDeprovision `json:"gcp,omitempty"`
+	// OpenStack contains OpenStack-specific deprovision settings
+	OpenStack *OpenStackClusterDeprovision `json:"openstack,omitempty"`
+	// VSphere contains vSphere-specific deprovision settings
+	VSphere *VSphereClusterDeprovision `json:"vsphere,omitempty"`
 }
 
 // AWSClusterDeprovision contains AWS-specific configuration for a ClusterDeprovision
@@ -57,6 +61,25 @@ type GCPClusterDeprovision struct {
 	CredentialsSecretRef *corev1.LocalObjectReference `json:"credentialsSecretRef,omitempty"`
 }
 
+// OpenStackClusterDeprovision contains OpenStack-specific configuration for a ClusterDeprovision
+type OpenStackClusterDeprovision struct {
+	// Cloud is the section in the clouds.yaml secret to use for auth/connectivity.
+	Cloud string `json:"cloud"`
+	// CredentialsSecretRef is the OpenStack account credentials to use for deprovisioning the cluster
+	CredentialsSecretRef *corev1.LocalObjectReference `json:"credentialsSecretRef,omitempty"`
+}
+
+// VSphereClusterDeprovision contains VMware vSphere-specific configuration for a ClusterDeprovision
+type VSphereClusterDeprovision struct {
+	// CredentialsSecretRef is the vSphere account credentials to use for deprovisioning the cluster
+	CredentialsSecretRef corev1.LocalObjectReference `json:"credentialsSecretRef"`
+	// CertificatesSecretRef refers to a secret that contains the vSphere CA certificates
+	// necessary for communicating with the VCenter.
+	CertificatesSecretRef corev1.LocalObjectReference `json:"certificatesSecretRef"`
+	// VCenter is the vSphere vCenter hostname.
+	VCenter string `json:"vCenter"`
+}
+
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
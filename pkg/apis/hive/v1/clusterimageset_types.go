@@ -1,6 +1,7 @@
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -12,7 +13,64 @@ type ClusterImageSetSpec struct {
 }
 
 // ClusterImageSetStatus defines the observed state of ClusterImageSet
-type ClusterImageSetStatus struct{}
+type ClusterImageSetStatus struct {
+	// ReleaseImage is the release image from which the metadata in the status was resolved.
+	// +optional
+	ReleaseImage string `json:"releaseImage,omitempty"`
+
+	// Version is the OpenShift version of the release.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Channel is the upgrade graph channel of the release, such as stable-4.5.
+	// +optional
+	Channel string `json:"channel,omitempty"`
+
+	// InstallerImage is the installer image of the release, referenced by digest.
+	// +optional
+	InstallerImage string `json:"installerImage,omitempty"`
+
+	// BareMetalInstallerImage is the installer image of the release used for bare metal installs, referenced by digest.
+	// +optional
+	BareMetalInstallerImage string `json:"bareMetalInstallerImage,omitempty"`
+
+	// CLIImage is the cli image of the release, referenced by digest.
+	// +optional
+	CLIImage string `json:"cliImage,omitempty"`
+
+	// Conditions includes more detailed status for the cluster image set.
+	// +optional
+	Conditions []ClusterImageSetCondition `json:"conditions,omitempty"`
+}
+
+// ClusterImageSetCondition contains details for the current condition of a cluster image set.
+type ClusterImageSetCondition struct {
+	// Type is the type of the condition.
+	Type ClusterImageSetConditionType `json:"type"`
+	// Status is the status of the condition.
+	Status corev1.ConditionStatus `json:"status"`
+	// LastProbeTime is the last time we probed the condition.
+	// +optional
+	LastProbeTime metav1.Time `json:"lastProbeTime,omitempty"`
+	// LastTransitionTime is the last time the condition transitioned from one status to another.
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// Reason is a unique, one-word, CamelCase reason for the condition's last transition.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Message is a human-readable message indicating details about last transition.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// ClusterImageSetConditionType is a valid value for ClusterImageSetCondition.Type.
+type ClusterImageSetConditionType string
+
+const (
+	// ClusterImageSetValidCondition is true when the metadata of the release image has been resolved, and false when
+	// the release image cannot be resolved.
+	ClusterImageSetValidCondition ClusterImageSetConditionType = "Valid"
+)
 
 // +genclient:nonNamespaced
 // +genclient
@@ -22,6 +80,8 @@ type ClusterImageSetStatus struct{}
 // +k8s:openapi-gen=true
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="Release",type="string",JSONPath=".spec.releaseImage"
+// +kubebuilder:printcolumn:name="Version",type="string",JSONPath=".status.version"
+// +kubebuilder:printcolumn:name="Valid",type="string",JSONPath=".status.conditions[?(@.type=='Valid')].status"
 // +kubebuilder:resource:path=clusterimagesets,shortName=imgset
 type ClusterImageSet struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -0,0 +1,98 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// FinalizerClusterPoolCleanup is used on ClusterPools to ensure that all of the ClusterDeployments
+	// and namespaces created for the pool are cleaned up before the pool is removed.
+	FinalizerClusterPoolCleanup string = "hive.openshift.io/clusterpool-cleanup"
+)
+
+// ClusterPoolSpec defines the desired state of the ClusterPool.
+type ClusterPoolSpec struct {
+
+	// Platform encompasses the desired platform for the cluster.
+	// +required
+	Platform Platform `json:"platform"`
+
+	// PullSecretRef is the reference to the secret to use when pulling images.
+	// +optional
+	PullSecretRef *corev1.LocalObjectReference `json:"pullSecretRef,omitempty"`
+
+	// Size is the number of unclaimed clusters to keep installed and ready in the pool.
+	// +kubebuilder:validation:Minimum=0
+	// +required
+	Size int32 `json:"size"`
+
+	// BaseDomain is the base domain to use for all clusters created in this pool.
+	// +required
+	BaseDomain string `json:"baseDomain"`
+
+	// ImageSetRef is a reference to a ClusterImageSet. The release image specified in the ClusterImageSet will be used
+	// by clusters created for this cluster pool.
+	// +required
+	ImageSetRef ClusterImageSetReference `json:"imageSetRef"`
+}
+
+// ClusterPoolStatus defines the observed state of ClusterPool
+type ClusterPoolStatus struct {
+	// Size is the number of unclaimed clusters that have been created for the pool.
+	Size int32 `json:"size"`
+
+	// Ready is the number of unclaimed clusters that have been installed and are ready to be claimed.
+	Ready int32 `json:"ready"`
+
+	// Installing is the number of unclaimed clusters that are still being installed.
+	Installing int32 `json:"installing"`
+}
+
+// ClusterPoolReference is a reference to a ClusterPool
+type ClusterPoolReference struct {
+	// Namespace is the namespace where the ClusterPool resides.
+	Namespace string `json:"namespace"`
+
+	// PoolName is the name of the ClusterPool for which the cluster was created.
+	PoolName string `json:"poolName"`
+
+	// ClaimName is the name of the ClusterClaim that claimed the cluster from the pool.
+	// +optional
+	ClaimName string `json:"claimName,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterPool represents a pool of clusters that should be kept ready to be given out to users. Clusters are removed
+// from the pool once claimed and then automatically replaced with a new one.
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:subresource:scale:specpath=.spec.size,statuspath=.status.size
+// +kubebuilder:printcolumn:name="Size",type="integer",JSONPath=".spec.size"
+// +kubebuilder:printcolumn:name="Ready",type="integer",JSONPath=".status.ready"
+// +kubebuilder:printcolumn:name="Installing",type="integer",JSONPath=".status.installing"
+// +kubebuilder:printcolumn:name="BaseDomain",type="string",JSONPath=".spec.baseDomain"
+// +kubebuilder:printcolumn:name="ImageSet",type="string",JSONPath=".spec.imageSetRef.name"
+// +kubebuilder:resource:path=clusterpools,shortName=cp
+type ClusterPool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterPoolSpec   `json:"spec"`
+	Status ClusterPoolStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterPoolList contains a list of ClusterPools
+type ClusterPoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterPool `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterPool{}, &ClusterPoolList{})
+}
@@ -30,6 +30,11 @@ type ClusterProvisionSpec struct {
 	// InstallLog is the log from the installer.
 	InstallLog *string `json:"installLog,omitempty"`
 
+	// InstallLogArchive references the logs of the provision stored in the install log archive configured in
+	// HiveConfig.
+	// +optional
+	InstallLogArchive *InstallLogArchiveReference `json:"installLogArchive,omitempty"`
+
 	// Metadata is the metadata.json generated by the installer, providing metadata information about the cluster created.
 	Metadata *runtime.RawExtension `json:"metadata,omitempty"`
 
@@ -46,6 +51,16 @@ type ClusterProvisionSpec struct {
 	PrevInfraID *string `json:"prevInfraID,omitempty"`
 }
 
+// InstallLogArchiveReference references the objects of the install log archive holding the logs of a provision.
+type InstallLogArchiveReference struct {
+	// Bucket is the bucket of the install log archive holding the objects.
+	Bucket string `json:"bucket"`
+
+	// Objects are the keys of the objects holding the logs of the provision.
+	// +optional
+	Objects []string `json:"objects,omitempty"`
+}
+
 // ClusterProvisionStatus defines the observed state of ClusterProvision.
 type ClusterProvisionStatus struct {
 	// JobRef is the reference to the job performing the provision.
@@ -54,8 +69,49 @@ type ClusterProvisionStatus struct {
 	// Conditions includes more detailed status for the cluster provision
 	// +optional
 	Conditions []ClusterProvisionCondition `json:"conditions,omitempty"`
+
+	// InstallFailure is the classification of the failure of the install, set when the provision fails.
+	// +optional
+	InstallFailure *InstallFailure `json:"installFailure,omitempty"`
 }
 
+// InstallFailure contains the details of why a cluster install failed, as determined from the installer log.
+type InstallFailure struct {
+	// Reason is a unique, one-word, CamelCase reason for the failure.
+	Reason string `json:"reason"`
+
+	// Message is a human-readable message describing the failure.
+	Message string `json:"message"`
+
+	// Stage is the stage of the install that the installer was in when it failed.
+	Stage InstallFailureStage `json:"stage"`
+
+	// FailingOperators are the names of the cluster operators that failed to become available when the
+	// install failed waiting for the cluster operators.
+	// +optional
+	FailingOperators []string `json:"failingOperators,omitempty"`
+
+	// Retryable is true when a new provision may succeed where this provision failed. Provisions are not
+	// retried after failures which are not retryable, such as quota or permission errors.
+	Retryable bool `json:"retryable"`
+}
+
+// InstallFailureStage is the stage of the install in which an install failed.
+type InstallFailureStage string
+
+const (
+	// InstallFailureStageInfrastructure indicates that the install failed creating the cloud infrastructure.
+	InstallFailureStageInfrastructure InstallFailureStage = "Infrastructure"
+	// InstallFailureStageBootstrap indicates that the install failed waiting for the bootstrap of the cluster to complete.
+	InstallFailureStageBootstrap InstallFailureStage = "Bootstrap"
+	// InstallFailureStageClusterOperators indicates that the install failed waiting for the cluster operators to
+	// become available.
+	InstallFailureStageClusterOperators InstallFailureStage = "ClusterOperators"
+	// InstallFailureStageUnknown indicates that the stage of the install in which the install failed could not be
+	// determined.
+	InstallFailureStageUnknown InstallFailureStage = "Unknown"
+)
+
 // ClusterProvisionStage is the stage of provisioning.
 type ClusterProvisionStage string
 
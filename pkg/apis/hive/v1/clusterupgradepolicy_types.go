@@ -0,0 +1,145 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ClusterUpgradePolicySpec defines the desired state of ClusterUpgradePolicy
+type ClusterUpgradePolicySpec struct {
+	// ClusterDeploymentSelector is a LabelSelector indicating which clusters the ClusterUpgradePolicy applies to
+	// in any namespace.
+	// +optional
+	ClusterDeploymentSelector metav1.LabelSelector `json:"clusterDeploymentSelector,omitempty"`
+
+	// ImageSetRef is a reference to the ClusterImageSet with the release to upgrade the selected clusters to.
+	ImageSetRef ClusterImageSetReference `json:"imageSetRef"`
+
+	// Waves are the groups of selected clusters that are upgraded one after the other. A cluster belongs to the first
+	// wave that selects it, and clusters selected by none of the waves are not upgraded. A wave starts once all the
+	// clusters of the previous wave have been upgraded, or have failed within the limit of failures of the wave. All
+	// the selected clusters are upgraded in a single wave with no limits when no wave is specified.
+	// +optional
+	Waves []ClusterUpgradeWave `json:"waves,omitempty"`
+}
+
+// ClusterUpgradeWave is a group of clusters upgraded together.
+type ClusterUpgradeWave struct {
+	// Name is the name of the wave, unique within the policy.
+	Name string `json:"name"`
+
+	// ClusterDeploymentSelector is a LabelSelector indicating which of the clusters selected by the policy belong to
+	// the wave. An empty selector selects all the remaining clusters.
+	// +optional
+	ClusterDeploymentSelector metav1.LabelSelector `json:"clusterDeploymentSelector,omitempty"`
+
+	// MaxParallel is the maximum number of clusters of the wave that are upgrading at the same time. All the
+	// clusters of the wave are upgraded at the same time when zero.
+	// +optional
+	MaxParallel int32 `json:"maxParallel,omitempty"`
+
+	// MaxFailures is the number of clusters of the wave whose upgrade may fail before the rollout is stopped.
+	// +optional
+	MaxFailures int32 `json:"maxFailures,omitempty"`
+}
+
+// ClusterUpgradePolicyStatus defines the observed state of ClusterUpgradePolicy
+type ClusterUpgradePolicyStatus struct {
+	// ClusterDeployments is the number of installed clusters selected by the policy.
+	// +optional
+	ClusterDeployments int32 `json:"clusterDeployments,omitempty"`
+
+	// UpgradedClusterDeployments is the number of selected clusters that have completed the upgrade to the release
+	// of the policy.
+	// +optional
+	UpgradedClusterDeployments int32 `json:"upgradedClusterDeployments,omitempty"`
+
+	// CurrentWave is the name of the wave being rolled out, or of the wave that stopped the rollout.
+	// +optional
+	CurrentWave string `json:"currentWave,omitempty"`
+
+	// Waves is the progress of the rollout of each wave.
+	// +optional
+	Waves []ClusterUpgradeWaveStatus `json:"waves,omitempty"`
+}
+
+// ClusterUpgradeWaveState is the state of the rollout of a wave.
+type ClusterUpgradeWaveState string
+
+const (
+	// ClusterUpgradeWavePending is the state of a wave waiting for the previous waves to complete.
+	ClusterUpgradeWavePending ClusterUpgradeWaveState = "Pending"
+	// ClusterUpgradeWaveProgressing is the state of a wave whose clusters are being upgraded.
+	ClusterUpgradeWaveProgressing ClusterUpgradeWaveState = "Progressing"
+	// ClusterUpgradeWavePaused is the state of a wave that starts no new upgrades, because cluster operators of its
+	// upgraded clusters are degraded.
+	ClusterUpgradeWavePaused ClusterUpgradeWaveState = "Paused"
+	// ClusterUpgradeWaveCompleted is the state of a wave whose clusters have all been upgraded, or have failed within
+	// the limit of failures of the wave.
+	ClusterUpgradeWaveCompleted ClusterUpgradeWaveState = "Completed"
+	// ClusterUpgradeWaveFailed is the state of a wave with more failed upgrades than the limit of failures of the
+	// wave. The rollout is stopped.
+	ClusterUpgradeWaveFailed ClusterUpgradeWaveState = "Failed"
+)
+
+// ClusterUpgradeWaveStatus is the progress of the rollout of a wave.
+type ClusterUpgradeWaveStatus struct {
+	// Name is the name of the wave.
+	Name string `json:"name"`
+
+	// State is the state of the rollout of the wave.
+	State ClusterUpgradeWaveState `json:"state"`
+
+	// Message explains the state of the wave.
+	// +optional
+	Message string `json:"message,omitempty"`
+
+	// ClusterDeployments is the number of clusters in the wave.
+	// +optional
+	ClusterDeployments int32 `json:"clusterDeployments,omitempty"`
+
+	// UpgradingClusterDeployments is the number of clusters of the wave that are upgrading.
+	// +optional
+	UpgradingClusterDeployments int32 `json:"upgradingClusterDeployments,omitempty"`
+
+	// UpgradedClusterDeployments is the number of clusters of the wave that have completed the upgrade.
+	// +optional
+	UpgradedClusterDeployments int32 `json:"upgradedClusterDeployments,omitempty"`
+
+	// FailedClusterDeployments is the number of clusters of the wave whose upgrade has failed.
+	// +optional
+	FailedClusterDeployments int32 `json:"failedClusterDeployments,omitempty"`
+}
+
+// +genclient:nonNamespaced
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterUpgradePolicy is the Schema for the clusterupgradepolicies API. It upgrades the installed clusters matching a
+// label selector to the release of a ClusterImageSet, in waves.
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="ImageSet",type="string",JSONPath=".spec.imageSetRef.name"
+// +kubebuilder:printcolumn:name="Clusters",type="integer",JSONPath=".status.clusterDeployments"
+// +kubebuilder:printcolumn:name="Upgraded",type="integer",JSONPath=".status.upgradedClusterDeployments"
+// +kubebuilder:printcolumn:name="Wave",type="string",JSONPath=".status.currentWave"
+// +kubebuilder:resource:path=clusterupgradepolicies,shortName=cup
+type ClusterUpgradePolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterUpgradePolicySpec   `json:"spec,omitempty"`
+	Status ClusterUpgradePolicyStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// ClusterUpgradePolicyList contains a list of ClusterUpgradePolicy
+type ClusterUpgradePolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterUpgradePolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ClusterUpgradePolicy{}, &ClusterUpgradePolicyList{})
+}
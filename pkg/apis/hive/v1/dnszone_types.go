@@ -33,6 +33,14 @@ type DNSZoneSpec struct {
 	// GCP specifies GCP-specific cloud configuration
 	// +optional
 	GCP *GCPDNSZoneSpec `json:"gcp,omitempty"`
+
+	// Azure specifies Azure-specific cloud configuration
+	// +optional
+	Azure *AzureDNSZoneSpec `json:"azure,omitempty"`
+
+	// RFC2136 specifies the configuration for a DNS server updated through RFC2136 dynamic updates
+	// +optional
+	RFC2136 *RFC2136DNSZoneSpec `json:"rfc2136,omitempty"`
 }
 
 // AWSDNSZoneSpec contains AWS-specific DNSZone specifications
@@ -70,6 +78,30 @@ type GCPDNSZoneSpec struct {
 	CredentialsSecretRef corev1.LocalObjectReference `json:"credentialsSecretRef"`
 }
 
+// AzureDNSZoneSpec contains Azure-specific DNSZone specifications
+type AzureDNSZoneSpec struct {
+	// CredentialsSecretRef references a secret that will be used to authenticate with
+	// Azure DNS. It will need permission to create and manage DNS zones in the resource group.
+	// Secret should have a key named 'osServicePrincipal.json'.
+	CredentialsSecretRef corev1.LocalObjectReference `json:"credentialsSecretRef"`
+
+	// ResourceGroupName specifies the Azure resource group in which the DNS zone is created.
+	ResourceGroupName string `json:"resourceGroupName"`
+}
+
+// RFC2136DNSZoneSpec contains the specifications of a DNSZone hosted on a DNS server, such as BIND, updated through
+// RFC2136 dynamic updates. Dynamic updates cannot create or delete zones, so the zone must be configured on the
+// DNS server before the DNSZone is created.
+type RFC2136DNSZoneSpec struct {
+	// Server is the address of the DNS server hosting the zone, with an optional port which defaults to 53.
+	Server string `json:"server"`
+
+	// TSIGKeySecretRef references a secret containing the TSIG key used to authenticate the dynamic updates and
+	// zone transfers. Secret should have keys named 'keyName' and 'secret', holding the name and the base64-encoded
+	// secret of the key, and may have a key named 'algorithm', which defaults to hmac-sha256.
+	TSIGKeySecretRef corev1.LocalObjectReference `json:"tsigKeySecretRef"`
+}
+
 // DNSZoneStatus defines the observed state of DNSZone
 type DNSZoneStatus struct {
 	// LastSyncTimestamp is the time that the zone was last sync'd.
@@ -92,6 +124,10 @@ type DNSZoneStatus struct {
 	// +optional
 	GCP *GCPDNSZoneStatus `json:"gcp,omitempty"`
 
+	// AzureDNSZoneStatus contains status information specific to Azure
+	// +optional
+	Azure *AzureDNSZoneStatus `json:"azure,omitempty"`
+
 	// Conditions includes more detailed status for the DNSZone
 	// +optional
 	Conditions []DNSZoneCondition `json:"conditions,omitempty"`
@@ -111,6 +147,13 @@ type GCPDNSZoneStatus struct {
 	ZoneName *string `json:"zoneName,omitempty"`
 }
 
+// AzureDNSZoneStatus contains status information specific to Azure DNS zones
+type AzureDNSZoneStatus struct {
+	// ZoneName is the name of the zone in Azure DNS
+	// +optional
+	ZoneName *string `json:"zoneName,omitempty"`
+}
+
 // DNSZoneCondition contains details for the current condition of a DNSZone
 type DNSZoneCondition struct {
 	// Type is the type of the condition.
@@ -46,6 +46,12 @@ type HiveConfigSpec struct {
 	// The default reapply interval is two hours.
 	SyncSetReapplyInterval string `json:"syncSetReapplyInterval,omitempty"`
 
+	// SyncSetDriftCheckInterval is a string duration indicating how often SyncSet resources with drift checks
+	// enabled are checked for drift on the target cluster.
+	// The default drift check interval is ten minutes.
+	// +optional
+	SyncSetDriftCheckInterval string `json:"syncSetDriftCheckInterval,omitempty"`
+
 	// HiveAPIEnabled is a boolean controlling whether or not the Hive operator will start up
 	// the v1alpha1 aggregated API server.
 	HiveAPIEnabled bool `json:"hiveAPIEnabled,omitempty"`
@@ -55,8 +61,160 @@ type HiveConfigSpec struct {
 	// Sets replicas to 0 for the hive-controllers deployment to accomplish this.
 	MaintenanceMode *bool `json:"maintenanceMode,omitempty"`
 
+	// ControllerShards is the number of shards the hive controllers are split into. Each shard is deployed as a
+	// separate hive-controllers-shard-N deployment with its own leader election, and reconciles only the resources in
+	// the namespaces which hash into the shard. ClusterDeployments and the resources depending on them, such as
+	// SyncSetInstances, MachinePools and ClusterProvisions, share a namespace and so are reconciled by the same shard.
+	// Changing the number of shards restarts all shards, rebalancing the namespaces across them.
+	// The default is a single hive-controllers deployment reconciling all namespaces.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ControllerShards int32 `json:"controllerShards,omitempty"`
+
+	// ControllersConfig configures the concurrency and rate limits of the hive controllers.
+	// +optional
+	ControllersConfig *ControllersConfig `json:"controllersConfig,omitempty"`
+
 	// DeprovisionsDisabled can be set to true to block deprovision jobs from running.
 	DeprovisionsDisabled *bool `json:"deprovisionsDisabled,omitempty"`
+
+	// ACME configures the ACME certificate authority issuing the ClusterDeployment certificate bundles which are
+	// to be generated. Certificate bundles are not generated when ACME is not configured.
+	// +optional
+	ACME *ACMEConfig `json:"acme,omitempty"`
+
+	// CertificateExpiryWarningWindow is how long before their expiry the certificates served by the control plane and
+	// ingresses of ClusterDeployments are reported by the CertificateExpiring condition.
+	// The default is 14 days.
+	// +optional
+	CertificateExpiryWarningWindow *metav1.Duration `json:"certificateExpiryWarningWindow,omitempty"`
+
+	// HiveManagedKubeconfig configures the credentials minted by Hive on installed clusters for its own use.
+	// +optional
+	HiveManagedKubeconfig HiveManagedKubeconfigConfig `json:"hiveManagedKubeconfig,omitempty"`
+
+	// ReleaseImagePolicy restricts the release images from which clusters can be installed or to which they can be
+	// upgraded. The policy is enforced when a ClusterDeployment is created or its upgrade is requested, and again
+	// before the installer image of a cluster is resolved and before a cluster is upgraded.
+	// +optional
+	ReleaseImagePolicy *ReleaseImagePolicy `json:"releaseImagePolicy,omitempty"`
+
+	// InstallLogArchive configures an S3-compatible object store in which the logs of installs are archived. The
+	// installer logs and the logs gathered after failed installs are uploaded there by the install pods, and
+	// referenced by the ClusterProvisions.
+	// +optional
+	InstallLogArchive *InstallLogArchiveConfig `json:"installLogArchive,omitempty"`
+}
+
+// InstallLogArchiveConfig contains the settings of the S3-compatible object store in which the logs of installs
+// are archived.
+type InstallLogArchiveConfig struct {
+	// Endpoint is the URL of the S3-compatible service, such as https://minio.example.com:9000. Objects are
+	// addressed with path-style URLs when an endpoint is specified.
+	// If not specified, AWS S3 is used.
+	// +optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// Region is the region of the bucket.
+	// The default is us-east-1.
+	// +optional
+	Region string `json:"region,omitempty"`
+
+	// Bucket is the bucket in which the logs are stored. The bucket must exist.
+	Bucket string `json:"bucket"`
+
+	// Prefix is prepended to the keys of the objects holding the logs. The logs of a provision are stored under
+	// <prefix>/<namespace>/<clusterprovision name>/. A prefix is required when a retention is set.
+	// +optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// CredentialsSecretRef references a secret in the 'hive' namespace holding the access key of the object store in
+	// 'aws_access_key_id' and 'aws_secret_access_key' keys. The credentials are not shared with the install pods,
+	// which are given presigned URLs to upload the logs of their provision instead.
+	CredentialsSecretRef corev1.LocalObjectReference `json:"credentialsSecretRef"`
+
+	// Retention is how long the logs are kept in the object store. Older objects under the prefix are deleted by
+	// Hive, so the prefix must not hold anything else.
+	// If not specified, logs are kept until they are deleted from the object store by other means.
+	// +optional
+	Retention *metav1.Duration `json:"retention,omitempty"`
+}
+
+// ReleaseImagePolicy contains the restrictions on the release images from which clusters can be installed, either
+// directly or through a ClusterImageSet.
+type ReleaseImagePolicy struct {
+	// AllowedRegistries are the registries from which release images can be pulled, such as quay.io or
+	// registry.example.com:5000. An entry can also restrict release images to the repositories under a path of a
+	// registry, such as quay.io/openshift-release-dev.
+	// When empty, release images can be pulled from any registry.
+	// +optional
+	AllowedRegistries []string `json:"allowedRegistries,omitempty"`
+
+	// RequireDigest requires release images to be referenced by digest rather than by tag, so that the content of
+	// a release image cannot change after it has been allowed.
+	// +optional
+	RequireDigest bool `json:"requireDigest,omitempty"`
+
+	// SignatureVerification requires release images to be signed by a trusted GPG key. Release images must be
+	// referenced by digest when signature verification is configured.
+	// +optional
+	SignatureVerification *ReleaseImageSignatureVerification `json:"signatureVerification,omitempty"`
+}
+
+// ReleaseImageSignatureVerification configures the verification of the GPG signatures of release images. Signatures
+// are fetched from signature stores laid out like the OpenShift release signature store, which serves the signatures
+// of a release image at <store>/sha256=<digest>/signature-<n>, n counting from 1.
+type ReleaseImageSignatureVerification struct {
+	// PublicKeysSecretRef references a secret in the 'hive' namespace holding the ASCII-armored GPG public keys trusted
+	// to sign release images. Each key of the secret holds one or more public keys.
+	PublicKeysSecretRef corev1.LocalObjectReference `json:"publicKeysSecretRef"`
+
+	// SignatureStores are the URLs of the signature stores from which the signatures of release images are fetched,
+	// such as https://mirror.openshift.com/pub/openshift-v4/signatures/openshift/release.
+	SignatureStores []string `json:"signatureStores"`
+}
+
+// HiveManagedKubeconfigConfig contains settings for the kubeconfigs minted by Hive on installed clusters. When enabled,
+// Hive creates a service account bound to the cluster-admin role on each installed cluster, issues a client
+// certificate for it, and connects to the cluster with the resulting kubeconfig instead of the admin kubeconfig
+// generated by the installer.
+type HiveManagedKubeconfigConfig struct {
+	// Enabled dictates if Hive mints its own kubeconfigs for installed clusters.
+	// If not specified, the default is disabled.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// RotationInterval is how often a new client certificate is issued for the kubeconfig minted by Hive. Client
+	// certificates are also renewed once 80% of their validity has passed.
+	// The default is 7 days.
+	// +optional
+	RotationInterval *metav1.Duration `json:"rotationInterval,omitempty"`
+}
+
+// ACMEConfig contains the settings of the ACME certificate authority used to generate certificate bundles.
+// Certificates are validated through DNS-01 challenges solved in the managed DNSZone of the ClusterDeployment.
+type ACMEConfig struct {
+	// DirectoryURL is the URL of the directory of the ACME server, such as
+	// https://acme-v02.api.letsencrypt.org/directory.
+	DirectoryURL string `json:"directoryURL"`
+
+	// Email is the contact address registered with the ACME account.
+	// +optional
+	Email string `json:"email,omitempty"`
+
+	// AccountKeySecretRef references a secret in the 'hive' namespace holding the private key of the ACME account
+	// in a 'tls.key' key. The secret is created with a new key if it does not exist.
+	AccountKeySecretRef corev1.LocalObjectReference `json:"accountKeySecretRef"`
+
+	// CASecretRef references a secret in the 'hive' namespace holding, in a 'ca.crt' key, the certificate authority
+	// of the ACME server, when the ACME server is not trusted by the system certificate authorities.
+	// +optional
+	CASecretRef *corev1.LocalObjectReference `json:"caSecretRef,omitempty"`
+
+	// RenewBefore is how long before their expiry the generated certificates are renewed.
+	// It is capped at half the lifetime of the certificates. The default is 30 days.
+	// +optional
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
 }
 
 // HiveConfigStatus defines the observed state of Hive
@@ -74,6 +232,61 @@ type HiveConfigStatus struct {
 	ConfigApplied bool `json:"configApplied,omitempty"`
 }
 
+// ControllersConfig contains the settings of the hive controllers.
+type ControllersConfig struct {
+	// Default specifies the settings used by the controllers for settings not specified for the controller in
+	// Controllers.
+	// +optional
+	Default *ControllerConfig `json:"default,omitempty"`
+
+	// Controllers specifies the settings of individual controllers.
+	// +optional
+	Controllers []SpecificControllerConfig `json:"controllers,omitempty"`
+}
+
+// SpecificControllerConfig contains the settings of a controller.
+type SpecificControllerConfig struct {
+	// Name is the name of the controller, for example syncsetinstance or dnsendpoint. Names are matched ignoring
+	// case.
+	Name string `json:"name"`
+
+	// Config is the settings of the controller.
+	Config ControllerConfig `json:"config"`
+}
+
+// ControllerConfig contains the concurrency and rate limit settings of a controller.
+type ControllerConfig struct {
+	// ConcurrentReconciles is the maximum number of reconciles run by the controller at the same time.
+	// The default is 5.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ConcurrentReconciles *int32 `json:"concurrentReconciles,omitempty"`
+
+	// ClientQPS is the number of queries per second the controller's client may make to the API server of the hub
+	// cluster. The default is the client-go default of 5.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ClientQPS *int32 `json:"clientQPS,omitempty"`
+
+	// ClientBurst is the number of queries the controller's client may make to the API server of the hub cluster in
+	// a burst above ClientQPS. The default is the client-go default of 10.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ClientBurst *int32 `json:"clientBurst,omitempty"`
+
+	// QueueQPS is the number of requeued items per second the work queue of the controller releases for
+	// reconciling. The default is 10.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	QueueQPS *int32 `json:"queueQPS,omitempty"`
+
+	// QueueBurst is the number of requeued items the work queue of the controller may release in a burst above
+	// QueueQPS. The default is 100.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	QueueBurst *int32 `json:"queueBurst,omitempty"`
+}
+
 // BackupConfig contains settings for the Velero backup integration.
 type BackupConfig struct {
 	// Velero specifies configuration for the Velero backup integration.
@@ -119,6 +332,14 @@ type ManageDNSConfig struct {
 	// +optional
 	GCP *ManageDNSGCPConfig `json:"gcp,omitempty"`
 
+	// Azure contains Azure-specific settings for external DNS
+	// +optional
+	Azure *ManageDNSAzureConfig `json:"azure,omitempty"`
+
+	// RFC2136 contains the settings for managing DNS on a DNS server through RFC2136 dynamic updates
+	// +optional
+	RFC2136 *ManageDNSRFC2136Config `json:"rfc2136,omitempty"`
+
 	// As other cloud providers are supported, additional fields will be
 	// added for each of those cloud providers. Only a single cloud provider
 	// may be configured at a time.
@@ -152,6 +373,34 @@ type ManageDNSGCPConfig struct {
 	CredentialsSecretRef corev1.LocalObjectReference `json:"credentialsSecretRef,omitempty"`
 }
 
+// ManageDNSAzureConfig contains Azure-specific info to manage a given domain.
+type ManageDNSAzureConfig struct {
+	// CredentialsSecretRef references a secret that will be used to authenticate with
+	// Azure DNS. It will need permission to manage entries in each of the
+	// managed domains listed in the parent ManageDNSConfig object.
+	// Secret should have a key named 'osServicePrincipal.json'.
+	// +optional
+	CredentialsSecretRef corev1.LocalObjectReference `json:"credentialsSecretRef,omitempty"`
+
+	// ResourceGroupName specifies the Azure resource group containing the DNS zones
+	// for the managed domains.
+	ResourceGroupName string `json:"resourceGroupName"`
+}
+
+// ManageDNSRFC2136Config contains the info to manage a given domain on a DNS server through RFC2136 dynamic updates.
+type ManageDNSRFC2136Config struct {
+	// Server is the address of the DNS server hosting the zones of the managed domains, with an optional port
+	// which defaults to 53.
+	Server string `json:"server"`
+
+	// TSIGKeySecretRef references a secret containing the TSIG key used to authenticate the dynamic updates and
+	// zone transfers. It will need permission to manage NS records in each of the managed domains
+	// listed in the parent ManageDNSConfig object.
+	// Secret should have keys named 'keyName' and 'secret', holding the name and the base64-encoded secret of the
+	// key, and may have a key named 'algorithm', which defaults to hmac-sha256.
+	TSIGKeySecretRef corev1.LocalObjectReference `json:"tsigKeySecretRef"`
+}
+
 // +genclient:nonNamespaced
 // +genclient
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
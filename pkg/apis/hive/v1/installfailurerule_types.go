@@ -0,0 +1,69 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// InstallFailureRuleSpec defines the desired state of InstallFailureRule
+type InstallFailureRuleSpec struct {
+	// Priority determines the order in which the rules are matched against an install log. Rules with a higher
+	// priority are matched first. Rules with the same priority are matched in order of their names. Matching stops at
+	// the first rule that matches.
+	// +optional
+	Priority int32 `json:"priority,omitempty"`
+
+	// Platforms limits the rule to installs on the given platforms. Valid values are aws, azure, gcp, openstack,
+	// vsphere and baremetal. The rule applies to installs on all platforms when empty.
+	// +optional
+	Platforms []string `json:"platforms,omitempty"`
+
+	// SearchRegexStrings are the regular expressions searched for in the install log. The rule matches if any one
+	// of the regular expressions matches.
+	SearchRegexStrings []string `json:"searchRegexStrings"`
+
+	// InstallFailingReason is the reason reported for an install failure matching the rule.
+	InstallFailingReason string `json:"installFailingReason"`
+
+	// InstallFailingMessage is the message reported for an install failure matching the rule.
+	InstallFailingMessage string `json:"installFailingMessage"`
+
+	// Retryable indicates whether a new install attempt may succeed after an install failure matching the rule.
+	// Defaults to true.
+	// +optional
+	Retryable *bool `json:"retryable,omitempty"`
+}
+
+// InstallFailureRuleStatus defines the observed state of InstallFailureRule
+type InstallFailureRuleStatus struct{}
+
+// +genclient:nonNamespaced
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// InstallFailureRule is the Schema for the installfailurerules API. It classifies install failures by matching
+// regular expressions against the install log.
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Reason",type="string",JSONPath=".spec.installFailingReason"
+// +kubebuilder:printcolumn:name="Priority",type="integer",JSONPath=".spec.priority"
+// +kubebuilder:resource:path=installfailurerules,shortName=ifr
+type InstallFailureRule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   InstallFailureRuleSpec   `json:"spec,omitempty"`
+	Status InstallFailureRuleStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// InstallFailureRuleList contains a list of InstallFailureRule
+type InstallFailureRuleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []InstallFailureRule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&InstallFailureRule{}, &InstallFailureRuleList{})
+}
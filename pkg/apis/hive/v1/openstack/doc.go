@@ -0,0 +1,4 @@
+// Package openstack contains API Schema definitions for OpenStack clusters.
+// +k8s:deepcopy-gen=package,register
+// +k8s:conversion-gen=github.com/openshift/hive/pkg/apis/hive
+package openstack
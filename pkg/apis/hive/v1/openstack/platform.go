@@ -0,0 +1,21 @@
+package openstack
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Platform stores all the global OpenStack configuration
+type Platform struct {
+	// CredentialsSecretRef refers to a secret that contains the OpenStack account access
+	// credentials. The credentials are expected in a clouds.yaml file stored under the
+	// "clouds.yaml" key of the secret.
+	CredentialsSecretRef corev1.LocalObjectReference `json:"credentialsSecretRef"`
+
+	// Cloud will be used to indicate the OS_CLOUD value to use the right section
+	// from the clouds.yaml in the CredentialsSecretRef.
+	Cloud string `json:"cloud"`
+
+	// TrunkSupport indicates whether or not to use trunk ports in your OpenShift cluster.
+	// +optional
+	TrunkSupport bool `json:"trunkSupport,omitempty"`
+}
@@ -19,6 +19,45 @@ const (
 	// of Upsert but also indicates that objects will be deleted if created
 	// previously and detected missing from defined Resources in the SyncSet.
 	SyncResourceApplyMode SyncSetResourceApplyMode = "Sync"
+
+	// ServerSideApplyResourceApplyMode inherits the create or update functionality
+	// of Upsert but applies objects with server-side apply, using the "hive" field
+	// manager. Conflicts with fields owned by other field managers are reported in
+	// the ApplyConflict condition of the resource and are not overwritten.
+	ServerSideApplyResourceApplyMode SyncSetResourceApplyMode = "ServerSideApply"
+)
+
+// SyncSetDriftCheckMode is a string representing the mode with which to check
+// SyncSet Resources for drift on the target cluster.
+type SyncSetDriftCheckMode string
+
+const (
+	// DisabledDriftCheckMode indicates that resources are not checked for drift.
+	DisabledDriftCheckMode SyncSetDriftCheckMode = "Disabled"
+
+	// ReportDriftCheckMode indicates that resources are checked for drift and
+	// drifted resources are reported in the Drifted condition of the resource.
+	// Drifted resources are still re-applied on the reapply interval.
+	ReportDriftCheckMode SyncSetDriftCheckMode = "Report"
+
+	// AuditDriftCheckMode inherits the reporting of Report but drifted resources
+	// are not re-applied until they are changed in the SyncSet, allowing the
+	// changes made on the target cluster to be audited before they are overwritten.
+	AuditDriftCheckMode SyncSetDriftCheckMode = "Audit"
+)
+
+// SyncSetResourceTemplateMode is a string representing the mode with which to
+// render SyncSet Resources.
+type SyncSetResourceTemplateMode string
+
+const (
+	// NoneResourceTemplateMode indicates that resources are applied as they are.
+	NoneResourceTemplateMode SyncSetResourceTemplateMode = "None"
+
+	// GoTemplateResourceTemplateMode indicates that the string values of resources
+	// are Go text/template templates, rendered for each cluster before the
+	// resources are applied.
+	GoTemplateResourceTemplateMode SyncSetResourceTemplateMode = "GoTemplate"
 )
 
 // SyncSetPatchApplyMode is a string representing the mode with which to apply
@@ -98,6 +137,19 @@ const (
 	// UnknownObjectSyncCondition indicates that the resource type cannot be determined.
 	// It should include a reason and message for the failure.
 	UnknownObjectSyncCondition SyncConditionType = "UnknownObject"
+
+	// ApplyConflictSyncCondition indicates that a resource applied with server-side apply has fields
+	// owned by other field managers. It should include a message listing the conflicts.
+	ApplyConflictSyncCondition SyncConditionType = "ApplyConflict"
+
+	// DriftedSyncCondition indicates that fields of a resource have been changed on the target
+	// cluster since the resource was applied. It should include a message listing the drifted fields.
+	DriftedSyncCondition SyncConditionType = "Drifted"
+
+	// TemplateRenderFailedSyncCondition indicates that the templates of the resources of a SyncSet with
+	// the GoTemplate resource template mode cannot be rendered for the cluster. It should include a
+	// message describing the failure.
+	TemplateRenderFailedSyncCondition SyncConditionType = "TemplateRenderFailed"
 )
 
 // SyncCondition is a condition in a SyncStatus
@@ -130,9 +182,10 @@ type SyncSetObjectStatus struct {
 	// +optional
 	Resources []SyncStatus `json:"resources,omitempty"`
 
-	// ResourceApplyMode indicates if the Resource apply mode is "upsert" (default) or "sync".
+	// ResourceApplyMode indicates if the Resource apply mode is "upsert" (default), "sync" or "serverSideApply".
 	// ApplyMode "upsert" indicates create and update.
 	// ApplyMode "sync" indicates create, update and delete.
+	// ApplyMode "serverSideApply" indicates create and update with server-side apply.
 	// +optional
 	ResourceApplyMode SyncSetResourceApplyMode `json:"resourceApplyMode,omitempty"`
 
@@ -185,12 +238,33 @@ type SyncSetCommonSpec struct {
 	// +optional
 	Resources []runtime.RawExtension `json:"resources,omitempty"`
 
-	// ResourceApplyMode indicates if the Resource apply mode is "upsert" (default) or "sync".
+	// ResourceApplyMode indicates if the Resource apply mode is "upsert" (default), "sync" or "serverSideApply".
 	// ApplyMode "upsert" indicates create and update.
 	// ApplyMode "sync" indicates create, update and delete.
+	// ApplyMode "serverSideApply" indicates create and update with server-side apply.
 	// +optional
 	ResourceApplyMode SyncSetResourceApplyMode `json:"resourceApplyMode,omitempty"`
 
+	// DriftCheckMode indicates if the Resource drift check mode is "disabled" (default), "report" or "audit".
+	// DriftCheckMode "disabled" indicates that resources are not checked for drift.
+	// DriftCheckMode "report" indicates that fields of the live resources are compared against the fields of the
+	// Resources and drifted resources are reported.
+	// DriftCheckMode "audit" indicates that drifted resources are reported and are not re-applied.
+	// +optional
+	DriftCheckMode SyncSetDriftCheckMode `json:"driftCheckMode,omitempty"`
+
+	// ResourceTemplateMode indicates if the Resource template mode is "none" (default) or "goTemplate".
+	// TemplateMode "none" indicates that resources are applied as they are.
+	// TemplateMode "goTemplate" indicates that the string values of resources are rendered as Go text/template
+	// templates with the fields of the ClusterDeployment and the TemplateParameters.
+	// +optional
+	ResourceTemplateMode SyncSetResourceTemplateMode `json:"resourceTemplateMode,omitempty"`
+
+	// TemplateParametersRef is a reference to a ConfigMap in the namespace of the ClusterDeployment. The data of
+	// the ConfigMap is available to the templates of the Resources as .Parameters.
+	// +optional
+	TemplateParametersRef *corev1.LocalObjectReference `json:"templateParametersRef,omitempty"`
+
 	// Patches is the list of patches to apply.
 	// +optional
 	Patches []SyncObjectPatch `json:"patches,omitempty"`
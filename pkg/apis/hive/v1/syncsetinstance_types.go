@@ -46,9 +46,10 @@ type SyncSetInstanceSpec struct {
 	// +optional
 	SelectorSyncSetRef *SelectorSyncSetReference `json:"selectorSyncSetRef,omitempty"`
 
-	// ResourceApplyMode indicates if the resource apply mode is "upsert" (default) or "sync".
+	// ResourceApplyMode indicates if the resource apply mode is "upsert" (default), "sync" or "serverSideApply".
 	// ApplyMode "upsert" indicates create and update.
 	// ApplyMode "sync" indicates create, update and delete.
+	// ApplyMode "serverSideApply" indicates create and update with server-side apply.
 	// +optional
 	ResourceApplyMode SyncSetResourceApplyMode `json:"resourceApplyMode,omitempty"`
 
@@ -0,0 +1,186 @@
+package validatingwebhooks
+
+import (
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/apiserver/pkg/authentication/serviceaccount"
+	"k8s.io/client-go/rest"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+)
+
+const (
+	clusterClaimGroup    = "hive.openshift.io"
+	clusterClaimVersion  = "v1"
+	clusterClaimResource = "clusterclaims"
+)
+
+// hiveControllersUser is the user of the service account the hive controllers run as. Only the hive controllers may
+// assign a cluster to a claim.
+var hiveControllersUser = serviceaccount.MakeUsername(constants.HiveNamespace, "default")
+
+// ClusterClaimValidatingAdmissionHook is a struct that is used to reference what code should be run by the generic-admission-server.
+type ClusterClaimValidatingAdmissionHook struct {
+	decoder runtime.Decoder
+}
+
+// ValidatingResource is called by generic-admission-server on startup to register the returned REST resource through which the
+// webhook is accessed by the kube apiserver.
+// For example, generic-admission-server uses the data below to register the webhook on the REST resource "/apis/admission.hive.openshift.io/v1/clusterclaimvalidators".
+// When the kube apiserver calls this registered REST resource, the generic-admission-server calls the Validate() method below.
+func (a *ClusterClaimValidatingAdmissionHook) ValidatingResource() (plural schema.GroupVersionResource, singular string) {
+	log.WithFields(log.Fields{
+		"group":    "admission.hive.openshift.io",
+		"version":  "v1",
+		"resource": "clusterclaimvalidator",
+	}).Info("Registering validation REST resource")
+	// NOTE: This GVR is meant to be different than the ClusterClaim CRD GVR which has group "hive.openshift.io".
+	return schema.GroupVersionResource{
+			Group:    "admission.hive.openshift.io",
+			Version:  "v1",
+			Resource: "clusterclaimvalidators",
+		},
+		"clusterclaimvalidator"
+}
+
+// Initialize is called by generic-admission-server on startup to setup any special initialization that your webhook needs.
+func (a *ClusterClaimValidatingAdmissionHook) Initialize(kubeClientConfig *rest.Config, stopCh <-chan struct{}) error {
+	log.WithFields(log.Fields{
+		"group":    "admission.hive.openshift.io",
+		"version":  "v1",
+		"resource": "clusterclaimvalidator",
+	}).Info("Initializing validation REST resource")
+
+	scheme := runtime.NewScheme()
+	hivev1.AddToScheme(scheme)
+	a.decoder = serializer.NewCodecFactory(scheme).UniversalDecoder(hivev1.SchemeGroupVersion)
+
+	return nil
+}
+
+// Validate is called by generic-admission-server when the registered REST resource above is called with an admission request.
+// Usually it's the kube apiserver that is making the admission validation request.
+func (a *ClusterClaimValidatingAdmissionHook) Validate(request *admissionv1beta1.AdmissionRequest) *admissionv1beta1.AdmissionResponse {
+	logger := log.WithFields(log.Fields{
+		"operation": request.Operation,
+		"group":     request.Resource.Group,
+		"version":   request.Resource.Version,
+		"resource":  request.Resource.Resource,
+		"method":    "Validate",
+	})
+
+	if !a.shouldValidate(request, logger) {
+		logger.Info("Skipping validation for request")
+		// The request object isn't something that this validator should validate.
+		// Therefore, we say that it's allowed.
+		return &admissionv1beta1.AdmissionResponse{
+			Allowed: true,
+		}
+	}
+
+	logger.Info("Validating request")
+
+	switch request.Operation {
+	case admissionv1beta1.Create, admissionv1beta1.Update:
+		return a.validateRequest(request, logger)
+	default:
+		logger.Info("Successful validation")
+		return &admissionv1beta1.AdmissionResponse{
+			Allowed: true,
+		}
+	}
+}
+
+// shouldValidate explicitly checks if the request should validated. For example, this webhook may have accidentally been registered to check
+// the validity of some other type of object with a different GVR.
+func (a *ClusterClaimValidatingAdmissionHook) shouldValidate(request *admissionv1beta1.AdmissionRequest, logger log.FieldLogger) bool {
+	logger = logger.WithField("method", "shouldValidate")
+
+	if request.Resource.Group != clusterClaimGroup {
+		logger.Debug("Returning False, not our group")
+		return false
+	}
+
+	if request.Resource.Version != clusterClaimVersion {
+		logger.Debug("Returning False, it's our group, but not the right version")
+		return false
+	}
+
+	if request.Resource.Resource != clusterClaimResource {
+		logger.Debug("Returning False, it's our group and version, but not the right resource")
+		return false
+	}
+
+	// If we get here, then we're supposed to validate the object.
+	logger.Debug("Returning True, passed all prerequisites.")
+	return true
+}
+
+// validateRequest validates create and update operations for ClusterClaim objects. The namespace of the claimed
+// cluster is owned by the hive controllers: it can only be set by them, and only once.
+func (a *ClusterClaimValidatingAdmissionHook) validateRequest(request *admissionv1beta1.AdmissionRequest, logger log.FieldLogger) *admissionv1beta1.AdmissionResponse {
+	logger = logger.WithField("method", "validateRequest")
+
+	newObject := &hivev1.ClusterClaim{}
+	if _, _, err := a.decoder.Decode(request.Object.Raw, nil, newObject); err != nil {
+		logger.WithError(err).Error("failed to decode")
+		return &admissionv1beta1.AdmissionResponse{
+			Allowed: false,
+			Result: &metav1.Status{
+				Status: metav1.StatusFailure, Code: http.StatusBadRequest, Reason: metav1.StatusReasonBadRequest,
+				Message: err.Error(),
+			},
+		}
+	}
+
+	logger = logger.WithField("object.Name", newObject.Name)
+
+	oldObject := &hivev1.ClusterClaim{}
+	if request.Operation == admissionv1beta1.Update {
+		if _, _, err := a.decoder.Decode(request.OldObject.Raw, nil, oldObject); err != nil {
+			logger.WithError(err).Error("failed to decode old object")
+			return &admissionv1beta1.AdmissionResponse{
+				Allowed: false,
+				Result: &metav1.Status{
+					Status: metav1.StatusFailure, Code: http.StatusBadRequest, Reason: metav1.StatusReasonBadRequest,
+					Message: err.Error(),
+				},
+			}
+		}
+	}
+
+	allErrs := field.ErrorList{}
+	namespacePath := field.NewPath("spec", "namespace")
+	switch newNamespace, oldNamespace := newObject.Spec.Namespace, oldObject.Spec.Namespace; {
+	case newNamespace == oldNamespace:
+	case oldNamespace != "":
+		allErrs = append(allErrs, field.Invalid(namespacePath, newNamespace, "field is immutable once set"))
+	case request.UserInfo.Username != hiveControllersUser:
+		allErrs = append(allErrs, field.Forbidden(namespacePath, "field can only be set by hive"))
+	}
+
+	if len(allErrs) > 0 {
+		logger.WithError(allErrs.ToAggregate()).Info("failed validation")
+		status := errors.NewInvalid(schemaGVK(request.Kind).GroupKind(), request.Name, allErrs).Status()
+		return &admissionv1beta1.AdmissionResponse{
+			Allowed: false,
+			Result:  &status,
+		}
+	}
+
+	// If we get here, then all checks passed, so the object is valid.
+	logger.Info("Successful validation")
+	return &admissionv1beta1.AdmissionResponse{
+		Allowed: true,
+	}
+}
@@ -0,0 +1,189 @@
+package validatingwebhooks
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+)
+
+func Test_ClusterClaimAdmission_Validate_Kind(t *testing.T) {
+	cases := []struct {
+		name         string
+		group        string
+		version      string
+		resource     string
+		expectToSkip bool
+	}{
+		{
+			name:     "clusterclaim",
+			group:    clusterClaimGroup,
+			version:  clusterClaimVersion,
+			resource: clusterClaimResource,
+		},
+		{
+			name:         "different group",
+			group:        "other group",
+			version:      clusterClaimVersion,
+			resource:     clusterClaimResource,
+			expectToSkip: true,
+		},
+		{
+			name:         "different version",
+			group:        clusterClaimGroup,
+			version:      "other version",
+			resource:     clusterClaimResource,
+			expectToSkip: true,
+		},
+		{
+			name:         "different resource",
+			group:        clusterClaimGroup,
+			version:      clusterClaimVersion,
+			resource:     "other resource",
+			expectToSkip: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cut := &ClusterClaimValidatingAdmissionHook{}
+			cut.Initialize(nil, nil)
+			request := &admissionv1beta1.AdmissionRequest{
+				Resource: metav1.GroupVersionResource{
+					Group:    tc.group,
+					Version:  tc.version,
+					Resource: tc.resource,
+				},
+				Operation: admissionv1beta1.Create,
+				// An object that cannot be decoded is only allowed if the request is skipped.
+				Object: runtime.RawExtension{Raw: []byte{0}},
+			}
+			response := cut.Validate(request)
+			assert.Equal(t, tc.expectToSkip, response.Allowed)
+		})
+	}
+}
+
+func Test_ClusterClaimAdmission_Validate(t *testing.T) {
+	cases := []struct {
+		name          string
+		oldNamespace  string
+		newNamespace  string
+		operation     admissionv1beta1.Operation
+		username      string
+		expectAllowed bool
+	}{
+		{
+			name:          "create",
+			expectAllowed: true,
+		},
+		{
+			name:         "create with namespace",
+			newNamespace: "cluster-namespace",
+		},
+		{
+			name:          "create with namespace by hive",
+			newNamespace:  "cluster-namespace",
+			username:      hiveControllersUser,
+			expectAllowed: true,
+		},
+		{
+			name:          "update",
+			operation:     admissionv1beta1.Update,
+			expectAllowed: true,
+		},
+		{
+			name:         "set namespace",
+			newNamespace: "cluster-namespace",
+			operation:    admissionv1beta1.Update,
+		},
+		{
+			name:          "set namespace by hive",
+			newNamespace:  "cluster-namespace",
+			operation:     admissionv1beta1.Update,
+			username:      hiveControllersUser,
+			expectAllowed: true,
+		},
+		{
+			name:          "keep namespace",
+			oldNamespace:  "cluster-namespace",
+			newNamespace:  "cluster-namespace",
+			operation:     admissionv1beta1.Update,
+			expectAllowed: true,
+		},
+		{
+			name:         "change namespace",
+			oldNamespace: "cluster-namespace",
+			newNamespace: "other-namespace",
+			operation:    admissionv1beta1.Update,
+		},
+		{
+			name:         "change namespace by hive",
+			oldNamespace: "cluster-namespace",
+			newNamespace: "other-namespace",
+			operation:    admissionv1beta1.Update,
+			username:     hiveControllersUser,
+		},
+		{
+			name:         "clear namespace",
+			oldNamespace: "cluster-namespace",
+			operation:    admissionv1beta1.Update,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cut := &ClusterClaimValidatingAdmissionHook{}
+			cut.Initialize(nil, nil)
+			operation := tc.operation
+			if operation == "" {
+				operation = admissionv1beta1.Create
+			}
+			username := tc.username
+			if username == "" {
+				username = "test-user"
+			}
+			rawClaim, err := json.Marshal(testClusterClaim(tc.newNamespace))
+			if !assert.NoError(t, err, "unexpected error marshalling claim") {
+				return
+			}
+			request := &admissionv1beta1.AdmissionRequest{
+				Resource: metav1.GroupVersionResource{
+					Group:    clusterClaimGroup,
+					Version:  clusterClaimVersion,
+					Resource: clusterClaimResource,
+				},
+				Operation: operation,
+				UserInfo:  authenticationv1.UserInfo{Username: username},
+				Object:    runtime.RawExtension{Raw: rawClaim},
+			}
+			if operation == admissionv1beta1.Update {
+				rawOldClaim, err := json.Marshal(testClusterClaim(tc.oldNamespace))
+				if !assert.NoError(t, err, "unexpected error marshalling old claim") {
+					return
+				}
+				request.OldObject = runtime.RawExtension{Raw: rawOldClaim}
+			}
+			response := cut.Validate(request)
+			assert.Equal(t, tc.expectAllowed, response.Allowed, "unexpected response: %#v", response.Result)
+		})
+	}
+}
+
+func testClusterClaim(namespace string) *hivev1.ClusterClaim {
+	return &hivev1.ClusterClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "claim-namespace",
+			Name:      "test-claim",
+		},
+		Spec: hivev1.ClusterClaimSpec{
+			ClusterPoolName: "test-pool",
+			Namespace:       namespace,
+		},
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"reflect"
 	"regexp"
 	"strings"
@@ -22,7 +23,9 @@ import (
 
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
 
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
 	"github.com/openshift/hive/pkg/manageddns"
+	"github.com/openshift/hive/pkg/releaseimage"
 )
 
 const (
@@ -35,12 +38,16 @@ const (
 )
 
 var (
-	mutableFields = []string{"CertificateBundles", "ClusterMetadata", "ControlPlaneConfig", "Ingress", "Installed", "PreserveOnDelete"}
+	mutableFields = []string{"CertificateBundles", "ClusterMetadata", "ClusterPoolRef", "ControlPlaneConfig", "Ingress", "Installed", "KubeadminPassword", "MaintenanceWindows", "PowerState", "PreserveOnDelete", "Upgrade"}
 )
 
 // ClusterDeploymentValidatingAdmissionHook is a struct that is used to reference what code should be run by the generic-admission-server.
 type ClusterDeploymentValidatingAdmissionHook struct {
 	validManagedDomains []string
+	// rfc2136ManagedDomains are the managed domains hosted on DNS servers updated through RFC2136 dynamic updates,
+	// for which DNS can be managed whatever the platform of the cluster.
+	rfc2136ManagedDomains []string
+	releaseImagePolicy    *hivev1.ReleaseImagePolicy
 }
 
 // NewClusterDeploymentValidatingAdmissionHook constructs a new ClusterDeploymentValidatingAdmissionHook
@@ -51,12 +58,22 @@ func NewClusterDeploymentValidatingAdmissionHook() *ClusterDeploymentValidatingA
 		logger.WithError(err).Fatal("Unable to read managedDomains file")
 	}
 	domains := []string{}
+	rfc2136Domains := []string{}
 	for _, md := range managedDomains {
 		domains = append(domains, md.Domains...)
+		if md.RFC2136 != nil {
+			rfc2136Domains = append(rfc2136Domains, md.Domains...)
+		}
 	}
 	logger.WithField("managedDomains", domains).Info("Read managed domains")
+	releaseImagePolicy, err := releaseimage.PolicyFromEnvironment()
+	if err != nil {
+		logger.WithError(err).Fatal("Unable to read release image policy")
+	}
 	return &ClusterDeploymentValidatingAdmissionHook{
-		validManagedDomains: domains,
+		validManagedDomains:   domains,
+		rfc2136ManagedDomains: rfc2136Domains,
+		releaseImagePolicy:    releaseImagePolicy,
 	}
 }
 
@@ -262,6 +279,7 @@ func (a *ClusterDeploymentValidatingAdmissionHook) validateCreate(admissionSpec
 	}
 	if newObject.Spec.Platform.Azure != nil {
 		numberOfPlatforms++
+		canManageDNS = true
 		azure := newObject.Spec.Platform.Azure
 		azurePath := platformPath.Child("azure")
 		if azure.CredentialsSecretRef.Name == "" {
@@ -286,6 +304,40 @@ func (a *ClusterDeploymentValidatingAdmissionHook) validateCreate(admissionSpec
 			allErrs = append(allErrs, field.Required(gcpPath.Child("region"), "must specify GCP region"))
 		}
 	}
+	if newObject.Spec.Platform.OpenStack != nil {
+		numberOfPlatforms++
+		openstack := newObject.Spec.Platform.OpenStack
+		openstackPath := platformPath.Child("openstack")
+		if openstack.CredentialsSecretRef.Name == "" {
+			allErrs = append(allErrs, field.Required(openstackPath.Child("credentialsSecretRef", "name"), "must specify secrets for OpenStack access"))
+		}
+		if openstack.Cloud == "" {
+			allErrs = append(allErrs, field.Required(openstackPath.Child("cloud"), "must specify cloud section of credentials secret to use"))
+		}
+	}
+	if newObject.Spec.Platform.VSphere != nil {
+		numberOfPlatforms++
+		vsphere := newObject.Spec.Platform.VSphere
+		vspherePath := platformPath.Child("vsphere")
+		if vsphere.CredentialsSecretRef.Name == "" {
+			allErrs = append(allErrs, field.Required(vspherePath.Child("credentialsSecretRef", "name"), "must specify secrets for vSphere access"))
+		}
+		if vsphere.CertificatesSecretRef.Name == "" {
+			allErrs = append(allErrs, field.Required(vspherePath.Child("certificatesSecretRef", "name"), "must specify certificates for vSphere access"))
+		}
+		if vsphere.VCenter == "" {
+			allErrs = append(allErrs, field.Required(vspherePath.Child("vCenter"), "must specify vSphere vCenter"))
+		}
+		if vsphere.Datacenter == "" {
+			allErrs = append(allErrs, field.Required(vspherePath.Child("datacenter"), "must specify vSphere datacenter"))
+		}
+		if vsphere.DefaultDatastore == "" {
+			allErrs = append(allErrs, field.Required(vspherePath.Child("defaultDatastore"), "must specify vSphere defaultDatastore"))
+		}
+		if vsphere.Network == "" {
+			allErrs = append(allErrs, field.Required(vspherePath.Child("network"), "must specify vSphere network"))
+		}
+	}
 	if newObject.Spec.Platform.BareMetal != nil {
 		numberOfPlatforms++
 	}
@@ -295,6 +347,20 @@ func (a *ClusterDeploymentValidatingAdmissionHook) validateCreate(admissionSpec
 	case numberOfPlatforms > 1:
 		allErrs = append(allErrs, field.Invalid(platformPath, newObject.Spec.Platform, "must specify only a single platform"))
 	}
+	// DNS is managed on the DNS server of an RFC2136 managed domain whatever the platform of the cluster, with a TSIG
+	// key of the cluster rather than the key of the managed domain.
+	if validateDomain(newObject.Spec.BaseDomain, a.rfc2136ManagedDomains) {
+		canManageDNS = true
+		if newObject.Spec.ManageDNS {
+			tsigKeyPath := specPath.Child("rfc2136TSIGKeySecretRef")
+			switch tsigKeySecretRef := newObject.Spec.RFC2136TSIGKeySecretRef; {
+			case tsigKeySecretRef == nil:
+				allErrs = append(allErrs, field.Required(tsigKeyPath, "must specify a TSIG key for the zone of the cluster to manage DNS in an RFC2136 managed domain"))
+			case tsigKeySecretRef.Name == "":
+				allErrs = append(allErrs, field.Required(tsigKeyPath.Child("name"), "must specify a name for the TSIG key secret"))
+			}
+		}
+	}
 	if !canManageDNS && newObject.Spec.ManageDNS {
 		allErrs = append(allErrs, field.Invalid(specPath.Child("manageDNS"), newObject.Spec.ManageDNS, "cannot manage DNS for the selected platform"))
 	}
@@ -303,8 +369,18 @@ func (a *ClusterDeploymentValidatingAdmissionHook) validateCreate(admissionSpec
 		if newObject.Spec.Provisioning.SSHPrivateKeySecretRef != nil && newObject.Spec.Provisioning.SSHPrivateKeySecretRef.Name == "" {
 			allErrs = append(allErrs, field.Required(specPath.Child("provisioning", "sshPrivateKeySecretRef", "name"), "must specify a name for the ssh private key secret if the ssh private key secret is specified"))
 		}
+		if releaseImage := newObject.Spec.Provisioning.ReleaseImage; releaseImage != "" {
+			if err := releaseimage.CheckPolicy(a.releaseImagePolicy, releaseImage); err != nil {
+				allErrs = append(allErrs, field.Forbidden(specPath.Child("provisioning", "releaseImage"), err.Error()))
+			}
+		}
 	}
 
+	allErrs = append(allErrs, validatePowerState(newObject.Spec.PowerState, specPath.Child("powerState"))...)
+	allErrs = append(allErrs, validateControlPlaneProxy(newObject.Spec.ControlPlaneConfig.Proxy, specPath.Child("controlPlaneConfig", "proxy"))...)
+	allErrs = append(allErrs, validateClusterUpgrade(newObject.Spec.Upgrade, a.releaseImagePolicy, specPath.Child("upgrade"))...)
+	allErrs = append(allErrs, validateMaintenanceWindows(newObject.Spec.MaintenanceWindows, specPath.Child("maintenanceWindows"))...)
+
 	if len(allErrs) > 0 {
 		status := errors.NewInvalid(schemaGVK(admissionSpec.Kind).GroupKind(), admissionSpec.Name, allErrs).Status()
 		return &admissionv1beta1.AdmissionResponse{
@@ -413,6 +489,12 @@ func (a *ClusterDeploymentValidatingAdmissionHook) validateUpdate(admissionSpec
 		}
 	}
 
+	allErrs = append(allErrs, validateClusterPoolRefUpdate(oldObject.Spec.ClusterPoolRef, newObject.Spec.ClusterPoolRef, specPath.Child("clusterPoolRef"))...)
+	allErrs = append(allErrs, validatePowerState(newObject.Spec.PowerState, specPath.Child("powerState"))...)
+	allErrs = append(allErrs, validateControlPlaneProxy(newObject.Spec.ControlPlaneConfig.Proxy, specPath.Child("controlPlaneConfig", "proxy"))...)
+	allErrs = append(allErrs, validateClusterUpgrade(newObject.Spec.Upgrade, a.releaseImagePolicy, specPath.Child("upgrade"))...)
+	allErrs = append(allErrs, validateMaintenanceWindows(newObject.Spec.MaintenanceWindows, specPath.Child("maintenanceWindows"))...)
+
 	if len(allErrs) > 0 {
 		contextLogger.WithError(allErrs.ToAggregate()).Info("failed validation")
 		status := errors.NewInvalid(schemaGVK(admissionSpec.Kind).GroupKind(), admissionSpec.Name, allErrs).Status()
@@ -470,6 +552,126 @@ func hasClearedOutPreviouslyDefinedIngressList(oldObject, newObject *hivev1.Clus
 	return false
 }
 
+// validateClusterPoolRefUpdate validates an update of the reference to the ClusterPool that the cluster originated
+// from. The reference is immutable, except that the claim may be set once when the cluster is assigned to a
+// ClusterClaim.
+func validateClusterPoolRefUpdate(oldRef, newRef *hivev1.ClusterPoolReference, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if oldRef == nil || newRef == nil {
+		return append(allErrs, apivalidation.ValidateImmutableField(newRef, oldRef, fldPath)...)
+	}
+	allErrs = append(allErrs, apivalidation.ValidateImmutableField(newRef.Namespace, oldRef.Namespace, fldPath.Child("namespace"))...)
+	allErrs = append(allErrs, apivalidation.ValidateImmutableField(newRef.PoolName, oldRef.PoolName, fldPath.Child("poolName"))...)
+	if oldRef.ClaimName != "" {
+		allErrs = append(allErrs, apivalidation.ValidateImmutableField(newRef.ClaimName, oldRef.ClaimName, fldPath.Child("claimName"))...)
+	}
+	return allErrs
+}
+
+// validatePowerState validates the power state that the cluster is asked to be in.
+func validatePowerState(powerState hivev1.ClusterPowerState, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	switch powerState {
+	case "", hivev1.RunningClusterPowerState, hivev1.HibernatingClusterPowerState:
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath, powerState, []string{string(hivev1.RunningClusterPowerState), string(hivev1.HibernatingClusterPowerState)}))
+	}
+	return allErrs
+}
+
+// validateControlPlaneProxy validates the proxy through which Hive connects to the API server of the cluster.
+func validateControlPlaneProxy(proxy *hivev1.ControlPlaneProxy, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if proxy == nil {
+		return allErrs
+	}
+	switch {
+	case proxy.URL == "" && proxy.SSHBastion == nil:
+		allErrs = append(allErrs, field.Required(fldPath, "must specify either the URL of the proxy or the SSH bastion"))
+	case proxy.URL != "" && proxy.SSHBastion != nil:
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("sshBastion"), "must not specify the SSH bastion along with the URL of the proxy"))
+	case proxy.SSHBastion != nil:
+		allErrs = append(allErrs, validateSSHBastion(proxy.SSHBastion, fldPath.Child("sshBastion"))...)
+		if proxy.CredentialsSecretRef != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("credentialsSecretRef"), "must not specify the proxy credentials secret for the SSH bastion"))
+		}
+		return allErrs
+	}
+	if proxy.URL != "" {
+		proxyURL, err := url.Parse(proxy.URL)
+		switch {
+		case err != nil:
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("url"), proxy.URL, err.Error()))
+		case proxyURL.Scheme != "http" && proxyURL.Scheme != "https" && proxyURL.Scheme != "socks5":
+			allErrs = append(allErrs, field.NotSupported(fldPath.Child("url"), proxyURL.Scheme, []string{"http", "https", "socks5"}))
+		case proxyURL.User != nil:
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("url"), proxy.URL, "must not contain credentials, use the credentials secret instead"))
+		case proxyURL.Host == "":
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("url"), proxy.URL, "must specify the host of the proxy"))
+		}
+	}
+	if proxy.CredentialsSecretRef != nil && proxy.CredentialsSecretRef.Name == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("credentialsSecretRef", "name"), "must specify a name for the proxy credentials secret if the proxy credentials secret is specified"))
+	}
+	return allErrs
+}
+
+// validateSSHBastion validates the SSH bastion through which Hive tunnels connections to the API server of the cluster.
+func validateSSHBastion(bastion *hivev1.SSHBastion, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if bastion.Host == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("host"), "must specify the host of the SSH bastion"))
+	}
+	if bastion.User == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("user"), "must specify the user to log in to the SSH bastion as"))
+	}
+	if bastion.PrivateKeySecretRef.Name == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("privateKeySecretRef", "name"), "must specify a name for the SSH bastion private key secret"))
+	}
+	if len(bastion.KnownHosts) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("knownHosts"), "must specify the host keys of the SSH bastion"))
+	}
+	return allErrs
+}
+
+// validateClusterUpgrade validates the release that the cluster is asked to upgrade to. A release image set on the
+// cluster must be allowed by the release image policy.
+func validateClusterUpgrade(upgrade *hivev1.ClusterUpgrade, releaseImagePolicy *hivev1.ReleaseImagePolicy, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if upgrade == nil {
+		return allErrs
+	}
+	if upgrade.ReleaseImage == "" && (upgrade.ImageSetRef == nil || upgrade.ImageSetRef.Name == "") {
+		allErrs = append(allErrs, field.Required(fldPath, "must specify either the release image or the name of the cluster image set to upgrade to"))
+	}
+	if upgrade.ReleaseImage != "" {
+		if err := releaseimage.CheckPolicy(releaseImagePolicy, upgrade.ReleaseImage); err != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("releaseImage"), err.Error()))
+		}
+	}
+	return allErrs
+}
+
+// validateMaintenanceWindows validates the days, start times and durations of the maintenance windows of the cluster.
+func validateMaintenanceWindows(windows []hivev1.MaintenanceWindow, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i, window := range windows {
+		windowPath := fldPath.Index(i)
+		for j, day := range window.Days {
+			if _, err := controllerutils.ParseMaintenanceWindowDay(day); err != nil {
+				allErrs = append(allErrs, field.Invalid(windowPath.Child("days").Index(j), day, "must be a day of the week"))
+			}
+		}
+		if _, err := controllerutils.ParseMaintenanceWindowStartTime(window.StartTime); err != nil {
+			allErrs = append(allErrs, field.Invalid(windowPath.Child("startTime"), window.StartTime, "must be a time of day in HH:MM format"))
+		}
+		if window.Duration.Duration <= 0 {
+			allErrs = append(allErrs, field.Invalid(windowPath.Child("duration"), window.Duration.String(), "must be positive"))
+		}
+	}
+	return allErrs
+}
+
 func validateIngressDomainsShareClusterDomain(newObject *hivev1.ClusterDeploymentSpec) bool {
 	// ingress entries must share the same domain as the cluster
 	// so watch for an ingress domain ending in: .<clusterName>.<baseDomain>
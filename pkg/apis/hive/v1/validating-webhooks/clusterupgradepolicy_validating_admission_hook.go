@@ -0,0 +1,184 @@
+package validatingwebhooks
+
+import (
+	"net/http"
+
+	log "github.com/sirupsen/logrus"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	metav1validation "k8s.io/apimachinery/pkg/apis/meta/v1/validation"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/rest"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+)
+
+const (
+	clusterUpgradePolicyGroup    = "hive.openshift.io"
+	clusterUpgradePolicyVersion  = "v1"
+	clusterUpgradePolicyResource = "clusterupgradepolicies"
+)
+
+// ClusterUpgradePolicyValidatingAdmissionHook is a struct that is used to reference what code should be run by the generic-admission-server.
+type ClusterUpgradePolicyValidatingAdmissionHook struct {
+	decoder runtime.Decoder
+}
+
+// ValidatingResource is called by generic-admission-server on startup to register the returned REST resource through which the
+// webhook is accessed by the kube apiserver.
+// For example, generic-admission-server uses the data below to register the webhook on the REST resource "/apis/admission.hive.openshift.io/v1/clusterupgradepolicyvalidators".
+// When the kube apiserver calls this registered REST resource, the generic-admission-server calls the Validate() method below.
+func (a *ClusterUpgradePolicyValidatingAdmissionHook) ValidatingResource() (plural schema.GroupVersionResource, singular string) {
+	log.WithFields(log.Fields{
+		"group":    "admission.hive.openshift.io",
+		"version":  "v1",
+		"resource": "clusterupgradepolicyvalidator",
+	}).Info("Registering validation REST resource")
+	// NOTE: This GVR is meant to be different than the ClusterUpgradePolicy CRD GVR which has group "hive.openshift.io".
+	return schema.GroupVersionResource{
+			Group:    "admission.hive.openshift.io",
+			Version:  "v1",
+			Resource: "clusterupgradepolicyvalidators",
+		},
+		"clusterupgradepolicyvalidator"
+}
+
+// Initialize is called by generic-admission-server on startup to setup any special initialization that your webhook needs.
+func (a *ClusterUpgradePolicyValidatingAdmissionHook) Initialize(kubeClientConfig *rest.Config, stopCh <-chan struct{}) error {
+	log.WithFields(log.Fields{
+		"group":    "admission.hive.openshift.io",
+		"version":  "v1",
+		"resource": "clusterupgradepolicyvalidator",
+	}).Info("Initializing validation REST resource")
+
+	scheme := runtime.NewScheme()
+	hivev1.AddToScheme(scheme)
+	a.decoder = serializer.NewCodecFactory(scheme).UniversalDecoder(hivev1.SchemeGroupVersion)
+
+	return nil
+}
+
+// Validate is called by generic-admission-server when the registered REST resource above is called with an admission request.
+// Usually it's the kube apiserver that is making the admission validation request.
+func (a *ClusterUpgradePolicyValidatingAdmissionHook) Validate(request *admissionv1beta1.AdmissionRequest) *admissionv1beta1.AdmissionResponse {
+	logger := log.WithFields(log.Fields{
+		"operation": request.Operation,
+		"group":     request.Resource.Group,
+		"version":   request.Resource.Version,
+		"resource":  request.Resource.Resource,
+		"method":    "Validate",
+	})
+
+	if !a.shouldValidate(request, logger) {
+		logger.Info("Skipping validation for request")
+		// The request object isn't something that this validator should validate.
+		// Therefore, we say that it's allowed.
+		return &admissionv1beta1.AdmissionResponse{
+			Allowed: true,
+		}
+	}
+
+	logger.Info("Validating request")
+
+	switch request.Operation {
+	case admissionv1beta1.Create, admissionv1beta1.Update:
+		return a.validateRequest(request, logger)
+	default:
+		logger.Info("Successful validation")
+		return &admissionv1beta1.AdmissionResponse{
+			Allowed: true,
+		}
+	}
+}
+
+// shouldValidate explicitly checks if the request should validated. For example, this webhook may have accidentally been registered to check
+// the validity of some other type of object with a different GVR.
+func (a *ClusterUpgradePolicyValidatingAdmissionHook) shouldValidate(request *admissionv1beta1.AdmissionRequest, logger log.FieldLogger) bool {
+	logger = logger.WithField("method", "shouldValidate")
+
+	if request.Resource.Group != clusterUpgradePolicyGroup {
+		logger.Debug("Returning False, not our group")
+		return false
+	}
+
+	if request.Resource.Version != clusterUpgradePolicyVersion {
+		logger.Debug("Returning False, it's our group, but not the right version")
+		return false
+	}
+
+	if request.Resource.Resource != clusterUpgradePolicyResource {
+		logger.Debug("Returning False, it's our group and version, but not the right resource")
+		return false
+	}
+
+	// If we get here, then we're supposed to validate the object.
+	logger.Debug("Returning True, passed all prerequisites.")
+	return true
+}
+
+// validateRequest validates create and update operations for ClusterUpgradePolicy objects. There are no immutable
+// fields, so both operations only validate the new object.
+func (a *ClusterUpgradePolicyValidatingAdmissionHook) validateRequest(request *admissionv1beta1.AdmissionRequest, logger log.FieldLogger) *admissionv1beta1.AdmissionResponse {
+	logger = logger.WithField("method", "validateRequest")
+
+	newObject := &hivev1.ClusterUpgradePolicy{}
+	if _, _, err := a.decoder.Decode(request.Object.Raw, nil, newObject); err != nil {
+		logger.WithError(err).Error("failed to decode")
+		return &admissionv1beta1.AdmissionResponse{
+			Allowed: false,
+			Result: &metav1.Status{
+				Status: metav1.StatusFailure, Code: http.StatusBadRequest, Reason: metav1.StatusReasonBadRequest,
+				Message: err.Error(),
+			},
+		}
+	}
+
+	logger = logger.WithField("object.Name", newObject.Name)
+
+	if allErrs := validateClusterUpgradePolicySpec(&newObject.Spec, field.NewPath("spec")); len(allErrs) > 0 {
+		logger.WithError(allErrs.ToAggregate()).Info("failed validation")
+		status := errors.NewInvalid(schemaGVK(request.Kind).GroupKind(), request.Name, allErrs).Status()
+		return &admissionv1beta1.AdmissionResponse{
+			Allowed: false,
+			Result:  &status,
+		}
+	}
+
+	// If we get here, then all checks passed, so the object is valid.
+	logger.Info("Successful validation")
+	return &admissionv1beta1.AdmissionResponse{
+		Allowed: true,
+	}
+}
+
+func validateClusterUpgradePolicySpec(spec *hivev1.ClusterUpgradePolicySpec, fldPath *field.Path) field.ErrorList {
+	allErrs := metav1validation.ValidateLabelSelector(&spec.ClusterDeploymentSelector, fldPath.Child("clusterDeploymentSelector"))
+	if spec.ImageSetRef.Name == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("imageSetRef", "name"), "must specify the cluster image set to upgrade to"))
+	}
+	waveNames := sets.NewString()
+	for i, wave := range spec.Waves {
+		wavePath := fldPath.Child("waves").Index(i)
+		switch {
+		case wave.Name == "":
+			allErrs = append(allErrs, field.Required(wavePath.Child("name"), "must specify the name of the wave"))
+		case waveNames.Has(wave.Name):
+			allErrs = append(allErrs, field.Duplicate(wavePath.Child("name"), wave.Name))
+		}
+		waveNames.Insert(wave.Name)
+		allErrs = append(allErrs, metav1validation.ValidateLabelSelector(&wave.ClusterDeploymentSelector, wavePath.Child("clusterDeploymentSelector"))...)
+		if wave.MaxParallel < 0 {
+			allErrs = append(allErrs, field.Invalid(wavePath.Child("maxParallel"), wave.MaxParallel, "must not be negative"))
+		}
+		if wave.MaxFailures < 0 {
+			allErrs = append(allErrs, field.Invalid(wavePath.Child("maxFailures"), wave.MaxFailures, "must not be negative"))
+		}
+	}
+	return allErrs
+}
@@ -0,0 +1,250 @@
+package validatingwebhooks
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+)
+
+func Test_ClusterUpgradePolicyAdmission_Validate_Kind(t *testing.T) {
+	cases := []struct {
+		name         string
+		group        string
+		version      string
+		resource     string
+		expectToSkip bool
+	}{
+		{
+			name:     "clusterupgradepolicy",
+			group:    clusterUpgradePolicyGroup,
+			version:  clusterUpgradePolicyVersion,
+			resource: clusterUpgradePolicyResource,
+		},
+		{
+			name:         "different group",
+			group:        "other group",
+			version:      clusterUpgradePolicyVersion,
+			resource:     clusterUpgradePolicyResource,
+			expectToSkip: true,
+		},
+		{
+			name:         "different version",
+			group:        clusterUpgradePolicyGroup,
+			version:      "other version",
+			resource:     clusterUpgradePolicyResource,
+			expectToSkip: true,
+		},
+		{
+			name:         "different resource",
+			group:        clusterUpgradePolicyGroup,
+			version:      clusterUpgradePolicyVersion,
+			resource:     "other resource",
+			expectToSkip: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cut := &ClusterUpgradePolicyValidatingAdmissionHook{}
+			cut.Initialize(nil, nil)
+			request := &admissionv1beta1.AdmissionRequest{
+				Resource: metav1.GroupVersionResource{
+					Group:    tc.group,
+					Version:  tc.version,
+					Resource: tc.resource,
+				},
+				Operation: admissionv1beta1.Create,
+			}
+			response := cut.Validate(request)
+			assert.Equal(t, tc.expectToSkip, response.Allowed)
+		})
+	}
+}
+
+func Test_ClusterUpgradePolicyAdmission_Validate(t *testing.T) {
+	cases := []struct {
+		name          string
+		policy        *hivev1.ClusterUpgradePolicy
+		operation     admissionv1beta1.Operation
+		expectAllowed bool
+	}{
+		{
+			name:          "good",
+			policy:        testClusterUpgradePolicy(),
+			expectAllowed: true,
+		},
+		{
+			name: "good update",
+			policy: func() *hivev1.ClusterUpgradePolicy {
+				policy := testClusterUpgradePolicy()
+				policy.Spec.ImageSetRef.Name = "openshift-v4.5.3"
+				return policy
+			}(),
+			operation:     admissionv1beta1.Update,
+			expectAllowed: true,
+		},
+		{
+			name: "empty selector",
+			policy: func() *hivev1.ClusterUpgradePolicy {
+				policy := testClusterUpgradePolicy()
+				policy.Spec.ClusterDeploymentSelector = metav1.LabelSelector{}
+				return policy
+			}(),
+			expectAllowed: true,
+		},
+		{
+			name: "bad selector",
+			policy: func() *hivev1.ClusterUpgradePolicy {
+				policy := testClusterUpgradePolicy()
+				policy.Spec.ClusterDeploymentSelector.MatchExpressions = []metav1.LabelSelectorRequirement{{
+					Key:      "environment",
+					Operator: metav1.LabelSelectorOpIn,
+				}}
+				return policy
+			}(),
+		},
+		{
+			name: "missing imageset",
+			policy: func() *hivev1.ClusterUpgradePolicy {
+				policy := testClusterUpgradePolicy()
+				policy.Spec.ImageSetRef.Name = ""
+				return policy
+			}(),
+		},
+		{
+			name: "good waves",
+			policy: func() *hivev1.ClusterUpgradePolicy {
+				policy := testClusterUpgradePolicy()
+				policy.Spec.Waves = testClusterUpgradeWaves()
+				return policy
+			}(),
+			expectAllowed: true,
+		},
+		{
+			name: "wave without name",
+			policy: func() *hivev1.ClusterUpgradePolicy {
+				policy := testClusterUpgradePolicy()
+				policy.Spec.Waves = testClusterUpgradeWaves()
+				policy.Spec.Waves[1].Name = ""
+				return policy
+			}(),
+		},
+		{
+			name: "duplicate wave name",
+			policy: func() *hivev1.ClusterUpgradePolicy {
+				policy := testClusterUpgradePolicy()
+				policy.Spec.Waves = testClusterUpgradeWaves()
+				policy.Spec.Waves[1].Name = policy.Spec.Waves[0].Name
+				return policy
+			}(),
+		},
+		{
+			name: "bad wave selector",
+			policy: func() *hivev1.ClusterUpgradePolicy {
+				policy := testClusterUpgradePolicy()
+				policy.Spec.Waves = testClusterUpgradeWaves()
+				policy.Spec.Waves[0].ClusterDeploymentSelector.MatchExpressions = []metav1.LabelSelectorRequirement{{
+					Key:      "tier",
+					Operator: metav1.LabelSelectorOpExists,
+					Values:   []string{"canary"},
+				}}
+				return policy
+			}(),
+		},
+		{
+			name: "negative max parallel",
+			policy: func() *hivev1.ClusterUpgradePolicy {
+				policy := testClusterUpgradePolicy()
+				policy.Spec.Waves = testClusterUpgradeWaves()
+				policy.Spec.Waves[0].MaxParallel = -1
+				return policy
+			}(),
+			operation: admissionv1beta1.Update,
+		},
+		{
+			name: "negative max failures",
+			policy: func() *hivev1.ClusterUpgradePolicy {
+				policy := testClusterUpgradePolicy()
+				policy.Spec.Waves = testClusterUpgradeWaves()
+				policy.Spec.Waves[0].MaxFailures = -1
+				return policy
+			}(),
+		},
+		{
+			name: "missing imageset in update",
+			policy: func() *hivev1.ClusterUpgradePolicy {
+				policy := testClusterUpgradePolicy()
+				policy.Spec.ImageSetRef.Name = ""
+				return policy
+			}(),
+			operation: admissionv1beta1.Update,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cut := &ClusterUpgradePolicyValidatingAdmissionHook{}
+			cut.Initialize(nil, nil)
+			rawPolicy, err := json.Marshal(tc.policy)
+			if !assert.NoError(t, err, "unexpected error marshalling policy") {
+				return
+			}
+			operation := tc.operation
+			if operation == "" {
+				operation = admissionv1beta1.Create
+			}
+			request := &admissionv1beta1.AdmissionRequest{
+				Resource: metav1.GroupVersionResource{
+					Group:    clusterUpgradePolicyGroup,
+					Version:  clusterUpgradePolicyVersion,
+					Resource: clusterUpgradePolicyResource,
+				},
+				Operation: operation,
+				Object:    runtime.RawExtension{Raw: rawPolicy},
+			}
+			if operation == admissionv1beta1.Update {
+				rawOldPolicy, err := json.Marshal(testClusterUpgradePolicy())
+				if !assert.NoError(t, err, "unexpected error marshalling old policy") {
+					return
+				}
+				request.OldObject = runtime.RawExtension{Raw: rawOldPolicy}
+			}
+			response := cut.Validate(request)
+			assert.Equal(t, tc.expectAllowed, response.Allowed, "unexpected response: %#v", response.Result)
+		})
+	}
+}
+
+func testClusterUpgradePolicy() *hivev1.ClusterUpgradePolicy {
+	return &hivev1.ClusterUpgradePolicy{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "production",
+		},
+		Spec: hivev1.ClusterUpgradePolicySpec{
+			ClusterDeploymentSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{"environment": "production"},
+			},
+			ImageSetRef: hivev1.ClusterImageSetReference{Name: "openshift-v4.5.2"},
+		},
+	}
+}
+
+func testClusterUpgradeWaves() []hivev1.ClusterUpgradeWave {
+	return []hivev1.ClusterUpgradeWave{
+		{
+			Name:                      "canary",
+			ClusterDeploymentSelector: metav1.LabelSelector{MatchLabels: map[string]string{"tier": "canary"}},
+			MaxParallel:               1,
+		},
+		{
+			Name:        "rest",
+			MaxParallel: 10,
+			MaxFailures: 2,
+		},
+	}
+}
@@ -0,0 +1,196 @@
+package validatingwebhooks
+
+import (
+	"net/http"
+	"regexp"
+
+	log "github.com/sirupsen/logrus"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/rest"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+)
+
+const (
+	installFailureRuleGroup    = "hive.openshift.io"
+	installFailureRuleVersion  = "v1"
+	installFailureRuleResource = "installfailurerules"
+)
+
+var validInstallFailureRulePlatforms = sets.NewString(
+	"aws",
+	"azure",
+	"baremetal",
+	"gcp",
+	"openstack",
+	"vsphere",
+)
+
+// InstallFailureRuleValidatingAdmissionHook is a struct that is used to reference what code should be run by the generic-admission-server.
+type InstallFailureRuleValidatingAdmissionHook struct {
+	decoder runtime.Decoder
+}
+
+// ValidatingResource is called by generic-admission-server on startup to register the returned REST resource through which the
+// webhook is accessed by the kube apiserver.
+// For example, generic-admission-server uses the data below to register the webhook on the REST resource "/apis/admission.hive.openshift.io/v1/installfailurerulevalidators".
+// When the kube apiserver calls this registered REST resource, the generic-admission-server calls the Validate() method below.
+func (a *InstallFailureRuleValidatingAdmissionHook) ValidatingResource() (plural schema.GroupVersionResource, singular string) {
+	log.WithFields(log.Fields{
+		"group":    "admission.hive.openshift.io",
+		"version":  "v1",
+		"resource": "installfailurerulevalidator",
+	}).Info("Registering validation REST resource")
+	// NOTE: This GVR is meant to be different than the InstallFailureRule CRD GVR which has group "hive.openshift.io".
+	return schema.GroupVersionResource{
+			Group:    "admission.hive.openshift.io",
+			Version:  "v1",
+			Resource: "installfailurerulevalidators",
+		},
+		"installfailurerulevalidator"
+}
+
+// Initialize is called by generic-admission-server on startup to setup any special initialization that your webhook needs.
+func (a *InstallFailureRuleValidatingAdmissionHook) Initialize(kubeClientConfig *rest.Config, stopCh <-chan struct{}) error {
+	log.WithFields(log.Fields{
+		"group":    "admission.hive.openshift.io",
+		"version":  "v1",
+		"resource": "installfailurerulevalidator",
+	}).Info("Initializing validation REST resource")
+
+	scheme := runtime.NewScheme()
+	hivev1.AddToScheme(scheme)
+	a.decoder = serializer.NewCodecFactory(scheme).UniversalDecoder(hivev1.SchemeGroupVersion)
+
+	return nil
+}
+
+// Validate is called by generic-admission-server when the registered REST resource above is called with an admission request.
+// Usually it's the kube apiserver that is making the admission validation request.
+func (a *InstallFailureRuleValidatingAdmissionHook) Validate(request *admissionv1beta1.AdmissionRequest) *admissionv1beta1.AdmissionResponse {
+	logger := log.WithFields(log.Fields{
+		"operation": request.Operation,
+		"group":     request.Resource.Group,
+		"version":   request.Resource.Version,
+		"resource":  request.Resource.Resource,
+		"method":    "Validate",
+	})
+
+	if !a.shouldValidate(request, logger) {
+		logger.Info("Skipping validation for request")
+		// The request object isn't something that this validator should validate.
+		// Therefore, we say that it's allowed.
+		return &admissionv1beta1.AdmissionResponse{
+			Allowed: true,
+		}
+	}
+
+	logger.Info("Validating request")
+
+	switch request.Operation {
+	case admissionv1beta1.Create, admissionv1beta1.Update:
+		return a.validateRequest(request, logger)
+	default:
+		logger.Info("Successful validation")
+		return &admissionv1beta1.AdmissionResponse{
+			Allowed: true,
+		}
+	}
+}
+
+// shouldValidate explicitly checks if the request should validated. For example, this webhook may have accidentally been registered to check
+// the validity of some other type of object with a different GVR.
+func (a *InstallFailureRuleValidatingAdmissionHook) shouldValidate(request *admissionv1beta1.AdmissionRequest, logger log.FieldLogger) bool {
+	logger = logger.WithField("method", "shouldValidate")
+
+	if request.Resource.Group != installFailureRuleGroup {
+		logger.Debug("Returning False, not our group")
+		return false
+	}
+
+	if request.Resource.Version != installFailureRuleVersion {
+		logger.Debug("Returning False, it's our group, but not the right version")
+		return false
+	}
+
+	if request.Resource.Resource != installFailureRuleResource {
+		logger.Debug("Returning False, it's our group and version, but not the right resource")
+		return false
+	}
+
+	// If we get here, then we're supposed to validate the object.
+	logger.Debug("Returning True, passed all prerequisites.")
+	return true
+}
+
+// validateRequest validates create and update operations for InstallFailureRule objects. There are no immutable
+// fields, so both operations only validate the new object.
+func (a *InstallFailureRuleValidatingAdmissionHook) validateRequest(request *admissionv1beta1.AdmissionRequest, logger log.FieldLogger) *admissionv1beta1.AdmissionResponse {
+	logger = logger.WithField("method", "validateRequest")
+
+	newObject := &hivev1.InstallFailureRule{}
+	if _, _, err := a.decoder.Decode(request.Object.Raw, nil, newObject); err != nil {
+		logger.WithError(err).Error("failed to decode")
+		return &admissionv1beta1.AdmissionResponse{
+			Allowed: false,
+			Result: &metav1.Status{
+				Status: metav1.StatusFailure, Code: http.StatusBadRequest, Reason: metav1.StatusReasonBadRequest,
+				Message: err.Error(),
+			},
+		}
+	}
+
+	logger = logger.WithField("object.Name", newObject.Name)
+
+	if allErrs := validateInstallFailureRuleSpec(&newObject.Spec, field.NewPath("spec")); len(allErrs) > 0 {
+		logger.WithError(allErrs.ToAggregate()).Info("failed validation")
+		status := errors.NewInvalid(schemaGVK(request.Kind).GroupKind(), request.Name, allErrs).Status()
+		return &admissionv1beta1.AdmissionResponse{
+			Allowed: false,
+			Result:  &status,
+		}
+	}
+
+	// If we get here, then all checks passed, so the object is valid.
+	logger.Info("Successful validation")
+	return &admissionv1beta1.AdmissionResponse{
+		Allowed: true,
+	}
+}
+
+func validateInstallFailureRuleSpec(spec *hivev1.InstallFailureRuleSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	for i, platform := range spec.Platforms {
+		if !validInstallFailureRulePlatforms.Has(platform) {
+			allErrs = append(allErrs, field.NotSupported(fldPath.Child("platforms").Index(i), platform, validInstallFailureRulePlatforms.List()))
+		}
+	}
+	if len(spec.SearchRegexStrings) == 0 {
+		allErrs = append(allErrs, field.Required(fldPath.Child("searchRegexStrings"), "must specify at least one regex to search for"))
+	}
+	for i, s := range spec.SearchRegexStrings {
+		regexPath := fldPath.Child("searchRegexStrings").Index(i)
+		if s == "" {
+			allErrs = append(allErrs, field.Invalid(regexPath, s, "regex cannot be an empty string"))
+			continue
+		}
+		if _, err := regexp.Compile(s); err != nil {
+			allErrs = append(allErrs, field.Invalid(regexPath, s, err.Error()))
+		}
+	}
+	if spec.InstallFailingReason == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("installFailingReason"), "must specify the reason for matching install failures"))
+	}
+	if spec.InstallFailingMessage == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("installFailingMessage"), "must specify the message for matching install failures"))
+	}
+	return allErrs
+}
@@ -0,0 +1,206 @@
+package validatingwebhooks
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+)
+
+func Test_InstallFailureRuleAdmission_Validate_Kind(t *testing.T) {
+	cases := []struct {
+		name         string
+		group        string
+		version      string
+		resource     string
+		expectToSkip bool
+	}{
+		{
+			name:     "installfailurerule",
+			group:    installFailureRuleGroup,
+			version:  installFailureRuleVersion,
+			resource: installFailureRuleResource,
+		},
+		{
+			name:         "different group",
+			group:        "other group",
+			version:      installFailureRuleVersion,
+			resource:     installFailureRuleResource,
+			expectToSkip: true,
+		},
+		{
+			name:         "different version",
+			group:        installFailureRuleGroup,
+			version:      "other version",
+			resource:     installFailureRuleResource,
+			expectToSkip: true,
+		},
+		{
+			name:         "different resource",
+			group:        installFailureRuleGroup,
+			version:      installFailureRuleVersion,
+			resource:     "other resource",
+			expectToSkip: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cut := &InstallFailureRuleValidatingAdmissionHook{}
+			cut.Initialize(nil, nil)
+			request := &admissionv1beta1.AdmissionRequest{
+				Resource: metav1.GroupVersionResource{
+					Group:    tc.group,
+					Version:  tc.version,
+					Resource: tc.resource,
+				},
+				Operation: admissionv1beta1.Create,
+			}
+			response := cut.Validate(request)
+			assert.Equal(t, tc.expectToSkip, response.Allowed)
+		})
+	}
+}
+
+func Test_InstallFailureRuleAdmission_Validate(t *testing.T) {
+	cases := []struct {
+		name          string
+		rule          *hivev1.InstallFailureRule
+		operation     admissionv1beta1.Operation
+		expectAllowed bool
+	}{
+		{
+			name:          "good",
+			rule:          testInstallFailureRule(),
+			expectAllowed: true,
+		},
+		{
+			name: "good update",
+			rule: func() *hivev1.InstallFailureRule {
+				rule := testInstallFailureRule()
+				rule.Spec.Priority = -10
+				return rule
+			}(),
+			operation:     admissionv1beta1.Update,
+			expectAllowed: true,
+		},
+		{
+			name: "all platforms",
+			rule: func() *hivev1.InstallFailureRule {
+				rule := testInstallFailureRule()
+				rule.Spec.Platforms = nil
+				return rule
+			}(),
+			expectAllowed: true,
+		},
+		{
+			name: "unsupported platform",
+			rule: func() *hivev1.InstallFailureRule {
+				rule := testInstallFailureRule()
+				rule.Spec.Platforms = []string{"aws", "other"}
+				return rule
+			}(),
+		},
+		{
+			name: "missing search regexes",
+			rule: func() *hivev1.InstallFailureRule {
+				rule := testInstallFailureRule()
+				rule.Spec.SearchRegexStrings = nil
+				return rule
+			}(),
+		},
+		{
+			name: "empty search regex",
+			rule: func() *hivev1.InstallFailureRule {
+				rule := testInstallFailureRule()
+				rule.Spec.SearchRegexStrings = append(rule.Spec.SearchRegexStrings, "")
+				return rule
+			}(),
+		},
+		{
+			name: "search regex does not compile",
+			rule: func() *hivev1.InstallFailureRule {
+				rule := testInstallFailureRule()
+				rule.Spec.SearchRegexStrings = append(rule.Spec.SearchRegexStrings, "*")
+				return rule
+			}(),
+		},
+		{
+			name: "bad search regex in update",
+			rule: func() *hivev1.InstallFailureRule {
+				rule := testInstallFailureRule()
+				rule.Spec.SearchRegexStrings = []string{"(unclosed"}
+				return rule
+			}(),
+			operation: admissionv1beta1.Update,
+		},
+		{
+			name: "missing reason",
+			rule: func() *hivev1.InstallFailureRule {
+				rule := testInstallFailureRule()
+				rule.Spec.InstallFailingReason = ""
+				return rule
+			}(),
+		},
+		{
+			name: "missing message",
+			rule: func() *hivev1.InstallFailureRule {
+				rule := testInstallFailureRule()
+				rule.Spec.InstallFailingMessage = ""
+				return rule
+			}(),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cut := &InstallFailureRuleValidatingAdmissionHook{}
+			cut.Initialize(nil, nil)
+			rawRule, err := json.Marshal(tc.rule)
+			if !assert.NoError(t, err, "unexpected error marshalling rule") {
+				return
+			}
+			operation := tc.operation
+			if operation == "" {
+				operation = admissionv1beta1.Create
+			}
+			request := &admissionv1beta1.AdmissionRequest{
+				Resource: metav1.GroupVersionResource{
+					Group:    installFailureRuleGroup,
+					Version:  installFailureRuleVersion,
+					Resource: installFailureRuleResource,
+				},
+				Operation: operation,
+				Object:    runtime.RawExtension{Raw: rawRule},
+			}
+			if operation == admissionv1beta1.Update {
+				rawOldRule, err := json.Marshal(testInstallFailureRule())
+				if !assert.NoError(t, err, "unexpected error marshalling old rule") {
+					return
+				}
+				request.OldObject = runtime.RawExtension{Raw: rawOldRule}
+			}
+			response := cut.Validate(request)
+			assert.Equal(t, tc.expectAllowed, response.Allowed, "unexpected response: %#v", response.Result)
+		})
+	}
+}
+
+func testInstallFailureRule() *hivev1.InstallFailureRule {
+	return &hivev1.InstallFailureRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "aws-vpc-limit-exceeded",
+		},
+		Spec: hivev1.InstallFailureRuleSpec{
+			Priority:              10,
+			Platforms:             []string{"aws"},
+			SearchRegexStrings:    []string{"VpcLimitExceeded", "The maximum number of VPCs has been reached"},
+			InstallFailingReason:  "AWSVPCLimitExceeded",
+			InstallFailingMessage: "AWS VPC limit exceeded",
+		},
+	}
+}
@@ -22,6 +22,8 @@ import (
 	hivev1aws "github.com/openshift/hive/pkg/apis/hive/v1/aws"
 	hivev1azure "github.com/openshift/hive/pkg/apis/hive/v1/azure"
 	hivev1gcp "github.com/openshift/hive/pkg/apis/hive/v1/gcp"
+	hivev1openstack "github.com/openshift/hive/pkg/apis/hive/v1/openstack"
+	hivev1vsphere "github.com/openshift/hive/pkg/apis/hive/v1/vsphere"
 )
 
 const (
@@ -278,6 +280,14 @@ func validateMachinePoolSpecInvariants(spec *hivev1.MachinePoolSpec, fldPath *fi
 		allErrs = append(allErrs, validateAzureMachinePoolPlatformInvariants(p, platformPath.Child("azure"))...)
 		numberOfMachineSets = len(p.Zones)
 	}
+	if p := spec.Platform.OpenStack; p != nil {
+		platforms = append(platforms, "openstack")
+		allErrs = append(allErrs, validateOpenStackMachinePoolPlatformInvariants(p, platformPath.Child("openstack"))...)
+	}
+	if p := spec.Platform.VSphere; p != nil {
+		platforms = append(platforms, "vsphere")
+		allErrs = append(allErrs, validateVSphereMachinePoolPlatformInvariants(p, platformPath.Child("vsphere"))...)
+	}
 	switch len(platforms) {
 	case 0:
 		allErrs = append(allErrs, field.Required(platformPath, "must specify a platform"))
@@ -359,3 +369,39 @@ func validateAzureMachinePoolPlatformInvariants(platform *hivev1azure.MachinePoo
 	}
 	return allErrs
 }
+
+func validateOpenStackMachinePoolPlatformInvariants(platform *hivev1openstack.MachinePool, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if platform.Flavor == "" {
+		allErrs = append(allErrs, field.Required(fldPath.Child("flavor"), "flavor name is required"))
+	}
+	if rootVolume := platform.RootVolume; rootVolume != nil {
+		rootVolumePath := fldPath.Child("rootVolume")
+		if rootVolume.Size <= 0 {
+			allErrs = append(allErrs, field.Invalid(rootVolumePath.Child("size"), rootVolume.Size, "volume size must be positive"))
+		}
+		if rootVolume.Type == "" {
+			allErrs = append(allErrs, field.Required(rootVolumePath.Child("type"), "volume type is required"))
+		}
+	}
+	return allErrs
+}
+
+func validateVSphereMachinePoolPlatformInvariants(platform *hivev1vsphere.MachinePool, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	if platform.NumCPUs <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("cpus"), platform.NumCPUs, "number of cpus must be positive"))
+	}
+	if platform.NumCoresPerSocket <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("coresPerSocket"), platform.NumCoresPerSocket, "cores per socket must be positive"))
+	} else if platform.NumCPUs > 0 && platform.NumCPUs%platform.NumCoresPerSocket != 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("coresPerSocket"), platform.NumCoresPerSocket, "number of cpus must be a multiple of the cores per socket"))
+	}
+	if platform.MemoryMiB <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("memoryMB"), platform.MemoryMiB, "memory must be positive"))
+	}
+	if platform.OSDisk.DiskSizeGB <= 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("osDisk", "diskSizeGB"), platform.OSDisk.DiskSizeGB, "disk size must be positive"))
+	}
+	return allErrs
+}
@@ -17,6 +17,8 @@ import (
 	hivev1aws "github.com/openshift/hive/pkg/apis/hive/v1/aws"
 	hivev1azure "github.com/openshift/hive/pkg/apis/hive/v1/azure"
 	hivev1gcp "github.com/openshift/hive/pkg/apis/hive/v1/gcp"
+	hivev1openstack "github.com/openshift/hive/pkg/apis/hive/v1/openstack"
+	hivev1vsphere "github.com/openshift/hive/pkg/apis/hive/v1/vsphere"
 )
 
 func Test_MachinePoolAdmission_Validate_Kind(t *testing.T) {
@@ -420,6 +422,74 @@ func Test_MachinePoolAdmission_Validate_Create(t *testing.T) {
 				return pool
 			}(),
 		},
+		{
+			name:          "OpenStack pool",
+			provision:     testOpenStackMachinePool(),
+			expectAllowed: true,
+		},
+		{
+			name: "missing OpenStack flavor",
+			provision: func() *hivev1.MachinePool {
+				pool := testOpenStackMachinePool()
+				pool.Spec.Platform.OpenStack.Flavor = ""
+				return pool
+			}(),
+		},
+		{
+			name: "OpenStack root volume",
+			provision: func() *hivev1.MachinePool {
+				pool := testOpenStackMachinePool()
+				pool.Spec.Platform.OpenStack.RootVolume = &hivev1openstack.RootVolume{Size: 100, Type: "test-volume-type"}
+				return pool
+			}(),
+			expectAllowed: true,
+		},
+		{
+			name: "invalid OpenStack root volume size",
+			provision: func() *hivev1.MachinePool {
+				pool := testOpenStackMachinePool()
+				pool.Spec.Platform.OpenStack.RootVolume = &hivev1openstack.RootVolume{Size: 0, Type: "test-volume-type"}
+				return pool
+			}(),
+		},
+		{
+			name: "missing OpenStack root volume type",
+			provision: func() *hivev1.MachinePool {
+				pool := testOpenStackMachinePool()
+				pool.Spec.Platform.OpenStack.RootVolume = &hivev1openstack.RootVolume{Size: 100}
+				return pool
+			}(),
+		},
+		{
+			name:          "vSphere pool",
+			provision:     testVSphereMachinePool(),
+			expectAllowed: true,
+		},
+		{
+			name: "missing vSphere memory",
+			provision: func() *hivev1.MachinePool {
+				pool := testVSphereMachinePool()
+				pool.Spec.Platform.VSphere.MemoryMiB = 0
+				return pool
+			}(),
+		},
+		{
+			name: "vSphere cpus not a multiple of cores per socket",
+			provision: func() *hivev1.MachinePool {
+				pool := testVSphereMachinePool()
+				pool.Spec.Platform.VSphere.NumCPUs = 3
+				pool.Spec.Platform.VSphere.NumCoresPerSocket = 2
+				return pool
+			}(),
+		},
+		{
+			name: "invalid vSphere disk size",
+			provision: func() *hivev1.MachinePool {
+				pool := testVSphereMachinePool()
+				pool.Spec.Platform.VSphere.OSDisk.DiskSizeGB = -1
+				return pool
+			}(),
+		},
 		{
 			name: "valid labels",
 			provision: func() *hivev1.MachinePool {
@@ -599,6 +669,22 @@ func testAzureMachinePool() *hivev1.MachinePool {
 	return pool
 }
 
+func testOpenStackMachinePool() *hivev1.MachinePool {
+	pool := testMachinePool()
+	pool.Spec.Platform = hivev1.MachinePoolPlatform{
+		OpenStack: validOpenStackMachinePoolPlatform(),
+	}
+	return pool
+}
+
+func testVSphereMachinePool() *hivev1.MachinePool {
+	pool := testMachinePool()
+	pool.Spec.Platform = hivev1.MachinePoolPlatform{
+		VSphere: validVSphereMachinePoolPlatform(),
+	}
+	return pool
+}
+
 func validAWSMachinePoolPlatform() *hivev1aws.MachinePoolPlatform {
 	return &hivev1aws.MachinePoolPlatform{
 		InstanceType: "test-instance-type",
@@ -624,3 +710,20 @@ func validAzureMachinePoolPlatform() *hivev1azure.MachinePool {
 		},
 	}
 }
+
+func validOpenStackMachinePoolPlatform() *hivev1openstack.MachinePool {
+	return &hivev1openstack.MachinePool{
+		Flavor: "test-flavor",
+	}
+}
+
+func validVSphereMachinePoolPlatform() *hivev1vsphere.MachinePool {
+	return &hivev1vsphere.MachinePool{
+		NumCPUs:           4,
+		NumCoresPerSocket: 2,
+		MemoryMiB:         16384,
+		OSDisk: hivev1vsphere.OSDisk{
+			DiskSizeGB: 120,
+		},
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/syncsettemplate"
 
 	admissionv1beta1 "k8s.io/api/admission/v1beta1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -34,6 +35,11 @@ var invalidResourceGroupKinds = map[string]map[string]bool{
 	},
 }
 
+var validResourceTemplateModes = []string{
+	string(hivev1.NoneResourceTemplateMode),
+	string(hivev1.GoTemplateResourceTemplateMode),
+}
+
 var validPatchTypes = map[string]bool{
 	"json":      true,
 	"merge":     true,
@@ -170,6 +176,7 @@ func (a *SyncSetValidatingAdmissionHook) validateCreate(admissionSpec *admission
 
 	allErrs := field.ErrorList{}
 	allErrs = append(allErrs, validateResources(newObject.Spec.Resources, field.NewPath("spec").Child("resources"))...)
+	allErrs = append(allErrs, validateResourceTemplates(&newObject.Spec.SyncSetCommonSpec, field.NewPath("spec"))...)
 	allErrs = append(allErrs, validatePatches(newObject.Spec.Patches, field.NewPath("spec").Child("patches"))...)
 	allErrs = append(allErrs, validateSecrets(newObject.Spec.Secrets, field.NewPath("spec").Child("secretMappings"))...)
 
@@ -217,6 +224,7 @@ func (a *SyncSetValidatingAdmissionHook) validateUpdate(admissionSpec *admission
 
 	allErrs := field.ErrorList{}
 	allErrs = append(allErrs, validateResources(newObject.Spec.Resources, field.NewPath("spec", "resources"))...)
+	allErrs = append(allErrs, validateResourceTemplates(&newObject.Spec.SyncSetCommonSpec, field.NewPath("spec"))...)
 	allErrs = append(allErrs, validatePatches(newObject.Spec.Patches, field.NewPath("spec", "patches"))...)
 	allErrs = append(allErrs, validateSecrets(newObject.Spec.Secrets, field.NewPath("spec", "secretMappings"))...)
 
@@ -272,6 +280,28 @@ func validateResource(resource runtime.RawExtension, fldPath *field.Path) field.
 	return allErrs
 }
 
+func validateResourceTemplates(spec *hivev1.SyncSetCommonSpec, fldPath *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+	switch spec.ResourceTemplateMode {
+	case "", hivev1.NoneResourceTemplateMode:
+		if spec.TemplateParametersRef != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("templateParametersRef"), "template parameters require the GoTemplate resource template mode"))
+		}
+	case hivev1.GoTemplateResourceTemplateMode:
+		for i, resource := range spec.Resources {
+			if err := syncsettemplate.Validate(resource.Raw); err != nil {
+				allErrs = append(allErrs, field.Invalid(fldPath.Child("resources").Index(i), string(resource.Raw), err.Error()))
+			}
+		}
+		if ref := spec.TemplateParametersRef; ref != nil && len(ref.Name) == 0 {
+			allErrs = append(allErrs, field.Required(fldPath.Child("templateParametersRef", "name"), "Name is required"))
+		}
+	default:
+		allErrs = append(allErrs, field.NotSupported(fldPath.Child("resourceTemplateMode"), spec.ResourceTemplateMode, validResourceTemplateModes))
+	}
+	return allErrs
+}
+
 func validateSecrets(secrets []hivev1.SecretMapping, fldPath *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 	for i, secret := range secrets {
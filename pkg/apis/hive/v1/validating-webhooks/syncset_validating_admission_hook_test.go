@@ -7,6 +7,7 @@ import (
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
 	"github.com/stretchr/testify/assert"
 	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -263,6 +264,51 @@ func TestSyncSetValidate(t *testing.T) {
 			syncSet:         testSyncSetWithResources(`{"apiVersion": "authorization.openshift.io/v1", "kind": "SubjectAccessReview"}`),
 			expectedAllowed: false,
 		},
+		{
+			name:      "Test valid template Resource create",
+			operation: admissionv1beta1.Create,
+			syncSet: testTemplateSyncSet(hivev1.GoTemplateResourceTemplateMode,
+				`{"apiVersion": "v1", "kind": "ConfigMap", "data": {"host": "{{ .ClusterName }}.{{ .BaseDomain }}"}}`),
+			expectedAllowed: true,
+		},
+		{
+			name:      "Test invalid template Resource create",
+			operation: admissionv1beta1.Create,
+			syncSet: testTemplateSyncSet(hivev1.GoTemplateResourceTemplateMode,
+				`{"apiVersion": "v1", "kind": "ConfigMap", "data": {"host": "{{ .ClusterName }"}}`),
+			expectedAllowed: false,
+		},
+		{
+			name:      "Test invalid template Resource update",
+			operation: admissionv1beta1.Update,
+			syncSet: testTemplateSyncSet(hivev1.GoTemplateResourceTemplateMode,
+				`{"apiVersion": "v1", "kind": "ConfigMap", "data": {"host": "{{ undefinedFunction }}"}}`),
+			expectedAllowed: false,
+		},
+		{
+			name:      "Test invalid template Resource without template mode create",
+			operation: admissionv1beta1.Create,
+			syncSet: testTemplateSyncSet("",
+				`{"apiVersion": "v1", "kind": "ConfigMap", "data": {"host": "{{ .ClusterName }"}}`),
+			expectedAllowed: true,
+		},
+		{
+			name:      "Test unsupported template mode create",
+			operation: admissionv1beta1.Create,
+			syncSet: testTemplateSyncSet("Jinja",
+				`{"apiVersion": "v1", "kind": "ConfigMap"}`),
+			expectedAllowed: false,
+		},
+		{
+			name:      "Test template parameters without template mode create",
+			operation: admissionv1beta1.Create,
+			syncSet: func() *hivev1.SyncSet {
+				ss := testTemplateSyncSet(hivev1.NoneResourceTemplateMode, `{"apiVersion": "v1", "kind": "ConfigMap"}`)
+				ss.Spec.TemplateParametersRef = &corev1.LocalObjectReference{Name: "params"}
+				return ss
+			}(),
+			expectedAllowed: false,
+		},
 	}
 
 	for _, tc := range cases {
@@ -359,3 +405,9 @@ func testSyncSetWithResources(resources ...string) *hivev1.SyncSet {
 	}
 	return ss
 }
+
+func testTemplateSyncSet(mode hivev1.SyncSetResourceTemplateMode, resources ...string) *hivev1.SyncSet {
+	ss := testSyncSetWithResources(resources...)
+	ss.Spec.ResourceTemplateMode = mode
+	return ss
+}
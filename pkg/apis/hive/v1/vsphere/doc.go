@@ -0,0 +1,4 @@
+// Package vsphere contains API Schema definitions for vSphere clusters.
+// +k8s:deepcopy-gen=package,register
+// +k8s:conversion-gen=github.com/openshift/hive/pkg/apis/hive
+package vsphere
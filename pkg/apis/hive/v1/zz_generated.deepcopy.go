@@ -10,11 +10,41 @@ import (
 	azure "github.com/openshift/hive/pkg/apis/hive/v1/azure"
 	baremetal "github.com/openshift/hive/pkg/apis/hive/v1/baremetal"
 	gcp "github.com/openshift/hive/pkg/apis/hive/v1/gcp"
+	openstack "github.com/openshift/hive/pkg/apis/hive/v1/openstack"
+	vsphere "github.com/openshift/hive/pkg/apis/hive/v1/vsphere"
 	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ACMEConfig) DeepCopyInto(out *ACMEConfig) {
+	*out = *in
+	out.AccountKeySecretRef = in.AccountKeySecretRef
+	if in.CASecretRef != nil {
+		in, out := &in.CASecretRef, &out.CASecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.RenewBefore != nil {
+		in, out := &in.RenewBefore, &out.RenewBefore
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ACMEConfig.
+func (in *ACMEConfig) DeepCopy() *ACMEConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ACMEConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AWSClusterDeprovision) DeepCopyInto(out *AWSClusterDeprovision) {
 	*out = *in
@@ -116,6 +146,44 @@ func (in *AzureClusterDeprovision) DeepCopy() *AzureClusterDeprovision {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureDNSZoneSpec) DeepCopyInto(out *AzureDNSZoneSpec) {
+	*out = *in
+	out.CredentialsSecretRef = in.CredentialsSecretRef
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureDNSZoneSpec.
+func (in *AzureDNSZoneSpec) DeepCopy() *AzureDNSZoneSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureDNSZoneSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureDNSZoneStatus) DeepCopyInto(out *AzureDNSZoneStatus) {
+	*out = *in
+	if in.ZoneName != nil {
+		in, out := &in.ZoneName, &out.ZoneName
+		*out = new(string)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureDNSZoneStatus.
+func (in *AzureDNSZoneStatus) DeepCopy() *AzureDNSZoneStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureDNSZoneStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BackupConfig) DeepCopyInto(out *BackupConfig) {
 	*out = *in
@@ -174,6 +242,10 @@ func (in *CertificateBundleSpec) DeepCopy() *CertificateBundleSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CertificateBundleStatus) DeepCopyInto(out *CertificateBundleStatus) {
 	*out = *in
+	if in.NotAfter != nil {
+		in, out := &in.NotAfter, &out.NotAfter
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
@@ -282,6 +354,134 @@ func (in *CheckpointStatus) DeepCopy() *CheckpointStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterClaim) DeepCopyInto(out *ClusterClaim) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterClaim.
+func (in *ClusterClaim) DeepCopy() *ClusterClaim {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterClaim)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterClaim) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterClaimCondition) DeepCopyInto(out *ClusterClaimCondition) {
+	*out = *in
+	in.LastProbeTime.DeepCopyInto(&out.LastProbeTime)
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterClaimCondition.
+func (in *ClusterClaimCondition) DeepCopy() *ClusterClaimCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterClaimCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterClaimList) DeepCopyInto(out *ClusterClaimList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterClaim, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterClaimList.
+func (in *ClusterClaimList) DeepCopy() *ClusterClaimList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterClaimList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterClaimList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterClaimSpec) DeepCopyInto(out *ClusterClaimSpec) {
+	*out = *in
+	if in.Subjects != nil {
+		in, out := &in.Subjects, &out.Subjects
+		*out = make([]rbacv1.Subject, len(*in))
+		copy(*out, *in)
+	}
+	if in.Lifetime != nil {
+		in, out := &in.Lifetime, &out.Lifetime
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterClaimSpec.
+func (in *ClusterClaimSpec) DeepCopy() *ClusterClaimSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterClaimSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterClaimStatus) DeepCopyInto(out *ClusterClaimStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]ClusterClaimCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterClaimStatus.
+func (in *ClusterClaimStatus) DeepCopy() *ClusterClaimStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterClaimStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterDeployment) DeepCopyInto(out *ClusterDeployment) {
 	*out = *in
@@ -383,16 +583,43 @@ func (in *ClusterDeploymentSpec) DeepCopyInto(out *ClusterDeploymentSpec) {
 		*out = make([]CertificateBundleSpec, len(*in))
 		copy(*out, *in)
 	}
+	if in.RFC2136TSIGKeySecretRef != nil {
+		in, out := &in.RFC2136TSIGKeySecretRef, &out.RFC2136TSIGKeySecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
 	if in.ClusterMetadata != nil {
 		in, out := &in.ClusterMetadata, &out.ClusterMetadata
 		*out = new(ClusterMetadata)
-		**out = **in
+		(*in).DeepCopyInto(*out)
 	}
 	if in.Provisioning != nil {
 		in, out := &in.Provisioning, &out.Provisioning
 		*out = new(Provisioning)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ClusterPoolRef != nil {
+		in, out := &in.ClusterPoolRef, &out.ClusterPoolRef
+		*out = new(ClusterPoolReference)
+		**out = **in
+	}
+	if in.KubeadminPassword != nil {
+		in, out := &in.KubeadminPassword, &out.KubeadminPassword
+		*out = new(KubeadminPasswordSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Upgrade != nil {
+		in, out := &in.Upgrade, &out.Upgrade
+		*out = new(ClusterUpgrade)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaintenanceWindows != nil {
+		in, out := &in.MaintenanceWindows, &out.MaintenanceWindows
+		*out = make([]MaintenanceWindow, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -430,7 +657,9 @@ func (in *ClusterDeploymentStatus) DeepCopyInto(out *ClusterDeploymentStatus) {
 	if in.CertificateBundles != nil {
 		in, out := &in.CertificateBundles, &out.CertificateBundles
 		*out = make([]CertificateBundleStatus, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	if in.InstalledTimestamp != nil {
 		in, out := &in.InstalledTimestamp, &out.InstalledTimestamp
@@ -441,6 +670,11 @@ func (in *ClusterDeploymentStatus) DeepCopyInto(out *ClusterDeploymentStatus) {
 		*out = new(corev1.LocalObjectReference)
 		**out = **in
 	}
+	if in.KubeadminPassword != nil {
+		in, out := &in.KubeadminPassword, &out.KubeadminPassword
+		*out = new(KubeadminPasswordStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -533,6 +767,16 @@ func (in *ClusterDeprovisionPlatform) DeepCopyInto(out *ClusterDeprovisionPlatfo
 		*out = new(GCPClusterDeprovision)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.OpenStack != nil {
+		in, out := &in.OpenStack, &out.OpenStack
+		*out = new(OpenStackClusterDeprovision)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VSphere != nil {
+		in, out := &in.VSphere, &out.VSphere
+		*out = new(VSphereClusterDeprovision)
+		**out = **in
+	}
 	return
 }
 
@@ -585,7 +829,7 @@ func (in *ClusterImageSet) DeepCopyInto(out *ClusterImageSet) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	out.Spec = in.Spec
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -607,6 +851,24 @@ func (in *ClusterImageSet) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterImageSetCondition) DeepCopyInto(out *ClusterImageSetCondition) {
+	*out = *in
+	in.LastProbeTime.DeepCopyInto(&out.LastProbeTime)
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterImageSetCondition.
+func (in *ClusterImageSetCondition) DeepCopy() *ClusterImageSetCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterImageSetCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterImageSetList) DeepCopyInto(out *ClusterImageSetList) {
 	*out = *in
@@ -675,6 +937,13 @@ func (in *ClusterImageSetSpec) DeepCopy() *ClusterImageSetSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ClusterImageSetStatus) DeepCopyInto(out *ClusterImageSetStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]ClusterImageSetCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -718,6 +987,11 @@ func (in *ClusterIngress) DeepCopy() *ClusterIngress {
 func (in *ClusterMetadata) DeepCopyInto(out *ClusterMetadata) {
 	*out = *in
 	out.AdminKubeconfigSecretRef = in.AdminKubeconfigSecretRef
+	if in.HiveManagedKubeconfigSecretRef != nil {
+		in, out := &in.HiveManagedKubeconfigSecretRef, &out.HiveManagedKubeconfigSecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
 	out.AdminPasswordSecretRef = in.AdminPasswordSecretRef
 	return
 }
@@ -756,27 +1030,27 @@ func (in *ClusterOperatorState) DeepCopy() *ClusterOperatorState {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterProvision) DeepCopyInto(out *ClusterProvision) {
+func (in *ClusterPool) DeepCopyInto(out *ClusterPool) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
+	out.Status = in.Status
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterProvision.
-func (in *ClusterProvision) DeepCopy() *ClusterProvision {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterPool.
+func (in *ClusterPool) DeepCopy() *ClusterPool {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterProvision)
+	out := new(ClusterPool)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterProvision) DeepCopyObject() runtime.Object {
+func (in *ClusterPool) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -784,31 +1058,13 @@ func (in *ClusterProvision) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterProvisionCondition) DeepCopyInto(out *ClusterProvisionCondition) {
-	*out = *in
-	in.LastProbeTime.DeepCopyInto(&out.LastProbeTime)
-	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
-	return
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterProvisionCondition.
-func (in *ClusterProvisionCondition) DeepCopy() *ClusterProvisionCondition {
-	if in == nil {
-		return nil
-	}
-	out := new(ClusterProvisionCondition)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterProvisionList) DeepCopyInto(out *ClusterProvisionList) {
+func (in *ClusterPoolList) DeepCopyInto(out *ClusterPoolList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	out.ListMeta = in.ListMeta
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]ClusterProvision, len(*in))
+		*out = make([]ClusterPool, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -816,18 +1072,18 @@ func (in *ClusterProvisionList) DeepCopyInto(out *ClusterProvisionList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterProvisionList.
-func (in *ClusterProvisionList) DeepCopy() *ClusterProvisionList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterPoolList.
+func (in *ClusterPoolList) DeepCopy() *ClusterPoolList {
 	if in == nil {
 		return nil
 	}
-	out := new(ClusterProvisionList)
+	out := new(ClusterPoolList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ClusterProvisionList) DeepCopyObject() runtime.Object {
+func (in *ClusterPoolList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -835,25 +1091,164 @@ func (in *ClusterProvisionList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ClusterProvisionSpec) DeepCopyInto(out *ClusterProvisionSpec) {
+func (in *ClusterPoolReference) DeepCopyInto(out *ClusterPoolReference) {
 	*out = *in
-	out.ClusterDeploymentRef = in.ClusterDeploymentRef
-	in.PodSpec.DeepCopyInto(&out.PodSpec)
-	if in.ClusterID != nil {
-		in, out := &in.ClusterID, &out.ClusterID
-		*out = new(string)
-		**out = **in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterPoolReference.
+func (in *ClusterPoolReference) DeepCopy() *ClusterPoolReference {
+	if in == nil {
+		return nil
 	}
-	if in.InfraID != nil {
-		in, out := &in.InfraID, &out.InfraID
-		*out = new(string)
+	out := new(ClusterPoolReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterPoolSpec) DeepCopyInto(out *ClusterPoolSpec) {
+	*out = *in
+	in.Platform.DeepCopyInto(&out.Platform)
+	if in.PullSecretRef != nil {
+		in, out := &in.PullSecretRef, &out.PullSecretRef
+		*out = new(corev1.LocalObjectReference)
 		**out = **in
 	}
-	if in.InstallLog != nil {
-		in, out := &in.InstallLog, &out.InstallLog
-		*out = new(string)
+	out.ImageSetRef = in.ImageSetRef
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterPoolSpec.
+func (in *ClusterPoolSpec) DeepCopy() *ClusterPoolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterPoolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterPoolStatus) DeepCopyInto(out *ClusterPoolStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterPoolStatus.
+func (in *ClusterPoolStatus) DeepCopy() *ClusterPoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterPoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterProvision) DeepCopyInto(out *ClusterProvision) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterProvision.
+func (in *ClusterProvision) DeepCopy() *ClusterProvision {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterProvision)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterProvision) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterProvisionCondition) DeepCopyInto(out *ClusterProvisionCondition) {
+	*out = *in
+	in.LastProbeTime.DeepCopyInto(&out.LastProbeTime)
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterProvisionCondition.
+func (in *ClusterProvisionCondition) DeepCopy() *ClusterProvisionCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterProvisionCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterProvisionList) DeepCopyInto(out *ClusterProvisionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterProvision, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterProvisionList.
+func (in *ClusterProvisionList) DeepCopy() *ClusterProvisionList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterProvisionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterProvisionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterProvisionSpec) DeepCopyInto(out *ClusterProvisionSpec) {
+	*out = *in
+	out.ClusterDeploymentRef = in.ClusterDeploymentRef
+	in.PodSpec.DeepCopyInto(&out.PodSpec)
+	if in.ClusterID != nil {
+		in, out := &in.ClusterID, &out.ClusterID
+		*out = new(string)
 		**out = **in
 	}
+	if in.InfraID != nil {
+		in, out := &in.InfraID, &out.InfraID
+		*out = new(string)
+		**out = **in
+	}
+	if in.InstallLog != nil {
+		in, out := &in.InstallLog, &out.InstallLog
+		*out = new(string)
+		**out = **in
+	}
+	if in.InstallLogArchive != nil {
+		in, out := &in.InstallLogArchive, &out.InstallLogArchive
+		*out = new(InstallLogArchiveReference)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Metadata != nil {
 		in, out := &in.Metadata, &out.Metadata
 		*out = new(runtime.RawExtension)
@@ -907,6 +1302,11 @@ func (in *ClusterProvisionStatus) DeepCopyInto(out *ClusterProvisionStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.InstallFailure != nil {
+		in, out := &in.InstallFailure, &out.InstallFailure
+		*out = new(InstallFailure)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1024,6 +1424,167 @@ func (in *ClusterStateStatus) DeepCopy() *ClusterStateStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterUpgrade) DeepCopyInto(out *ClusterUpgrade) {
+	*out = *in
+	if in.ImageSetRef != nil {
+		in, out := &in.ImageSetRef, &out.ImageSetRef
+		*out = new(ClusterImageSetReference)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterUpgrade.
+func (in *ClusterUpgrade) DeepCopy() *ClusterUpgrade {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterUpgrade)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterUpgradePolicy) DeepCopyInto(out *ClusterUpgradePolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterUpgradePolicy.
+func (in *ClusterUpgradePolicy) DeepCopy() *ClusterUpgradePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterUpgradePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterUpgradePolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterUpgradePolicyList) DeepCopyInto(out *ClusterUpgradePolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ClusterUpgradePolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterUpgradePolicyList.
+func (in *ClusterUpgradePolicyList) DeepCopy() *ClusterUpgradePolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterUpgradePolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ClusterUpgradePolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterUpgradePolicySpec) DeepCopyInto(out *ClusterUpgradePolicySpec) {
+	*out = *in
+	in.ClusterDeploymentSelector.DeepCopyInto(&out.ClusterDeploymentSelector)
+	out.ImageSetRef = in.ImageSetRef
+	if in.Waves != nil {
+		in, out := &in.Waves, &out.Waves
+		*out = make([]ClusterUpgradeWave, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterUpgradePolicySpec.
+func (in *ClusterUpgradePolicySpec) DeepCopy() *ClusterUpgradePolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterUpgradePolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterUpgradePolicyStatus) DeepCopyInto(out *ClusterUpgradePolicyStatus) {
+	*out = *in
+	if in.Waves != nil {
+		in, out := &in.Waves, &out.Waves
+		*out = make([]ClusterUpgradeWaveStatus, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterUpgradePolicyStatus.
+func (in *ClusterUpgradePolicyStatus) DeepCopy() *ClusterUpgradePolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterUpgradePolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterUpgradeWave) DeepCopyInto(out *ClusterUpgradeWave) {
+	*out = *in
+	in.ClusterDeploymentSelector.DeepCopyInto(&out.ClusterDeploymentSelector)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterUpgradeWave.
+func (in *ClusterUpgradeWave) DeepCopy() *ClusterUpgradeWave {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterUpgradeWave)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterUpgradeWaveStatus) DeepCopyInto(out *ClusterUpgradeWaveStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterUpgradeWaveStatus.
+func (in *ClusterUpgradeWaveStatus) DeepCopy() *ClusterUpgradeWaveStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterUpgradeWaveStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ControlPlaneAdditionalCertificate) DeepCopyInto(out *ControlPlaneAdditionalCertificate) {
 	*out = *in
@@ -1044,6 +1605,11 @@ func (in *ControlPlaneAdditionalCertificate) DeepCopy() *ControlPlaneAdditionalC
 func (in *ControlPlaneConfigSpec) DeepCopyInto(out *ControlPlaneConfigSpec) {
 	*out = *in
 	in.ServingCertificates.DeepCopyInto(&out.ServingCertificates)
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(ControlPlaneProxy)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1057,6 +1623,32 @@ func (in *ControlPlaneConfigSpec) DeepCopy() *ControlPlaneConfigSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControlPlaneProxy) DeepCopyInto(out *ControlPlaneProxy) {
+	*out = *in
+	if in.CredentialsSecretRef != nil {
+		in, out := &in.CredentialsSecretRef, &out.CredentialsSecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	if in.SSHBastion != nil {
+		in, out := &in.SSHBastion, &out.SSHBastion
+		*out = new(SSHBastion)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControlPlaneProxy.
+func (in *ControlPlaneProxy) DeepCopy() *ControlPlaneProxy {
+	if in == nil {
+		return nil
+	}
+	out := new(ControlPlaneProxy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ControlPlaneServingCertificateSpec) DeepCopyInto(out *ControlPlaneServingCertificateSpec) {
 	*out = *in
@@ -1078,6 +1670,75 @@ func (in *ControlPlaneServingCertificateSpec) DeepCopy() *ControlPlaneServingCer
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControllerConfig) DeepCopyInto(out *ControllerConfig) {
+	*out = *in
+	if in.ConcurrentReconciles != nil {
+		in, out := &in.ConcurrentReconciles, &out.ConcurrentReconciles
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ClientQPS != nil {
+		in, out := &in.ClientQPS, &out.ClientQPS
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ClientBurst != nil {
+		in, out := &in.ClientBurst, &out.ClientBurst
+		*out = new(int32)
+		**out = **in
+	}
+	if in.QueueQPS != nil {
+		in, out := &in.QueueQPS, &out.QueueQPS
+		*out = new(int32)
+		**out = **in
+	}
+	if in.QueueBurst != nil {
+		in, out := &in.QueueBurst, &out.QueueBurst
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControllerConfig.
+func (in *ControllerConfig) DeepCopy() *ControllerConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ControllerConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ControllersConfig) DeepCopyInto(out *ControllersConfig) {
+	*out = *in
+	if in.Default != nil {
+		in, out := &in.Default, &out.Default
+		*out = new(ControllerConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Controllers != nil {
+		in, out := &in.Controllers, &out.Controllers
+		*out = make([]SpecificControllerConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ControllersConfig.
+func (in *ControllersConfig) DeepCopy() *ControllersConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ControllersConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DNSZone) DeepCopyInto(out *DNSZone) {
 	*out = *in
@@ -1170,6 +1831,16 @@ func (in *DNSZoneSpec) DeepCopyInto(out *DNSZoneSpec) {
 		*out = new(GCPDNSZoneSpec)
 		**out = **in
 	}
+	if in.Azure != nil {
+		in, out := &in.Azure, &out.Azure
+		*out = new(AzureDNSZoneSpec)
+		**out = **in
+	}
+	if in.RFC2136 != nil {
+		in, out := &in.RFC2136, &out.RFC2136
+		*out = new(RFC2136DNSZoneSpec)
+		**out = **in
+	}
 	return
 }
 
@@ -1205,6 +1876,11 @@ func (in *DNSZoneStatus) DeepCopyInto(out *DNSZoneStatus) {
 		*out = new(GCPDNSZoneStatus)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Azure != nil {
+		in, out := &in.Azure, &out.Azure
+		*out = new(AzureDNSZoneStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]DNSZoneCondition, len(*in))
@@ -1290,18 +1966,224 @@ func (in *GCPDNSZoneStatus) DeepCopyInto(out *GCPDNSZoneStatus) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GCPDNSZoneStatus.
-func (in *GCPDNSZoneStatus) DeepCopy() *GCPDNSZoneStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GCPDNSZoneStatus.
+func (in *GCPDNSZoneStatus) DeepCopy() *GCPDNSZoneStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GCPDNSZoneStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HiveConfig) DeepCopyInto(out *HiveConfig) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	out.Status = in.Status
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HiveConfig.
+func (in *HiveConfig) DeepCopy() *HiveConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(HiveConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HiveConfig) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HiveConfigList) DeepCopyInto(out *HiveConfigList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	out.ListMeta = in.ListMeta
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]HiveConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HiveConfigList.
+func (in *HiveConfigList) DeepCopy() *HiveConfigList {
+	if in == nil {
+		return nil
+	}
+	out := new(HiveConfigList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *HiveConfigList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HiveConfigSpec) DeepCopyInto(out *HiveConfigSpec) {
+	*out = *in
+	if in.ManagedDomains != nil {
+		in, out := &in.ManagedDomains, &out.ManagedDomains
+		*out = make([]ManageDNSConfig, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.AdditionalCertificateAuthoritiesSecretRef != nil {
+		in, out := &in.AdditionalCertificateAuthoritiesSecretRef, &out.AdditionalCertificateAuthoritiesSecretRef
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.GlobalPullSecretRef != nil {
+		in, out := &in.GlobalPullSecretRef, &out.GlobalPullSecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	in.Backup.DeepCopyInto(&out.Backup)
+	out.FailedProvisionConfig = in.FailedProvisionConfig
+	if in.MaintenanceMode != nil {
+		in, out := &in.MaintenanceMode, &out.MaintenanceMode
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ControllersConfig != nil {
+		in, out := &in.ControllersConfig, &out.ControllersConfig
+		*out = new(ControllersConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.DeprovisionsDisabled != nil {
+		in, out := &in.DeprovisionsDisabled, &out.DeprovisionsDisabled
+		*out = new(bool)
+		**out = **in
+	}
+	if in.ACME != nil {
+		in, out := &in.ACME, &out.ACME
+		*out = new(ACMEConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CertificateExpiryWarningWindow != nil {
+		in, out := &in.CertificateExpiryWarningWindow, &out.CertificateExpiryWarningWindow
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	in.HiveManagedKubeconfig.DeepCopyInto(&out.HiveManagedKubeconfig)
+	if in.ReleaseImagePolicy != nil {
+		in, out := &in.ReleaseImagePolicy, &out.ReleaseImagePolicy
+		*out = new(ReleaseImagePolicy)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InstallLogArchive != nil {
+		in, out := &in.InstallLogArchive, &out.InstallLogArchive
+		*out = new(InstallLogArchiveConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HiveConfigSpec.
+func (in *HiveConfigSpec) DeepCopy() *HiveConfigSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(HiveConfigSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HiveConfigStatus) DeepCopyInto(out *HiveConfigStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HiveConfigStatus.
+func (in *HiveConfigStatus) DeepCopy() *HiveConfigStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(HiveConfigStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *HiveManagedKubeconfigConfig) DeepCopyInto(out *HiveManagedKubeconfigConfig) {
+	*out = *in
+	if in.RotationInterval != nil {
+		in, out := &in.RotationInterval, &out.RotationInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HiveManagedKubeconfigConfig.
+func (in *HiveManagedKubeconfigConfig) DeepCopy() *HiveManagedKubeconfigConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(HiveManagedKubeconfigConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IdentityProviderStatus) DeepCopyInto(out *IdentityProviderStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IdentityProviderStatus.
+func (in *IdentityProviderStatus) DeepCopy() *IdentityProviderStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(IdentityProviderStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstallFailure) DeepCopyInto(out *InstallFailure) {
+	*out = *in
+	if in.FailingOperators != nil {
+		in, out := &in.FailingOperators, &out.FailingOperators
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallFailure.
+func (in *InstallFailure) DeepCopy() *InstallFailure {
 	if in == nil {
 		return nil
 	}
-	out := new(GCPDNSZoneStatus)
+	out := new(InstallFailure)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HiveConfig) DeepCopyInto(out *HiveConfig) {
+func (in *InstallFailureRule) DeepCopyInto(out *InstallFailureRule) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -1310,18 +2192,18 @@ func (in *HiveConfig) DeepCopyInto(out *HiveConfig) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HiveConfig.
-func (in *HiveConfig) DeepCopy() *HiveConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallFailureRule.
+func (in *InstallFailureRule) DeepCopy() *InstallFailureRule {
 	if in == nil {
 		return nil
 	}
-	out := new(HiveConfig)
+	out := new(InstallFailureRule)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *HiveConfig) DeepCopyObject() runtime.Object {
+func (in *InstallFailureRule) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1329,13 +2211,13 @@ func (in *HiveConfig) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HiveConfigList) DeepCopyInto(out *HiveConfigList) {
+func (in *InstallFailureRuleList) DeepCopyInto(out *InstallFailureRuleList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	out.ListMeta = in.ListMeta
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]HiveConfig, len(*in))
+		*out = make([]InstallFailureRule, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
@@ -1343,18 +2225,18 @@ func (in *HiveConfigList) DeepCopyInto(out *HiveConfigList) {
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HiveConfigList.
-func (in *HiveConfigList) DeepCopy() *HiveConfigList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallFailureRuleList.
+func (in *InstallFailureRuleList) DeepCopy() *InstallFailureRuleList {
 	if in == nil {
 		return nil
 	}
-	out := new(HiveConfigList)
+	out := new(InstallFailureRuleList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *HiveConfigList) DeepCopyObject() runtime.Object {
+func (in *InstallFailureRuleList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1362,78 +2244,136 @@ func (in *HiveConfigList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HiveConfigSpec) DeepCopyInto(out *HiveConfigSpec) {
+func (in *InstallFailureRuleSpec) DeepCopyInto(out *InstallFailureRuleSpec) {
 	*out = *in
-	if in.ManagedDomains != nil {
-		in, out := &in.ManagedDomains, &out.ManagedDomains
-		*out = make([]ManageDNSConfig, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.AdditionalCertificateAuthoritiesSecretRef != nil {
-		in, out := &in.AdditionalCertificateAuthoritiesSecretRef, &out.AdditionalCertificateAuthoritiesSecretRef
-		*out = make([]corev1.LocalObjectReference, len(*in))
+	if in.Platforms != nil {
+		in, out := &in.Platforms, &out.Platforms
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
-	if in.GlobalPullSecretRef != nil {
-		in, out := &in.GlobalPullSecretRef, &out.GlobalPullSecretRef
-		*out = new(corev1.LocalObjectReference)
-		**out = **in
+	if in.SearchRegexStrings != nil {
+		in, out := &in.SearchRegexStrings, &out.SearchRegexStrings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
 	}
-	in.Backup.DeepCopyInto(&out.Backup)
-	out.FailedProvisionConfig = in.FailedProvisionConfig
-	if in.MaintenanceMode != nil {
-		in, out := &in.MaintenanceMode, &out.MaintenanceMode
+	if in.Retryable != nil {
+		in, out := &in.Retryable, &out.Retryable
 		*out = new(bool)
 		**out = **in
 	}
-	if in.DeprovisionsDisabled != nil {
-		in, out := &in.DeprovisionsDisabled, &out.DeprovisionsDisabled
-		*out = new(bool)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallFailureRuleSpec.
+func (in *InstallFailureRuleSpec) DeepCopy() *InstallFailureRuleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InstallFailureRuleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstallFailureRuleStatus) DeepCopyInto(out *InstallFailureRuleStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallFailureRuleStatus.
+func (in *InstallFailureRuleStatus) DeepCopy() *InstallFailureRuleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(InstallFailureRuleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InstallLogArchiveConfig) DeepCopyInto(out *InstallLogArchiveConfig) {
+	*out = *in
+	out.CredentialsSecretRef = in.CredentialsSecretRef
+	if in.Retention != nil {
+		in, out := &in.Retention, &out.Retention
+		*out = new(metav1.Duration)
 		**out = **in
 	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HiveConfigSpec.
-func (in *HiveConfigSpec) DeepCopy() *HiveConfigSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallLogArchiveConfig.
+func (in *InstallLogArchiveConfig) DeepCopy() *InstallLogArchiveConfig {
 	if in == nil {
 		return nil
 	}
-	out := new(HiveConfigSpec)
+	out := new(InstallLogArchiveConfig)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *HiveConfigStatus) DeepCopyInto(out *HiveConfigStatus) {
+func (in *InstallLogArchiveReference) DeepCopyInto(out *InstallLogArchiveReference) {
 	*out = *in
+	if in.Objects != nil {
+		in, out := &in.Objects, &out.Objects
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HiveConfigStatus.
-func (in *HiveConfigStatus) DeepCopy() *HiveConfigStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InstallLogArchiveReference.
+func (in *InstallLogArchiveReference) DeepCopy() *InstallLogArchiveReference {
 	if in == nil {
 		return nil
 	}
-	out := new(HiveConfigStatus)
+	out := new(InstallLogArchiveReference)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *IdentityProviderStatus) DeepCopyInto(out *IdentityProviderStatus) {
+func (in *KubeadminPasswordSpec) DeepCopyInto(out *KubeadminPasswordSpec) {
 	*out = *in
+	if in.RotationInterval != nil {
+		in, out := &in.RotationInterval, &out.RotationInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RotationRequestedAt != nil {
+		in, out := &in.RotationRequestedAt, &out.RotationRequestedAt
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IdentityProviderStatus.
-func (in *IdentityProviderStatus) DeepCopy() *IdentityProviderStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeadminPasswordSpec.
+func (in *KubeadminPasswordSpec) DeepCopy() *KubeadminPasswordSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(IdentityProviderStatus)
+	out := new(KubeadminPasswordSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KubeadminPasswordStatus) DeepCopyInto(out *KubeadminPasswordStatus) {
+	*out = *in
+	if in.LastRotationTime != nil {
+		in, out := &in.LastRotationTime, &out.LastRotationTime
+		*out = (*in).DeepCopy()
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeadminPasswordStatus.
+func (in *KubeadminPasswordStatus) DeepCopy() *KubeadminPasswordStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(KubeadminPasswordStatus)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -1644,6 +2584,16 @@ func (in *MachinePoolPlatform) DeepCopyInto(out *MachinePoolPlatform) {
 		*out = new(gcp.MachinePool)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.OpenStack != nil {
+		in, out := &in.OpenStack, &out.OpenStack
+		*out = new(openstack.MachinePool)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.VSphere != nil {
+		in, out := &in.VSphere, &out.VSphere
+		*out = new(vsphere.MachinePool)
+		**out = **in
+	}
 	return
 }
 
@@ -1743,6 +2693,28 @@ func (in *MachineSetStatus) DeepCopy() *MachineSetStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MaintenanceWindow) DeepCopyInto(out *MaintenanceWindow) {
+	*out = *in
+	if in.Days != nil {
+		in, out := &in.Days, &out.Days
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.Duration = in.Duration
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MaintenanceWindow.
+func (in *MaintenanceWindow) DeepCopy() *MaintenanceWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(MaintenanceWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManageDNSAWSConfig) DeepCopyInto(out *ManageDNSAWSConfig) {
 	*out = *in
@@ -1760,6 +2732,23 @@ func (in *ManageDNSAWSConfig) DeepCopy() *ManageDNSAWSConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManageDNSAzureConfig) DeepCopyInto(out *ManageDNSAzureConfig) {
+	*out = *in
+	out.CredentialsSecretRef = in.CredentialsSecretRef
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManageDNSAzureConfig.
+func (in *ManageDNSAzureConfig) DeepCopy() *ManageDNSAzureConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ManageDNSAzureConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManageDNSConfig) DeepCopyInto(out *ManageDNSConfig) {
 	*out = *in
@@ -1778,6 +2767,16 @@ func (in *ManageDNSConfig) DeepCopyInto(out *ManageDNSConfig) {
 		*out = new(ManageDNSGCPConfig)
 		**out = **in
 	}
+	if in.Azure != nil {
+		in, out := &in.Azure, &out.Azure
+		*out = new(ManageDNSAzureConfig)
+		**out = **in
+	}
+	if in.RFC2136 != nil {
+		in, out := &in.RFC2136, &out.RFC2136
+		*out = new(ManageDNSRFC2136Config)
+		**out = **in
+	}
 	return
 }
 
@@ -1808,6 +2807,44 @@ func (in *ManageDNSGCPConfig) DeepCopy() *ManageDNSGCPConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ManageDNSRFC2136Config) DeepCopyInto(out *ManageDNSRFC2136Config) {
+	*out = *in
+	out.TSIGKeySecretRef = in.TSIGKeySecretRef
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ManageDNSRFC2136Config.
+func (in *ManageDNSRFC2136Config) DeepCopy() *ManageDNSRFC2136Config {
+	if in == nil {
+		return nil
+	}
+	out := new(ManageDNSRFC2136Config)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenStackClusterDeprovision) DeepCopyInto(out *OpenStackClusterDeprovision) {
+	*out = *in
+	if in.CredentialsSecretRef != nil {
+		in, out := &in.CredentialsSecretRef, &out.CredentialsSecretRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenStackClusterDeprovision.
+func (in *OpenStackClusterDeprovision) DeepCopy() *OpenStackClusterDeprovision {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenStackClusterDeprovision)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Platform) DeepCopyInto(out *Platform) {
 	*out = *in
@@ -1831,6 +2868,16 @@ func (in *Platform) DeepCopyInto(out *Platform) {
 		*out = new(baremetal.Platform)
 		**out = **in
 	}
+	if in.OpenStack != nil {
+		in, out := &in.OpenStack, &out.OpenStack
+		*out = new(openstack.Platform)
+		**out = **in
+	}
+	if in.VSphere != nil {
+		in, out := &in.VSphere, &out.VSphere
+		*out = new(vsphere.Platform)
+		**out = **in
+	}
 	return
 }
 
@@ -1888,6 +2935,93 @@ func (in *Provisioning) DeepCopy() *Provisioning {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RFC2136DNSZoneSpec) DeepCopyInto(out *RFC2136DNSZoneSpec) {
+	*out = *in
+	out.TSIGKeySecretRef = in.TSIGKeySecretRef
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RFC2136DNSZoneSpec.
+func (in *RFC2136DNSZoneSpec) DeepCopy() *RFC2136DNSZoneSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RFC2136DNSZoneSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReleaseImagePolicy) DeepCopyInto(out *ReleaseImagePolicy) {
+	*out = *in
+	if in.AllowedRegistries != nil {
+		in, out := &in.AllowedRegistries, &out.AllowedRegistries
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SignatureVerification != nil {
+		in, out := &in.SignatureVerification, &out.SignatureVerification
+		*out = new(ReleaseImageSignatureVerification)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReleaseImagePolicy.
+func (in *ReleaseImagePolicy) DeepCopy() *ReleaseImagePolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ReleaseImagePolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReleaseImageSignatureVerification) DeepCopyInto(out *ReleaseImageSignatureVerification) {
+	*out = *in
+	out.PublicKeysSecretRef = in.PublicKeysSecretRef
+	if in.SignatureStores != nil {
+		in, out := &in.SignatureStores, &out.SignatureStores
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReleaseImageSignatureVerification.
+func (in *ReleaseImageSignatureVerification) DeepCopy() *ReleaseImageSignatureVerification {
+	if in == nil {
+		return nil
+	}
+	out := new(ReleaseImageSignatureVerification)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SSHBastion) DeepCopyInto(out *SSHBastion) {
+	*out = *in
+	out.PrivateKeySecretRef = in.PrivateKeySecretRef
+	if in.KnownHosts != nil {
+		in, out := &in.KnownHosts, &out.KnownHosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SSHBastion.
+func (in *SSHBastion) DeepCopy() *SSHBastion {
+	if in == nil {
+		return nil
+	}
+	out := new(SSHBastion)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SecretMapping) DeepCopyInto(out *SecretMapping) {
 	*out = *in
@@ -2112,6 +3246,23 @@ func (in *SelectorSyncSetStatus) DeepCopy() *SelectorSyncSetStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpecificControllerConfig) DeepCopyInto(out *SpecificControllerConfig) {
+	*out = *in
+	in.Config.DeepCopyInto(&out.Config)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpecificControllerConfig.
+func (in *SpecificControllerConfig) DeepCopy() *SpecificControllerConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(SpecificControllerConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SyncCondition) DeepCopyInto(out *SyncCondition) {
 	*out = *in
@@ -2290,6 +3441,11 @@ func (in *SyncSetCommonSpec) DeepCopyInto(out *SyncSetCommonSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.TemplateParametersRef != nil {
+		in, out := &in.TemplateParametersRef, &out.TemplateParametersRef
+		*out = new(corev1.LocalObjectReference)
+		**out = **in
+	}
 	if in.Patches != nil {
 		in, out := &in.Patches, &out.Patches
 		*out = make([]SyncObjectPatch, len(*in))
@@ -2583,6 +3739,24 @@ func (in *SyncStatus) DeepCopy() *SyncStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *VSphereClusterDeprovision) DeepCopyInto(out *VSphereClusterDeprovision) {
+	*out = *in
+	out.CredentialsSecretRef = in.CredentialsSecretRef
+	out.CertificatesSecretRef = in.CertificatesSecretRef
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new VSphereClusterDeprovision.
+func (in *VSphereClusterDeprovision) DeepCopy() *VSphereClusterDeprovision {
+	if in == nil {
+		return nil
+	}
+	out := new(VSphereClusterDeprovision)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VeleroBackupConfig) DeepCopyInto(out *VeleroBackupConfig) {
 	*out = *in
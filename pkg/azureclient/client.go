@@ -3,10 +3,15 @@ package azureclient
 import (
 	"context"
 	"encoding/json"
+	"io/ioutil"
+	"net/http"
 
 	"github.com/Azure/azure-sdk-for-go/services/compute/mgmt/2018-10-01/compute"
+	"github.com/Azure/azure-sdk-for-go/services/dns/mgmt/2017-10-01/dns"
+	"github.com/Azure/go-autorest/autorest"
 	"github.com/Azure/go-autorest/autorest/azure"
 	"github.com/Azure/go-autorest/autorest/azure/auth"
+	"github.com/Azure/go-autorest/autorest/to"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 
@@ -18,6 +23,17 @@ import (
 // Client is a wrapper object for actual Azure libraries to allow for easier mocking/testing.
 type Client interface {
 	ListResourceSKUs(ctx context.Context) (ResourceSKUsPage, error)
+
+	// Zones
+	GetZone(ctx context.Context, resourceGroupName string, zone string) (dns.Zone, error)
+	CreateOrUpdateZone(ctx context.Context, resourceGroupName string, zone string) (dns.Zone, error)
+	DeleteZone(ctx context.Context, resourceGroupName string, zone string) error
+
+	// RecordSets
+	ListRecordSetsByZone(ctx context.Context, resourceGroupName string, zone string, suffix string) (RecordSetPage, error)
+	GetRecordSet(ctx context.Context, resourceGroupName string, zone string, recordSetName string, recordType dns.RecordType) (dns.RecordSet, error)
+	CreateOrUpdateRecordSet(ctx context.Context, resourceGroupName string, zone string, recordSetName string, recordType dns.RecordType, recordSet dns.RecordSet) (dns.RecordSet, error)
+	DeleteRecordSet(ctx context.Context, resourceGroupName string, zone string, recordSetName string, recordType dns.RecordType) error
 }
 
 // ResourceSKUsPage is a page of results from listing resource SKUs.
@@ -27,16 +43,27 @@ type ResourceSKUsPage interface {
 	Values() []compute.ResourceSku
 }
 
+// RecordSetPage is a page of results from listing record sets.
+type RecordSetPage interface {
+	NextWithContext(ctx context.Context) error
+	NotDone() bool
+	Values() []dns.RecordSet
+}
+
 type azureClient struct {
 	config         auth.ClientCredentialsConfig
 	subscriptionID string
 }
 
-func (c *azureClient) ListResourceSKUs(ctx context.Context) (ResourceSKUsPage, error) {
-	skusClient := compute.NewResourceSkusClient(c.subscriptionID)
+func (c *azureClient) authorizer() (autorest.Authorizer, error) {
 	config := c.config
 	config.Resource = azure.PublicCloud.ResourceManagerEndpoint
-	authorizer, err := config.Authorizer()
+	return config.Authorizer()
+}
+
+func (c *azureClient) ListResourceSKUs(ctx context.Context) (ResourceSKUsPage, error) {
+	skusClient := compute.NewResourceSkusClient(c.subscriptionID)
+	authorizer, err := c.authorizer()
 	if err != nil {
 		return nil, err
 	}
@@ -45,12 +72,109 @@ func (c *azureClient) ListResourceSKUs(ctx context.Context) (ResourceSKUsPage, e
 	return &page, err
 }
 
+func (c *azureClient) zonesClient() (dns.ZonesClient, error) {
+	zonesClient := dns.NewZonesClient(c.subscriptionID)
+	authorizer, err := c.authorizer()
+	if err != nil {
+		return zonesClient, err
+	}
+	zonesClient.Authorizer = authorizer
+	return zonesClient, nil
+}
+
+func (c *azureClient) recordSetsClient() (dns.RecordSetsClient, error) {
+	recordSetsClient := dns.NewRecordSetsClient(c.subscriptionID)
+	authorizer, err := c.authorizer()
+	if err != nil {
+		return recordSetsClient, err
+	}
+	recordSetsClient.Authorizer = authorizer
+	return recordSetsClient, nil
+}
+
+func (c *azureClient) GetZone(ctx context.Context, resourceGroupName string, zone string) (dns.Zone, error) {
+	zonesClient, err := c.zonesClient()
+	if err != nil {
+		return dns.Zone{}, err
+	}
+	return zonesClient.Get(ctx, resourceGroupName, zone)
+}
+
+func (c *azureClient) CreateOrUpdateZone(ctx context.Context, resourceGroupName string, zone string) (dns.Zone, error) {
+	zonesClient, err := c.zonesClient()
+	if err != nil {
+		return dns.Zone{}, err
+	}
+	return zonesClient.CreateOrUpdate(ctx, resourceGroupName, zone, dns.Zone{
+		// Azure DNS zones are global resources.
+		Location: to.StringPtr("global"),
+	}, "", "")
+}
+
+func (c *azureClient) DeleteZone(ctx context.Context, resourceGroupName string, zone string) error {
+	zonesClient, err := c.zonesClient()
+	if err != nil {
+		return err
+	}
+	future, err := zonesClient.Delete(ctx, resourceGroupName, zone, "")
+	if err != nil {
+		return err
+	}
+	return future.WaitForCompletionRef(ctx, zonesClient.Client)
+}
+
+func (c *azureClient) ListRecordSetsByZone(ctx context.Context, resourceGroupName string, zone string, suffix string) (RecordSetPage, error) {
+	recordSetsClient, err := c.recordSetsClient()
+	if err != nil {
+		return nil, err
+	}
+	page, err := recordSetsClient.ListByDNSZone(ctx, resourceGroupName, zone, nil, suffix)
+	return &page, err
+}
+
+func (c *azureClient) GetRecordSet(ctx context.Context, resourceGroupName string, zone string, recordSetName string, recordType dns.RecordType) (dns.RecordSet, error) {
+	recordSetsClient, err := c.recordSetsClient()
+	if err != nil {
+		return dns.RecordSet{}, err
+	}
+	return recordSetsClient.Get(ctx, resourceGroupName, zone, recordSetName, recordType)
+}
+
+func (c *azureClient) CreateOrUpdateRecordSet(ctx context.Context, resourceGroupName string, zone string, recordSetName string, recordType dns.RecordType, recordSet dns.RecordSet) (dns.RecordSet, error) {
+	recordSetsClient, err := c.recordSetsClient()
+	if err != nil {
+		return dns.RecordSet{}, err
+	}
+	return recordSetsClient.CreateOrUpdate(ctx, resourceGroupName, zone, recordSetName, recordType, recordSet, "", "")
+}
+
+func (c *azureClient) DeleteRecordSet(ctx context.Context, resourceGroupName string, zone string, recordSetName string, recordType dns.RecordType) error {
+	recordSetsClient, err := c.recordSetsClient()
+	if err != nil {
+		return err
+	}
+	_, err = recordSetsClient.Delete(ctx, resourceGroupName, zone, recordSetName, recordType, "")
+	return err
+}
+
+// IsNotFound returns whether the given error is an Azure API error for a resource that does not exist.
+func IsNotFound(err error) bool {
+	detailedErr, ok := err.(autorest.DetailedError)
+	return ok && detailedErr.StatusCode == http.StatusNotFound
+}
+
 // NewClientFromSecret creates our client wrapper object for interacting with Azure. The Azure creds are read from the
 // specified secret.
 func NewClientFromSecret(secret *corev1.Secret) (Client, error) {
 	return newClient(authJSONFromSecretSource(secret))
 }
 
+// NewClientFromFile creates our client wrapper object for interacting with Azure. The Azure creds are read from the
+// specified file.
+func NewClientFromFile(filename string) (Client, error) {
+	return newClient(authJSONFromFileSource(filename))
+}
+
 func newClient(authJSONSource func() ([]byte, error)) (*azureClient, error) {
 	authJSON, err := authJSONSource()
 	if err != nil {
@@ -91,3 +215,9 @@ func authJSONFromSecretSource(secret *corev1.Secret) func() ([]byte, error) {
 		return authJSON, nil
 	}
 }
+
+func authJSONFromFileSource(filename string) func() ([]byte, error) {
+	return func() ([]byte, error) {
+		return ioutil.ReadFile(filename)
+	}
+}
@@ -16,6 +16,10 @@ func (c *FakeHiveV1) Checkpoints(namespace string) v1.CheckpointInterface {
 	return &FakeCheckpoints{c, namespace}
 }
 
+func (c *FakeHiveV1) ClusterClaims(namespace string) v1.ClusterClaimInterface {
+	return &FakeClusterClaims{c, namespace}
+}
+
 func (c *FakeHiveV1) ClusterDeployments(namespace string) v1.ClusterDeploymentInterface {
 	return &FakeClusterDeployments{c, namespace}
 }
@@ -28,6 +32,10 @@ func (c *FakeHiveV1) ClusterImageSets() v1.ClusterImageSetInterface {
 	return &FakeClusterImageSets{c}
 }
 
+func (c *FakeHiveV1) ClusterPools(namespace string) v1.ClusterPoolInterface {
+	return &FakeClusterPools{c, namespace}
+}
+
 func (c *FakeHiveV1) ClusterProvisions(namespace string) v1.ClusterProvisionInterface {
 	return &FakeClusterProvisions{c, namespace}
 }
@@ -36,6 +44,10 @@ func (c *FakeHiveV1) ClusterStates(namespace string) v1.ClusterStateInterface {
 	return &FakeClusterStates{c, namespace}
 }
 
+func (c *FakeHiveV1) ClusterUpgradePolicies() v1.ClusterUpgradePolicyInterface {
+	return &FakeClusterUpgradePolicies{c}
+}
+
 func (c *FakeHiveV1) DNSZones(namespace string) v1.DNSZoneInterface {
 	return &FakeDNSZones{c, namespace}
 }
@@ -44,6 +56,10 @@ func (c *FakeHiveV1) HiveConfigs() v1.HiveConfigInterface {
 	return &FakeHiveConfigs{c}
 }
 
+func (c *FakeHiveV1) InstallFailureRules() v1.InstallFailureRuleInterface {
+	return &FakeInstallFailureRules{c}
+}
+
 func (c *FakeHiveV1) MachinePools(namespace string) v1.MachinePoolInterface {
 	return &FakeMachinePools{c, namespace}
 }
@@ -4,20 +4,28 @@ package v1
 
 type CheckpointExpansion interface{}
 
+type ClusterClaimExpansion interface{}
+
 type ClusterDeploymentExpansion interface{}
 
 type ClusterDeprovisionExpansion interface{}
 
 type ClusterImageSetExpansion interface{}
 
+type ClusterPoolExpansion interface{}
+
 type ClusterProvisionExpansion interface{}
 
 type ClusterStateExpansion interface{}
 
+type ClusterUpgradePolicyExpansion interface{}
+
 type DNSZoneExpansion interface{}
 
 type HiveConfigExpansion interface{}
 
+type InstallFailureRuleExpansion interface{}
+
 type MachinePoolExpansion interface{}
 
 type MachinePoolNameLeaseExpansion interface{}
@@ -12,13 +12,17 @@ import (
 type HiveV1Interface interface {
 	RESTClient() rest.Interface
 	CheckpointsGetter
+	ClusterClaimsGetter
 	ClusterDeploymentsGetter
 	ClusterDeprovisionsGetter
 	ClusterImageSetsGetter
+	ClusterPoolsGetter
 	ClusterProvisionsGetter
 	ClusterStatesGetter
+	ClusterUpgradePoliciesGetter
 	DNSZonesGetter
 	HiveConfigsGetter
+	InstallFailureRulesGetter
 	MachinePoolsGetter
 	MachinePoolNameLeasesGetter
 	SelectorSyncIdentityProvidersGetter
@@ -37,6 +41,10 @@ func (c *HiveV1Client) Checkpoints(namespace string) CheckpointInterface {
 	return newCheckpoints(c, namespace)
 }
 
+func (c *HiveV1Client) ClusterClaims(namespace string) ClusterClaimInterface {
+	return newClusterClaims(c, namespace)
+}
+
 func (c *HiveV1Client) ClusterDeployments(namespace string) ClusterDeploymentInterface {
 	return newClusterDeployments(c, namespace)
 }
@@ -49,6 +57,10 @@ func (c *HiveV1Client) ClusterImageSets() ClusterImageSetInterface {
 	return newClusterImageSets(c)
 }
 
+func (c *HiveV1Client) ClusterPools(namespace string) ClusterPoolInterface {
+	return newClusterPools(c, namespace)
+}
+
 func (c *HiveV1Client) ClusterProvisions(namespace string) ClusterProvisionInterface {
 	return newClusterProvisions(c, namespace)
 }
@@ -57,6 +69,10 @@ func (c *HiveV1Client) ClusterStates(namespace string) ClusterStateInterface {
 	return newClusterStates(c, namespace)
 }
 
+func (c *HiveV1Client) ClusterUpgradePolicies() ClusterUpgradePolicyInterface {
+	return newClusterUpgradePolicies(c)
+}
+
 func (c *HiveV1Client) DNSZones(namespace string) DNSZoneInterface {
 	return newDNSZones(c, namespace)
 }
@@ -65,6 +81,10 @@ func (c *HiveV1Client) HiveConfigs() HiveConfigInterface {
 	return newHiveConfigs(c)
 }
 
+func (c *HiveV1Client) InstallFailureRules() InstallFailureRuleInterface {
+	return newInstallFailureRules(c)
+}
+
 func (c *HiveV1Client) MachinePools(namespace string) MachinePoolInterface {
 	return newMachinePools(c, namespace)
 }
@@ -1,6 +1,8 @@
 package constants
 
 import (
+	"strings"
+
 	apihelpers "github.com/openshift/hive/pkg/apis/helpers"
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
 )
@@ -18,6 +20,118 @@ const (
 	// MinBackupPeriodSecondsEnvVar is the name of the environment variable used to tell the controller manager the minimum period of time between backups.
 	MinBackupPeriodSecondsEnvVar = "HIVE_MIN_BACKUP_PERIOD_SECONDS"
 
+	// ControllersShardEnvVar is the name of the environment variable used to tell the controller manager which shard of
+	// the hive controllers it runs.
+	ControllersShardEnvVar = "HIVE_CONTROLLERS_SHARD"
+
+	// ControllersShardCountEnvVar is the name of the environment variable used to tell the controller manager the
+	// number of shards the hive controllers are split into.
+	ControllersShardCountEnvVar = "HIVE_CONTROLLERS_SHARD_COUNT"
+
+	// ACMEDirectoryURLEnvVar is the name of the environment variable used to tell the controller manager the
+	// directory URL of the ACME server generating certificate bundles.
+	ACMEDirectoryURLEnvVar = "HIVE_ACME_DIRECTORY_URL"
+
+	// ACMEEmailEnvVar is the name of the environment variable used to tell the controller manager the contact email
+	// of the ACME account.
+	ACMEEmailEnvVar = "HIVE_ACME_EMAIL"
+
+	// ACMEAccountKeySecretEnvVar is the name of the environment variable used to tell the controller manager the
+	// name of the secret in the hive namespace holding the private key of the ACME account.
+	ACMEAccountKeySecretEnvVar = "HIVE_ACME_ACCOUNT_KEY_SECRET"
+
+	// ACMECASecretEnvVar is the name of the environment variable used to tell the controller manager the name of
+	// the secret in the hive namespace holding the certificate authority of the ACME server.
+	ACMECASecretEnvVar = "HIVE_ACME_CA_SECRET"
+
+	// ACMERenewBeforeEnvVar is the name of the environment variable used to tell the controller manager how long
+	// before their expiry generated certificates are renewed.
+	ACMERenewBeforeEnvVar = "HIVE_ACME_RENEW_BEFORE"
+
+	// CertificateExpiryWarningWindowEnvVar is the name of the environment variable used to tell the controller
+	// manager how long before their expiry certificates are reported as expiring.
+	CertificateExpiryWarningWindowEnvVar = "HIVE_CERTIFICATE_EXPIRY_WARNING_WINDOW"
+
+	// HiveManagedKubeconfigEnvVar is the name of the environment variable used to tell the controller manager to mint
+	// its own kubeconfigs for installed clusters.
+	HiveManagedKubeconfigEnvVar = "HIVE_MANAGED_KUBECONFIG"
+
+	// HiveManagedKubeconfigRotationIntervalEnvVar is the name of the environment variable used to tell the controller
+	// manager how often the client certificates of the kubeconfigs minted by Hive are rotated.
+	HiveManagedKubeconfigRotationIntervalEnvVar = "HIVE_MANAGED_KUBECONFIG_ROTATION_INTERVAL"
+
+	// ReleaseImageAllowedRegistriesEnvVar is the name of the environment variable used to tell the controller manager
+	// and the admission webhooks the comma-separated registries from which release images can be pulled.
+	ReleaseImageAllowedRegistriesEnvVar = "HIVE_RELEASE_IMAGE_ALLOWED_REGISTRIES"
+
+	// ReleaseImageRequireDigestEnvVar is the name of the environment variable used to tell the controller manager and
+	// the admission webhooks that release images must be referenced by digest.
+	ReleaseImageRequireDigestEnvVar = "HIVE_RELEASE_IMAGE_REQUIRE_DIGEST"
+
+	// ReleaseImagePublicKeysSecretEnvVar is the name of the environment variable used to tell the controller manager
+	// and the admission webhooks the name of the secret in the hive namespace holding the GPG public keys trusted to
+	// sign release images.
+	ReleaseImagePublicKeysSecretEnvVar = "HIVE_RELEASE_IMAGE_PUBLIC_KEYS_SECRET"
+
+	// ReleaseImageSignatureStoresEnvVar is the name of the environment variable used to tell the controller manager
+	// and the admission webhooks the comma-separated URLs of the stores serving the signatures of release images.
+	ReleaseImageSignatureStoresEnvVar = "HIVE_RELEASE_IMAGE_SIGNATURE_STORES"
+
+	// InstallLogArchiveEndpointEnvVar is the name of the environment variable used to tell the controller manager
+	// the endpoint of the object store in which install logs are archived.
+	InstallLogArchiveEndpointEnvVar = "HIVE_INSTALL_LOG_ARCHIVE_ENDPOINT"
+
+	// InstallLogArchiveRegionEnvVar is the name of the environment variable used to tell the controller manager the
+	// region of the bucket in which install logs are archived.
+	InstallLogArchiveRegionEnvVar = "HIVE_INSTALL_LOG_ARCHIVE_REGION"
+
+	// InstallLogArchiveBucketEnvVar is the name of the environment variable used to tell the controller manager the
+	// bucket in which install logs are archived.
+	InstallLogArchiveBucketEnvVar = "HIVE_INSTALL_LOG_ARCHIVE_BUCKET"
+
+	// InstallLogArchivePrefixEnvVar is the name of the environment variable used to tell the controller manager the
+	// prefix of the keys of the archived install logs.
+	InstallLogArchivePrefixEnvVar = "HIVE_INSTALL_LOG_ARCHIVE_PREFIX"
+
+	// InstallLogArchiveCredentialsSecretEnvVar is the name of the environment variable used to tell the controller
+	// manager the name of the secret holding the credentials of the object store in which install logs are archived.
+	InstallLogArchiveCredentialsSecretEnvVar = "HIVE_INSTALL_LOG_ARCHIVE_CREDENTIALS_SECRET"
+
+	// InstallLogArchiveRetentionEnvVar is the name of the environment variable used to tell the controller manager
+	// how long archived install logs are kept.
+	InstallLogArchiveRetentionEnvVar = "HIVE_INSTALL_LOG_ARCHIVE_RETENTION"
+
+	// InstallLogArchiveUploadsEnvVar is the name of the environment variable used to tell the install manager the
+	// objects of the install log archive to which the logs of the provision are uploaded.
+	InstallLogArchiveUploadsEnvVar = "HIVE_INSTALL_LOG_ARCHIVE_UPLOADS"
+
+	// ControllersShardLabel is the label identifying the shard of the hive controllers run by a pod.
+	ControllersShardLabel = "hive.openshift.io/controllers-shard"
+
+	// DefaultControllerConfigEnvVarPrefix is the prefix of the environment variables used to tell the controller
+	// manager the default settings of the controllers.
+	DefaultControllerConfigEnvVarPrefix = "DEFAULT"
+
+	// ConcurrentReconcilesEnvVarSuffix is the suffix of the environment variables used to tell the controller manager
+	// the concurrent reconciles of a controller.
+	ConcurrentReconcilesEnvVarSuffix = "CONCURRENT_RECONCILES"
+
+	// ClientQPSEnvVarSuffix is the suffix of the environment variables used to tell the controller manager the client
+	// QPS of a controller.
+	ClientQPSEnvVarSuffix = "CLIENT_QPS"
+
+	// ClientBurstEnvVarSuffix is the suffix of the environment variables used to tell the controller manager the
+	// client burst of a controller.
+	ClientBurstEnvVarSuffix = "CLIENT_BURST"
+
+	// QueueQPSEnvVarSuffix is the suffix of the environment variables used to tell the controller manager the work
+	// queue QPS of a controller.
+	QueueQPSEnvVarSuffix = "QUEUE_QPS"
+
+	// QueueBurstEnvVarSuffix is the suffix of the environment variables used to tell the controller manager the work
+	// queue burst of a controller.
+	QueueBurstEnvVarSuffix = "QUEUE_BURST"
+
 	// SkipGatherLogsEnvVar is the environment variable which passes the configuration to disable
 	// log gathering on failed cluster installs. The value will be either "true" or "false".
 	// If unset "false" should be assumed. This variable is set by the operator depending on the
@@ -49,6 +163,9 @@ const (
 	// SelectorSyncSetNameLabel is the label that is used to identify a relationship to a given selector syncset object.
 	SelectorSyncSetNameLabel = "hive.openshift.io/selector-syncset-name"
 
+	// ClusterImageSetNameLabel is the label that is used to identify a relationship to a given cluster image set object.
+	ClusterImageSetNameLabel = "hive.openshift.io/cluster-image-set-name"
+
 	// PVCTypeLabel is the label that is used to identify what a PVC is being used for.
 	PVCTypeLabel = "hive.openshift.io/pvc-type"
 
@@ -109,6 +226,10 @@ const (
 	// SyncsetPauseAnnotation is a annotation used by clusterDeployment, if it's true, then we will disable syncing to a specific cluster
 	SyncsetPauseAnnotation = "hive.openshift.io/syncset-pause"
 
+	// SyncSetTemplateAnnotationPrefix is the prefix of annotations on ClusterDeployments which are available to
+	// templated SyncSet resources, keyed by the remainder of the annotation key.
+	SyncSetTemplateAnnotationPrefix = "template.hive.openshift.io/"
+
 	// DisableInstallLogPasswordRedactionAnnotation is an annotation used on ClusterDeployments to disable the installmanager
 	// functionality which refuses to print output if it appears to contain a password or sensitive info. This can be
 	// useful in scenarios where debugging is needed and important info is being redacted. Set to "true".
@@ -135,6 +256,15 @@ const (
 	// AzureCredentialsName is the name of the Azure credentials file or secret key.
 	AzureCredentialsName = "osServicePrincipal.json"
 
+	// OpenStackCredentialsName is the name of the OpenStack credentials file or secret key.
+	OpenStackCredentialsName = "clouds.yaml"
+
+	// VSphereUsernameEnvVar is the environment variable specifying the vSphere username.
+	VSphereUsernameEnvVar = "GOVC_USERNAME"
+
+	// VSpherePasswordEnvVar is the environment variable specifying the vSphere password.
+	VSpherePasswordEnvVar = "GOVC_PASSWORD"
+
 	// SSHPrivKeyPathEnvVar is the environment variable Hive will set for the installmanager pod to point to the
 	// path where we mount in the SSH key to be configured on the cluster hosts.
 	SSHPrivKeyPathEnvVar = "SSH_PRIV_KEY_PATH"
@@ -169,9 +299,31 @@ const (
 
 	// AWSChinaRegionPrefix is the prefix for regions in AWS China.
 	AWSChinaRegionPrefix = "cn-"
+
+	// ClusterPoolNameLabel is the label that is used to signal that a namespace or cluster deployment was created
+	// for a cluster pool.
+	ClusterPoolNameLabel = "hive.openshift.io/cluster-pool-name"
+
+	// ClusterPoolNamespaceLabel is the label that is used to identify the namespace of the cluster pool for which
+	// a namespace was created.
+	ClusterPoolNamespaceLabel = "hive.openshift.io/cluster-pool-namespace"
+
+	// TryInstallOnceAnnotation is an annotation used on ClusterDeployments to stop Hive from starting a new provision
+	// after a failed install. Set to "true".
+	TryInstallOnceAnnotation = "hive.openshift.io/try-install-once"
+
+	// ClusterUpgradePolicyAnnotation is an annotation set on ClusterDeployments by the ClusterUpgradePolicy
+	// controller to record the name of the policy that requested the upgrade of the cluster.
+	ClusterUpgradePolicyAnnotation = "hive.openshift.io/cluster-upgrade-policy"
 )
 
 // GetMergedPullSecretName returns name for merged pull secret name per cluster deployment
 func GetMergedPullSecretName(cd *hivev1.ClusterDeployment) string {
 	return apihelpers.GetResourceName(cd.Name, mergedPullSecretSuffix)
 }
+
+// ControllerConfigEnvVar returns the name of the environment variable used to tell the controller manager a setting
+// of the controller with the given name, or of all controllers for the DefaultControllerConfigEnvVarPrefix.
+func ControllerConfigEnvVar(controllerName, suffix string) string {
+	return strings.ToUpper(controllerName) + "_" + suffix
+}
@@ -0,0 +1,16 @@
+package controller
+
+import (
+	"os"
+
+	hiveconstants "github.com/openshift/hive/pkg/constants"
+	"github.com/openshift/hive/pkg/controller/certificatebundle"
+)
+
+func init() {
+	// Certificate bundles are only generated when an ACME server is configured.
+	if os.Getenv(hiveconstants.ACMEDirectoryURLEnvVar) != "" {
+		// AddToManagerFuncs is a list of functions to create controllers and add them to a manager.
+		AddToManagerFuncs = append(AddToManagerFuncs, certificatebundle.Add)
+	}
+}
@@ -0,0 +1,8 @@
+package controller
+
+import "github.com/openshift/hive/pkg/controller/kubeadminpassword"
+
+func init() {
+	// AddToManagerFuncs is a list of functions to create controllers and add them to a manager.
+	AddToManagerFuncs = append(AddToManagerFuncs, kubeadminpassword.Add)
+}
@@ -0,0 +1,17 @@
+package controller
+
+import (
+	"os"
+	"strings"
+
+	hiveconstants "github.com/openshift/hive/pkg/constants"
+	"github.com/openshift/hive/pkg/controller/kubeconfigrotation"
+)
+
+func init() {
+	// Hive only mints its own kubeconfigs when enabled in HiveConfig.
+	if strings.EqualFold(os.Getenv(hiveconstants.HiveManagedKubeconfigEnvVar), "true") {
+		// AddToManagerFuncs is a list of functions to create controllers and add them to a manager.
+		AddToManagerFuncs = append(AddToManagerFuncs, kubeconfigrotation.Add)
+	}
+}
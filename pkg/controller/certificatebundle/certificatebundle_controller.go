@@ -0,0 +1,642 @@
+package certificatebundle
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/acme"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	k8slabels "k8s.io/kubernetes/pkg/util/labels"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	"github.com/openshift/hive/pkg/acmeclient"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+	hivemetrics "github.com/openshift/hive/pkg/controller/metrics"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+)
+
+const (
+	controllerName = "certificateBundle"
+
+	// caSecretKey is the key of the certificate authority of the ACME server in the ACME CA secret.
+	caSecretKey = "ca.crt"
+
+	defaultRenewBefore = 30 * 24 * time.Hour
+	acmeRequestTimeout = time.Minute
+	certificateKeyBits = 2048
+
+	// propagationInterval is how long to wait for the challenge records of an order to be served by all of the name
+	// servers of the zone before checking them again.
+	propagationInterval = 10 * time.Second
+	// validationInterval is how long to wait for the ACME server to validate the challenges of an order before
+	// checking the order again.
+	validationInterval = 5 * time.Second
+)
+
+// Add creates a new CertificateBundle Controller and adds it to the Manager with default RBAC. The Manager will set fields on the
+// Controller and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	r, err := NewReconciler(mgr)
+	if err != nil {
+		return err
+	}
+	return AddToManager(mgr, r)
+}
+
+// NewReconciler returns a new reconcile.Reconciler
+func NewReconciler(mgr manager.Manager) (reconcile.Reconciler, error) {
+	logger := log.WithField("controller", controllerName)
+	renewBefore := defaultRenewBefore
+	if renewBeforeStr := os.Getenv(constants.ACMERenewBeforeEnvVar); renewBeforeStr != "" {
+		var err error
+		renewBefore, err = time.ParseDuration(renewBeforeStr)
+		if err != nil {
+			logger.WithError(err).Errorf("Couldn't parse environment variable %v: %v", constants.ACMERenewBeforeEnvVar, renewBeforeStr)
+			return nil, err
+		}
+	}
+	r := &ReconcileCertificateBundle{
+		Client:               controllerutils.NewClientWithMetricsOrDie(mgr, controllerName),
+		scheme:               mgr.GetScheme(),
+		logger:               logger,
+		directoryURL:         os.Getenv(constants.ACMEDirectoryURLEnvVar),
+		email:                os.Getenv(constants.ACMEEmailEnvVar),
+		accountKeySecretName: os.Getenv(constants.ACMEAccountKeySecretEnvVar),
+		caSecretName:         os.Getenv(constants.ACMECASecretEnvVar),
+		renewBefore:          renewBefore,
+		acmeClientBuilder:    acmeclient.NewClient,
+	}
+	r.solverBuilder = r.newSolver
+	r.recordsServed = served
+	return r, nil
+}
+
+// AddToManager adds a new Controller to mgr with r as the reconcile.Reconciler
+func AddToManager(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New("certificatebundle-controller", mgr, controller.Options{Reconciler: controllerutils.NewShardedReconciler(r), MaxConcurrentReconciles: controllerutils.GetConcurrentReconciles(controllerName)})
+	if err != nil {
+		return err
+	}
+
+	if err := controllerutils.SetQueueRateLimiter(c, controllerName); err != nil {
+		return err
+	}
+
+	// Watch for changes to ClusterDeployment
+	err = c.Watch(&source.Kind{Type: &hivev1.ClusterDeployment{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the generated certificate secrets, so that deleted secrets are generated again
+	err = c.Watch(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &hivev1.ClusterDeployment{},
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var _ reconcile.Reconciler = &ReconcileCertificateBundle{}
+
+// ReconcileCertificateBundle generates the certificate bundles of a ClusterDeployment object through ACME
+type ReconcileCertificateBundle struct {
+	client.Client
+	scheme *runtime.Scheme
+	logger log.FieldLogger
+
+	directoryURL         string
+	email                string
+	accountKeySecretName string
+	caSecretName         string
+	renewBefore          time.Duration
+
+	acmeClientBuilder func(directoryURL, email string, accountKey crypto.Signer, caBundle []byte) (acmeclient.Client, error)
+	solverBuilder     func(dnsZone *hivev1.DNSZone) (acmeclient.Solver, error)
+	// recordsServed returns whether all of the name servers serve the TXT records with the values at the name.
+	recordsServed func(nameServers []string, name string, values []string) bool
+}
+
+// Reconcile generates the certificates of the certificate bundles of a ClusterDeployment which are to be generated,
+// and renews them before they expire. The ACME order of a certificate is advanced by one step per reconcile, and
+// its URL is kept in the status of the certificate bundle until the certificate is saved.
+func (r *ReconcileCertificateBundle) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	start := time.Now()
+	cdLog := r.logger.WithFields(log.Fields{
+		"clusterDeployment": request.Name,
+		"namespace":         request.Namespace,
+	})
+
+	cdLog.Info("reconciling cluster deployment")
+	defer func() {
+		dur := time.Since(start)
+		hivemetrics.MetricControllerReconcileTime.WithLabelValues(controllerName).Observe(dur.Seconds())
+		cdLog.WithField("elapsed", dur).Info("reconcile complete")
+	}()
+
+	// Fetch the ClusterDeployment instance
+	cd := &hivev1.ClusterDeployment{}
+	err := r.Get(context.TODO(), request.NamespacedName, cd)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+	// If the clusterdeployment is deleted, do not reconcile.
+	if cd.DeletionTimestamp != nil {
+		return reconcile.Result{}, nil
+	}
+
+	// The certificates are only used once the cluster is installed.
+	if !cd.Spec.Installed {
+		return reconcile.Result{}, nil
+	}
+
+	var bundles []hivev1.CertificateBundleSpec
+	for _, bundle := range cd.Spec.CertificateBundles {
+		if bundle.Generate {
+			bundles = append(bundles, bundle)
+		}
+	}
+	if len(bundles) == 0 {
+		cdLog.Debug("no certificate bundles to generate")
+		return reconcile.Result{}, nil
+	}
+
+	if !cd.Spec.ManageDNS {
+		cdLog.Warn("certificate bundles can only be generated for clusters with managed DNS")
+		return reconcile.Result{}, nil
+	}
+
+	dnsZone := &hivev1.DNSZone{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Namespace: cd.Namespace, Name: controllerutils.DNSZoneName(cd.Name)}, dnsZone); err != nil {
+		cdLog.WithError(err).Log(controllerutils.LogLevel(err), "failed to get the managed DNSZone")
+		return reconcile.Result{}, err
+	}
+
+	g := &generator{ReconcileCertificateBundle: r, dnsZone: dnsZone, logger: cdLog}
+	var statuses []hivev1.CertificateBundleStatus
+	var requeueAfter time.Duration
+	var generateErr error
+	for _, bundle := range bundles {
+		bundleLog := cdLog.WithField("certificateBundle", bundle.Name)
+		status := hivev1.CertificateBundleStatus{Name: bundle.Name}
+		for _, existing := range cd.Status.CertificateBundles {
+			if existing.Name == bundle.Name {
+				status.OrderURL = existing.OrderURL
+			}
+		}
+		certificate, orderRequeueAfter, err := r.reconcileBundle(cd, &bundle, &status, g, bundleLog)
+		if err != nil {
+			bundleLog.WithError(err).Error("failed to generate certificate bundle")
+			generateErr = err
+		}
+		if certificate != nil {
+			status.Generated = true
+			status.NotAfter = &metav1.Time{Time: certificate.NotAfter}
+		}
+		// Bundles with a pending order are requeued to advance the order, the others to renew their certificate.
+		bundleRequeueAfter := orderRequeueAfter
+		if certificate != nil && status.OrderURL == "" {
+			bundleRequeueAfter = time.Until(r.renewalTime(certificate))
+		}
+		if bundleRequeueAfter > 0 && (requeueAfter == 0 || bundleRequeueAfter < requeueAfter) {
+			requeueAfter = bundleRequeueAfter
+		}
+		statuses = append(statuses, status)
+	}
+
+	if err := r.updateStatus(cd, statuses, cdLog); err != nil {
+		return reconcile.Result{}, err
+	}
+	if generateErr != nil {
+		return reconcile.Result{}, generateErr
+	}
+	if requeueAfter > 0 {
+		cdLog.WithField("requeueAfter", requeueAfter).Debug("requeueing to generate or renew certificates")
+		return reconcile.Result{RequeueAfter: requeueAfter}, nil
+	}
+	return reconcile.Result{}, nil
+}
+
+// reconcileBundle ensures that the secret of the certificate bundle holds a certificate for the domains of the
+// bundle which is not due for renewal, advancing the order of a new certificate otherwise. It returns the
+// certificate in the secret, or nil if there is no certificate, and how long to wait before advancing the pending
+// order, if any. The URL of the pending order is kept in the status.
+func (r *ReconcileCertificateBundle) reconcileBundle(cd *hivev1.ClusterDeployment, bundle *hivev1.CertificateBundleSpec, status *hivev1.CertificateBundleStatus, g *generator, bundleLog log.FieldLogger) (*x509.Certificate, time.Duration, error) {
+	domains := bundleDomains(cd, bundle.Name)
+	if len(domains) == 0 {
+		bundleLog.Debug("certificate bundle is not used by the control plane or an ingress, nothing to generate")
+		return nil, 0, nil
+	}
+	for _, domain := range domains {
+		if !inZone(domain, g.dnsZone.Spec.Zone) {
+			return nil, 0, fmt.Errorf("domain %s is not in the managed DNS zone %s", domain, g.dnsZone.Spec.Zone)
+		}
+	}
+
+	secret := &corev1.Secret{}
+	err := r.Get(context.TODO(), types.NamespacedName{Namespace: cd.Namespace, Name: bundle.CertificateSecretRef.Name}, secret)
+	switch {
+	case apierrors.IsNotFound(err):
+		secret = nil
+	case err != nil:
+		return nil, 0, errors.Wrap(err, "could not get certificate secret")
+	}
+
+	var current *x509.Certificate
+	if secret != nil {
+		certificate, err := parseCertificate(secret.Data[corev1.TLSCertKey])
+		if err != nil {
+			bundleLog.WithError(err).Info("could not parse certificate in secret, generating a new certificate")
+		} else {
+			current = certificate
+			switch {
+			case !sets.NewString(certificate.DNSNames...).Equal(sets.NewString(domains...)):
+				bundleLog.WithField("domains", domains).Info("certificate domains changed, generating a new certificate")
+			case time.Now().After(r.renewalTime(certificate)):
+				bundleLog.WithField("notAfter", certificate.NotAfter).Info("certificate is due for renewal, generating a new certificate")
+			default:
+				bundleLog.Debug("certificate is current")
+				// Any pending order was superseded, such as by a certificate restored from a backup.
+				status.OrderURL = ""
+				return current, 0, nil
+			}
+		}
+	}
+
+	certificatePEM, keyPEM, requeueAfter, err := g.advanceOrder(status, domains, bundleLog)
+	if err != nil || certificatePEM == nil {
+		return current, requeueAfter, err
+	}
+	certificate, err := parseCertificate(certificatePEM)
+	if err != nil {
+		return current, 0, errors.Wrap(err, "could not parse generated certificate")
+	}
+
+	exists := secret != nil
+	if !exists {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      bundle.CertificateSecretRef.Name,
+				Namespace: cd.Namespace,
+			},
+		}
+	}
+	secret.Type = corev1.SecretTypeTLS
+	secret.Data = map[string][]byte{
+		corev1.TLSCertKey:       certificatePEM,
+		corev1.TLSPrivateKeyKey: keyPEM,
+	}
+	secret.Labels = k8slabels.AddLabel(secret.Labels, constants.ClusterDeploymentNameLabel, cd.Name)
+	if metav1.GetControllerOf(secret) == nil {
+		if err := controllerutil.SetControllerReference(cd, secret, r.scheme); err != nil {
+			return current, 0, errors.Wrap(err, "error setting owner reference")
+		}
+	}
+	if !exists {
+		err = r.Create(context.TODO(), secret)
+	} else {
+		err = r.Update(context.TODO(), secret)
+	}
+	if err != nil {
+		return current, 0, errors.Wrap(err, "could not save certificate secret")
+	}
+	bundleLog.WithField("notAfter", certificate.NotAfter).Info("generated certificate")
+	return certificate, 0, nil
+}
+
+// renewalTime returns when the certificate is due for renewal. The renewal period is capped at half the lifetime of
+// the certificate, so that a renewal period longer than the lifetime does not renew the certificate on every reconcile.
+func (r *ReconcileCertificateBundle) renewalTime(certificate *x509.Certificate) time.Time {
+	renewBefore := r.renewBefore
+	if maxRenewBefore := certificate.NotAfter.Sub(certificate.NotBefore) / 2; renewBefore > maxRenewBefore {
+		renewBefore = maxRenewBefore
+	}
+	return certificate.NotAfter.Add(-renewBefore)
+}
+
+func (r *ReconcileCertificateBundle) updateStatus(cd *hivev1.ClusterDeployment, statuses []hivev1.CertificateBundleStatus, cdLog log.FieldLogger) error {
+	// Keep the status of the bundles which are not generated by this controller.
+	generated := sets.NewString()
+	for _, status := range statuses {
+		generated.Insert(status.Name)
+	}
+	for _, status := range cd.Status.CertificateBundles {
+		if !generated.Has(status.Name) {
+			statuses = append(statuses, status)
+		}
+	}
+	if reflect.DeepEqual(statuses, cd.Status.CertificateBundles) {
+		return nil
+	}
+	cd.Status.CertificateBundles = statuses
+	if err := r.Status().Update(context.TODO(), cd); err != nil {
+		cdLog.WithError(err).Log(controllerutils.LogLevel(err), "failed to update certificate bundle status")
+		return err
+	}
+	return nil
+}
+
+// generator obtains certificates from the ACME server, solving the challenges in the managed DNSZone of the
+// ClusterDeployment. The ACME client and the solver are created the first time that an order is advanced.
+type generator struct {
+	*ReconcileCertificateBundle
+	dnsZone *hivev1.DNSZone
+	logger  log.FieldLogger
+
+	acmeClient acmeclient.Client
+	solver     acmeclient.Solver
+}
+
+func (g *generator) init(logger log.FieldLogger) error {
+	if g.acmeClient != nil {
+		return nil
+	}
+	accountKey, err := g.accountKey(logger)
+	if err != nil {
+		return err
+	}
+	caBundle, err := g.caBundle()
+	if err != nil {
+		return err
+	}
+	acmeClient, err := g.acmeClientBuilder(g.directoryURL, g.email, accountKey, caBundle)
+	if err != nil {
+		return errors.Wrap(err, "error creating ACME client")
+	}
+	solver, err := g.solverBuilder(g.dnsZone)
+	if err != nil {
+		return errors.Wrap(err, "error creating challenge solver")
+	}
+	g.acmeClient, g.solver = acmeClient, solver
+	return nil
+}
+
+// advanceOrder advances the order of a certificate for the specified domains by one step, creating the order when
+// the status has none. It returns the PEM-encoded certificate chain and private key once the order is fulfilled,
+// or how long to wait before advancing the order again.
+func (g *generator) advanceOrder(status *hivev1.CertificateBundleStatus, domains []string, logger log.FieldLogger) ([]byte, []byte, time.Duration, error) {
+	if err := g.init(logger); err != nil {
+		return nil, nil, 0, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), acmeRequestTimeout)
+	defer cancel()
+
+	var order *acmeclient.Order
+	if status.OrderURL != "" {
+		var err error
+		order, err = g.acmeClient.GetOrder(ctx, status.OrderURL)
+		if err != nil {
+			// The order may be gone from the ACME server, so a new order is created on the next attempt.
+			status.OrderURL = ""
+			return nil, nil, 0, err
+		}
+		logger = logger.WithField("order", order.URL)
+		switch {
+		case !sets.NewString(order.Domains...).Equal(sets.NewString(domains...)):
+			logger.Info("certificate domains changed, creating a new order")
+			g.abandonOrder(status, order, logger)
+			order = nil
+		case order.Status == acme.StatusInvalid:
+			g.abandonOrder(status, order, logger)
+			return nil, nil, 0, errors.Errorf("order %s failed", order.URL)
+		case order.Status == acme.StatusProcessing || order.Status == acme.StatusValid:
+			// The order was finalized with a key that was not saved, such as when its finalization timed out.
+			logger.Info("order was finalized without saving the certificate, creating a new order")
+			g.abandonOrder(status, order, logger)
+			order = nil
+		}
+	}
+	if order == nil {
+		logger.WithField("domains", domains).Info("ordering certificate")
+		var err error
+		order, err = g.acmeClient.CreateOrder(ctx, domains)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		status.OrderURL = order.URL
+		logger = logger.WithField("order", order.URL)
+	}
+
+	switch order.Status {
+	case acme.StatusPending:
+		requeueAfter, err := g.solveChallenges(ctx, order, logger)
+		return nil, nil, requeueAfter, err
+	case acme.StatusReady:
+		certificatePEM, keyPEM, err := g.finalize(ctx, order, domains)
+		if err != nil {
+			return nil, nil, 0, err
+		}
+		g.abandonOrder(status, order, logger)
+		return certificatePEM, keyPEM, 0, nil
+	default:
+		return nil, nil, 0, errors.Errorf("unexpected status %s of order %s", order.Status, order.URL)
+	}
+}
+
+// solveChallenges presents the challenge records of the order, and accepts the challenges once the records are
+// served by all of the name servers of the zone, since the ACME server may query any of them.
+func (g *generator) solveChallenges(ctx context.Context, order *acmeclient.Order, logger log.FieldLogger) (time.Duration, error) {
+	propagated := true
+	for name, values := range order.Records {
+		if g.recordsServed(g.dnsZone.Status.NameServers, name, values) {
+			continue
+		}
+		propagated = false
+		if err := g.solver.Present(name, values); err != nil {
+			return 0, errors.Wrapf(err, "could not present challenge record %s", name)
+		}
+	}
+	if !propagated {
+		logger.Debug("waiting for challenge records to propagate")
+		return propagationInterval, nil
+	}
+	if err := g.acmeClient.AcceptChallenges(ctx, order.URL); err != nil {
+		return 0, err
+	}
+	logger.Debug("waiting for challenges to be validated")
+	return validationInterval, nil
+}
+
+// finalize finalizes the ready order with a new private key. It returns the PEM-encoded certificate chain and
+// private key.
+func (g *generator) finalize(ctx context.Context, order *acmeclient.Order, domains []string) ([]byte, []byte, error) {
+	key, err := rsa.GenerateKey(rand.Reader, certificateKeyBits)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not generate certificate key")
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: domains[0]},
+		DNSNames: domains,
+	}, key)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "could not create certificate request")
+	}
+	certificatePEM, err := g.acmeClient.FinalizeOrder(ctx, order.URL, csr)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	return certificatePEM, keyPEM, nil
+}
+
+// abandonOrder clears the order from the status and cleans up its challenge records, once the order is fulfilled
+// or will not be.
+func (g *generator) abandonOrder(status *hivev1.CertificateBundleStatus, order *acmeclient.Order, logger log.FieldLogger) {
+	status.OrderURL = ""
+	for name, values := range order.Records {
+		if err := g.solver.CleanUp(name, values); err != nil {
+			logger.WithError(err).WithField("record", name).Warn("could not clean up challenge record")
+		}
+	}
+}
+
+// accountKey returns the private key of the ACME account, generating it when its secret does not exist yet.
+func (g *generator) accountKey(logger log.FieldLogger) (crypto.Signer, error) {
+	key := types.NamespacedName{Namespace: constants.HiveNamespace, Name: g.accountKeySecretName}
+	secret := &corev1.Secret{}
+	err := g.Get(context.TODO(), key, secret)
+	if apierrors.IsNotFound(err) {
+		logger.WithField("secret", key.Name).Info("generating ACME account key")
+		secret, err = g.createAccountKeySecret(key)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "could not get ACME account key secret")
+	}
+	return parsePrivateKey(secret.Data[corev1.TLSPrivateKeyKey])
+}
+
+func (g *generator) createAccountKeySecret(key types.NamespacedName) (*corev1.Secret, error) {
+	accountKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalECPrivateKey(accountKey)
+	if err != nil {
+		return nil, err
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      key.Name,
+			Namespace: key.Namespace,
+		},
+		Data: map[string][]byte{
+			corev1.TLSPrivateKeyKey: pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}),
+		},
+	}
+	err = g.Create(context.TODO(), secret)
+	if apierrors.IsAlreadyExists(err) {
+		// Another shard created the account key first.
+		err = g.Get(context.TODO(), key, secret)
+	}
+	return secret, err
+}
+
+// caBundle returns the certificate authority of the ACME server, if one is configured.
+func (g *generator) caBundle() ([]byte, error) {
+	if g.caSecretName == "" {
+		return nil, nil
+	}
+	secret := &corev1.Secret{}
+	if err := g.Get(context.TODO(), types.NamespacedName{Namespace: constants.HiveNamespace, Name: g.caSecretName}, secret); err != nil {
+		return nil, errors.Wrap(err, "could not get ACME certificate authority secret")
+	}
+	caBundle, ok := secret.Data[caSecretKey]
+	if !ok {
+		return nil, fmt.Errorf("ACME certificate authority secret does not contain %q data", caSecretKey)
+	}
+	return caBundle, nil
+}
+
+// bundleDomains returns the domains served with the certificate bundle with the specified name, by the control plane
+// and by the ingresses of the ClusterDeployment.
+func bundleDomains(cd *hivev1.ClusterDeployment, name string) []string {
+	domains := sets.NewString()
+	servingCertificates := cd.Spec.ControlPlaneConfig.ServingCertificates
+	if servingCertificates.Default == name {
+		domains.Insert(fmt.Sprintf("api.%s.%s", cd.Spec.ClusterName, cd.Spec.BaseDomain))
+	}
+	for _, additional := range servingCertificates.Additional {
+		if additional.Name == name {
+			domains.Insert(additional.Domain)
+		}
+	}
+	for _, ingress := range cd.Spec.Ingress {
+		if ingress.ServingCertificate == name {
+			domains.Insert("*." + ingress.Domain)
+		}
+	}
+	return domains.List()
+}
+
+// inZone returns whether the domain, which may be a wildcard domain, is in the specified zone.
+func inZone(domain string, zone string) bool {
+	domain = strings.TrimPrefix(domain, "*.")
+	return domain == zone || strings.HasSuffix(domain, "."+zone)
+}
+
+// parseCertificate parses the first certificate of a PEM-encoded certificate chain.
+func parseCertificate(data []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("no PEM-encoded certificate found")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// parsePrivateKey parses a PEM-encoded EC, PKCS#1 or PKCS#8 private key.
+func parsePrivateKey(data []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM-encoded private key found")
+	}
+	switch block.Type {
+	case "EC PRIVATE KEY":
+		return x509.ParseECPrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errors.New("unsupported private key type")
+	}
+	return signer, nil
+}
@@ -0,0 +1,526 @@
+package certificatebundle
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/acme"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openshift/hive/pkg/acmeclient"
+	mockacmeclient "github.com/openshift/hive/pkg/acmeclient/mock"
+	"github.com/openshift/hive/pkg/apis"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+)
+
+const (
+	testName                 = "test-cluster"
+	testNamespace            = "test-namespace"
+	testBaseDomain           = "example.com"
+	testBundleName           = "test-bundle"
+	testSecretName           = "test-bundle-secret"
+	testAccountKeySecretName = "acme-account-key"
+
+	testAPIDomain     = "api.test-cluster.example.com"
+	testIngressDomain = "*.apps.test-cluster.example.com"
+
+	testOrderURL = "https://acme.example.com/order/1"
+)
+
+func init() {
+	log.SetLevel(log.DebugLevel)
+}
+
+func TestReconcileCertificateBundle(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	tests := []struct {
+		name                   string
+		existing               []runtime.Object
+		setupMocks             func(*mockacmeclient.MockClient, *mockacmeclient.MockSolver)
+		recordsServed          bool
+		renewBefore            time.Duration
+		expectFinalize         bool
+		expectNoStatus         bool
+		expectAccountKeyExists bool
+		expectOrderURL         string
+		expectRequeueAfter     time.Duration
+		expectNotAfter         *time.Time
+		expectErr              bool
+	}{
+		{
+			name: "not installed",
+			existing: []runtime.Object{
+				testClusterDeployment(func(cd *hivev1.ClusterDeployment) { cd.Spec.Installed = false }),
+				testDNSZone(),
+			},
+			expectNoStatus: true,
+		},
+		{
+			name: "no bundles to generate",
+			existing: []runtime.Object{
+				testClusterDeployment(func(cd *hivev1.ClusterDeployment) { cd.Spec.CertificateBundles[0].Generate = false }),
+				testDNSZone(),
+			},
+			expectNoStatus: true,
+		},
+		{
+			name: "unmanaged DNS",
+			existing: []runtime.Object{
+				testClusterDeployment(func(cd *hivev1.ClusterDeployment) { cd.Spec.ManageDNS = false }),
+			},
+			expectNoStatus: true,
+		},
+		{
+			name: "order missing certificate",
+			existing: []runtime.Object{
+				testClusterDeployment(),
+				testDNSZone(),
+			},
+			setupMocks: func(mockACMEClient *mockacmeclient.MockClient, mockSolver *mockacmeclient.MockSolver) {
+				order := testOrder(acme.StatusPending, testIngressDomain, testAPIDomain)
+				mockACMEClient.EXPECT().CreateOrder(gomock.Any(), []string{testIngressDomain, testAPIDomain}).Return(order, nil)
+				expectPresent(mockSolver, order)
+			},
+			expectAccountKeyExists: true,
+			expectOrderURL:         testOrderURL,
+			expectRequeueAfter:     propagationInterval,
+		},
+		{
+			name: "wait for challenge records to propagate",
+			existing: []runtime.Object{
+				testClusterDeployment(withOrderURL),
+				testDNSZone(),
+				testAccountKeySecret(t),
+			},
+			setupMocks: func(mockACMEClient *mockacmeclient.MockClient, mockSolver *mockacmeclient.MockSolver) {
+				order := testOrder(acme.StatusPending, testIngressDomain, testAPIDomain)
+				mockACMEClient.EXPECT().GetOrder(gomock.Any(), testOrderURL).Return(order, nil)
+				expectPresent(mockSolver, order)
+			},
+			expectAccountKeyExists: true,
+			expectOrderURL:         testOrderURL,
+			expectRequeueAfter:     propagationInterval,
+		},
+		{
+			name: "accept challenges once records propagated",
+			existing: []runtime.Object{
+				testClusterDeployment(withOrderURL),
+				testDNSZone(),
+				testAccountKeySecret(t),
+			},
+			setupMocks: func(mockACMEClient *mockacmeclient.MockClient, mockSolver *mockacmeclient.MockSolver) {
+				mockACMEClient.EXPECT().GetOrder(gomock.Any(), testOrderURL).Return(testOrder(acme.StatusPending, testIngressDomain, testAPIDomain), nil)
+				mockACMEClient.EXPECT().AcceptChallenges(gomock.Any(), testOrderURL).Return(nil)
+			},
+			recordsServed:          true,
+			expectAccountKeyExists: true,
+			expectOrderURL:         testOrderURL,
+			expectRequeueAfter:     validationInterval,
+		},
+		{
+			name: "finalize ready order",
+			existing: []runtime.Object{
+				testClusterDeployment(withOrderURL),
+				testDNSZone(),
+				testAccountKeySecret(t),
+			},
+			setupMocks: func(mockACMEClient *mockacmeclient.MockClient, mockSolver *mockacmeclient.MockSolver) {
+				order := testOrder(acme.StatusReady, testIngressDomain, testAPIDomain)
+				mockACMEClient.EXPECT().GetOrder(gomock.Any(), testOrderURL).Return(order, nil)
+				expectCleanUp(mockSolver, order)
+			},
+			expectFinalize:         true,
+			expectAccountKeyExists: true,
+		},
+		{
+			name: "current certificate",
+			existing: []runtime.Object{
+				testClusterDeployment(),
+				testDNSZone(),
+				testCertificateSecret(t, timePtr(testNotAfter(60*24*time.Hour)), testAPIDomain, testIngressDomain),
+			},
+			expectNotAfter: timePtr(testNotAfter(60 * 24 * time.Hour)),
+		},
+		{
+			name: "renewal period longer than certificate lifetime",
+			existing: []runtime.Object{
+				testClusterDeployment(),
+				testDNSZone(),
+				testCertificateSecret(t, timePtr(testNotAfter(60*24*time.Hour)), testAPIDomain, testIngressDomain),
+			},
+			renewBefore:    120 * 24 * time.Hour,
+			expectNotAfter: timePtr(testNotAfter(60 * 24 * time.Hour)),
+		},
+		{
+			name: "renew expiring certificate",
+			existing: []runtime.Object{
+				testClusterDeployment(),
+				testDNSZone(),
+				testCertificateSecret(t, timePtr(testNotAfter(10*24*time.Hour)), testAPIDomain, testIngressDomain),
+				testAccountKeySecret(t),
+			},
+			setupMocks: func(mockACMEClient *mockacmeclient.MockClient, mockSolver *mockacmeclient.MockSolver) {
+				order := testOrder(acme.StatusPending, testIngressDomain, testAPIDomain)
+				mockACMEClient.EXPECT().CreateOrder(gomock.Any(), []string{testIngressDomain, testAPIDomain}).Return(order, nil)
+				expectPresent(mockSolver, order)
+			},
+			expectAccountKeyExists: true,
+			expectOrderURL:         testOrderURL,
+			expectRequeueAfter:     propagationInterval,
+			expectNotAfter:         timePtr(testNotAfter(10 * 24 * time.Hour)),
+		},
+		{
+			name: "renew certificate for changed domains",
+			existing: []runtime.Object{
+				testClusterDeployment(),
+				testDNSZone(),
+				testCertificateSecret(t, timePtr(testNotAfter(60*24*time.Hour)), testAPIDomain),
+			},
+			setupMocks: func(mockACMEClient *mockacmeclient.MockClient, mockSolver *mockacmeclient.MockSolver) {
+				order := testOrder(acme.StatusPending, testIngressDomain, testAPIDomain)
+				mockACMEClient.EXPECT().CreateOrder(gomock.Any(), []string{testIngressDomain, testAPIDomain}).Return(order, nil)
+				expectPresent(mockSolver, order)
+			},
+			expectAccountKeyExists: true,
+			expectOrderURL:         testOrderURL,
+			expectRequeueAfter:     propagationInterval,
+			expectNotAfter:         timePtr(testNotAfter(60 * 24 * time.Hour)),
+		},
+		{
+			name: "replace order for changed domains",
+			existing: []runtime.Object{
+				testClusterDeployment(withOrderURL),
+				testDNSZone(),
+				testAccountKeySecret(t),
+			},
+			setupMocks: func(mockACMEClient *mockacmeclient.MockClient, mockSolver *mockacmeclient.MockSolver) {
+				oldOrder := testOrder(acme.StatusPending, testAPIDomain)
+				mockACMEClient.EXPECT().GetOrder(gomock.Any(), testOrderURL).Return(oldOrder, nil)
+				expectCleanUp(mockSolver, oldOrder)
+				order := testOrder(acme.StatusPending, testIngressDomain, testAPIDomain)
+				order.URL = testOrderURL + "-2"
+				mockACMEClient.EXPECT().CreateOrder(gomock.Any(), []string{testIngressDomain, testAPIDomain}).Return(order, nil)
+				expectPresent(mockSolver, order)
+			},
+			expectAccountKeyExists: true,
+			expectOrderURL:         testOrderURL + "-2",
+			expectRequeueAfter:     propagationInterval,
+		},
+		{
+			name: "failed order",
+			existing: []runtime.Object{
+				testClusterDeployment(withOrderURL),
+				testDNSZone(),
+				testAccountKeySecret(t),
+			},
+			setupMocks: func(mockACMEClient *mockacmeclient.MockClient, mockSolver *mockacmeclient.MockSolver) {
+				order := testOrder(acme.StatusInvalid, testIngressDomain, testAPIDomain)
+				mockACMEClient.EXPECT().GetOrder(gomock.Any(), testOrderURL).Return(order, nil)
+				expectCleanUp(mockSolver, order)
+			},
+			expectAccountKeyExists: true,
+			expectErr:              true,
+		},
+		{
+			name: "domain outside of managed zone",
+			existing: []runtime.Object{
+				testClusterDeployment(func(cd *hivev1.ClusterDeployment) {
+					cd.Spec.ControlPlaneConfig.ServingCertificates.Additional = []hivev1.ControlPlaneAdditionalCertificate{{
+						Name:   testBundleName,
+						Domain: "api.example.org",
+					}}
+				}),
+				testDNSZone(),
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			fakeClient := fake.NewFakeClient(test.existing...)
+			mockACMEClient := mockacmeclient.NewMockClient(mockCtrl)
+			mockSolver := mockacmeclient.NewMockSolver(mockCtrl)
+			if test.setupMocks != nil {
+				test.setupMocks(mockACMEClient, mockSolver)
+			}
+
+			var obtainedNotAfter time.Time
+			if test.expectFinalize {
+				mockACMEClient.EXPECT().FinalizeOrder(gomock.Any(), testOrderURL, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, url string, csr []byte) ([]byte, error) {
+						request, err := x509.ParseCertificateRequest(csr)
+						require.NoError(t, err, "could not parse certificate request")
+						assert.ElementsMatch(t, []string{testIngressDomain, testAPIDomain}, request.DNSNames, "unexpected certificate request domains")
+						obtainedNotAfter = testNotAfter(90 * 24 * time.Hour)
+						return testCertificate(t, request.PublicKey, obtainedNotAfter, request.DNSNames...), nil
+					})
+			}
+
+			renewBefore := test.renewBefore
+			if renewBefore == 0 {
+				renewBefore = defaultRenewBefore
+			}
+			r := &ReconcileCertificateBundle{
+				Client:               fakeClient,
+				scheme:               scheme.Scheme,
+				logger:               log.WithField("controller", controllerName),
+				directoryURL:         "https://acme.example.com/directory",
+				accountKeySecretName: testAccountKeySecretName,
+				renewBefore:          renewBefore,
+				acmeClientBuilder: func(directoryURL, email string, accountKey crypto.Signer, caBundle []byte) (acmeclient.Client, error) {
+					return mockACMEClient, nil
+				},
+				solverBuilder: func(dnsZone *hivev1.DNSZone) (acmeclient.Solver, error) {
+					return mockSolver, nil
+				},
+				recordsServed: func(nameServers []string, name string, values []string) bool {
+					return test.recordsServed
+				},
+			}
+
+			result, err := r.Reconcile(reconcile.Request{
+				NamespacedName: types.NamespacedName{Name: testName, Namespace: testNamespace},
+			})
+			if test.expectErr {
+				assert.Error(t, err, "expected error from reconcile")
+			} else {
+				assert.NoError(t, err, "unexpected error from reconcile")
+			}
+			if test.expectRequeueAfter != 0 {
+				assert.Equal(t, test.expectRequeueAfter, result.RequeueAfter, "unexpected requeue")
+			}
+
+			cd := &hivev1.ClusterDeployment{}
+			require.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Name: testName, Namespace: testNamespace}, cd))
+
+			accountKeySecret := &corev1.Secret{}
+			accountKeyErr := fakeClient.Get(context.TODO(), types.NamespacedName{Name: testAccountKeySecretName, Namespace: constants.HiveNamespace}, accountKeySecret)
+			if test.expectAccountKeyExists {
+				assert.NoError(t, accountKeyErr, "expected account key secret")
+			} else {
+				assert.Error(t, accountKeyErr, "unexpected account key secret")
+			}
+
+			if test.expectNoStatus {
+				assert.Empty(t, cd.Status.CertificateBundles, "unexpected certificate bundle status")
+				return
+			}
+			require.Len(t, cd.Status.CertificateBundles, 1, "expected certificate bundle status")
+			status := cd.Status.CertificateBundles[0]
+			assert.Equal(t, testBundleName, status.Name, "unexpected certificate bundle status name")
+			assert.Equal(t, test.expectOrderURL, status.OrderURL, "unexpected order URL")
+
+			switch {
+			case test.expectFinalize:
+				assert.True(t, status.Generated, "expected generated certificate bundle")
+				if assert.NotNil(t, status.NotAfter, "expected certificate expiry") {
+					assert.Equal(t, obtainedNotAfter, status.NotAfter.Time.UTC(), "unexpected certificate expiry")
+				}
+				secret := &corev1.Secret{}
+				require.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Name: testSecretName, Namespace: testNamespace}, secret))
+				assert.Equal(t, corev1.SecretTypeTLS, secret.Type, "unexpected secret type")
+				assert.Equal(t, testName, secret.Labels[constants.ClusterDeploymentNameLabel], "unexpected cluster deployment label")
+				if owner := metav1.GetControllerOf(secret); assert.NotNil(t, owner, "expected controller reference") {
+					assert.Equal(t, testName, owner.Name, "unexpected controller reference")
+				}
+				certificate, err := parseCertificate(secret.Data[corev1.TLSCertKey])
+				require.NoError(t, err, "could not parse generated certificate")
+				key, err := parsePrivateKey(secret.Data[corev1.TLSPrivateKeyKey])
+				require.NoError(t, err, "could not parse generated key")
+				assert.Equal(t, key.Public(), certificate.PublicKey, "certificate does not match key")
+			case test.expectNotAfter != nil:
+				assert.True(t, status.Generated, "expected generated certificate bundle")
+				if assert.NotNil(t, status.NotAfter, "expected certificate expiry") {
+					assert.Equal(t, *test.expectNotAfter, status.NotAfter.Time.UTC(), "unexpected certificate expiry")
+				}
+			default:
+				assert.False(t, status.Generated, "unexpected generated certificate bundle")
+			}
+		})
+	}
+}
+
+func TestBundleDomains(t *testing.T) {
+	cd := testClusterDeployment(func(cd *hivev1.ClusterDeployment) {
+		cd.Spec.ControlPlaneConfig.ServingCertificates.Additional = []hivev1.ControlPlaneAdditionalCertificate{
+			{Name: testBundleName, Domain: "api.custom.example.com"},
+			{Name: "other-bundle", Domain: "api.other.example.com"},
+		}
+		cd.Spec.Ingress = append(cd.Spec.Ingress, hivev1.ClusterIngress{
+			Name:               "other",
+			Domain:             "other.test-cluster.example.com",
+			ServingCertificate: "other-bundle",
+		})
+	})
+	assert.Equal(t, []string{testIngressDomain, "api.custom.example.com", testAPIDomain}, bundleDomains(cd, testBundleName))
+	assert.Equal(t, []string{"*.other.test-cluster.example.com", "api.other.example.com"}, bundleDomains(cd, "other-bundle"))
+	assert.Empty(t, bundleDomains(cd, "unused-bundle"))
+}
+
+func testClusterDeployment(modifiers ...func(*hivev1.ClusterDeployment)) *hivev1.ClusterDeployment {
+	cd := &hivev1.ClusterDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testName,
+			Namespace: testNamespace,
+			UID:       types.UID("1234"),
+		},
+		Spec: hivev1.ClusterDeploymentSpec{
+			ClusterName: testName,
+			BaseDomain:  testBaseDomain,
+			ManageDNS:   true,
+			Installed:   true,
+			CertificateBundles: []hivev1.CertificateBundleSpec{{
+				Name:                 testBundleName,
+				Generate:             true,
+				CertificateSecretRef: corev1.LocalObjectReference{Name: testSecretName},
+			}},
+			ControlPlaneConfig: hivev1.ControlPlaneConfigSpec{
+				ServingCertificates: hivev1.ControlPlaneServingCertificateSpec{
+					Default: testBundleName,
+				},
+			},
+			Ingress: []hivev1.ClusterIngress{{
+				Name:               "default",
+				Domain:             "apps.test-cluster.example.com",
+				ServingCertificate: testBundleName,
+			}},
+		},
+	}
+	for _, modify := range modifiers {
+		modify(cd)
+	}
+	return cd
+}
+
+func withOrderURL(cd *hivev1.ClusterDeployment) {
+	cd.Status.CertificateBundles = []hivev1.CertificateBundleStatus{{
+		Name:     testBundleName,
+		OrderURL: testOrderURL,
+	}}
+}
+
+func testDNSZone() *hivev1.DNSZone {
+	return &hivev1.DNSZone{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      controllerutils.DNSZoneName(testName),
+			Namespace: testNamespace,
+		},
+		Spec: hivev1.DNSZoneSpec{
+			Zone: "test-cluster.example.com",
+		},
+	}
+}
+
+func testAccountKeySecret(t *testing.T) *corev1.Secret {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err, "could not generate account key")
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err, "could not marshal account key")
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testAccountKeySecretName,
+			Namespace: constants.HiveNamespace,
+		},
+		Data: map[string][]byte{
+			corev1.TLSPrivateKeyKey: pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}),
+		},
+	}
+}
+
+func testCertificateSecret(t *testing.T, notAfter *time.Time, domains ...string) *corev1.Secret {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err, "could not generate certificate key")
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testSecretName,
+			Namespace: testNamespace,
+		},
+		Type: corev1.SecretTypeTLS,
+		Data: map[string][]byte{
+			corev1.TLSCertKey:       testCertificate(t, key.Public(), *notAfter, domains...),
+			corev1.TLSPrivateKeyKey: []byte("key"),
+		},
+	}
+}
+
+// testCertificate returns a self-signed PEM-encoded certificate for the specified public key and domains, valid for
+// 90 days like the certificates of Let's Encrypt.
+func testCertificate(t *testing.T, publicKey crypto.PublicKey, notAfter time.Time, domains ...string) []byte {
+	signer, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err, "could not generate signing key")
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: domains[0]},
+		DNSNames:     domains,
+		NotBefore:    notAfter.Add(-90 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, publicKey, signer)
+	require.NoError(t, err, "could not create certificate")
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+// testNotAfter returns a certificate expiry time the specified duration from now, truncated to the precision of
+// certificates and of the status.
+func testNotAfter(d time.Duration) time.Time {
+	return time.Now().Add(d).UTC().Truncate(time.Second)
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+// testOrder returns an order with the specified status for the domains, with a challenge record for each domain.
+func testOrder(status string, domains ...string) *acmeclient.Order {
+	order := &acmeclient.Order{
+		URL:     testOrderURL,
+		Status:  status,
+		Domains: domains,
+		Records: map[string][]string{},
+	}
+	for _, domain := range domains {
+		name := acmeclient.ChallengeRecordName(domain)
+		order.Records[name] = append(order.Records[name], "token-"+domain)
+	}
+	return order
+}
+
+func expectPresent(mockSolver *mockacmeclient.MockSolver, order *acmeclient.Order) {
+	for name, values := range order.Records {
+		mockSolver.EXPECT().Present(name, values).Return(nil)
+	}
+}
+
+func expectCleanUp(mockSolver *mockacmeclient.MockSolver, order *acmeclient.Order) {
+	for name, values := range order.Records {
+		mockSolver.EXPECT().CleanUp(name, values).Return(nil)
+	}
+}
@@ -0,0 +1,279 @@
+package certificatebundle
+
+import (
+	"context"
+	"net"
+	"strconv"
+	"strings"
+
+	azuredns "github.com/Azure/azure-sdk-for-go/services/dns/mgmt/2017-10-01/dns"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/miekg/dns"
+	"github.com/pkg/errors"
+	gcpdns "google.golang.org/api/dns/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/hive/pkg/acmeclient"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/awsclient"
+	"github.com/openshift/hive/pkg/azureclient"
+	"github.com/openshift/hive/pkg/constants"
+	"github.com/openshift/hive/pkg/gcpclient"
+	"github.com/openshift/hive/pkg/rfc2136client"
+)
+
+const (
+	challengeRecordTTL = 60
+)
+
+// newSolver creates a solver publishing the challenge records in the given DNSZone with the credentials of the
+// DNSZone.
+func (r *ReconcileCertificateBundle) newSolver(dnsZone *hivev1.DNSZone) (acmeclient.Solver, error) {
+	var secretName string
+	switch {
+	case dnsZone.Spec.AWS != nil:
+		secretName = dnsZone.Spec.AWS.CredentialsSecretRef.Name
+	case dnsZone.Spec.GCP != nil:
+		secretName = dnsZone.Spec.GCP.CredentialsSecretRef.Name
+	case dnsZone.Spec.Azure != nil:
+		secretName = dnsZone.Spec.Azure.CredentialsSecretRef.Name
+	case dnsZone.Spec.RFC2136 != nil:
+		secretName = dnsZone.Spec.RFC2136.TSIGKeySecretRef.Name
+	default:
+		return nil, errors.New("unable to determine the DNS provider of the DNSZone")
+	}
+	secret := &corev1.Secret{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Namespace: dnsZone.Namespace, Name: secretName}, secret); err != nil {
+		return nil, errors.Wrap(err, "could not get the DNSZone credentials secret")
+	}
+
+	var solver acmeclient.Solver
+	switch {
+	case dnsZone.Spec.AWS != nil:
+		if dnsZone.Status.AWS == nil || dnsZone.Status.AWS.ZoneID == nil {
+			return nil, errors.New("DNSZone has no hosted zone ID")
+		}
+		region := dnsZone.Spec.AWS.Region
+		if region == "" {
+			region = constants.AWSRoute53Region
+		}
+		awsClient, err := awsclient.NewClientFromSecret(secret, region)
+		if err != nil {
+			return nil, errors.Wrap(err, "error creating AWS client")
+		}
+		solver = &route53Solver{client: awsClient, zoneID: *dnsZone.Status.AWS.ZoneID}
+	case dnsZone.Spec.GCP != nil:
+		if dnsZone.Status.GCP == nil || dnsZone.Status.GCP.ZoneName == nil {
+			return nil, errors.New("DNSZone has no managed zone name")
+		}
+		gcpClient, err := gcpclient.NewClientFromSecret(secret)
+		if err != nil {
+			return nil, errors.Wrap(err, "error creating GCP client")
+		}
+		solver = &cloudDNSSolver{client: gcpClient, zoneName: *dnsZone.Status.GCP.ZoneName}
+	case dnsZone.Spec.Azure != nil:
+		azureClient, err := azureclient.NewClientFromSecret(secret)
+		if err != nil {
+			return nil, errors.Wrap(err, "error creating Azure client")
+		}
+		// Azure DNS zones are named after the domain that they host.
+		solver = &azureDNSSolver{
+			client:            azureClient,
+			resourceGroupName: dnsZone.Spec.Azure.ResourceGroupName,
+			zone:              dnsZone.Spec.Zone,
+		}
+	case dnsZone.Spec.RFC2136 != nil:
+		rfc2136Client, err := rfc2136client.NewClientFromSecret(dnsZone.Spec.RFC2136.Server, secret)
+		if err != nil {
+			return nil, errors.Wrap(err, "error creating RFC2136 client")
+		}
+		solver = &rfc2136Solver{client: rfc2136Client, zone: dnsZone.Spec.Zone}
+	}
+
+	return solver, nil
+}
+
+// served returns whether all of the name servers serve TXT records with the specified values at the specified name.
+func served(nameServers []string, name string, values []string) bool {
+	for _, nameServer := range nameServers {
+		if !serves(nameServer, name, values) {
+			return false
+		}
+	}
+	return true
+}
+
+// serves returns whether the specified name server serves TXT records with the specified values at the
+// specified name.
+func serves(nameServer string, name string, values []string) bool {
+	m := new(dns.Msg)
+	m.SetQuestion(dns.Fqdn(name), dns.TypeTXT)
+	r, err := dns.Exchange(m, net.JoinHostPort(nameServer, "53"))
+	if err != nil || r.Rcode != dns.RcodeSuccess {
+		return false
+	}
+	served := sets.NewString()
+	for _, rr := range r.Answer {
+		if txt, ok := rr.(*dns.TXT); ok {
+			served.Insert(strings.Join(txt.Txt, ""))
+		}
+	}
+	return served.HasAll(values...)
+}
+
+// quoted returns the values quoted as the character strings of TXT record data.
+func quoted(values []string) []string {
+	result := make([]string, len(values))
+	for i, v := range values {
+		result[i] = strconv.Quote(v)
+	}
+	return result
+}
+
+// route53Solver presents challenge records in an AWS Route53 hosted zone.
+type route53Solver struct {
+	client awsclient.Client
+	zoneID string
+}
+
+func (s *route53Solver) Present(name string, values []string) error {
+	return s.changeRecords(route53.ChangeActionUpsert, name, values)
+}
+
+// CleanUp deletes the records, whose values are required by Route53 to delete them. Records which are not
+// presented are not found.
+func (s *route53Solver) CleanUp(name string, values []string) error {
+	err := s.changeRecords(route53.ChangeActionDelete, name, values)
+	if awsErr, ok := err.(awserr.Error); ok && awsErr.Code() == route53.ErrCodeInvalidChangeBatch {
+		return nil
+	}
+	return err
+}
+
+func (s *route53Solver) changeRecords(action string, name string, values []string) error {
+	records := make([]*route53.ResourceRecord, len(values))
+	for i, v := range quoted(values) {
+		records[i] = &route53.ResourceRecord{Value: aws.String(v)}
+	}
+	_, err := s.client.ChangeResourceRecordSets(&route53.ChangeResourceRecordSetsInput{
+		HostedZoneId: aws.String(s.zoneID),
+		ChangeBatch: &route53.ChangeBatch{
+			Changes: []*route53.Change{{
+				Action: aws.String(action),
+				ResourceRecordSet: &route53.ResourceRecordSet{
+					Name:            aws.String(name),
+					Type:            aws.String(route53.RRTypeTxt),
+					TTL:             aws.Int64(challengeRecordTTL),
+					ResourceRecords: records,
+				},
+			}},
+		},
+	})
+	return err
+}
+
+// cloudDNSSolver presents challenge records in a GCP Cloud DNS managed zone.
+type cloudDNSSolver struct {
+	client   gcpclient.Client
+	zoneName string
+}
+
+func (s *cloudDNSSolver) Present(name string, values []string) error {
+	existing, err := s.existingRecordSet(name)
+	if err != nil {
+		return err
+	}
+	recordSet := &gcpdns.ResourceRecordSet{
+		Name:    dns.Fqdn(name),
+		Type:    "TXT",
+		Ttl:     challengeRecordTTL,
+		Rrdatas: quoted(values),
+	}
+	if existing != nil {
+		return s.client.UpdateResourceRecordSet(s.zoneName, recordSet, existing)
+	}
+	return s.client.AddResourceRecordSet(s.zoneName, recordSet)
+}
+
+func (s *cloudDNSSolver) CleanUp(name string, values []string) error {
+	existing, err := s.existingRecordSet(name)
+	if err != nil || existing == nil {
+		return err
+	}
+	return s.client.DeleteResourceRecordSet(s.zoneName, existing)
+}
+
+func (s *cloudDNSSolver) existingRecordSet(name string) (*gcpdns.ResourceRecordSet, error) {
+	resp, err := s.client.ListResourceRecordSets(s.zoneName, gcpclient.ListResourceRecordSetsOptions{
+		Name: dns.Fqdn(name),
+		Type: "TXT",
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(resp.Rrsets) == 0 {
+		return nil, nil
+	}
+	return resp.Rrsets[0], nil
+}
+
+// azureDNSSolver presents challenge records in an Azure DNS zone.
+type azureDNSSolver struct {
+	client            azureclient.Client
+	resourceGroupName string
+	zone              string
+}
+
+func (s *azureDNSSolver) Present(name string, values []string) error {
+	txtRecords := make([]azuredns.TxtRecord, len(values))
+	for i := range values {
+		txtRecords[i] = azuredns.TxtRecord{Value: &[]string{values[i]}}
+	}
+	_, err := s.client.CreateOrUpdateRecordSet(
+		context.TODO(),
+		s.resourceGroupName,
+		s.zone,
+		s.recordSetName(name),
+		azuredns.TXT,
+		azuredns.RecordSet{
+			RecordSetProperties: &azuredns.RecordSetProperties{
+				TTL:        to.Int64Ptr(challengeRecordTTL),
+				TxtRecords: &txtRecords,
+			},
+		},
+	)
+	return err
+}
+
+func (s *azureDNSSolver) CleanUp(name string, values []string) error {
+	err := s.client.DeleteRecordSet(context.TODO(), s.resourceGroupName, s.zone, s.recordSetName(name), azuredns.TXT)
+	if azureclient.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// recordSetName returns the name of the record set relative to the zone.
+func (s *azureDNSSolver) recordSetName(name string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(name, "."), "."+strings.TrimSuffix(s.zone, "."))
+}
+
+// rfc2136Solver presents challenge records on a DNS server through RFC2136 dynamic updates.
+type rfc2136Solver struct {
+	client rfc2136client.Client
+	zone   string
+}
+
+func (s *rfc2136Solver) Present(name string, values []string) error {
+	return s.client.ReplaceRecords(s.zone, name, dns.TypeTXT, challengeRecordTTL, quoted(values))
+}
+
+func (s *rfc2136Solver) CleanUp(name string, values []string) error {
+	return s.client.DeleteRecords(s.zone, name, dns.TypeTXT)
+}
@@ -0,0 +1,69 @@
+package certificatebundle
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/route53"
+	"github.com/golang/mock/gomock"
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/hive/pkg/awsclient/mock"
+	"github.com/openshift/hive/pkg/rfc2136client"
+	"github.com/openshift/hive/pkg/test/dnsserver"
+)
+
+func TestRFC2136Solver(t *testing.T) {
+	server := dnsserver.Start(t, map[string][]string{"test-cluster.example.com": {"ns1.example.com"}})
+	solver := &rfc2136Solver{
+		client: rfc2136client.NewClient(server.Addr, dnsserver.TSIGKeyName, "", dnsserver.TSIGSecret),
+		zone:   "test-cluster.example.com",
+	}
+	name := "_acme-challenge.apps.test-cluster.example.com"
+
+	require.NoError(t, solver.Present(name, []string{"value-1", "value-2"}), "unexpected error presenting records")
+	assert.Equal(t, []string{`"value-1"`, `"value-2"`}, server.Records(name, dns.TypeTXT), "unexpected presented records")
+
+	require.NoError(t, solver.Present(name, []string{"value-3"}), "unexpected error presenting records again")
+	assert.Equal(t, []string{`"value-3"`}, server.Records(name, dns.TypeTXT), "expected records to be replaced")
+
+	require.NoError(t, solver.CleanUp(name, []string{"value-3"}), "unexpected error cleaning up records")
+	assert.Empty(t, server.Records(name, dns.TypeTXT), "expected records to be cleaned up")
+}
+
+func TestRoute53Solver(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockAWSClient := mock.NewMockClient(mockCtrl)
+	solver := &route53Solver{client: mockAWSClient, zoneID: "zone-id"}
+	name := "_acme-challenge.apps.test-cluster.example.com"
+
+	expectChange := func(action string) {
+		mockAWSClient.EXPECT().ChangeResourceRecordSets(gomock.Any()).
+			Do(func(input *route53.ChangeResourceRecordSetsInput) {
+				assert.Equal(t, "zone-id", aws.StringValue(input.HostedZoneId), "unexpected hosted zone")
+				require.Len(t, input.ChangeBatch.Changes, 1, "expected a single change")
+				change := input.ChangeBatch.Changes[0]
+				assert.Equal(t, action, aws.StringValue(change.Action), "unexpected change action")
+				assert.Equal(t, name, aws.StringValue(change.ResourceRecordSet.Name), "unexpected record name")
+				require.Len(t, change.ResourceRecordSet.ResourceRecords, 1, "expected a single record")
+				assert.Equal(t, `"value"`, aws.StringValue(change.ResourceRecordSet.ResourceRecords[0].Value), "unexpected record value")
+			}).
+			Return(&route53.ChangeResourceRecordSetsOutput{}, nil)
+	}
+
+	expectChange(route53.ChangeActionUpsert)
+	require.NoError(t, solver.Present(name, []string{"value"}), "unexpected error presenting records")
+
+	// The deletion must carry the values of the presented records.
+	expectChange(route53.ChangeActionDelete)
+	require.NoError(t, solver.CleanUp(name, []string{"value"}), "unexpected error cleaning up records")
+
+	// Records which were already deleted are ignored.
+	mockAWSClient.EXPECT().ChangeResourceRecordSets(gomock.Any()).
+		Return(nil, awserr.New(route53.ErrCodeInvalidChangeBatch, "record not found", nil))
+	assert.NoError(t, solver.CleanUp(name, []string{"value"}), "unexpected error cleaning up records twice")
+}
@@ -0,0 +1,408 @@
+// Package certificateexpiry provides a controller which monitors the expiry of the certificates served by the control
+// plane and ingresses of installed clusters. It reports the expiry of each certificate as a metric, and sets the
+// CertificateExpiring condition and emits events when a certificate is about to expire.
+package certificateexpiry
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+	hivemetrics "github.com/openshift/hive/pkg/controller/metrics"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+	"github.com/openshift/hive/pkg/remoteclient"
+)
+
+const (
+	controllerName = "certificateExpiry"
+
+	defaultWarningWindow = 14 * 24 * time.Hour
+	checkInterval        = time.Hour
+
+	certificateBundleSource = "certificate_bundle"
+	apiServerSource         = "api_server"
+
+	certificateExpiringReason = "CertificateExpiring"
+	certificateExpiredReason  = "CertificateExpired"
+	certificatesValidReason   = "CertificatesValid"
+)
+
+var (
+	metricCertificateExpiry = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "hive_certificate_expiry_seconds",
+		Help: "Expiry time, in seconds since the epoch, of the certificates served by the control plane and ingresses of clusters.",
+	}, []string{"cluster_deployment", "namespace", "source", "name"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(metricCertificateExpiry)
+}
+
+// Add creates a new CertificateExpiry Controller and adds it to the Manager with default RBAC. The Manager will set fields on the
+// Controller and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	r, err := NewReconciler(mgr)
+	if err != nil {
+		return err
+	}
+	return AddToManager(mgr, r)
+}
+
+// NewReconciler returns a new reconcile.Reconciler
+func NewReconciler(mgr manager.Manager) (reconcile.Reconciler, error) {
+	logger := log.WithField("controller", controllerName)
+	warningWindow := defaultWarningWindow
+	if windowStr := os.Getenv(constants.CertificateExpiryWarningWindowEnvVar); windowStr != "" {
+		var err error
+		warningWindow, err = time.ParseDuration(windowStr)
+		if err != nil {
+			logger.WithError(err).Errorf("Couldn't parse environment variable %v: %v", constants.CertificateExpiryWarningWindowEnvVar, windowStr)
+			return nil, err
+		}
+	}
+	r := &ReconcileCertificateExpiry{
+		Client:        controllerutils.NewClientWithMetricsOrDie(mgr, controllerName),
+		scheme:        mgr.GetScheme(),
+		logger:        logger,
+		eventRecorder: mgr.GetEventRecorderFor(controllerName),
+		warningWindow: warningWindow,
+		reported:      map[types.NamespacedName]map[certificateID]bool{},
+	}
+	r.remoteClientBuilder = func(cd *hivev1.ClusterDeployment) remoteclient.Builder {
+		return remoteclient.NewBuilder(r.Client, cd, controllerName)
+	}
+	return r, nil
+}
+
+// AddToManager adds a new Controller to mgr with r as the reconcile.Reconciler
+func AddToManager(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New("certificateexpiry-controller", mgr, controller.Options{Reconciler: controllerutils.NewShardedReconciler(r), MaxConcurrentReconciles: controllerutils.GetConcurrentReconciles(controllerName)})
+	if err != nil {
+		return err
+	}
+
+	if err := controllerutils.SetQueueRateLimiter(c, controllerName); err != nil {
+		return err
+	}
+
+	// Watch for changes to ClusterDeployment
+	err = c.Watch(&source.Kind{Type: &hivev1.ClusterDeployment{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var _ reconcile.Reconciler = &ReconcileCertificateExpiry{}
+
+// ReconcileCertificateExpiry monitors the expiry of the certificates of a ClusterDeployment object
+type ReconcileCertificateExpiry struct {
+	client.Client
+	scheme        *runtime.Scheme
+	logger        log.FieldLogger
+	eventRecorder record.EventRecorder
+
+	// warningWindow is how long before their expiry certificates are reported as expiring.
+	warningWindow time.Duration
+
+	// remoteClientBuilder is a function pointer to the function that gets a builder for building a client
+	// for the remote cluster's API server
+	remoteClientBuilder func(cd *hivev1.ClusterDeployment) remoteclient.Builder
+
+	// reported holds the certificates for which an expiry metric is reported for each cluster, so that the metrics of
+	// certificates which are no longer served are removed.
+	reportedLock sync.Mutex
+	reported     map[types.NamespacedName]map[certificateID]bool
+}
+
+// certificateID identifies a certificate of a cluster by the labels of its expiry metric.
+type certificateID struct {
+	source string
+	name   string
+}
+
+// certificateExpiry is the expiry time of a certificate of a cluster.
+type certificateExpiry struct {
+	certificateID
+	notAfter time.Time
+}
+
+func (c certificateExpiry) String() string {
+	if c.source == apiServerSource {
+		return fmt.Sprintf("certificate served by API server %s expires at %s", c.name, c.notAfter.UTC().Format(time.RFC3339))
+	}
+	return fmt.Sprintf("certificate bundle %s expires at %s", c.name, c.notAfter.UTC().Format(time.RFC3339))
+}
+
+// Reconcile checks the expiry of the certificate bundles served by the control plane and ingresses of a cluster, and
+// of the certificate served by its API server.
+func (r *ReconcileCertificateExpiry) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	start := time.Now()
+	cdLog := r.logger.WithFields(log.Fields{
+		"clusterDeployment": request.Name,
+		"namespace":         request.Namespace,
+	})
+
+	cdLog.Info("reconciling cluster deployment")
+	defer func() {
+		dur := time.Since(start)
+		hivemetrics.MetricControllerReconcileTime.WithLabelValues(controllerName).Observe(dur.Seconds())
+		cdLog.WithField("elapsed", dur).Info("reconcile complete")
+	}()
+
+	// Fetch the ClusterDeployment instance
+	cd := &hivev1.ClusterDeployment{}
+	err := r.Get(context.TODO(), request.NamespacedName, cd)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			r.reportExpiries(request.NamespacedName, nil)
+			return reconcile.Result{}, nil
+		}
+		cdLog.WithError(err).Error("error looking up cluster deployment")
+		return reconcile.Result{}, err
+	}
+
+	// If the clusterdeployment is deleted, do not reconcile.
+	if cd.DeletionTimestamp != nil {
+		r.reportExpiries(request.NamespacedName, nil)
+		return reconcile.Result{}, nil
+	}
+
+	if !cd.Spec.Installed {
+		cdLog.Debug("cluster installation is not complete")
+		return reconcile.Result{}, nil
+	}
+
+	expiries, err := r.bundleExpiries(cd, cdLog)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	if expiry := r.apiServerExpiry(cd, cdLog); expiry != nil {
+		expiries = append(expiries, *expiry)
+	}
+	r.reportExpiries(request.NamespacedName, expiries)
+
+	if err := r.setCertificateExpiringCondition(cd, expiries, cdLog); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	// Check again once the next certificate enters the warning window, so that the condition is set on time, and
+	// periodically to pick up changes to the certificates.
+	requeueAfter := checkInterval
+	for _, expiry := range expiries {
+		if untilWarning := time.Until(expiry.notAfter.Add(-r.warningWindow)); untilWarning > 0 && untilWarning < requeueAfter {
+			requeueAfter = untilWarning
+		}
+	}
+	return reconcile.Result{RequeueAfter: requeueAfter}, nil
+}
+
+// bundleExpiries returns the expiry times of the certificate bundles served by the control plane and ingresses of the
+// cluster. Missing certificate bundles are reported by the controllers serving them, so they are skipped.
+func (r *ReconcileCertificateExpiry) bundleExpiries(cd *hivev1.ClusterDeployment, cdLog log.FieldLogger) ([]certificateExpiry, error) {
+	served := map[string]bool{}
+	if name := cd.Spec.ControlPlaneConfig.ServingCertificates.Default; name != "" {
+		served[name] = true
+	}
+	for _, additional := range cd.Spec.ControlPlaneConfig.ServingCertificates.Additional {
+		served[additional.Name] = true
+	}
+	for _, ingress := range cd.Spec.Ingress {
+		if ingress.ServingCertificate != "" {
+			served[ingress.ServingCertificate] = true
+		}
+	}
+
+	var expiries []certificateExpiry
+	for _, bundle := range cd.Spec.CertificateBundles {
+		if !served[bundle.Name] {
+			continue
+		}
+		bundleLog := cdLog.WithField("certificateBundle", bundle.Name)
+		secret := &corev1.Secret{}
+		err := r.Get(context.TODO(), types.NamespacedName{Namespace: cd.Namespace, Name: bundle.CertificateSecretRef.Name}, secret)
+		if apierrors.IsNotFound(err) {
+			bundleLog.Debug("certificate bundle secret not found")
+			continue
+		}
+		if err != nil {
+			bundleLog.WithError(err).Error("error looking up certificate bundle secret")
+			return nil, err
+		}
+		certificates, err := parseCertificates(secret.Data[corev1.TLSCertKey])
+		if err != nil {
+			bundleLog.WithError(err).Warn("could not parse certificate bundle")
+			continue
+		}
+		expiries = append(expiries, certificateExpiry{
+			certificateID: certificateID{source: certificateBundleSource, name: bundle.Name},
+			notAfter:      earliestNotAfter(certificates),
+		})
+	}
+	return expiries, nil
+}
+
+// apiServerExpiry returns the expiry time of the certificate served by the API server of the cluster, or nil if the
+// cluster cannot be reached.
+func (r *ReconcileCertificateExpiry) apiServerExpiry(cd *hivev1.ClusterDeployment, cdLog log.FieldLogger) *certificateExpiry {
+	if cd.Spec.ClusterMetadata == nil {
+		cdLog.Error("installed cluster with no cluster metadata")
+		return nil
+	}
+	remoteClientBuilder := r.remoteClientBuilder(cd)
+	if remoteClientBuilder.Unreachable() {
+		cdLog.Debug("skipping API server certificate check for unreachable cluster")
+		return nil
+	}
+	apiURL, err := remoteClientBuilder.APIURL()
+	if err != nil {
+		cdLog.WithError(err).Warn("could not determine the API URL of the cluster")
+		return nil
+	}
+	certificates, err := remoteClientBuilder.ServingCertificates()
+	if err != nil {
+		cdLog.WithError(err).Warn("could not get the certificate served by the API server")
+		return nil
+	}
+	return &certificateExpiry{
+		certificateID: certificateID{source: apiServerSource, name: apiURL},
+		notAfter:      earliestNotAfter(certificates),
+	}
+}
+
+// reportExpiries sets the expiry metrics of the certificates of the cluster, removing the metrics of the certificates
+// that were reported previously but are no longer served.
+func (r *ReconcileCertificateExpiry) reportExpiries(key types.NamespacedName, expiries []certificateExpiry) {
+	r.reportedLock.Lock()
+	defer r.reportedLock.Unlock()
+	reported := map[certificateID]bool{}
+	for _, expiry := range expiries {
+		metricCertificateExpiry.WithLabelValues(key.Name, key.Namespace, expiry.source, expiry.name).Set(float64(expiry.notAfter.Unix()))
+		reported[expiry.certificateID] = true
+	}
+	for id := range r.reported[key] {
+		if !reported[id] {
+			metricCertificateExpiry.DeleteLabelValues(key.Name, key.Namespace, id.source, id.name)
+		}
+	}
+	if len(reported) == 0 {
+		delete(r.reported, key)
+	} else {
+		r.reported[key] = reported
+	}
+}
+
+// setCertificateExpiringCondition sets the CertificateExpiring condition from the certificates which expire within
+// the warning window, and emits an event when the condition changes.
+func (r *ReconcileCertificateExpiry) setCertificateExpiringCondition(cd *hivev1.ClusterDeployment, expiries []certificateExpiry, cdLog log.FieldLogger) error {
+	var expiring []string
+	expired := false
+	for _, expiry := range expiries {
+		if time.Now().Before(expiry.notAfter.Add(-r.warningWindow)) {
+			continue
+		}
+		expiring = append(expiring, expiry.String())
+		if time.Now().After(expiry.notAfter) {
+			expired = true
+		}
+	}
+	sort.Strings(expiring)
+
+	status := corev1.ConditionFalse
+	reason := certificatesValidReason
+	message := fmt.Sprintf("No certificates expire within %s", r.warningWindow)
+	eventType := corev1.EventTypeNormal
+	if len(expiring) > 0 {
+		status = corev1.ConditionTrue
+		reason = certificateExpiringReason
+		if expired {
+			reason = certificateExpiredReason
+		}
+		message = strings.Join(expiring, "; ")
+		eventType = corev1.EventTypeWarning
+	}
+
+	var changed bool
+	cd.Status.Conditions, changed = controllerutils.SetClusterDeploymentConditionWithChangeCheck(
+		cd.Status.Conditions,
+		hivev1.CertificateExpiringCondition,
+		status,
+		reason,
+		message,
+		controllerutils.UpdateConditionIfReasonOrMessageChange,
+	)
+	if !changed {
+		return nil
+	}
+	cdLog.WithField("reason", reason).Info(message)
+	if err := r.Status().Update(context.TODO(), cd); err != nil {
+		cdLog.WithError(err).Log(controllerutils.LogLevel(err), "error updating cluster deployment with certificate expiring condition")
+		return err
+	}
+	r.eventRecorder.Event(cd, eventType, reason, message)
+	return nil
+}
+
+// parseCertificates parses a PEM-encoded certificate chain.
+func parseCertificates(data []byte) ([]*x509.Certificate, error) {
+	var certificates []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certificates = append(certificates, certificate)
+	}
+	if len(certificates) == 0 {
+		return nil, errors.New("no PEM-encoded certificate found")
+	}
+	return certificates, nil
+}
+
+// earliestNotAfter returns the earliest expiry time of the certificates of a chain, since the chain can no longer be
+// verified once any of its certificates expires.
+func earliestNotAfter(certificates []*x509.Certificate) time.Time {
+	notAfter := certificates[0].NotAfter
+	for _, certificate := range certificates[1:] {
+		if certificate.NotAfter.Before(notAfter) {
+			notAfter = certificate.NotAfter
+		}
+	}
+	return notAfter
+}
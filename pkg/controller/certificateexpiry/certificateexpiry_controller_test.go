@@ -0,0 +1,324 @@
+package certificateexpiry
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openshift/hive/pkg/apis"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+	"github.com/openshift/hive/pkg/remoteclient"
+	remoteclientmock "github.com/openshift/hive/pkg/remoteclient/mock"
+)
+
+const (
+	testName       = "test-cluster"
+	testNamespace  = "test-namespace"
+	testBundleName = "test-bundle"
+	testSecretName = "test-bundle-secret"
+	testAPIURL     = "https://api.test-cluster.example.com:6443"
+
+	day = 24 * time.Hour
+)
+
+func init() {
+	log.SetLevel(log.DebugLevel)
+}
+
+func TestReconcileCertificateExpiry(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	tests := []struct {
+		name string
+		cd   *hivev1.ClusterDeployment
+		// bundleExpiresIn is the time until the certificate bundle expires, no secret exists when zero.
+		bundleExpiresIn time.Duration
+		// servedExpiresIn is the time until the certificate served by the API server expires, the cluster is
+		// unreachable when zero.
+		servedExpiresIn time.Duration
+		expectCondition *hivev1.ClusterDeploymentCondition
+		expectEvent     string
+		expectMetrics   map[certificateID]time.Duration
+	}{
+		{
+			name: "not installed",
+			cd:   testClusterDeployment(func(cd *hivev1.ClusterDeployment) { cd.Spec.Installed = false }),
+		},
+		{
+			name:            "valid certificates",
+			cd:              testClusterDeployment(),
+			bundleExpiresIn: 60 * day,
+			servedExpiresIn: 60 * day,
+			expectMetrics: map[certificateID]time.Duration{
+				{source: certificateBundleSource, name: testBundleName}: 60 * day,
+				{source: apiServerSource, name: testAPIURL}:             60 * day,
+			},
+		},
+		{
+			name:            "expiring certificate bundle",
+			cd:              testClusterDeployment(),
+			bundleExpiresIn: 10 * day,
+			servedExpiresIn: 60 * day,
+			expectCondition: &hivev1.ClusterDeploymentCondition{
+				Status: corev1.ConditionTrue,
+				Reason: certificateExpiringReason,
+			},
+			expectEvent: "Warning CertificateExpiring certificate bundle test-bundle expires at",
+			expectMetrics: map[certificateID]time.Duration{
+				{source: certificateBundleSource, name: testBundleName}: 10 * day,
+				{source: apiServerSource, name: testAPIURL}:             60 * day,
+			},
+		},
+		{
+			name:            "expired served certificate",
+			cd:              testClusterDeployment(),
+			bundleExpiresIn: 60 * day,
+			servedExpiresIn: -day,
+			expectCondition: &hivev1.ClusterDeploymentCondition{
+				Status: corev1.ConditionTrue,
+				Reason: certificateExpiredReason,
+			},
+			expectEvent: "Warning CertificateExpired certificate served by API server " + testAPIURL + " expires at",
+			expectMetrics: map[certificateID]time.Duration{
+				{source: certificateBundleSource, name: testBundleName}: 60 * day,
+				{source: apiServerSource, name: testAPIURL}:             -day,
+			},
+		},
+		{
+			name: "renewed certificate",
+			cd: testClusterDeployment(func(cd *hivev1.ClusterDeployment) {
+				cd.Status.Conditions = []hivev1.ClusterDeploymentCondition{{
+					Type:    hivev1.CertificateExpiringCondition,
+					Status:  corev1.ConditionTrue,
+					Reason:  certificateExpiringReason,
+					Message: "certificate bundle test-bundle expires soon",
+				}}
+			}),
+			bundleExpiresIn: 60 * day,
+			servedExpiresIn: 60 * day,
+			expectCondition: &hivev1.ClusterDeploymentCondition{
+				Status: corev1.ConditionFalse,
+				Reason: certificatesValidReason,
+			},
+			expectEvent: "Normal CertificatesValid No certificates expire within",
+			expectMetrics: map[certificateID]time.Duration{
+				{source: certificateBundleSource, name: testBundleName}: 60 * day,
+				{source: apiServerSource, name: testAPIURL}:             60 * day,
+			},
+		},
+		{
+			name:            "unreachable cluster",
+			cd:              testClusterDeployment(),
+			bundleExpiresIn: 10 * day,
+			expectCondition: &hivev1.ClusterDeploymentCondition{
+				Status: corev1.ConditionTrue,
+				Reason: certificateExpiringReason,
+			},
+			expectEvent: "Warning CertificateExpiring certificate bundle test-bundle expires at",
+			expectMetrics: map[certificateID]time.Duration{
+				{source: certificateBundleSource, name: testBundleName}: 10 * day,
+			},
+		},
+		{
+			name:            "missing certificate bundle",
+			cd:              testClusterDeployment(),
+			servedExpiresIn: 60 * day,
+			expectMetrics: map[certificateID]time.Duration{
+				{source: apiServerSource, name: testAPIURL}: 60 * day,
+			},
+		},
+		{
+			name: "unused certificate bundle",
+			cd: testClusterDeployment(func(cd *hivev1.ClusterDeployment) {
+				cd.Spec.ControlPlaneConfig.ServingCertificates.Default = ""
+			}),
+			bundleExpiresIn: 10 * day,
+			servedExpiresIn: 60 * day,
+			expectMetrics: map[certificateID]time.Duration{
+				{source: apiServerSource, name: testAPIURL}: 60 * day,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			metricCertificateExpiry.Reset()
+
+			existing := []runtime.Object{test.cd}
+			now := time.Now().Truncate(time.Second)
+			if test.bundleExpiresIn != 0 {
+				existing = append(existing, &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: testSecretName, Namespace: testNamespace},
+					Type:       corev1.SecretTypeTLS,
+					Data: map[string][]byte{
+						corev1.TLSCertKey: pem.EncodeToMemory(&pem.Block{
+							Type:  "CERTIFICATE",
+							Bytes: testCertificate(t, now.Add(test.bundleExpiresIn)).Raw,
+						}),
+					},
+				})
+			}
+			fakeClient := fake.NewFakeClient(existing...)
+
+			mockRemoteClientBuilder := remoteclientmock.NewMockBuilder(mockCtrl)
+			if test.cd.Spec.Installed {
+				mockRemoteClientBuilder.EXPECT().Unreachable().Return(test.servedExpiresIn == 0)
+				if test.servedExpiresIn != 0 {
+					mockRemoteClientBuilder.EXPECT().APIURL().Return(testAPIURL, nil)
+					mockRemoteClientBuilder.EXPECT().ServingCertificates().
+						Return([]*x509.Certificate{testCertificate(t, now.Add(test.servedExpiresIn))}, nil)
+				}
+			}
+			fakeRecorder := record.NewFakeRecorder(10)
+
+			r := &ReconcileCertificateExpiry{
+				Client:        fakeClient,
+				scheme:        scheme.Scheme,
+				logger:        log.WithField("controller", controllerName),
+				eventRecorder: fakeRecorder,
+				warningWindow: defaultWarningWindow,
+				remoteClientBuilder: func(*hivev1.ClusterDeployment) remoteclient.Builder {
+					return mockRemoteClientBuilder
+				},
+				reported: map[types.NamespacedName]map[certificateID]bool{},
+			}
+
+			key := types.NamespacedName{Name: testName, Namespace: testNamespace}
+			_, err := r.Reconcile(reconcile.Request{NamespacedName: key})
+			require.NoError(t, err, "unexpected error from reconcile")
+
+			cd := &hivev1.ClusterDeployment{}
+			require.NoError(t, fakeClient.Get(context.TODO(), key, cd))
+			cond := controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.CertificateExpiringCondition)
+			if test.expectCondition == nil {
+				assert.Nil(t, cond, "unexpected certificate expiring condition")
+			} else if assert.NotNil(t, cond, "expected certificate expiring condition") {
+				assert.Equal(t, test.expectCondition.Status, cond.Status, "unexpected condition status")
+				assert.Equal(t, test.expectCondition.Reason, cond.Reason, "unexpected condition reason")
+			}
+
+			if test.expectEvent == "" {
+				assert.Empty(t, fakeRecorder.Events, "unexpected event")
+			} else if assert.Len(t, fakeRecorder.Events, 1, "expected event") {
+				assert.Contains(t, <-fakeRecorder.Events, test.expectEvent, "unexpected event")
+			}
+
+			for id, expiresIn := range test.expectMetrics {
+				assert.Equal(t, float64(now.Add(expiresIn).Unix()), testMetricValue(t, key, id), "unexpected metric value for %v", id)
+			}
+			assert.Equal(t, len(test.expectMetrics), testMetricCount(t), "unexpected number of metrics")
+
+			// Deleting the cluster deployment removes its metrics.
+			require.NoError(t, fakeClient.Delete(context.TODO(), cd))
+			_, err = r.Reconcile(reconcile.Request{NamespacedName: key})
+			require.NoError(t, err, "unexpected error from reconcile")
+			assert.Zero(t, testMetricCount(t), "expected metrics to be removed")
+		})
+	}
+}
+
+func TestParseCertificates(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	leaf := testCertificate(t, now.Add(60*day))
+	intermediate := testCertificate(t, now.Add(30*day))
+	chain := append(
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leaf.Raw}),
+		pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: intermediate.Raw})...,
+	)
+	certificates, err := parseCertificates(chain)
+	require.NoError(t, err, "unexpected error parsing certificates")
+	assert.Len(t, certificates, 2, "unexpected number of certificates")
+	assert.Equal(t, now.Add(30*day).UTC(), earliestNotAfter(certificates).UTC(), "unexpected earliest expiry")
+
+	_, err = parseCertificates([]byte("not a certificate"))
+	assert.Error(t, err, "expected error parsing invalid certificate")
+}
+
+func testClusterDeployment(modifiers ...func(*hivev1.ClusterDeployment)) *hivev1.ClusterDeployment {
+	cd := &hivev1.ClusterDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testName,
+			Namespace: testNamespace,
+		},
+		Spec: hivev1.ClusterDeploymentSpec{
+			ClusterName: testName,
+			BaseDomain:  "example.com",
+			Installed:   true,
+			ClusterMetadata: &hivev1.ClusterMetadata{
+				AdminKubeconfigSecretRef: corev1.LocalObjectReference{Name: "test-kubeconfig"},
+			},
+			CertificateBundles: []hivev1.CertificateBundleSpec{{
+				Name:                 testBundleName,
+				CertificateSecretRef: corev1.LocalObjectReference{Name: testSecretName},
+			}},
+			ControlPlaneConfig: hivev1.ControlPlaneConfigSpec{
+				ServingCertificates: hivev1.ControlPlaneServingCertificateSpec{
+					Default: testBundleName,
+				},
+			},
+		},
+	}
+	for _, modify := range modifiers {
+		modify(cd)
+	}
+	return cd
+}
+
+// testCertificate returns a self-signed certificate expiring at the specified time.
+func testCertificate(t *testing.T, notAfter time.Time) *x509.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err, "could not generate key")
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "test"},
+		NotBefore:    notAfter.Add(-90 * day),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err, "could not create certificate")
+	certificate, err := x509.ParseCertificate(der)
+	require.NoError(t, err, "could not parse certificate")
+	return certificate
+}
+
+func testMetricValue(t *testing.T, key types.NamespacedName, id certificateID) float64 {
+	gauge, err := metricCertificateExpiry.GetMetricWithLabelValues(key.Name, key.Namespace, id.source, id.name)
+	require.NoError(t, err, "could not get metric")
+	m := &dto.Metric{}
+	require.NoError(t, gauge.Write(m), "could not read metric")
+	return m.GetGauge().GetValue()
+}
+
+func testMetricCount(t *testing.T) int {
+	ch := make(chan prometheus.Metric, 100)
+	metricCertificateExpiry.Collect(ch)
+	close(ch)
+	return len(ch)
+}
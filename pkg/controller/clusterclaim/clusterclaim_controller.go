@@ -0,0 +1,420 @@
+package clusterclaim
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+	hivemetrics "github.com/openshift/hive/pkg/controller/metrics"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+)
+
+const (
+	controllerName = "clusterClaim"
+
+	// claimOwnerRoleName is the name of the Role and RoleBinding created in the namespace of a claimed cluster
+	// to give the subjects of the claim access to the cluster.
+	claimOwnerRoleName = "hive-claim-owner"
+)
+
+// Add creates a new ClusterClaim controller and adds it to the manager with default RBAC.
+func Add(mgr manager.Manager) error {
+	return AddToManager(mgr, NewReconciler(mgr))
+}
+
+// NewReconciler returns a new reconcile.Reconciler
+func NewReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileClusterClaim{
+		Client: controllerutils.NewClientWithMetricsOrDie(mgr, controllerName),
+		scheme: mgr.GetScheme(),
+		logger: log.WithField("controller", controllerName),
+	}
+}
+
+// AddToManager adds a new Controller to mgr with r as the reconcile.Reconciler
+func AddToManager(mgr manager.Manager, r reconcile.Reconciler) error {
+	c, err := controller.New("clusterclaim-controller", mgr, controller.Options{Reconciler: controllerutils.NewShardedReconciler(r), MaxConcurrentReconciles: controllerutils.GetConcurrentReconciles(controllerName)})
+	if err != nil {
+		log.WithField("controller", controllerName).WithError(err).Error("Error creating new clusterclaim controller")
+		return err
+	}
+
+	if err := controllerutils.SetQueueRateLimiter(c, controllerName); err != nil {
+		return err
+	}
+
+	// Watch for changes to ClusterClaims
+	if err := c.Watch(&source.Kind{Type: &hivev1.ClusterClaim{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		log.WithField("controller", controllerName).WithError(err).Error("Error watching cluster claims")
+		return err
+	}
+
+	// Watch for changes to ClusterDeployments that have been claimed
+	if err := c.Watch(&source.Kind{Type: &hivev1.ClusterDeployment{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(requestsForClusterDeployment),
+	}); err != nil {
+		log.WithField("controller", controllerName).WithError(err).Error("Error watching cluster deployments")
+		return err
+	}
+
+	return nil
+}
+
+func requestsForClusterDeployment(o handler.MapObject) []reconcile.Request {
+	cd, ok := o.Object.(*hivev1.ClusterDeployment)
+	if !ok {
+		return nil
+	}
+	ref := cd.Spec.ClusterPoolRef
+	if ref == nil || ref.ClaimName == "" {
+		return nil
+	}
+	return []reconcile.Request{{
+		NamespacedName: types.NamespacedName{
+			Namespace: ref.Namespace,
+			Name:      ref.ClaimName,
+		},
+	}}
+}
+
+var _ reconcile.Reconciler = &ReconcileClusterClaim{}
+
+// ReconcileClusterClaim reconciles a ClusterClaim object
+type ReconcileClusterClaim struct {
+	client.Client
+	scheme *runtime.Scheme
+	logger log.FieldLogger
+}
+
+// Reconcile reconciles a ClusterClaim. Claims are assigned a cluster by the ClusterPool controller, after which
+// the subjects of the claim are given access to the cluster. Deleting the claim deletes the claimed cluster.
+func (r *ReconcileClusterClaim) Reconcile(request reconcile.Request) (result reconcile.Result, returnErr error) {
+	start := time.Now()
+	logger := r.logger.WithFields(log.Fields{
+		"controller":   controllerName,
+		"clusterClaim": request.Name,
+		"namespace":    request.Namespace,
+	})
+
+	logger.Info("reconciling cluster claim")
+	defer func() {
+		dur := time.Since(start)
+		hivemetrics.MetricControllerReconcileTime.WithLabelValues(controllerName).Observe(dur.Seconds())
+		logger.WithField("elapsed", dur).Info("reconcile complete")
+	}()
+
+	// Fetch the ClusterClaim instance
+	claim := &hivev1.ClusterClaim{}
+	err := r.Get(context.TODO(), request.NamespacedName, claim)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Debug("claim not found")
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		logger.WithError(err).Error("error reading cluster claim")
+		return reconcile.Result{}, err
+	}
+
+	if claim.DeletionTimestamp != nil {
+		return reconcile.Result{}, r.reconcileDeletedClaim(claim, logger)
+	}
+
+	if !controllerutils.HasFinalizer(claim, hivev1.FinalizerClusterClaimCleanup) {
+		logger.Debug("adding cluster claim finalizer")
+		controllerutils.AddFinalizer(claim, hivev1.FinalizerClusterClaimCleanup)
+		if err := r.Update(context.TODO(), claim); err != nil {
+			logger.WithError(err).Log(controllerutils.LogLevel(err), "error adding finalizer")
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, nil
+	}
+
+	// Make sure the claim starts out pending so that there is a transition time for when the claim is assigned a
+	// cluster. This is persisted with the next status update.
+	if controllerutils.FindClusterClaimCondition(claim.Status.Conditions, hivev1.ClusterClaimPendingCondition) == nil {
+		claim.Status.Conditions = controllerutils.SetClusterClaimCondition(
+			claim.Status.Conditions,
+			hivev1.ClusterClaimPendingCondition,
+			corev1.ConditionTrue,
+			"Initialized",
+			"Initializing cluster claim",
+			controllerutils.UpdateConditionNever,
+		)
+	}
+
+	if claim.Spec.Namespace == "" {
+		logger.Debug("claim has not been assigned a cluster")
+		return reconcile.Result{}, r.setCondition(claim, logger, conditionUpdate{
+			conditionType: hivev1.ClusterClaimPendingCondition,
+			status:        corev1.ConditionTrue,
+			reason:        "NoClusters",
+			message:       "No clusters in the pool are ready to be claimed",
+		})
+	}
+
+	// The lifetime of the claim begins once the claim has been assigned a cluster.
+	if claim.Spec.Lifetime != nil {
+		if cond := controllerutils.FindClusterClaimCondition(claim.Status.Conditions, hivev1.ClusterClaimPendingCondition); cond != nil && cond.Status == corev1.ConditionFalse {
+			expired, expiry := controllerutils.IsExpired(cond.LastTransitionTime.Time, claim.Spec.Lifetime.Duration)
+			if expired {
+				logger.WithField("expiry", expiry).Info("claim has expired, issuing delete")
+				if err := r.Delete(context.TODO(), claim); err != nil {
+					logger.WithError(err).Log(controllerutils.LogLevel(err), "error deleting expired claim")
+					return reconcile.Result{}, err
+				}
+				return reconcile.Result{}, nil
+			}
+			defer func() {
+				if returnErr == nil && controllerutils.RequeueAtExpiry(&result, expiry) {
+					logger.Debugf("claim will re-sync due to expiry time in: %v", result.RequeueAfter)
+				}
+			}()
+		}
+	}
+
+	cd := &hivev1.ClusterDeployment{}
+	switch err := r.Get(context.TODO(), types.NamespacedName{Namespace: claim.Spec.Namespace, Name: claim.Spec.Namespace}, cd); {
+	case apierrors.IsNotFound(err):
+		logger.Info("claimed cluster has been deleted")
+		return reconcile.Result{}, r.setClusterDeleted(claim, logger)
+	case err != nil:
+		logger.WithError(err).Error("error getting claimed cluster deployment")
+		return reconcile.Result{}, err
+	}
+
+	if ref := cd.Spec.ClusterPoolRef; ref == nil || ref.Namespace != claim.Namespace || ref.ClaimName != claim.Name {
+		logger.WithField("cluster", cd.Name).Error("cluster deployment is not assigned to the claim")
+		return reconcile.Result{}, r.setCondition(claim, logger, conditionUpdate{
+			conditionType: hivev1.ClusterClaimPendingCondition,
+			status:        corev1.ConditionTrue,
+			reason:        "AssignmentConflict",
+			message:       fmt.Sprintf("ClusterDeployment %s is not assigned to this claim", cd.Name),
+		})
+	}
+
+	if cd.DeletionTimestamp != nil {
+		logger.Info("claimed cluster is being deleted")
+		return reconcile.Result{}, r.setClusterDeleted(claim, logger)
+	}
+
+	if err := r.applyClaimOwnerRBAC(claim, cd, logger); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	return reconcile.Result{}, r.setCondition(claim, logger, conditionUpdate{
+		conditionType: hivev1.ClusterClaimPendingCondition,
+		status:        corev1.ConditionFalse,
+		reason:        "ClusterClaimed",
+		message:       "Cluster claimed",
+	})
+}
+
+// reconcileDeletedClaim deletes the claimed ClusterDeployment, which deprovisions the cluster. Once the
+// ClusterDeployment is gone, the namespace created for it by the pool is deleted and the finalizer is removed
+// from the claim.
+func (r *ReconcileClusterClaim) reconcileDeletedClaim(claim *hivev1.ClusterClaim, logger log.FieldLogger) error {
+	if !controllerutils.HasFinalizer(claim, hivev1.FinalizerClusterClaimCleanup) {
+		return nil
+	}
+	if claim.Spec.Namespace != "" {
+		cd := &hivev1.ClusterDeployment{}
+		switch err := r.Get(context.TODO(), types.NamespacedName{Namespace: claim.Spec.Namespace, Name: claim.Spec.Namespace}, cd); {
+		case apierrors.IsNotFound(err):
+			logger.Debug("claimed cluster deployment has been removed")
+			if err := r.deletePoolNamespace(claim.Spec.Namespace, logger); err != nil {
+				return err
+			}
+		case err != nil:
+			logger.WithError(err).Error("error getting claimed cluster deployment")
+			return err
+		case cd.Spec.ClusterPoolRef == nil ||
+			cd.Spec.ClusterPoolRef.Namespace != claim.Namespace ||
+			cd.Spec.ClusterPoolRef.ClaimName != claim.Name:
+			logger.Warn("cluster deployment is not assigned to the claim, not deleting")
+		case cd.DeletionTimestamp != nil:
+			logger.Debug("waiting for claimed cluster deployment to be removed")
+			return nil
+		default:
+			logger.WithField("cluster", cd.Name).Info("deleting claimed cluster deployment")
+			if err := r.Delete(context.TODO(), cd); err != nil {
+				logger.WithError(err).Log(controllerutils.LogLevel(err), "error deleting claimed cluster deployment")
+				return err
+			}
+			return nil
+		}
+	}
+	controllerutils.DeleteFinalizer(claim, hivev1.FinalizerClusterClaimCleanup)
+	if err := r.Update(context.TODO(), claim); err != nil {
+		logger.WithError(err).Log(controllerutils.LogLevel(err), "error removing finalizer")
+		return err
+	}
+	return nil
+}
+
+// deletePoolNamespace deletes the namespace of a claimed cluster if it was created by a ClusterPool.
+func (r *ReconcileClusterClaim) deletePoolNamespace(name string, logger log.FieldLogger) error {
+	ns := &corev1.Namespace{}
+	switch err := r.Get(context.TODO(), types.NamespacedName{Name: name}, ns); {
+	case apierrors.IsNotFound(err):
+		return nil
+	case err != nil:
+		logger.WithError(err).Error("error getting namespace of claimed cluster")
+		return err
+	}
+	if ns.Labels[constants.ClusterPoolNameLabel] == "" || ns.DeletionTimestamp != nil {
+		return nil
+	}
+	logger.WithField("namespace", name).Info("deleting namespace of claimed cluster")
+	if err := r.Delete(context.TODO(), ns); err != nil {
+		logger.WithError(err).Log(controllerutils.LogLevel(err), "error deleting namespace of claimed cluster")
+		return err
+	}
+	return nil
+}
+
+// applyClaimOwnerRBAC gives the subjects of the claim access to the claimed ClusterDeployment and to its admin
+// kubeconfig and password secrets.
+func (r *ReconcileClusterClaim) applyClaimOwnerRBAC(claim *hivev1.ClusterClaim, cd *hivev1.ClusterDeployment, logger log.FieldLogger) error {
+	rules := []rbacv1.PolicyRule{{
+		APIGroups: []string{hivev1.SchemeGroupVersion.Group},
+		Resources: []string{"clusterdeployments"},
+		Verbs:     []string{"get", "list", "watch"},
+	}}
+	if metadata := cd.Spec.ClusterMetadata; metadata != nil {
+		secretNames := []string{metadata.AdminKubeconfigSecretRef.Name}
+		if metadata.AdminPasswordSecretRef.Name != "" {
+			secretNames = append(secretNames, metadata.AdminPasswordSecretRef.Name)
+		}
+		rules = append(rules, rbacv1.PolicyRule{
+			APIGroups:     []string{""},
+			Resources:     []string{"secrets"},
+			ResourceNames: secretNames,
+			Verbs:         []string{"get"},
+		})
+	}
+	role := &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: cd.Namespace,
+			Name:      claimOwnerRoleName,
+		},
+		Rules: rules,
+	}
+	key := types.NamespacedName{Namespace: cd.Namespace, Name: claimOwnerRoleName}
+	if err := r.createOrUpdate(role, key, func(existing runtime.Object) bool {
+		existingRole := existing.(*rbacv1.Role)
+		if reflect.DeepEqual(existingRole.Rules, role.Rules) {
+			return false
+		}
+		existingRole.Rules = role.Rules
+		return true
+	}, logger); err != nil {
+		return err
+	}
+
+	binding := &rbacv1.RoleBinding{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: cd.Namespace,
+			Name:      claimOwnerRoleName,
+		},
+		Subjects: claim.Spec.Subjects,
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "Role",
+			Name:     role.Name,
+		},
+	}
+	return r.createOrUpdate(binding, key, func(existing runtime.Object) bool {
+		existingBinding := existing.(*rbacv1.RoleBinding)
+		if reflect.DeepEqual(existingBinding.Subjects, binding.Subjects) {
+			return false
+		}
+		existingBinding.Subjects = binding.Subjects
+		return true
+	}, logger)
+}
+
+// createOrUpdate creates the given object if it does not exist. If it does exist, the mutate function is called
+// with the existing object and the object is updated if the function returns true.
+func (r *ReconcileClusterClaim) createOrUpdate(obj runtime.Object, key types.NamespacedName, mutate func(existing runtime.Object) bool, logger log.FieldLogger) error {
+	objLog := logger.WithFields(log.Fields{
+		"kind": reflect.TypeOf(obj).Elem().Name(),
+		"name": key.Name,
+	})
+	existing := obj.DeepCopyObject()
+	switch err := r.Get(context.TODO(), key, existing); {
+	case apierrors.IsNotFound(err):
+		objLog.Info("creating claim owner RBAC")
+		if err := r.Create(context.TODO(), obj); err != nil {
+			objLog.WithError(err).Log(controllerutils.LogLevel(err), "could not create claim owner RBAC")
+			return err
+		}
+		return nil
+	case err != nil:
+		objLog.WithError(err).Error("could not get claim owner RBAC")
+		return err
+	}
+	if !mutate(existing) {
+		return nil
+	}
+	objLog.Info("updating claim owner RBAC")
+	if err := r.Update(context.TODO(), existing); err != nil {
+		objLog.WithError(err).Log(controllerutils.LogLevel(err), "could not update claim owner RBAC")
+		return err
+	}
+	return nil
+}
+
+func (r *ReconcileClusterClaim) setClusterDeleted(claim *hivev1.ClusterClaim, logger log.FieldLogger) error {
+	return r.setCondition(claim, logger, conditionUpdate{
+		conditionType: hivev1.ClusterClaimClusterDeletedCondition,
+		status:        corev1.ConditionTrue,
+		reason:        "ClusterDeleted",
+		message:       "Assigned cluster has been deleted",
+	})
+}
+
+type conditionUpdate struct {
+	conditionType hivev1.ClusterClaimConditionType
+	status        corev1.ConditionStatus
+	reason        string
+	message       string
+}
+
+func (r *ReconcileClusterClaim) setCondition(claim *hivev1.ClusterClaim, logger log.FieldLogger, update conditionUpdate) error {
+	var changed bool
+	claim.Status.Conditions, changed = controllerutils.SetClusterClaimConditionWithChangeCheck(
+		claim.Status.Conditions,
+		update.conditionType,
+		update.status,
+		update.reason,
+		update.message,
+		controllerutils.UpdateConditionIfReasonOrMessageChange,
+	)
+	if !changed {
+		return nil
+	}
+	if err := r.Status().Update(context.TODO(), claim); err != nil {
+		logger.WithError(err).Log(controllerutils.LogLevel(err), "could not update status of cluster claim")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,321 @@
+package clusterclaim
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openshift/hive/pkg/apis"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+)
+
+const (
+	claimNamespace   = "claim-namespace"
+	claimName        = "test-claim"
+	clusterName      = "test-cluster"
+	kubeconfigSecret = "kubeconfig-secret"
+	passwordSecret   = "password-secret"
+)
+
+var testSubjects = []rbacv1.Subject{{
+	APIGroup: rbacv1.GroupName,
+	Kind:     rbacv1.UserKind,
+	Name:     "test-user",
+}}
+
+func TestReconcileClusterClaim(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	tests := []struct {
+		name                 string
+		claim                *hivev1.ClusterClaim
+		cd                   *hivev1.ClusterDeployment
+		existing             []runtime.Object
+		expectFinalizer      bool
+		expectPending        *corev1.ConditionStatus
+		expectPendingReason  string
+		expectClusterDeleted bool
+		expectRBAC           bool
+		expectClaimDeleted   bool
+		expectCDDeleted      bool
+		expectNSDeleted      bool
+		expectRequeueAfter   bool
+	}{
+		{
+			name:            "add finalizer",
+			claim:           testClaim(withoutFinalizer()),
+			expectFinalizer: true,
+		},
+		{
+			name:                "no cluster assigned",
+			claim:               testClaim(),
+			expectFinalizer:     true,
+			expectPending:       conditionStatus(corev1.ConditionTrue),
+			expectPendingReason: "NoClusters",
+		},
+		{
+			name:                "cluster assigned",
+			claim:               testClaim(withNamespace(clusterName)),
+			cd:                  testClusterDeployment(claimName),
+			expectFinalizer:     true,
+			expectPending:       conditionStatus(corev1.ConditionFalse),
+			expectPendingReason: "ClusterClaimed",
+			expectRBAC:          true,
+		},
+		{
+			name: "cluster assigned with lifetime",
+			claim: testClaim(withNamespace(clusterName), withLifetime(time.Hour),
+				withPendingCondition(corev1.ConditionFalse, time.Now().Add(-10*time.Minute))),
+			cd:                  testClusterDeployment(claimName),
+			expectFinalizer:     true,
+			expectPending:       conditionStatus(corev1.ConditionFalse),
+			expectPendingReason: "ClusterClaimed",
+			expectRBAC:          true,
+			expectRequeueAfter:  true,
+		},
+		{
+			name: "lifetime expired",
+			claim: testClaim(withNamespace(clusterName), withLifetime(time.Hour),
+				withPendingCondition(corev1.ConditionFalse, time.Now().Add(-2*time.Hour))),
+			cd:                 testClusterDeployment(claimName),
+			expectClaimDeleted: true,
+		},
+		{
+			name:                "cluster assigned to another claim",
+			claim:               testClaim(withNamespace(clusterName)),
+			cd:                  testClusterDeployment("other-claim"),
+			expectFinalizer:     true,
+			expectPending:       conditionStatus(corev1.ConditionTrue),
+			expectPendingReason: "AssignmentConflict",
+		},
+		{
+			name:                 "cluster deleted",
+			claim:                testClaim(withNamespace(clusterName)),
+			expectFinalizer:      true,
+			expectPending:        conditionStatus(corev1.ConditionTrue),
+			expectClusterDeleted: true,
+		},
+		{
+			name:            "deleted claim deletes cluster",
+			claim:           testClaim(withNamespace(clusterName), deleted()),
+			cd:              testClusterDeployment(claimName),
+			expectFinalizer: true,
+			expectCDDeleted: true,
+		},
+		{
+			name:               "deleted claim removes finalizer once cluster is gone",
+			claim:              testClaim(withNamespace(clusterName), deleted()),
+			existing:           []runtime.Object{testNamespace()},
+			expectClaimDeleted: true,
+			expectCDDeleted:    true,
+			expectNSDeleted:    true,
+		},
+		{
+			name:               "deleted claim does not delete cluster of another claim",
+			claim:              testClaim(withNamespace(clusterName), deleted()),
+			cd:                 testClusterDeployment("other-claim"),
+			expectClaimDeleted: true,
+		},
+		{
+			name:  "deleted claim does not delete cluster of a claim in another namespace",
+			claim: testClaim(withNamespace(clusterName), deleted()),
+			cd: func() *hivev1.ClusterDeployment {
+				cd := testClusterDeployment(claimName)
+				cd.Spec.ClusterPoolRef.Namespace = "other-namespace"
+				return cd
+			}(),
+			expectClaimDeleted: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			existing := append(test.existing, test.claim)
+			if test.cd != nil {
+				existing = append(existing, test.cd)
+			}
+			fakeClient := fake.NewFakeClient(existing...)
+			rcc := &ReconcileClusterClaim{
+				Client: fakeClient,
+				scheme: scheme.Scheme,
+				logger: log.WithField("controller", "clusterclaim"),
+			}
+
+			result, err := rcc.Reconcile(reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: claimNamespace, Name: claimName},
+			})
+			require.NoError(t, err, "unexpected error from Reconcile")
+			assert.Equal(t, test.expectRequeueAfter, result.RequeueAfter > 0, "unexpected requeue after")
+
+			claim := &hivev1.ClusterClaim{}
+			err = fakeClient.Get(context.Background(), types.NamespacedName{Namespace: claimNamespace, Name: claimName}, claim)
+			if test.expectClaimDeleted {
+				if err == nil {
+					assert.False(t, controllerutils.HasFinalizer(claim, hivev1.FinalizerClusterClaimCleanup), "expected claim finalizer to be removed")
+				} else {
+					assert.True(t, apierrors.IsNotFound(err), "unexpected error getting claim")
+				}
+			} else {
+				require.NoError(t, err, "unexpected error getting claim")
+				assert.Equal(t, test.expectFinalizer, controllerutils.HasFinalizer(claim, hivev1.FinalizerClusterClaimCleanup),
+					"unexpected finalizer")
+				pending := controllerutils.FindClusterClaimCondition(claim.Status.Conditions, hivev1.ClusterClaimPendingCondition)
+				if test.expectPending == nil {
+					assert.Nil(t, pending, "unexpected pending condition")
+				} else if assert.NotNil(t, pending, "expected pending condition") {
+					assert.Equal(t, *test.expectPending, pending.Status, "unexpected pending condition status")
+					if test.expectPendingReason != "" {
+						assert.Equal(t, test.expectPendingReason, pending.Reason, "unexpected pending condition reason")
+					}
+				}
+				deletedCond := controllerutils.FindClusterClaimCondition(claim.Status.Conditions, hivev1.ClusterClaimClusterDeletedCondition)
+				assert.Equal(t, test.expectClusterDeleted, deletedCond != nil && deletedCond.Status == corev1.ConditionTrue,
+					"unexpected cluster deleted condition")
+			}
+
+			cd := &hivev1.ClusterDeployment{}
+			err = fakeClient.Get(context.Background(), types.NamespacedName{Namespace: clusterName, Name: clusterName}, cd)
+			if test.expectCDDeleted {
+				assert.True(t, apierrors.IsNotFound(err), "expected cluster deployment to be deleted")
+			} else if test.cd != nil {
+				assert.NoError(t, err, "expected cluster deployment to exist")
+			}
+
+			ns := &corev1.Namespace{}
+			err = fakeClient.Get(context.Background(), types.NamespacedName{Name: clusterName}, ns)
+			if test.expectNSDeleted {
+				assert.True(t, apierrors.IsNotFound(err), "expected namespace to be deleted")
+			}
+
+			role := &rbacv1.Role{}
+			roleErr := fakeClient.Get(context.Background(), types.NamespacedName{Namespace: clusterName, Name: claimOwnerRoleName}, role)
+			binding := &rbacv1.RoleBinding{}
+			bindingErr := fakeClient.Get(context.Background(), types.NamespacedName{Namespace: clusterName, Name: claimOwnerRoleName}, binding)
+			if test.expectRBAC {
+				if assert.NoError(t, roleErr, "expected claim owner role") {
+					var secretNames []string
+					for _, rule := range role.Rules {
+						secretNames = append(secretNames, rule.ResourceNames...)
+					}
+					assert.ElementsMatch(t, []string{kubeconfigSecret, passwordSecret}, secretNames, "unexpected secrets in role")
+				}
+				if assert.NoError(t, bindingErr, "expected claim owner role binding") {
+					assert.Equal(t, testSubjects, binding.Subjects, "unexpected subjects in role binding")
+					assert.Equal(t, claimOwnerRoleName, binding.RoleRef.Name, "unexpected role in role binding")
+				}
+			} else {
+				assert.True(t, apierrors.IsNotFound(roleErr), "unexpected claim owner role")
+				assert.True(t, apierrors.IsNotFound(bindingErr), "unexpected claim owner role binding")
+			}
+		})
+	}
+}
+
+type claimOption func(*hivev1.ClusterClaim)
+
+func withoutFinalizer() claimOption {
+	return func(c *hivev1.ClusterClaim) {
+		c.Finalizers = nil
+	}
+}
+
+func withNamespace(namespace string) claimOption {
+	return func(c *hivev1.ClusterClaim) {
+		c.Spec.Namespace = namespace
+	}
+}
+
+func withLifetime(lifetime time.Duration) claimOption {
+	return func(c *hivev1.ClusterClaim) {
+		c.Spec.Lifetime = &metav1.Duration{Duration: lifetime}
+	}
+}
+
+func withPendingCondition(status corev1.ConditionStatus, transitionTime time.Time) claimOption {
+	return func(c *hivev1.ClusterClaim) {
+		c.Status.Conditions = append(c.Status.Conditions, hivev1.ClusterClaimCondition{
+			Type:               hivev1.ClusterClaimPendingCondition,
+			Status:             status,
+			Reason:             "ClusterClaimed",
+			Message:            "Cluster claimed",
+			LastTransitionTime: metav1.NewTime(transitionTime),
+		})
+	}
+}
+
+func deleted() claimOption {
+	return func(c *hivev1.ClusterClaim) {
+		now := metav1.Now()
+		c.DeletionTimestamp = &now
+	}
+}
+
+func testClaim(opts ...claimOption) *hivev1.ClusterClaim {
+	c := &hivev1.ClusterClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  claimNamespace,
+			Name:       claimName,
+			Finalizers: []string{hivev1.FinalizerClusterClaimCleanup},
+		},
+		Spec: hivev1.ClusterClaimSpec{
+			ClusterPoolName: "test-pool",
+			Subjects:        testSubjects,
+		},
+	}
+	for _, o := range opts {
+		o(c)
+	}
+	return c
+}
+
+func testClusterDeployment(claimName string) *hivev1.ClusterDeployment {
+	return &hivev1.ClusterDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: clusterName,
+			Name:      clusterName,
+		},
+		Spec: hivev1.ClusterDeploymentSpec{
+			Installed: true,
+			ClusterMetadata: &hivev1.ClusterMetadata{
+				AdminKubeconfigSecretRef: corev1.LocalObjectReference{Name: kubeconfigSecret},
+				AdminPasswordSecretRef:   corev1.LocalObjectReference{Name: passwordSecret},
+			},
+			ClusterPoolRef: &hivev1.ClusterPoolReference{
+				Namespace: claimNamespace,
+				PoolName:  "test-pool",
+				ClaimName: claimName,
+			},
+		},
+	}
+}
+
+func testNamespace() *corev1.Namespace {
+	return &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: clusterName,
+			Labels: map[string]string{
+				constants.ClusterPoolNameLabel: "test-pool",
+			},
+		},
+	}
+}
+
+func conditionStatus(status corev1.ConditionStatus) *corev1.ConditionStatus {
+	return &status
+}
@@ -41,6 +41,9 @@ import (
 	controllerutils "github.com/openshift/hive/pkg/controller/utils"
 	"github.com/openshift/hive/pkg/imageset"
 	"github.com/openshift/hive/pkg/install"
+	"github.com/openshift/hive/pkg/installlogarchive"
+	"github.com/openshift/hive/pkg/manageddns"
+	"github.com/openshift/hive/pkg/releaseimage"
 	"github.com/openshift/hive/pkg/remoteclient"
 )
 
@@ -52,21 +55,36 @@ const (
 	defaultRequeueTime = 10 * time.Second
 	maxProvisions      = 3
 
+	// installLogArchiveUploadExpiry is how long the install manager can upload the logs of a provision to the
+	// install log archive.
+	installLogArchiveUploadExpiry = 24 * time.Hour
+
 	rawAdminKubeconfigKey = "raw-kubeconfig"
 
 	clusterImageSetNotFoundReason = "ClusterImageSetNotFound"
 	clusterImageSetFoundReason    = "ClusterImageSetFound"
 
+	installerImageResolvedReason         = "InstallerImageResolved"
+	installerImageResolutionFailedReason = "InstallerImageResolutionFailed"
+
+	releaseImageAllowedReason    = "ReleaseImageAllowed"
+	releaseImageNotAllowedReason = "ReleaseImageNotAllowed"
+
+	// releaseImageSignatureRecheckInterval is how often the signatures of a release image are looked for again when
+	// none could be verified.
+	releaseImageSignatureRecheckInterval = 10 * time.Minute
+
 	dnsNotReadyReason  = "DNSNotReady"
 	dnsReadyReason     = "DNSReady"
 	dnsReadyAnnotation = "hive.openshift.io/dnsready"
 
-	deleteAfterAnnotation    = "hive.openshift.io/delete-after" // contains a duration after which the cluster should be cleaned up.
-	tryInstallOnceAnnotation = "hive.openshift.io/try-install-once"
+	deleteAfterAnnotation = "hive.openshift.io/delete-after" // contains a duration after which the cluster should be cleaned up.
 
 	platformAWS       = "aws"
 	platformAzure     = "azure"
 	platformGCP       = "gcp"
+	platformOpenStack = "openstack"
+	platformVSphere   = "vsphere"
 	platformBaremetal = "baremetal"
 	platformUnknown   = "unknown"
 	regionUnknown     = "unknown"
@@ -142,22 +160,47 @@ func init() {
 
 // Add creates a new ClusterDeployment controller and adds it to the manager with default RBAC.
 func Add(mgr manager.Manager) error {
-	return AddToManager(mgr, NewReconciler(mgr))
+	r, err := NewReconciler(mgr)
+	if err != nil {
+		return err
+	}
+	return AddToManager(mgr, r)
 }
 
 // NewReconciler returns a new reconcile.Reconciler
-func NewReconciler(mgr manager.Manager) reconcile.Reconciler {
+func NewReconciler(mgr manager.Manager) (reconcile.Reconciler, error) {
 	logger := log.WithField("controller", controllerName)
+	releaseImagePolicy, err := releaseimage.PolicyFromEnvironment()
+	if err != nil {
+		logger.WithError(err).Error("cannot read release image policy")
+		return nil, err
+	}
+	installLogArchive, err := installlogarchive.ConfigFromEnvironment()
+	if err != nil {
+		logger.WithError(err).Error("cannot read install log archive configuration")
+		return nil, err
+	}
+	managedDomains, err := manageddns.ReadManagedDomainsFile()
+	if err != nil {
+		logger.WithError(err).Error("cannot read managed domains file")
+		return nil, err
+	}
 	r := &ReconcileClusterDeployment{
-		Client:       controllerutils.NewClientWithMetricsOrDie(mgr, controllerName),
-		scheme:       mgr.GetScheme(),
-		logger:       logger,
-		expectations: controllerutils.NewExpectations(logger),
+		Client:             controllerutils.NewClientWithMetricsOrDie(mgr, controllerName),
+		scheme:             mgr.GetScheme(),
+		logger:             logger,
+		expectations:       controllerutils.NewExpectations(logger),
+		releaseImagePolicy: releaseImagePolicy,
+		installLogArchive:  installLogArchive,
+		managedDomains:     managedDomains,
 	}
 	r.remoteClusterAPIClientBuilder = func(cd *hivev1.ClusterDeployment) remoteclient.Builder {
 		return remoteclient.NewBuilder(r.Client, cd, controllerName)
 	}
-	return r
+	if verifier := releaseimage.NewSignatureVerifier(r.Client, releaseImagePolicy); verifier != nil {
+		r.verifyReleaseImageSignature = verifier.Verify
+	}
+	return r, nil
 }
 
 // AddToManager adds a new Controller to mgr with r as the reconcile.Reconciler
@@ -167,12 +210,16 @@ func AddToManager(mgr manager.Manager, r reconcile.Reconciler) error {
 		return errors.New("reconciler supplied is not a ReconcileClusterDeployment")
 	}
 
-	c, err := controller.New("clusterdeployment-controller", mgr, controller.Options{Reconciler: r, MaxConcurrentReconciles: controllerutils.GetConcurrentReconciles()})
+	c, err := controller.New("clusterdeployment-controller", mgr, controller.Options{Reconciler: controllerutils.NewShardedReconciler(r), MaxConcurrentReconciles: controllerutils.GetConcurrentReconciles(controllerName)})
 	if err != nil {
 		log.WithField("controller", controllerName).WithError(err).Error("Error getting new cluster deployment")
 		return err
 	}
 
+	if err := controllerutils.SetQueueRateLimiter(c, controllerName); err != nil {
+		return err
+	}
+
 	// Watch for changes to ClusterDeployment
 	err = c.Watch(&source.Kind{Type: &hivev1.ClusterDeployment{}}, &handler.EnqueueRequestForObject{})
 	if err != nil {
@@ -233,9 +280,44 @@ func AddToManager(mgr manager.Manager, r reconcile.Reconciler) error {
 		return fmt.Errorf("cannot start watch on syncset instance: %v", err)
 	}
 
+	// Watch for changes to ClusterImageSet, whose resolved images are used by installing clusters
+	err = c.Watch(&source.Kind{Type: &hivev1.ClusterImageSet{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(cdReconciler.clusterImageSetWatchHandler),
+	})
+	if err != nil {
+		return fmt.Errorf("cannot start watch on clusterimageset: %v", err)
+	}
+
 	return nil
 }
 
+// clusterImageSetWatchHandler requests the reconcile of the installing ClusterDeployments that are waiting for the
+// images of the ClusterImageSet to be resolved.
+func (r *ReconcileClusterDeployment) clusterImageSetWatchHandler(a handler.MapObject) []reconcile.Request {
+	cdList := &hivev1.ClusterDeploymentList{}
+	if err := r.List(context.TODO(), cdList); err != nil {
+		r.logger.WithError(err).Error("error listing cluster deployments")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, cd := range cdList.Items {
+		if cd.Spec.Installed || cd.Spec.Provisioning == nil || cd.Spec.Provisioning.ReleaseImage != "" {
+			continue
+		}
+		if imageSetRef := cd.Spec.Provisioning.ImageSetRef; imageSetRef == nil || imageSetRef.Name != a.Meta.GetName() {
+			continue
+		}
+		if cd.Status.InstallerImage != nil && cd.Status.CLIImage != nil {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+			Name:      cd.Name,
+			Namespace: cd.Namespace,
+		}})
+	}
+	return requests
+}
+
 var _ reconcile.Reconciler = &ReconcileClusterDeployment{}
 
 // ReconcileClusterDeployment reconciles a ClusterDeployment object
@@ -250,6 +332,20 @@ type ReconcileClusterDeployment struct {
 	// remoteClusterAPIClientBuilder is a function pointer to the function that gets a builder for building a client
 	// for the remote cluster's API server
 	remoteClusterAPIClientBuilder func(cd *hivev1.ClusterDeployment) remoteclient.Builder
+
+	// releaseImagePolicy restricts the release images from which clusters can be installed.
+	releaseImagePolicy *hivev1.ReleaseImagePolicy
+
+	// verifyReleaseImageSignature is a function pointer to the function that verifies the signatures of release
+	// images required by the release image policy, if any.
+	verifyReleaseImageSignature func(releaseImage string) error
+
+	// installLogArchive is the object store to which install logs are uploaded, if any.
+	installLogArchive *hivev1.InstallLogArchiveConfig
+
+	// managedDomains are the domains for which DNS can be managed, used to find the DNS servers updated through
+	// RFC2136 dynamic updates that host the zones of clusters.
+	managedDomains []hivev1.ManageDNSConfig
 }
 
 // Reconcile reads that state of the cluster for a ClusterDeployment object and makes changes based on the state read
@@ -297,6 +393,11 @@ func (r *ReconcileClusterDeployment) addAdditionalKubeconfigCAs(cd *hivev1.Clust
 
 	adminKubeconfigSecret := &corev1.Secret{}
 	if err := r.Get(context.Background(), types.NamespacedName{Namespace: cd.Namespace, Name: cd.Spec.ClusterMetadata.AdminKubeconfigSecretRef.Name}, adminKubeconfigSecret); err != nil {
+		if apierrors.IsNotFound(err) && cd.Spec.ClusterMetadata.HiveManagedKubeconfigSecretRef != nil {
+			// The admin kubeconfig may be removed once hive has minted its own kubeconfig.
+			cdLog.Debug("admin kubeconfig secret not found, using hive-managed kubeconfig")
+			return nil
+		}
 		cdLog.WithError(err).Error("failed to get admin kubeconfig secret")
 		return err
 	}
@@ -400,9 +501,9 @@ func (r *ReconcileClusterDeployment) reconcile(request reconcile.Request, cd *hi
 			return reconcile.Result{}, fmt.Errorf("error parsing %s as a duration: %v", deleteAfterAnnotation, err)
 		}
 		if !cd.CreationTimestamp.IsZero() {
-			expiry := cd.CreationTimestamp.Add(dur)
+			expired, expiry := controllerutils.IsExpired(cd.CreationTimestamp.Time, dur)
 			cdLog.Debugf("cluster expires at: %s", expiry)
-			if time.Now().After(expiry) {
+			if expired {
 				cdLog.WithField("expiry", expiry).Info("cluster has expired, issuing delete")
 				err := r.Delete(context.TODO(), cd)
 				if err != nil {
@@ -412,15 +513,10 @@ func (r *ReconcileClusterDeployment) reconcile(request reconcile.Request, cd *hi
 			}
 
 			defer func() {
-				requeueNow := result.Requeue && result.RequeueAfter <= 0
-				if returnErr == nil && !requeueNow {
-					// We have an expiry time but we're not expired yet. Set requeueAfter for just after expiry time
-					// so that we requeue cluster for deletion once reconcile has completed
-					requeueAfter := time.Until(expiry) + 60*time.Second
-					if requeueAfter < result.RequeueAfter || result.RequeueAfter <= 0 {
-						cdLog.Debugf("cluster will re-sync due to expiry time in: %v", requeueAfter)
-						result.RequeueAfter = requeueAfter
-					}
+				// We have an expiry time but we're not expired yet. Set requeueAfter for just after expiry time
+				// so that we requeue cluster for deletion once reconcile has completed
+				if returnErr == nil && controllerutils.RequeueAtExpiry(&result, expiry) {
+					cdLog.Debugf("cluster will re-sync due to expiry time in: %v", result.RequeueAfter)
 				}
 			}()
 
@@ -487,6 +583,33 @@ func (r *ReconcileClusterDeployment) reconcile(request reconcile.Request, cd *hi
 
 	releaseImage := r.getReleaseImage(cd, imageSet, cdLog)
 
+	// Check the release image against the release image policy before anything is run from it. The release image
+	// of a provision that has already started is not checked again.
+	if cd.Status.ProvisionRef == nil && releaseImage != "" {
+		policyErr := releaseimage.CheckPolicy(r.releaseImagePolicy, releaseImage)
+		if policyErr == nil && r.verifyReleaseImageSignature != nil {
+			switch err := r.verifyReleaseImageSignature(releaseImage); err.(type) {
+			case nil:
+			case *releaseimage.SignatureNotVerifiedError:
+				policyErr = err
+			default:
+				cdLog.WithError(err).Error("cannot verify the signatures of the release image")
+				return reconcile.Result{}, err
+			}
+		}
+		if err := r.setReleaseImageNotAllowedCondition(cd, policyErr, cdLog); err != nil {
+			return reconcile.Result{}, err
+		}
+		if policyErr != nil {
+			cdLog.WithError(policyErr).Warn("release image is not allowed by the release image policy")
+			if _, ok := policyErr.(*releaseimage.SignatureNotVerifiedError); ok {
+				// Signatures may be published after the cluster is created.
+				return reconcile.Result{RequeueAfter: releaseImageSignatureRecheckInterval}, nil
+			}
+			return reconcile.Result{}, nil
+		}
+	}
+
 	cdLog.Debug("loading pull secrets")
 	pullSecret, err := r.mergePullSecrets(cd, cdLog)
 	if err != nil {
@@ -518,7 +641,7 @@ func (r *ReconcileClusterDeployment) reconcile(request reconcile.Request, cd *hi
 	}
 
 	if cd.Status.ProvisionRef == nil {
-		if cd.Status.InstallRestarts > 0 && cd.Annotations[tryInstallOnceAnnotation] == "true" {
+		if cd.Status.InstallRestarts > 0 && cd.Annotations[constants.TryInstallOnceAnnotation] == "true" {
 			cdLog.Debug("not creating new provision since the deployment is set to try install only once")
 			return reconcile.Result{}, nil
 		}
@@ -594,6 +717,13 @@ func (r *ReconcileClusterDeployment) startNewProvision(
 		return reconcile.Result{}, err
 	}
 
+	if r.installLogArchive != nil {
+		if err := r.addInstallLogArchiveUploads(podSpec, cd.Namespace, provisionName); err != nil {
+			cdLog.WithError(err).Error("could not presign install log archive uploads")
+			return reconcile.Result{}, err
+		}
+	}
+
 	provision := &hivev1.ClusterProvision{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      provisionName,
@@ -641,6 +771,29 @@ func (r *ReconcileClusterDeployment) startNewProvision(
 	return reconcile.Result{}, nil
 }
 
+// addInstallLogArchiveUploads passes the install manager presigned URLs to which it uploads the logs of the
+// provision, so that the credentials of the install log archive are not exposed to the install pod.
+func (r *ReconcileClusterDeployment) addInstallLogArchiveUploads(podSpec *corev1.PodSpec, namespace, provisionName string) error {
+	s3Client, err := installlogarchive.NewClient(r, r.installLogArchive, constants.HiveNamespace)
+	if err != nil {
+		return err
+	}
+	uploads, err := installlogarchive.PresignUploads(s3Client, r.installLogArchive, namespace, provisionName, installLogArchiveUploadExpiry)
+	if err != nil {
+		return err
+	}
+	envVar, err := installlogarchive.UploadsEnvVar(uploads)
+	if err != nil {
+		return err
+	}
+	for i := range podSpec.Containers {
+		if podSpec.Containers[i].Name == install.InstallManagerContainerName {
+			podSpec.Containers[i].Env = append(podSpec.Containers[i].Env, envVar)
+		}
+	}
+	return nil
+}
+
 func (r *ReconcileClusterDeployment) reconcileExistingProvision(cd *hivev1.ClusterDeployment, cdLog log.FieldLogger) (result reconcile.Result, returnedErr error) {
 	cdLog = cdLog.WithField("provision", cd.Status.ProvisionRef.Name)
 	cdLog.Debug("reconciling existing provision")
@@ -698,6 +851,10 @@ func (r *ReconcileClusterDeployment) reconcileExistingProvision(cd *hivev1.Clust
 }
 
 func (r *ReconcileClusterDeployment) reconcileFailedProvision(cd *hivev1.ClusterDeployment, provision *hivev1.ClusterProvision, cdLog log.FieldLogger) (reconcile.Result, error) {
+	if failure := provision.Status.InstallFailure; failure != nil && !failure.Retryable {
+		return r.stopProvisioning(cd, provision, cdLog)
+	}
+
 	nextProvisionTime := time.Now()
 	reason := "MissingCondition"
 
@@ -734,6 +891,34 @@ func (r *ReconcileClusterDeployment) reconcileFailedProvision(cd *hivev1.Cluster
 	return r.clearOutCurrentProvision(cd, cdLog)
 }
 
+// stopProvisioning leaves the failed provision in place without starting a new provision, as the failure of the
+// provision will not be resolved by retrying. Deleting the failed provision will start a new provision.
+func (r *ReconcileClusterDeployment) stopProvisioning(cd *hivev1.ClusterDeployment, provision *hivev1.ClusterProvision, cdLog log.FieldLogger) (reconcile.Result, error) {
+	failure := provision.Status.InstallFailure
+	cdLog.WithField("reason", failure.Reason).Info("provision failed with an error that is not retryable, not starting a new provision")
+	conds, failedChanged := controllerutils.SetClusterDeploymentConditionWithChangeCheck(
+		cd.Status.Conditions,
+		hivev1.ProvisionFailedCondition,
+		corev1.ConditionTrue,
+		failure.Reason,
+		fmt.Sprintf("Provision %s failed. %s.", provision.Name, failure.Message),
+		controllerutils.UpdateConditionIfReasonOrMessageChange,
+	)
+	conds, stoppedChanged := controllerutils.SetClusterDeploymentConditionWithChangeCheck(
+		conds,
+		hivev1.ProvisionStoppedCondition,
+		corev1.ConditionTrue,
+		failure.Reason,
+		fmt.Sprintf("Provision %s failed with an error that is not retryable. Delete the provision to try again.", provision.Name),
+		controllerutils.UpdateConditionIfReasonOrMessageChange,
+	)
+	if !failedChanged && !stoppedChanged {
+		return reconcile.Result{}, nil
+	}
+	cd.Status.Conditions = conds
+	return reconcile.Result{}, r.statusUpdate(cd, cdLog)
+}
+
 func (r *ReconcileClusterDeployment) reconcileCompletedProvision(cd *hivev1.ClusterDeployment, provision *hivev1.ClusterProvision, cdLog log.FieldLogger) (reconcile.Result, error) {
 	cdLog.Info("provision completed successfully")
 
@@ -817,6 +1002,14 @@ func (r *ReconcileClusterDeployment) reconcileCompletedProvision(cd *hivev1.Clus
 func (r *ReconcileClusterDeployment) clearOutCurrentProvision(cd *hivev1.ClusterDeployment, cdLog log.FieldLogger) (reconcile.Result, error) {
 	cd.Status.ProvisionRef = nil
 	cd.Status.InstallRestarts = cd.Status.InstallRestarts + 1
+	cd.Status.Conditions = controllerutils.SetClusterDeploymentCondition(
+		cd.Status.Conditions,
+		hivev1.ProvisionStoppedCondition,
+		corev1.ConditionFalse,
+		"ProvisionCleared",
+		"The failed provision has been cleared out for a new provision",
+		controllerutils.UpdateConditionNever,
+	)
 	if err := r.Status().Update(context.TODO(), cd); err != nil {
 		cdLog.WithError(err).Log(controllerutils.LogLevel(err), "could not clear out current provision")
 		return reconcile.Result{}, err
@@ -936,12 +1129,23 @@ func (r *ReconcileClusterDeployment) resolveInstallerImage(cd *hivev1.ClusterDep
 
 	existingJob := &batchv1.Job{}
 	switch err := r.Get(context.Background(), jobKey, existingJob); {
-	// The job does not exist. If the images have been resolved, continue reconciling. Otherwise, create the job.
+	// The job does not exist. If the images have been resolved, continue reconciling. Otherwise, use the images
+	// resolved for the ClusterImageSet, or create the job when the release image is set on the cluster itself or
+	// could not be resolved for the ClusterImageSet.
 	case apierrors.IsNotFound(err):
 		if areImagesResolved {
 			return nil, nil
 		}
 
+		if cd.Spec.Provisioning.ReleaseImage == "" && imageSet != nil {
+			if !isClusterImageSetInvalid(imageSet) {
+				return r.setImagesFromClusterImageSet(cd, imageSet, cdLog)
+			}
+			// The global pull secret may not grant access to the release image, while the pull secret of the
+			// cluster does.
+			cdLog.WithField("clusterimageset", imageSet.Name).Info("clusterimageset is not valid, resolving its release image with the pull secret of the cluster")
+		}
+
 		// If the .status.clusterVersionsStatus.availableUpdates field is nil,
 		// do a status update to set it to an empty list. All status updates
 		// done by controllers set this automatically. However, the imageset
@@ -1017,6 +1221,68 @@ func (r *ReconcileClusterDeployment) resolveInstallerImage(cd *hivev1.ClusterDep
 	}
 }
 
+// setImagesFromClusterImageSet sets the installer and cli images of the cluster to the images resolved by the
+// clusterimageset controller for the ClusterImageSet of the cluster. It waits for the ClusterImageSet to be resolved.
+func (r *ReconcileClusterDeployment) setImagesFromClusterImageSet(cd *hivev1.ClusterDeployment, imageSet *hivev1.ClusterImageSet, cdLog log.FieldLogger) (*reconcile.Result, error) {
+	var valid *hivev1.ClusterImageSetCondition
+	if imageSet.Status.ReleaseImage == imageSet.Spec.ReleaseImage {
+		valid = controllerutils.FindClusterImageSetCondition(imageSet.Status.Conditions, hivev1.ClusterImageSetValidCondition)
+	}
+	if valid == nil {
+		cdLog.WithField("clusterimageset", imageSet.Name).Debug("waiting for the release image of the clusterimageset to be resolved")
+		return &reconcile.Result{}, nil
+	}
+
+	installerImage := imageSet.Status.InstallerImage
+	if cd.Spec.Platform.BareMetal != nil {
+		installerImage = imageSet.Status.BareMetalInstallerImage
+	}
+	var message string
+	switch {
+	case valid.Status != corev1.ConditionTrue:
+		message = fmt.Sprintf("ClusterImageSet %s is not valid: %s", imageSet.Name, valid.Message)
+	case installerImage == "":
+		message = fmt.Sprintf("ClusterImageSet %s has no installer image for the platform of the cluster", imageSet.Name)
+	}
+	if message != "" {
+		conditions, changed := controllerutils.SetClusterDeploymentConditionWithChangeCheck(
+			cd.Status.Conditions,
+			hivev1.InstallerImageResolutionFailedCondition,
+			corev1.ConditionTrue,
+			installerImageResolutionFailedReason,
+			message,
+			controllerutils.UpdateConditionIfReasonOrMessageChange)
+		if !changed {
+			return &reconcile.Result{}, nil
+		}
+		cd.Status.Conditions = conditions
+		return &reconcile.Result{}, r.statusUpdate(cd, cdLog)
+	}
+
+	cliImage := imageSet.Status.CLIImage
+	cdLog.WithField("installerImage", installerImage).Info("using installer image resolved for clusterimageset")
+	cd.Status.InstallerImage = &installerImage
+	cd.Status.CLIImage = &cliImage
+	cd.Status.Conditions = controllerutils.SetClusterDeploymentCondition(
+		cd.Status.Conditions,
+		hivev1.InstallerImageResolutionFailedCondition,
+		corev1.ConditionFalse,
+		installerImageResolvedReason,
+		"InstallerImage is resolved.",
+		controllerutils.UpdateConditionNever)
+	return &reconcile.Result{}, r.statusUpdate(cd, cdLog)
+}
+
+// isClusterImageSetInvalid returns whether the clusterimageset controller failed to resolve the current release image
+// of the ClusterImageSet.
+func isClusterImageSetInvalid(imageSet *hivev1.ClusterImageSet) bool {
+	if imageSet.Status.ReleaseImage != imageSet.Spec.ReleaseImage {
+		return false
+	}
+	valid := controllerutils.FindClusterImageSetCondition(imageSet.Status.Conditions, hivev1.ClusterImageSetValidCondition)
+	return valid != nil && valid.Status == corev1.ConditionFalse
+}
+
 func (r *ReconcileClusterDeployment) setDNSNotReadyCondition(cd *hivev1.ClusterDeployment, isReady bool, message string, cdLog log.FieldLogger) error {
 	status := corev1.ConditionFalse
 	reason := dnsReadyReason
@@ -1067,6 +1333,32 @@ func (r *ReconcileClusterDeployment) setImageSetNotFoundCondition(cd *hivev1.Clu
 	return err
 }
 
+// setReleaseImageNotAllowedCondition sets the ReleaseImageNotAllowed condition to true when the release image of the
+// cluster is not allowed by the release image policy, as reported by policyErr, or to false otherwise.
+func (r *ReconcileClusterDeployment) setReleaseImageNotAllowedCondition(cd *hivev1.ClusterDeployment, policyErr error, cdLog log.FieldLogger) error {
+	status := corev1.ConditionFalse
+	reason := releaseImageAllowedReason
+	message := "The release image is allowed by the release image policy"
+	if policyErr != nil {
+		status = corev1.ConditionTrue
+		reason = releaseImageNotAllowedReason
+		message = policyErr.Error()
+	}
+	conds, changed := controllerutils.SetClusterDeploymentConditionWithChangeCheck(
+		cd.Status.Conditions,
+		hivev1.ReleaseImageNotAllowedCondition,
+		status,
+		reason,
+		message,
+		controllerutils.UpdateConditionIfReasonOrMessageChange)
+	if !changed {
+		return nil
+	}
+	cdLog.Infof("setting ReleaseImageNotAllowedCondition to %v", status)
+	cd.Status.Conditions = conds
+	return r.statusUpdate(cd, cdLog)
+}
+
 // setClusterStatusURLs fetches the openshift console route from the remote cluster and uses it to determine
 // the correct APIURL and WebConsoleURL, and then set them in the Status. Typically only called if these Status fields
 // are unset.
@@ -1314,7 +1606,8 @@ func (r *ReconcileClusterDeployment) setDNSDelayMetric(cd *hivev1.ClusterDeploym
 }
 
 func (r *ReconcileClusterDeployment) ensureManagedDNSZone(cd *hivev1.ClusterDeployment, cdLog log.FieldLogger) (*hivev1.DNSZone, error) {
-	if cd.Spec.Platform.AWS == nil && cd.Spec.Platform.GCP == nil {
+	rfc2136Domain := r.rfc2136ManagedDomain(cd)
+	if rfc2136Domain == nil && cd.Spec.Platform.AWS == nil && cd.Spec.Platform.GCP == nil {
 		cdLog.Error("cluster deployment platform does not support managed DNS")
 		if err := r.setDNSNotReadyCondition(cd, false, "Managed DNS is not supported for platform", cdLog); err != nil {
 			cdLog.WithError(err).Log(controllerutils.LogLevel(err), "could not update DNSNotReadyCondition")
@@ -1323,6 +1616,17 @@ func (r *ReconcileClusterDeployment) ensureManagedDNSZone(cd *hivev1.ClusterDepl
 		return nil, errors.New("managed DNS not supported on platform")
 	}
 
+	// The TSIG key of the managed domain is only used to delegate the zone of the cluster, the zone itself is updated
+	// with a key of the cluster.
+	if rfc2136Domain != nil && cd.Spec.RFC2136TSIGKeySecretRef == nil {
+		cdLog.Error("cluster deployment has no TSIG key for managed DNS in RFC2136 managed domain")
+		if err := r.setDNSNotReadyCondition(cd, false, "Managed DNS in RFC2136 managed domain requires a TSIG key for the cluster", cdLog); err != nil {
+			cdLog.WithError(err).Log(controllerutils.LogLevel(err), "could not update DNSNotReadyCondition")
+			return nil, err
+		}
+		return nil, errors.New("no TSIG key for managed DNS in RFC2136 managed domain")
+	}
+
 	dnsZone := &hivev1.DNSZone{}
 	dnsZoneNamespacedName := types.NamespacedName{Namespace: cd.Namespace, Name: controllerutils.DNSZoneName(cd.Name)}
 	logger := cdLog.WithField("zone", dnsZoneNamespacedName.String())
@@ -1376,7 +1680,15 @@ func (r *ReconcileClusterDeployment) createManagedDNSZone(cd *hivev1.ClusterDepl
 		},
 	}
 
+	// The zone of a cluster in an RFC2136 managed domain is hosted on the DNS server of the managed domain, whatever
+	// the platform of the cluster.
+	rfc2136Domain := r.rfc2136ManagedDomain(cd)
 	switch {
+	case rfc2136Domain != nil:
+		dnsZone.Spec.RFC2136 = &hivev1.RFC2136DNSZoneSpec{
+			Server:           rfc2136Domain.RFC2136.Server,
+			TSIGKeySecretRef: *cd.Spec.RFC2136TSIGKeySecretRef,
+		}
 	case cd.Spec.Platform.AWS != nil:
 		additionalTags := make([]hivev1.AWSResourceTag, 0, len(cd.Spec.Platform.AWS.UserTags))
 		for k, v := range cd.Spec.Platform.AWS.UserTags {
@@ -1395,6 +1707,11 @@ func (r *ReconcileClusterDeployment) createManagedDNSZone(cd *hivev1.ClusterDepl
 		dnsZone.Spec.GCP = &hivev1.GCPDNSZoneSpec{
 			CredentialsSecretRef: cd.Spec.Platform.GCP.CredentialsSecretRef,
 		}
+	case cd.Spec.Platform.Azure != nil:
+		dnsZone.Spec.Azure = &hivev1.AzureDNSZoneSpec{
+			CredentialsSecretRef: cd.Spec.Platform.Azure.CredentialsSecretRef,
+			ResourceGroupName:    cd.Spec.Platform.Azure.BaseDomainResourceGroupName,
+		}
 	}
 
 	logger.WithField("derivedObject", dnsZone.Name).Debug("Setting labels on derived object")
@@ -1414,6 +1731,16 @@ func (r *ReconcileClusterDeployment) createManagedDNSZone(cd *hivev1.ClusterDepl
 	return nil
 }
 
+// rfc2136ManagedDomain returns the managed domain of the base domain of the cluster deployment if it is hosted on a
+// DNS server updated through RFC2136 dynamic updates, or nil otherwise.
+func (r *ReconcileClusterDeployment) rfc2136ManagedDomain(cd *hivev1.ClusterDeployment) *hivev1.ManageDNSConfig {
+	md := manageddns.FindManagedDomain(r.managedDomains, cd.Spec.BaseDomain)
+	if md == nil || md.RFC2136 == nil {
+		return nil
+	}
+	return md
+}
+
 func selectorPodWatchHandler(a handler.MapObject) []reconcile.Request {
 	retval := []reconcile.Request{}
 
@@ -1503,6 +1830,17 @@ func generateDeprovision(cd *hivev1.ClusterDeployment) (*hivev1.ClusterDeprovisi
 			Region:               cd.Spec.Platform.GCP.Region,
 			CredentialsSecretRef: &cd.Spec.Platform.GCP.CredentialsSecretRef,
 		}
+	case cd.Spec.Platform.OpenStack != nil:
+		req.Spec.Platform.OpenStack = &hivev1.OpenStackClusterDeprovision{
+			Cloud:                cd.Spec.Platform.OpenStack.Cloud,
+			CredentialsSecretRef: &cd.Spec.Platform.OpenStack.CredentialsSecretRef,
+		}
+	case cd.Spec.Platform.VSphere != nil:
+		req.Spec.Platform.VSphere = &hivev1.VSphereClusterDeprovision{
+			CredentialsSecretRef:  cd.Spec.Platform.VSphere.CredentialsSecretRef,
+			CertificatesSecretRef: cd.Spec.Platform.VSphere.CertificatesSecretRef,
+			VCenter:               cd.Spec.Platform.VSphere.VCenter,
+		}
 	default:
 		return nil, errors.New("unsupported cloud provider for deprovision")
 	}
@@ -1847,6 +2185,10 @@ func getClusterPlatform(cd *hivev1.ClusterDeployment) string {
 		return platformAzure
 	case cd.Spec.Platform.GCP != nil:
 		return platformGCP
+	case cd.Spec.Platform.OpenStack != nil:
+		return platformOpenStack
+	case cd.Spec.Platform.VSphere != nil:
+		return platformVSphere
 	case cd.Spec.Platform.BareMetal != nil:
 		return platformBaremetal
 	}
@@ -2,6 +2,7 @@ package clusterdeployment
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"testing"
@@ -37,6 +38,9 @@ import (
 	"github.com/openshift/hive/pkg/apis/hive/v1/baremetal"
 	"github.com/openshift/hive/pkg/constants"
 	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+	"github.com/openshift/hive/pkg/install"
+	"github.com/openshift/hive/pkg/installlogarchive"
+	"github.com/openshift/hive/pkg/releaseimage"
 	"github.com/openshift/hive/pkg/remoteclient"
 	remoteclientmock "github.com/openshift/hive/pkg/remoteclient/mock"
 )
@@ -115,6 +119,10 @@ func TestClusterDeploymentReconcile(t *testing.T) {
 		expectedRequeueAfter    time.Duration
 		expectPendingCreation   bool
 		expectConsoleRouteFetch bool
+		releaseImagePolicy      *hivev1.ReleaseImagePolicy
+		signatureErr            error
+		installLogArchive       *hivev1.InstallLogArchiveConfig
+		managedDomains          []hivev1.ManageDNSConfig
 		validate                func(client.Client, *testing.T)
 	}{
 		{
@@ -491,11 +499,10 @@ func TestClusterDeploymentReconcile(t *testing.T) {
 				func() *hivev1.ClusterDeployment {
 					cd := testClusterDeployment()
 					cd.Status.InstallerImage = nil
-					cd.Spec.Provisioning.ImageSetRef = &hivev1.ClusterImageSetReference{Name: testClusterImageSetName}
+					cd.Spec.Provisioning.ReleaseImage = "embedded-release-image:latest"
 					cd.Status.ClusterVersionStatus.AvailableUpdates = []openshiftapiv1.Update{}
 					return cd
 				}(),
-				testClusterImageSet(),
 				testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, "{}"),
 				testSecret(corev1.SecretTypeDockerConfigJson, constants.GetMergedPullSecretName(testClusterDeployment()), corev1.DockerConfigJsonKey, "{}"),
 			},
@@ -504,11 +511,11 @@ func TestClusterDeploymentReconcile(t *testing.T) {
 				if job == nil {
 					t.Errorf("did not find expected imageset job")
 				}
-				// Ensure that the release image from the imageset is used in the job
+				// Ensure that the release image from the clusterdeployment is used in the job
 				envVars := job.Spec.Template.Spec.Containers[0].Env
 				for _, e := range envVars {
 					if e.Name == "RELEASE_IMAGE" {
-						if e.Value != testClusterImageSet().Spec.ReleaseImage {
+						if e.Value != "embedded-release-image:latest" {
 							t.Errorf("unexpected release image used in job: %s", e.Value)
 						}
 						break
@@ -521,6 +528,88 @@ func TestClusterDeploymentReconcile(t *testing.T) {
 				assert.Equal(t, constants.JobTypeImageSet, job.Labels[constants.JobTypeLabel], "incorrect job type label")
 			},
 		},
+		{
+			name: "Use images resolved for clusterimageset",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Status.InstallerImage = nil
+					cd.Status.CLIImage = nil
+					cd.Spec.Provisioning.ImageSetRef = &hivev1.ClusterImageSetReference{Name: testClusterImageSetName}
+					return cd
+				}(),
+				testResolvedClusterImageSet(corev1.ConditionTrue),
+				testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, "{}"),
+				testSecret(corev1.SecretTypeDockerConfigJson, constants.GetMergedPullSecretName(testClusterDeployment()), corev1.DockerConfigJsonKey, "{}"),
+			},
+			validate: func(c client.Client, t *testing.T) {
+				assert.Nil(t, getImageSetJob(c), "unexpected imageset job")
+				cd := getCD(c)
+				require.NotNil(t, cd, "missing clusterdeployment")
+				if assert.NotNil(t, cd.Status.InstallerImage, "missing installer image") {
+					assert.Equal(t, "quay.io/release@sha256:installer", *cd.Status.InstallerImage, "unexpected installer image")
+				}
+				if assert.NotNil(t, cd.Status.CLIImage, "missing cli image") {
+					assert.Equal(t, "quay.io/release@sha256:cli", *cd.Status.CLIImage, "unexpected cli image")
+				}
+			},
+		},
+		{
+			name: "Wait for clusterimageset to be resolved",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Status.InstallerImage = nil
+					cd.Spec.Provisioning.ImageSetRef = &hivev1.ClusterImageSetReference{Name: testClusterImageSetName}
+					return cd
+				}(),
+				func() *hivev1.ClusterImageSet {
+					cis := testResolvedClusterImageSet(corev1.ConditionTrue)
+					cis.Status.ReleaseImage = "previous-release-image:latest"
+					return cis
+				}(),
+				testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, "{}"),
+				testSecret(corev1.SecretTypeDockerConfigJson, constants.GetMergedPullSecretName(testClusterDeployment()), corev1.DockerConfigJsonKey, "{}"),
+			},
+			validate: func(c client.Client, t *testing.T) {
+				assert.Nil(t, getImageSetJob(c), "unexpected imageset job")
+				assert.Empty(t, getProvisions(c), "unexpected provision")
+				cd := getCD(c)
+				require.NotNil(t, cd, "missing clusterdeployment")
+				assert.Nil(t, cd.Status.InstallerImage, "unexpected installer image")
+			},
+		},
+		{
+			name: "Fall back to imageset job for invalid clusterimageset",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Status.InstallerImage = nil
+					cd.Spec.Provisioning.ImageSetRef = &hivev1.ClusterImageSetReference{Name: testClusterImageSetName}
+					cd.Status.ClusterVersionStatus.AvailableUpdates = []openshiftapiv1.Update{}
+					return cd
+				}(),
+				testResolvedClusterImageSet(corev1.ConditionFalse),
+				testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, "{}"),
+				testSecret(corev1.SecretTypeDockerConfigJson, constants.GetMergedPullSecretName(testClusterDeployment()), corev1.DockerConfigJsonKey, "{}"),
+			},
+			validate: func(c client.Client, t *testing.T) {
+				job := getImageSetJob(c)
+				if assert.NotNil(t, job, "expected imageset job") {
+					// Ensure that the release image from the clusterimageset is used in the job
+					var releaseImage string
+					for _, e := range job.Spec.Template.Spec.Containers[0].Env {
+						if e.Name == "RELEASE_IMAGE" {
+							releaseImage = e.Value
+						}
+					}
+					assert.Equal(t, testClusterImageSet().Spec.ReleaseImage, releaseImage, "unexpected release image used in job")
+				}
+				cd := getCD(c)
+				require.NotNil(t, cd, "missing clusterdeployment")
+				assert.Nil(t, cd.Status.InstallerImage, "unexpected installer image")
+			},
+		},
 		{
 			name: "Delete imageset job when complete",
 			existing: []runtime.Object{
@@ -633,6 +722,64 @@ func TestClusterDeploymentReconcile(t *testing.T) {
 				assert.Equal(t, constants.DNSZoneTypeChild, zone.Labels[constants.DNSZoneTypeLabel], "incorrect dnszone type label")
 			},
 		},
+		{
+			name: "Create RFC2136 DNSZone when manageDNS is true for RFC2136 managed domain",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Spec.ManageDNS = true
+					cd.Spec.BaseDomain = "test.rfc2136.example.com"
+					cd.Spec.RFC2136TSIGKeySecretRef = &corev1.LocalObjectReference{Name: "cluster-tsig-key"}
+					cd.Spec.Platform.AWS = nil
+					cd.Spec.Platform.BareMetal = &baremetal.Platform{}
+					cd.Labels[hivev1.HiveClusterPlatformLabel] = "baremetal"
+					return cd
+				}(),
+				testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, "{}"),
+				testSecret(corev1.SecretTypeDockerConfigJson, constants.GetMergedPullSecretName(testClusterDeployment()), corev1.DockerConfigJsonKey, "{}"),
+			},
+			managedDomains: testRFC2136ManagedDomains(),
+			validate: func(c client.Client, t *testing.T) {
+				zone := getDNSZone(c)
+				require.NotNil(t, zone, "dns zone should exist")
+				assert.Nil(t, zone.Spec.AWS, "unexpected AWS zone")
+				assert.Equal(t, &hivev1.RFC2136DNSZoneSpec{
+					Server:           "ns.rfc2136.example.com:53",
+					TSIGKeySecretRef: corev1.LocalObjectReference{Name: "cluster-tsig-key"},
+				}, zone.Spec.RFC2136, "unexpected RFC2136 zone")
+				secrets := &corev1.SecretList{}
+				require.NoError(t, c.List(context.TODO(), secrets, client.InNamespace(testNamespace)), "error listing secrets")
+				for _, secret := range secrets.Items {
+					assert.NotEqual(t, "tsig-key", secret.Name, "unexpected copy of managed domain TSIG key secret")
+				}
+			},
+		},
+		{
+			name: "No DNSZone without TSIG key for RFC2136 managed domain",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Spec.ManageDNS = true
+					cd.Spec.BaseDomain = "test.rfc2136.example.com"
+					return cd
+				}(),
+				testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, "{}"),
+				testSecret(corev1.SecretTypeDockerConfigJson, constants.GetMergedPullSecretName(testClusterDeployment()), corev1.DockerConfigJsonKey, "{}"),
+			},
+			managedDomains: testRFC2136ManagedDomains(),
+			expectErr:      true,
+			validate: func(c client.Client, t *testing.T) {
+				assert.Nil(t, getDNSZone(c), "unexpected DNSZone")
+				cd := getCD(c)
+				if assert.NotNil(t, cd, "missing clusterdeployment") {
+					cond := controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.DNSNotReadyCondition)
+					if assert.NotNil(t, cond, "expected to find condition") {
+						assert.Equal(t, corev1.ConditionTrue, cond.Status, "unexpected condition status")
+						assert.Equal(t, "Managed DNS in RFC2136 managed domain requires a TSIG key for the cluster", cond.Message, "unexpected condition message")
+					}
+				}
+			},
+		},
 		{
 			name: "Wait when DNSZone is not available yet",
 			existing: []runtime.Object{
@@ -916,6 +1063,43 @@ func TestClusterDeploymentReconcile(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "Stop provisioning after not retryable failure",
+			existing: []runtime.Object{
+				testClusterDeploymentWithProvision(),
+				func() runtime.Object {
+					provision := testFailedProvisionTime(time.Now().Add(-2 * time.Minute))
+					provision.Status.InstallFailure = &hivev1.InstallFailure{
+						Reason:    "AWSVPCLimitExceeded",
+						Message:   "AWS VPC limit exceeded",
+						Stage:     hivev1.InstallFailureStageInfrastructure,
+						Retryable: false,
+					}
+					return provision
+				}(),
+				testMetadataConfigMap(),
+				testSecret(corev1.SecretTypeOpaque, adminKubeconfigSecret, "kubeconfig", adminKubeconfig),
+				testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, "{}"),
+				testSecret(corev1.SecretTypeDockerConfigJson, constants.GetMergedPullSecretName(testClusterDeployment()), corev1.DockerConfigJsonKey, "{}"),
+			},
+			validate: func(c client.Client, t *testing.T) {
+				cd := getCD(c)
+				if assert.NotNil(t, cd, "missing clusterdeployment") {
+					if assert.NotNil(t, cd.Status.ProvisionRef, "missing provision ref") {
+						assert.Equal(t, provisionName, cd.Status.ProvisionRef.Name, "unexpected provision ref name")
+					}
+					assert.Equal(t, 0, cd.Status.InstallRestarts, "expected install restart count to not change")
+					cond := controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.ProvisionStoppedCondition)
+					if assert.NotNil(t, cond, "missing provision stopped condition") {
+						assert.Equal(t, corev1.ConditionTrue, cond.Status, "unexpected provision stopped condition status")
+					}
+					cond = controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.ProvisionFailedCondition)
+					if assert.NotNil(t, cond, "missing provision failed condition") {
+						assert.Equal(t, "AWSVPCLimitExceeded", cond.Reason, "unexpected provision failed condition reason")
+					}
+				}
+			},
+		},
 		{
 			name: "Clear out provision after wait time",
 			existing: []runtime.Object{
@@ -1136,6 +1320,181 @@ func TestClusterDeploymentReconcile(t *testing.T) {
 				require.Equal(t, clusterImageSetNotFoundReason, cd.Status.Conditions[0].Reason)
 			},
 		},
+		{
+			name: "Set release image not allowed condition",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Status.InstallerImage = nil
+					cd.Spec.Provisioning.ImageSetRef = &hivev1.ClusterImageSetReference{Name: testClusterImageSetName}
+					return cd
+				}(),
+				testClusterImageSet(),
+				testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, "{}"),
+				testSecret(corev1.SecretTypeDockerConfigJson, constants.GetMergedPullSecretName(testClusterDeployment()), corev1.DockerConfigJsonKey, "{}"),
+			},
+			releaseImagePolicy: &hivev1.ReleaseImagePolicy{AllowedRegistries: []string{"quay.io"}},
+			validate: func(c client.Client, t *testing.T) {
+				assert.Nil(t, getImageSetJob(c), "unexpected imageset job")
+				assert.Empty(t, getProvisions(c), "unexpected provision")
+				cd := getCD(c)
+				require.NotNil(t, cd, "missing clusterdeployment")
+				cond := controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.ReleaseImageNotAllowedCondition)
+				if assert.NotNil(t, cond, "missing release image not allowed condition") {
+					assert.Equal(t, corev1.ConditionTrue, cond.Status, "unexpected condition status")
+					assert.Equal(t, releaseImageNotAllowedReason, cond.Reason, "unexpected condition reason")
+					assert.Contains(t, cond.Message, "test-release-image:latest", "expected release image in condition message")
+				}
+			},
+		},
+		{
+			name: "Clear release image not allowed condition",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Spec.Provisioning.ReleaseImage = "quay.io/openshift-release-dev/ocp-release:4.5.2-x86_64"
+					cd.Status.Conditions = []hivev1.ClusterDeploymentCondition{{
+						Type:   hivev1.ReleaseImageNotAllowedCondition,
+						Status: corev1.ConditionTrue,
+						Reason: releaseImageNotAllowedReason,
+					}}
+					return cd
+				}(),
+				testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, "{}"),
+				testSecret(corev1.SecretTypeDockerConfigJson, constants.GetMergedPullSecretName(testClusterDeployment()), corev1.DockerConfigJsonKey, "{}"),
+			},
+			releaseImagePolicy:    &hivev1.ReleaseImagePolicy{AllowedRegistries: []string{"quay.io"}},
+			expectPendingCreation: true,
+			validate: func(c client.Client, t *testing.T) {
+				assert.Len(t, getProvisions(c), 1, "expected provision to exist")
+				cd := getCD(c)
+				require.NotNil(t, cd, "missing clusterdeployment")
+				cond := controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.ReleaseImageNotAllowedCondition)
+				if assert.NotNil(t, cond, "missing release image not allowed condition") {
+					assert.Equal(t, corev1.ConditionFalse, cond.Status, "unexpected condition status")
+					assert.Equal(t, releaseImageAllowedReason, cond.Reason, "unexpected condition reason")
+				}
+			},
+		},
+		{
+			name: "Block provision of release image without verified signature",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Spec.Provisioning.ReleaseImage = "quay.io/openshift-release-dev/ocp-release@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+					return cd
+				}(),
+				testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, "{}"),
+				testSecret(corev1.SecretTypeDockerConfigJson, constants.GetMergedPullSecretName(testClusterDeployment()), corev1.DockerConfigJsonKey, "{}"),
+			},
+			releaseImagePolicy: &hivev1.ReleaseImagePolicy{
+				SignatureVerification: &hivev1.ReleaseImageSignatureVerification{},
+			},
+			signatureErr:         &releaseimage.SignatureNotVerifiedError{ReleaseImage: "quay.io/openshift-release-dev/ocp-release"},
+			expectedRequeueAfter: releaseImageSignatureRecheckInterval,
+			validate: func(c client.Client, t *testing.T) {
+				assert.Empty(t, getProvisions(c), "unexpected provision")
+				cd := getCD(c)
+				require.NotNil(t, cd, "missing clusterdeployment")
+				cond := controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.ReleaseImageNotAllowedCondition)
+				if assert.NotNil(t, cond, "missing release image not allowed condition") {
+					assert.Equal(t, corev1.ConditionTrue, cond.Status, "unexpected condition status")
+					assert.Equal(t, releaseImageNotAllowedReason, cond.Reason, "unexpected condition reason")
+					assert.Contains(t, cond.Message, "no signature verified", "expected signature error in condition message")
+				}
+			},
+		},
+		{
+			name: "Retry signature verification errors",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Spec.Provisioning.ReleaseImage = "quay.io/openshift-release-dev/ocp-release@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+					return cd
+				}(),
+				testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, "{}"),
+				testSecret(corev1.SecretTypeDockerConfigJson, constants.GetMergedPullSecretName(testClusterDeployment()), corev1.DockerConfigJsonKey, "{}"),
+			},
+			releaseImagePolicy: &hivev1.ReleaseImagePolicy{
+				SignatureVerification: &hivev1.ReleaseImageSignatureVerification{},
+			},
+			signatureErr: fmt.Errorf("signature store unavailable"),
+			expectErr:    true,
+			validate: func(c client.Client, t *testing.T) {
+				assert.Empty(t, getProvisions(c), "unexpected provision")
+				cd := getCD(c)
+				require.NotNil(t, cd, "missing clusterdeployment")
+				assert.Nil(t, controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.ReleaseImageNotAllowedCondition),
+					"unexpected release image not allowed condition")
+			},
+		},
+		{
+			name: "Provision release image with verified signature",
+			existing: []runtime.Object{
+				func() *hivev1.ClusterDeployment {
+					cd := testClusterDeployment()
+					cd.Spec.Provisioning.ReleaseImage = "quay.io/openshift-release-dev/ocp-release@sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+					return cd
+				}(),
+				testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, "{}"),
+				testSecret(corev1.SecretTypeDockerConfigJson, constants.GetMergedPullSecretName(testClusterDeployment()), corev1.DockerConfigJsonKey, "{}"),
+			},
+			releaseImagePolicy: &hivev1.ReleaseImagePolicy{
+				SignatureVerification: &hivev1.ReleaseImageSignatureVerification{},
+			},
+			expectPendingCreation: true,
+			validate: func(c client.Client, t *testing.T) {
+				assert.Len(t, getProvisions(c), 1, "expected provision to exist")
+			},
+		},
+		{
+			name: "Pass install log archive uploads to install manager",
+			existing: []runtime.Object{
+				testClusterDeployment(),
+				testSecret(corev1.SecretTypeDockerConfigJson, pullSecretSecret, corev1.DockerConfigJsonKey, "{}"),
+				testSecret(corev1.SecretTypeDockerConfigJson, constants.GetMergedPullSecretName(testClusterDeployment()), corev1.DockerConfigJsonKey, "{}"),
+				&corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "install-log-archive-creds",
+						Namespace: constants.HiveNamespace,
+					},
+					Data: map[string][]byte{
+						"aws_access_key_id":     []byte("access-key"),
+						"aws_secret_access_key": []byte("secret-key"),
+					},
+				},
+			},
+			installLogArchive: &hivev1.InstallLogArchiveConfig{
+				Endpoint:             "http://minio.example.com:9000",
+				Bucket:               "install-logs",
+				CredentialsSecretRef: corev1.LocalObjectReference{Name: "install-log-archive-creds"},
+			},
+			expectPendingCreation: true,
+			validate: func(c client.Client, t *testing.T) {
+				provisions := getProvisions(c)
+				require.Len(t, provisions, 1, "expected provision to exist")
+				var uploadsEnvVar *corev1.EnvVar
+				for _, container := range provisions[0].Spec.PodSpec.Containers {
+					for i, envVar := range container.Env {
+						if envVar.Name != constants.InstallLogArchiveUploadsEnvVar {
+							continue
+						}
+						assert.Equal(t, install.InstallManagerContainerName, container.Name, "uploads passed to unexpected container")
+						uploadsEnvVar = &container.Env[i]
+					}
+				}
+				if assert.NotNil(t, uploadsEnvVar, "missing install log archive uploads") {
+					uploads := &installlogarchive.Uploads{}
+					require.NoError(t, json.Unmarshal([]byte(uploadsEnvVar.Value), uploads), "unexpected error parsing uploads")
+					assert.Equal(t, "install-logs", uploads.Bucket, "unexpected bucket")
+					assert.Len(t, uploads.Objects, len(installlogarchive.Logs), "unexpected number of uploads")
+					for _, upload := range uploads.Objects {
+						assert.Equal(t, fmt.Sprintf("%s/%s/%s", testNamespace, provisions[0].Name, upload.Log), upload.Key, "unexpected key")
+						assert.Contains(t, upload.URL, "http://minio.example.com:9000/install-logs/"+upload.Key, "unexpected upload URL")
+					}
+				}
+			},
+		},
 	}
 
 	for _, test := range tests {
@@ -1152,6 +1511,12 @@ func TestClusterDeploymentReconcile(t *testing.T) {
 				logger:                        logger,
 				expectations:                  controllerExpectations,
 				remoteClusterAPIClientBuilder: func(*hivev1.ClusterDeployment) remoteclient.Builder { return mockRemoteClientBuilder },
+				releaseImagePolicy:            test.releaseImagePolicy,
+				installLogArchive:             test.installLogArchive,
+				managedDomains:                test.managedDomains,
+			}
+			if test.releaseImagePolicy != nil && test.releaseImagePolicy.SignatureVerification != nil {
+				rcd.verifyReleaseImageSignature = func(string) error { return test.signatureErr }
 			}
 
 			reconcileRequest := reconcile.Request{
@@ -1538,6 +1903,21 @@ func testMetadataConfigMap() *corev1.ConfigMap {
 	return cm
 }
 
+func testRFC2136ManagedDomains() []hivev1.ManageDNSConfig {
+	return []hivev1.ManageDNSConfig{
+		{
+			Domains: []string{"example.com"},
+		},
+		{
+			Domains: []string{"rfc2136.example.com"},
+			RFC2136: &hivev1.ManageDNSRFC2136Config{
+				Server:           "ns.rfc2136.example.com:53",
+				TSIGKeySecretRef: corev1.LocalObjectReference{Name: "tsig-key"},
+			},
+		},
+	}
+}
+
 func testSecret(secretType corev1.SecretType, name, key, value string) *corev1.Secret {
 	s := &corev1.Secret{
 		Type: secretType,
@@ -1571,6 +1951,25 @@ func testClusterImageSet() *hivev1.ClusterImageSet {
 	return cis
 }
 
+func testResolvedClusterImageSet(valid corev1.ConditionStatus) *hivev1.ClusterImageSet {
+	cis := testClusterImageSet()
+	cis.Status.ReleaseImage = cis.Spec.ReleaseImage
+	message := "The metadata of the release image is resolved."
+	if valid == corev1.ConditionTrue {
+		cis.Status.Version = "4.5.2"
+		cis.Status.InstallerImage = "quay.io/release@sha256:installer"
+		cis.Status.CLIImage = "quay.io/release@sha256:cli"
+	} else {
+		message = "Failed to resolve release image: manifest unknown"
+	}
+	cis.Status.Conditions = []hivev1.ClusterImageSetCondition{{
+		Type:    hivev1.ClusterImageSetValidCondition,
+		Status:  valid,
+		Message: message,
+	}}
+	return cis
+}
+
 func testDNSZone() *hivev1.DNSZone {
 	zone := &hivev1.DNSZone{}
 	zone.Name = testName + "-zone"
@@ -85,12 +85,16 @@ func newReconciler(mgr manager.Manager) (reconcile.Reconciler, error) {
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
 func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	// Create a new controller
-	c, err := controller.New("clusterdeprovision-controller", mgr, controller.Options{Reconciler: r})
+	c, err := controller.New("clusterdeprovision-controller", mgr, controller.Options{Reconciler: controllerutils.NewShardedReconciler(r), MaxConcurrentReconciles: controllerutils.GetConcurrentReconcilesOrDefault(controllerName, 1)})
 	if err != nil {
 		log.WithField("controller", controllerName).WithError(err).Error("Error getting new clusterdeprovision-controller")
 		return err
 	}
 
+	if err := controllerutils.SetQueueRateLimiter(c, controllerName); err != nil {
+		return err
+	}
+
 	// Watch for changes to ClusterDeprovision
 	err = c.Watch(&source.Kind{Type: &hivev1.ClusterDeprovision{}}, &handler.EnqueueRequestForObject{})
 	if err != nil {
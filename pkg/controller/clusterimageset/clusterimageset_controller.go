@@ -0,0 +1,206 @@
+// Package clusterimageset provides a controller which resolves the metadata of the release image of each
+// ClusterImageSet once, and records it in the status of the ClusterImageSet for use by the clusters installed from it.
+package clusterimageset
+
+import (
+	"context"
+	"os"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+	"github.com/openshift/hive/pkg/controller/images"
+	hivemetrics "github.com/openshift/hive/pkg/controller/metrics"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+	"github.com/openshift/hive/pkg/imageset"
+)
+
+const (
+	controllerName = "clusterImageSet"
+
+	// serviceAccountName is the service account in the hive namespace allowed to update the status of
+	// ClusterImageSets, which runs the jobs resolving their release images.
+	serviceAccountName = "hive-imageset"
+
+	// resolutionRetryInterval is how long to wait before trying again to resolve a release image that could not be
+	// resolved.
+	resolutionRetryInterval = 10 * time.Minute
+)
+
+// Add creates a new ClusterImageSet Controller and adds it to the Manager with default RBAC. The Manager will set fields on the
+// Controller and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	return AddToManager(mgr, NewReconciler(mgr))
+}
+
+// NewReconciler returns a new reconcile.Reconciler
+func NewReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileClusterImageSet{
+		Client: controllerutils.NewClientWithMetricsOrDie(mgr, controllerName),
+		scheme: mgr.GetScheme(),
+		logger: log.WithField("controller", controllerName),
+	}
+}
+
+// AddToManager adds a new Controller to mgr with r as the reconcile.Reconciler
+func AddToManager(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New("clusterimageset-controller", mgr, controller.Options{Reconciler: controllerutils.NewShardedReconciler(r), MaxConcurrentReconciles: controllerutils.GetConcurrentReconciles(controllerName)})
+	if err != nil {
+		return err
+	}
+
+	if err := controllerutils.SetQueueRateLimiter(c, controllerName); err != nil {
+		return err
+	}
+
+	// Watch for changes to ClusterImageSet
+	err = c.Watch(&source.Kind{Type: &hivev1.ClusterImageSet{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	// Watch for jobs created for a ClusterImageSet
+	err = c.Watch(&source.Kind{Type: &batchv1.Job{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &hivev1.ClusterImageSet{},
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var _ reconcile.Reconciler = &ReconcileClusterImageSet{}
+
+// ReconcileClusterImageSet reconciles a ClusterImageSet object
+type ReconcileClusterImageSet struct {
+	client.Client
+	scheme *runtime.Scheme
+	logger log.FieldLogger
+}
+
+// Reconcile resolves the metadata of the release image of a ClusterImageSet with a job, once for each release image
+// of the ClusterImageSet. Release images that cannot be resolved are retried periodically.
+func (r *ReconcileClusterImageSet) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	start := time.Now()
+	isLog := r.logger.WithField("clusterImageSet", request.Name)
+
+	isLog.Info("reconciling cluster image set")
+	defer func() {
+		dur := time.Since(start)
+		hivemetrics.MetricControllerReconcileTime.WithLabelValues(controllerName).Observe(dur.Seconds())
+		isLog.WithField("elapsed", dur).Info("reconcile complete")
+	}()
+
+	imageSet := &hivev1.ClusterImageSet{}
+	err := r.Get(context.TODO(), request.NamespacedName, imageSet)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		isLog.WithError(err).Error("error looking up cluster image set")
+		return reconcile.Result{}, err
+	}
+
+	if imageSet.DeletionTimestamp != nil {
+		return reconcile.Result{}, nil
+	}
+
+	var valid *hivev1.ClusterImageSetCondition
+	if imageSet.Status.ReleaseImage == imageSet.Spec.ReleaseImage {
+		valid = controllerutils.FindClusterImageSetCondition(imageSet.Status.Conditions, hivev1.ClusterImageSetValidCondition)
+	}
+
+	jobKey := types.NamespacedName{Namespace: constants.HiveNamespace, Name: imageset.GetClusterImageSetJobName(imageSet.Name)}
+	jobLog := isLog.WithField("job", jobKey.Name)
+	job := &batchv1.Job{}
+	switch err := r.Get(context.TODO(), jobKey, job); {
+	// The job does not exist. Create it unless the release image has been resolved, or failed to resolve recently.
+	case apierrors.IsNotFound(err):
+		if valid != nil {
+			if valid.Status == corev1.ConditionTrue {
+				isLog.Debug("release image is resolved")
+				return reconcile.Result{}, nil
+			}
+			if wait := time.Until(valid.LastProbeTime.Add(resolutionRetryInterval)); wait > 0 {
+				isLog.WithField("retryAfter", wait).Debug("release image failed to resolve, waiting to retry")
+				return reconcile.Result{RequeueAfter: wait}, nil
+			}
+		}
+		return reconcile.Result{}, r.createJob(imageSet, jobLog)
+
+	case err != nil:
+		jobLog.WithError(err).Error("cannot get job")
+		return reconcile.Result{}, err
+
+	// The job is being deleted. It will be re-created once deleted if needed.
+	case !job.DeletionTimestamp.IsZero():
+		jobLog.Debug("job is being deleted")
+		return reconcile.Result{}, nil
+
+	// The job is finished, or resolves a previous release image. Delete it.
+	case controllerutils.IsFinished(job) || jobReleaseImage(job) != imageSet.Spec.ReleaseImage:
+		jobLog.WithField("successful", controllerutils.IsSuccessful(job)).Info("deleting finished or outdated job")
+		if err := r.Delete(context.TODO(), job, client.PropagationPolicy(metav1.DeletePropagationForeground)); err != nil && !apierrors.IsNotFound(err) {
+			jobLog.WithError(err).Log(controllerutils.LogLevel(err), "cannot delete job")
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, nil
+
+	// The job is in progress.
+	default:
+		jobLog.Debug("job is in progress")
+		return reconcile.Result{}, nil
+	}
+}
+
+func (r *ReconcileClusterImageSet) createJob(imageSet *hivev1.ClusterImageSet, jobLog log.FieldLogger) error {
+	job := imageset.GenerateClusterImageSetJob(
+		imageSet,
+		constants.HiveNamespace,
+		serviceAccountName,
+		os.Getenv(constants.GlobalPullSecret),
+		imageset.AlwaysPullImage(images.GetCLIImage()),
+	)
+	if err := controllerutil.SetControllerReference(imageSet, job, r.scheme); err != nil {
+		jobLog.WithError(err).Error("error setting controller reference on job")
+		return err
+	}
+	jobLog.WithField("releaseImage", imageSet.Spec.ReleaseImage).Info("creating job to resolve release image")
+	if err := r.Create(context.TODO(), job); err != nil {
+		jobLog.WithError(err).Log(controllerutils.LogLevel(err), "error creating job")
+		return err
+	}
+	return nil
+}
+
+// jobReleaseImage returns the release image resolved by the job.
+func jobReleaseImage(job *batchv1.Job) string {
+	for _, container := range job.Spec.Template.Spec.Containers {
+		for _, env := range container.Env {
+			if env.Name == "RELEASE_IMAGE" {
+				return env.Value
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,177 @@
+package clusterimageset
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openshift/hive/pkg/apis"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+	"github.com/openshift/hive/pkg/imageset"
+)
+
+const (
+	testImageSetName = "openshift-v4.5.2"
+	testReleaseImage = "quay.io/openshift-release-dev/ocp-release:4.5.2-x86_64"
+)
+
+func init() {
+	log.SetLevel(log.DebugLevel)
+}
+
+func TestReconcileClusterImageSet(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	tests := []struct {
+		name             string
+		imageSet         *hivev1.ClusterImageSet
+		existingJob      *batchv1.Job
+		globalPullSecret string
+		expectJob        bool
+		expectPullSecret bool
+		expectRequeue    time.Duration
+	}{
+		{
+			name:      "create job for unresolved release image",
+			imageSet:  testClusterImageSet(),
+			expectJob: true,
+		},
+		{
+			name:             "create job with global pull secret",
+			imageSet:         testClusterImageSet(),
+			globalPullSecret: "global-pull-secret",
+			expectJob:        true,
+			expectPullSecret: true,
+		},
+		{
+			name:     "resolved release image",
+			imageSet: testClusterImageSet(withValidCondition(testReleaseImage, corev1.ConditionTrue, time.Now())),
+		},
+		{
+			name:      "create job for changed release image",
+			imageSet:  testClusterImageSet(withValidCondition("quay.io/openshift-release-dev/ocp-release:4.5.1-x86_64", corev1.ConditionTrue, time.Now())),
+			expectJob: true,
+		},
+		{
+			name:          "wait to retry failed release image",
+			imageSet:      testClusterImageSet(withValidCondition(testReleaseImage, corev1.ConditionFalse, time.Now().Add(-4*time.Minute))),
+			expectRequeue: 6 * time.Minute,
+		},
+		{
+			name:      "retry failed release image",
+			imageSet:  testClusterImageSet(withValidCondition(testReleaseImage, corev1.ConditionFalse, time.Now().Add(-11*time.Minute))),
+			expectJob: true,
+		},
+		{
+			name:        "delete finished job",
+			imageSet:    testClusterImageSet(withValidCondition(testReleaseImage, corev1.ConditionTrue, time.Now())),
+			existingJob: testJob(testReleaseImage, true),
+		},
+		{
+			name:        "delete job for previous release image",
+			imageSet:    testClusterImageSet(),
+			existingJob: testJob("quay.io/openshift-release-dev/ocp-release:4.5.1-x86_64", false),
+		},
+		{
+			name:        "keep job in progress",
+			imageSet:    testClusterImageSet(),
+			existingJob: testJob(testReleaseImage, false),
+			expectJob:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.globalPullSecret != "" {
+				os.Setenv(constants.GlobalPullSecret, test.globalPullSecret)
+				defer os.Unsetenv(constants.GlobalPullSecret)
+			}
+			existing := []runtime.Object{test.imageSet}
+			if test.existingJob != nil {
+				existing = append(existing, test.existingJob)
+			}
+			fakeClient := fake.NewFakeClient(existing...)
+			r := &ReconcileClusterImageSet{
+				Client: fakeClient,
+				scheme: scheme.Scheme,
+				logger: log.WithField("controller", "clusterImageSet"),
+			}
+
+			result, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: testImageSetName}})
+			require.NoError(t, err, "unexpected error from reconcile")
+			assert.InDelta(t, test.expectRequeue.Seconds(), result.RequeueAfter.Seconds(), 5, "unexpected requeue")
+
+			job := &batchv1.Job{}
+			err = fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: constants.HiveNamespace, Name: imageset.GetClusterImageSetJobName(testImageSetName)}, job)
+			if !test.expectJob {
+				assert.True(t, apierrors.IsNotFound(err), "expected no job")
+				return
+			}
+			require.NoError(t, err, "expected job")
+			assert.Equal(t, testReleaseImage, jobReleaseImage(job), "unexpected release image of job")
+			assert.Equal(t, serviceAccountName, job.Spec.Template.Spec.ServiceAccountName, "unexpected service account of job")
+			if test.existingJob == nil && assert.Len(t, job.OwnerReferences, 1, "expected owner reference on created job") {
+				assert.Equal(t, testImageSetName, job.OwnerReferences[0].Name, "unexpected owner of job")
+			}
+			hasPullSecret := false
+			for _, volume := range job.Spec.Template.Spec.Volumes {
+				if volume.Secret != nil && volume.Secret.SecretName == test.globalPullSecret {
+					hasPullSecret = true
+				}
+			}
+			assert.Equal(t, test.expectPullSecret, hasPullSecret, "unexpected pull secret volume")
+		})
+	}
+}
+
+func testClusterImageSet(opts ...func(*hivev1.ClusterImageSet)) *hivev1.ClusterImageSet {
+	imageSet := &hivev1.ClusterImageSet{
+		ObjectMeta: metav1.ObjectMeta{Name: testImageSetName},
+		Spec:       hivev1.ClusterImageSetSpec{ReleaseImage: testReleaseImage},
+	}
+	for _, o := range opts {
+		o(imageSet)
+	}
+	return imageSet
+}
+
+func withValidCondition(releaseImage string, status corev1.ConditionStatus, probeTime time.Time) func(*hivev1.ClusterImageSet) {
+	return func(imageSet *hivev1.ClusterImageSet) {
+		imageSet.Status.ReleaseImage = releaseImage
+		imageSet.Status.Conditions = []hivev1.ClusterImageSetCondition{{
+			Type:          hivev1.ClusterImageSetValidCondition,
+			Status:        status,
+			LastProbeTime: metav1.NewTime(probeTime),
+		}}
+	}
+}
+
+func testJob(releaseImage string, finished bool) *batchv1.Job {
+	imageSet := testClusterImageSet()
+	imageSet.Spec.ReleaseImage = releaseImage
+	job := imageset.GenerateClusterImageSetJob(imageSet, constants.HiveNamespace, serviceAccountName, "", imageset.AlwaysPullImage("cli"))
+	if finished {
+		job.Status.Conditions = []batchv1.JobCondition{{
+			Type:   batchv1.JobComplete,
+			Status: corev1.ConditionTrue,
+		}}
+	}
+	return job
+}
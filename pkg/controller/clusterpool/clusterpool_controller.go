@@ -0,0 +1,641 @@
+package clusterpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	apihelpers "github.com/openshift/hive/pkg/apis/helpers"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+	hivemetrics "github.com/openshift/hive/pkg/controller/metrics"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+)
+
+const (
+	controllerName = "clusterPool"
+)
+
+// Add creates a new ClusterPool controller and adds it to the manager with default RBAC.
+func Add(mgr manager.Manager) error {
+	return AddToManager(mgr, NewReconciler(mgr))
+}
+
+// NewReconciler returns a new reconcile.Reconciler
+func NewReconciler(mgr manager.Manager) reconcile.Reconciler {
+	logger := log.WithField("controller", controllerName)
+	return &ReconcileClusterPool{
+		Client:       controllerutils.NewClientWithMetricsOrDie(mgr, controllerName),
+		scheme:       mgr.GetScheme(),
+		logger:       logger,
+		expectations: controllerutils.NewExpectations(logger),
+	}
+}
+
+// AddToManager adds a new Controller to mgr with r as the reconcile.Reconciler
+func AddToManager(mgr manager.Manager, r reconcile.Reconciler) error {
+	cpReconciler, ok := r.(*ReconcileClusterPool)
+	if !ok {
+		return fmt.Errorf("reconciler supplied is not a ReconcileClusterPool")
+	}
+
+	c, err := controller.New("clusterpool-controller", mgr, controller.Options{Reconciler: controllerutils.NewShardedReconciler(r), MaxConcurrentReconciles: controllerutils.GetConcurrentReconciles(controllerName)})
+	if err != nil {
+		log.WithField("controller", controllerName).WithError(err).Error("Error creating new clusterpool controller")
+		return err
+	}
+
+	if err := controllerutils.SetQueueRateLimiter(c, controllerName); err != nil {
+		return err
+	}
+
+	// Watch for changes to ClusterPools
+	if err := c.Watch(&source.Kind{Type: &hivev1.ClusterPool{}}, &handler.EnqueueRequestForObject{}); err != nil {
+		log.WithField("controller", controllerName).WithError(err).Error("Error watching cluster pools")
+		return err
+	}
+
+	// Watch for changes to ClusterDeployments created for a ClusterPool
+	if err := c.Watch(&source.Kind{Type: &hivev1.ClusterDeployment{}}, &clusterDeploymentEventHandler{
+		EnqueueRequestsFromMapFunc: handler.EnqueueRequestsFromMapFunc{
+			ToRequests: handler.ToRequestsFunc(requestsForClusterDeployment),
+		},
+		reconciler: cpReconciler,
+	}); err != nil {
+		log.WithField("controller", controllerName).WithError(err).Error("Error watching cluster deployments")
+		return err
+	}
+
+	// Watch for changes to ClusterClaims on the pools
+	if err := c.Watch(&source.Kind{Type: &hivev1.ClusterClaim{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(requestsForClusterClaim),
+	}); err != nil {
+		log.WithField("controller", controllerName).WithError(err).Error("Error watching cluster claims")
+		return err
+	}
+
+	return nil
+}
+
+func requestsForClusterClaim(o handler.MapObject) []reconcile.Request {
+	claim, ok := o.Object.(*hivev1.ClusterClaim)
+	if !ok {
+		return nil
+	}
+	return []reconcile.Request{{
+		NamespacedName: types.NamespacedName{
+			Namespace: claim.Namespace,
+			Name:      claim.Spec.ClusterPoolName,
+		},
+	}}
+}
+
+func requestsForClusterDeployment(o handler.MapObject) []reconcile.Request {
+	cd, ok := o.Object.(*hivev1.ClusterDeployment)
+	if !ok {
+		return nil
+	}
+	poolKey := clusterPoolKey(cd)
+	if poolKey == nil {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: *poolKey}}
+}
+
+// clusterPoolKey returns the key of the ClusterPool that the ClusterDeployment was created for, or nil if the
+// ClusterDeployment was not created for a ClusterPool.
+func clusterPoolKey(cd *hivev1.ClusterDeployment) *types.NamespacedName {
+	ref := cd.Spec.ClusterPoolRef
+	if ref == nil {
+		return nil
+	}
+	return &types.NamespacedName{Namespace: ref.Namespace, Name: ref.PoolName}
+}
+
+var _ handler.EventHandler = &clusterDeploymentEventHandler{}
+
+type clusterDeploymentEventHandler struct {
+	handler.EnqueueRequestsFromMapFunc
+	reconciler *ReconcileClusterPool
+}
+
+// Create implements handler.EventHandler
+func (h *clusterDeploymentEventHandler) Create(e event.CreateEvent, q workqueue.RateLimitingInterface) {
+	h.reconciler.trackClusterDeploymentAdd(e.Object)
+	h.EnqueueRequestsFromMapFunc.Create(e, q)
+}
+
+// When a ClusterDeployment is created, update the expectations of the ClusterPool that the ClusterDeployment
+// was created for.
+func (r *ReconcileClusterPool) trackClusterDeploymentAdd(obj interface{}) {
+	cd, ok := obj.(*hivev1.ClusterDeployment)
+	if !ok {
+		return
+	}
+	if cd.DeletionTimestamp != nil {
+		// on a restart of the controller, it's possible a new object shows up in a state that
+		// is already pending deletion. Prevent the object from being a creation observation.
+		return
+	}
+	if poolKey := clusterPoolKey(cd); poolKey != nil {
+		r.expectations.CreationObserved(poolKey.String())
+	}
+}
+
+var _ reconcile.Reconciler = &ReconcileClusterPool{}
+
+// ReconcileClusterPool reconciles a ClusterPool object
+type ReconcileClusterPool struct {
+	client.Client
+	scheme *runtime.Scheme
+	logger log.FieldLogger
+
+	// A TTLCache of clusterdeployment creates each clusterpool expects to see
+	expectations controllerutils.ExpectationsInterface
+}
+
+// Reconcile reads the state of the ClusterPool, checks if we currently have enough ClusterDeployments waiting, and
+// attempts to reach the desired state if not.
+func (r *ReconcileClusterPool) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	start := time.Now()
+	logger := r.logger.WithFields(log.Fields{
+		"controller":  controllerName,
+		"clusterPool": request.Name,
+		"namespace":   request.Namespace,
+	})
+
+	logger.Info("reconciling cluster pool")
+	defer func() {
+		dur := time.Since(start)
+		hivemetrics.MetricControllerReconcileTime.WithLabelValues(controllerName).Observe(dur.Seconds())
+		logger.WithField("elapsed", dur).Info("reconcile complete")
+	}()
+
+	// Fetch the ClusterPool instance
+	clp := &hivev1.ClusterPool{}
+	err := r.Get(context.TODO(), request.NamespacedName, clp)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Debug("pool not found")
+			r.expectations.DeleteExpectations(request.NamespacedName.String())
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		logger.WithError(err).Error("error reading cluster pool")
+		return reconcile.Result{}, err
+	}
+
+	if clp.DeletionTimestamp != nil {
+		return reconcile.Result{}, r.reconcileDeletedPool(clp, logger)
+	}
+
+	if !controllerutils.HasFinalizer(clp, hivev1.FinalizerClusterPoolCleanup) {
+		logger.Debug("adding cluster pool finalizer")
+		controllerutils.AddFinalizer(clp, hivev1.FinalizerClusterPoolCleanup)
+		if err := r.Update(context.TODO(), clp); err != nil {
+			logger.WithError(err).Log(controllerutils.LogLevel(err), "error adding finalizer")
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, nil
+	}
+
+	if !r.expectations.SatisfiedExpectations(request.String()) {
+		logger.Debug("waiting for expectations to be satisfied")
+		return reconcile.Result{}, nil
+	}
+
+	cds, err := r.getAllClusterDeploymentsForPool(clp, logger)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	var installing, ready, claimed []*hivev1.ClusterDeployment
+	for _, cd := range cds {
+		switch {
+		case cd.DeletionTimestamp != nil:
+			continue
+		case cd.Spec.ClusterPoolRef.ClaimName != "":
+			// Claimed clusters are no longer part of the pool.
+			claimed = append(claimed, cd)
+		case isFailedInstall(cd):
+			cdLog := logger.WithField("cluster", cd.Name)
+			cdLog.Info("deleting cluster deployment whose install failed")
+			if err := r.Delete(context.TODO(), cd); err != nil {
+				cdLog.WithError(err).Log(controllerutils.LogLevel(err), "error deleting failed cluster deployment")
+				return reconcile.Result{}, err
+			}
+		case cd.Spec.Installed:
+			ready = append(ready, cd)
+		default:
+			installing = append(installing, cd)
+		}
+	}
+
+	if ready, err = r.assignClustersToClaims(clp, claimed, ready, logger); err != nil {
+		return reconcile.Result{}, err
+	}
+
+	numClusters := len(installing) + len(ready)
+	logger.WithFields(log.Fields{
+		"installing": len(installing),
+		"ready":      len(ready),
+		"size":       clp.Spec.Size,
+	}).Debug("found clusters for pool")
+
+	switch drift := int(clp.Spec.Size) - numClusters; {
+	case drift > 0:
+		if err := r.addClusters(clp, drift, logger); err != nil {
+			return reconcile.Result{}, err
+		}
+	case drift < 0:
+		if installing, ready, err = r.deleteExcessClusters(installing, ready, -drift, logger); err != nil {
+			return reconcile.Result{}, err
+		}
+		fallthrough
+	default:
+		// Namespaces are only cleaned up when no clusters were just added, otherwise the namespaces for the new
+		// clusters would look empty.
+		if err := r.cleanupEmptyNamespaces(clp, cds, logger); err != nil {
+			return reconcile.Result{}, err
+		}
+	}
+
+	return reconcile.Result{}, r.updatePoolStatus(clp, len(installing), len(ready), logger)
+}
+
+// assignClustersToClaims assigns ready clusters to the pending claims on the pool, oldest claims first. The ready
+// clusters which remain unclaimed are returned.
+func (r *ReconcileClusterPool) assignClustersToClaims(clp *hivev1.ClusterPool, claimed, ready []*hivev1.ClusterDeployment, logger log.FieldLogger) ([]*hivev1.ClusterDeployment, error) {
+	claimList := &hivev1.ClusterClaimList{}
+	if err := r.List(context.TODO(), claimList, client.InNamespace(clp.Namespace)); err != nil {
+		logger.WithError(err).Error("error listing cluster claims")
+		return nil, err
+	}
+	claimedBy := map[string]*hivev1.ClusterDeployment{}
+	for _, cd := range claimed {
+		claimedBy[cd.Spec.ClusterPoolRef.ClaimName] = cd
+	}
+	var pending []*hivev1.ClusterClaim
+	for i, claim := range claimList.Items {
+		if claim.Spec.ClusterPoolName != clp.Name || claim.Spec.Namespace != "" || claim.DeletionTimestamp != nil {
+			continue
+		}
+		pending = append(pending, &claimList.Items[i])
+	}
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].CreationTimestamp.Before(&pending[j].CreationTimestamp)
+	})
+	// Hand out the clusters which have been ready the longest first.
+	sort.Slice(ready, func(i, j int) bool {
+		return ready[i].CreationTimestamp.Before(&ready[j].CreationTimestamp)
+	})
+
+	for _, claim := range pending {
+		claimLog := logger.WithField("claim", claim.Name)
+		// A previous attempt to assign the claim may have claimed the cluster without updating the claim.
+		cd, ok := claimedBy[claim.Name]
+		if !ok {
+			if len(ready) == 0 {
+				claimLog.Debug("no ready clusters for claim")
+				continue
+			}
+			cd, ready = ready[0], ready[1:]
+			cd.Spec.ClusterPoolRef.ClaimName = claim.Name
+			if err := r.Update(context.TODO(), cd); err != nil {
+				claimLog.WithError(err).Log(controllerutils.LogLevel(err), "could not assign cluster to claim")
+				return nil, err
+			}
+		}
+		claim.Spec.Namespace = cd.Namespace
+		if err := r.Update(context.TODO(), claim); err != nil {
+			claimLog.WithError(err).Log(controllerutils.LogLevel(err), "could not set cluster namespace on claim")
+			return nil, err
+		}
+		claimLog.WithField("cluster", cd.Name).Info("cluster assigned to claim")
+	}
+	return ready, nil
+}
+
+// isFailedInstall returns true if a pool cluster has failed to install. Pool clusters are only attempted once,
+// failures result in the cluster being thrown away and replaced.
+func isFailedInstall(cd *hivev1.ClusterDeployment) bool {
+	if cd.Spec.Installed {
+		return false
+	}
+	cond := controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.ProvisionFailedCondition)
+	return cond != nil && cond.Status == corev1.ConditionTrue
+}
+
+func (r *ReconcileClusterPool) updatePoolStatus(clp *hivev1.ClusterPool, installing, ready int, logger log.FieldLogger) error {
+	status := hivev1.ClusterPoolStatus{
+		Size:       int32(installing + ready),
+		Ready:      int32(ready),
+		Installing: int32(installing),
+	}
+	if clp.Status == status {
+		return nil
+	}
+	clp.Status = status
+	if err := r.Status().Update(context.TODO(), clp); err != nil {
+		logger.WithError(err).Log(controllerutils.LogLevel(err), "could not update cluster pool status")
+		return err
+	}
+	return nil
+}
+
+func (r *ReconcileClusterPool) addClusters(clp *hivev1.ClusterPool, count int, logger log.FieldLogger) error {
+	logger.WithField("count", count).Info("adding new clusters")
+
+	poolKey := types.NamespacedName{Namespace: clp.Namespace, Name: clp.Name}.String()
+	r.expectations.ExpectCreations(poolKey, count)
+	for i := 0; i < count; i++ {
+		if err := r.createCluster(clp, logger); err != nil {
+			// Lower the expectations for the creations that will not be observed.
+			r.expectations.LowerExpectations(poolKey, count-i, 0)
+			return err
+		}
+	}
+	return nil
+}
+
+// createCluster creates a namespace for a new cluster along with the secrets and ClusterDeployment needed to
+// install it. The namespace is deleted again if the cluster cannot be created in it.
+func (r *ReconcileClusterPool) createCluster(clp *hivev1.ClusterPool, logger log.FieldLogger) (returnErr error) {
+	name := apihelpers.GetResourceName(clp.Name, utilrand.String(5))
+	cdLog := logger.WithField("cluster", name)
+
+	credsSecretName, err := credentialsSecretName(&clp.Spec.Platform)
+	if err != nil {
+		cdLog.WithError(err).Error("cannot create cluster")
+		return err
+	}
+
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				constants.ClusterPoolNameLabel:      clp.Name,
+				constants.ClusterPoolNamespaceLabel: clp.Namespace,
+			},
+		},
+	}
+	if err := r.Create(context.TODO(), ns); err != nil {
+		cdLog.WithError(err).Log(controllerutils.LogLevel(err), "error creating namespace")
+		return err
+	}
+	defer func() {
+		if returnErr == nil {
+			return
+		}
+		if err := r.Delete(context.TODO(), ns); err != nil && !apierrors.IsNotFound(err) {
+			cdLog.WithError(err).Log(controllerutils.LogLevel(err), "error deleting namespace of cluster that could not be created")
+			return
+		}
+		cdLog.Info("deleted namespace of cluster that could not be created")
+	}()
+
+	var credsSecret *corev1.Secret
+	if credsSecretName != "" {
+		if credsSecret, err = r.copySecret(clp.Namespace, credsSecretName, name, cdLog); err != nil {
+			return err
+		}
+	}
+	if clp.Spec.PullSecretRef != nil {
+		if _, err := r.copySecret(clp.Namespace, clp.Spec.PullSecretRef.Name, name, cdLog); err != nil {
+			return err
+		}
+	}
+
+	installConfig, err := generateInstallConfig(clp, name, credsSecret)
+	if err != nil {
+		cdLog.WithError(err).Error("could not generate install config")
+		return err
+	}
+	installConfigSecret, err := generateInstallConfigSecret(apihelpers.GetResourceName(name, "install-config"), name, installConfig)
+	if err != nil {
+		cdLog.WithError(err).Error("could not generate install config secret")
+		return err
+	}
+	if err := r.Create(context.TODO(), installConfigSecret); err != nil {
+		cdLog.WithError(err).Log(controllerutils.LogLevel(err), "error creating install config secret")
+		return err
+	}
+
+	cd := &hivev1.ClusterDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: name,
+			Labels: map[string]string{
+				constants.ClusterPoolNameLabel: clp.Name,
+			},
+			Annotations: map[string]string{
+				constants.TryInstallOnceAnnotation: "true",
+			},
+		},
+		Spec: hivev1.ClusterDeploymentSpec{
+			ClusterName:   name,
+			BaseDomain:    clp.Spec.BaseDomain,
+			Platform:      *clp.Spec.Platform.DeepCopy(),
+			PullSecretRef: clp.Spec.PullSecretRef,
+			Provisioning: &hivev1.Provisioning{
+				InstallConfigSecretRef: corev1.LocalObjectReference{Name: installConfigSecret.Name},
+				ImageSetRef:            &hivev1.ClusterImageSetReference{Name: clp.Spec.ImageSetRef.Name},
+			},
+			ClusterPoolRef: &hivev1.ClusterPoolReference{
+				Namespace: clp.Namespace,
+				PoolName:  clp.Name,
+			},
+		},
+	}
+	if clusterType, ok := clp.Labels[hivev1.HiveClusterTypeLabel]; ok {
+		cd.Labels[hivev1.HiveClusterTypeLabel] = clusterType
+	}
+	if err := r.Create(context.TODO(), cd); err != nil {
+		cdLog.WithError(err).Log(controllerutils.LogLevel(err), "error creating cluster deployment")
+		return err
+	}
+	cdLog.Info("created new cluster deployment for pool")
+	return nil
+}
+
+// copySecret copies a secret from the namespace of the pool into the namespace of a pool cluster.
+func (r *ReconcileClusterPool) copySecret(fromNamespace, name, toNamespace string, logger log.FieldLogger) (*corev1.Secret, error) {
+	secretLog := logger.WithField("secret", name)
+	src := &corev1.Secret{}
+	if err := r.Get(context.TODO(), types.NamespacedName{Namespace: fromNamespace, Name: name}, src); err != nil {
+		secretLog.WithError(err).Error("could not get secret to copy for pool cluster")
+		return nil, err
+	}
+	dest := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: toNamespace,
+		},
+		Type: src.Type,
+		Data: src.Data,
+	}
+	if err := r.Create(context.TODO(), dest); err != nil {
+		secretLog.WithError(err).Log(controllerutils.LogLevel(err), "could not create secret for pool cluster")
+		return nil, err
+	}
+	return dest, nil
+}
+
+// deleteExcessClusters deletes clusters from the pool when there are more than desired. Clusters that are still
+// installing are removed first, newest first, as they have had the least amount of time invested in them. The
+// installing and ready clusters that remain are returned.
+func (r *ReconcileClusterPool) deleteExcessClusters(installing, ready []*hivev1.ClusterDeployment, count int, logger log.FieldLogger) ([]*hivev1.ClusterDeployment, []*hivev1.ClusterDeployment, error) {
+	logger.WithField("count", count).Info("deleting excess clusters")
+
+	byNewest := func(cds []*hivev1.ClusterDeployment) {
+		sort.Slice(cds, func(i, j int) bool {
+			return cds[j].CreationTimestamp.Before(&cds[i].CreationTimestamp)
+		})
+	}
+	byNewest(installing)
+	byNewest(ready)
+
+	for ; count > 0; count-- {
+		var cd *hivev1.ClusterDeployment
+		if len(installing) > 0 {
+			cd, installing = installing[0], installing[1:]
+		} else {
+			cd, ready = ready[0], ready[1:]
+		}
+		cdLog := logger.WithField("cluster", cd.Name)
+		if err := r.Delete(context.TODO(), cd); err != nil {
+			cdLog.WithError(err).Log(controllerutils.LogLevel(err), "error deleting excess cluster deployment")
+			return nil, nil, err
+		}
+		cdLog.Info("deleted excess cluster deployment")
+	}
+	return installing, ready, nil
+}
+
+// cleanupEmptyNamespaces deletes the namespaces created for the pool that no longer contain a ClusterDeployment.
+func (r *ReconcileClusterPool) cleanupEmptyNamespaces(clp *hivev1.ClusterPool, cds []*hivev1.ClusterDeployment, logger log.FieldLogger) error {
+	namespaces := &corev1.NamespaceList{}
+	if err := r.List(
+		context.TODO(),
+		namespaces,
+		client.MatchingLabels(map[string]string{
+			constants.ClusterPoolNameLabel:      clp.Name,
+			constants.ClusterPoolNamespaceLabel: clp.Namespace,
+		}),
+	); err != nil {
+		logger.WithError(err).Error("error listing namespaces for pool")
+		return err
+	}
+	inUse := sets.NewString()
+	for _, cd := range cds {
+		inUse.Insert(cd.Namespace)
+	}
+	for i, ns := range namespaces.Items {
+		if ns.DeletionTimestamp != nil || inUse.Has(ns.Name) {
+			continue
+		}
+		nsLog := logger.WithField("namespace", ns.Name)
+		if err := r.Delete(context.TODO(), &namespaces.Items[i]); err != nil {
+			nsLog.WithError(err).Log(controllerutils.LogLevel(err), "error deleting empty namespace for pool")
+			return err
+		}
+		nsLog.Info("deleted empty namespace for pool")
+	}
+	return nil
+}
+
+// reconcileDeletedPool deletes all of the unclaimed clusters created for the pool and removes the finalizer once
+// they, and their namespaces, are gone. Claimed clusters are left for their claims.
+func (r *ReconcileClusterPool) reconcileDeletedPool(clp *hivev1.ClusterPool, logger log.FieldLogger) error {
+	if !controllerutils.HasFinalizer(clp, hivev1.FinalizerClusterPoolCleanup) {
+		return nil
+	}
+	cds, err := r.getAllClusterDeploymentsForPool(clp, logger)
+	if err != nil {
+		return err
+	}
+	var claimed []*hivev1.ClusterDeployment
+	remaining := 0
+	for _, cd := range cds {
+		if cd.Spec.ClusterPoolRef.ClaimName != "" {
+			claimed = append(claimed, cd)
+			continue
+		}
+		remaining++
+		if cd.DeletionTimestamp != nil {
+			continue
+		}
+		cdLog := logger.WithField("cluster", cd.Name)
+		if err := r.Delete(context.TODO(), cd); err != nil {
+			cdLog.WithError(err).Log(controllerutils.LogLevel(err), "error deleting cluster deployment for deleted pool")
+			return err
+		}
+		cdLog.Info("deleted cluster deployment for deleted pool")
+	}
+	if remaining > 0 {
+		logger.WithField("remaining", remaining).Debug("waiting for pool clusters to be removed")
+		return nil
+	}
+	if err := r.cleanupEmptyNamespaces(clp, claimed, logger); err != nil {
+		return err
+	}
+	controllerutils.DeleteFinalizer(clp, hivev1.FinalizerClusterPoolCleanup)
+	if err := r.Update(context.TODO(), clp); err != nil {
+		logger.WithError(err).Log(controllerutils.LogLevel(err), "error removing finalizer")
+		return err
+	}
+	return nil
+}
+
+func (r *ReconcileClusterPool) getAllClusterDeploymentsForPool(clp *hivev1.ClusterPool, logger log.FieldLogger) ([]*hivev1.ClusterDeployment, error) {
+	cdList := &hivev1.ClusterDeploymentList{}
+	if err := r.List(
+		context.TODO(),
+		cdList,
+		client.MatchingLabels(map[string]string{constants.ClusterPoolNameLabel: clp.Name}),
+	); err != nil {
+		logger.WithError(err).Error("error listing cluster deployments")
+		return nil, err
+	}
+	var cds []*hivev1.ClusterDeployment
+	for i, cd := range cdList.Items {
+		if ref := cd.Spec.ClusterPoolRef; ref != nil && ref.Namespace == clp.Namespace && ref.PoolName == clp.Name {
+			cds = append(cds, &cdList.Items[i])
+		}
+	}
+	return cds, nil
+}
+
+// credentialsSecretName returns the name of the cloud credentials secret for the platform, or an error if cluster
+// pools do not support the platform.
+func credentialsSecretName(platform *hivev1.Platform) (string, error) {
+	switch {
+	case platform.AWS != nil:
+		return platform.AWS.CredentialsSecretRef.Name, nil
+	case platform.Azure != nil:
+		return platform.Azure.CredentialsSecretRef.Name, nil
+	case platform.GCP != nil:
+		return platform.GCP.CredentialsSecretRef.Name, nil
+	}
+	return "", errors.New("unsupported platform for cluster pools")
+}
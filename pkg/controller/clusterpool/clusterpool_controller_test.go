@@ -0,0 +1,469 @@
+package clusterpool
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openshift/hive/pkg/apis"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	hivev1aws "github.com/openshift/hive/pkg/apis/hive/v1/aws"
+	hivev1openstack "github.com/openshift/hive/pkg/apis/hive/v1/openstack"
+	"github.com/openshift/hive/pkg/constants"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+)
+
+const (
+	testNamespace       = "test-namespace"
+	testPoolName        = "test-pool"
+	credsSecretName     = "aws-creds"
+	pullSecretName      = "pull-secret"
+	imageSetName        = "test-image-set"
+	testBaseDomain      = "example.com"
+	testRegion          = "us-east-1"
+	testExistingCDLabel = "existing"
+)
+
+func TestReconcileClusterPool(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	tests := []struct {
+		name                   string
+		existing               []runtime.Object
+		noPool                 bool
+		deletePool             bool
+		expectedTotalClusters  int
+		expectedObservedSize   int32
+		expectedObservedReady  int32
+		expectedFinalizer      bool
+		expectedDeletedCluster string
+		expectedNamespaces     int
+		expectedAssignedClaims map[string]string
+	}{
+		{
+			name:               "initialize finalizer",
+			existing:           []runtime.Object{testPool(withoutFinalizer())},
+			expectedFinalizer:  true,
+			expectedNamespaces: 0,
+		},
+		{
+			name:                  "create all clusters",
+			existing:              []runtime.Object{testPool(withSize(3))},
+			expectedTotalClusters: 3,
+			expectedFinalizer:     true,
+			expectedNamespaces:    3,
+		},
+		{
+			name: "scale up",
+			existing: []runtime.Object{
+				testPool(withSize(3)),
+				testClusterDeployment("c1", installed()),
+			},
+			expectedTotalClusters: 3,
+			expectedObservedSize:  1,
+			expectedObservedReady: 1,
+			expectedFinalizer:     true,
+			expectedNamespaces:    2,
+		},
+		{
+			name: "scale down deletes installing clusters first",
+			existing: []runtime.Object{
+				testPool(withSize(1)),
+				testClusterDeployment("c1", installed()),
+				testClusterDeployment("c2"),
+			},
+			expectedTotalClusters:  1,
+			expectedObservedSize:   1,
+			expectedObservedReady:  1,
+			expectedFinalizer:      true,
+			expectedDeletedCluster: "c2",
+		},
+		{
+			name: "scale down deletes newest ready cluster",
+			existing: []runtime.Object{
+				testPool(withSize(1)),
+				testClusterDeployment("c1", installed(), createdAt(time.Now().Add(-2*time.Hour))),
+				testClusterDeployment("c2", installed(), createdAt(time.Now().Add(-1*time.Hour))),
+			},
+			expectedTotalClusters:  1,
+			expectedObservedSize:   1,
+			expectedObservedReady:  1,
+			expectedFinalizer:      true,
+			expectedDeletedCluster: "c2",
+		},
+		{
+			name: "replace failed install",
+			existing: []runtime.Object{
+				testPool(withSize(2)),
+				testClusterDeployment("c1", installed()),
+				testClusterDeployment("c2", provisionFailed()),
+			},
+			expectedTotalClusters:  2,
+			expectedObservedSize:   1,
+			expectedObservedReady:  1,
+			expectedFinalizer:      true,
+			expectedDeletedCluster: "c2",
+			expectedNamespaces:     1,
+		},
+		{
+			name: "ignore clusters from other pools",
+			existing: []runtime.Object{
+				testPool(withSize(1)),
+				testClusterDeployment("c1", installed(), inPool("other-namespace", testPoolName)),
+			},
+			expectedTotalClusters: 2,
+			expectedFinalizer:     true,
+			expectedNamespaces:    1,
+		},
+		{
+			name: "deleted pool deletes clusters",
+			existing: []runtime.Object{
+				testPool(withSize(1)),
+				testClusterDeployment("c1", installed()),
+			},
+			deletePool:             true,
+			expectedTotalClusters:  0,
+			expectedObservedSize:   0,
+			expectedFinalizer:      true,
+			expectedDeletedCluster: "c1",
+		},
+		{
+			name:              "deleted pool without clusters removes finalizer",
+			existing:          []runtime.Object{testPool(withSize(1))},
+			deletePool:        true,
+			expectedFinalizer: false,
+		},
+		{
+			name: "assign ready cluster to claim",
+			existing: []runtime.Object{
+				testPool(withSize(1)),
+				testClusterDeployment("c1", installed()),
+				testClaim("claim1", time.Now().Add(-1*time.Hour)),
+				testClaim("claim2", time.Now()),
+			},
+			expectedTotalClusters:  2,
+			expectedFinalizer:      true,
+			expectedNamespaces:     1,
+			expectedAssignedClaims: map[string]string{"claim1": "c1", "claim2": ""},
+		},
+		{
+			name: "claimed clusters are not part of pool",
+			existing: []runtime.Object{
+				testPool(withSize(1)),
+				testClusterDeployment("c1", installed(), claimedBy("claim1")),
+			},
+			expectedTotalClusters: 2,
+			expectedFinalizer:     true,
+			expectedNamespaces:    1,
+		},
+		{
+			name: "finish assigning claimed cluster",
+			existing: []runtime.Object{
+				testPool(withSize(1)),
+				testClusterDeployment("c1", installed(), claimedBy("claim1")),
+				testClusterDeployment("c2", installed()),
+				testClaim("claim1", time.Now()),
+			},
+			expectedTotalClusters:  2,
+			expectedObservedSize:   1,
+			expectedObservedReady:  1,
+			expectedFinalizer:      true,
+			expectedAssignedClaims: map[string]string{"claim1": "c1"},
+		},
+		{
+			name: "deleted pool does not delete claimed clusters",
+			existing: []runtime.Object{
+				testPool(withSize(1)),
+				testClusterDeployment("c1", installed(), claimedBy("claim1")),
+			},
+			deletePool:            true,
+			expectedTotalClusters: 1,
+			expectedFinalizer:     false,
+		},
+		{
+			name:   "pool not found",
+			noPool: true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			existing := append(test.existing,
+				testSecret(credsSecretName),
+				testSecret(pullSecretName),
+			)
+			if test.deletePool {
+				for _, obj := range existing {
+					if pool, ok := obj.(*hivev1.ClusterPool); ok {
+						now := metav1.Now()
+						pool.DeletionTimestamp = &now
+					}
+				}
+			}
+			fakeClient := fake.NewFakeClient(existing...)
+			logger := log.WithField("controller", "clusterpool")
+			rcp := &ReconcileClusterPool{
+				Client:       fakeClient,
+				scheme:       scheme.Scheme,
+				logger:       logger,
+				expectations: controllerutils.NewExpectations(logger),
+			}
+
+			poolKey := types.NamespacedName{Namespace: testNamespace, Name: testPoolName}
+			_, err := rcp.Reconcile(reconcile.Request{NamespacedName: poolKey})
+			require.NoError(t, err, "unexpected error from Reconcile")
+
+			if test.noPool {
+				return
+			}
+
+			pool := &hivev1.ClusterPool{}
+			err = fakeClient.Get(context.Background(), poolKey, pool)
+			require.NoError(t, err, "unexpected error getting pool")
+			assert.Equal(t, test.expectedFinalizer, controllerutils.HasFinalizer(pool, hivev1.FinalizerClusterPoolCleanup),
+				"unexpected finalizer on pool")
+			assert.Equal(t, test.expectedObservedSize, pool.Status.Size, "unexpected observed size")
+			assert.Equal(t, test.expectedObservedReady, pool.Status.Ready, "unexpected observed ready count")
+
+			cds := &hivev1.ClusterDeploymentList{}
+			err = fakeClient.List(context.Background(), cds)
+			require.NoError(t, err, "unexpected error listing cluster deployments")
+			assert.Len(t, cds.Items, test.expectedTotalClusters, "unexpected number of cluster deployments")
+			for _, cd := range cds.Items {
+				assert.NotEqual(t, test.expectedDeletedCluster, cd.Name, "expected cluster deployment to be deleted")
+				if cd.Labels[testExistingCDLabel] == "true" {
+					continue
+				}
+				assert.Equal(t, testPoolName, cd.Labels[constants.ClusterPoolNameLabel], "unexpected pool label")
+				assert.Equal(t, "true", cd.Annotations[constants.TryInstallOnceAnnotation], "expected try install once annotation")
+				if assert.NotNil(t, cd.Spec.ClusterPoolRef, "expected cluster pool reference") {
+					assert.Equal(t, testNamespace, cd.Spec.ClusterPoolRef.Namespace, "unexpected pool namespace")
+					assert.Equal(t, testPoolName, cd.Spec.ClusterPoolRef.PoolName, "unexpected pool name")
+				}
+				assert.Equal(t, cd.Name, cd.Namespace, "expected cluster deployment to be in namespace of the same name")
+				assert.Equal(t, testBaseDomain, cd.Spec.BaseDomain, "unexpected base domain")
+				if assert.NotNil(t, cd.Spec.Provisioning, "expected provisioning") {
+					assert.Equal(t, imageSetName, cd.Spec.Provisioning.ImageSetRef.Name, "unexpected image set")
+					icSecret := &corev1.Secret{}
+					err := fakeClient.Get(context.Background(),
+						types.NamespacedName{Namespace: cd.Namespace, Name: cd.Spec.Provisioning.InstallConfigSecretRef.Name}, icSecret)
+					if assert.NoError(t, err, "expected install config secret") {
+						assert.Contains(t, string(icSecret.Data[installConfigSecretKey]), testRegion, "expected region in install config")
+					}
+				}
+				for _, secretName := range []string{credsSecretName, pullSecretName} {
+					secret := &corev1.Secret{}
+					err := fakeClient.Get(context.Background(), types.NamespacedName{Namespace: cd.Namespace, Name: secretName}, secret)
+					assert.NoError(t, err, "expected secret %s to be copied to cluster namespace", secretName)
+				}
+			}
+
+			for claimName, cdName := range test.expectedAssignedClaims {
+				claim := &hivev1.ClusterClaim{}
+				err := fakeClient.Get(context.Background(), types.NamespacedName{Namespace: testNamespace, Name: claimName}, claim)
+				require.NoError(t, err, "unexpected error getting claim")
+				assert.Equal(t, cdName, claim.Spec.Namespace, "unexpected cluster assigned to claim %s", claimName)
+				if cdName == "" {
+					continue
+				}
+				cd := &hivev1.ClusterDeployment{}
+				err = fakeClient.Get(context.Background(), types.NamespacedName{Namespace: cdName, Name: cdName}, cd)
+				require.NoError(t, err, "unexpected error getting claimed cluster deployment")
+				assert.Equal(t, claimName, cd.Spec.ClusterPoolRef.ClaimName, "unexpected claim on cluster deployment")
+			}
+
+			namespaces := &corev1.NamespaceList{}
+			err = fakeClient.List(context.Background(), namespaces, client.MatchingLabels(map[string]string{
+				constants.ClusterPoolNameLabel: testPoolName,
+			}))
+			require.NoError(t, err, "unexpected error listing namespaces")
+			assert.Len(t, namespaces.Items, test.expectedNamespaces, "unexpected number of pool namespaces")
+		})
+	}
+}
+
+func TestCreateClusterDeletesNamespaceOnError(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+	// The pull secret is missing, so the cluster cannot be created after its namespace is.
+	fakeClient := fake.NewFakeClient(testPool(), testSecret(credsSecretName))
+	logger := log.WithField("controller", "clusterpool")
+	rcp := &ReconcileClusterPool{
+		Client:       fakeClient,
+		scheme:       scheme.Scheme,
+		logger:       logger,
+		expectations: controllerutils.NewExpectations(logger),
+	}
+
+	err := rcp.createCluster(testPool(), logger)
+	assert.Error(t, err, "expected error creating cluster without pull secret")
+
+	namespaces := &corev1.NamespaceList{}
+	err = fakeClient.List(context.Background(), namespaces, client.MatchingLabels(map[string]string{
+		constants.ClusterPoolNameLabel: testPoolName,
+	}))
+	require.NoError(t, err, "unexpected error listing namespaces")
+	assert.Empty(t, namespaces.Items, "expected namespace of cluster that could not be created to be deleted")
+}
+
+func TestCreateClusterUnsupportedPlatform(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+	pool := testPool()
+	pool.Spec.Platform = hivev1.Platform{
+		OpenStack: &hivev1openstack.Platform{
+			CredentialsSecretRef: corev1.LocalObjectReference{Name: credsSecretName},
+		},
+	}
+	fakeClient := fake.NewFakeClient(pool, testSecret(credsSecretName), testSecret(pullSecretName))
+	logger := log.WithField("controller", "clusterpool")
+	rcp := &ReconcileClusterPool{
+		Client:       fakeClient,
+		scheme:       scheme.Scheme,
+		logger:       logger,
+		expectations: controllerutils.NewExpectations(logger),
+	}
+
+	err := rcp.createCluster(pool, logger)
+	assert.Error(t, err, "expected error creating cluster on unsupported platform")
+
+	namespaces := &corev1.NamespaceList{}
+	err = fakeClient.List(context.Background(), namespaces, client.MatchingLabels(map[string]string{
+		constants.ClusterPoolNameLabel: testPoolName,
+	}))
+	require.NoError(t, err, "unexpected error listing namespaces")
+	assert.Empty(t, namespaces.Items, "expected no namespace for cluster on unsupported platform")
+}
+
+type poolOption func(*hivev1.ClusterPool)
+
+func withSize(size int32) poolOption {
+	return func(p *hivev1.ClusterPool) {
+		p.Spec.Size = size
+	}
+}
+
+func withoutFinalizer() poolOption {
+	return func(p *hivev1.ClusterPool) {
+		p.Finalizers = nil
+	}
+}
+
+func testPool(opts ...poolOption) *hivev1.ClusterPool {
+	p := &hivev1.ClusterPool{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  testNamespace,
+			Name:       testPoolName,
+			Finalizers: []string{hivev1.FinalizerClusterPoolCleanup},
+		},
+		Spec: hivev1.ClusterPoolSpec{
+			Platform: hivev1.Platform{
+				AWS: &hivev1aws.Platform{
+					CredentialsSecretRef: corev1.LocalObjectReference{Name: credsSecretName},
+					Region:               testRegion,
+				},
+			},
+			PullSecretRef: &corev1.LocalObjectReference{Name: pullSecretName},
+			BaseDomain:    testBaseDomain,
+			ImageSetRef:   hivev1.ClusterImageSetReference{Name: imageSetName},
+		},
+	}
+	for _, o := range opts {
+		o(p)
+	}
+	return p
+}
+
+type cdOption func(*hivev1.ClusterDeployment)
+
+func installed() cdOption {
+	return func(cd *hivev1.ClusterDeployment) {
+		cd.Spec.Installed = true
+	}
+}
+
+func provisionFailed() cdOption {
+	return func(cd *hivev1.ClusterDeployment) {
+		cd.Status.Conditions = append(cd.Status.Conditions, hivev1.ClusterDeploymentCondition{
+			Type:   hivev1.ProvisionFailedCondition,
+			Status: corev1.ConditionTrue,
+		})
+	}
+}
+
+func createdAt(t time.Time) cdOption {
+	return func(cd *hivev1.ClusterDeployment) {
+		cd.CreationTimestamp = metav1.NewTime(t)
+	}
+}
+
+func inPool(namespace, name string) cdOption {
+	return func(cd *hivev1.ClusterDeployment) {
+		cd.Spec.ClusterPoolRef = &hivev1.ClusterPoolReference{Namespace: namespace, PoolName: name}
+	}
+}
+
+func claimedBy(claimName string) cdOption {
+	return func(cd *hivev1.ClusterDeployment) {
+		cd.Spec.ClusterPoolRef.ClaimName = claimName
+	}
+}
+
+func testClusterDeployment(name string, opts ...cdOption) *hivev1.ClusterDeployment {
+	cd := &hivev1.ClusterDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: name,
+			Labels: map[string]string{
+				constants.ClusterPoolNameLabel: testPoolName,
+				testExistingCDLabel:            "true",
+			},
+		},
+		Spec: hivev1.ClusterDeploymentSpec{
+			ClusterPoolRef: &hivev1.ClusterPoolReference{
+				Namespace: testNamespace,
+				PoolName:  testPoolName,
+			},
+		},
+	}
+	for _, o := range opts {
+		o(cd)
+	}
+	return cd
+}
+
+func testClaim(name string, created time.Time) *hivev1.ClusterClaim {
+	return &hivev1.ClusterClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:         testNamespace,
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Spec: hivev1.ClusterClaimSpec{
+			ClusterPoolName: testPoolName,
+		},
+	}
+}
+
+func testSecret(name string) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNamespace,
+			Name:      name,
+		},
+		Data: map[string][]byte{
+			"key": []byte("value"),
+		},
+	}
+}
@@ -0,0 +1,95 @@
+package clusterpool
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ghodss/yaml"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/pointer"
+
+	installertypes "github.com/openshift/installer/pkg/types"
+	installeraws "github.com/openshift/installer/pkg/types/aws"
+	installerazure "github.com/openshift/installer/pkg/types/azure"
+	installergcp "github.com/openshift/installer/pkg/types/gcp"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/gcpclient"
+)
+
+const (
+	installConfigSecretKey = "install-config.yaml"
+)
+
+// generateInstallConfig builds a minimal InstallConfig for a cluster created for the given pool. Anything not
+// specified here is defaulted by the installer. The credentials secret is only consulted for platforms which
+// need information from it, such as the GCP project ID.
+func generateInstallConfig(pool *hivev1.ClusterPool, clusterName string, credsSecret *corev1.Secret) (*installertypes.InstallConfig, error) {
+	ic := &installertypes.InstallConfig{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: installertypes.InstallConfigVersion,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name: clusterName,
+		},
+		BaseDomain: pool.Spec.BaseDomain,
+		ControlPlane: &installertypes.MachinePool{
+			Name:     "master",
+			Replicas: pointer.Int64Ptr(3),
+		},
+		Compute: []installertypes.MachinePool{
+			{
+				Name:     "worker",
+				Replicas: pointer.Int64Ptr(3),
+			},
+		},
+	}
+
+	platform := pool.Spec.Platform
+	switch {
+	case platform.AWS != nil:
+		ic.Platform.AWS = &installeraws.Platform{
+			Region:   platform.AWS.Region,
+			UserTags: platform.AWS.UserTags,
+		}
+	case platform.Azure != nil:
+		ic.Platform.Azure = &installerazure.Platform{
+			Region:                      platform.Azure.Region,
+			BaseDomainResourceGroupName: platform.Azure.BaseDomainResourceGroupName,
+		}
+	case platform.GCP != nil:
+		if credsSecret == nil {
+			return nil, errors.New("GCP credentials secret is required to determine the project ID")
+		}
+		projectID, err := gcpclient.ProjectIDFromSecret(credsSecret)
+		if err != nil {
+			return nil, fmt.Errorf("could not determine GCP project ID: %v", err)
+		}
+		ic.Platform.GCP = &installergcp.Platform{
+			ProjectID: projectID,
+			Region:    platform.GCP.Region,
+		}
+	default:
+		return nil, errors.New("unsupported platform for cluster pools")
+	}
+	return ic, nil
+}
+
+func generateInstallConfigSecret(name, namespace string, ic *installertypes.InstallConfig) (*corev1.Secret, error) {
+	d, err := yaml.Marshal(ic)
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			installConfigSecretKey: d,
+		},
+	}, nil
+}
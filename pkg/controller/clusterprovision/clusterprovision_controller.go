@@ -81,11 +81,15 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	}
 
 	// Create a new controller
-	c, err := controller.New("clusterprovision-controller", mgr, controller.Options{Reconciler: r})
+	c, err := controller.New("clusterprovision-controller", mgr, controller.Options{Reconciler: controllerutils.NewShardedReconciler(r), MaxConcurrentReconciles: controllerutils.GetConcurrentReconcilesOrDefault(controllerName, 1)})
 	if err != nil {
 		return errors.Wrap(err, "could not create controller")
 	}
 
+	if err := controllerutils.SetQueueRateLimiter(c, controllerName); err != nil {
+		return err
+	}
+
 	// Watch for changes to ClusterProvision
 	if err := c.Watch(&source.Kind{Type: &hivev1.ClusterProvision{}}, &handler.EnqueueRequestForObject{}); err != nil {
 		return errors.Wrap(err, "could not watch clusterprovisions")
@@ -115,6 +119,8 @@ type ReconcileClusterProvision struct {
 	logger log.FieldLogger
 	// A TTLCache of job creates each clusterprovision expects to see
 	expectations controllerutils.ExpectationsInterface
+	// failureRules caches the compiled InstallFailureRules used to classify install failures
+	failureRules installFailureRuleCache
 }
 
 // Reconcile reads that state of the cluster for a ClusterProvision object and makes changes based on the state read
@@ -288,10 +294,12 @@ func (r *ReconcileClusterProvision) reconcileSuccessfulJob(instance *hivev1.Clus
 
 func (r *ReconcileClusterProvision) reconcileFailedJob(instance *hivev1.ClusterProvision, job *batchv1.Job, pLog log.FieldLogger) (reconcile.Result, error) {
 	pLog.Info("install job failed")
-	reason, message := r.parseInstallLog(instance.Spec.InstallLog, pLog)
+	failure := r.parseInstallLog(instance.Spec.InstallLog, instance.Labels[hivev1.HiveClusterPlatformLabel], pLog)
 	// Increment a counter metric for this cluster type and error reason:
-	metricInstallErrors.WithLabelValues(hivemetrics.GetClusterDeploymentType(instance), reason).Inc()
-	return r.transitionStage(instance, hivev1.ClusterProvisionStageFailed, reason, message, pLog)
+	metricInstallErrors.WithLabelValues(hivemetrics.GetClusterDeploymentType(instance), failure.Reason).Inc()
+	// The install failure is saved along with the failed condition when transitioning to the failed stage.
+	instance.Status.InstallFailure = failure
+	return r.transitionStage(instance, hivev1.ClusterProvisionStageFailed, failure.Reason, failure.Message, pLog)
 }
 
 func (r *ReconcileClusterProvision) startProvisioning(instance *hivev1.ClusterProvision, pLog log.FieldLogger) (reconcile.Result, error) {
@@ -126,6 +126,13 @@ func TestClusterProvisionReconcile(t *testing.T) {
 			},
 			expectedStage:      hivev1.ClusterProvisionStageFailed,
 			expectedFailReason: unknownReason,
+			validate: func(c client.Client, t *testing.T) {
+				provision := getProvision(c)
+				if assert.NotNil(t, provision.Status.InstallFailure, "expected install failure") {
+					assert.Equal(t, unknownReason, provision.Status.InstallFailure.Reason, "unexpected install failure reason")
+					assert.True(t, provision.Status.InstallFailure.Retryable, "expected install failure to be retryable")
+				}
+			},
 		},
 		{
 			name: "keep job after success",
@@ -0,0 +1,125 @@
+package clusterprovision
+
+import (
+	"context"
+	"regexp"
+	"sort"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+)
+
+// installFailureRule is an InstallFailureRule with its regexes compiled, ready to be matched against install logs.
+type installFailureRule struct {
+	name      string
+	priority  int32
+	platforms sets.String
+	regexes   []*regexp.Regexp
+	reason    string
+	message   string
+	retryable bool
+}
+
+// appliesTo returns whether the rule should be matched against the install log of a cluster on the given platform.
+func (rule *installFailureRule) appliesTo(platform string) bool {
+	return rule.platforms.Len() == 0 || rule.platforms.Has(platform)
+}
+
+// matches returns whether any of the regexes of the rule matches the install log.
+func (rule *installFailureRule) matches(installLog string) bool {
+	for _, re := range rule.regexes {
+		if re.MatchString(installLog) {
+			return true
+		}
+	}
+	return false
+}
+
+// installFailureRuleCache caches the compiled InstallFailureRules. The rules are read from the cache of the
+// manager, which watches InstallFailureRules, and are only compiled again when a rule has changed.
+type installFailureRuleCache struct {
+	mutex sync.Mutex
+	// resourceVersions are the resource versions of the compiled rules, keyed by the name of the rule.
+	resourceVersions map[string]string
+	// rules are the compiled rules in the order in which they are matched.
+	rules []*installFailureRule
+}
+
+// getInstallFailureRules returns the compiled InstallFailureRules in the order in which they should be matched.
+func (r *ReconcileClusterProvision) getInstallFailureRules(pLog log.FieldLogger) ([]*installFailureRule, error) {
+	ruleList := &hivev1.InstallFailureRuleList{}
+	if err := r.List(context.TODO(), ruleList); err != nil {
+		return nil, err
+	}
+	return r.failureRules.get(ruleList.Items, pLog), nil
+}
+
+// get returns the compiled rules, compiling them again if any of the rules have changed since the last call.
+func (c *installFailureRuleCache) get(rules []hivev1.InstallFailureRule, pLog log.FieldLogger) []*installFailureRule {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	resourceVersions := make(map[string]string, len(rules))
+	for _, rule := range rules {
+		resourceVersions[rule.Name] = rule.ResourceVersion
+	}
+	if c.resourceVersions != nil && equalResourceVersions(c.resourceVersions, resourceVersions) {
+		return c.rules
+	}
+
+	pLog.WithField("rules", len(rules)).Info("compiling install failure rules")
+	compiled := make([]*installFailureRule, 0, len(rules))
+	for i := range rules {
+		if rule := compileInstallFailureRule(&rules[i], pLog); rule != nil {
+			compiled = append(compiled, rule)
+		}
+	}
+	sort.SliceStable(compiled, func(i, j int) bool {
+		if compiled[i].priority != compiled[j].priority {
+			return compiled[i].priority > compiled[j].priority
+		}
+		return compiled[i].name < compiled[j].name
+	})
+	c.resourceVersions = resourceVersions
+	c.rules = compiled
+	return c.rules
+}
+
+// compileInstallFailureRule compiles the regexes of an InstallFailureRule. Returns nil if any of the regexes does
+// not compile, so that one bad rule does not prevent matching the other rules.
+func compileInstallFailureRule(rule *hivev1.InstallFailureRule, pLog log.FieldLogger) *installFailureRule {
+	ruleLog := pLog.WithField("installFailureRule", rule.Name)
+	compiled := &installFailureRule{
+		name:      rule.Name,
+		priority:  rule.Spec.Priority,
+		platforms: sets.NewString(rule.Spec.Platforms...),
+		reason:    rule.Spec.InstallFailingReason,
+		message:   rule.Spec.InstallFailingMessage,
+		retryable: rule.Spec.Retryable == nil || *rule.Spec.Retryable,
+	}
+	for _, s := range rule.Spec.SearchRegexStrings {
+		re, err := regexp.Compile(s)
+		if err != nil {
+			ruleLog.WithError(err).WithField("searchString", s).Error("unable to compile regex, skipping rule")
+			return nil
+		}
+		compiled.regexes = append(compiled.regexes, re)
+	}
+	return compiled
+}
+
+func equalResourceVersions(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, version := range a {
+		if v, ok := b[name]; !ok || v != version {
+			return false
+		}
+	}
+	return true
+}
@@ -1,80 +1,153 @@
 package clusterprovision
 
 import (
-	"context"
+	"fmt"
 	"regexp"
 	"strings"
 
-	"github.com/ghodss/yaml"
 	log "github.com/sirupsen/logrus"
 
-	corev1 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 
-	"github.com/openshift/hive/pkg/constants"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
 )
 
 const (
-	regexConfigMapName = "install-log-regexes"
-	regexDataEntryName = "regexes"
-	unknownReason      = "UnknownError"
-	logMissingMessage  = "Cluster install failed but installer log was not captured"
-	regexBadMessage    = "Cluster install failed but regex configmap to parse for known reasons could not be used"
-	unknownMessage     = "Cluster install failed but no known errors found in logs"
+	unknownReason           = "UnknownError"
+	logMissingMessage       = "Cluster install failed but installer log was not captured"
+	rulesUnavailableMessage = "Cluster install failed but install failure rules to parse for known reasons could not be loaded"
+	unknownMessage          = "Cluster install failed but no known errors found in logs"
+
+	clusterOperatorsNotAvailableReason = "ClusterOperatorsNotAvailable"
 )
 
-// parseInstallLog parses install log to monitor for known issues.
-func (r *ReconcileClusterProvision) parseInstallLog(log *string, pLog log.FieldLogger) (string, string) {
-	if log == nil {
-		return unknownReason, logMissingMessage
+var (
+	// installStageMarkers are the progress messages logged by the installer when it enters each stage of the
+	// install. The stage in which the install failed is the stage of the last marker found in the log.
+	installStageMarkers = []struct {
+		stage  hivev1.InstallFailureStage
+		marker *regexp.Regexp
+	}{
+		{
+			stage:  hivev1.InstallFailureStageInfrastructure,
+			marker: regexp.MustCompile(`Creating infrastructure resources`),
+		},
+		{
+			stage:  hivev1.InstallFailureStageBootstrap,
+			marker: regexp.MustCompile(`Waiting up to \S+ for the Kubernetes API`),
+		},
+		{
+			stage:  hivev1.InstallFailureStageBootstrap,
+			marker: regexp.MustCompile(`Waiting up to \S+ for bootstrapping to complete`),
+		},
+		{
+			stage:  hivev1.InstallFailureStageClusterOperators,
+			marker: regexp.MustCompile(`Waiting up to \S+ for the cluster at \S+ to initialize`),
+		},
 	}
 
-	// Load the regex configmap, if we don't have one, there's not much point proceeding here.
-	regexCM := &corev1.ConfigMap{}
-	if err := r.Get(context.TODO(), types.NamespacedName{Name: regexConfigMapName, Namespace: constants.HiveNamespace}, regexCM); err != nil {
-		pLog.WithError(err).Errorf("error loading %s configmap", regexConfigMapName)
-		// Even if the error was a transient error in fetching the configmap, we should not block
-		// the continuation of deploying the cluster just so that we can potentially get a
-		// better failure message.
-		return unknownReason, regexBadMessage
+	// failingOperatorRegex matches the installer messages about a single cluster operator that is not available.
+	failingOperatorRegex = regexp.MustCompile(`Cluster operator (\S+) (?:is still updating|is reporting a failure|has not yet reported success|Degraded is True|Available is False)`)
+
+	// failingOperatorsRegex matches the installer message listing all cluster operators that are still updating.
+	failingOperatorsRegex = regexp.MustCompile(`[Ss]ome cluster operators are still updating: ([a-z0-9-]+(?:, [a-z0-9-]+)*)`)
+)
+
+// parseInstallLog parses install log to classify the failure of the install of a cluster on the given platform.
+func (r *ReconcileClusterProvision) parseInstallLog(installLog *string, platform string, pLog log.FieldLogger) *hivev1.InstallFailure {
+	if installLog == nil {
+		return &hivev1.InstallFailure{
+			Reason:    unknownReason,
+			Message:   logMissingMessage,
+			Stage:     hivev1.InstallFailureStageUnknown,
+			Retryable: true,
+		}
 	}
 
-	regexesRaw, ok := regexCM.Data[regexDataEntryName]
-	if !ok {
-		pLog.Errorf("%s configmap does not have a %q data entry", regexConfigMapName, regexDataEntryName)
-		return unknownReason, regexBadMessage
+	failure := &hivev1.InstallFailure{
+		Stage:            detectInstallFailureStage(*installLog),
+		FailingOperators: detectFailingOperators(*installLog),
+	}
+	failure.Reason, failure.Message, failure.Retryable = r.matchKnownFailure(*installLog, platform, pLog)
+	if failure.Reason == unknownReason && len(failure.FailingOperators) > 0 {
+		failure.Reason = clusterOperatorsNotAvailableReason
+		failure.Message = fmt.Sprintf("Timeout waiting for cluster operators to become available: %s", strings.Join(failure.FailingOperators, ", "))
 	}
+	pLog.WithFields(log.Fields{
+		"reason":           failure.Reason,
+		"stage":            failure.Stage,
+		"failingOperators": failure.FailingOperators,
+		"retryable":        failure.Retryable,
+	}).Info("classified install failure")
+	return failure
+}
 
-	regexes := []installLogRegex{}
-	if err := yaml.Unmarshal([]byte(regexesRaw), &regexes); err != nil {
-		pLog.WithError(err).Errorf("cannot unmarshal data from %s configmap", regexConfigMapName)
-		return unknownReason, regexBadMessage
+// matchKnownFailure matches the install log against the InstallFailureRules for the platform of the cluster. Returns
+// the reason and message for the failure, and whether a new provision may succeed.
+func (r *ReconcileClusterProvision) matchKnownFailure(installLog, platform string, pLog log.FieldLogger) (string, string, bool) {
+	rules, err := r.getInstallFailureRules(pLog)
+	if err != nil {
+		pLog.WithError(err).Error("error loading install failure rules")
+		// Even if the error was a transient error in fetching the rules, we should not block
+		// the continuation of deploying the cluster just so that we can potentially get a
+		// better failure message.
+		return unknownReason, rulesUnavailableMessage, true
 	}
 
 	pLog.Info("processing new install log")
 
 	// Log each line separately, this brings all our install logs from many namespaces into
 	// the main hive log where we can aggregate search results.
-	for _, l := range strings.Split(*log, "\n") {
+	for _, l := range strings.Split(installLog, "\n") {
 		pLog.WithField("line", l).Info("install log line")
 	}
 
 	// Scan log contents for known errors
-	for _, ilr := range regexes {
-		ilrLog := pLog.WithField("regexName", ilr.Name)
-		ilrLog.Debug("parsing regex entry")
-		for _, ss := range ilr.SearchRegexStrings {
-			ssLog := ilrLog.WithField("searchString", ss)
-			ssLog.Debug("matching search string")
-			switch match, err := regexp.Match(ss, []byte(*log)); {
-			case err != nil:
-				ssLog.WithError(err).Error("unable to compile regex")
-			case match:
-				pLog.WithField("reason", ilr.InstallFailingReason).Info("found known install failure string")
-				return ilr.InstallFailingReason, ilr.InstallFailingMessage
-			}
+	for _, rule := range rules {
+		ruleLog := pLog.WithField("installFailureRule", rule.name)
+		if !rule.appliesTo(platform) {
+			ruleLog.Debug("skipping rule for other platforms")
+			continue
+		}
+		ruleLog.Debug("matching rule")
+		if rule.matches(installLog) {
+			ruleLog.WithField("reason", rule.reason).Info("found known install failure string")
+			return rule.reason, rule.message, rule.retryable
 		}
 	}
 
-	return unknownReason, unknownMessage
+	return unknownReason, unknownMessage, true
+}
+
+// detectInstallFailureStage returns the stage of the install that the installer reached before failing.
+func detectInstallFailureStage(installLog string) hivev1.InstallFailureStage {
+	stage := hivev1.InstallFailureStageUnknown
+	lastIndex := -1
+	for _, m := range installStageMarkers {
+		matches := m.marker.FindAllStringIndex(installLog, -1)
+		if len(matches) == 0 {
+			continue
+		}
+		if index := matches[len(matches)-1][0]; index > lastIndex {
+			lastIndex = index
+			stage = m.stage
+		}
+	}
+	return stage
+}
+
+// detectFailingOperators returns the sorted names of the cluster operators that the installer reported as not
+// available.
+func detectFailingOperators(installLog string) []string {
+	operators := sets.NewString()
+	for _, match := range failingOperatorRegex.FindAllStringSubmatch(installLog, -1) {
+		operators.Insert(match[1])
+	}
+	for _, match := range failingOperatorsRegex.FindAllStringSubmatch(installLog, -1) {
+		operators.Insert(strings.Split(match[1], ", ")...)
+	}
+	if operators.Len() == 0 {
+		return nil
+	}
+	return operators.List()
 }
@@ -1,6 +1,7 @@
 package clusterprovision
 
 import (
+	"fmt"
 	"testing"
 
 	"k8s.io/apimachinery/pkg/runtime"
@@ -8,7 +9,6 @@ import (
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 
-	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/utils/pointer"
@@ -16,7 +16,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"github.com/openshift/hive/pkg/apis"
-	"github.com/openshift/hive/pkg/constants"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
 )
 
 func init() {
@@ -26,108 +26,121 @@ func init() {
 const (
 	dnsAlreadyExistsLog    = "blahblah\naws_route53_record.api_external: [ERR]: Error building changeset: InvalidChangeBatch: [Tried to create resource record set [name='api.jh-stg-2405-2.n6b3.s1.devshift.org.'type='A'] but it already exists]\n\nblahblah"
 	pendingVerificationLog = "blahblah\naws_instance.master.2: Error launching source instance: PendingVerification: Your request for accessing resources in this region is being validated, and you will not be able to launch additional resources in this region until the validation is complete. We will notify you by email once your request has been validated. While normally resolved within minutes, please allow up to 4 hours for this process to complete. If the issue still persists, please let us know by writing to awsa\n\nblahblah"
+	natGatewayLimitLog     = `level=info msg="Creating infrastructure resources..."
+level=error msg="Error: Error creating NAT Gateway: NatGatewayLimitExceeded: The maximum number of NAT Gateways has been reached."
+level=fatal msg="failed to fetch Cluster: failed to generate asset \"Cluster\": failed to create cluster: failed to apply using Terraform"`
+	kubeAPITimeoutLog = `level=info msg="Creating infrastructure resources..."
+level=info msg="Waiting up to 20m0s for the Kubernetes API at https://api.test.example.com:6443..."
+level=fatal msg="waiting for Kubernetes API: context deadline exceeded"`
+	clusterOperatorsLog = `level=info msg="Creating infrastructure resources..."
+level=info msg="Waiting up to 20m0s for the Kubernetes API at https://api.test.example.com:6443..."
+level=info msg="Waiting up to 40m0s for bootstrapping to complete..."
+level=info msg="Destroying the bootstrap resources..."
+level=info msg="Waiting up to 30m0s for the cluster at https://api.test.example.com:6443 to initialize..."
+level=error msg="Cluster operator authentication Degraded is True with RouteHealthDegradedFailedGet: failed to GET route"
+level=info msg="Cluster operator console Available is False with DeploymentAvailableFailedUpdate: Deployment is not available"
+level=fatal msg="failed to initialize the cluster: Some cluster operators are still updating: authentication, console, ingress"`
 )
 
 func TestParseInstallLog(t *testing.T) {
 	apis.AddToScheme(scheme.Scheme)
 	tests := []struct {
-		name           string
-		log            *string
-		existing       []runtime.Object
-		expectedReason string
+		name                     string
+		log                      *string
+		platform                 string
+		existing                 []runtime.Object
+		expectedReason           string
+		expectedStage            hivev1.InstallFailureStage
+		expectedFailingOperators []string
+		expectNotRetryable       bool
 	}{
 		{
 			name:           "DNS already exists",
 			log:            pointer.StringPtr(dnsAlreadyExistsLog),
-			existing:       []runtime.Object{buildRegexConfigMap()},
+			existing:       testInstallFailureRules(),
 			expectedReason: "DNSAlreadyExists",
 		},
 		{
 			name:           "PendingVerification",
 			log:            pointer.StringPtr(pendingVerificationLog),
-			existing:       []runtime.Object{buildRegexConfigMap()},
+			existing:       testInstallFailureRules(),
 			expectedReason: "PendingVerification",
 		},
+		{
+			name:               "not retryable failure in infrastructure",
+			log:                pointer.StringPtr(natGatewayLimitLog),
+			existing:           testInstallFailureRules(),
+			expectedReason:     "AWSNATGatewayLimitExceeded",
+			expectedStage:      hivev1.InstallFailureStageInfrastructure,
+			expectNotRetryable: true,
+		},
+		{
+			name:           "failure in bootstrap",
+			log:            pointer.StringPtr(kubeAPITimeoutLog),
+			existing:       testInstallFailureRules(),
+			expectedReason: unknownReason,
+			expectedStage:  hivev1.InstallFailureStageBootstrap,
+		},
+		{
+			name:                     "failing cluster operators",
+			log:                      pointer.StringPtr(clusterOperatorsLog),
+			existing:                 testInstallFailureRules(),
+			expectedReason:           clusterOperatorsNotAvailableReason,
+			expectedStage:            hivev1.InstallFailureStageClusterOperators,
+			expectedFailingOperators: []string{"authentication", "console", "ingress"},
+		},
 		{
 			name:           "no log",
-			existing:       []runtime.Object{buildRegexConfigMap()},
+			existing:       testInstallFailureRules(),
 			expectedReason: unknownReason,
 		},
 		{
-			name:           "missing regex configmap",
+			name:           "no install failure rules",
 			log:            pointer.StringPtr(dnsAlreadyExistsLog),
 			expectedReason: unknownReason,
 		},
 		{
-			name: "missing regexes data entry",
-			log:  pointer.StringPtr(dnsAlreadyExistsLog),
-			existing: []runtime.Object{&corev1.ConfigMap{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      regexConfigMapName,
-					Namespace: constants.HiveNamespace,
-				},
-			}},
+			name:           "skip rule for other platform",
+			log:            pointer.StringPtr(dnsAlreadyExistsLog),
+			platform:       "gcp",
+			existing:       testInstallFailureRules(),
 			expectedReason: unknownReason,
 		},
 		{
-			name: "malformed regex",
-			log:  pointer.StringPtr(dnsAlreadyExistsLog),
-			existing: []runtime.Object{&corev1.ConfigMap{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      regexConfigMapName,
-					Namespace: constants.HiveNamespace,
-				},
-				BinaryData: map[string][]byte{
-					"regexes": []byte("malformed"),
-				},
-			}},
-			expectedReason: unknownReason,
+			name:     "rule for all platforms",
+			log:      pointer.StringPtr(dnsAlreadyExistsLog),
+			platform: "gcp",
+			existing: []runtime.Object{
+				testInstallFailureRule("dns-already-exists", "DNSAlreadyExists", 0, nil, "Tried to create resource record set.*but it already exists"),
+			},
+			expectedReason: "DNSAlreadyExists",
 		},
 		{
-			name: "skip bad regex entry",
+			name: "skip rule with bad regex",
 			log:  pointer.StringPtr(dnsAlreadyExistsLog),
-			existing: []runtime.Object{&corev1.ConfigMap{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      regexConfigMapName,
-					Namespace: constants.HiveNamespace,
-				},
-				Data: map[string]string{
-					"regexes": `
-- name: BadEntry
-  searchRegexStrings:
-  - "*"
-  installFailingReason: BadEntry
-  installFailingMessage: Bad entry
-- name: DNSAlreadyExists
-  searchRegexStrings:
-  - "aws_route53_record.*Error building changeset:.*Tried to create resource record set.*but it already exists"
-  installFailingReason: DNSAlreadyExists
-  installFailingMessage: DNS record already exists
-`,
-				},
-			}},
+			existing: []runtime.Object{
+				testInstallFailureRule("bad-rule", "BadRule", 10, nil, "*", "aws_route53_record"),
+				testInstallFailureRule("dns-already-exists", "DNSAlreadyExists", 0, nil, "aws_route53_record"),
+			},
 			expectedReason: "DNSAlreadyExists",
 		},
 		{
-			name: "skip bad search string",
+			name: "match rules in priority order",
 			log:  pointer.StringPtr(dnsAlreadyExistsLog),
-			existing: []runtime.Object{&corev1.ConfigMap{
-				ObjectMeta: metav1.ObjectMeta{
-					Name:      regexConfigMapName,
-					Namespace: constants.HiveNamespace,
-				},
-				Data: map[string]string{
-					"regexes": `
-- name: DNSAlreadyExists
-  searchRegexStrings:
-  - "*"
-  - "aws_route53_record.*Error building changeset:.*Tried to create resource record set.*but it already exists"
-  installFailingReason: DNSAlreadyExists
-  installFailingMessage: DNS record already exists
-`,
-				},
-			}},
-			expectedReason: "DNSAlreadyExists",
+			existing: []runtime.Object{
+				testInstallFailureRule("a-generic", "Generic", 0, nil, "Error building changeset"),
+				testInstallFailureRule("b-specific", "Specific", 10, nil, "Error building changeset"),
+			},
+			expectedReason: "Specific",
+		},
+		{
+			name: "match rules with same priority in name order",
+			log:  pointer.StringPtr(dnsAlreadyExistsLog),
+			existing: []runtime.Object{
+				testInstallFailureRule("b-rule", "B", 0, nil, "Error building changeset"),
+				testInstallFailureRule("a-rule", "A", 0, nil, "Error building changeset"),
+			},
+			expectedReason: "A",
 		},
 	}
 
@@ -138,33 +151,67 @@ func TestParseInstallLog(t *testing.T) {
 				Client: fakeClient,
 				scheme: scheme.Scheme,
 			}
-			reason, message := r.parseInstallLog(test.log, log.WithFields(log.Fields{}))
-			assert.Equal(t, test.expectedReason, reason, "unexpected reason")
-			assert.NotEmpty(t, message, "expected message to be not empty")
+			platform := test.platform
+			if platform == "" {
+				platform = "aws"
+			}
+			failure := r.parseInstallLog(test.log, platform, log.WithFields(log.Fields{}))
+			assert.Equal(t, test.expectedReason, failure.Reason, "unexpected reason")
+			assert.NotEmpty(t, failure.Message, "expected message to be not empty")
+			expectedStage := test.expectedStage
+			if expectedStage == "" {
+				expectedStage = hivev1.InstallFailureStageUnknown
+			}
+			assert.Equal(t, expectedStage, failure.Stage, "unexpected stage")
+			assert.Equal(t, test.expectedFailingOperators, failure.FailingOperators, "unexpected failing operators")
+			assert.Equal(t, !test.expectNotRetryable, failure.Retryable, "unexpected retryable")
 		})
 	}
 }
 
-func buildRegexConfigMap() *corev1.ConfigMap {
-	cm := &corev1.ConfigMap{
+func TestInstallFailureRuleCache(t *testing.T) {
+	logger := log.WithField("test", "TestInstallFailureRuleCache")
+	rule := testInstallFailureRule("dns-already-exists", "DNSAlreadyExists", 0, nil, "aws_route53_record")
+	rule.ResourceVersion = "1"
+	cache := &installFailureRuleCache{}
+
+	rules := cache.get([]hivev1.InstallFailureRule{*rule}, logger)
+	if assert.Len(t, rules, 1, "unexpected number of rules") {
+		assert.Equal(t, "DNSAlreadyExists", rules[0].reason, "unexpected reason")
+	}
+	assert.True(t, rules[0] == cache.get([]hivev1.InstallFailureRule{*rule}, logger)[0], "expected unchanged rule to not be compiled again")
+
+	rule.ResourceVersion = "2"
+	rule.Spec.InstallFailingReason = "UpdatedReason"
+	rules = cache.get([]hivev1.InstallFailureRule{*rule}, logger)
+	if assert.Len(t, rules, 1, "unexpected number of rules") {
+		assert.Equal(t, "UpdatedReason", rules[0].reason, "expected updated rule to be compiled again")
+	}
+
+	assert.Empty(t, cache.get(nil, logger), "expected deleted rule to be removed")
+}
+
+func testInstallFailureRules() []runtime.Object {
+	natGatewayLimit := testInstallFailureRule("aws-nat-gateway-limit-exceeded", "AWSNATGatewayLimitExceeded", 10, []string{"aws"}, "NatGatewayLimitExceeded")
+	natGatewayLimit.Spec.Retryable = pointer.BoolPtr(false)
+	return []runtime.Object{
+		natGatewayLimit,
+		testInstallFailureRule("dns-already-exists", "DNSAlreadyExists", 10, []string{"aws"}, "aws_route53_record.*Error building changeset:.*Tried to create resource record set.*but it already exists"),
+		testInstallFailureRule("pending-verification", "PendingVerification", 10, []string{"aws"}, "PendingVerification: Your request for accessing resources in this region is being validated"),
+	}
+}
+
+func testInstallFailureRule(name, reason string, priority int32, platforms []string, regexes ...string) *hivev1.InstallFailureRule {
+	return &hivev1.InstallFailureRule{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      regexConfigMapName,
-			Namespace: constants.HiveNamespace,
+			Name: name,
 		},
-		Data: map[string]string{
-			"regexes": `
-- name: DNSAlreadyExists
-  searchRegexStrings:
-  - "aws_route53_record.*Error building changeset:.*Tried to create resource record set.*but it already exists"
-  installFailingReason: DNSAlreadyExists
-  installFailingMessage: DNS record already exists
-- name: PendingVerification
-  searchRegexStrings:
-  - "PendingVerification: Your request for accessing resources in this region is being validated"
-  installFailingReason: PendingVerification
-  installFailingMessage: Account pending verification for region
-`,
+		Spec: hivev1.InstallFailureRuleSpec{
+			Priority:              priority,
+			Platforms:             platforms,
+			SearchRegexStrings:    regexes,
+			InstallFailingReason:  reason,
+			InstallFailingMessage: fmt.Sprintf("Install failed with %s", reason),
 		},
 	}
-	return cm
 }
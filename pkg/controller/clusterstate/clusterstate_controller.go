@@ -56,12 +56,16 @@ func NewReconciler(mgr manager.Manager) reconcile.Reconciler {
 
 // AddToManager adds a new Controller to mgr with r as the reconcile.Reconciler
 func AddToManager(mgr manager.Manager, r reconcile.Reconciler) error {
-	c, err := controller.New("clusterstate-controller", mgr, controller.Options{Reconciler: r, MaxConcurrentReconciles: controllerutils.GetConcurrentReconciles()})
+	c, err := controller.New("clusterstate-controller", mgr, controller.Options{Reconciler: controllerutils.NewShardedReconciler(r), MaxConcurrentReconciles: controllerutils.GetConcurrentReconciles(controllerName)})
 	if err != nil {
 		log.WithField("controller", controllerName).WithError(err).Error("Error creating new clusterstate controller")
 		return err
 	}
 
+	if err := controllerutils.SetQueueRateLimiter(c, controllerName); err != nil {
+		return err
+	}
+
 	// Watch for changes to ClusterDeployment
 	err = c.Watch(&source.Kind{Type: &hivev1.ClusterDeployment{}}, &handler.EnqueueRequestForObject{})
 	if err != nil {
@@ -178,7 +182,7 @@ func (r *ReconcileClusterState) Reconcile(request reconcile.Request) (reconcile.
 		}
 	}
 
-	remoteClient, err := remoteClientBuilder.Build()
+	remoteClient, err := remoteClientBuilder.BuildCached()
 	if err != nil {
 		logger.WithError(err).Error("error building remote cluster client connection")
 		return reconcile.Result{}, err
@@ -165,7 +165,7 @@ func TestClusterStateReconcile(t *testing.T) {
 			mockRemoteClientBuilder := remoteclientmock.NewMockBuilder(mockCtrl)
 			mockRemoteClientBuilder.EXPECT().Unreachable().Return(false)
 			if !test.noRemoteCall {
-				mockRemoteClientBuilder.EXPECT().Build().Return(fake.NewFakeClient(test.remote...), nil)
+				mockRemoteClientBuilder.EXPECT().BuildCached().Return(fake.NewFakeClient(test.remote...), nil)
 			}
 			updateCalled := false
 			rcd := &ReconcileClusterState{
@@ -0,0 +1,427 @@
+// Package clusterupgrade provides a controller which upgrades installed clusters to the release requested in the
+// upgrade field of their ClusterDeployment, and tracks the progress of the upgrade in ClusterDeployment conditions.
+package clusterupgrade
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	openshiftapiv1 "github.com/openshift/api/config/v1"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	hivemetrics "github.com/openshift/hive/pkg/controller/metrics"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+	"github.com/openshift/hive/pkg/releaseimage"
+	"github.com/openshift/hive/pkg/remoteclient"
+)
+
+const (
+	controllerName = "clusterUpgrade"
+
+	clusterVersionObjectName = "version"
+
+	// clusterVersionFailingCondition is the condition of the ClusterVersion set by the cluster version operator when
+	// it fails to apply the desired update.
+	clusterVersionFailingCondition openshiftapiv1.ClusterStatusConditionType = "Failing"
+
+	// upgradeProgressCheckInterval is how often the progress of an upgrade is checked while the cluster is upgrading.
+	upgradeProgressCheckInterval = 5 * time.Minute
+
+	// releaseImageSignatureRecheckInterval is how often the signatures of a release image are looked for again when
+	// none could be verified.
+	releaseImageSignatureRecheckInterval = 10 * time.Minute
+
+	clusterImageSetNotFoundReason  = "ClusterImageSetNotFound"
+	releaseImageNotAllowedReason   = "ReleaseImageNotAllowed"
+	clusterUnreachableReason       = "ClusterUnreachable"
+	clusterHibernatingReason       = "ClusterHibernating"
+	syncSetsFailingReason          = "SyncSetsFailing"
+	outsideMaintenanceWindowReason = "OutsideMaintenanceWindow"
+	upgradeStartedReason           = "UpgradeStarted"
+	upgradingReason                = "Upgrading"
+	upgradeCompletedReason         = "UpgradeCompleted"
+	upgradeFailingReason           = "UpgradeFailing"
+	upgradeNotFailingReason        = "UpgradeNotFailing"
+	upgradeNotRequestedReason      = "UpgradeNotRequested"
+)
+
+// Add creates a new ClusterUpgrade Controller and adds it to the Manager with default RBAC. The Manager will set fields on the
+// Controller and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	r, err := NewReconciler(mgr)
+	if err != nil {
+		return err
+	}
+	return AddToManager(mgr, r)
+}
+
+// NewReconciler returns a new reconcile.Reconciler
+func NewReconciler(mgr manager.Manager) (reconcile.Reconciler, error) {
+	logger := log.WithField("controller", controllerName)
+	releaseImagePolicy, err := releaseimage.PolicyFromEnvironment()
+	if err != nil {
+		logger.WithError(err).Error("cannot read release image policy")
+		return nil, err
+	}
+	r := &ReconcileClusterUpgrade{
+		Client:             controllerutils.NewClientWithMetricsOrDie(mgr, controllerName),
+		scheme:             mgr.GetScheme(),
+		logger:             logger,
+		eventRecorder:      mgr.GetEventRecorderFor(controllerName),
+		releaseImagePolicy: releaseImagePolicy,
+	}
+	r.remoteClientBuilder = func(cd *hivev1.ClusterDeployment) remoteclient.Builder {
+		return remoteclient.NewBuilder(r.Client, cd, controllerName)
+	}
+	if verifier := releaseimage.NewSignatureVerifier(r.Client, releaseImagePolicy); verifier != nil {
+		r.verifyReleaseImageSignature = verifier.Verify
+	}
+	return r, nil
+}
+
+// AddToManager adds a new Controller to mgr with r as the reconcile.Reconciler
+func AddToManager(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New("clusterupgrade-controller", mgr, controller.Options{Reconciler: controllerutils.NewShardedReconciler(r), MaxConcurrentReconciles: controllerutils.GetConcurrentReconciles(controllerName)})
+	if err != nil {
+		return err
+	}
+
+	if err := controllerutils.SetQueueRateLimiter(c, controllerName); err != nil {
+		return err
+	}
+
+	// Watch for changes to ClusterDeployment
+	err = c.Watch(&source.Kind{Type: &hivev1.ClusterDeployment{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	reconciler := r.(*ReconcileClusterUpgrade)
+
+	// Watch for changes to ClusterImageSets, to start upgrades blocked by a missing ClusterImageSet
+	err = c.Watch(&source.Kind{Type: &hivev1.ClusterImageSet{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(reconciler.clusterImageSetWatchHandler),
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// clusterImageSetWatchHandler requests the reconcile of the ClusterDeployments that upgrade to the release of the
+// ClusterImageSet.
+func (r *ReconcileClusterUpgrade) clusterImageSetWatchHandler(a handler.MapObject) []reconcile.Request {
+	cdList := &hivev1.ClusterDeploymentList{}
+	if err := r.List(context.TODO(), cdList); err != nil {
+		r.logger.WithError(err).Error("error listing cluster deployments")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, cd := range cdList.Items {
+		upgrade := cd.Spec.Upgrade
+		if upgrade == nil || upgrade.ReleaseImage != "" || upgrade.ImageSetRef == nil || upgrade.ImageSetRef.Name != a.Meta.GetName() {
+			continue
+		}
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+			Name:      cd.Name,
+			Namespace: cd.Namespace,
+		}})
+	}
+	return requests
+}
+
+var _ reconcile.Reconciler = &ReconcileClusterUpgrade{}
+
+// ReconcileClusterUpgrade upgrades the cluster of a ClusterDeployment object
+type ReconcileClusterUpgrade struct {
+	client.Client
+	scheme        *runtime.Scheme
+	logger        log.FieldLogger
+	eventRecorder record.EventRecorder
+
+	// remoteClientBuilder is a function pointer to the function that gets a builder for building a client
+	// for the remote cluster's API server
+	remoteClientBuilder func(cd *hivev1.ClusterDeployment) remoteclient.Builder
+
+	// releaseImagePolicy restricts the release images to which clusters can be upgraded.
+	releaseImagePolicy *hivev1.ReleaseImagePolicy
+
+	// verifyReleaseImageSignature is a function pointer to the function that verifies the signatures of release
+	// images required by the release image policy, if any.
+	verifyReleaseImageSignature func(releaseImage string) error
+}
+
+// Reconcile sets the desired update of the remote ClusterVersion to the release requested for the cluster, unless the
+// upgrade is blocked, and reflects the progress of the upgrade in the conditions of the ClusterDeployment.
+func (r *ReconcileClusterUpgrade) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	start := time.Now()
+	cdLog := r.logger.WithFields(log.Fields{
+		"clusterDeployment": request.Name,
+		"namespace":         request.Namespace,
+	})
+
+	cdLog.Info("reconciling cluster deployment")
+	defer func() {
+		dur := time.Since(start)
+		hivemetrics.MetricControllerReconcileTime.WithLabelValues(controllerName).Observe(dur.Seconds())
+		cdLog.WithField("elapsed", dur).Info("reconcile complete")
+	}()
+
+	// Fetch the ClusterDeployment instance
+	cd := &hivev1.ClusterDeployment{}
+	err := r.Get(context.TODO(), request.NamespacedName, cd)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		cdLog.WithError(err).Error("error looking up cluster deployment")
+		return reconcile.Result{}, err
+	}
+
+	// If the clusterdeployment is deleted, do not reconcile.
+	if cd.DeletionTimestamp != nil {
+		return reconcile.Result{}, nil
+	}
+
+	if cd.Spec.Upgrade == nil {
+		// Clear the conditions of an upgrade that is no longer requested.
+		return reconcile.Result{}, r.setConditions(cd, cdLog,
+			condition(hivev1.UpgradeBlockedCondition, corev1.ConditionFalse, upgradeNotRequestedReason, "No upgrade is requested"),
+			condition(hivev1.UpgradeProgressingCondition, corev1.ConditionFalse, upgradeNotRequestedReason, "No upgrade is requested"),
+			condition(hivev1.UpgradeFailedCondition, corev1.ConditionFalse, upgradeNotRequestedReason, "No upgrade is requested"),
+		)
+	}
+
+	if !cd.Spec.Installed {
+		cdLog.Debug("cluster installation is not complete")
+		return reconcile.Result{}, nil
+	}
+
+	if cd.Spec.ClusterMetadata == nil {
+		cdLog.Error("installed cluster with no cluster metadata")
+		return reconcile.Result{}, nil
+	}
+
+	releaseImage, err := r.getReleaseImage(cd)
+	if err != nil {
+		if apierrors.IsNotFound(errors.Cause(err)) {
+			return reconcile.Result{}, r.setBlocked(cd, clusterImageSetNotFoundReason, err.Error(), cdLog)
+		}
+		if notAllowed, ok := err.(*releaseImageNotAllowedError); ok {
+			cdLog.WithError(err).Warn("release image is not allowed by the release image policy")
+			result := reconcile.Result{}
+			if _, ok := notAllowed.error.(*releaseimage.SignatureNotVerifiedError); ok {
+				// Signatures may be published after the upgrade is requested.
+				result.RequeueAfter = releaseImageSignatureRecheckInterval
+			}
+			return result, r.setBlocked(cd, releaseImageNotAllowedReason, err.Error(), cdLog)
+		}
+		cdLog.WithError(err).Error("error getting release image to upgrade to")
+		return reconcile.Result{}, err
+	}
+	cdLog = cdLog.WithField("releaseImage", releaseImage)
+
+	// An upgrade that has already started is left to the cluster, its progress is checked once the cluster can be
+	// reached again.
+	upgrading := conditionTrue(cd, hivev1.UpgradeProgressingCondition)
+	if cd.Spec.PowerState == hivev1.HibernatingClusterPowerState {
+		if upgrading {
+			cdLog.Debug("skipping upgrade progress check for hibernating cluster")
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, r.setBlocked(cd, clusterHibernatingReason, "Cluster is hibernating", cdLog)
+	}
+	remoteClientBuilder := r.remoteClientBuilder(cd)
+	if remoteClientBuilder.Unreachable() {
+		if upgrading {
+			cdLog.Debug("skipping upgrade progress check for unreachable cluster")
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, r.setBlocked(cd, clusterUnreachableReason, "Cluster is unreachable", cdLog)
+	}
+
+	remoteClient, err := remoteClientBuilder.Build()
+	if err != nil {
+		cdLog.WithError(err).Error("error building remote cluster-api client connection")
+		return reconcile.Result{}, err
+	}
+	clusterVersion := &openshiftapiv1.ClusterVersion{}
+	if err := remoteClient.Get(context.TODO(), types.NamespacedName{Name: clusterVersionObjectName}, clusterVersion); err != nil {
+		cdLog.WithError(err).Error("error fetching remote clusterversion object")
+		return reconcile.Result{}, err
+	}
+
+	if upgradeCompleted(clusterVersion, releaseImage) {
+		if upgrading {
+			cdLog.Info("cluster upgrade completed")
+			r.eventRecorder.Eventf(cd, corev1.EventTypeNormal, upgradeCompletedReason, "Upgraded cluster to %s", releaseImage)
+		}
+		message := fmt.Sprintf("Cluster is running %s", releaseImage)
+		return reconcile.Result{}, r.setConditions(cd, cdLog,
+			condition(hivev1.UpgradeBlockedCondition, corev1.ConditionFalse, upgradeCompletedReason, message),
+			condition(hivev1.UpgradeProgressingCondition, corev1.ConditionFalse, upgradeCompletedReason, message),
+			condition(hivev1.UpgradeFailedCondition, corev1.ConditionFalse, upgradeCompletedReason, message),
+		)
+	}
+
+	desired := clusterVersion.Spec.DesiredUpdate
+	if desired == nil || desired.Image != releaseImage || desired.Force != cd.Spec.Upgrade.Force {
+		if open, next := controllerutils.InMaintenanceWindow(cd.Spec.MaintenanceWindows, time.Now()); !upgrading && !open {
+			result := reconcile.Result{}
+			message := "No maintenance window of the cluster is open"
+			if !next.IsZero() {
+				result.RequeueAfter = time.Until(next)
+				message = fmt.Sprintf("Waiting for the maintenance window starting at %s", next.Format(time.RFC3339))
+			}
+			return result, r.setBlocked(cd, outsideMaintenanceWindowReason, message, cdLog)
+		}
+		// Syncsets are expected to apply cleanly before the cluster is upgraded, as their failures would be hidden by
+		// the failures of the upgrade.
+		if !upgrading && conditionTrue(cd, hivev1.SyncSetFailedCondition) {
+			return reconcile.Result{}, r.setBlocked(cd, syncSetsFailingReason, "Syncsets are failing to apply to the cluster", cdLog)
+		}
+		cdLog.Info("setting desired update of the cluster")
+		clusterVersion.Spec.DesiredUpdate = &openshiftapiv1.Update{
+			Image: releaseImage,
+			Force: cd.Spec.Upgrade.Force,
+		}
+		if err := remoteClient.Update(context.TODO(), clusterVersion); err != nil {
+			cdLog.WithError(err).Error("error updating remote clusterversion object")
+			return reconcile.Result{}, err
+		}
+		r.eventRecorder.Eventf(cd, corev1.EventTypeNormal, upgradeStartedReason, "Started upgrade of cluster to %s", releaseImage)
+	}
+
+	failed := condition(hivev1.UpgradeFailedCondition, corev1.ConditionFalse, upgradeNotFailingReason, "Upgrade is not failing")
+	if failing := findClusterVersionCondition(clusterVersion, clusterVersionFailingCondition); failing != nil && failing.Status == openshiftapiv1.ConditionTrue {
+		failed = condition(hivev1.UpgradeFailedCondition, corev1.ConditionTrue, upgradeFailingReason, failing.Message)
+	}
+	message := fmt.Sprintf("Cluster is upgrading to %s", releaseImage)
+	if progressing := findClusterVersionCondition(clusterVersion, openshiftapiv1.OperatorProgressing); progressing != nil && progressing.Message != "" {
+		message = progressing.Message
+	}
+	err = r.setConditions(cd, cdLog,
+		condition(hivev1.UpgradeBlockedCondition, corev1.ConditionFalse, upgradeStartedReason, "Upgrade has started"),
+		condition(hivev1.UpgradeProgressingCondition, corev1.ConditionTrue, upgradingReason, message),
+		failed,
+	)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+	return reconcile.Result{RequeueAfter: upgradeProgressCheckInterval}, nil
+}
+
+// releaseImageNotAllowedError is returned when the release image that the cluster is asked to upgrade to is not
+// allowed by the release image policy.
+type releaseImageNotAllowedError struct {
+	error
+}
+
+// getReleaseImage returns the release image that the cluster is asked to upgrade to, once it has been checked against
+// the release image policy and its signatures have been verified.
+func (r *ReconcileClusterUpgrade) getReleaseImage(cd *hivev1.ClusterDeployment) (string, error) {
+	upgrade := cd.Spec.Upgrade
+	releaseImage := upgrade.ReleaseImage
+	if releaseImage == "" {
+		if upgrade.ImageSetRef == nil || upgrade.ImageSetRef.Name == "" {
+			return "", errors.New("no release image or cluster image set specified for the upgrade")
+		}
+		imageSet := &hivev1.ClusterImageSet{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Name: upgrade.ImageSetRef.Name}, imageSet); err != nil {
+			return "", errors.Wrapf(err, "could not get cluster image set %s", upgrade.ImageSetRef.Name)
+		}
+		releaseImage = imageSet.Spec.ReleaseImage
+	}
+	if err := releaseimage.CheckPolicy(r.releaseImagePolicy, releaseImage); err != nil {
+		return "", &releaseImageNotAllowedError{err}
+	}
+	if r.verifyReleaseImageSignature != nil {
+		switch err := r.verifyReleaseImageSignature(releaseImage); err.(type) {
+		case nil:
+		case *releaseimage.SignatureNotVerifiedError:
+			return "", &releaseImageNotAllowedError{err}
+		default:
+			return "", errors.Wrap(err, "could not verify the signatures of the release image")
+		}
+	}
+	return releaseImage, nil
+}
+
+// upgradeCompleted returns whether the cluster has completed the update to the release image.
+func upgradeCompleted(clusterVersion *openshiftapiv1.ClusterVersion, releaseImage string) bool {
+	history := clusterVersion.Status.History
+	return len(history) > 0 && history[0].Image == releaseImage && history[0].State == openshiftapiv1.CompletedUpdate
+}
+
+func conditionTrue(cd *hivev1.ClusterDeployment, conditionType hivev1.ClusterDeploymentConditionType) bool {
+	cond := controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, conditionType)
+	return cond != nil && cond.Status == corev1.ConditionTrue
+}
+
+func findClusterVersionCondition(clusterVersion *openshiftapiv1.ClusterVersion, conditionType openshiftapiv1.ClusterStatusConditionType) *openshiftapiv1.ClusterOperatorStatusCondition {
+	for i, cond := range clusterVersion.Status.Conditions {
+		if cond.Type == conditionType {
+			return &clusterVersion.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+type conditionUpdate struct {
+	conditionType hivev1.ClusterDeploymentConditionType
+	status        corev1.ConditionStatus
+	reason        string
+	message       string
+}
+
+func condition(conditionType hivev1.ClusterDeploymentConditionType, status corev1.ConditionStatus, reason, message string) conditionUpdate {
+	return conditionUpdate{conditionType: conditionType, status: status, reason: reason, message: message}
+}
+
+// setBlocked sets the UpgradeBlocked condition of a cluster whose upgrade cannot be started.
+func (r *ReconcileClusterUpgrade) setBlocked(cd *hivev1.ClusterDeployment, reason, message string, cdLog log.FieldLogger) error {
+	cdLog.WithField("reason", reason).Info("upgrade is blocked")
+	return r.setConditions(cd, cdLog, condition(hivev1.UpgradeBlockedCondition, corev1.ConditionTrue, reason, message))
+}
+
+func (r *ReconcileClusterUpgrade) setConditions(cd *hivev1.ClusterDeployment, cdLog log.FieldLogger, updates ...conditionUpdate) error {
+	changed := false
+	for _, u := range updates {
+		var c bool
+		cd.Status.Conditions, c = controllerutils.SetClusterDeploymentConditionWithChangeCheck(
+			cd.Status.Conditions,
+			u.conditionType,
+			u.status,
+			u.reason,
+			u.message,
+			controllerutils.UpdateConditionIfReasonOrMessageChange,
+		)
+		changed = changed || c
+	}
+	if !changed {
+		return nil
+	}
+	if err := r.Status().Update(context.TODO(), cd); err != nil {
+		cdLog.WithError(err).Log(controllerutils.LogLevel(err), "error updating cluster deployment upgrade conditions")
+		return err
+	}
+	return nil
+}
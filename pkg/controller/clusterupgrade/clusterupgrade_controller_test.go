@@ -0,0 +1,400 @@
+package clusterupgrade
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	openshiftapiv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/hive/pkg/apis"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+	"github.com/openshift/hive/pkg/releaseimage"
+	"github.com/openshift/hive/pkg/remoteclient"
+	remoteclientmock "github.com/openshift/hive/pkg/remoteclient/mock"
+)
+
+const (
+	testName      = "test-cluster"
+	testNamespace = "test-namespace"
+	testImageSet  = "openshift-v4.5.2"
+
+	currentReleaseImage = "quay.io/openshift-release-dev/ocp-release:4.5.1-x86_64"
+	targetReleaseImage  = "quay.io/openshift-release-dev/ocp-release:4.5.2-x86_64"
+)
+
+func init() {
+	log.SetLevel(log.DebugLevel)
+}
+
+func TestReconcileClusterUpgrade(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+	openshiftapiv1.Install(scheme.Scheme)
+
+	tests := []struct {
+		name                string
+		cd                  *hivev1.ClusterDeployment
+		noImageSet          bool
+		releaseImagePolicy  *hivev1.ReleaseImagePolicy
+		verifySignature     func(string) error
+		clusterVersion      *openshiftapiv1.ClusterVersion
+		unreachable         bool
+		expectNoRemote      bool
+		expectDesiredUpdate *openshiftapiv1.Update
+		expectConditions    map[hivev1.ClusterDeploymentConditionType]string
+		expectRequeue       bool
+		expectRequeueAfter  time.Duration
+		expectEvent         string
+	}{
+		{
+			name:           "no upgrade requested",
+			cd:             testClusterDeployment(nil),
+			expectNoRemote: true,
+		},
+		{
+			name:           "upgrade no longer requested",
+			cd:             testClusterDeployment(nil, withCondition(hivev1.UpgradeProgressingCondition, upgradingReason)),
+			expectNoRemote: true,
+			expectConditions: map[hivev1.ClusterDeploymentConditionType]string{
+				hivev1.UpgradeProgressingCondition: "False/" + upgradeNotRequestedReason,
+			},
+		},
+		{
+			name: "not installed",
+			cd: testClusterDeployment(imageSetUpgrade(), func(cd *hivev1.ClusterDeployment) {
+				cd.Spec.Installed = false
+			}),
+			expectNoRemote: true,
+		},
+		{
+			name:           "cluster image set not found",
+			cd:             testClusterDeployment(imageSetUpgrade()),
+			noImageSet:     true,
+			expectNoRemote: true,
+			expectConditions: map[hivev1.ClusterDeploymentConditionType]string{
+				hivev1.UpgradeBlockedCondition: "True/" + clusterImageSetNotFoundReason,
+			},
+		},
+		{
+			name:               "release image not allowed",
+			cd:                 testClusterDeployment(imageSetUpgrade()),
+			releaseImagePolicy: &hivev1.ReleaseImagePolicy{AllowedRegistries: []string{"registry.example.com"}},
+			expectNoRemote:     true,
+			expectConditions: map[hivev1.ClusterDeploymentConditionType]string{
+				hivev1.UpgradeBlockedCondition: "True/" + releaseImageNotAllowedReason,
+			},
+		},
+		{
+			name: "release image signature not verified",
+			cd:   testClusterDeployment(imageSetUpgrade()),
+			verifySignature: func(releaseImage string) error {
+				return &releaseimage.SignatureNotVerifiedError{ReleaseImage: releaseImage}
+			},
+			expectNoRemote: true,
+			expectConditions: map[hivev1.ClusterDeploymentConditionType]string{
+				hivev1.UpgradeBlockedCondition: "True/" + releaseImageNotAllowedReason,
+			},
+			expectRequeueAfter: releaseImageSignatureRecheckInterval,
+		},
+		{
+			name:           "unreachable",
+			cd:             testClusterDeployment(imageSetUpgrade()),
+			unreachable:    true,
+			expectNoRemote: true,
+			expectConditions: map[hivev1.ClusterDeploymentConditionType]string{
+				hivev1.UpgradeBlockedCondition: "True/" + clusterUnreachableReason,
+			},
+		},
+		{
+			name:           "unreachable while upgrading",
+			cd:             testClusterDeployment(imageSetUpgrade(), withCondition(hivev1.UpgradeProgressingCondition, upgradingReason)),
+			unreachable:    true,
+			expectNoRemote: true,
+			expectConditions: map[hivev1.ClusterDeploymentConditionType]string{
+				hivev1.UpgradeProgressingCondition: "True/" + upgradingReason,
+			},
+		},
+		{
+			name: "hibernating",
+			cd: testClusterDeployment(imageSetUpgrade(), func(cd *hivev1.ClusterDeployment) {
+				cd.Spec.PowerState = hivev1.HibernatingClusterPowerState
+			}),
+			expectNoRemote: true,
+			expectConditions: map[hivev1.ClusterDeploymentConditionType]string{
+				hivev1.UpgradeBlockedCondition: "True/" + clusterHibernatingReason,
+			},
+		},
+		{
+			name:           "syncsets failing",
+			cd:             testClusterDeployment(imageSetUpgrade(), withCondition(hivev1.SyncSetFailedCondition, "SyncSetApplyFailure")),
+			clusterVersion: testClusterVersion(nil, currentReleaseImage),
+			expectConditions: map[hivev1.ClusterDeploymentConditionType]string{
+				hivev1.UpgradeBlockedCondition: "True/" + syncSetsFailingReason,
+			},
+		},
+		{
+			name: "outside maintenance window",
+			cd: testClusterDeployment(imageSetUpgrade(), func(cd *hivev1.ClusterDeployment) {
+				cd.Spec.MaintenanceWindows = []hivev1.MaintenanceWindow{{
+					StartTime: time.Now().UTC().Add(2 * time.Hour).Format("15:04"),
+					Duration:  metav1.Duration{Duration: time.Hour},
+				}}
+			}),
+			clusterVersion: testClusterVersion(nil, currentReleaseImage),
+			expectConditions: map[hivev1.ClusterDeploymentConditionType]string{
+				hivev1.UpgradeBlockedCondition: "True/" + outsideMaintenanceWindowReason,
+			},
+			expectRequeueAfter: 2 * time.Hour,
+		},
+		{
+			name: "within maintenance window",
+			cd: testClusterDeployment(imageSetUpgrade(), func(cd *hivev1.ClusterDeployment) {
+				cd.Spec.MaintenanceWindows = []hivev1.MaintenanceWindow{{
+					StartTime: time.Now().UTC().Add(-time.Hour).Format("15:04"),
+					Duration:  metav1.Duration{Duration: 2 * time.Hour},
+				}}
+			}),
+			clusterVersion:      testClusterVersion(nil, currentReleaseImage),
+			expectDesiredUpdate: &openshiftapiv1.Update{Image: targetReleaseImage},
+			expectConditions: map[hivev1.ClusterDeploymentConditionType]string{
+				hivev1.UpgradeProgressingCondition: "True/" + upgradingReason,
+			},
+			expectRequeue: true,
+			expectEvent:   upgradeStartedReason,
+		},
+		{
+			name:                "start upgrade to release image with verified signature",
+			cd:                  testClusterDeployment(imageSetUpgrade()),
+			verifySignature:     func(string) error { return nil },
+			clusterVersion:      testClusterVersion(nil, currentReleaseImage),
+			expectDesiredUpdate: &openshiftapiv1.Update{Image: targetReleaseImage},
+			expectConditions: map[hivev1.ClusterDeploymentConditionType]string{
+				hivev1.UpgradeProgressingCondition: "True/" + upgradingReason,
+			},
+			expectRequeue: true,
+			expectEvent:   upgradeStartedReason,
+		},
+		{
+			name:                "start upgrade to cluster image set",
+			cd:                  testClusterDeployment(imageSetUpgrade(), withCondition(hivev1.UpgradeBlockedCondition, clusterUnreachableReason)),
+			clusterVersion:      testClusterVersion(nil, currentReleaseImage),
+			expectDesiredUpdate: &openshiftapiv1.Update{Image: targetReleaseImage},
+			expectConditions: map[hivev1.ClusterDeploymentConditionType]string{
+				hivev1.UpgradeBlockedCondition:     "False/" + upgradeStartedReason,
+				hivev1.UpgradeProgressingCondition: "True/" + upgradingReason,
+			},
+			expectRequeue: true,
+			expectEvent:   upgradeStartedReason,
+		},
+		{
+			name: "start forced upgrade to release image",
+			cd: testClusterDeployment(&hivev1.ClusterUpgrade{
+				ImageSetRef:  &hivev1.ClusterImageSetReference{Name: "does-not-exist"},
+				ReleaseImage: targetReleaseImage,
+				Force:        true,
+			}),
+			noImageSet:          true,
+			clusterVersion:      testClusterVersion(nil, currentReleaseImage),
+			expectDesiredUpdate: &openshiftapiv1.Update{Image: targetReleaseImage, Force: true},
+			expectConditions: map[hivev1.ClusterDeploymentConditionType]string{
+				hivev1.UpgradeProgressingCondition: "True/" + upgradingReason,
+			},
+			expectRequeue: true,
+			expectEvent:   upgradeStartedReason,
+		},
+		{
+			name: "upgrading despite syncsets failing",
+			cd: testClusterDeployment(imageSetUpgrade(),
+				withCondition(hivev1.UpgradeProgressingCondition, upgradingReason),
+				withCondition(hivev1.SyncSetFailedCondition, "SyncSetApplyFailure"),
+			),
+			clusterVersion:      testClusterVersion(&openshiftapiv1.Update{Image: targetReleaseImage}, currentReleaseImage),
+			expectDesiredUpdate: &openshiftapiv1.Update{Image: targetReleaseImage},
+			expectConditions: map[hivev1.ClusterDeploymentConditionType]string{
+				hivev1.UpgradeProgressingCondition: "True/" + upgradingReason,
+			},
+			expectRequeue: true,
+		},
+		{
+			name: "upgrade failing",
+			cd:   testClusterDeployment(imageSetUpgrade(), withCondition(hivev1.UpgradeProgressingCondition, upgradingReason)),
+			clusterVersion: func() *openshiftapiv1.ClusterVersion {
+				cv := testClusterVersion(&openshiftapiv1.Update{Image: targetReleaseImage}, currentReleaseImage)
+				cv.Status.Conditions = append(cv.Status.Conditions, openshiftapiv1.ClusterOperatorStatusCondition{
+					Type:    clusterVersionFailingCondition,
+					Status:  openshiftapiv1.ConditionTrue,
+					Message: "Cluster operator etcd is degraded",
+				})
+				return cv
+			}(),
+			expectDesiredUpdate: &openshiftapiv1.Update{Image: targetReleaseImage},
+			expectConditions: map[hivev1.ClusterDeploymentConditionType]string{
+				hivev1.UpgradeProgressingCondition: "True/" + upgradingReason,
+				hivev1.UpgradeFailedCondition:      "True/" + upgradeFailingReason,
+			},
+			expectRequeue: true,
+		},
+		{
+			name: "upgrade completed",
+			cd: testClusterDeployment(imageSetUpgrade(),
+				withCondition(hivev1.UpgradeProgressingCondition, upgradingReason),
+				withCondition(hivev1.UpgradeFailedCondition, upgradeFailingReason),
+			),
+			clusterVersion:      testClusterVersion(&openshiftapiv1.Update{Image: targetReleaseImage}, targetReleaseImage),
+			expectDesiredUpdate: &openshiftapiv1.Update{Image: targetReleaseImage},
+			expectConditions: map[hivev1.ClusterDeploymentConditionType]string{
+				hivev1.UpgradeProgressingCondition: "False/" + upgradeCompletedReason,
+				hivev1.UpgradeFailedCondition:      "False/" + upgradeCompletedReason,
+			},
+			expectEvent: upgradeCompletedReason,
+		},
+		{
+			name:           "already running release",
+			cd:             testClusterDeployment(imageSetUpgrade()),
+			clusterVersion: testClusterVersion(nil, targetReleaseImage),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			existing := []runtime.Object{test.cd}
+			if !test.noImageSet {
+				existing = append(existing, &hivev1.ClusterImageSet{
+					ObjectMeta: metav1.ObjectMeta{Name: testImageSet},
+					Spec:       hivev1.ClusterImageSetSpec{ReleaseImage: targetReleaseImage},
+				})
+			}
+			fakeClient := fake.NewFakeClient(existing...)
+
+			var remoteClient = fake.NewFakeClient()
+			if test.clusterVersion != nil {
+				remoteClient = fake.NewFakeClient(test.clusterVersion)
+			}
+			mockRemoteClientBuilder := remoteclientmock.NewMockBuilder(mockCtrl)
+			mockRemoteClientBuilder.EXPECT().Unreachable().Return(test.unreachable).AnyTimes()
+			if !test.expectNoRemote {
+				mockRemoteClientBuilder.EXPECT().Build().Return(remoteClient, nil)
+			}
+			fakeRecorder := record.NewFakeRecorder(10)
+
+			r := &ReconcileClusterUpgrade{
+				Client:        fakeClient,
+				scheme:        scheme.Scheme,
+				logger:        log.WithField("controller", controllerName),
+				eventRecorder: fakeRecorder,
+				remoteClientBuilder: func(*hivev1.ClusterDeployment) remoteclient.Builder {
+					return mockRemoteClientBuilder
+				},
+				releaseImagePolicy:          test.releaseImagePolicy,
+				verifyReleaseImageSignature: test.verifySignature,
+			}
+
+			key := types.NamespacedName{Name: testName, Namespace: testNamespace}
+			result, err := r.Reconcile(reconcile.Request{NamespacedName: key})
+			require.NoError(t, err, "unexpected error from reconcile")
+			switch {
+			case test.expectRequeue:
+				assert.Equal(t, upgradeProgressCheckInterval, result.RequeueAfter, "expected requeue to check upgrade progress")
+			case test.expectRequeueAfter != 0:
+				assert.InDelta(t, test.expectRequeueAfter.Seconds(), result.RequeueAfter.Seconds(), 60, "unexpected requeue")
+			default:
+				assert.Zero(t, result.RequeueAfter, "unexpected requeue")
+			}
+
+			cd := &hivev1.ClusterDeployment{}
+			require.NoError(t, fakeClient.Get(context.TODO(), key, cd))
+			for conditionType, expected := range test.expectConditions {
+				cond := controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, conditionType)
+				if assert.NotNil(t, cond, "expected %s condition", conditionType) {
+					assert.Equal(t, expected, string(cond.Status)+"/"+cond.Reason, "unexpected %s condition", conditionType)
+				}
+			}
+
+			if test.clusterVersion != nil {
+				cv := &openshiftapiv1.ClusterVersion{}
+				require.NoError(t, remoteClient.Get(context.TODO(), types.NamespacedName{Name: clusterVersionObjectName}, cv))
+				assert.Equal(t, test.expectDesiredUpdate, cv.Spec.DesiredUpdate, "unexpected desired update")
+			}
+
+			if test.expectEvent == "" {
+				assert.Empty(t, fakeRecorder.Events, "unexpected event")
+			} else if assert.Len(t, fakeRecorder.Events, 1, "expected event") {
+				assert.Contains(t, <-fakeRecorder.Events, test.expectEvent, "unexpected event")
+			}
+		})
+	}
+}
+
+func testClusterDeployment(upgrade *hivev1.ClusterUpgrade, modifiers ...func(*hivev1.ClusterDeployment)) *hivev1.ClusterDeployment {
+	cd := &hivev1.ClusterDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testName,
+			Namespace: testNamespace,
+		},
+		Spec: hivev1.ClusterDeploymentSpec{
+			ClusterName: testName,
+			BaseDomain:  "example.com",
+			Installed:   true,
+			ClusterMetadata: &hivev1.ClusterMetadata{
+				AdminKubeconfigSecretRef: corev1.LocalObjectReference{Name: "test-cluster-admin-kubeconfig"},
+			},
+			Upgrade: upgrade,
+		},
+	}
+	for _, modify := range modifiers {
+		modify(cd)
+	}
+	return cd
+}
+
+func imageSetUpgrade() *hivev1.ClusterUpgrade {
+	return &hivev1.ClusterUpgrade{ImageSetRef: &hivev1.ClusterImageSetReference{Name: testImageSet}}
+}
+
+func withCondition(conditionType hivev1.ClusterDeploymentConditionType, reason string) func(*hivev1.ClusterDeployment) {
+	return func(cd *hivev1.ClusterDeployment) {
+		cd.Status.Conditions = append(cd.Status.Conditions, hivev1.ClusterDeploymentCondition{
+			Type:   conditionType,
+			Status: corev1.ConditionTrue,
+			Reason: reason,
+		})
+	}
+}
+
+func testClusterVersion(desiredUpdate *openshiftapiv1.Update, lastImage string) *openshiftapiv1.ClusterVersion {
+	state := openshiftapiv1.CompletedUpdate
+	if desiredUpdate != nil && desiredUpdate.Image != lastImage {
+		state = openshiftapiv1.PartialUpdate
+	}
+	now := metav1.NewTime(time.Now())
+	return &openshiftapiv1.ClusterVersion{
+		ObjectMeta: metav1.ObjectMeta{Name: clusterVersionObjectName},
+		Spec: openshiftapiv1.ClusterVersionSpec{
+			DesiredUpdate: desiredUpdate,
+		},
+		Status: openshiftapiv1.ClusterVersionStatus{
+			History: []openshiftapiv1.UpdateHistory{{
+				State:       state,
+				Image:       lastImage,
+				StartedTime: now,
+			}},
+		},
+	}
+}
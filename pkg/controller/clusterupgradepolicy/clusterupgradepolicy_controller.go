@@ -0,0 +1,304 @@
+// Package clusterupgradepolicy provides a controller which requests the upgrade of the installed clusters selected by
+// a ClusterUpgradePolicy to the release of the policy. The upgrades themselves are carried out by the clusterupgrade
+// controller.
+package clusterupgradepolicy
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	openshiftapiv1 "github.com/openshift/api/config/v1"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+	hivemetrics "github.com/openshift/hive/pkg/controller/metrics"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+)
+
+const (
+	controllerName = "clusterUpgradePolicy"
+)
+
+// Add creates a new ClusterUpgradePolicy Controller and adds it to the Manager with default RBAC. The Manager will set fields on the
+// Controller and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	return AddToManager(mgr, NewReconciler(mgr))
+}
+
+// NewReconciler returns a new reconcile.Reconciler
+func NewReconciler(mgr manager.Manager) reconcile.Reconciler {
+	return &ReconcileClusterUpgradePolicy{
+		Client: controllerutils.NewClientWithMetricsOrDie(mgr, controllerName),
+		scheme: mgr.GetScheme(),
+		logger: log.WithField("controller", controllerName),
+	}
+}
+
+// AddToManager adds a new Controller to mgr with r as the reconcile.Reconciler
+func AddToManager(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New("clusterupgradepolicy-controller", mgr, controller.Options{Reconciler: controllerutils.NewShardedReconciler(r), MaxConcurrentReconciles: controllerutils.GetConcurrentReconciles(controllerName)})
+	if err != nil {
+		return err
+	}
+
+	if err := controllerutils.SetQueueRateLimiter(c, controllerName); err != nil {
+		return err
+	}
+
+	// Watch for changes to ClusterUpgradePolicy
+	err = c.Watch(&source.Kind{Type: &hivev1.ClusterUpgradePolicy{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	reconciler := r.(*ReconcileClusterUpgradePolicy)
+
+	// Watch for changes to ClusterDeployment, which may change the clusters selected by a policy or their upgrade
+	err = c.Watch(&source.Kind{Type: &hivev1.ClusterDeployment{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(reconciler.clusterDeploymentWatchHandler),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to ClusterState, which may pause or resume the rollout of a wave
+	err = c.Watch(&source.Kind{Type: &hivev1.ClusterState{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(reconciler.clusterDeploymentWatchHandler),
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// clusterDeploymentWatchHandler requests the reconcile of all ClusterUpgradePolicies, as a change to the labels of a
+// ClusterDeployment may move it from one policy to another. It is also used for ClusterStates, which share the name
+// of their ClusterDeployment.
+func (r *ReconcileClusterUpgradePolicy) clusterDeploymentWatchHandler(a handler.MapObject) []reconcile.Request {
+	policies := &hivev1.ClusterUpgradePolicyList{}
+	if err := r.List(context.TODO(), policies); err != nil {
+		r.logger.WithError(err).Error("error listing cluster upgrade policies")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, policy := range policies.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Name: policy.Name}})
+	}
+	return requests
+}
+
+var _ reconcile.Reconciler = &ReconcileClusterUpgradePolicy{}
+
+// ReconcileClusterUpgradePolicy reconciles a ClusterUpgradePolicy object
+type ReconcileClusterUpgradePolicy struct {
+	client.Client
+	scheme *runtime.Scheme
+	logger log.FieldLogger
+}
+
+// Reconcile rolls the release of a ClusterUpgradePolicy out to the installed clusters it selects, one wave after the
+// other, and reports the progress of each wave.
+func (r *ReconcileClusterUpgradePolicy) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	start := time.Now()
+	policyLog := r.logger.WithField("clusterUpgradePolicy", request.Name)
+
+	policyLog.Info("reconciling cluster upgrade policy")
+	defer func() {
+		dur := time.Since(start)
+		hivemetrics.MetricControllerReconcileTime.WithLabelValues(controllerName).Observe(dur.Seconds())
+		policyLog.WithField("elapsed", dur).Info("reconcile complete")
+	}()
+
+	policy := &hivev1.ClusterUpgradePolicy{}
+	err := r.Get(context.TODO(), request.NamespacedName, policy)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		policyLog.WithError(err).Error("error looking up cluster upgrade policy")
+		return reconcile.Result{}, err
+	}
+
+	if policy.DeletionTimestamp != nil {
+		return reconcile.Result{}, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(&policy.Spec.ClusterDeploymentSelector)
+	if err != nil {
+		policyLog.WithError(err).Error("invalid cluster deployment selector")
+		return reconcile.Result{}, nil
+	}
+	rollouts, err := newWaveRollouts(policy)
+	if err != nil {
+		policyLog.WithError(err).Error("invalid waves")
+		return reconcile.Result{}, nil
+	}
+
+	policies := &hivev1.ClusterUpgradePolicyList{}
+	if err := r.List(context.TODO(), policies); err != nil {
+		policyLog.WithError(err).Error("error listing cluster upgrade policies")
+		return reconcile.Result{}, err
+	}
+	cdList := &hivev1.ClusterDeploymentList{}
+	if err := r.List(context.TODO(), cdList); err != nil {
+		policyLog.WithError(err).Error("error listing cluster deployments")
+		return reconcile.Result{}, err
+	}
+	clusterStates := &hivev1.ClusterStateList{}
+	if err := r.List(context.TODO(), clusterStates); err != nil {
+		policyLog.WithError(err).Error("error listing cluster states")
+		return reconcile.Result{}, err
+	}
+	clusterStatesByName := map[types.NamespacedName]*hivev1.ClusterState{}
+	for i, clusterState := range clusterStates.Items {
+		clusterStatesByName[types.NamespacedName{Namespace: clusterState.Namespace, Name: clusterState.Name}] = &clusterStates.Items[i]
+	}
+
+	// The release image is only used to find the upgraded clusters, the upgrade waits for a missing
+	// ClusterImageSet in the clusterupgrade controller.
+	releaseImage := ""
+	imageSet := &hivev1.ClusterImageSet{}
+	switch err := r.Get(context.TODO(), types.NamespacedName{Name: policy.Spec.ImageSetRef.Name}, imageSet); {
+	case err == nil:
+		releaseImage = imageSet.Spec.ReleaseImage
+	case apierrors.IsNotFound(err):
+		policyLog.WithField("clusterImageSet", policy.Spec.ImageSetRef.Name).Warn("cluster image set not found")
+	default:
+		policyLog.WithError(err).Error("error looking up cluster image set")
+		return reconcile.Result{}, err
+	}
+
+	status := hivev1.ClusterUpgradePolicyStatus{}
+	for i := range cdList.Items {
+		cd := &cdList.Items[i]
+		cdLog := policyLog.WithFields(log.Fields{
+			"clusterDeployment": cd.Name,
+			"namespace":         cd.Namespace,
+		})
+		var wave *waveRollout
+		if cd.DeletionTimestamp == nil && cd.Spec.Installed && selector.Matches(labels.Set(cd.Labels)) {
+			wave = waveFor(rollouts, cd)
+		}
+		managedBy := cd.Annotations[constants.ClusterUpgradePolicyAnnotation]
+
+		if wave == nil {
+			if managedBy == policy.Name && cd.DeletionTimestamp == nil {
+				// The cluster is released so that another policy can select it. An upgrade that has already started
+				// on the cluster carries on, but is no longer tracked.
+				cdLog.Info("cluster deployment is no longer selected by the policy")
+				delete(cd.Annotations, constants.ClusterUpgradePolicyAnnotation)
+				cd.Spec.Upgrade = nil
+				if err := r.Update(context.TODO(), cd); err != nil {
+					cdLog.WithError(err).Log(controllerutils.LogLevel(err), "error releasing cluster deployment from policy")
+					return reconcile.Result{}, err
+				}
+			}
+			continue
+		}
+		if managedBy == "" && cd.Spec.Upgrade != nil {
+			cdLog.Debug("skipping cluster deployment with an upgrade requested outside of a policy")
+			continue
+		}
+		if managedBy != "" && managedBy != policy.Name && selectedByPolicy(policies.Items, managedBy, cd) {
+			cdLog.WithField("managedBy", managedBy).Debug("skipping cluster deployment managed by another policy")
+			continue
+		}
+
+		upgraded := upgradeCompleted(cd, releaseImage)
+		status.ClusterDeployments++
+		if upgraded {
+			status.UpgradedClusterDeployments++
+		}
+		requested := managedBy == policy.Name && upgradeRequested(cd, policy.Spec.ImageSetRef.Name)
+		wave.add(cd, requested, upgraded, clusterStatesByName[types.NamespacedName{Namespace: cd.Namespace, Name: cd.Name}])
+	}
+
+	var toStart []*hivev1.ClusterDeployment
+	var nextWindow time.Time
+	status.Waves, status.CurrentWave, toStart, nextWindow = rollout(rollouts, time.Now())
+
+	for _, cd := range toStart {
+		cdLog := policyLog.WithFields(log.Fields{
+			"clusterDeployment": cd.Name,
+			"namespace":         cd.Namespace,
+		})
+		cdLog.WithFields(log.Fields{
+			"clusterImageSet": policy.Spec.ImageSetRef.Name,
+			"wave":            status.CurrentWave,
+		}).Info("requesting upgrade of cluster deployment")
+		if cd.Annotations == nil {
+			cd.Annotations = map[string]string{}
+		}
+		cd.Annotations[constants.ClusterUpgradePolicyAnnotation] = policy.Name
+		if cd.Spec.Upgrade == nil {
+			cd.Spec.Upgrade = &hivev1.ClusterUpgrade{}
+		}
+		cd.Spec.Upgrade.ImageSetRef = &hivev1.ClusterImageSetReference{Name: policy.Spec.ImageSetRef.Name}
+		cd.Spec.Upgrade.ReleaseImage = ""
+		if err := r.Update(context.TODO(), cd); err != nil {
+			cdLog.WithError(err).Log(controllerutils.LogLevel(err), "error requesting upgrade of cluster deployment")
+			return reconcile.Result{}, err
+		}
+	}
+
+	result := reconcile.Result{}
+	if !nextWindow.IsZero() {
+		result.RequeueAfter = time.Until(nextWindow)
+	}
+	if reflect.DeepEqual(status, policy.Status) {
+		return result, nil
+	}
+	policy.Status = status
+	if err := r.Status().Update(context.TODO(), policy); err != nil {
+		policyLog.WithError(err).Log(controllerutils.LogLevel(err), "error updating cluster upgrade policy status")
+		return reconcile.Result{}, err
+	}
+	return result, nil
+}
+
+// selectedByPolicy returns whether the policy with the given name exists and selects the ClusterDeployment in one of
+// its waves.
+func selectedByPolicy(policies []hivev1.ClusterUpgradePolicy, name string, cd *hivev1.ClusterDeployment) bool {
+	for i := range policies {
+		if policies[i].Name != name || policies[i].DeletionTimestamp != nil {
+			continue
+		}
+		selector, err := metav1.LabelSelectorAsSelector(&policies[i].Spec.ClusterDeploymentSelector)
+		if err != nil || !selector.Matches(labels.Set(cd.Labels)) {
+			return false
+		}
+		rollouts, err := newWaveRollouts(&policies[i])
+		return err == nil && waveFor(rollouts, cd) != nil
+	}
+	return false
+}
+
+// upgradeRequested returns whether the ClusterDeployment is asked to upgrade to the release of the ClusterImageSet.
+func upgradeRequested(cd *hivev1.ClusterDeployment, imageSetName string) bool {
+	upgrade := cd.Spec.Upgrade
+	return upgrade != nil && upgrade.ReleaseImage == "" && upgrade.ImageSetRef != nil && upgrade.ImageSetRef.Name == imageSetName
+}
+
+// upgradeCompleted returns whether the cluster has completed the update to the release image, according to the
+// ClusterVersion status last seen on the cluster.
+func upgradeCompleted(cd *hivev1.ClusterDeployment, releaseImage string) bool {
+	history := cd.Status.ClusterVersionStatus.History
+	return releaseImage != "" && len(history) > 0 && history[0].Image == releaseImage && history[0].State == openshiftapiv1.CompletedUpdate
+}
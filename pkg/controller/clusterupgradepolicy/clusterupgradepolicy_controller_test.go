@@ -0,0 +1,457 @@
+package clusterupgradepolicy
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	openshiftapiv1 "github.com/openshift/api/config/v1"
+	"github.com/openshift/hive/pkg/apis"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+)
+
+const (
+	testPolicy      = "production"
+	testOtherPolicy = "canary"
+	testNamespace   = "test-namespace"
+	testImageSet    = "openshift-v4.5.2"
+
+	targetReleaseImage = "quay.io/openshift-release-dev/ocp-release:4.5.2-x86_64"
+)
+
+func init() {
+	log.SetLevel(log.DebugLevel)
+}
+
+func TestReconcileClusterUpgradePolicy(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	tests := []struct {
+		name     string
+		waves    []hivev1.ClusterUpgradeWave
+		existing []runtime.Object
+		// expectUpgrades maps the names of the expected ClusterDeployments to the ClusterImageSet they are asked to
+		// upgrade to, and the policy they are managed by.
+		expectUpgrades map[string]string
+		expectStatus   hivev1.ClusterUpgradePolicyStatus
+		expectRequeue  bool
+	}{
+		{
+			name: "request upgrades of selected clusters",
+			existing: []runtime.Object{
+				testClusterDeployment("prod-1", "production"),
+				testClusterDeployment("prod-2", "production"),
+				testClusterDeployment("dev-1", "development"),
+				testClusterDeployment("prod-uninstalled", "production", func(cd *hivev1.ClusterDeployment) {
+					cd.Spec.Installed = false
+				}),
+			},
+			expectUpgrades: map[string]string{
+				"prod-1":           testImageSet + "/" + testPolicy,
+				"prod-2":           testImageSet + "/" + testPolicy,
+				"dev-1":            "",
+				"prod-uninstalled": "",
+			},
+			expectStatus: testStatus(2, 0, "all", waveStatus("all", hivev1.ClusterUpgradeWaveProgressing, 2, 2, 0, 0)),
+		},
+		{
+			name: "count upgraded clusters",
+			existing: []runtime.Object{
+				testClusterDeployment("prod-1", "production", managedBy(testPolicy), upgradedTo(targetReleaseImage)),
+				testClusterDeployment("prod-2", "production", managedBy(testPolicy), upgradedTo("quay.io/openshift-release-dev/ocp-release:4.5.1-x86_64")),
+			},
+			expectUpgrades: map[string]string{
+				"prod-1": testImageSet + "/" + testPolicy,
+				"prod-2": testImageSet + "/" + testPolicy,
+			},
+			expectStatus: testStatus(2, 1, "all", waveStatus("all", hivev1.ClusterUpgradeWaveProgressing, 2, 1, 1, 0)),
+		},
+		{
+			name: "update release of managed cluster",
+			existing: []runtime.Object{
+				testClusterDeployment("prod-1", "production", managedBy(testPolicy), func(cd *hivev1.ClusterDeployment) {
+					cd.Spec.Upgrade.ImageSetRef.Name = "openshift-v4.5.1"
+				}),
+			},
+			expectUpgrades: map[string]string{
+				"prod-1": testImageSet + "/" + testPolicy,
+			},
+			expectStatus: testStatus(1, 0, "all", waveStatus("all", hivev1.ClusterUpgradeWaveProgressing, 1, 1, 0, 0)),
+		},
+		{
+			name: "skip upgrade requested outside of a policy",
+			existing: []runtime.Object{
+				testClusterDeployment("prod-1", "production", func(cd *hivev1.ClusterDeployment) {
+					cd.Spec.Upgrade = &hivev1.ClusterUpgrade{ReleaseImage: targetReleaseImage}
+				}),
+			},
+			expectUpgrades: map[string]string{
+				"prod-1": "/",
+			},
+			expectStatus: testStatus(0, 0, "", waveStatus("all", hivev1.ClusterUpgradeWaveCompleted, 0, 0, 0, 0)),
+		},
+		{
+			name: "skip cluster managed by another policy",
+			existing: []runtime.Object{
+				testOtherClusterUpgradePolicy("production"),
+				testClusterDeployment("prod-1", "production", managedBy(testOtherPolicy)),
+			},
+			expectUpgrades: map[string]string{
+				"prod-1": "openshift-v4.6.0/" + testOtherPolicy,
+			},
+			expectStatus: testStatus(0, 0, "", waveStatus("all", hivev1.ClusterUpgradeWaveCompleted, 0, 0, 0, 0)),
+		},
+		{
+			name: "take over cluster no longer selected by another policy",
+			existing: []runtime.Object{
+				testOtherClusterUpgradePolicy("canary"),
+				testClusterDeployment("prod-1", "production", managedBy(testOtherPolicy)),
+			},
+			expectUpgrades: map[string]string{
+				"prod-1": testImageSet + "/" + testPolicy,
+			},
+			expectStatus: testStatus(1, 0, "all", waveStatus("all", hivev1.ClusterUpgradeWaveProgressing, 1, 1, 0, 0)),
+		},
+		{
+			name: "release cluster no longer selected",
+			existing: []runtime.Object{
+				testClusterDeployment("dev-1", "development", managedBy(testPolicy)),
+			},
+			expectUpgrades: map[string]string{
+				"dev-1": "",
+			},
+			expectStatus: testStatus(0, 0, "", waveStatus("all", hivev1.ClusterUpgradeWaveCompleted, 0, 0, 0, 0)),
+		},
+		{
+			name:  "start first wave within max parallel",
+			waves: testWaves(1, 0),
+			existing: []runtime.Object{
+				testClusterDeployment("canary-1", "production", canary),
+				testClusterDeployment("canary-2", "production", canary),
+				testClusterDeployment("prod-1", "production"),
+			},
+			expectUpgrades: map[string]string{
+				"canary-1": testImageSet + "/" + testPolicy,
+				"canary-2": "",
+				"prod-1":   "",
+			},
+			expectStatus: testStatus(3, 0, "canary",
+				waveStatus("canary", hivev1.ClusterUpgradeWaveProgressing, 2, 1, 0, 0),
+				waveStatus("rest", hivev1.ClusterUpgradeWavePending, 1, 0, 0, 0),
+			),
+		},
+		{
+			name:  "start next wave once wave completed",
+			waves: testWaves(1, 0),
+			existing: []runtime.Object{
+				testClusterDeployment("canary-1", "production", canary, managedBy(testPolicy), upgradedTo(targetReleaseImage)),
+				testClusterDeployment("canary-2", "production", canary, upgradedTo(targetReleaseImage)),
+				testClusterDeployment("prod-1", "production"),
+				testClusterDeployment("prod-2", "production"),
+			},
+			expectUpgrades: map[string]string{
+				"canary-1": testImageSet + "/" + testPolicy,
+				"canary-2": "",
+				"prod-1":   testImageSet + "/" + testPolicy,
+				"prod-2":   testImageSet + "/" + testPolicy,
+			},
+			expectStatus: testStatus(4, 2, "rest",
+				waveStatus("canary", hivev1.ClusterUpgradeWaveCompleted, 2, 0, 2, 0),
+				waveStatus("rest", hivev1.ClusterUpgradeWaveProgressing, 2, 2, 0, 0),
+			),
+		},
+		{
+			name:  "stop rollout after too many failures",
+			waves: testWaves(1, 0),
+			existing: []runtime.Object{
+				testClusterDeployment("canary-1", "production", canary, managedBy(testPolicy), upgradeFailed),
+				testClusterDeployment("canary-2", "production", canary),
+				testClusterDeployment("prod-1", "production"),
+			},
+			expectUpgrades: map[string]string{
+				"canary-2": "",
+				"prod-1":   "",
+			},
+			expectStatus: testStatus(3, 0, "canary",
+				withMessage(waveStatus("canary", hivev1.ClusterUpgradeWaveFailed, 2, 0, 0, 1), "1 cluster upgrades failed, more than the 0 allowed"),
+				waveStatus("rest", hivev1.ClusterUpgradeWavePending, 1, 0, 0, 0),
+			),
+		},
+		{
+			name:  "continue rollout within max failures",
+			waves: testWaves(1, 1),
+			existing: []runtime.Object{
+				testClusterDeployment("canary-1", "production", canary, managedBy(testPolicy), upgradeFailed),
+				testClusterDeployment("canary-2", "production", canary),
+				testClusterDeployment("prod-1", "production"),
+			},
+			expectUpgrades: map[string]string{
+				"canary-2": testImageSet + "/" + testPolicy,
+				"prod-1":   "",
+			},
+			expectStatus: testStatus(3, 0, "canary",
+				waveStatus("canary", hivev1.ClusterUpgradeWaveProgressing, 2, 1, 0, 1),
+				waveStatus("rest", hivev1.ClusterUpgradeWavePending, 1, 0, 0, 0),
+			),
+		},
+		{
+			name:  "complete wave with failures within max failures",
+			waves: testWaves(1, 1),
+			existing: []runtime.Object{
+				testClusterDeployment("canary-1", "production", canary, managedBy(testPolicy), upgradeFailed),
+				testClusterDeployment("canary-2", "production", canary, managedBy(testPolicy), upgradedTo(targetReleaseImage)),
+				testClusterDeployment("prod-1", "production"),
+			},
+			expectUpgrades: map[string]string{
+				"prod-1": testImageSet + "/" + testPolicy,
+			},
+			expectStatus: testStatus(3, 1, "rest",
+				waveStatus("canary", hivev1.ClusterUpgradeWaveCompleted, 2, 0, 1, 1),
+				waveStatus("rest", hivev1.ClusterUpgradeWaveProgressing, 1, 1, 0, 0),
+			),
+		},
+		{
+			name:  "pause wave with degraded operators",
+			waves: testWaves(0, 0),
+			existing: []runtime.Object{
+				testClusterDeployment("canary-1", "production", canary, managedBy(testPolicy), upgradedTo(targetReleaseImage)),
+				testClusterDeployment("canary-2", "production", canary),
+				testClusterState("canary-1", "etcd"),
+				testClusterState("canary-2", "ingress"),
+			},
+			expectUpgrades: map[string]string{
+				"canary-2": "",
+			},
+			expectStatus: testStatus(2, 1, "canary",
+				withMessage(waveStatus("canary", hivev1.ClusterUpgradeWavePaused, 2, 0, 1, 0), "Cluster operators are degraded on test-namespace/canary-1 (etcd)"),
+				waveStatus("rest", hivev1.ClusterUpgradeWavePending, 0, 0, 0, 0),
+			),
+		},
+		{
+			name:  "wait for maintenance window",
+			waves: testWaves(0, 0),
+			existing: []runtime.Object{
+				testClusterDeployment("canary-1", "production", canary, func(cd *hivev1.ClusterDeployment) {
+					cd.Spec.MaintenanceWindows = []hivev1.MaintenanceWindow{{
+						StartTime: time.Now().UTC().Add(2 * time.Hour).Format("15:04"),
+						Duration:  metav1.Duration{Duration: time.Hour},
+					}}
+				}),
+				testClusterDeployment("canary-2", "production", canary),
+			},
+			expectUpgrades: map[string]string{
+				"canary-1": "",
+				"canary-2": testImageSet + "/" + testPolicy,
+			},
+			expectStatus: testStatus(2, 0, "canary",
+				withMessage(waveStatus("canary", hivev1.ClusterUpgradeWaveProgressing, 2, 1, 0, 0), "1 clusters are waiting for their maintenance window"),
+				waveStatus("rest", hivev1.ClusterUpgradeWavePending, 0, 0, 0, 0),
+			),
+			expectRequeue: true,
+		},
+		{
+			name: "release cluster selected by no wave",
+			waves: []hivev1.ClusterUpgradeWave{{
+				Name:                      "canary",
+				ClusterDeploymentSelector: metav1.LabelSelector{MatchLabels: map[string]string{"tier": "canary"}},
+			}},
+			existing: []runtime.Object{
+				testClusterDeployment("prod-1", "production", managedBy(testPolicy)),
+			},
+			expectUpgrades: map[string]string{
+				"prod-1": "",
+			},
+			expectStatus: testStatus(0, 0, "", waveStatus("canary", hivev1.ClusterUpgradeWaveCompleted, 0, 0, 0, 0)),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			policy := testClusterUpgradePolicy()
+			policy.Spec.Waves = test.waves
+			existing := append(test.existing,
+				policy,
+				&hivev1.ClusterImageSet{
+					ObjectMeta: metav1.ObjectMeta{Name: testImageSet},
+					Spec:       hivev1.ClusterImageSetSpec{ReleaseImage: targetReleaseImage},
+				},
+			)
+			fakeClient := fake.NewFakeClient(existing...)
+			r := &ReconcileClusterUpgradePolicy{
+				Client: fakeClient,
+				scheme: scheme.Scheme,
+				logger: log.WithField("controller", controllerName),
+			}
+
+			result, err := r.Reconcile(reconcile.Request{NamespacedName: types.NamespacedName{Name: testPolicy}})
+			require.NoError(t, err, "unexpected error from reconcile")
+			if test.expectRequeue {
+				assert.InDelta(t, (2 * time.Hour).Seconds(), result.RequeueAfter.Seconds(), 60, "expected requeue at next maintenance window")
+			} else {
+				assert.Zero(t, result.RequeueAfter, "unexpected requeue")
+			}
+
+			for name, expected := range test.expectUpgrades {
+				cd := &hivev1.ClusterDeployment{}
+				require.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: name}, cd))
+				actual := ""
+				if upgrade := cd.Spec.Upgrade; upgrade != nil {
+					if upgrade.ImageSetRef != nil {
+						actual = upgrade.ImageSetRef.Name
+					}
+					actual += "/" + cd.Annotations[constants.ClusterUpgradePolicyAnnotation]
+				}
+				assert.Equal(t, expected, actual, "unexpected upgrade of cluster deployment %s", name)
+			}
+
+			policy = &hivev1.ClusterUpgradePolicy{}
+			require.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Name: testPolicy}, policy))
+			assert.Equal(t, test.expectStatus, policy.Status, "unexpected policy status")
+		})
+	}
+}
+
+func testClusterUpgradePolicy() *hivev1.ClusterUpgradePolicy {
+	return &hivev1.ClusterUpgradePolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: testPolicy},
+		Spec: hivev1.ClusterUpgradePolicySpec{
+			ClusterDeploymentSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{"environment": "production"},
+			},
+			ImageSetRef: hivev1.ClusterImageSetReference{Name: testImageSet},
+		},
+	}
+}
+
+func testOtherClusterUpgradePolicy(environment string) *hivev1.ClusterUpgradePolicy {
+	return &hivev1.ClusterUpgradePolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: testOtherPolicy},
+		Spec: hivev1.ClusterUpgradePolicySpec{
+			ClusterDeploymentSelector: metav1.LabelSelector{
+				MatchLabels: map[string]string{"environment": environment},
+			},
+			ImageSetRef: hivev1.ClusterImageSetReference{Name: "openshift-v4.6.0"},
+		},
+	}
+}
+
+func testClusterDeployment(name, environment string, modifiers ...func(*hivev1.ClusterDeployment)) *hivev1.ClusterDeployment {
+	cd := &hivev1.ClusterDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+			Labels:    map[string]string{"environment": environment},
+		},
+		Spec: hivev1.ClusterDeploymentSpec{
+			ClusterName: name,
+			BaseDomain:  "example.com",
+			Installed:   true,
+		},
+	}
+	for _, modify := range modifiers {
+		modify(cd)
+	}
+	return cd
+}
+
+// managedBy marks the ClusterDeployment as managed by the policy, upgrading to the release of the policy.
+func managedBy(policy string) func(*hivev1.ClusterDeployment) {
+	return func(cd *hivev1.ClusterDeployment) {
+		cd.Annotations = map[string]string{constants.ClusterUpgradePolicyAnnotation: policy}
+		imageSet := testImageSet
+		if policy == testOtherPolicy {
+			imageSet = "openshift-v4.6.0"
+		}
+		cd.Spec.Upgrade = &hivev1.ClusterUpgrade{ImageSetRef: &hivev1.ClusterImageSetReference{Name: imageSet}}
+	}
+}
+
+func canary(cd *hivev1.ClusterDeployment) {
+	cd.Labels["tier"] = "canary"
+}
+
+func upgradeFailed(cd *hivev1.ClusterDeployment) {
+	cd.Status.Conditions = append(cd.Status.Conditions, hivev1.ClusterDeploymentCondition{
+		Type:   hivev1.UpgradeFailedCondition,
+		Status: corev1.ConditionTrue,
+		Reason: "UpgradeFailing",
+	})
+}
+
+// testWaves returns a canary wave with the given limits, followed by a wave with the rest of the clusters.
+func testWaves(maxParallel, maxFailures int32) []hivev1.ClusterUpgradeWave {
+	return []hivev1.ClusterUpgradeWave{
+		{
+			Name:                      "canary",
+			ClusterDeploymentSelector: metav1.LabelSelector{MatchLabels: map[string]string{"tier": "canary"}},
+			MaxParallel:               maxParallel,
+			MaxFailures:               maxFailures,
+		},
+		{
+			Name: "rest",
+		},
+	}
+}
+
+func testClusterState(name string, degradedOperators ...string) *hivev1.ClusterState {
+	clusterState := &hivev1.ClusterState{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+	}
+	for _, operator := range degradedOperators {
+		clusterState.Status.ClusterOperators = append(clusterState.Status.ClusterOperators, hivev1.ClusterOperatorState{
+			Name: operator,
+			Conditions: []openshiftapiv1.ClusterOperatorStatusCondition{{
+				Type:   openshiftapiv1.OperatorDegraded,
+				Status: openshiftapiv1.ConditionTrue,
+			}},
+		})
+	}
+	return clusterState
+}
+
+func testStatus(clusterDeployments, upgraded int32, currentWave string, waves ...hivev1.ClusterUpgradeWaveStatus) hivev1.ClusterUpgradePolicyStatus {
+	return hivev1.ClusterUpgradePolicyStatus{
+		ClusterDeployments:         clusterDeployments,
+		UpgradedClusterDeployments: upgraded,
+		CurrentWave:                currentWave,
+		Waves:                      waves,
+	}
+}
+
+func waveStatus(name string, state hivev1.ClusterUpgradeWaveState, clusterDeployments, upgrading, upgraded, failed int32) hivev1.ClusterUpgradeWaveStatus {
+	return hivev1.ClusterUpgradeWaveStatus{
+		Name:                        name,
+		State:                       state,
+		ClusterDeployments:          clusterDeployments,
+		UpgradingClusterDeployments: upgrading,
+		UpgradedClusterDeployments:  upgraded,
+		FailedClusterDeployments:    failed,
+	}
+}
+
+func withMessage(status hivev1.ClusterUpgradeWaveStatus, message string) hivev1.ClusterUpgradeWaveStatus {
+	status.Message = message
+	return status
+}
+
+func upgradedTo(releaseImage string) func(*hivev1.ClusterDeployment) {
+	return func(cd *hivev1.ClusterDeployment) {
+		cd.Status.ClusterVersionStatus.History = []openshiftapiv1.UpdateHistory{{
+			State: openshiftapiv1.CompletedUpdate,
+			Image: releaseImage,
+		}}
+	}
+}
@@ -0,0 +1,163 @@
+package clusterupgradepolicy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	openshiftapiv1 "github.com/openshift/api/config/v1"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+)
+
+// defaultWaveName is the name of the single wave of a policy with no waves.
+const defaultWaveName = "all"
+
+// waveRollout tracks the clusters of a wave during the reconcile of a policy.
+type waveRollout struct {
+	wave     hivev1.ClusterUpgradeWave
+	selector labels.Selector
+
+	pending   []*hivev1.ClusterDeployment
+	upgrading int32
+	upgraded  int32
+	failed    int32
+	// degraded are the names of the upgrading or upgraded clusters of the wave with degraded cluster operators.
+	degraded []string
+}
+
+// newWaveRollouts returns the rollouts of the waves of the policy, in order.
+func newWaveRollouts(policy *hivev1.ClusterUpgradePolicy) ([]*waveRollout, error) {
+	waves := policy.Spec.Waves
+	if len(waves) == 0 {
+		waves = []hivev1.ClusterUpgradeWave{{Name: defaultWaveName}}
+	}
+	rollouts := make([]*waveRollout, len(waves))
+	for i, wave := range waves {
+		selector, err := metav1.LabelSelectorAsSelector(&waves[i].ClusterDeploymentSelector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cluster deployment selector of wave %s: %v", wave.Name, err)
+		}
+		rollouts[i] = &waveRollout{wave: wave, selector: selector}
+	}
+	return rollouts, nil
+}
+
+// waveFor returns the rollout of the first wave selecting the ClusterDeployment, or nil if no wave selects it.
+func waveFor(rollouts []*waveRollout, cd *hivev1.ClusterDeployment) *waveRollout {
+	for _, rollout := range rollouts {
+		if rollout.selector.Matches(labels.Set(cd.Labels)) {
+			return rollout
+		}
+	}
+	return nil
+}
+
+// add tracks a cluster of the wave. The cluster is upgrading when it has been asked to upgrade to the release of the
+// policy and has not completed the upgrade yet.
+func (w *waveRollout) add(cd *hivev1.ClusterDeployment, requested, upgraded bool, clusterState *hivev1.ClusterState) {
+	switch {
+	case upgraded:
+		w.upgraded++
+	case requested && conditionTrue(cd, hivev1.UpgradeFailedCondition):
+		w.failed++
+		return
+	case requested:
+		w.upgrading++
+	default:
+		w.pending = append(w.pending, cd)
+		return
+	}
+	if operators := degradedOperators(clusterState); len(operators) > 0 {
+		w.degraded = append(w.degraded, fmt.Sprintf("%s/%s (%s)", cd.Namespace, cd.Name, strings.Join(operators, ", ")))
+	}
+}
+
+// rollout computes the status of the waves of a policy and the clusters whose upgrade should be started now, in the
+// first wave that has not completed. It also returns when the next maintenance window of a cluster waiting for one
+// starts, if any.
+func rollout(rollouts []*waveRollout, now time.Time) ([]hivev1.ClusterUpgradeWaveStatus, string, []*hivev1.ClusterDeployment, time.Time) {
+	var (
+		statuses    []hivev1.ClusterUpgradeWaveStatus
+		currentWave string
+		start       []*hivev1.ClusterDeployment
+		nextWindow  time.Time
+	)
+	for _, w := range rollouts {
+		status := hivev1.ClusterUpgradeWaveStatus{
+			Name:                       w.wave.Name,
+			ClusterDeployments:         int32(len(w.pending)) + w.upgrading + w.upgraded + w.failed,
+			UpgradedClusterDeployments: w.upgraded,
+			FailedClusterDeployments:   w.failed,
+		}
+		switch {
+		case currentWave != "":
+			status.State = hivev1.ClusterUpgradeWavePending
+		case w.failed > w.wave.MaxFailures:
+			currentWave = w.wave.Name
+			status.State = hivev1.ClusterUpgradeWaveFailed
+			status.Message = fmt.Sprintf("%d cluster upgrades failed, more than the %d allowed", w.failed, w.wave.MaxFailures)
+		case len(w.pending) == 0 && w.upgrading == 0:
+			status.State = hivev1.ClusterUpgradeWaveCompleted
+		case len(w.degraded) > 0:
+			currentWave = w.wave.Name
+			sort.Strings(w.degraded)
+			status.State = hivev1.ClusterUpgradeWavePaused
+			status.Message = fmt.Sprintf("Cluster operators are degraded on %s", strings.Join(w.degraded, "; "))
+		default:
+			currentWave = w.wave.Name
+			status.State = hivev1.ClusterUpgradeWaveProgressing
+			sort.Slice(w.pending, func(i, j int) bool {
+				return w.pending[i].Namespace+"/"+w.pending[i].Name < w.pending[j].Namespace+"/"+w.pending[j].Name
+			})
+			waiting := 0
+			for _, cd := range w.pending {
+				if w.wave.MaxParallel > 0 && w.upgrading >= w.wave.MaxParallel {
+					break
+				}
+				open, next := controllerutils.InMaintenanceWindow(cd.Spec.MaintenanceWindows, now)
+				if !open {
+					waiting++
+					if !next.IsZero() && (nextWindow.IsZero() || next.Before(nextWindow)) {
+						nextWindow = next
+					}
+					continue
+				}
+				start = append(start, cd)
+				w.upgrading++
+			}
+			if waiting > 0 {
+				status.Message = fmt.Sprintf("%d clusters are waiting for their maintenance window", waiting)
+			}
+		}
+		status.UpgradingClusterDeployments = w.upgrading
+		statuses = append(statuses, status)
+	}
+	return statuses, currentWave, start, nextWindow
+}
+
+// degradedOperators returns the names of the degraded cluster operators in the ClusterState.
+func degradedOperators(clusterState *hivev1.ClusterState) []string {
+	if clusterState == nil {
+		return nil
+	}
+	var operators []string
+	for _, operator := range clusterState.Status.ClusterOperators {
+		for _, cond := range operator.Conditions {
+			if cond.Type == openshiftapiv1.OperatorDegraded && cond.Status == openshiftapiv1.ConditionTrue {
+				operators = append(operators, operator.Name)
+			}
+		}
+	}
+	return operators
+}
+
+func conditionTrue(cd *hivev1.ClusterDeployment, conditionType hivev1.ClusterDeploymentConditionType) bool {
+	cond := controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, conditionType)
+	return cond != nil && cond.Status == corev1.ConditionTrue
+}
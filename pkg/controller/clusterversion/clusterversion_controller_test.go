@@ -87,7 +87,7 @@ func TestClusterVersionReconcile(t *testing.T) {
 			mockRemoteClientBuilder := remoteclientmock.NewMockBuilder(mockCtrl)
 			if !test.noRemoteCall {
 				mockRemoteClientBuilder.EXPECT().Unreachable().Return(false)
-				mockRemoteClientBuilder.EXPECT().Build().Return(testRemoteClusterAPIClient(), nil)
+				mockRemoteClientBuilder.EXPECT().BuildCached().Return(testRemoteClusterAPIClient(), nil)
 			}
 			rcd := &ReconcileClusterVersion{
 				Client:                        fakeClient,
@@ -34,8 +34,14 @@ const (
 )
 
 // Add creates a new DNSZone Controller and adds it to the Manager with default RBAC. The Manager will set fields on the Controller
-// and Start it when the Manager is Started.
+// and Start it when the Manager is Started. When the hive controllers are sharded, the controller only runs in the first
+// shard, where it reconciles the DNSZones of all namespaces, so that the name servers of the managed domains are scraped
+// once rather than by every shard.
 func Add(mgr manager.Manager) error {
+	if !controllerutils.GetShard().Owns("") {
+		log.WithField("controller", controllerName).Info("dns endpoints are reconciled by the first controllers shard")
+		return nil
+	}
 	c := controllerutils.NewClientWithMetricsOrDie(mgr, controllerName)
 
 	reconciler, nameServerChangeNotifier, err := newReconciler(mgr, c)
@@ -52,13 +58,17 @@ func Add(mgr manager.Manager) error {
 		mgr,
 		controller.Options{
 			Reconciler:              reconciler,
-			MaxConcurrentReconciles: controllerutils.GetConcurrentReconciles(),
+			MaxConcurrentReconciles: controllerutils.GetConcurrentReconciles(controllerName),
 		},
 	)
 	if err != nil {
 		return err
 	}
 
+	if err := controllerutils.SetQueueRateLimiter(ctrl, controllerName); err != nil {
+		return err
+	}
+
 	if err := ctrl.Watch(&source.Kind{Type: &hivev1.DNSZone{}}, &handler.EnqueueRequestForObject{}); err != nil {
 		return err
 	}
@@ -289,6 +299,16 @@ func createNameServerQuery(c client.Client, logger log.FieldLogger, managedDomai
 		logger.Infof("using gcp creds for managed domain stored in %q secret", secretName)
 		return nameserver.NewGCPQuery(c, secretName)
 	}
+	if managedDomain.Azure != nil {
+		secretName := managedDomain.Azure.CredentialsSecretRef.Name
+		logger.Infof("using azure creds for managed domain stored in %q secret", secretName)
+		return nameserver.NewAzureQuery(c, secretName, managedDomain.Azure.ResourceGroupName)
+	}
+	if managedDomain.RFC2136 != nil {
+		secretName := managedDomain.RFC2136.TSIGKeySecretRef.Name
+		logger.Infof("using TSIG key for managed domain stored in %q secret", secretName)
+		return nameserver.NewRFC2136Query(c, secretName, managedDomain.RFC2136.Server)
+	}
 	logger.Error("unsupported cloud for managing DNS")
 	return nil
 }
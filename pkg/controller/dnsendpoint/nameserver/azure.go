@@ -0,0 +1,165 @@
+package nameserver
+
+import (
+	"context"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/dns/mgmt/2017-10-01/dns"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/hive/pkg/azureclient"
+	"github.com/openshift/hive/pkg/constants"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+)
+
+// NewAzureQuery creates a new name server query for Azure.
+func NewAzureQuery(c client.Client, credsSecretName string, resourceGroupName string) Query {
+	return &azureQuery{
+		getAzureClient: func() (azureclient.Client, error) {
+			credsSecret := &corev1.Secret{}
+			if err := c.Get(
+				context.Background(),
+				client.ObjectKey{Namespace: constants.HiveNamespace, Name: credsSecretName},
+				credsSecret,
+			); err != nil {
+				return nil, errors.Wrap(err, "could not get the creds secret")
+			}
+			azureClient, err := azureclient.NewClientFromSecret(credsSecret)
+			return azureClient, errors.Wrap(err, "error creating Azure client")
+		},
+		resourceGroupName: resourceGroupName,
+	}
+}
+
+type azureQuery struct {
+	getAzureClient    func() (azureclient.Client, error)
+	resourceGroupName string
+}
+
+var _ Query = (*azureQuery)(nil)
+
+// Get implements Query.Get.
+func (q *azureQuery) Get(domain string) (map[string]sets.String, error) {
+	azureClient, err := q.getAzureClient()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get Azure client")
+	}
+	// Azure DNS zones are named after the domain that they host.
+	if _, err := azureClient.GetZone(context.TODO(), q.resourceGroupName, domain); err != nil {
+		if azureclient.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "error querying zone")
+	}
+	currentNameServers, err := q.queryNameServers(azureClient, domain)
+	return currentNameServers, errors.Wrap(err, "error querying name servers")
+}
+
+// Create implements Query.Create.
+func (q *azureQuery) Create(rootDomain string, domain string, values sets.String) error {
+	azureClient, err := q.getAzureClient()
+	if err != nil {
+		return errors.Wrap(err, "failed to get Azure client")
+	}
+	// An update in Azure DNS replaces the record set, so there is no need to
+	// distinguish between creating and updating the name servers.
+	_, err = azureClient.CreateOrUpdateRecordSet(
+		context.TODO(),
+		q.resourceGroupName,
+		rootDomain,
+		relativeRecordSetName(rootDomain, domain),
+		dns.NS,
+		q.recordSet(values),
+	)
+	return errors.Wrap(err, "error creating the name server")
+}
+
+// Delete implements Query.Delete.
+func (q *azureQuery) Delete(rootDomain string, domain string, values sets.String) error {
+	azureClient, err := q.getAzureClient()
+	if err != nil {
+		return errors.Wrap(err, "failed to get Azure client")
+	}
+	// Azure DNS deletes the record set by name and type, regardless of its
+	// current values.
+	err = azureClient.DeleteRecordSet(
+		context.TODO(),
+		q.resourceGroupName,
+		rootDomain,
+		relativeRecordSetName(rootDomain, domain),
+		dns.NS,
+	)
+	if azureclient.IsNotFound(err) {
+		return nil
+	}
+	return errors.Wrap(err, "error deleting the name server")
+}
+
+// queryNameServers queries Azure for the name servers in the specified zone.
+func (q *azureQuery) queryNameServers(azureClient azureclient.Client, rootDomain string) (map[string]sets.String, error) {
+	nameServers := map[string]sets.String{}
+	page, err := azureClient.ListRecordSetsByZone(context.TODO(), q.resourceGroupName, rootDomain, "")
+	if err != nil {
+		return nil, err
+	}
+	for page.NotDone() {
+		for _, recordSet := range page.Values() {
+			if recordSet.Type == nil || !strings.HasSuffix(*recordSet.Type, "/"+string(dns.NS)) {
+				continue
+			}
+			if recordSet.RecordSetProperties == nil || recordSet.NsRecords == nil {
+				continue
+			}
+			values := sets.NewString()
+			for _, v := range *recordSet.NsRecords {
+				if v.Nsdname != nil {
+					values.Insert(controllerutils.Undotted(*v.Nsdname))
+				}
+			}
+			nameServers[absoluteDomain(rootDomain, to.String(recordSet.Name))] = values
+		}
+		if err := page.NextWithContext(context.TODO()); err != nil {
+			return nil, err
+		}
+	}
+	return nameServers, nil
+}
+
+func (q *azureQuery) recordSet(values sets.String) dns.RecordSet {
+	nsRecords := make([]dns.NsRecord, len(values))
+	for i, v := range values.List() {
+		nsRecords[i] = dns.NsRecord{Nsdname: to.StringPtr(v)}
+	}
+	return dns.RecordSet{
+		RecordSetProperties: &dns.RecordSetProperties{
+			TTL:       to.Int64Ptr(60),
+			NsRecords: &nsRecords,
+		},
+	}
+}
+
+// relativeRecordSetName returns the name of the record set for the specified domain relative to the zone of the
+// specified root domain.
+func relativeRecordSetName(rootDomain string, domain string) string {
+	rootDomain = controllerutils.Undotted(rootDomain)
+	domain = controllerutils.Undotted(domain)
+	if domain == rootDomain {
+		return "@"
+	}
+	return strings.TrimSuffix(domain, "."+rootDomain)
+}
+
+// absoluteDomain returns the domain for the specified record set name relative to the zone of the specified root
+// domain.
+func absoluteDomain(rootDomain string, recordSetName string) string {
+	rootDomain = controllerutils.Undotted(rootDomain)
+	if recordSetName == "@" {
+		return rootDomain
+	}
+	return recordSetName + "." + rootDomain
+}
@@ -0,0 +1,221 @@
+package nameserver
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/dns/mgmt/2017-10-01/dns"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/hive/pkg/azureclient"
+	"github.com/openshift/hive/pkg/azureclient/mock"
+)
+
+func TestAzureGet(t *testing.T) {
+	cases := []struct {
+		name                string
+		zoneNotFound        bool
+		recordSetPages      [][]dns.RecordSet
+		expectedNameServers map[string]sets.String
+	}{
+		{
+			name:         "no zone for domain",
+			zoneNotFound: true,
+		},
+		{
+			name:           "no records",
+			recordSetPages: [][]dns.RecordSet{{}},
+		},
+		{
+			name: "no name server records",
+			recordSetPages: [][]dns.RecordSet{{
+				azure.aRecordSet("test-subdomain", "1.2.3.4"),
+			}},
+		},
+		{
+			name: "single name server",
+			recordSetPages: [][]dns.RecordSet{{
+				azure.nsRecordSet("test-subdomain", "test-ns"),
+			}},
+			expectedNameServers: map[string]sets.String{
+				"test-subdomain.test-domain": sets.NewString("test-ns"),
+			},
+		},
+		{
+			name: "name servers for root domain",
+			recordSetPages: [][]dns.RecordSet{{
+				azure.nsRecordSet("@", "test-ns-1.", "test-ns-2."),
+			}},
+			expectedNameServers: map[string]sets.String{
+				"test-domain": sets.NewString("test-ns-1", "test-ns-2"),
+			},
+		},
+		{
+			name: "name servers for multiple domains",
+			recordSetPages: [][]dns.RecordSet{{
+				azure.nsRecordSet("test-subdomain-1", "test-ns-1"),
+				azure.nsRecordSet("test-subdomain-2", "test-ns-2"),
+				azure.nsRecordSet("test-subdomain-3", "test-ns-3"),
+			}},
+			expectedNameServers: map[string]sets.String{
+				"test-subdomain-1.test-domain": sets.NewString("test-ns-1"),
+				"test-subdomain-2.test-domain": sets.NewString("test-ns-2"),
+				"test-subdomain-3.test-domain": sets.NewString("test-ns-3"),
+			},
+		},
+		{
+			name: "multiple pages",
+			recordSetPages: [][]dns.RecordSet{
+				{
+					azure.nsRecordSet("test-subdomain-1", "test-ns-1"),
+					azure.aRecordSet("other-subdomain", "1.2.3.4"),
+				},
+				{
+					azure.nsRecordSet("test-subdomain-2", "test-ns-2"),
+				},
+			},
+			expectedNameServers: map[string]sets.String{
+				"test-subdomain-1.test-domain": sets.NewString("test-ns-1"),
+				"test-subdomain-2.test-domain": sets.NewString("test-ns-2"),
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockAzureClient := mock.NewMockClient(mockCtrl)
+			azureQuery := &azureQuery{
+				getAzureClient: func() (azureclient.Client, error) {
+					return mockAzureClient, nil
+				},
+				resourceGroupName: "test-resource-group",
+			}
+			if tc.zoneNotFound {
+				mockAzureClient.EXPECT().
+					GetZone(gomock.Any(), "test-resource-group", "test-domain").
+					Return(dns.Zone{}, autorest.DetailedError{StatusCode: http.StatusNotFound})
+			} else {
+				mockAzureClient.EXPECT().
+					GetZone(gomock.Any(), "test-resource-group", "test-domain").
+					Return(dns.Zone{Name: to.StringPtr("test-domain")}, nil)
+				mockPage := mock.NewMockRecordSetPage(mockCtrl)
+				mockAzureClient.EXPECT().
+					ListRecordSetsByZone(gomock.Any(), "test-resource-group", "test-domain", "").
+					Return(mockPage, nil)
+				var calls []*gomock.Call
+				for _, values := range tc.recordSetPages {
+					calls = append(calls,
+						mockPage.EXPECT().NotDone().Return(true),
+						mockPage.EXPECT().Values().Return(values),
+						mockPage.EXPECT().NextWithContext(gomock.Any()).Return(nil),
+					)
+				}
+				calls = append(calls, mockPage.EXPECT().NotDone().Return(false))
+				gomock.InOrder(calls...)
+			}
+			actualNameServers, err := azureQuery.Get("test-domain")
+			assert.NoError(t, err, "expected no error from querying")
+			if len(tc.expectedNameServers) == 0 {
+				assert.Empty(t, actualNameServers, "expected no name servers")
+			} else {
+				assert.Equal(t, tc.expectedNameServers, actualNameServers, "unexpected name servers")
+			}
+		})
+	}
+}
+
+func TestAzureCreate(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	mockAzureClient := mock.NewMockClient(mockCtrl)
+	azureQuery := &azureQuery{
+		getAzureClient: func() (azureclient.Client, error) {
+			return mockAzureClient, nil
+		},
+		resourceGroupName: "test-resource-group",
+	}
+	mockAzureClient.EXPECT().
+		CreateOrUpdateRecordSet(gomock.Any(), "test-resource-group", "test-domain", "test-subdomain", dns.NS, gomock.Any()).
+		DoAndReturn(func(_, _, _, _ interface{}, _ dns.RecordType, recordSet dns.RecordSet) (dns.RecordSet, error) {
+			if assert.NotNil(t, recordSet.NsRecords, "expected NS records") {
+				assert.Equal(t, []dns.NsRecord{
+					{Nsdname: to.StringPtr("test-ns-1")},
+					{Nsdname: to.StringPtr("test-ns-2")},
+				}, *recordSet.NsRecords, "unexpected NS records")
+			}
+			return recordSet, nil
+		})
+	err := azureQuery.Create("test-domain", "test-subdomain.test-domain", sets.NewString("test-ns-2", "test-ns-1"))
+	assert.NoError(t, err, "expected no error from creating")
+}
+
+func TestAzureDelete(t *testing.T) {
+	cases := []struct {
+		name        string
+		deleteError error
+	}{
+		{
+			name: "existing name servers",
+		},
+		{
+			name:        "no name servers",
+			deleteError: autorest.DetailedError{StatusCode: http.StatusNotFound},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			mockAzureClient := mock.NewMockClient(mockCtrl)
+			azureQuery := &azureQuery{
+				getAzureClient: func() (azureclient.Client, error) {
+					return mockAzureClient, nil
+				},
+				resourceGroupName: "test-resource-group",
+			}
+			mockAzureClient.EXPECT().
+				DeleteRecordSet(gomock.Any(), "test-resource-group", "test-domain", "test-subdomain", dns.NS).
+				Return(tc.deleteError)
+			err := azureQuery.Delete("test-domain", "test-subdomain.test-domain", sets.NewString("test-ns"))
+			assert.NoError(t, err, "expected no error from deleting")
+		})
+	}
+}
+
+type azureTestFuncs struct{}
+
+var azure azureTestFuncs
+
+func (*azureTestFuncs) nsRecordSet(name string, values ...string) dns.RecordSet {
+	nsRecords := make([]dns.NsRecord, len(values))
+	for i, v := range values {
+		nsRecords[i] = dns.NsRecord{Nsdname: to.StringPtr(v)}
+	}
+	return dns.RecordSet{
+		Name: to.StringPtr(name),
+		Type: to.StringPtr("Microsoft.Network/dnszones/NS"),
+		RecordSetProperties: &dns.RecordSetProperties{
+			NsRecords: &nsRecords,
+		},
+	}
+}
+
+func (*azureTestFuncs) aRecordSet(name string, values ...string) dns.RecordSet {
+	aRecords := make([]dns.ARecord, len(values))
+	for i, v := range values {
+		aRecords[i] = dns.ARecord{Ipv4Address: to.StringPtr(v)}
+	}
+	return dns.RecordSet{
+		Name: to.StringPtr(name),
+		Type: to.StringPtr("Microsoft.Network/dnszones/A"),
+		RecordSetProperties: &dns.RecordSetProperties{
+			ARecords: &aRecords,
+		},
+	}
+}
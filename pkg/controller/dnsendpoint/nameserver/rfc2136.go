@@ -0,0 +1,104 @@
+package nameserver
+
+import (
+	"context"
+
+	"github.com/miekg/dns"
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/openshift/hive/pkg/constants"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+	"github.com/openshift/hive/pkg/rfc2136client"
+)
+
+// NewRFC2136Query creates a new name server query for a DNS server updated through RFC2136 dynamic updates.
+func NewRFC2136Query(c client.Client, tsigKeySecretName string, server string) Query {
+	return &rfc2136Query{
+		getRFC2136Client: func() (rfc2136client.Client, error) {
+			tsigKeySecret := &corev1.Secret{}
+			if err := c.Get(
+				context.Background(),
+				client.ObjectKey{Namespace: constants.HiveNamespace, Name: tsigKeySecretName},
+				tsigKeySecret,
+			); err != nil {
+				return nil, errors.Wrap(err, "could not get the TSIG key secret")
+			}
+			rfc2136Client, err := rfc2136client.NewClientFromSecret(server, tsigKeySecret)
+			return rfc2136Client, errors.Wrap(err, "error creating RFC2136 client")
+		},
+	}
+}
+
+type rfc2136Query struct {
+	getRFC2136Client func() (rfc2136client.Client, error)
+}
+
+var _ Query = (*rfc2136Query)(nil)
+
+// Get implements Query.Get.
+func (q *rfc2136Query) Get(domain string) (map[string]sets.String, error) {
+	rfc2136Client, err := q.getRFC2136Client()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get RFC2136 client")
+	}
+	zoneNameServers, err := rfc2136Client.GetNameServers(domain)
+	if err != nil {
+		return nil, errors.Wrap(err, "error querying zone")
+	}
+	if zoneNameServers == nil {
+		return nil, nil
+	}
+	currentNameServers, err := q.queryNameServers(rfc2136Client, domain)
+	return currentNameServers, errors.Wrap(err, "error querying name servers")
+}
+
+// Create implements Query.Create.
+func (q *rfc2136Query) Create(rootDomain string, domain string, values sets.String) error {
+	rfc2136Client, err := q.getRFC2136Client()
+	if err != nil {
+		return errors.Wrap(err, "failed to get RFC2136 client")
+	}
+	// The update replaces the record set, so there is no need to distinguish
+	// between creating and updating the name servers.
+	err = rfc2136Client.ReplaceRecords(rootDomain, domain, dns.TypeNS, 60, values.List())
+	return errors.Wrap(err, "error creating the name server")
+}
+
+// Delete implements Query.Delete.
+func (q *rfc2136Query) Delete(rootDomain string, domain string, values sets.String) error {
+	rfc2136Client, err := q.getRFC2136Client()
+	if err != nil {
+		return errors.Wrap(err, "failed to get RFC2136 client")
+	}
+	// The update deletes the record set by name and type, regardless of its
+	// current values, and succeeds when the record set does not exist.
+	err = rfc2136Client.DeleteRecords(rootDomain, domain, dns.TypeNS)
+	return errors.Wrap(err, "error deleting the name server")
+}
+
+// queryNameServers transfers the specified zone from the DNS server to find the name servers in the zone.
+func (q *rfc2136Query) queryNameServers(rfc2136Client rfc2136client.Client, rootDomain string) (map[string]sets.String, error) {
+	nameServers := map[string]sets.String{}
+	records, err := rfc2136Client.ListRecords(rootDomain)
+	if err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		ns, ok := record.(*dns.NS)
+		if !ok {
+			continue
+		}
+		domain := controllerutils.Undotted(ns.Hdr.Name)
+		values, ok := nameServers[domain]
+		if !ok {
+			values = sets.NewString()
+			nameServers[domain] = values
+		}
+		values.Insert(controllerutils.Undotted(ns.Ns))
+	}
+	return nameServers, nil
+}
@@ -0,0 +1,123 @@
+package nameserver
+
+import (
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+
+	"k8s.io/apimachinery/pkg/util/sets"
+
+	"github.com/openshift/hive/pkg/rfc2136client"
+	"github.com/openshift/hive/pkg/test/dnsserver"
+)
+
+func TestRFC2136Get(t *testing.T) {
+	cases := []struct {
+		name                string
+		domain              string
+		delegations         map[string][]string
+		expectedNameServers map[string]sets.String
+	}{
+		{
+			name:   "zone not hosted",
+			domain: "other-domain",
+		},
+		{
+			name:   "no delegations",
+			domain: "test-domain",
+			expectedNameServers: map[string]sets.String{
+				"test-domain": sets.NewString("test-ns"),
+			},
+		},
+		{
+			name:   "single delegation",
+			domain: "test-domain",
+			delegations: map[string][]string{
+				"test-subdomain.test-domain.": {"test-sub-ns-1.", "test-sub-ns-2."},
+			},
+			expectedNameServers: map[string]sets.String{
+				"test-domain":                sets.NewString("test-ns"),
+				"test-subdomain.test-domain": sets.NewString("test-sub-ns-1", "test-sub-ns-2"),
+			},
+		},
+		{
+			name:   "multiple delegations",
+			domain: "test-domain",
+			delegations: map[string][]string{
+				"test-subdomain-1.test-domain.": {"test-ns-1."},
+				"test-subdomain-2.test-domain.": {"test-ns-2."},
+			},
+			expectedNameServers: map[string]sets.String{
+				"test-domain":                  sets.NewString("test-ns"),
+				"test-subdomain-1.test-domain": sets.NewString("test-ns-1"),
+				"test-subdomain-2.test-domain": sets.NewString("test-ns-2"),
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := dnsserver.Start(t, map[string][]string{"test-domain.": {"test-ns."}})
+			rfc2136Query := newTestRFC2136Query(server)
+			for domain, values := range tc.delegations {
+				err := rfc2136Query.Create("test-domain", domain, sets.NewString(values...))
+				if !assert.NoError(t, err, "unexpected error creating delegation") {
+					return
+				}
+			}
+			actualNameservers, err := rfc2136Query.Get(tc.domain)
+			assert.NoError(t, err, "expected no error from querying")
+			assert.Equal(t, tc.expectedNameServers, actualNameservers, "unexpected name servers")
+		})
+	}
+}
+
+func TestRFC2136Create(t *testing.T) {
+	server := dnsserver.Start(t, map[string][]string{"test-domain.": {"test-ns."}})
+	rfc2136Query := newTestRFC2136Query(server)
+	err := rfc2136Query.Create("test-domain", "test-subdomain.test-domain", sets.NewString("test-ns-1", "test-ns-2"))
+	assert.NoError(t, err, "expected no error from creating")
+	assert.Equal(t, []string{"test-ns-1.", "test-ns-2."}, server.Records("test-subdomain.test-domain.", dns.TypeNS))
+
+	err = rfc2136Query.Create("test-domain", "test-subdomain.test-domain", sets.NewString("test-ns-3"))
+	assert.NoError(t, err, "expected no error from updating")
+	assert.Equal(t, []string{"test-ns-3."}, server.Records("test-subdomain.test-domain.", dns.TypeNS))
+}
+
+func TestRFC2136Delete(t *testing.T) {
+	cases := []struct {
+		name           string
+		existingValues []string
+	}{
+		{
+			name:           "existing name server",
+			existingValues: []string{"test-ns-1", "test-ns-2"},
+		},
+		{
+			name: "no existing name server",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := dnsserver.Start(t, map[string][]string{"test-domain.": {"test-ns."}})
+			rfc2136Query := newTestRFC2136Query(server)
+			if len(tc.existingValues) > 0 {
+				err := rfc2136Query.Create("test-domain", "test-subdomain.test-domain", sets.NewString(tc.existingValues...))
+				if !assert.NoError(t, err, "unexpected error creating delegation") {
+					return
+				}
+			}
+			err := rfc2136Query.Delete("test-domain", "test-subdomain.test-domain", sets.NewString(tc.existingValues...))
+			assert.NoError(t, err, "expected no error from deleting")
+			assert.Empty(t, server.Records("test-subdomain.test-domain.", dns.TypeNS), "expected name servers to be deleted")
+		})
+	}
+}
+
+func newTestRFC2136Query(server *dnsserver.Server) *rfc2136Query {
+	return &rfc2136Query{
+		getRFC2136Client: func() (rfc2136client.Client, error) {
+			return rfc2136client.NewClient(server.Addr, dnsserver.TSIGKeyName, "", dnsserver.TSIGSecret), nil
+		},
+	}
+}
@@ -0,0 +1,158 @@
+package dnszone
+
+import (
+	"context"
+
+	"github.com/Azure/azure-sdk-for-go/services/dns/mgmt/2017-10-01/dns"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/azureclient"
+)
+
+// AzureActuator attempts to make the current state reflect the given desired state.
+type AzureActuator struct {
+	// logger is the logger used for this controller
+	logger log.FieldLogger
+
+	// azureClient is a utility for making it easy for controllers to interface with Azure
+	azureClient azureclient.Client
+
+	// dnsZone is the DNSZone that represents the desired state.
+	dnsZone *hivev1.DNSZone
+
+	// managedZone is the Azure DNS zone object.
+	managedZone *dns.Zone
+}
+
+type azureClientBuilderType func(secret *corev1.Secret) (azureclient.Client, error)
+
+// NewAzureActuator creates a new AzureActuator object. A new AzureActuator is expected to be created for each controller sync.
+func NewAzureActuator(
+	logger log.FieldLogger,
+	secret *corev1.Secret,
+	dnsZone *hivev1.DNSZone,
+	azureClientBuilder azureClientBuilderType,
+) (*AzureActuator, error) {
+	azureClient, err := azureClientBuilder(secret)
+	if err != nil {
+		logger.WithError(err).Error("Error creating AzureClient")
+		return nil, err
+	}
+
+	azureActuator := &AzureActuator{
+		logger:      logger,
+		azureClient: azureClient,
+		dnsZone:     dnsZone,
+	}
+
+	return azureActuator, nil
+}
+
+// Ensure AzureActuator implements the Actuator interface. This will fail at compile time when false.
+var _ Actuator = &AzureActuator{}
+
+// Create implements the Create call of the actuator interface
+func (a *AzureActuator) Create() error {
+	logger := a.logger.WithField("zone", a.dnsZone.Spec.Zone)
+	logger.Info("Creating managed zone")
+
+	resourceGroupName := a.dnsZone.Spec.Azure.ResourceGroupName
+	managedZone, err := a.azureClient.CreateOrUpdateZone(context.TODO(), resourceGroupName, a.dnsZone.Spec.Zone)
+	if err != nil {
+		logger.WithError(err).Error("Error creating managed zone")
+		return err
+	}
+
+	logger.Debug("Managed zone successfully created")
+	a.managedZone = &managedZone
+	return nil
+}
+
+// Delete implements the Delete call of the actuator interface
+func (a *AzureActuator) Delete() error {
+	if a.managedZone == nil {
+		return errors.New("managedZone is unpopulated")
+	}
+
+	logger := a.logger.WithField("zone", a.dnsZone.Spec.Zone).WithField("zoneName", *a.managedZone.Name)
+	logger.Info("Deleting managed zone")
+	resourceGroupName := a.dnsZone.Spec.Azure.ResourceGroupName
+	err := a.azureClient.DeleteZone(context.TODO(), resourceGroupName, *a.managedZone.Name)
+	if err != nil {
+		logger.WithError(err).Error("Cannot delete managed zone")
+	}
+	return err
+}
+
+// Exists implements the Exists call of the actuator interface
+func (a *AzureActuator) Exists() (bool, error) {
+	return a.managedZone != nil, nil
+}
+
+// UpdateMetadata implements the UpdateMetadata call of the actuator interface
+func (a *AzureActuator) UpdateMetadata() error {
+	// Nothing to do here since the DNSZone does not specify any tags for Azure DNS zones.
+	return nil
+}
+
+// ModifyStatus implements the ModifyStatus call of the actuator interface
+func (a *AzureActuator) ModifyStatus() error {
+	if a.managedZone == nil {
+		return errors.New("managedZone is unpopulated")
+	}
+
+	a.dnsZone.Status.Azure = &hivev1.AzureDNSZoneStatus{
+		ZoneName: a.managedZone.Name,
+	}
+
+	return nil
+}
+
+// GetNameServers implements the GetNameServers call of the actuator interface
+func (a *AzureActuator) GetNameServers() ([]string, error) {
+	if a.managedZone == nil {
+		return nil, errors.New("managedZone is unpopulated")
+	}
+
+	logger := a.logger.WithField("zone", a.dnsZone.Spec.Zone)
+	var result []string
+	if a.managedZone.ZoneProperties != nil && a.managedZone.NameServers != nil {
+		result = *a.managedZone.NameServers
+	}
+	logger.WithField("nameservers", result).Debug("found managed zone name servers")
+	return result, nil
+}
+
+// Refresh implements the Refresh call of the actuator interface
+func (a *AzureActuator) Refresh() error {
+	// Azure DNS zones are named after the domain that they host.
+	zoneName := a.dnsZone.Spec.Zone
+	if a.dnsZone.Status.Azure != nil && a.dnsZone.Status.Azure.ZoneName != nil {
+		a.logger.Debug("ZoneName is set in status, will retrieve by that name")
+		zoneName = *a.dnsZone.Status.Azure.ZoneName
+	}
+
+	// Fetch the managed zone
+	logger := a.logger.WithField("zoneName", zoneName)
+	logger.Debug("Fetching managed zone by zone name")
+	resourceGroupName := a.dnsZone.Spec.Azure.ResourceGroupName
+	resp, err := a.azureClient.GetZone(context.TODO(), resourceGroupName, zoneName)
+	if err != nil {
+		if azureclient.IsNotFound(err) {
+			logger.Debug("Zone not found, clearing out the cached object")
+			a.managedZone = nil
+			return nil
+		}
+
+		logger.WithError(err).Error("Cannot get managed zone")
+		return err
+	}
+
+	logger.Debug("Found managed zone")
+	a.managedZone = &resp
+	return nil
+}
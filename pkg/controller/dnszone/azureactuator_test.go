@@ -0,0 +1,101 @@
+package dnszone
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/dns/mgmt/2017-10-01/dns"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/to"
+	"github.com/golang/mock/gomock"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/azureclient/mock"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// TestNewAzureActuator tests that a new AzureActuator object can be created.
+func TestNewAzureActuator(t *testing.T) {
+	cases := []struct {
+		name    string
+		dnsZone *hivev1.DNSZone
+		secret  *corev1.Secret
+	}{
+		{
+			name:    "Successfully create new zone",
+			dnsZone: azureDNSZone(validDNSZone()),
+			secret:  validAzureSecret(),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			mocks := setupDefaultMocks(t)
+			expectedAzureActuator := &AzureActuator{
+				logger:  log.WithField("controller", controllerName),
+				dnsZone: tc.dnsZone,
+			}
+
+			// Act
+			zr, err := NewAzureActuator(
+				expectedAzureActuator.logger,
+				tc.secret,
+				tc.dnsZone,
+				fakeAzureClientBuilder(mocks.mockAzureClient),
+			)
+			expectedAzureActuator.azureClient = zr.azureClient // Function pointers can't be compared reliably. Don't compare.
+
+			// Assert
+			assert.Nil(t, err)
+			assert.NotNil(t, zr.azureClient)
+			assert.Equal(t, expectedAzureActuator, zr)
+		})
+	}
+}
+
+// azureDNSZone replaces the AWS-specific settings of the given DNSZone with Azure-specific settings.
+func azureDNSZone(zone *hivev1.DNSZone) *hivev1.DNSZone {
+	zone.Spec.AWS = nil
+	zone.Spec.Azure = &hivev1.AzureDNSZoneSpec{
+		CredentialsSecretRef: corev1.LocalObjectReference{
+			Name: "somesecret",
+		},
+		ResourceGroupName: "some-resource-group",
+	}
+	if zone.Status.AWS != nil {
+		zone.Status.AWS = nil
+		zone.Status.Azure = &hivev1.AzureDNSZoneStatus{
+			ZoneName: to.StringPtr("blah.example.com"),
+		}
+	}
+	return zone
+}
+
+func azureZone() dns.Zone {
+	return dns.Zone{
+		Name: to.StringPtr("blah.example.com"),
+		ZoneProperties: &dns.ZoneProperties{
+			NameServers: &[]string{"ns1.example.com", "ns2.example.com"},
+		},
+	}
+}
+
+func mockAzureZoneExists(expect *mock.MockClientMockRecorder) {
+	expect.GetZone(gomock.Any(), "some-resource-group", "blah.example.com").Return(azureZone(), nil).Times(1)
+}
+
+func mockAzureZoneDoesntExist(expect *mock.MockClientMockRecorder) {
+	expect.GetZone(gomock.Any(), "some-resource-group", "blah.example.com").
+		Return(dns.Zone{}, autorest.DetailedError{StatusCode: http.StatusNotFound}).
+		Times(1)
+}
+
+func mockCreateAzureZone(expect *mock.MockClientMockRecorder) {
+	expect.CreateOrUpdateZone(gomock.Any(), "some-resource-group", "blah.example.com").Return(azureZone(), nil).Times(1)
+}
+
+func mockDeleteAzureZone(expect *mock.MockClientMockRecorder) {
+	expect.DeleteZone(gomock.Any(), "some-resource-group", "blah.example.com").Return(nil).Times(1)
+}
@@ -9,8 +9,11 @@ import (
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
 	"github.com/openshift/hive/pkg/awsclient/mock"
 	awsmock "github.com/openshift/hive/pkg/awsclient/mock"
+	azuremock "github.com/openshift/hive/pkg/azureclient/mock"
 	controllerutils "github.com/openshift/hive/pkg/controller/utils"
 	gcpmock "github.com/openshift/hive/pkg/gcpclient/mock"
+	"github.com/openshift/hive/pkg/rfc2136client"
+	"github.com/openshift/hive/pkg/test/dnsserver"
 	"github.com/stretchr/testify/assert"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
@@ -333,3 +336,256 @@ func TestReconcileDNSProviderForGCP(t *testing.T) {
 		})
 	}
 }
+
+// TestReconcileDNSProviderForAzure tests that ReconcileDNSProvider reacts properly under different reconciliation states on Azure.
+func TestReconcileDNSProviderForAzure(t *testing.T) {
+
+	log.SetLevel(log.DebugLevel)
+
+	cases := []struct {
+		name            string
+		dnsZone         *hivev1.DNSZone
+		setupAzureMock  func(*azuremock.MockClientMockRecorder)
+		validateZone    func(*testing.T, *hivev1.DNSZone)
+		errorExpected   bool
+		soaLookupResult bool
+	}{
+		{
+			name:    "DNSZone without finalizer",
+			dnsZone: azureDNSZone(validDNSZoneWithoutFinalizer()),
+			setupAzureMock: func(expect *azuremock.MockClientMockRecorder) {
+				mockAzureZoneExists(expect)
+			},
+			validateZone: func(t *testing.T, zone *hivev1.DNSZone) {
+				assert.True(t, controllerutils.HasFinalizer(zone, hivev1.FinalizerDNSZone))
+			},
+		},
+		{
+			name:    "Create DNS Zone, No ZoneName Set",
+			dnsZone: azureDNSZone(validDNSZoneWithoutID()),
+			setupAzureMock: func(expect *azuremock.MockClientMockRecorder) {
+				mockAzureZoneDoesntExist(expect)
+				mockCreateAzureZone(expect)
+			},
+			validateZone: func(t *testing.T, zone *hivev1.DNSZone) {
+				assert.NotNil(t, zone.Status.Azure)
+				assert.NotNil(t, zone.Status.Azure.ZoneName)
+				assert.Equal(t, *zone.Status.Azure.ZoneName, "blah.example.com")
+				assert.Equal(t, zone.Status.NameServers, []string{"ns1.example.com", "ns2.example.com"}, "nameservers must be set in status")
+			},
+		},
+		{
+			name:    "Adopt existing zone, No ZoneName Set",
+			dnsZone: azureDNSZone(validDNSZoneWithoutID()),
+			setupAzureMock: func(expect *azuremock.MockClientMockRecorder) {
+				mockAzureZoneExists(expect)
+			},
+			validateZone: func(t *testing.T, zone *hivev1.DNSZone) {
+				assert.NotNil(t, zone.Status.Azure)
+				assert.NotNil(t, zone.Status.Azure.ZoneName)
+				assert.Equal(t, *zone.Status.Azure.ZoneName, "blah.example.com")
+				assert.Equal(t, zone.Status.NameServers, []string{"ns1.example.com", "ns2.example.com"}, "nameservers must be set in status")
+			},
+		},
+		{
+			name:    "Delete DNS zone",
+			dnsZone: azureDNSZone(validDNSZoneBeingDeleted()),
+			setupAzureMock: func(expect *azuremock.MockClientMockRecorder) {
+				mockAzureZoneExists(expect)
+				mockDeleteAzureZone(expect)
+			},
+			validateZone: func(t *testing.T, zone *hivev1.DNSZone) {
+				assert.False(t, controllerutils.HasFinalizer(zone, hivev1.FinalizerDNSZone))
+			},
+		},
+		{
+			name:    "Delete non-existent DNS zone",
+			dnsZone: azureDNSZone(validDNSZoneBeingDeleted()),
+			setupAzureMock: func(expect *azuremock.MockClientMockRecorder) {
+				mockAzureZoneDoesntExist(expect)
+			},
+			validateZone: func(t *testing.T, zone *hivev1.DNSZone) {
+				assert.False(t, controllerutils.HasFinalizer(zone, hivev1.FinalizerDNSZone))
+			},
+		},
+		{
+			name:            "Existing zone, link to parent, reachable SOA",
+			dnsZone:         azureDNSZone(validDNSZoneWithLinkToParent()),
+			soaLookupResult: true,
+			setupAzureMock: func(expect *azuremock.MockClientMockRecorder) {
+				mockAzureZoneExists(expect)
+			},
+			validateZone: func(t *testing.T, zone *hivev1.DNSZone) {
+				condition := controllerutils.FindDNSZoneCondition(zone.Status.Conditions, hivev1.ZoneAvailableDNSZoneCondition)
+				assert.NotNil(t, condition, "zone available condition should be set on dnszone")
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			mocks := setupDefaultMocks(t)
+
+			zr, _ := NewAzureActuator(
+				log.WithField("controller", controllerName),
+				validAzureSecret(),
+				tc.dnsZone,
+				fakeAzureClientBuilder(mocks.mockAzureClient),
+			)
+
+			r := ReconcileDNSZone{
+				Client: mocks.fakeKubeClient,
+				logger: zr.logger,
+				scheme: scheme.Scheme,
+			}
+
+			r.soaLookup = func(string, log.FieldLogger) (bool, error) {
+				return tc.soaLookupResult, nil
+			}
+
+			// This is necessary for the mocks to report failures like methods not being called an expected number of times.
+			defer mocks.mockCtrl.Finish()
+
+			setFakeDNSZoneInKube(mocks, tc.dnsZone)
+
+			if tc.setupAzureMock != nil {
+				tc.setupAzureMock(mocks.mockAzureClient.EXPECT())
+			}
+
+			// Act
+			_, err := r.reconcileDNSProvider(zr, tc.dnsZone)
+
+			// Assert
+			if tc.errorExpected {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			// Validate
+			zone := &hivev1.DNSZone{}
+			err = mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Namespace: tc.dnsZone.Namespace, Name: tc.dnsZone.Name}, zone)
+			if err != nil {
+				t.Fatalf("unexpected: %v", err)
+			}
+			if tc.validateZone != nil {
+				tc.validateZone(t, zone)
+			}
+		})
+	}
+}
+
+// TestReconcileDNSProviderForRFC2136 tests that ReconcileDNSProvider reacts properly under different reconciliation states
+// against a DNS server updated through RFC2136 dynamic updates.
+func TestReconcileDNSProviderForRFC2136(t *testing.T) {
+
+	log.SetLevel(log.DebugLevel)
+
+	cases := []struct {
+		name            string
+		dnsZone         *hivev1.DNSZone
+		hostedZones     map[string][]string
+		validateZone    func(*testing.T, *hivev1.DNSZone)
+		errorExpected   bool
+		soaLookupResult bool
+	}{
+		{
+			name:        "DNSZone without finalizer",
+			dnsZone:     validDNSZoneWithoutFinalizer(),
+			hostedZones: map[string][]string{"blah.example.com.": {"ns1.example.com."}},
+			validateZone: func(t *testing.T, zone *hivev1.DNSZone) {
+				assert.True(t, controllerutils.HasFinalizer(zone, hivev1.FinalizerDNSZone))
+			},
+		},
+		{
+			name:        "Adopt existing zone",
+			dnsZone:     validDNSZone(),
+			hostedZones: map[string][]string{"blah.example.com.": {"ns1.example.com.", "ns2.example.com."}},
+			validateZone: func(t *testing.T, zone *hivev1.DNSZone) {
+				assert.ElementsMatch(t, zone.Status.NameServers, []string{"ns1.example.com", "ns2.example.com"}, "nameservers must be set in status")
+			},
+		},
+		{
+			name:          "Zone not hosted on DNS server",
+			dnsZone:       validDNSZone(),
+			hostedZones:   map[string][]string{"example.com.": {"ns1.example.com."}},
+			errorExpected: true,
+			validateZone: func(t *testing.T, zone *hivev1.DNSZone) {
+				assert.Empty(t, zone.Status.NameServers)
+			},
+		},
+		{
+			name:        "Delete DNS zone",
+			dnsZone:     validDNSZoneBeingDeleted(),
+			hostedZones: map[string][]string{"blah.example.com.": {"ns1.example.com."}},
+			validateZone: func(t *testing.T, zone *hivev1.DNSZone) {
+				assert.False(t, controllerutils.HasFinalizer(zone, hivev1.FinalizerDNSZone))
+			},
+		},
+		{
+			name:            "Existing zone, link to parent, reachable SOA",
+			dnsZone:         validDNSZoneWithLinkToParent(),
+			hostedZones:     map[string][]string{"blah.example.com.": {"ns1.example.com."}},
+			soaLookupResult: true,
+			validateZone: func(t *testing.T, zone *hivev1.DNSZone) {
+				condition := controllerutils.FindDNSZoneCondition(zone.Status.Conditions, hivev1.ZoneAvailableDNSZoneCondition)
+				assert.NotNil(t, condition, "zone available condition should be set on dnszone")
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			mocks := setupDefaultMocks(t)
+			server := dnsserver.Start(t, tc.hostedZones)
+			dnsZone := rfc2136DNSZone(tc.dnsZone, server.Addr)
+			secret := validRFC2136Secret()
+			secret.Data["keyName"] = []byte(dnsserver.TSIGKeyName)
+			secret.Data["secret"] = []byte(dnsserver.TSIGSecret)
+
+			zr, err := NewRFC2136Actuator(
+				log.WithField("controller", controllerName),
+				secret,
+				dnsZone,
+				rfc2136client.NewClientFromSecret,
+			)
+			if err != nil {
+				t.Fatalf("unexpected: %v", err)
+			}
+
+			r := ReconcileDNSZone{
+				Client: mocks.fakeKubeClient,
+				logger: zr.logger,
+				scheme: scheme.Scheme,
+			}
+
+			r.soaLookup = func(string, log.FieldLogger) (bool, error) {
+				return tc.soaLookupResult, nil
+			}
+
+			setFakeDNSZoneInKube(mocks, dnsZone)
+
+			// Act
+			_, err = r.reconcileDNSProvider(zr, dnsZone)
+
+			// Assert
+			if tc.errorExpected {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			// Validate
+			zone := &hivev1.DNSZone{}
+			err = mocks.fakeKubeClient.Get(context.TODO(), types.NamespacedName{Namespace: dnsZone.Namespace, Name: dnsZone.Name}, zone)
+			if err != nil {
+				t.Fatalf("unexpected: %v", err)
+			}
+			if tc.validateZone != nil {
+				tc.validateZone(t, zone)
+			}
+		})
+	}
+}
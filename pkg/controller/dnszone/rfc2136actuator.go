@@ -0,0 +1,138 @@
+package dnszone
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/rfc2136client"
+)
+
+// RFC2136Actuator attempts to make the current state reflect the given desired state.
+// RFC2136 dynamic updates cannot create or delete zones, so the actuator only adopts zones that have been
+// configured on the DNS server ahead of time.
+type RFC2136Actuator struct {
+	// logger is the logger used for this controller
+	logger log.FieldLogger
+
+	// rfc2136Client is a utility for making it easy for controllers to interface with the DNS server
+	rfc2136Client rfc2136client.Client
+
+	// dnsZone is the DNSZone that represents the desired state.
+	dnsZone *hivev1.DNSZone
+
+	// nameServers are the name servers of the zone on the DNS server, nil if the server does not host the zone.
+	nameServers []string
+}
+
+type rfc2136ClientBuilderType func(server string, secret *corev1.Secret) (rfc2136client.Client, error)
+
+// NewRFC2136Actuator creates a new RFC2136Actuator object. A new RFC2136Actuator is expected to be created for each controller sync.
+func NewRFC2136Actuator(
+	logger log.FieldLogger,
+	secret *corev1.Secret,
+	dnsZone *hivev1.DNSZone,
+	rfc2136ClientBuilder rfc2136ClientBuilderType,
+) (*RFC2136Actuator, error) {
+	rfc2136Client, err := rfc2136ClientBuilder(dnsZone.Spec.RFC2136.Server, secret)
+	if err != nil {
+		logger.WithError(err).Error("Error creating RFC2136Client")
+		return nil, err
+	}
+
+	rfc2136Actuator := &RFC2136Actuator{
+		logger:        logger,
+		rfc2136Client: rfc2136Client,
+		dnsZone:       dnsZone,
+	}
+
+	return rfc2136Actuator, nil
+}
+
+// Ensure RFC2136Actuator implements the Actuator interface. This will fail at compile time when false.
+var _ Actuator = &RFC2136Actuator{}
+
+// Create implements the Create call of the actuator interface
+// RFC2136 dynamic updates cannot create zones, so the zone is looked up again in case it has been configured on the
+// DNS server since the last refresh, and adopted once the TSIG key is verified.
+func (a *RFC2136Actuator) Create() error {
+	if err := a.Refresh(); err != nil {
+		return err
+	}
+	if a.nameServers == nil {
+		return fmt.Errorf("zone %s is not hosted on DNS server %s: the zone must be configured on the DNS server since RFC2136 dynamic updates cannot create zones",
+			a.dnsZone.Spec.Zone, a.dnsZone.Spec.RFC2136.Server)
+	}
+	a.logger.WithField("zone", a.dnsZone.Spec.Zone).Info("Adopting zone configured on DNS server")
+	return a.verifyZone()
+}
+
+// Delete implements the Delete call of the actuator interface
+func (a *RFC2136Actuator) Delete() error {
+	// The zone was not created by hive, so it is left for the administrator of the DNS server to remove.
+	a.logger.WithField("zone", a.dnsZone.Spec.Zone).Info("RFC2136 dynamic updates cannot delete zones, leaving zone on DNS server")
+	return nil
+}
+
+// Exists implements the Exists call of the actuator interface
+func (a *RFC2136Actuator) Exists() (bool, error) {
+	return a.nameServers != nil, nil
+}
+
+// UpdateMetadata implements the UpdateMetadata call of the actuator interface
+func (a *RFC2136Actuator) UpdateMetadata() error {
+	// DNS zones do not carry any metadata, so the TSIG key is verified instead.
+	return a.verifyZone()
+}
+
+// verifyZone verifies that the TSIG key is accepted by the DNS server for the zone by transferring the zone.
+func (a *RFC2136Actuator) verifyZone() error {
+	if _, err := a.rfc2136Client.ListRecords(a.dnsZone.Spec.Zone); err != nil {
+		a.logger.WithField("zone", a.dnsZone.Spec.Zone).WithError(err).Error("Cannot transfer zone with TSIG key")
+		return errors.Wrapf(err, "could not transfer zone %s from DNS server %s with TSIG key", a.dnsZone.Spec.Zone, a.dnsZone.Spec.RFC2136.Server)
+	}
+	return nil
+}
+
+// ModifyStatus implements the ModifyStatus call of the actuator interface
+func (a *RFC2136Actuator) ModifyStatus() error {
+	// Nothing to do here since the zone is identified by its name alone.
+	return nil
+}
+
+// GetNameServers implements the GetNameServers call of the actuator interface
+func (a *RFC2136Actuator) GetNameServers() ([]string, error) {
+	if a.nameServers == nil {
+		return nil, errors.New("nameServers is unpopulated")
+	}
+
+	logger := a.logger.WithField("zone", a.dnsZone.Spec.Zone)
+	result := make([]string, len(a.nameServers))
+	for i, nameServer := range a.nameServers {
+		result[i] = strings.TrimSuffix(nameServer, ".")
+	}
+	logger.WithField("nameservers", result).Debug("found zone name servers")
+	return result, nil
+}
+
+// Refresh implements the Refresh call of the actuator interface
+func (a *RFC2136Actuator) Refresh() error {
+	logger := a.logger.WithField("zone", a.dnsZone.Spec.Zone).WithField("server", a.dnsZone.Spec.RFC2136.Server)
+	logger.Debug("Fetching zone name servers")
+	nameServers, err := a.rfc2136Client.GetNameServers(a.dnsZone.Spec.Zone)
+	if err != nil {
+		logger.WithError(err).Error("Cannot get zone name servers")
+		return err
+	}
+
+	if nameServers == nil {
+		logger.Debug("Zone not hosted on DNS server, clearing out the cached name servers")
+	}
+	a.nameServers = nameServers
+	return nil
+}
@@ -0,0 +1,153 @@
+package dnszone
+
+import (
+	"errors"
+	"testing"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// TestNewRFC2136Actuator tests that a new RFC2136Actuator object can be created.
+func TestNewRFC2136Actuator(t *testing.T) {
+	cases := []struct {
+		name    string
+		dnsZone *hivev1.DNSZone
+		secret  *corev1.Secret
+	}{
+		{
+			name:    "Successfully create new zone",
+			dnsZone: rfc2136DNSZone(validDNSZone(), "127.0.0.1"),
+			secret:  validRFC2136Secret(),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			mocks := setupDefaultMocks(t)
+			expectedRFC2136Actuator := &RFC2136Actuator{
+				logger:  log.WithField("controller", controllerName),
+				dnsZone: tc.dnsZone,
+			}
+
+			// Act
+			zr, err := NewRFC2136Actuator(
+				expectedRFC2136Actuator.logger,
+				tc.secret,
+				tc.dnsZone,
+				fakeRFC2136ClientBuilder(mocks.mockRFC2136Client),
+			)
+			expectedRFC2136Actuator.rfc2136Client = zr.rfc2136Client // Function pointers can't be compared reliably. Don't compare.
+
+			// Assert
+			assert.Nil(t, err)
+			assert.NotNil(t, zr.rfc2136Client)
+			assert.Equal(t, expectedRFC2136Actuator, zr)
+		})
+	}
+}
+
+// TestRFC2136ActuatorGetNameServers tests that the name servers of the zone are returned without trailing dots.
+func TestRFC2136ActuatorGetNameServers(t *testing.T) {
+	mocks := setupDefaultMocks(t)
+	defer mocks.mockCtrl.Finish()
+	mocks.mockRFC2136Client.EXPECT().GetNameServers("blah.example.com").
+		Return([]string{"ns1.example.com.", "ns2.example.com."}, nil).
+		Times(1)
+
+	zr, err := NewRFC2136Actuator(
+		log.WithField("controller", controllerName),
+		validRFC2136Secret(),
+		rfc2136DNSZone(validDNSZone(), "127.0.0.1"),
+		fakeRFC2136ClientBuilder(mocks.mockRFC2136Client),
+	)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_, err = zr.GetNameServers()
+	assert.Error(t, err, "expected error before refresh")
+
+	assert.NoError(t, zr.Refresh())
+	exists, err := zr.Exists()
+	assert.NoError(t, err)
+	assert.True(t, exists)
+
+	nameServers, err := zr.GetNameServers()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ns1.example.com", "ns2.example.com"}, nameServers)
+}
+
+// TestRFC2136ActuatorCreate tests that zones configured on the DNS server since the last refresh are adopted once the
+// TSIG key is verified.
+func TestRFC2136ActuatorCreate(t *testing.T) {
+	cases := []struct {
+		name          string
+		nameServers   []string
+		transferErr   error
+		expectError   bool
+		expectedNames []string
+	}{
+		{
+			name:          "zone configured on DNS server",
+			nameServers:   []string{"ns1.example.com."},
+			expectedNames: []string{"ns1.example.com"},
+		},
+		{
+			name:        "zone not hosted on DNS server",
+			expectError: true,
+		},
+		{
+			name:        "TSIG key not accepted",
+			nameServers: []string{"ns1.example.com."},
+			transferErr: errors.New("dns: bad signature"),
+			expectError: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			mocks := setupDefaultMocks(t)
+			defer mocks.mockCtrl.Finish()
+			mocks.mockRFC2136Client.EXPECT().GetNameServers("blah.example.com").Return(tc.nameServers, nil).Times(1)
+			if tc.nameServers != nil {
+				mocks.mockRFC2136Client.EXPECT().ListRecords("blah.example.com").Return(nil, tc.transferErr).Times(1)
+			}
+
+			zr, err := NewRFC2136Actuator(
+				log.WithField("controller", controllerName),
+				validRFC2136Secret(),
+				rfc2136DNSZone(validDNSZone(), "127.0.0.1"),
+				fakeRFC2136ClientBuilder(mocks.mockRFC2136Client),
+			)
+			require.NoError(t, err)
+
+			err = zr.Create()
+			if tc.expectError {
+				assert.Error(t, err, "expected error creating zone")
+				return
+			}
+			assert.NoError(t, err, "unexpected error creating zone")
+			nameServers, err := zr.GetNameServers()
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedNames, nameServers)
+		})
+	}
+}
+
+// rfc2136DNSZone replaces the AWS-specific settings of the given DNSZone with RFC2136-specific settings.
+func rfc2136DNSZone(zone *hivev1.DNSZone, server string) *hivev1.DNSZone {
+	zone.Spec.AWS = nil
+	zone.Spec.RFC2136 = &hivev1.RFC2136DNSZoneSpec{
+		Server: server,
+		TSIGKeySecretRef: corev1.LocalObjectReference{
+			Name: "somesecret",
+		},
+	}
+	zone.Status.AWS = nil
+	return zone
+}
@@ -13,12 +13,16 @@ import (
 
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
 	awsclient "github.com/openshift/hive/pkg/awsclient"
+	azureclient "github.com/openshift/hive/pkg/azureclient"
 	gcpclient "github.com/openshift/hive/pkg/gcpclient"
+	"github.com/openshift/hive/pkg/rfc2136client"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/golang/mock/gomock"
 	mockaws "github.com/openshift/hive/pkg/awsclient/mock"
+	mockazure "github.com/openshift/hive/pkg/azureclient/mock"
 	mockgcp "github.com/openshift/hive/pkg/gcpclient/mock"
+	mockrfc2136 "github.com/openshift/hive/pkg/rfc2136client/mock"
 )
 
 var (
@@ -83,6 +87,31 @@ var (
 		}
 	}
 
+	validAzureSecret = func() *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "somesecret",
+				Namespace: "ns",
+			},
+			Data: map[string][]byte{
+				"osServicePrincipal.json": []byte("notrealsecrettoken"),
+			},
+		}
+	}
+
+	validRFC2136Secret = func() *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "somesecret",
+				Namespace: "ns",
+			},
+			Data: map[string][]byte{
+				"keyName": []byte("notrealkeyname"),
+				"secret":  []byte("notrealsecret"),
+			},
+		}
+	}
+
 	validDNSZoneWithLinkToParent = func() *hivev1.DNSZone {
 		zone := validDNSZone()
 		zone.Spec.LinkToParentDomain = true
@@ -127,10 +156,12 @@ var (
 )
 
 type mocks struct {
-	fakeKubeClient client.Client
-	mockCtrl       *gomock.Controller
-	mockAWSClient  *mockaws.MockClient
-	mockGCPClient  *mockgcp.MockClient
+	fakeKubeClient    client.Client
+	mockCtrl          *gomock.Controller
+	mockAWSClient     *mockaws.MockClient
+	mockGCPClient     *mockgcp.MockClient
+	mockAzureClient   *mockazure.MockClient
+	mockRFC2136Client *mockrfc2136.MockClient
 }
 
 // setupDefaultMocks is an easy way to setup all of the default mocks
@@ -142,6 +173,8 @@ func setupDefaultMocks(t *testing.T) *mocks {
 
 	mocks.mockAWSClient = mockaws.NewMockClient(mocks.mockCtrl)
 	mocks.mockGCPClient = mockgcp.NewMockClient(mocks.mockCtrl)
+	mocks.mockAzureClient = mockazure.NewMockClient(mocks.mockCtrl)
+	mocks.mockRFC2136Client = mockrfc2136.NewMockClient(mocks.mockCtrl)
 
 	return mocks
 }
@@ -158,6 +191,18 @@ func fakeGCPClientBuilder(mockGCPClient *mockgcp.MockClient) gcpClientBuilderTyp
 	}
 }
 
+func fakeAzureClientBuilder(mockAzureClient *mockazure.MockClient) azureClientBuilderType {
+	return func(secret *corev1.Secret) (azureclient.Client, error) {
+		return mockAzureClient, nil
+	}
+}
+
+func fakeRFC2136ClientBuilder(mockRFC2136Client *mockrfc2136.MockClient) rfc2136ClientBuilderType {
+	return func(server string, secret *corev1.Secret) (rfc2136client.Client, error) {
+		return mockRFC2136Client, nil
+	}
+}
+
 // setFakeDNSZoneInKube is an easy way to register a dns zone object with kube.
 func setFakeDNSZoneInKube(mocks *mocks, dnsZone *hivev1.DNSZone) error {
 	return mocks.fakeKubeClient.Create(context.TODO(), dnsZone)
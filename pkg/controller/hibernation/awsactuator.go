@@ -0,0 +1,164 @@
+package hibernation
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/awsclient"
+)
+
+const (
+	kubernetesKeyPrefix = "kubernetes.io/cluster/"
+)
+
+var (
+	awsRunningStates    = []string{ec2.InstanceStateNamePending, ec2.InstanceStateNameRunning}
+	awsStoppedStates    = []string{ec2.InstanceStateNameStopped}
+	awsNotRunningStates = []string{ec2.InstanceStateNameStopping, ec2.InstanceStateNameStopped, ec2.InstanceStateNamePending}
+	awsNotStoppedStates = []string{ec2.InstanceStateNamePending, ec2.InstanceStateNameRunning, ec2.InstanceStateNameStopping}
+)
+
+type awsActuator struct {
+	// awsClientFn is the function to build an AWS client, here for testing
+	awsClientFn func(*hivev1.ClusterDeployment, client.Client, log.FieldLogger) (awsclient.Client, error)
+}
+
+var _ HibernationActuator = &awsActuator{}
+
+func newAWSActuator() *awsActuator {
+	return &awsActuator{
+		awsClientFn: getAWSClient,
+	}
+}
+
+// CanHandle returns true if the actuator can handle a particular ClusterDeployment
+func (a *awsActuator) CanHandle(cd *hivev1.ClusterDeployment) bool {
+	return cd.Spec.Platform.AWS != nil
+}
+
+// StopMachines will stop machines belonging to the given ClusterDeployment
+func (a *awsActuator) StopMachines(cd *hivev1.ClusterDeployment, c client.Client, logger log.FieldLogger) error {
+	logger = logger.WithField("cloud", "aws")
+	awsClient, err := a.awsClientFn(cd, c, logger)
+	if err != nil {
+		return err
+	}
+	instanceIDs, err := getAWSClusterInstanceIDs(cd, awsClient, awsRunningStates, logger)
+	if err != nil {
+		return err
+	}
+	if len(instanceIDs) == 0 {
+		logger.Warning("No instances were found to stop")
+		return nil
+	}
+	logger.WithField("instanceIDs", aws.StringValueSlice(instanceIDs)).Info("Stopping cluster instances")
+	_, err = awsClient.StopInstances(&ec2.StopInstancesInput{
+		InstanceIds: instanceIDs,
+	})
+	if err != nil {
+		logger.WithError(err).Error("failed to stop instances")
+	}
+	return err
+}
+
+// StartMachines will start machines belonging to the given ClusterDeployment
+func (a *awsActuator) StartMachines(cd *hivev1.ClusterDeployment, c client.Client, logger log.FieldLogger) error {
+	logger = logger.WithField("cloud", "aws")
+	awsClient, err := a.awsClientFn(cd, c, logger)
+	if err != nil {
+		return err
+	}
+	instanceIDs, err := getAWSClusterInstanceIDs(cd, awsClient, awsStoppedStates, logger)
+	if err != nil {
+		return err
+	}
+	if len(instanceIDs) == 0 {
+		logger.Info("No instances were found to start")
+		return nil
+	}
+	logger.WithField("instanceIDs", aws.StringValueSlice(instanceIDs)).Info("Starting cluster instances")
+	_, err = awsClient.StartInstances(&ec2.StartInstancesInput{
+		InstanceIds: instanceIDs,
+	})
+	if err != nil {
+		logger.WithError(err).Error("failed to start instances")
+	}
+	return err
+}
+
+// MachinesRunning will return true if the machines associated with the given
+// ClusterDeployment are in a running state.
+func (a *awsActuator) MachinesRunning(cd *hivev1.ClusterDeployment, c client.Client, logger log.FieldLogger) (bool, error) {
+	logger = logger.WithField("cloud", "aws")
+	awsClient, err := a.awsClientFn(cd, c, logger)
+	if err != nil {
+		return false, err
+	}
+	instanceIDs, err := getAWSClusterInstanceIDs(cd, awsClient, awsNotRunningStates, logger)
+	if err != nil {
+		return false, err
+	}
+	return len(instanceIDs) == 0, nil
+}
+
+// MachinesStopped will return true if the machines associated with the given
+// ClusterDeployment are in a stopped state.
+func (a *awsActuator) MachinesStopped(cd *hivev1.ClusterDeployment, c client.Client, logger log.FieldLogger) (bool, error) {
+	logger = logger.WithField("cloud", "aws")
+	awsClient, err := a.awsClientFn(cd, c, logger)
+	if err != nil {
+		return false, err
+	}
+	instanceIDs, err := getAWSClusterInstanceIDs(cd, awsClient, awsNotStoppedStates, logger)
+	if err != nil {
+		return false, err
+	}
+	return len(instanceIDs) == 0, nil
+}
+
+func getAWSClient(cd *hivev1.ClusterDeployment, c client.Client, logger log.FieldLogger) (awsclient.Client, error) {
+	awsClient, err := awsclient.NewClient(c, cd.Spec.Platform.AWS.CredentialsSecretRef.Name, cd.Namespace, cd.Spec.Platform.AWS.Region)
+	if err != nil {
+		logger.WithError(err).Error("failed to get AWS client")
+	}
+	return awsClient, err
+}
+
+// getAWSClusterInstanceIDs returns the IDs of the instances tagged as owned by the cluster's infra ID
+// that are in one of the given states.
+func getAWSClusterInstanceIDs(cd *hivev1.ClusterDeployment, awsClient awsclient.Client, states []string, logger log.FieldLogger) ([]*string, error) {
+	infraID := cd.Spec.ClusterMetadata.InfraID
+	logger = logger.WithField("infraID", infraID)
+	logger.Debug("listing cluster instances")
+	out, err := awsClient.DescribeInstances(&ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("tag:" + kubernetesKeyPrefix + infraID),
+				Values: []*string{aws.String("owned")},
+			},
+			{
+				Name:   aws.String("instance-state-name"),
+				Values: aws.StringSlice(states),
+			},
+		},
+	})
+	if err != nil {
+		logger.WithError(err).Error("failed to list instances")
+		return nil, errors.Wrap(err, "failed to list instances")
+	}
+	var result []*string
+	for _, reservation := range out.Reservations {
+		for _, instance := range reservation.Instances {
+			if instance.InstanceId != nil {
+				result = append(result, instance.InstanceId)
+			}
+		}
+	}
+	logger.WithField("count", len(result)).WithField("states", states).Debug("result of listing instances")
+	return result, nil
+}
@@ -0,0 +1,135 @@
+package hibernation
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/golang/mock/gomock"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/awsclient"
+	mockaws "github.com/openshift/hive/pkg/awsclient/mock"
+)
+
+func TestAWSActuator(t *testing.T) {
+	tests := []struct {
+		name          string
+		testFunc      func(*awsActuator) (bool, error)
+		mockAWSClient func(*mockaws.MockClient)
+		expectResult  bool
+	}{
+		{
+			name: "stop running instances",
+			testFunc: func(a *awsActuator) (bool, error) {
+				return false, a.StopMachines(testClusterDeployment(), nil, log.New())
+			},
+			mockAWSClient: func(c *mockaws.MockClient) {
+				mockDescribeInstances(c, awsRunningStates, "i-1", "i-2")
+				c.EXPECT().StopInstances(&ec2.StopInstancesInput{
+					InstanceIds: aws.StringSlice([]string{"i-1", "i-2"}),
+				}).Return(&ec2.StopInstancesOutput{}, nil)
+			},
+		},
+		{
+			name: "stop with no running instances",
+			testFunc: func(a *awsActuator) (bool, error) {
+				return false, a.StopMachines(testClusterDeployment(), nil, log.New())
+			},
+			mockAWSClient: func(c *mockaws.MockClient) {
+				mockDescribeInstances(c, awsRunningStates)
+			},
+		},
+		{
+			name: "start stopped instances",
+			testFunc: func(a *awsActuator) (bool, error) {
+				return false, a.StartMachines(testClusterDeployment(), nil, log.New())
+			},
+			mockAWSClient: func(c *mockaws.MockClient) {
+				mockDescribeInstances(c, awsStoppedStates, "i-1")
+				c.EXPECT().StartInstances(&ec2.StartInstancesInput{
+					InstanceIds: aws.StringSlice([]string{"i-1"}),
+				}).Return(&ec2.StartInstancesOutput{}, nil)
+			},
+		},
+		{
+			name: "machines running",
+			testFunc: func(a *awsActuator) (bool, error) {
+				return a.MachinesRunning(testClusterDeployment(), nil, log.New())
+			},
+			mockAWSClient: func(c *mockaws.MockClient) {
+				mockDescribeInstances(c, awsNotRunningStates)
+			},
+			expectResult: true,
+		},
+		{
+			name: "machines not running",
+			testFunc: func(a *awsActuator) (bool, error) {
+				return a.MachinesRunning(testClusterDeployment(), nil, log.New())
+			},
+			mockAWSClient: func(c *mockaws.MockClient) {
+				mockDescribeInstances(c, awsNotRunningStates, "i-1")
+			},
+		},
+		{
+			name: "machines stopped",
+			testFunc: func(a *awsActuator) (bool, error) {
+				return a.MachinesStopped(testClusterDeployment(), nil, log.New())
+			},
+			mockAWSClient: func(c *mockaws.MockClient) {
+				mockDescribeInstances(c, awsNotStoppedStates)
+			},
+			expectResult: true,
+		},
+		{
+			name: "machines not stopped",
+			testFunc: func(a *awsActuator) (bool, error) {
+				return a.MachinesStopped(testClusterDeployment(), nil, log.New())
+			},
+			mockAWSClient: func(c *mockaws.MockClient) {
+				mockDescribeInstances(c, awsNotStoppedStates, "i-1")
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+			awsClient := mockaws.NewMockClient(mockCtrl)
+			test.mockAWSClient(awsClient)
+			actuator := &awsActuator{
+				awsClientFn: func(*hivev1.ClusterDeployment, client.Client, log.FieldLogger) (awsclient.Client, error) {
+					return awsClient, nil
+				},
+			}
+			result, err := test.testFunc(actuator)
+			require.NoError(t, err, "unexpected error")
+			assert.Equal(t, test.expectResult, result, "unexpected result")
+		})
+	}
+}
+
+func mockDescribeInstances(c *mockaws.MockClient, states []string, instanceIDs ...string) {
+	reservation := &ec2.Reservation{}
+	for _, id := range instanceIDs {
+		reservation.Instances = append(reservation.Instances, &ec2.Instance{InstanceId: aws.String(id)})
+	}
+	c.EXPECT().DescribeInstances(&ec2.DescribeInstancesInput{
+		Filters: []*ec2.Filter{
+			{
+				Name:   aws.String("tag:kubernetes.io/cluster/test-infra-id"),
+				Values: aws.StringSlice([]string{"owned"}),
+			},
+			{
+				Name:   aws.String("instance-state-name"),
+				Values: aws.StringSlice(states),
+			},
+		},
+	}).Return(&ec2.DescribeInstancesOutput{Reservations: []*ec2.Reservation{reservation}}, nil)
+}
@@ -0,0 +1,146 @@
+package hibernation
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	certsv1beta1 "k8s.io/api/certificates/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	nodeUserPrefix          = "system:node:"
+	nodeBootstrapperUser    = "system:serviceaccount:openshift-machine-config-operator:node-bootstrapper"
+	csrApprovalReason       = "HiveHibernationApprove"
+	csrApprovalMessage      = "This CSR was approved by the Hive hibernation controller"
+	certificateRequestBlock = "CERTIFICATE REQUEST"
+)
+
+// remoteClusterClient is the subset of operations on the remote cluster that the hibernation
+// controller needs in order to bring nodes back after a cluster resumes.
+type remoteClusterClient interface {
+	ListNodes() ([]corev1.Node, error)
+	ListCSRs() ([]certsv1beta1.CertificateSigningRequest, error)
+	ApproveCSR(*certsv1beta1.CertificateSigningRequest) error
+}
+
+type kubeRemoteClusterClient struct {
+	kubeClient kubernetes.Interface
+}
+
+func (c *kubeRemoteClusterClient) ListNodes() ([]corev1.Node, error) {
+	nodes, err := c.kubeClient.CoreV1().Nodes().List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return nodes.Items, nil
+}
+
+func (c *kubeRemoteClusterClient) ListCSRs() ([]certsv1beta1.CertificateSigningRequest, error) {
+	csrs, err := c.kubeClient.CertificatesV1beta1().CertificateSigningRequests().List(metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return csrs.Items, nil
+}
+
+func (c *kubeRemoteClusterClient) ApproveCSR(csr *certsv1beta1.CertificateSigningRequest) error {
+	_, err := c.kubeClient.CertificatesV1beta1().CertificateSigningRequests().UpdateApproval(csr)
+	return err
+}
+
+// approveNodeCSRs approves pending CSRs for kubelets of nodes that already exist in the cluster. Kubelet
+// certificates can expire while a cluster is hibernating, in which case the kubelet requests a new client
+// certificate through the node bootstrapper and a new serving certificate as the node itself.
+func approveNodeCSRs(remoteClient remoteClusterClient, nodes []corev1.Node, logger log.FieldLogger) error {
+	csrs, err := remoteClient.ListCSRs()
+	if err != nil {
+		logger.WithError(err).Error("failed to list CSRs")
+		return err
+	}
+	nodeNames := sets.NewString()
+	for _, node := range nodes {
+		nodeNames.Insert(node.Name)
+	}
+	for i := range csrs {
+		csr := &csrs[i]
+		csrLogger := logger.WithField("csr", csr.Name)
+		if !isPendingCSR(csr) {
+			continue
+		}
+		if err := validateNodeCSR(csr, nodeNames); err != nil {
+			csrLogger.WithError(err).Debug("not approving CSR")
+			continue
+		}
+		csr.Status.Conditions = append(csr.Status.Conditions, certsv1beta1.CertificateSigningRequestCondition{
+			Type:           certsv1beta1.CertificateApproved,
+			Reason:         csrApprovalReason,
+			Message:        csrApprovalMessage,
+			LastUpdateTime: metav1.Now(),
+		})
+		csrLogger.Info("approving CSR")
+		if err := remoteClient.ApproveCSR(csr); err != nil {
+			csrLogger.WithError(err).Error("failed to approve CSR")
+			return err
+		}
+	}
+	return nil
+}
+
+func isPendingCSR(csr *certsv1beta1.CertificateSigningRequest) bool {
+	for _, cond := range csr.Status.Conditions {
+		if cond.Type == certsv1beta1.CertificateApproved || cond.Type == certsv1beta1.CertificateDenied {
+			return false
+		}
+	}
+	return true
+}
+
+// validateNodeCSR returns an error if the CSR is not a kubelet CSR for one of the given nodes.
+func validateNodeCSR(csr *certsv1beta1.CertificateSigningRequest, nodeNames sets.String) error {
+	block, _ := pem.Decode(csr.Spec.Request)
+	if block == nil || block.Type != certificateRequestBlock {
+		return fmt.Errorf("CSR does not contain a PEM encoded certificate request")
+	}
+	request, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		return err
+	}
+	commonName := request.Subject.CommonName
+	if !strings.HasPrefix(commonName, nodeUserPrefix) {
+		return fmt.Errorf("CSR common name %q is not a node", commonName)
+	}
+	if nodeName := strings.TrimPrefix(commonName, nodeUserPrefix); !nodeNames.Has(nodeName) {
+		return fmt.Errorf("CSR is for unknown node %q", nodeName)
+	}
+	switch csr.Spec.Username {
+	case nodeBootstrapperUser, commonName:
+		return nil
+	default:
+		return fmt.Errorf("CSR requested by unexpected user %q", csr.Spec.Username)
+	}
+}
+
+// nodesReady returns true if all of the given nodes have a true Ready condition.
+func nodesReady(nodes []corev1.Node) bool {
+	for _, node := range nodes {
+		ready := false
+		for _, cond := range node.Status.Conditions {
+			if cond.Type == corev1.NodeReady {
+				ready = cond.Status == corev1.ConditionTrue
+				break
+			}
+		}
+		if !ready {
+			return false
+		}
+	}
+	return true
+}
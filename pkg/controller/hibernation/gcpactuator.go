@@ -0,0 +1,166 @@
+package hibernation
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	compute "google.golang.org/api/compute/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/gcpclient"
+)
+
+var (
+	gcpRunningStates    = sets.NewString("RUNNING", "PROVISIONING", "STAGING")
+	gcpStoppedStates    = sets.NewString("TERMINATED")
+	gcpNotRunningStates = sets.NewString("PROVISIONING", "STAGING", "STOPPING", "TERMINATED")
+	gcpNotStoppedStates = sets.NewString("PROVISIONING", "STAGING", "RUNNING", "STOPPING")
+)
+
+type gcpActuator struct {
+	// gcpClientFn is the function to build a GCP client, here for testing
+	gcpClientFn func(*hivev1.ClusterDeployment, client.Client, log.FieldLogger) (gcpclient.Client, error)
+}
+
+var _ HibernationActuator = &gcpActuator{}
+
+func newGCPActuator() *gcpActuator {
+	return &gcpActuator{
+		gcpClientFn: getGCPClient,
+	}
+}
+
+// CanHandle returns true if the actuator can handle a particular ClusterDeployment
+func (a *gcpActuator) CanHandle(cd *hivev1.ClusterDeployment) bool {
+	return cd.Spec.Platform.GCP != nil
+}
+
+// StopMachines will stop machines belonging to the given ClusterDeployment
+func (a *gcpActuator) StopMachines(cd *hivev1.ClusterDeployment, c client.Client, logger log.FieldLogger) error {
+	logger = logger.WithField("cloud", "gcp")
+	gcpClient, err := a.gcpClientFn(cd, c, logger)
+	if err != nil {
+		return err
+	}
+	instances, err := getGCPClusterInstances(cd, gcpClient, gcpRunningStates, logger)
+	if err != nil {
+		return err
+	}
+	if len(instances) == 0 {
+		logger.Warning("No instances were found to stop")
+		return nil
+	}
+	for _, instance := range instances {
+		logger.WithField("instance", instance.Name).Info("Stopping cluster instance")
+		if err := gcpClient.StopInstance(instance); err != nil {
+			logger.WithError(err).WithField("instance", instance.Name).Error("failed to stop instance")
+			return err
+		}
+	}
+	return nil
+}
+
+// StartMachines will start machines belonging to the given ClusterDeployment
+func (a *gcpActuator) StartMachines(cd *hivev1.ClusterDeployment, c client.Client, logger log.FieldLogger) error {
+	logger = logger.WithField("cloud", "gcp")
+	gcpClient, err := a.gcpClientFn(cd, c, logger)
+	if err != nil {
+		return err
+	}
+	instances, err := getGCPClusterInstances(cd, gcpClient, gcpStoppedStates, logger)
+	if err != nil {
+		return err
+	}
+	if len(instances) == 0 {
+		logger.Info("No instances were found to start")
+		return nil
+	}
+	for _, instance := range instances {
+		logger.WithField("instance", instance.Name).Info("Starting cluster instance")
+		if err := gcpClient.StartInstance(instance); err != nil {
+			logger.WithError(err).WithField("instance", instance.Name).Error("failed to start instance")
+			return err
+		}
+	}
+	return nil
+}
+
+// MachinesRunning will return true if the machines associated with the given
+// ClusterDeployment are in a running state.
+func (a *gcpActuator) MachinesRunning(cd *hivev1.ClusterDeployment, c client.Client, logger log.FieldLogger) (bool, error) {
+	logger = logger.WithField("cloud", "gcp")
+	gcpClient, err := a.gcpClientFn(cd, c, logger)
+	if err != nil {
+		return false, err
+	}
+	instances, err := getGCPClusterInstances(cd, gcpClient, gcpNotRunningStates, logger)
+	if err != nil {
+		return false, err
+	}
+	return len(instances) == 0, nil
+}
+
+// MachinesStopped will return true if the machines associated with the given
+// ClusterDeployment are in a stopped state.
+func (a *gcpActuator) MachinesStopped(cd *hivev1.ClusterDeployment, c client.Client, logger log.FieldLogger) (bool, error) {
+	logger = logger.WithField("cloud", "gcp")
+	gcpClient, err := a.gcpClientFn(cd, c, logger)
+	if err != nil {
+		return false, err
+	}
+	instances, err := getGCPClusterInstances(cd, gcpClient, gcpNotStoppedStates, logger)
+	if err != nil {
+		return false, err
+	}
+	return len(instances) == 0, nil
+}
+
+func getGCPClient(cd *hivev1.ClusterDeployment, c client.Client, logger log.FieldLogger) (gcpclient.Client, error) {
+	secret := &corev1.Secret{}
+	if err := c.Get(
+		context.TODO(),
+		client.ObjectKey{Namespace: cd.Namespace, Name: cd.Spec.Platform.GCP.CredentialsSecretRef.Name},
+		secret,
+	); err != nil {
+		logger.WithError(err).Error("failed to get GCP credentials secret")
+		return nil, errors.Wrap(err, "failed to get GCP credentials secret")
+	}
+	gcpClient, err := gcpclient.NewClientFromSecret(secret)
+	if err != nil {
+		logger.WithError(err).Error("failed to get GCP client")
+	}
+	return gcpClient, err
+}
+
+// getGCPClusterInstances returns the instances labeled as owned by the cluster's infra ID
+// that are in one of the given states.
+func getGCPClusterInstances(cd *hivev1.ClusterDeployment, gcpClient gcpclient.Client, states sets.String, logger log.FieldLogger) ([]*compute.Instance, error) {
+	infraID := cd.Spec.ClusterMetadata.InfraID
+	logger = logger.WithField("infraID", infraID)
+	logger.Debug("listing cluster instances")
+	var instances []*compute.Instance
+	err := gcpClient.ListComputeInstances(gcpclient.ListComputeInstancesOptions{
+		Filter: fmt.Sprintf("labels.kubernetes-io-cluster-%s = \"owned\"", infraID),
+	}, func(list *compute.InstanceAggregatedList) error {
+		for _, scopedList := range list.Items {
+			for _, instance := range scopedList.Instances {
+				if states.Has(instance.Status) {
+					instances = append(instances, instance)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		logger.WithError(err).Error("failed to list instances")
+		return nil, errors.Wrap(err, "failed to list instances")
+	}
+	logger.WithField("count", len(instances)).WithField("states", states.List()).Debug("result of listing instances")
+	return instances, nil
+}
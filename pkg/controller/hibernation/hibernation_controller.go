@@ -0,0 +1,298 @@
+// Package hibernation provides a controller which stops and starts the machines of a cluster
+// according to the power state requested in its ClusterDeployment.
+package hibernation
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	hivemetrics "github.com/openshift/hive/pkg/controller/metrics"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+	"github.com/openshift/hive/pkg/remoteclient"
+)
+
+const (
+	controllerName = "hibernation"
+
+	// stateCheckInterval is the time interval for polling
+	// whether a cluster's machines are stopped or are running
+	stateCheckInterval = 30 * time.Second
+
+	stoppingReason    = "Stopping"
+	hibernatingReason = "Hibernating"
+	resumingReason    = "Resuming"
+	runningReason     = "Running"
+	unsupportedReason = "Unsupported"
+)
+
+// Add creates a new Hibernation Controller and adds it to the Manager with default RBAC. The Manager will set fields on the
+// Controller and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	return AddToManager(mgr, NewReconciler(mgr))
+}
+
+// NewReconciler returns a new reconcile.Reconciler
+func NewReconciler(mgr manager.Manager) reconcile.Reconciler {
+	r := &ReconcileHibernation{
+		Client: controllerutils.NewClientWithMetricsOrDie(mgr, controllerName),
+		scheme: mgr.GetScheme(),
+		logger: log.WithField("controller", controllerName),
+		actuators: []HibernationActuator{
+			newAWSActuator(),
+			newGCPActuator(),
+		},
+	}
+	r.remoteClientFn = func(cd *hivev1.ClusterDeployment) (remoteClusterClient, error) {
+		kubeClient, err := remoteclient.NewBuilder(r.Client, cd, controllerName).BuildKubeClient()
+		if err != nil {
+			return nil, err
+		}
+		return &kubeRemoteClusterClient{kubeClient: kubeClient}, nil
+	}
+	return r
+}
+
+// AddToManager adds a new Controller to mgr with r as the reconcile.Reconciler
+func AddToManager(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New("hibernation-controller", mgr, controller.Options{Reconciler: controllerutils.NewShardedReconciler(r), MaxConcurrentReconciles: controllerutils.GetConcurrentReconciles(controllerName)})
+	if err != nil {
+		return err
+	}
+
+	if err := controllerutils.SetQueueRateLimiter(c, controllerName); err != nil {
+		return err
+	}
+
+	// Watch for changes to ClusterDeployment
+	err = c.Watch(&source.Kind{Type: &hivev1.ClusterDeployment{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var _ reconcile.Reconciler = &ReconcileHibernation{}
+
+// ReconcileHibernation reconciles the power state of the cluster of a ClusterDeployment object
+type ReconcileHibernation struct {
+	client.Client
+	scheme *runtime.Scheme
+
+	logger log.FieldLogger
+
+	// actuators are the cloud specific implementations for stopping and starting machines
+	actuators []HibernationActuator
+
+	// remoteClientFn is a function pointer to the function that builds a client for the
+	// remote cluster's API server
+	remoteClientFn func(cd *hivev1.ClusterDeployment) (remoteClusterClient, error)
+}
+
+// Reconcile stops or starts the machines of a cluster to match the power state requested in the ClusterDeployment.
+func (r *ReconcileHibernation) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	start := time.Now()
+	cdLog := r.logger.WithFields(log.Fields{
+		"clusterDeployment": request.Name,
+		"namespace":         request.Namespace,
+	})
+
+	// For logging, we need to see when the reconciliation loop starts and ends.
+	cdLog.Info("reconciling cluster deployment")
+	defer func() {
+		dur := time.Since(start)
+		hivemetrics.MetricControllerReconcileTime.WithLabelValues(controllerName).Observe(dur.Seconds())
+		cdLog.WithField("elapsed", dur).Info("reconcile complete")
+	}()
+
+	cd := &hivev1.ClusterDeployment{}
+	err := r.Get(context.TODO(), request.NamespacedName, cd)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+
+		// Error reading the object - requeue the request
+		cdLog.WithError(err).Error("error looking up cluster deployment")
+		return reconcile.Result{}, err
+	}
+
+	// If the clusterdeployment is deleted, do not reconcile.
+	if cd.DeletionTimestamp != nil {
+		cdLog.Debug("cluster has deletion timestamp")
+		return reconcile.Result{}, nil
+	}
+
+	if !cd.Spec.Installed {
+		cdLog.Debug("cluster installation is not complete")
+		return reconcile.Result{}, nil
+	}
+
+	if cd.Spec.ClusterMetadata == nil {
+		cdLog.Error("installed cluster with no cluster metadata")
+		return reconcile.Result{}, nil
+	}
+
+	if cd.Spec.PowerState == hivev1.HibernatingClusterPowerState {
+		return r.reconcileHibernating(cd, cdLog)
+	}
+	return r.reconcileRunning(cd, cdLog)
+}
+
+func (r *ReconcileHibernation) reconcileHibernating(cd *hivev1.ClusterDeployment, cdLog log.FieldLogger) (reconcile.Result, error) {
+	actuator := r.getActuator(cd)
+	if actuator == nil {
+		cdLog.Warn("hibernation is not supported for the cluster platform")
+		if cond := controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.ClusterHibernatingCondition); cond != nil && cond.Reason == unsupportedReason {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, r.setHibernatingCondition(cd, corev1.ConditionFalse, unsupportedReason,
+			"Hibernation is not supported for the cluster platform", cdLog)
+	}
+
+	switch cd.Status.PowerState {
+	case hivev1.HibernatingClusterPowerState:
+		cdLog.Debug("cluster is hibernating")
+		return reconcile.Result{}, nil
+	case hivev1.StoppingClusterPowerState:
+		stopped, err := actuator.MachinesStopped(cd, r.Client, cdLog)
+		if err != nil {
+			cdLog.WithError(err).Error("failed to check whether machines are stopped")
+			return reconcile.Result{}, err
+		}
+		if !stopped {
+			// Stop any machines that were still starting when hibernation was requested.
+			if err := actuator.StopMachines(cd, r.Client, cdLog); err != nil {
+				cdLog.WithError(err).Error("failed to stop machines")
+				return reconcile.Result{}, err
+			}
+			cdLog.Info("waiting for machines to stop")
+			return reconcile.Result{RequeueAfter: stateCheckInterval}, nil
+		}
+		cdLog.Info("cluster machines are stopped, cluster is hibernating")
+		cd.Status.PowerState = hivev1.HibernatingClusterPowerState
+		return reconcile.Result{}, r.setHibernatingCondition(cd, corev1.ConditionTrue, hibernatingReason,
+			"Cluster is stopped", cdLog)
+	default:
+		cdLog.Info("stopping cluster machines")
+		if err := actuator.StopMachines(cd, r.Client, cdLog); err != nil {
+			cdLog.WithError(err).Error("failed to stop machines")
+			return reconcile.Result{}, err
+		}
+		cd.Status.PowerState = hivev1.StoppingClusterPowerState
+		err := r.setHibernatingCondition(cd, corev1.ConditionTrue, stoppingReason, "Stopping cluster machines", cdLog)
+		return reconcile.Result{RequeueAfter: stateCheckInterval}, err
+	}
+}
+
+func (r *ReconcileHibernation) reconcileRunning(cd *hivev1.ClusterDeployment, cdLog log.FieldLogger) (reconcile.Result, error) {
+	switch cd.Status.PowerState {
+	case "", hivev1.RunningClusterPowerState:
+		// Clear out any condition left over from a request to hibernate an unsupported platform.
+		if cond := controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.ClusterHibernatingCondition); cond == nil || cond.Reason == runningReason {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, r.setHibernatingCondition(cd, corev1.ConditionFalse, runningReason, "Cluster is running", cdLog)
+	}
+
+	actuator := r.getActuator(cd)
+	if actuator == nil {
+		cdLog.Warn("hibernation is not supported for the cluster platform")
+		return reconcile.Result{}, nil
+	}
+
+	if cd.Status.PowerState != hivev1.ResumingClusterPowerState {
+		cdLog.Info("starting cluster machines")
+		if err := actuator.StartMachines(cd, r.Client, cdLog); err != nil {
+			cdLog.WithError(err).Error("failed to start machines")
+			return reconcile.Result{}, err
+		}
+		cd.Status.PowerState = hivev1.ResumingClusterPowerState
+		err := r.setHibernatingCondition(cd, corev1.ConditionTrue, resumingReason, "Starting cluster machines", cdLog)
+		return reconcile.Result{RequeueAfter: stateCheckInterval}, err
+	}
+
+	running, err := actuator.MachinesRunning(cd, r.Client, cdLog)
+	if err != nil {
+		cdLog.WithError(err).Error("failed to check whether machines are running")
+		return reconcile.Result{}, err
+	}
+	if !running {
+		// Start any machines that were still stopping when resuming was requested.
+		if err := actuator.StartMachines(cd, r.Client, cdLog); err != nil {
+			cdLog.WithError(err).Error("failed to start machines")
+			return reconcile.Result{}, err
+		}
+		cdLog.Info("waiting for machines to start")
+		return reconcile.Result{RequeueAfter: stateCheckInterval}, nil
+	}
+
+	remoteClient, err := r.remoteClientFn(cd)
+	if err != nil {
+		cdLog.WithError(err).Info("unable to connect to the cluster, will retry")
+		return reconcile.Result{RequeueAfter: stateCheckInterval}, nil
+	}
+	nodes, err := remoteClient.ListNodes()
+	if err != nil {
+		cdLog.WithError(err).Info("unable to list nodes, will retry")
+		return reconcile.Result{RequeueAfter: stateCheckInterval}, nil
+	}
+	if !nodesReady(nodes) {
+		if err := approveNodeCSRs(remoteClient, nodes, cdLog); err != nil {
+			return reconcile.Result{}, err
+		}
+		cdLog.Info("waiting for nodes to become ready")
+		return reconcile.Result{RequeueAfter: stateCheckInterval}, nil
+	}
+
+	cdLog.Info("cluster nodes are ready, cluster is running")
+	cd.Status.PowerState = hivev1.RunningClusterPowerState
+	return reconcile.Result{}, r.setHibernatingCondition(cd, corev1.ConditionFalse, runningReason, "Cluster is running", cdLog)
+}
+
+func (r *ReconcileHibernation) setHibernatingCondition(cd *hivev1.ClusterDeployment, status corev1.ConditionStatus, reason, message string, cdLog log.FieldLogger) error {
+	if cond := controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.ClusterHibernatingCondition); cond == nil {
+		// Add the condition explicitly so that a false condition is reported to the user as well.
+		cd.Status.Conditions = append(cd.Status.Conditions, hivev1.ClusterDeploymentCondition{
+			Type:   hivev1.ClusterHibernatingCondition,
+			Status: corev1.ConditionUnknown,
+		})
+	}
+	cd.Status.Conditions = controllerutils.SetClusterDeploymentCondition(
+		cd.Status.Conditions,
+		hivev1.ClusterHibernatingCondition,
+		status,
+		reason,
+		message,
+		controllerutils.UpdateConditionIfReasonOrMessageChange,
+	)
+	if err := r.Status().Update(context.TODO(), cd); err != nil {
+		cdLog.WithError(err).Log(controllerutils.LogLevel(err), "failed to update cluster deployment status")
+		return err
+	}
+	return nil
+}
+
+func (r *ReconcileHibernation) getActuator(cd *hivev1.ClusterDeployment) HibernationActuator {
+	for _, a := range r.actuators {
+		if a.CanHandle(cd) {
+			return a
+		}
+	}
+	return nil
+}
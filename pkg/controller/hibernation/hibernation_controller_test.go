@@ -0,0 +1,336 @@
+package hibernation
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	certsv1beta1 "k8s.io/api/certificates/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openshift/hive/pkg/apis"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	hivev1aws "github.com/openshift/hive/pkg/apis/hive/v1/aws"
+	hivev1azure "github.com/openshift/hive/pkg/apis/hive/v1/azure"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+)
+
+const (
+	namespace = "test-namespace"
+	cdName    = "test-cluster"
+)
+
+func TestReconcileHibernation(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	tests := []struct {
+		name               string
+		cd                 *hivev1.ClusterDeployment
+		actuator           *fakeActuator
+		remoteClient       *fakeRemoteClusterClient
+		expectPowerState   hivev1.ClusterPowerState
+		expectCondition    *corev1.ConditionStatus
+		expectReason       string
+		expectStopCalled   bool
+		expectStartCalled  bool
+		expectApprovedCSRs []string
+		expectRequeueAfter bool
+	}{
+		{
+			name:     "cluster not installed",
+			cd:       testClusterDeployment(withPowerState(hivev1.HibernatingClusterPowerState), notInstalled()),
+			actuator: &fakeActuator{},
+		},
+		{
+			name:     "running cluster left alone",
+			cd:       testClusterDeployment(),
+			actuator: &fakeActuator{},
+		},
+		{
+			name:               "start hibernating",
+			cd:                 testClusterDeployment(withPowerState(hivev1.HibernatingClusterPowerState)),
+			actuator:           &fakeActuator{},
+			expectPowerState:   hivev1.StoppingClusterPowerState,
+			expectCondition:    conditionStatus(corev1.ConditionTrue),
+			expectReason:       stoppingReason,
+			expectStopCalled:   true,
+			expectRequeueAfter: true,
+		},
+		{
+			name: "machines still stopping",
+			cd: testClusterDeployment(withPowerState(hivev1.HibernatingClusterPowerState),
+				withStatusPowerState(hivev1.StoppingClusterPowerState, stoppingReason)),
+			actuator:           &fakeActuator{},
+			expectPowerState:   hivev1.StoppingClusterPowerState,
+			expectCondition:    conditionStatus(corev1.ConditionTrue),
+			expectReason:       stoppingReason,
+			expectStopCalled:   true,
+			expectRequeueAfter: true,
+		},
+		{
+			name: "machines stopped",
+			cd: testClusterDeployment(withPowerState(hivev1.HibernatingClusterPowerState),
+				withStatusPowerState(hivev1.StoppingClusterPowerState, stoppingReason)),
+			actuator:         &fakeActuator{stopped: true},
+			expectPowerState: hivev1.HibernatingClusterPowerState,
+			expectCondition:  conditionStatus(corev1.ConditionTrue),
+			expectReason:     hibernatingReason,
+		},
+		{
+			name:             "unsupported platform",
+			cd:               testClusterDeployment(withPowerState(hivev1.HibernatingClusterPowerState), withAzurePlatform()),
+			actuator:         &fakeActuator{},
+			expectCondition:  conditionStatus(corev1.ConditionFalse),
+			expectReason:     unsupportedReason,
+			expectStopCalled: false,
+		},
+		{
+			name:               "start resuming",
+			cd:                 testClusterDeployment(withStatusPowerState(hivev1.HibernatingClusterPowerState, hibernatingReason)),
+			actuator:           &fakeActuator{stopped: true},
+			expectPowerState:   hivev1.ResumingClusterPowerState,
+			expectCondition:    conditionStatus(corev1.ConditionTrue),
+			expectReason:       resumingReason,
+			expectStartCalled:  true,
+			expectRequeueAfter: true,
+		},
+		{
+			name:               "machines still starting",
+			cd:                 testClusterDeployment(withStatusPowerState(hivev1.ResumingClusterPowerState, resumingReason)),
+			actuator:           &fakeActuator{},
+			expectPowerState:   hivev1.ResumingClusterPowerState,
+			expectCondition:    conditionStatus(corev1.ConditionTrue),
+			expectReason:       resumingReason,
+			expectStartCalled:  true,
+			expectRequeueAfter: true,
+		},
+		{
+			name:     "nodes not ready approves node CSRs",
+			cd:       testClusterDeployment(withStatusPowerState(hivev1.ResumingClusterPowerState, resumingReason)),
+			actuator: &fakeActuator{running: true},
+			remoteClient: &fakeRemoteClusterClient{
+				nodes: []corev1.Node{testNode("node1", false), testNode("node2", true)},
+				csrs: []certsv1beta1.CertificateSigningRequest{
+					testCSR(t, "bootstrap-csr", nodeBootstrapperUser, "system:node:node1"),
+					testCSR(t, "serving-csr", "system:node:node1", "system:node:node1"),
+					testCSR(t, "unknown-node-csr", nodeBootstrapperUser, "system:node:node3"),
+					testCSR(t, "wrong-user-csr", "system:node:node2", "system:node:node1"),
+					testCSR(t, "not-node-csr", nodeBootstrapperUser, "some-user"),
+					approved(testCSR(t, "approved-csr", nodeBootstrapperUser, "system:node:node2")),
+				},
+			},
+			expectPowerState:   hivev1.ResumingClusterPowerState,
+			expectCondition:    conditionStatus(corev1.ConditionTrue),
+			expectReason:       resumingReason,
+			expectApprovedCSRs: []string{"bootstrap-csr", "serving-csr"},
+			expectRequeueAfter: true,
+		},
+		{
+			name:     "nodes ready",
+			cd:       testClusterDeployment(withStatusPowerState(hivev1.ResumingClusterPowerState, resumingReason)),
+			actuator: &fakeActuator{running: true},
+			remoteClient: &fakeRemoteClusterClient{
+				nodes: []corev1.Node{testNode("node1", true), testNode("node2", true)},
+			},
+			expectPowerState: hivev1.RunningClusterPowerState,
+			expectCondition:  conditionStatus(corev1.ConditionFalse),
+			expectReason:     runningReason,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			fakeClient := fake.NewFakeClient(test.cd)
+			remoteClient := test.remoteClient
+			if remoteClient == nil {
+				remoteClient = &fakeRemoteClusterClient{}
+			}
+			r := &ReconcileHibernation{
+				Client:    fakeClient,
+				scheme:    scheme.Scheme,
+				logger:    log.WithField("controller", "hibernation"),
+				actuators: []HibernationActuator{test.actuator},
+				remoteClientFn: func(*hivev1.ClusterDeployment) (remoteClusterClient, error) {
+					return remoteClient, nil
+				},
+			}
+
+			result, err := r.Reconcile(reconcile.Request{
+				NamespacedName: types.NamespacedName{Namespace: namespace, Name: cdName},
+			})
+			require.NoError(t, err, "unexpected error from Reconcile")
+			assert.Equal(t, test.expectRequeueAfter, result.RequeueAfter > 0, "unexpected requeue after")
+			assert.Equal(t, test.expectStopCalled, test.actuator.stopCalled, "unexpected call to stop machines")
+			assert.Equal(t, test.expectStartCalled, test.actuator.startCalled, "unexpected call to start machines")
+			assert.ElementsMatch(t, test.expectApprovedCSRs, remoteClient.approved, "unexpected approved CSRs")
+
+			cd := &hivev1.ClusterDeployment{}
+			err = fakeClient.Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: cdName}, cd)
+			require.NoError(t, err, "unexpected error getting cluster deployment")
+			assert.Equal(t, test.expectPowerState, cd.Status.PowerState, "unexpected power state")
+			cond := controllerutils.FindClusterDeploymentCondition(cd.Status.Conditions, hivev1.ClusterHibernatingCondition)
+			if test.expectCondition == nil {
+				assert.Nil(t, cond, "unexpected hibernating condition")
+			} else if assert.NotNil(t, cond, "expected hibernating condition") {
+				assert.Equal(t, *test.expectCondition, cond.Status, "unexpected hibernating condition status")
+				assert.Equal(t, test.expectReason, cond.Reason, "unexpected hibernating condition reason")
+			}
+		})
+	}
+}
+
+type fakeActuator struct {
+	running     bool
+	stopped     bool
+	stopCalled  bool
+	startCalled bool
+}
+
+func (a *fakeActuator) CanHandle(cd *hivev1.ClusterDeployment) bool {
+	return cd.Spec.Platform.AWS != nil
+}
+
+func (a *fakeActuator) StopMachines(*hivev1.ClusterDeployment, client.Client, log.FieldLogger) error {
+	a.stopCalled = true
+	return nil
+}
+
+func (a *fakeActuator) StartMachines(*hivev1.ClusterDeployment, client.Client, log.FieldLogger) error {
+	a.startCalled = true
+	return nil
+}
+
+func (a *fakeActuator) MachinesRunning(*hivev1.ClusterDeployment, client.Client, log.FieldLogger) (bool, error) {
+	return a.running, nil
+}
+
+func (a *fakeActuator) MachinesStopped(*hivev1.ClusterDeployment, client.Client, log.FieldLogger) (bool, error) {
+	return a.stopped, nil
+}
+
+type fakeRemoteClusterClient struct {
+	nodes    []corev1.Node
+	csrs     []certsv1beta1.CertificateSigningRequest
+	approved []string
+}
+
+func (c *fakeRemoteClusterClient) ListNodes() ([]corev1.Node, error) {
+	return c.nodes, nil
+}
+
+func (c *fakeRemoteClusterClient) ListCSRs() ([]certsv1beta1.CertificateSigningRequest, error) {
+	return c.csrs, nil
+}
+
+func (c *fakeRemoteClusterClient) ApproveCSR(csr *certsv1beta1.CertificateSigningRequest) error {
+	c.approved = append(c.approved, csr.Name)
+	return nil
+}
+
+type cdOption func(*hivev1.ClusterDeployment)
+
+func withPowerState(powerState hivev1.ClusterPowerState) cdOption {
+	return func(cd *hivev1.ClusterDeployment) {
+		cd.Spec.PowerState = powerState
+	}
+}
+
+func withStatusPowerState(powerState hivev1.ClusterPowerState, reason string) cdOption {
+	return func(cd *hivev1.ClusterDeployment) {
+		cd.Status.PowerState = powerState
+		cd.Status.Conditions = append(cd.Status.Conditions, hivev1.ClusterDeploymentCondition{
+			Type:   hivev1.ClusterHibernatingCondition,
+			Status: corev1.ConditionTrue,
+			Reason: reason,
+		})
+	}
+}
+
+func notInstalled() cdOption {
+	return func(cd *hivev1.ClusterDeployment) {
+		cd.Spec.Installed = false
+	}
+}
+
+func withAzurePlatform() cdOption {
+	return func(cd *hivev1.ClusterDeployment) {
+		cd.Spec.Platform = hivev1.Platform{Azure: &hivev1azure.Platform{Region: "centralus"}}
+	}
+}
+
+func testClusterDeployment(opts ...cdOption) *hivev1.ClusterDeployment {
+	cd := &hivev1.ClusterDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: namespace,
+			Name:      cdName,
+		},
+		Spec: hivev1.ClusterDeploymentSpec{
+			Installed: true,
+			ClusterMetadata: &hivev1.ClusterMetadata{
+				InfraID: "test-infra-id",
+			},
+			Platform: hivev1.Platform{
+				AWS: &hivev1aws.Platform{Region: "us-east-1"},
+			},
+		},
+	}
+	for _, o := range opts {
+		o(cd)
+	}
+	return cd
+}
+
+func testNode(name string, ready bool) corev1.Node {
+	status := corev1.ConditionFalse
+	if ready {
+		status = corev1.ConditionTrue
+	}
+	return corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}},
+		},
+	}
+}
+
+func testCSR(t *testing.T, name, username, commonName string) certsv1beta1.CertificateSigningRequest {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err, "failed to generate key")
+	request, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: commonName, Organization: []string{"system:nodes"}},
+	}, key)
+	require.NoError(t, err, "failed to create certificate request")
+	return certsv1beta1.CertificateSigningRequest{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Spec: certsv1beta1.CertificateSigningRequestSpec{
+			Username: username,
+			Request:  pem.EncodeToMemory(&pem.Block{Type: certificateRequestBlock, Bytes: request}),
+		},
+	}
+}
+
+func approved(csr certsv1beta1.CertificateSigningRequest) certsv1beta1.CertificateSigningRequest {
+	csr.Status.Conditions = append(csr.Status.Conditions, certsv1beta1.CertificateSigningRequestCondition{
+		Type: certsv1beta1.CertificateApproved,
+	})
+	return csr
+}
+
+func conditionStatus(status corev1.ConditionStatus) *corev1.ConditionStatus {
+	return &status
+}
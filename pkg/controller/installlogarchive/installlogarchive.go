@@ -0,0 +1,91 @@
+package installlogarchive
+
+import (
+	"time"
+
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	log "github.com/sirupsen/logrus"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+	"github.com/openshift/hive/pkg/installlogarchive"
+)
+
+const (
+	controllerName = "installLogArchive"
+)
+
+// Add creates a new install log archive Pruner and adds it to the Manager. Archived install logs are only pruned
+// when a retention is configured, and only by the first controllers shard, since they are pruned across all
+// ClusterProvisions.
+func Add(mgr manager.Manager) error {
+	logger := log.WithField("controller", controllerName)
+	config, err := installlogarchive.ConfigFromEnvironment()
+	if err != nil {
+		logger.WithError(err).Error("cannot read install log archive configuration")
+		return err
+	}
+	if config == nil || config.Retention == nil {
+		logger.Debug("install log archive retention not configured")
+		return nil
+	}
+	if !controllerutils.GetShard().Owns("") {
+		logger.Info("archived install logs are pruned by the first controllers shard")
+		return nil
+	}
+	return mgr.Add(&Pruner{
+		Client:   mgr.GetClient(),
+		Config:   config,
+		Interval: time.Hour,
+		logger:   logger,
+		newS3Client: func(c client.Client, config *hivev1.InstallLogArchiveConfig) (s3iface.S3API, error) {
+			return installlogarchive.NewClient(c, config, constants.HiveNamespace)
+		},
+	})
+}
+
+// Pruner runs in a goroutine and periodically deletes the archived install logs that are older than the retention
+// of the install log archive.
+type Pruner struct {
+	Client client.Client
+
+	// Config is the configuration of the install log archive.
+	Config *hivev1.InstallLogArchiveConfig
+
+	// Interval is the length of time we sleep between prunings.
+	Interval time.Duration
+
+	logger      log.FieldLogger
+	newS3Client func(client.Client, *hivev1.InstallLogArchiveConfig) (s3iface.S3API, error)
+}
+
+// Start begins the pruning loop.
+func (p *Pruner) Start(stopCh <-chan struct{}) error {
+	p.logger.Info("started install log archive pruner goroutine")
+	wait.Until(p.prune, p.Interval, stopCh)
+	return nil
+}
+
+func (p *Pruner) prune() {
+	logger := p.logger.WithField("bucket", p.Config.Bucket)
+	// The client is created for every pruning so that rotated credentials are picked up.
+	s3Client, err := p.newS3Client(p.Client, p.Config)
+	if err != nil {
+		logger.WithError(err).Error("error creating install log archive client")
+		return
+	}
+	before := time.Now().Add(-p.Config.Retention.Duration)
+	logger.WithField("before", before).Info("pruning archived install logs")
+	deleted, err := installlogarchive.Prune(s3Client, p.Config, before)
+	if err != nil {
+		logger.WithError(err).WithField("deleted", deleted).Error("error pruning archived install logs")
+		return
+	}
+	logger.WithField("deleted", deleted).Info("pruned archived install logs")
+}
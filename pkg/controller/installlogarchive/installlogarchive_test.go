@@ -0,0 +1,83 @@
+package installlogarchive
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+)
+
+type fakeS3Client struct {
+	s3iface.S3API
+	objects []*s3.Object
+	deleted []string
+}
+
+func (c *fakeS3Client) ListObjectsV2Pages(input *s3.ListObjectsV2Input, fn func(*s3.ListObjectsV2Output, bool) bool) error {
+	fn(&s3.ListObjectsV2Output{Contents: c.objects}, true)
+	return nil
+}
+
+func (c *fakeS3Client) DeleteObjects(input *s3.DeleteObjectsInput) (*s3.DeleteObjectsOutput, error) {
+	for _, obj := range input.Delete.Objects {
+		c.deleted = append(c.deleted, *obj.Key)
+	}
+	return &s3.DeleteObjectsOutput{}, nil
+}
+
+func TestPrune(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name            string
+		clientErr       error
+		expectedDeleted []string
+	}{
+		{
+			name:            "expired logs",
+			expectedDeleted: []string{"logs/test-namespace/old-provision/openshift_install.log"},
+		},
+		{
+			name:      "client error",
+			clientErr: errors.New("missing credentials"),
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			s3Client := &fakeS3Client{
+				objects: []*s3.Object{
+					{Key: aws.String("logs/test-namespace/old-provision/openshift_install.log"), LastModified: aws.Time(now.Add(-8 * 24 * time.Hour))},
+					{Key: aws.String("logs/test-namespace/new-provision/openshift_install.log"), LastModified: aws.Time(now.Add(-time.Hour))},
+				},
+			}
+			p := &Pruner{
+				Client: fake.NewFakeClient(),
+				Config: &hivev1.InstallLogArchiveConfig{
+					Bucket:    "install-logs",
+					Prefix:    "logs",
+					Retention: &metav1.Duration{Duration: 7 * 24 * time.Hour},
+				},
+				logger: log.WithField("controller", controllerName),
+				newS3Client: func(client.Client, *hivev1.InstallLogArchiveConfig) (s3iface.S3API, error) {
+					if test.clientErr != nil {
+						return nil, test.clientErr
+					}
+					return s3Client, nil
+				},
+			}
+			p.prune()
+			assert.Equal(t, test.expectedDeleted, s3Client.deleted, "unexpected deleted objects")
+		})
+	}
+}
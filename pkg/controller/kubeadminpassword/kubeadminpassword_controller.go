@@ -0,0 +1,370 @@
+// Package kubeadminpassword provides a controller which manages the kubeadmin user of installed clusters. It rotates
+// the kubeadmin password on a schedule or on demand, and removes the kubeadmin user once the identity providers of the
+// cluster have been applied.
+package kubeadminpassword
+
+import (
+	"context"
+	"crypto/rand"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	hivemetrics "github.com/openshift/hive/pkg/controller/metrics"
+	"github.com/openshift/hive/pkg/controller/syncidentityprovider"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+	"github.com/openshift/hive/pkg/remoteclient"
+)
+
+const (
+	controllerName = "kubeadminPassword"
+
+	adminPasswordSecretPasswordKey = "password"
+	// adminPasswordSecretPendingPasswordKey holds the new password during a rotation, until it has been set on the
+	// remote cluster.
+	adminPasswordSecretPendingPasswordKey = "pendingPassword"
+
+	kubeadminPasswordRotatedReason = "KubeadminPasswordRotated"
+	kubeadminRemovedReason         = "KubeadminRemoved"
+
+	// The kubeadmin password follows the format of the password generated by the installer: four groups of five
+	// characters, with at least one lower case letter, upper case letter and digit. Characters that are easily
+	// confused are left out.
+	passwordLowerCases  = "abcdefghijkmnopqrstuvwxyz"
+	passwordUpperCases  = "ABCDEFGHIJKLMNPQRSTUVWXYZ"
+	passwordDigits      = "23456789"
+	passwordGroups      = 4
+	passwordGroupLength = 5
+)
+
+// Add creates a new KubeadminPassword Controller and adds it to the Manager with default RBAC. The Manager will set fields on the
+// Controller and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	return AddToManager(mgr, NewReconciler(mgr))
+}
+
+// NewReconciler returns a new reconcile.Reconciler
+func NewReconciler(mgr manager.Manager) reconcile.Reconciler {
+	r := &ReconcileKubeadminPassword{
+		Client:                controllerutils.NewClientWithMetricsOrDie(mgr, controllerName),
+		scheme:                mgr.GetScheme(),
+		logger:                log.WithField("controller", controllerName),
+		eventRecorder:         mgr.GetEventRecorderFor(controllerName),
+		setRemotePasswordHash: setRemotePasswordHash,
+		removeRemoteKubeadmin: removeRemoteKubeadmin,
+	}
+	r.remoteClientBuilder = func(cd *hivev1.ClusterDeployment) remoteclient.Builder {
+		return remoteclient.NewBuilder(r.Client, cd, controllerName)
+	}
+	return r
+}
+
+// AddToManager adds a new Controller to mgr with r as the reconcile.Reconciler
+func AddToManager(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New("kubeadminpassword-controller", mgr, controller.Options{Reconciler: controllerutils.NewShardedReconciler(r), MaxConcurrentReconciles: controllerutils.GetConcurrentReconciles(controllerName)})
+	if err != nil {
+		return err
+	}
+
+	if err := controllerutils.SetQueueRateLimiter(c, controllerName); err != nil {
+		return err
+	}
+
+	// Watch for changes to ClusterDeployment
+	err = c.Watch(&source.Kind{Type: &hivev1.ClusterDeployment{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to SyncSetInstances, to remove kubeadmin once the identity providers have been applied
+	err = c.Watch(&source.Kind{Type: &hivev1.SyncSetInstance{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(syncSetInstanceWatchHandler),
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func syncSetInstanceWatchHandler(a handler.MapObject) []reconcile.Request {
+	ssi, ok := a.Object.(*hivev1.SyncSetInstance)
+	if !ok || ssi.Spec.SyncSetRef == nil {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{
+		Name:      ssi.Spec.ClusterDeploymentRef.Name,
+		Namespace: ssi.Namespace,
+	}}}
+}
+
+var _ reconcile.Reconciler = &ReconcileKubeadminPassword{}
+
+// ReconcileKubeadminPassword manages the kubeadmin user of a ClusterDeployment object
+type ReconcileKubeadminPassword struct {
+	client.Client
+	scheme        *runtime.Scheme
+	logger        log.FieldLogger
+	eventRecorder record.EventRecorder
+
+	// remoteClientBuilder is a function pointer to the function that gets a builder for building a client
+	// for the remote cluster's API server
+	remoteClientBuilder func(cd *hivev1.ClusterDeployment) remoteclient.Builder
+
+	// setRemotePasswordHash is a function pointer to the function that sets the hash of the kubeadmin password on
+	// the remote cluster.
+	setRemotePasswordHash func(remoteClientBuilder remoteclient.Builder, hash []byte) error
+
+	// removeRemoteKubeadmin is a function pointer to the function that removes the kubeadmin user from the remote
+	// cluster.
+	removeRemoteKubeadmin func(remoteClientBuilder remoteclient.Builder) error
+}
+
+// Reconcile rotates the kubeadmin password of a cluster when it is due, and removes the kubeadmin user once the
+// identity providers of the cluster have been applied if requested.
+func (r *ReconcileKubeadminPassword) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	start := time.Now()
+	cdLog := r.logger.WithFields(log.Fields{
+		"clusterDeployment": request.Name,
+		"namespace":         request.Namespace,
+	})
+
+	cdLog.Info("reconciling cluster deployment")
+	defer func() {
+		dur := time.Since(start)
+		hivemetrics.MetricControllerReconcileTime.WithLabelValues(controllerName).Observe(dur.Seconds())
+		cdLog.WithField("elapsed", dur).Info("reconcile complete")
+	}()
+
+	// Fetch the ClusterDeployment instance
+	cd := &hivev1.ClusterDeployment{}
+	err := r.Get(context.TODO(), request.NamespacedName, cd)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		cdLog.WithError(err).Error("error looking up cluster deployment")
+		return reconcile.Result{}, err
+	}
+
+	// If the clusterdeployment is deleted, do not reconcile.
+	if cd.DeletionTimestamp != nil {
+		return reconcile.Result{}, nil
+	}
+
+	if cd.Spec.KubeadminPassword == nil {
+		return reconcile.Result{}, nil
+	}
+
+	if !cd.Spec.Installed {
+		cdLog.Debug("cluster installation is not complete")
+		return reconcile.Result{}, nil
+	}
+
+	if cd.Spec.ClusterMetadata == nil {
+		cdLog.Error("installed cluster with no cluster metadata")
+		return reconcile.Result{}, nil
+	}
+
+	if cd.Status.KubeadminPassword != nil && cd.Status.KubeadminPassword.Removed {
+		cdLog.Debug("kubeadmin has been removed from the cluster")
+		return reconcile.Result{}, nil
+	}
+
+	removeKubeadmin := false
+	if cd.Spec.KubeadminPassword.RemoveAfterIdentityProviders {
+		removeKubeadmin, err = syncidentityprovider.IdentityProvidersApplied(r.Client, cd)
+		if err != nil {
+			cdLog.WithError(err).Error("error checking whether identity providers have been applied")
+			return reconcile.Result{}, err
+		}
+	}
+	rotateAt := rotationTime(cd)
+	rotate := rotateAt != nil && !rotateAt.After(start)
+	if !removeKubeadmin && !rotate {
+		if rotateAt != nil {
+			cdLog.WithField("rotateAt", rotateAt).Debug("kubeadmin password rotation is not due")
+			return reconcile.Result{RequeueAfter: rotateAt.Sub(start)}, nil
+		}
+		return reconcile.Result{}, nil
+	}
+
+	remoteClientBuilder := r.remoteClientBuilder(cd)
+	if remoteClientBuilder.Unreachable() {
+		cdLog.Debug("skipping kubeadmin management for unreachable cluster")
+		return reconcile.Result{}, nil
+	}
+
+	if removeKubeadmin {
+		cdLog.Info("identity providers have been applied, removing kubeadmin")
+		if err := r.removeRemoteKubeadmin(remoteClientBuilder); err != nil {
+			cdLog.WithError(err).Error("error removing kubeadmin")
+			return reconcile.Result{}, err
+		}
+		return reconcile.Result{}, r.setRemoved(cd, "Removed kubeadmin once identity providers were applied", cdLog)
+	}
+
+	if err := r.rotate(cd, remoteClientBuilder, cdLog); err != nil {
+		if errors.Cause(err) == errKubeadminNotFound {
+			return reconcile.Result{}, r.setRemoved(cd, "kubeadmin has been removed from the cluster", cdLog)
+		}
+		cdLog.WithError(err).Error("error rotating kubeadmin password")
+		return reconcile.Result{}, err
+	}
+	if rotateAt := rotationTime(cd); rotateAt != nil {
+		return reconcile.Result{RequeueAfter: time.Until(*rotateAt)}, nil
+	}
+	return reconcile.Result{}, nil
+}
+
+// rotationTime returns when the kubeadmin password is next due for rotation, or nil if no rotation is scheduled or
+// requested.
+func rotationTime(cd *hivev1.ClusterDeployment) *time.Time {
+	spec := cd.Spec.KubeadminPassword
+	var lastRotation *metav1.Time
+	if cd.Status.KubeadminPassword != nil {
+		lastRotation = cd.Status.KubeadminPassword.LastRotationTime
+	}
+
+	var rotateAt *time.Time
+	if spec.RotationInterval != nil {
+		// The password generated by the installer is as old as the cluster.
+		since := cd.CreationTimestamp
+		switch {
+		case lastRotation != nil:
+			since = *lastRotation
+		case cd.Status.InstalledTimestamp != nil:
+			since = *cd.Status.InstalledTimestamp
+		}
+		t := since.Add(spec.RotationInterval.Duration)
+		rotateAt = &t
+	}
+	if requested := spec.RotationRequestedAt; requested != nil && (lastRotation == nil || lastRotation.Before(requested)) {
+		if rotateAt == nil || requested.Time.Before(*rotateAt) {
+			t := requested.Time
+			rotateAt = &t
+		}
+	}
+	return rotateAt
+}
+
+// rotate sets a new kubeadmin password on the remote cluster, and saves it in the admin password secret. The new
+// password is saved as pending before it is set on the remote cluster, so that a password set on the cluster is never
+// lost when the rotation fails midway. A pending password left by a failed rotation is used by the next rotation.
+func (r *ReconcileKubeadminPassword) rotate(cd *hivev1.ClusterDeployment, remoteClientBuilder remoteclient.Builder, cdLog log.FieldLogger) error {
+	secret := &corev1.Secret{}
+	secretName := cd.Spec.ClusterMetadata.AdminPasswordSecretRef.Name
+	if err := r.Get(context.TODO(), types.NamespacedName{Namespace: cd.Namespace, Name: secretName}, secret); err != nil {
+		return errors.Wrap(err, "could not get admin password secret")
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+
+	password := secret.Data[adminPasswordSecretPendingPasswordKey]
+	if len(password) == 0 {
+		generated, err := generatePassword()
+		if err != nil {
+			return errors.Wrap(err, "could not generate password")
+		}
+		password = []byte(generated)
+		secret.Data[adminPasswordSecretPendingPasswordKey] = password
+		if err := r.Update(context.TODO(), secret); err != nil {
+			return errors.Wrap(err, "could not save pending kubeadmin password")
+		}
+	} else {
+		cdLog.Info("resuming rotation with pending kubeadmin password")
+	}
+	hash, err := bcrypt.GenerateFromPassword(password, bcrypt.DefaultCost)
+	if err != nil {
+		return errors.Wrap(err, "could not hash password")
+	}
+
+	cdLog.Info("rotating kubeadmin password")
+	if err := r.setRemotePasswordHash(remoteClientBuilder, hash); err != nil {
+		if errors.Cause(err) == errKubeadminNotFound {
+			// The pending password can no longer be set on the cluster.
+			delete(secret.Data, adminPasswordSecretPendingPasswordKey)
+			if err := r.Update(context.TODO(), secret); err != nil {
+				cdLog.WithError(err).Warn("could not remove pending kubeadmin password")
+			}
+		}
+		return err
+	}
+
+	secret.Data[adminPasswordSecretPasswordKey] = password
+	delete(secret.Data, adminPasswordSecretPendingPasswordKey)
+	if err := r.Update(context.TODO(), secret); err != nil {
+		return errors.Wrap(err, "could not save kubeadmin password")
+	}
+
+	now := metav1.Now()
+	if cd.Status.KubeadminPassword == nil {
+		cd.Status.KubeadminPassword = &hivev1.KubeadminPasswordStatus{}
+	}
+	cd.Status.KubeadminPassword.LastRotationTime = &now
+	if err := r.Status().Update(context.TODO(), cd); err != nil {
+		cdLog.WithError(err).Log(controllerutils.LogLevel(err), "error updating cluster deployment with kubeadmin password rotation")
+		return err
+	}
+	r.eventRecorder.Eventf(cd, corev1.EventTypeNormal, kubeadminPasswordRotatedReason, "Rotated kubeadmin password, saved in secret %s", secretName)
+	return nil
+}
+
+func (r *ReconcileKubeadminPassword) setRemoved(cd *hivev1.ClusterDeployment, message string, cdLog log.FieldLogger) error {
+	cdLog.Info(message)
+	if cd.Status.KubeadminPassword == nil {
+		cd.Status.KubeadminPassword = &hivev1.KubeadminPasswordStatus{}
+	}
+	cd.Status.KubeadminPassword.Removed = true
+	if err := r.Status().Update(context.TODO(), cd); err != nil {
+		cdLog.WithError(err).Log(controllerutils.LogLevel(err), "error updating cluster deployment with kubeadmin removal")
+		return err
+	}
+	r.eventRecorder.Event(cd, corev1.EventTypeNormal, kubeadminRemovedReason, message)
+	return nil
+}
+
+// generatePassword returns a random password in the format of the kubeadmin password generated by the installer.
+func generatePassword() (string, error) {
+	all := passwordLowerCases + passwordUpperCases + passwordDigits
+	for {
+		var b strings.Builder
+		for i := 0; i < passwordGroups*passwordGroupLength; i++ {
+			if i > 0 && i%passwordGroupLength == 0 {
+				b.WriteByte('-')
+			}
+			n, err := rand.Int(rand.Reader, big.NewInt(int64(len(all))))
+			if err != nil {
+				return "", err
+			}
+			b.WriteByte(all[n.Int64()])
+		}
+		password := b.String()
+		if strings.ContainsAny(password, passwordLowerCases) &&
+			strings.ContainsAny(password, passwordUpperCases) &&
+			strings.ContainsAny(password, passwordDigits) {
+			return password, nil
+		}
+	}
+}
@@ -0,0 +1,363 @@
+package kubeadminpassword
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+
+	openshiftapiv1 "github.com/openshift/api/config/v1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openshift/hive/pkg/apis"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/controller/syncidentityprovider"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+	"github.com/openshift/hive/pkg/remoteclient"
+	remoteclientmock "github.com/openshift/hive/pkg/remoteclient/mock"
+)
+
+const (
+	testName            = "test-cluster"
+	testNamespace       = "test-namespace"
+	testPasswordSecret  = "test-cluster-admin-password"
+	testPassword        = "installer-password"
+	testPendingPassword = "pending-password"
+
+	day = 24 * time.Hour
+)
+
+func init() {
+	log.SetLevel(log.DebugLevel)
+}
+
+func TestReconcileKubeadminPassword(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	now := time.Now()
+	ago := func(d time.Duration) *metav1.Time {
+		t := metav1.NewTime(now.Add(-d))
+		return &t
+	}
+
+	tests := []struct {
+		name                   string
+		cd                     *hivev1.ClusterDeployment
+		identityProvidersApply bool
+		unreachable            bool
+		kubeadminNotFound      bool
+		pendingPassword        string
+		remoteErr              error
+		expectRotated          bool
+		expectPending          bool
+		expectErr              bool
+		expectRemoved          bool
+		expectRemoteRemoval    bool
+		expectRequeue          time.Duration
+		expectEvent            string
+	}{
+		{
+			name: "kubeadmin not managed",
+			cd:   testClusterDeployment(nil),
+		},
+		{
+			name: "not installed",
+			cd: testClusterDeployment(&hivev1.KubeadminPasswordSpec{RotationInterval: &metav1.Duration{Duration: 7 * day}},
+				func(cd *hivev1.ClusterDeployment) { cd.Spec.Installed = false }),
+		},
+		{
+			name:          "rotation not due",
+			cd:            testClusterDeployment(&hivev1.KubeadminPasswordSpec{RotationInterval: &metav1.Duration{Duration: 7 * day}}),
+			expectRequeue: 6 * day,
+		},
+		{
+			name: "scheduled rotation",
+			cd: testClusterDeployment(&hivev1.KubeadminPasswordSpec{RotationInterval: &metav1.Duration{Duration: 7 * day}},
+				func(cd *hivev1.ClusterDeployment) { cd.Status.InstalledTimestamp = ago(8 * day) }),
+			expectRotated: true,
+			expectRequeue: 7 * day,
+			expectEvent:   "Normal KubeadminPasswordRotated Rotated kubeadmin password",
+		},
+		{
+			name: "requested rotation",
+			cd: testClusterDeployment(&hivev1.KubeadminPasswordSpec{RotationRequestedAt: ago(time.Minute)},
+				func(cd *hivev1.ClusterDeployment) {
+					cd.Status.KubeadminPassword = &hivev1.KubeadminPasswordStatus{LastRotationTime: ago(time.Hour)}
+				}),
+			expectRotated: true,
+			expectEvent:   "Normal KubeadminPasswordRotated Rotated kubeadmin password",
+		},
+		{
+			name: "requested rotation already done",
+			cd: testClusterDeployment(&hivev1.KubeadminPasswordSpec{RotationRequestedAt: ago(2 * time.Hour)},
+				func(cd *hivev1.ClusterDeployment) {
+					cd.Status.KubeadminPassword = &hivev1.KubeadminPasswordStatus{LastRotationTime: ago(time.Hour)}
+				}),
+		},
+		{
+			name:          "future requested rotation",
+			cd:            testClusterDeployment(&hivev1.KubeadminPasswordSpec{RotationRequestedAt: ago(-time.Hour)}),
+			expectRequeue: time.Hour,
+		},
+		{
+			name: "unreachable cluster",
+			cd: testClusterDeployment(&hivev1.KubeadminPasswordSpec{RotationRequestedAt: ago(time.Minute)},
+				func(cd *hivev1.ClusterDeployment) { cd.Status.InstalledTimestamp = ago(8 * day) }),
+			unreachable: true,
+		},
+		{
+			name:          "remote password update fails",
+			cd:            testClusterDeployment(&hivev1.KubeadminPasswordSpec{RotationRequestedAt: ago(time.Minute)}),
+			remoteErr:     errors.New("connection refused"),
+			expectPending: true,
+			expectErr:     true,
+		},
+		{
+			name:            "pending password from failed rotation",
+			cd:              testClusterDeployment(&hivev1.KubeadminPasswordSpec{RotationRequestedAt: ago(time.Minute)}),
+			pendingPassword: testPendingPassword,
+			expectRotated:   true,
+			expectEvent:     "Normal KubeadminPasswordRotated Rotated kubeadmin password",
+		},
+		{
+			name:              "kubeadmin removed from cluster",
+			cd:                testClusterDeployment(&hivev1.KubeadminPasswordSpec{RotationRequestedAt: ago(time.Minute)}),
+			kubeadminNotFound: true,
+			expectRemoved:     true,
+			expectEvent:       "Normal KubeadminRemoved kubeadmin has been removed from the cluster",
+		},
+		{
+			name:                   "remove after identity providers",
+			cd:                     testClusterDeployment(&hivev1.KubeadminPasswordSpec{RemoveAfterIdentityProviders: true}),
+			identityProvidersApply: true,
+			expectRemoved:          true,
+			expectRemoteRemoval:    true,
+			expectEvent:            "Normal KubeadminRemoved Removed kubeadmin once identity providers were applied",
+		},
+		{
+			name: "identity providers not applied",
+			cd:   testClusterDeployment(&hivev1.KubeadminPasswordSpec{RemoveAfterIdentityProviders: true}),
+		},
+		{
+			name: "kubeadmin already removed",
+			cd: testClusterDeployment(&hivev1.KubeadminPasswordSpec{RotationRequestedAt: ago(time.Minute), RemoveAfterIdentityProviders: true},
+				func(cd *hivev1.ClusterDeployment) {
+					cd.Status.KubeadminPassword = &hivev1.KubeadminPasswordStatus{Removed: true}
+				}),
+			identityProvidersApply: true,
+			expectRemoved:          true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			passwordSecret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: testPasswordSecret, Namespace: testNamespace},
+				Data: map[string][]byte{
+					"username": []byte("kubeadmin"),
+					"password": []byte(testPassword),
+				},
+			}
+			if test.pendingPassword != "" {
+				passwordSecret.Data["pendingPassword"] = []byte(test.pendingPassword)
+			}
+			existing := []runtime.Object{test.cd, passwordSecret}
+			if test.identityProvidersApply {
+				existing = append(existing, testIdentityProviderObjects(t)...)
+			}
+			fakeClient := fake.NewFakeClient(existing...)
+
+			mockRemoteClientBuilder := remoteclientmock.NewMockBuilder(mockCtrl)
+			mockRemoteClientBuilder.EXPECT().Unreachable().Return(test.unreachable).AnyTimes()
+			fakeRecorder := record.NewFakeRecorder(10)
+
+			var remoteHash []byte
+			removed := false
+			r := &ReconcileKubeadminPassword{
+				Client:        fakeClient,
+				scheme:        scheme.Scheme,
+				logger:        log.WithField("controller", controllerName),
+				eventRecorder: fakeRecorder,
+				remoteClientBuilder: func(*hivev1.ClusterDeployment) remoteclient.Builder {
+					return mockRemoteClientBuilder
+				},
+				setRemotePasswordHash: func(_ remoteclient.Builder, hash []byte) error {
+					if test.kubeadminNotFound {
+						return errKubeadminNotFound
+					}
+					if test.remoteErr != nil {
+						return test.remoteErr
+					}
+					remoteHash = hash
+					return nil
+				},
+				removeRemoteKubeadmin: func(remoteclient.Builder) error {
+					removed = true
+					return nil
+				},
+			}
+
+			key := types.NamespacedName{Name: testName, Namespace: testNamespace}
+			result, err := r.Reconcile(reconcile.Request{NamespacedName: key})
+			if test.expectErr {
+				assert.Error(t, err, "expected error from reconcile")
+			} else {
+				require.NoError(t, err, "unexpected error from reconcile")
+			}
+			assert.InDelta(t, test.expectRequeue.Seconds(), result.RequeueAfter.Seconds(), 60, "unexpected requeue")
+
+			cd := &hivev1.ClusterDeployment{}
+			require.NoError(t, fakeClient.Get(context.TODO(), key, cd))
+			secret := &corev1.Secret{}
+			require.NoError(t, fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: testPasswordSecret}, secret))
+			password := secret.Data["password"]
+
+			if test.expectRotated {
+				assert.NotEqual(t, testPassword, string(password), "expected new password")
+				assert.NoError(t, bcrypt.CompareHashAndPassword(remoteHash, password), "remote hash does not match new password")
+				if test.pendingPassword != "" {
+					assert.Equal(t, test.pendingPassword, string(password), "expected pending password to be used")
+				}
+				if assert.NotNil(t, cd.Status.KubeadminPassword, "expected kubeadmin password status") &&
+					assert.NotNil(t, cd.Status.KubeadminPassword.LastRotationTime, "expected last rotation time") {
+					assert.WithinDuration(t, now, cd.Status.KubeadminPassword.LastRotationTime.Time, time.Minute, "unexpected last rotation time")
+				}
+			} else {
+				assert.Equal(t, testPassword, string(password), "unexpected change to password")
+				assert.Nil(t, remoteHash, "unexpected change to remote password")
+			}
+
+			if test.expectPending {
+				assert.NotEmpty(t, secret.Data["pendingPassword"], "expected pending password")
+			} else {
+				assert.NotContains(t, secret.Data, "pendingPassword", "unexpected pending password")
+			}
+
+			if test.expectRemoved {
+				if assert.NotNil(t, cd.Status.KubeadminPassword, "expected kubeadmin password status") {
+					assert.True(t, cd.Status.KubeadminPassword.Removed, "expected kubeadmin to be removed")
+				}
+			} else if cd.Status.KubeadminPassword != nil {
+				assert.False(t, cd.Status.KubeadminPassword.Removed, "unexpected kubeadmin removal")
+			}
+			assert.Equal(t, test.expectRemoteRemoval, removed, "unexpected removal of kubeadmin from cluster")
+
+			if test.expectEvent == "" {
+				assert.Empty(t, fakeRecorder.Events, "unexpected event")
+			} else if assert.Len(t, fakeRecorder.Events, 1, "expected event") {
+				assert.Contains(t, <-fakeRecorder.Events, test.expectEvent, "unexpected event")
+			}
+		})
+	}
+}
+
+func TestGeneratePassword(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		password, err := generatePassword()
+		require.NoError(t, err, "unexpected error generating password")
+		assert.Regexp(t, regexp.MustCompile(`^([a-km-zA-NP-Z2-9]{5}-){3}[a-km-zA-NP-Z2-9]{5}$`), password, "unexpected password format")
+		assert.Regexp(t, "[a-z]", password, "expected lower case letter")
+		assert.Regexp(t, "[A-Z]", password, "expected upper case letter")
+		assert.Regexp(t, "[0-9]", password, "expected digit")
+	}
+}
+
+func testClusterDeployment(spec *hivev1.KubeadminPasswordSpec, modifiers ...func(*hivev1.ClusterDeployment)) *hivev1.ClusterDeployment {
+	installed := metav1.NewTime(time.Now().Add(-day))
+	cd := &hivev1.ClusterDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testName,
+			Namespace: testNamespace,
+		},
+		Spec: hivev1.ClusterDeploymentSpec{
+			ClusterName: testName,
+			BaseDomain:  "example.com",
+			Installed:   true,
+			ClusterMetadata: &hivev1.ClusterMetadata{
+				AdminKubeconfigSecretRef: corev1.LocalObjectReference{Name: "test-cluster-admin-kubeconfig"},
+				AdminPasswordSecretRef:   corev1.LocalObjectReference{Name: testPasswordSecret},
+			},
+			KubeadminPassword: spec,
+		},
+		Status: hivev1.ClusterDeploymentStatus{
+			InstalledTimestamp: &installed,
+		},
+	}
+	for _, modify := range modifiers {
+		modify(cd)
+	}
+	return cd
+}
+
+// testIdentityProviderObjects returns an identity provider syncset for the test cluster, applied successfully.
+func testIdentityProviderObjects(t *testing.T) []runtime.Object {
+	patch, err := json.Marshal(map[string]interface{}{
+		"spec": map[string]interface{}{
+			"identityProviders": []openshiftapiv1.IdentityProvider{{Name: "github"}},
+		},
+	})
+	require.NoError(t, err, "could not marshal identity provider patch")
+	ss := &hivev1.SyncSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      syncidentityprovider.GenerateIdentityProviderSyncSetName(testName),
+			Namespace: testNamespace,
+		},
+		Spec: hivev1.SyncSetSpec{
+			SyncSetCommonSpec: hivev1.SyncSetCommonSpec{
+				Patches: []hivev1.SyncObjectPatch{{
+					APIVersion: "config.openshift.io/v1",
+					Kind:       "OAuth",
+					Name:       "cluster",
+					PatchType:  "merge",
+					Patch:      string(patch),
+				}},
+			},
+			ClusterDeploymentRefs: []corev1.LocalObjectReference{{Name: testName}},
+		},
+	}
+	hash, err := controllerutils.GetChecksumOfObject(ss.Spec.Patches[0])
+	require.NoError(t, err, "could not compute patch hash")
+	ssi := &hivev1.SyncSetInstance{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testName + "-idp-syncset",
+			Namespace: testNamespace,
+		},
+		Spec: hivev1.SyncSetInstanceSpec{
+			ClusterDeploymentRef: corev1.LocalObjectReference{Name: testName},
+			SyncSetRef:           &corev1.LocalObjectReference{Name: ss.Name},
+		},
+		Status: hivev1.SyncSetInstanceStatus{
+			Patches: []hivev1.SyncStatus{{
+				APIVersion: "config.openshift.io/v1",
+				Kind:       "OAuth",
+				Name:       "cluster",
+				Hash:       hash,
+				Conditions: []hivev1.SyncCondition{{
+					Type:   hivev1.ApplySuccessSyncCondition,
+					Status: corev1.ConditionTrue,
+				}},
+			}},
+		},
+	}
+	return []runtime.Object{ss, ssi}
+}
@@ -0,0 +1,57 @@
+package kubeadminpassword
+
+import (
+	"github.com/pkg/errors"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/openshift/hive/pkg/remoteclient"
+)
+
+const (
+	kubeadminSecretNamespace = "kube-system"
+	kubeadminSecretName      = "kubeadmin"
+	kubeadminSecretKey       = "kubeadmin"
+)
+
+// errKubeadminNotFound is returned when the kubeadmin user no longer exists on the remote cluster.
+var errKubeadminNotFound = errors.New("kubeadmin secret not found")
+
+// setRemotePasswordHash sets the bcrypt hash of the kubeadmin password in the kubeadmin secret of the remote cluster.
+// The secret is updated rather than recreated, since the OAuth server only accepts a kubeadmin secret created shortly
+// after the cluster.
+func setRemotePasswordHash(remoteClientBuilder remoteclient.Builder, hash []byte) error {
+	kubeClient, err := remoteClientBuilder.BuildKubeClient()
+	if err != nil {
+		return errors.Wrap(err, "could not build remote cluster client")
+	}
+	secret, err := kubeClient.CoreV1().Secrets(kubeadminSecretNamespace).Get(kubeadminSecretName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return errKubeadminNotFound
+	}
+	if err != nil {
+		return errors.Wrap(err, "could not get kubeadmin secret")
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[kubeadminSecretKey] = hash
+	if _, err := kubeClient.CoreV1().Secrets(kubeadminSecretNamespace).Update(secret); err != nil {
+		return errors.Wrap(err, "could not update kubeadmin secret")
+	}
+	return nil
+}
+
+// removeRemoteKubeadmin removes the kubeadmin user from the remote cluster by deleting its secret.
+func removeRemoteKubeadmin(remoteClientBuilder remoteclient.Builder) error {
+	kubeClient, err := remoteClientBuilder.BuildKubeClient()
+	if err != nil {
+		return errors.Wrap(err, "could not build remote cluster client")
+	}
+	err = kubeClient.CoreV1().Secrets(kubeadminSecretNamespace).Delete(kubeadminSecretName, &metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrap(err, "could not delete kubeadmin secret")
+	}
+	return nil
+}
@@ -0,0 +1,536 @@
+// Package kubeconfigrotation provides a controller which mints a kubeconfig for Hive's own use on each installed
+// cluster, and rotates its client certificate on a schedule. Once minted, the remote clients of all controllers connect
+// to the cluster with the hive-managed kubeconfig rather than the admin kubeconfig generated by the installer.
+package kubeconfigrotation
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	clientcmdlatest "k8s.io/client-go/tools/clientcmd/api/latest"
+	clientcmdv1 "k8s.io/client-go/tools/clientcmd/api/v1"
+	k8slabels "k8s.io/kubernetes/pkg/util/labels"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	"sigs.k8s.io/yaml"
+
+	apihelpers "github.com/openshift/hive/pkg/apis/helpers"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+	hivemetrics "github.com/openshift/hive/pkg/controller/metrics"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+	"github.com/openshift/hive/pkg/remoteclient"
+)
+
+const (
+	controllerName = "kubeconfigRotation"
+
+	defaultRotationInterval = 7 * 24 * time.Hour
+
+	// renewalFraction is the fraction of the validity of a client certificate after which it is renewed, whatever
+	// the rotation interval.
+	renewalFraction = 0.8
+
+	hiveManagedKubeconfigSecretSuffix = "hive-managed-kubeconfig"
+	// certificateRequestSecretSuffix is the suffix of the secret holding the client key and the name of the
+	// CertificateSigningRequest of a client certificate being issued.
+	certificateRequestSecretSuffix = "hive-managed-kubeconfig-request"
+	certificateRequestNameKey      = "csr"
+	hiveManagedContextName         = "hive-managed"
+	clientKeyBits                  = 2048
+)
+
+// Add creates a new KubeconfigRotation Controller and adds it to the Manager with default RBAC. The Manager will set fields on the
+// Controller and Start it when the Manager is Started.
+func Add(mgr manager.Manager) error {
+	r, err := NewReconciler(mgr)
+	if err != nil {
+		return err
+	}
+	return AddToManager(mgr, r)
+}
+
+// NewReconciler returns a new reconcile.Reconciler
+func NewReconciler(mgr manager.Manager) (reconcile.Reconciler, error) {
+	logger := log.WithField("controller", controllerName)
+	rotationInterval := defaultRotationInterval
+	if intervalStr := os.Getenv(constants.HiveManagedKubeconfigRotationIntervalEnvVar); intervalStr != "" {
+		var err error
+		rotationInterval, err = time.ParseDuration(intervalStr)
+		if err != nil {
+			logger.WithError(err).Errorf("Couldn't parse environment variable %v: %v", constants.HiveManagedKubeconfigRotationIntervalEnvVar, intervalStr)
+			return nil, err
+		}
+	}
+	r := &ReconcileKubeconfigRotation{
+		Client:                   controllerutils.NewClientWithMetricsOrDie(mgr, controllerName),
+		scheme:                   mgr.GetScheme(),
+		logger:                   logger,
+		rotationInterval:         rotationInterval,
+		requestCertificate:       requestRemoteCertificate,
+		fetchCertificate:         fetchRemoteCertificate,
+		deleteCertificateRequest: deleteRemoteCertificateRequest,
+		verifyConnection:         verifyRemoteConnection,
+	}
+	r.remoteClientBuilder = func(cd *hivev1.ClusterDeployment) remoteclient.Builder {
+		return remoteclient.NewUncachedBuilder(r.Client, cd, controllerName)
+	}
+	return r, nil
+}
+
+// AddToManager adds a new Controller to mgr with r as the reconcile.Reconciler
+func AddToManager(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New("kubeconfigrotation-controller", mgr, controller.Options{Reconciler: controllerutils.NewShardedReconciler(r), MaxConcurrentReconciles: controllerutils.GetConcurrentReconciles(controllerName)})
+	if err != nil {
+		return err
+	}
+
+	if err := controllerutils.SetQueueRateLimiter(c, controllerName); err != nil {
+		return err
+	}
+
+	// Watch for changes to ClusterDeployment
+	err = c.Watch(&source.Kind{Type: &hivev1.ClusterDeployment{}}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes to the hive-managed kubeconfig secrets, so that deleted secrets are minted again
+	err = c.Watch(&source.Kind{Type: &corev1.Secret{}}, &handler.EnqueueRequestForOwner{
+		IsController: true,
+		OwnerType:    &hivev1.ClusterDeployment{},
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var _ reconcile.Reconciler = &ReconcileKubeconfigRotation{}
+
+// ReconcileKubeconfigRotation mints and rotates the hive-managed kubeconfig of a ClusterDeployment object
+type ReconcileKubeconfigRotation struct {
+	client.Client
+	scheme *runtime.Scheme
+	logger log.FieldLogger
+
+	// rotationInterval is how often the client certificate of the hive-managed kubeconfig is rotated.
+	rotationInterval time.Duration
+
+	// remoteClientBuilder is a function pointer to the function that gets a builder for building a client
+	// for the remote cluster's API server
+	remoteClientBuilder func(cd *hivev1.ClusterDeployment) remoteclient.Builder
+
+	// requestCertificate is a function pointer to the function that asks the remote cluster to issue a client
+	// certificate for the hive-managed service account, given a PEM-encoded certificate request. It returns the name
+	// of the CertificateSigningRequest.
+	requestCertificate func(remoteClientBuilder remoteclient.Builder, request []byte, logger log.FieldLogger) (string, error)
+
+	// fetchCertificate is a function pointer to the function that gets the certificate signed for a
+	// CertificateSigningRequest of the remote cluster, or nil if it is not signed yet.
+	fetchCertificate func(remoteClientBuilder remoteclient.Builder, name string, logger log.FieldLogger) ([]byte, error)
+
+	// deleteCertificateRequest is a function pointer to the function that deletes a CertificateSigningRequest of the
+	// remote cluster.
+	deleteCertificateRequest func(remoteClientBuilder remoteclient.Builder, name string) error
+
+	// verifyConnection is a function pointer to the function that makes an authenticated request to the remote
+	// cluster with the given config.
+	verifyConnection func(cfg *rest.Config) error
+}
+
+// Reconcile mints the hive-managed kubeconfig of an installed cluster when it does not exist, or when its client
+// certificate is due for rotation.
+func (r *ReconcileKubeconfigRotation) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	start := time.Now()
+	cdLog := r.logger.WithFields(log.Fields{
+		"clusterDeployment": request.Name,
+		"namespace":         request.Namespace,
+	})
+
+	cdLog.Info("reconciling cluster deployment")
+	defer func() {
+		dur := time.Since(start)
+		hivemetrics.MetricControllerReconcileTime.WithLabelValues(controllerName).Observe(dur.Seconds())
+		cdLog.WithField("elapsed", dur).Info("reconcile complete")
+	}()
+
+	// Fetch the ClusterDeployment instance
+	cd := &hivev1.ClusterDeployment{}
+	err := r.Get(context.TODO(), request.NamespacedName, cd)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		cdLog.WithError(err).Error("error looking up cluster deployment")
+		return reconcile.Result{}, err
+	}
+
+	// If the clusterdeployment is deleted, do not reconcile.
+	if cd.DeletionTimestamp != nil {
+		return reconcile.Result{}, nil
+	}
+
+	if !cd.Spec.Installed {
+		cdLog.Debug("cluster installation is not complete")
+		return reconcile.Result{}, nil
+	}
+
+	if cd.Spec.ClusterMetadata == nil {
+		cdLog.Error("installed cluster with no cluster metadata")
+		return reconcile.Result{}, nil
+	}
+
+	secretName := hiveManagedKubeconfigSecretName(cd)
+	secret := &corev1.Secret{}
+	err = r.Get(context.TODO(), types.NamespacedName{Namespace: cd.Namespace, Name: secretName}, secret)
+	switch {
+	case apierrors.IsNotFound(err):
+		secret = nil
+	case err != nil:
+		cdLog.WithError(err).Error("error looking up hive-managed kubeconfig secret")
+		return reconcile.Result{}, err
+	}
+
+	if secret != nil {
+		rotateAt, err := r.rotationTime(secret.Data[constants.KubeconfigSecretKey])
+		if err != nil {
+			cdLog.WithError(err).Warn("could not parse hive-managed kubeconfig, minting a new kubeconfig")
+		} else if time.Now().Before(rotateAt) {
+			if err := r.setHiveManagedKubeconfigSecretRef(cd, secretName, cdLog); err != nil {
+				return reconcile.Result{}, err
+			}
+			cdLog.WithField("rotateAt", rotateAt).Debug("hive-managed kubeconfig is current")
+			return reconcile.Result{RequeueAfter: time.Until(rotateAt)}, nil
+		}
+	}
+
+	if r.remoteClientBuilder(cd).Unreachable() {
+		cdLog.Debug("skipping kubeconfig rotation for unreachable cluster")
+		return reconcile.Result{}, nil
+	}
+
+	rotated, err := r.rotate(cd, secret, secretName, cdLog)
+	if err != nil {
+		cdLog.WithError(err).Error("failed to mint hive-managed kubeconfig")
+		return reconcile.Result{}, err
+	}
+	if !rotated {
+		cdLog.Debug("waiting for client certificate to be issued")
+		return reconcile.Result{RequeueAfter: certificateIssueInterval}, nil
+	}
+	if err := r.setHiveManagedKubeconfigSecretRef(cd, secretName, cdLog); err != nil {
+		return reconcile.Result{}, err
+	}
+	// The rotation time of the new kubeconfig is picked up when the secret change is reconciled.
+	return reconcile.Result{}, nil
+}
+
+// rotationTime returns when the client certificate of the kubeconfig is due for rotation.
+func (r *ReconcileKubeconfigRotation) rotationTime(kubeconfig []byte) (time.Time, error) {
+	certificate, err := clientCertificate(kubeconfig)
+	if err != nil {
+		return time.Time{}, err
+	}
+	validity := certificate.NotAfter.Sub(certificate.NotBefore)
+	rotateAt := certificate.NotBefore.Add(time.Duration(float64(validity) * renewalFraction))
+	if intervalEnd := certificate.NotBefore.Add(r.rotationInterval); intervalEnd.Before(rotateAt) {
+		rotateAt = intervalEnd
+	}
+	return rotateAt, nil
+}
+
+// rotate has the remote cluster issue a new client certificate for the hive-managed service account, and saves the
+// kubeconfig with the new certificate once it has been verified to connect to the cluster. The certificate is issued
+// across reconciles: the client key and the name of the CertificateSigningRequest are kept in a secret until the
+// request is signed. It returns whether the kubeconfig was saved. The client certificate of the previous kubeconfig is
+// not revoked, since Kubernetes cannot revoke client certificates, and stays valid until it expires.
+func (r *ReconcileKubeconfigRotation) rotate(cd *hivev1.ClusterDeployment, secret *corev1.Secret, secretName string, cdLog log.FieldLogger) (bool, error) {
+	cluster, err := r.kubeconfigCluster(cd, secret)
+	if err != nil {
+		return false, err
+	}
+
+	requestSecret := &corev1.Secret{}
+	err = r.Get(context.TODO(), types.NamespacedName{Namespace: cd.Namespace, Name: certificateRequestSecretName(cd)}, requestSecret)
+	switch {
+	case apierrors.IsNotFound(err):
+		return false, r.requestClientCertificate(cd, cdLog)
+	case err != nil:
+		return false, errors.Wrap(err, "could not get certificate request secret")
+	}
+	key, err := x509.ParsePKCS1PrivateKey(pemBlock(requestSecret.Data[corev1.TLSPrivateKeyKey]))
+	if err != nil {
+		cdLog.WithError(err).Warn("could not parse client key of certificate request, making a new request")
+		return false, r.Delete(context.TODO(), requestSecret)
+	}
+	csrName := string(requestSecret.Data[certificateRequestNameKey])
+	cdLog = cdLog.WithField("csr", csrName)
+
+	certificate, err := r.fetchCertificate(r.remoteClientBuilder(cd), csrName, cdLog)
+	if err != nil {
+		if errors.Cause(err) == errCertificateRequestFailed {
+			r.deleteCertificateRequestSecret(cd, requestSecret, csrName, cdLog)
+		}
+		return false, errors.Wrap(err, "could not issue client certificate")
+	}
+	if certificate == nil {
+		return false, nil
+	}
+
+	authInfo := clientcmdapi.NewAuthInfo()
+	authInfo.ClientCertificateData = certificate
+	authInfo.ClientKeyData = pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	kubeContext := clientcmdapi.NewContext()
+	kubeContext.Cluster = hiveManagedContextName
+	kubeContext.AuthInfo = hiveManagedContextName
+	cfg := clientcmdapi.NewConfig()
+	cfg.Clusters[hiveManagedContextName] = cluster
+	cfg.AuthInfos[hiveManagedContextName] = authInfo
+	cfg.Contexts[hiveManagedContextName] = kubeContext
+	cfg.CurrentContext = hiveManagedContextName
+	kubeconfig, err := writeKubeconfig(cfg)
+	if err != nil {
+		return false, errors.Wrap(err, "could not write kubeconfig")
+	}
+
+	// Verify the new kubeconfig before saving it, so that a kubeconfig which does not work never replaces the
+	// previous one.
+	if err := r.verifyKubeconfig(cd, kubeconfig); err != nil {
+		return false, errors.Wrap(err, "could not connect to cluster with new hive-managed kubeconfig")
+	}
+
+	exists := secret != nil
+	if !exists {
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      secretName,
+				Namespace: cd.Namespace,
+			},
+		}
+	}
+	secret.Type = corev1.SecretTypeOpaque
+	secret.Data = map[string][]byte{constants.KubeconfigSecretKey: kubeconfig}
+	secret.Labels = k8slabels.AddLabel(secret.Labels, constants.ClusterDeploymentNameLabel, cd.Name)
+	secret.Labels = k8slabels.AddLabel(secret.Labels, constants.SecretTypeLabel, constants.SecretTypeKubeConfig)
+	if metav1.GetControllerOf(secret) == nil {
+		if err := controllerutil.SetControllerReference(cd, secret, r.scheme); err != nil {
+			return false, errors.Wrap(err, "error setting owner reference")
+		}
+	}
+	if exists {
+		err = r.Update(context.TODO(), secret)
+	} else {
+		err = r.Create(context.TODO(), secret)
+	}
+	if err != nil {
+		return false, errors.Wrap(err, "could not save hive-managed kubeconfig secret")
+	}
+	cdLog.Info("rotated hive-managed kubeconfig")
+	r.deleteCertificateRequestSecret(cd, requestSecret, csrName, cdLog)
+	return true, nil
+}
+
+// requestClientCertificate generates a client key and asks the remote cluster to issue a certificate for it. The key
+// and the name of the CertificateSigningRequest are saved in the certificate request secret.
+func (r *ReconcileKubeconfigRotation) requestClientCertificate(cd *hivev1.ClusterDeployment, cdLog log.FieldLogger) error {
+	key, err := rsa.GenerateKey(rand.Reader, clientKeyBits)
+	if err != nil {
+		return errors.Wrap(err, "could not generate client key")
+	}
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{
+			CommonName:   hiveManagedUsername,
+			Organization: hiveManagedGroups,
+		},
+	}, key)
+	if err != nil {
+		return errors.Wrap(err, "could not create certificate request")
+	}
+
+	cdLog.Info("requesting client certificate for hive-managed kubeconfig")
+	csrName, err := r.requestCertificate(r.remoteClientBuilder(cd), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csr}), cdLog)
+	if err != nil {
+		return errors.Wrap(err, "could not request client certificate")
+	}
+
+	requestSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      certificateRequestSecretName(cd),
+			Namespace: cd.Namespace,
+			Labels:    map[string]string{constants.ClusterDeploymentNameLabel: cd.Name},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			corev1.TLSPrivateKeyKey:   pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+			certificateRequestNameKey: []byte(csrName),
+		},
+	}
+	if err := controllerutil.SetControllerReference(cd, requestSecret, r.scheme); err != nil {
+		return errors.Wrap(err, "error setting owner reference")
+	}
+	if err := r.Create(context.TODO(), requestSecret); err != nil {
+		if err := r.deleteCertificateRequest(r.remoteClientBuilder(cd), csrName); err != nil {
+			cdLog.WithError(err).Warn("could not delete certificate signing request")
+		}
+		return errors.Wrap(err, "could not save certificate request secret")
+	}
+	return nil
+}
+
+// deleteCertificateRequestSecret deletes the CertificateSigningRequest of the remote cluster and the certificate
+// request secret, once the request has been used or has failed. Errors are only logged, since a request that is left
+// behind is superseded by the next one.
+func (r *ReconcileKubeconfigRotation) deleteCertificateRequestSecret(cd *hivev1.ClusterDeployment, requestSecret *corev1.Secret, csrName string, cdLog log.FieldLogger) {
+	if err := r.deleteCertificateRequest(r.remoteClientBuilder(cd), csrName); err != nil {
+		cdLog.WithError(err).Warn("could not delete certificate signing request")
+	}
+	if err := r.Delete(context.TODO(), requestSecret); err != nil && !apierrors.IsNotFound(err) {
+		cdLog.WithError(err).Warn("could not delete certificate request secret")
+	}
+}
+
+// verifyKubeconfig makes a request to the cluster with the given kubeconfig, connecting to the API URL in use by the
+// remote clients of the cluster and through its proxy, if any.
+func (r *ReconcileKubeconfigRotation) verifyKubeconfig(cd *hivev1.ClusterDeployment, kubeconfig []byte) error {
+	cfg, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return errors.Wrap(err, "could not load kubeconfig")
+	}
+	activeCfg, err := r.remoteClientBuilder(cd).RESTConfig()
+	if err != nil {
+		return errors.Wrap(err, "could not get remote cluster config")
+	}
+	cfg.Host = activeCfg.Host
+	proxy, err := remoteclient.GetProxy(r.Client, cd)
+	if err != nil {
+		return errors.Wrap(err, "could not get proxy")
+	}
+	if proxy != nil {
+		if err := remoteclient.ConfigureProxy(cfg, proxy); err != nil {
+			return errors.Wrap(err, "could not configure proxy")
+		}
+	}
+	return r.verifyConnection(cfg)
+}
+
+// kubeconfigCluster returns the cluster entry of the kubeconfig used to connect to the cluster, to be used by the
+// hive-managed kubeconfig. The admin kubeconfig is preferred since it carries the additional certificate authorities
+// configured in HiveConfig.
+func (r *ReconcileKubeconfigRotation) kubeconfigCluster(cd *hivev1.ClusterDeployment, hiveManagedSecret *corev1.Secret) (*clientcmdapi.Cluster, error) {
+	adminSecret := &corev1.Secret{}
+	err := r.Get(context.TODO(), types.NamespacedName{Namespace: cd.Namespace, Name: cd.Spec.ClusterMetadata.AdminKubeconfigSecretRef.Name}, adminSecret)
+	switch {
+	case err == nil:
+		return currentCluster(adminSecret.Data[constants.KubeconfigSecretKey])
+	case !apierrors.IsNotFound(err):
+		return nil, errors.Wrap(err, "could not get admin kubeconfig secret")
+	case hiveManagedSecret != nil:
+		return currentCluster(hiveManagedSecret.Data[constants.KubeconfigSecretKey])
+	}
+	return nil, errors.New("neither the admin kubeconfig nor the hive-managed kubeconfig exists")
+}
+
+func (r *ReconcileKubeconfigRotation) setHiveManagedKubeconfigSecretRef(cd *hivev1.ClusterDeployment, secretName string, cdLog log.FieldLogger) error {
+	if ref := cd.Spec.ClusterMetadata.HiveManagedKubeconfigSecretRef; ref != nil && ref.Name == secretName {
+		return nil
+	}
+	cd.Spec.ClusterMetadata.HiveManagedKubeconfigSecretRef = &corev1.LocalObjectReference{Name: secretName}
+	if err := r.Update(context.TODO(), cd); err != nil {
+		cdLog.WithError(err).Log(controllerutils.LogLevel(err), "error setting hive-managed kubeconfig secret reference")
+		return err
+	}
+	return nil
+}
+
+func hiveManagedKubeconfigSecretName(cd *hivev1.ClusterDeployment) string {
+	return apihelpers.GetResourceName(cd.Name, hiveManagedKubeconfigSecretSuffix)
+}
+
+func certificateRequestSecretName(cd *hivev1.ClusterDeployment) string {
+	return apihelpers.GetResourceName(cd.Name, certificateRequestSecretSuffix)
+}
+
+// pemBlock returns the bytes of the first PEM block of data, or nil if there is none.
+func pemBlock(data []byte) []byte {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil
+	}
+	return block.Bytes
+}
+
+// writeKubeconfig serializes a kubeconfig in its v1 form.
+func writeKubeconfig(cfg *clientcmdapi.Config) ([]byte, error) {
+	v1Config := &clientcmdv1.Config{}
+	if err := clientcmdlatest.Scheme.Convert(cfg, v1Config, nil); err != nil {
+		return nil, err
+	}
+	v1Config.APIVersion = clientcmdv1.SchemeGroupVersion.Version
+	v1Config.Kind = "Config"
+	return yaml.Marshal(v1Config)
+}
+
+// currentCluster returns the cluster entry of the current context of a kubeconfig.
+func currentCluster(kubeconfig []byte) (*clientcmdapi.Cluster, error) {
+	cfg, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not load kubeconfig")
+	}
+	kubeContext, ok := cfg.Contexts[cfg.CurrentContext]
+	if !ok {
+		return nil, fmt.Errorf("kubeconfig has no context %q", cfg.CurrentContext)
+	}
+	cluster, ok := cfg.Clusters[kubeContext.Cluster]
+	if !ok {
+		return nil, fmt.Errorf("kubeconfig has no cluster %q", kubeContext.Cluster)
+	}
+	return cluster, nil
+}
+
+// clientCertificate returns the client certificate of the current context of a kubeconfig.
+func clientCertificate(kubeconfig []byte) (*x509.Certificate, error) {
+	cfg, err := clientcmd.Load(kubeconfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not load kubeconfig")
+	}
+	kubeContext, ok := cfg.Contexts[cfg.CurrentContext]
+	if !ok {
+		return nil, fmt.Errorf("kubeconfig has no context %q", cfg.CurrentContext)
+	}
+	authInfo, ok := cfg.AuthInfos[kubeContext.AuthInfo]
+	if !ok {
+		return nil, fmt.Errorf("kubeconfig has no user %q", kubeContext.AuthInfo)
+	}
+	block, _ := pem.Decode(authInfo.ClientCertificateData)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, errors.New("kubeconfig has no client certificate")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
@@ -0,0 +1,459 @@
+package kubeconfigrotation
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	pkgerrors "github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openshift/hive/pkg/apis"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+	"github.com/openshift/hive/pkg/remoteclient"
+	remoteclientmock "github.com/openshift/hive/pkg/remoteclient/mock"
+)
+
+const (
+	testName                     = "test-cluster"
+	testNamespace                = "test-namespace"
+	testAdminKubeconfigSecret    = "test-cluster-admin-kubeconfig"
+	testHiveManagedSecret        = "test-cluster-hive-managed-kubeconfig"
+	testCertificateRequestSecret = "test-cluster-hive-managed-kubeconfig-request"
+	testCSRName                  = "hive-managed-abcde"
+	testAPIURL                   = "https://api.test-cluster.example.com:6443"
+	testActiveAPIURL             = "https://api-override.test-cluster.example.com:6443"
+
+	day = 24 * time.Hour
+)
+
+func init() {
+	log.SetLevel(log.DebugLevel)
+}
+
+func TestReconcileKubeconfigRotation(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	tests := []struct {
+		name string
+		cd   *hivev1.ClusterDeployment
+		// existingValidity is the validity of the client certificate of the existing hive-managed kubeconfig, relative
+		// to now. No hive-managed kubeconfig exists when nil.
+		existingValidity *[2]time.Duration
+		// pendingRequest is whether a client certificate has already been requested from the remote cluster.
+		pendingRequest       bool
+		notSigned            bool
+		fetchErr             error
+		unreachable          bool
+		verifyErr            error
+		expectRequested      bool
+		expectIssued         bool
+		expectPendingRequest bool
+		expectErr            bool
+		expectRef            bool
+		expectRequeue        time.Duration
+	}{
+		{
+			name: "not installed",
+			cd:   testClusterDeployment(func(cd *hivev1.ClusterDeployment) { cd.Spec.Installed = false }),
+		},
+		{
+			name:                 "no hive-managed kubeconfig",
+			cd:                   testClusterDeployment(),
+			expectRequested:      true,
+			expectPendingRequest: true,
+			expectRequeue:        certificateIssueInterval,
+		},
+		{
+			name:           "client certificate issued",
+			cd:             testClusterDeployment(),
+			pendingRequest: true,
+			expectIssued:   true,
+			expectRef:      true,
+		},
+		{
+			name:                 "client certificate not issued yet",
+			cd:                   testClusterDeployment(),
+			pendingRequest:       true,
+			notSigned:            true,
+			expectPendingRequest: true,
+			expectRequeue:        certificateIssueInterval,
+		},
+		{
+			name:           "client certificate request failed",
+			cd:             testClusterDeployment(),
+			pendingRequest: true,
+			fetchErr:       pkgerrors.Wrap(errCertificateRequestFailed, "denied"),
+			expectErr:      true,
+		},
+		{
+			name:                 "client certificate request not reachable",
+			cd:                   testClusterDeployment(),
+			pendingRequest:       true,
+			fetchErr:             errors.New("connection refused"),
+			expectPendingRequest: true,
+			expectErr:            true,
+		},
+		{
+			name:             "current hive-managed kubeconfig",
+			cd:               testClusterDeployment(),
+			existingValidity: &[2]time.Duration{-day, 30 * day},
+			expectRef:        true,
+			expectRequeue:    6 * day,
+		},
+		{
+			name:                 "rotation interval passed",
+			cd:                   testClusterDeployment(withHiveManagedRef),
+			existingValidity:     &[2]time.Duration{-8 * day, 30 * day},
+			expectRequested:      true,
+			expectPendingRequest: true,
+			expectRef:            true,
+			expectRequeue:        certificateIssueInterval,
+		},
+		{
+			name:             "rotated client certificate issued",
+			cd:               testClusterDeployment(withHiveManagedRef),
+			existingValidity: &[2]time.Duration{-8 * day, 30 * day},
+			pendingRequest:   true,
+			expectIssued:     true,
+			expectRef:        true,
+		},
+		{
+			name:             "client certificate nearing expiry",
+			cd:               testClusterDeployment(withHiveManagedRef),
+			existingValidity: &[2]time.Duration{-day, time.Hour},
+			pendingRequest:   true,
+			expectIssued:     true,
+			expectRef:        true,
+		},
+		{
+			name:        "unreachable cluster",
+			cd:          testClusterDeployment(),
+			unreachable: true,
+		},
+		{
+			name:                 "new kubeconfig does not connect",
+			cd:                   testClusterDeployment(),
+			pendingRequest:       true,
+			verifyErr:            errors.New("unauthorized"),
+			expectIssued:         true,
+			expectPendingRequest: true,
+			expectErr:            true,
+		},
+		{
+			name:                 "rotated kubeconfig does not connect",
+			cd:                   testClusterDeployment(withHiveManagedRef),
+			existingValidity:     &[2]time.Duration{-8 * day, 30 * day},
+			pendingRequest:       true,
+			verifyErr:            errors.New("unauthorized"),
+			expectIssued:         true,
+			expectPendingRequest: true,
+			expectErr:            true,
+			expectRef:            true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			ca := newTestCA(t)
+			now := time.Now().Truncate(time.Second)
+
+			existing := []runtime.Object{
+				test.cd,
+				testSecret(testAdminKubeconfigSecret, testKubeconfig(t, nil, nil)),
+			}
+			var existingKubeconfig []byte
+			if test.existingValidity != nil {
+				certificate, key := ca.issue(t, now.Add(test.existingValidity[0]), now.Add(test.existingValidity[1]))
+				existingKubeconfig = testKubeconfig(t, certificate, key)
+				existing = append(existing, testSecret(testHiveManagedSecret, existingKubeconfig))
+			}
+			var pendingKey *rsa.PrivateKey
+			if test.pendingRequest {
+				var err error
+				pendingKey, err = rsa.GenerateKey(rand.Reader, clientKeyBits)
+				require.NoError(t, err, "could not generate client key")
+				existing = append(existing, testRequestSecret(pendingKey))
+			}
+			fakeClient := fake.NewFakeClient(existing...)
+
+			mockRemoteClientBuilder := remoteclientmock.NewMockBuilder(mockCtrl)
+			if test.expectRequested || test.pendingRequest || test.unreachable {
+				mockRemoteClientBuilder.EXPECT().Unreachable().Return(test.unreachable)
+			}
+			if test.expectIssued {
+				mockRemoteClientBuilder.EXPECT().RESTConfig().Return(&rest.Config{Host: testActiveAPIURL}, nil)
+			}
+
+			requested := false
+			issued := false
+			var deletedRequests []string
+			var verifiedConfig *rest.Config
+			r := &ReconcileKubeconfigRotation{
+				Client:           fakeClient,
+				scheme:           scheme.Scheme,
+				logger:           log.WithField("controller", controllerName),
+				rotationInterval: defaultRotationInterval,
+				remoteClientBuilder: func(*hivev1.ClusterDeployment) remoteclient.Builder {
+					return mockRemoteClientBuilder
+				},
+				requestCertificate: func(_ remoteclient.Builder, request []byte, _ log.FieldLogger) (string, error) {
+					requested = true
+					return testCSRName, nil
+				},
+				fetchCertificate: func(_ remoteclient.Builder, name string, _ log.FieldLogger) ([]byte, error) {
+					assert.Equal(t, testCSRName, name, "unexpected certificate signing request")
+					if test.fetchErr != nil || test.notSigned {
+						return nil, test.fetchErr
+					}
+					issued = true
+					return ca.sign(t, testCertificateRequest(t, pendingKey), now, now.Add(30*day)), nil
+				},
+				deleteCertificateRequest: func(_ remoteclient.Builder, name string) error {
+					deletedRequests = append(deletedRequests, name)
+					return nil
+				},
+				verifyConnection: func(cfg *rest.Config) error {
+					verifiedConfig = cfg
+					return test.verifyErr
+				},
+			}
+
+			key := types.NamespacedName{Name: testName, Namespace: testNamespace}
+			result, err := r.Reconcile(reconcile.Request{NamespacedName: key})
+			if test.expectErr {
+				assert.Error(t, err, "expected error from reconcile")
+			} else {
+				require.NoError(t, err, "unexpected error from reconcile")
+			}
+			assert.Equal(t, test.expectRequested, requested, "unexpected certificate request")
+			assert.Equal(t, test.expectIssued, issued, "unexpected certificate issuance")
+			if test.expectIssued && assert.NotNil(t, verifiedConfig, "expected new kubeconfig to be verified") {
+				assert.Equal(t, testActiveAPIURL, verifiedConfig.Host, "expected verification against the active API URL")
+				assert.NotEmpty(t, verifiedConfig.CertData, "expected verification with the new client certificate")
+			}
+			assert.InDelta(t, test.expectRequeue.Seconds(), result.RequeueAfter.Seconds(), 60, "unexpected requeue")
+
+			cd := &hivev1.ClusterDeployment{}
+			require.NoError(t, fakeClient.Get(context.TODO(), key, cd))
+			if test.expectRef {
+				if assert.NotNil(t, cd.Spec.ClusterMetadata.HiveManagedKubeconfigSecretRef, "expected hive-managed kubeconfig secret reference") {
+					assert.Equal(t, testHiveManagedSecret, cd.Spec.ClusterMetadata.HiveManagedKubeconfigSecretRef.Name, "unexpected secret reference")
+				}
+			} else {
+				assert.Nil(t, cd.Spec.ClusterMetadata.HiveManagedKubeconfigSecretRef, "unexpected hive-managed kubeconfig secret reference")
+			}
+
+			requestSecret := &corev1.Secret{}
+			err = fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: testCertificateRequestSecret}, requestSecret)
+			if test.expectPendingRequest {
+				if assert.NoError(t, err, "expected certificate request secret") {
+					assert.Equal(t, testCSRName, string(requestSecret.Data[certificateRequestNameKey]), "unexpected certificate signing request")
+				}
+				assert.Empty(t, deletedRequests, "unexpected deletion of certificate signing request")
+			} else {
+				assert.True(t, apierrors.IsNotFound(err), "unexpected certificate request secret")
+				if test.pendingRequest {
+					assert.Equal(t, []string{testCSRName}, deletedRequests, "expected certificate signing request to be deleted")
+				}
+			}
+
+			secret := &corev1.Secret{}
+			err = fakeClient.Get(context.TODO(), types.NamespacedName{Namespace: testNamespace, Name: testHiveManagedSecret}, secret)
+			switch {
+			case test.expectIssued && test.verifyErr == nil:
+				require.NoError(t, err, "expected hive-managed kubeconfig secret")
+				kubeconfig := secret.Data[constants.KubeconfigSecretKey]
+				assert.NotEqual(t, existingKubeconfig, kubeconfig, "expected rotated kubeconfig")
+				cluster, err := currentCluster(kubeconfig)
+				require.NoError(t, err, "unexpected error reading kubeconfig cluster")
+				assert.Equal(t, testAPIURL, cluster.Server, "unexpected server")
+				certificate, err := clientCertificate(kubeconfig)
+				require.NoError(t, err, "unexpected error reading kubeconfig client certificate")
+				assert.Equal(t, hiveManagedUsername, certificate.Subject.CommonName, "unexpected username")
+				assert.Equal(t, hiveManagedGroups, certificate.Subject.Organization, "unexpected groups")
+				assert.Equal(t, constants.SecretTypeKubeConfig, secret.Labels[constants.SecretTypeLabel], "unexpected secret type label")
+				assert.Equal(t, testName, secret.Labels[constants.ClusterDeploymentNameLabel], "unexpected cluster deployment label")
+				assert.Equal(t, verifiedConfig.CertData, authInfoCertificate(t, kubeconfig), "expected saved kubeconfig to be the verified one")
+			case existingKubeconfig != nil:
+				require.NoError(t, err, "expected hive-managed kubeconfig secret")
+				assert.Equal(t, existingKubeconfig, secret.Data[constants.KubeconfigSecretKey], "unexpected change to kubeconfig")
+			default:
+				assert.True(t, apierrors.IsNotFound(err), "unexpected hive-managed kubeconfig secret")
+			}
+		})
+	}
+}
+
+func withHiveManagedRef(cd *hivev1.ClusterDeployment) {
+	cd.Spec.ClusterMetadata.HiveManagedKubeconfigSecretRef = &corev1.LocalObjectReference{Name: testHiveManagedSecret}
+}
+
+func testClusterDeployment(modifiers ...func(*hivev1.ClusterDeployment)) *hivev1.ClusterDeployment {
+	cd := &hivev1.ClusterDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      testName,
+			Namespace: testNamespace,
+		},
+		Spec: hivev1.ClusterDeploymentSpec{
+			ClusterName: testName,
+			BaseDomain:  "example.com",
+			Installed:   true,
+			ClusterMetadata: &hivev1.ClusterMetadata{
+				AdminKubeconfigSecretRef: corev1.LocalObjectReference{Name: testAdminKubeconfigSecret},
+			},
+		},
+	}
+	for _, modify := range modifiers {
+		modify(cd)
+	}
+	return cd
+}
+
+func testRequestSecret(key *rsa.PrivateKey) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: testCertificateRequestSecret, Namespace: testNamespace},
+		Data: map[string][]byte{
+			corev1.TLSPrivateKeyKey:   pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+			certificateRequestNameKey: []byte(testCSRName),
+		},
+	}
+}
+
+// testCertificateRequest returns a PEM-encoded certificate request of the hive-managed service account for the key.
+func testCertificateRequest(t *testing.T, key *rsa.PrivateKey) []byte {
+	request, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{
+			CommonName:   hiveManagedUsername,
+			Organization: hiveManagedGroups,
+		},
+	}, key)
+	require.NoError(t, err, "could not create certificate request")
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: request})
+}
+
+func testSecret(name string, kubeconfig []byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+		Data: map[string][]byte{
+			constants.KubeconfigSecretKey: kubeconfig,
+		},
+	}
+}
+
+// testKubeconfig returns a kubeconfig for the test cluster, authenticating with the given client certificate, or with a
+// token when nil.
+func testKubeconfig(t *testing.T, certificate, key []byte) []byte {
+	authInfo := clientcmdapi.NewAuthInfo()
+	if certificate == nil {
+		authInfo.Token = "admin-token"
+	} else {
+		authInfo.ClientCertificateData = certificate
+		authInfo.ClientKeyData = key
+	}
+	cluster := clientcmdapi.NewCluster()
+	cluster.Server = testAPIURL
+	kubeContext := clientcmdapi.NewContext()
+	kubeContext.Cluster = "cluster"
+	kubeContext.AuthInfo = "user"
+	cfg := clientcmdapi.NewConfig()
+	cfg.Clusters["cluster"] = cluster
+	cfg.AuthInfos["user"] = authInfo
+	cfg.Contexts["admin"] = kubeContext
+	cfg.CurrentContext = "admin"
+	kubeconfig, err := writeKubeconfig(cfg)
+	require.NoError(t, err, "could not write kubeconfig")
+	return kubeconfig
+}
+
+type testCA struct {
+	key         *ecdsa.PrivateKey
+	certificate *x509.Certificate
+}
+
+func newTestCA(t *testing.T) *testCA {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err, "could not generate CA key")
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-365 * day),
+		NotAfter:              time.Now().Add(365 * day),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+	require.NoError(t, err, "could not create CA certificate")
+	certificate, err := x509.ParseCertificate(der)
+	require.NoError(t, err, "could not parse CA certificate")
+	return &testCA{key: key, certificate: certificate}
+}
+
+// sign returns the PEM-encoded certificate for the given PEM-encoded certificate request.
+func (ca *testCA) sign(t *testing.T, request []byte, notBefore, notAfter time.Time) []byte {
+	block, _ := pem.Decode(request)
+	require.NotNil(t, block, "could not decode certificate request")
+	csr, err := x509.ParseCertificateRequest(block.Bytes)
+	require.NoError(t, err, "could not parse certificate request")
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      csr.Subject,
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca.certificate, csr.PublicKey, ca.key)
+	require.NoError(t, err, "could not create certificate")
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+// issue returns a PEM-encoded client certificate and key.
+func (ca *testCA) issue(t *testing.T, notBefore, notAfter time.Time) ([]byte, []byte) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err, "could not generate key")
+	request, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: hiveManagedUsername},
+	}, key)
+	require.NoError(t, err, "could not create certificate request")
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err, "could not marshal key")
+	return ca.sign(t, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: request}), notBefore, notAfter),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// authInfoCertificate returns the PEM-encoded client certificate of the current context of a kubeconfig.
+func authInfoCertificate(t *testing.T, kubeconfig []byte) []byte {
+	cfg, err := clientcmd.Load(kubeconfig)
+	require.NoError(t, err, "unexpected error loading kubeconfig")
+	return cfg.AuthInfos[cfg.Contexts[cfg.CurrentContext].AuthInfo].ClientCertificateData
+}
@@ -0,0 +1,181 @@
+package kubeconfigrotation
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	certificatesv1beta1 "k8s.io/api/certificates/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/openshift/hive/pkg/remoteclient"
+)
+
+const (
+	hiveManagedNamespace          = "openshift-hive-managed"
+	hiveManagedServiceAccount     = "hive"
+	hiveManagedClusterRoleBinding = "hive-managed-cluster-admin"
+
+	// certificateIssueInterval is how long to wait for a CertificateSigningRequest to be signed before checking it
+	// again, and certificateIssueTimeout how long to wait in total before making a new request.
+	certificateIssueInterval = 2 * time.Second
+	certificateIssueTimeout  = time.Minute
+)
+
+var (
+	// hiveManagedUsername and hiveManagedGroups identify the hive-managed client certificate as the hive service
+	// account, so that the access of the hive-managed kubeconfig can be managed through the RBAC of the service
+	// account.
+	hiveManagedUsername = fmt.Sprintf("system:serviceaccount:%s:%s", hiveManagedNamespace, hiveManagedServiceAccount)
+	hiveManagedGroups   = []string{"system:serviceaccounts", "system:serviceaccounts:" + hiveManagedNamespace}
+)
+
+// errCertificateRequestFailed is the cause of the errors returned for a CertificateSigningRequest that will never be
+// signed, so that a new request is made.
+var errCertificateRequestFailed = errors.New("certificate signing request failed")
+
+// requestRemoteCertificate makes sure the hive service account exists on the remote cluster with cluster-admin access,
+// then creates and approves a CertificateSigningRequest for the given certificate request. It returns the name of the
+// CertificateSigningRequest.
+func requestRemoteCertificate(remoteClientBuilder remoteclient.Builder, request []byte, logger log.FieldLogger) (string, error) {
+	kubeClient, err := remoteClientBuilder.BuildKubeClient()
+	if err != nil {
+		return "", errors.Wrap(err, "could not build remote cluster client")
+	}
+	if err := ensureServiceAccount(kubeClient, logger); err != nil {
+		return "", err
+	}
+
+	csrClient := kubeClient.CertificatesV1beta1().CertificateSigningRequests()
+	csr, err := csrClient.Create(&certificatesv1beta1.CertificateSigningRequest{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: "hive-managed-",
+		},
+		Spec: certificatesv1beta1.CertificateSigningRequestSpec{
+			Request: request,
+			Usages: []certificatesv1beta1.KeyUsage{
+				certificatesv1beta1.UsageDigitalSignature,
+				certificatesv1beta1.UsageKeyEncipherment,
+				certificatesv1beta1.UsageClientAuth,
+			},
+		},
+	})
+	if err != nil {
+		return "", errors.Wrap(err, "could not create certificate signing request")
+	}
+	logger = logger.WithField("csr", csr.Name)
+
+	csr.Status.Conditions = append(csr.Status.Conditions, certificatesv1beta1.CertificateSigningRequestCondition{
+		Type:           certificatesv1beta1.CertificateApproved,
+		Reason:         "HiveManagedKubeconfig",
+		Message:        "Approved by Hive for the hive-managed kubeconfig",
+		LastUpdateTime: metav1.Now(),
+	})
+	if _, err := csrClient.UpdateApproval(csr); err != nil {
+		if err := csrClient.Delete(csr.Name, &metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			logger.WithError(err).Warn("could not delete certificate signing request")
+		}
+		return "", errors.Wrap(err, "could not approve certificate signing request")
+	}
+	logger.Debug("approved certificate signing request")
+	return csr.Name, nil
+}
+
+// fetchRemoteCertificate returns the certificate signed for the named CertificateSigningRequest on the remote cluster,
+// or nil if it has not been signed yet. Requests which are missing, denied, or not signed within
+// certificateIssueTimeout fail with errCertificateRequestFailed as the cause.
+func fetchRemoteCertificate(remoteClientBuilder remoteclient.Builder, name string, logger log.FieldLogger) ([]byte, error) {
+	kubeClient, err := remoteClientBuilder.BuildKubeClient()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not build remote cluster client")
+	}
+	csr, err := kubeClient.CertificatesV1beta1().CertificateSigningRequests().Get(name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		return nil, errors.Wrapf(errCertificateRequestFailed, "certificate signing request %s not found", name)
+	case err != nil:
+		return nil, errors.Wrap(err, "could not get certificate signing request")
+	}
+	for _, cond := range csr.Status.Conditions {
+		if cond.Type == certificatesv1beta1.CertificateDenied {
+			return nil, errors.Wrapf(errCertificateRequestFailed, "certificate signing request %s was denied: %s", name, cond.Message)
+		}
+	}
+	if len(csr.Status.Certificate) > 0 {
+		return csr.Status.Certificate, nil
+	}
+	if time.Since(csr.CreationTimestamp.Time) > certificateIssueTimeout {
+		return nil, errors.Wrapf(errCertificateRequestFailed, "certificate signing request %s was not signed within %v", name, certificateIssueTimeout)
+	}
+	logger.WithField("csr", name).Debug("certificate signing request is not signed yet")
+	return nil, nil
+}
+
+// deleteRemoteCertificateRequest deletes the named CertificateSigningRequest from the remote cluster.
+func deleteRemoteCertificateRequest(remoteClientBuilder remoteclient.Builder, name string) error {
+	kubeClient, err := remoteClientBuilder.BuildKubeClient()
+	if err != nil {
+		return errors.Wrap(err, "could not build remote cluster client")
+	}
+	err = kubeClient.CertificatesV1beta1().CertificateSigningRequests().Delete(name, &metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrap(err, "could not delete certificate signing request")
+	}
+	return nil
+}
+
+// ensureServiceAccount creates the hive service account on the remote cluster, bound to the cluster-admin role.
+// Existing objects are left as they are, so that the access of the hive-managed kubeconfig can be revoked by removing
+// the role binding.
+func ensureServiceAccount(kubeClient kubernetes.Interface, logger log.FieldLogger) error {
+	_, err := kubeClient.CoreV1().Namespaces().Create(&corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{Name: hiveManagedNamespace},
+	})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return errors.Wrap(err, "could not create hive-managed namespace")
+	}
+
+	_, err = kubeClient.CoreV1().ServiceAccounts(hiveManagedNamespace).Create(&corev1.ServiceAccount{
+		ObjectMeta: metav1.ObjectMeta{Name: hiveManagedServiceAccount},
+	})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return errors.Wrap(err, "could not create hive-managed service account")
+	}
+
+	_, err = kubeClient.RbacV1().ClusterRoleBindings().Create(&rbacv1.ClusterRoleBinding{
+		ObjectMeta: metav1.ObjectMeta{Name: hiveManagedClusterRoleBinding},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     "cluster-admin",
+		},
+		Subjects: []rbacv1.Subject{{
+			Kind:      rbacv1.ServiceAccountKind,
+			Name:      hiveManagedServiceAccount,
+			Namespace: hiveManagedNamespace,
+		}},
+	})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return errors.Wrap(err, "could not create hive-managed cluster role binding")
+	}
+	logger.Debug("hive-managed service account is in place")
+	return nil
+}
+
+// verifyRemoteConnection gets the hive service account from the remote cluster with the given config, which requires
+// the config to both authenticate and be authorized through the cluster role binding of the service account.
+func verifyRemoteConnection(cfg *rest.Config) error {
+	kubeClient, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return errors.Wrap(err, "could not build remote cluster client")
+	}
+	_, err = kubeClient.CoreV1().ServiceAccounts(hiveManagedNamespace).Get(hiveManagedServiceAccount, metav1.GetOptions{})
+	return err
+}
@@ -11,6 +11,7 @@ import (
 
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
 	"github.com/openshift/hive/pkg/constants"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
 
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
@@ -77,6 +78,22 @@ var (
 		Name: "hive_syncsets_unapplied_total",
 		Help: "Total number of SyncSetsInstances referencing non-selector SyncSets that have not successfully applied all resources/patches/secrets.",
 	})
+	metricSyncSetResourcesDriftedTotal = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "hive_syncset_resources_drifted_total",
+		Help: "Total number of SyncSet and SelectorSyncSet resources that have drifted on the target clusters.",
+	})
+	metricClusterPoolSize = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "hive_clusterpool_size",
+		Help: "Desired number of unclaimed clusters for each ClusterPool.",
+	}, []string{"clusterpool_namespace", "clusterpool_name"})
+	metricClusterPoolClustersReady = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "hive_clusterpool_clusterdeployments_ready",
+		Help: "Total number of unclaimed clusters in each ClusterPool that are installed and ready to be claimed.",
+	}, []string{"clusterpool_namespace", "clusterpool_name"})
+	metricClusterPoolClustersInstalling = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "hive_clusterpool_clusterdeployments_installing",
+		Help: "Total number of unclaimed clusters in each ClusterPool that are still installing.",
+	}, []string{"clusterpool_namespace", "clusterpool_name"})
 
 	// MetricClusterDeploymentProvisionUnderwaySeconds is a prometheus metric for the number of seconds
 	// between when a still provisioning cluster was created and now.
@@ -123,14 +140,23 @@ func init() {
 	metrics.Registry.MustRegister(metricSelectorSyncSetClustersUnappliedTotal)
 	metrics.Registry.MustRegister(metricSyncSetsTotal)
 	metrics.Registry.MustRegister(metricSyncSetsUnappliedTotal)
+	metrics.Registry.MustRegister(metricSyncSetResourcesDriftedTotal)
+	metrics.Registry.MustRegister(metricClusterPoolSize)
+	metrics.Registry.MustRegister(metricClusterPoolClustersReady)
+	metrics.Registry.MustRegister(metricClusterPoolClustersInstalling)
 	metrics.Registry.MustRegister(MetricControllerReconcileTime)
 
 	metrics.Registry.MustRegister(MetricClusterDeploymentProvisionUnderwaySeconds)
 	metrics.Registry.MustRegister(MetricClusterDeploymentDeprovisioningUnderwaySeconds)
 }
 
-// Add creates a new metrics Calculator and adds it to the Manager.
+// Add creates a new metrics Calculator and adds it to the Manager. When the hive controllers are sharded, metrics
+// are only calculated by the first shard, since they are calculated across all ClusterDeployments.
 func Add(mgr manager.Manager) error {
+	if !controllerutils.GetShard().Owns("") {
+		log.Info("metrics are calculated by the first controllers shard")
+		return nil
+	}
 	mc := &Calculator{
 		Client:   mgr.GetClient(),
 		Interval: 2 * time.Minute,
@@ -286,6 +312,7 @@ func (mc *Calculator) Start(stopCh <-chan struct{}) error {
 		}
 
 		mc.calculateSelectorSyncSetMetrics(mcLog)
+		mc.calculateClusterPoolMetrics(mcLog)
 
 		elapsed := time.Since(start)
 		mcLog.WithField("elapsed", elapsed).Info("metrics calculation complete")
@@ -294,6 +321,26 @@ func (mc *Calculator) Start(stopCh <-chan struct{}) error {
 	return nil
 }
 
+func (mc *Calculator) calculateClusterPoolMetrics(mcLog log.FieldLogger) {
+	mcLog.Debug("calculating metrics across all ClusterPools")
+	pools := &hivev1.ClusterPoolList{}
+	err := mc.Client.List(context.Background(), pools)
+	if err != nil {
+		mcLog.WithError(err).Error("error listing all ClusterPools")
+		return
+	}
+
+	// Reset so that deleted pools are no longer reported.
+	metricClusterPoolSize.Reset()
+	metricClusterPoolClustersReady.Reset()
+	metricClusterPoolClustersInstalling.Reset()
+	for _, pool := range pools.Items {
+		metricClusterPoolSize.WithLabelValues(pool.Namespace, pool.Name).Set(float64(pool.Spec.Size))
+		metricClusterPoolClustersReady.WithLabelValues(pool.Namespace, pool.Name).Set(float64(pool.Status.Ready))
+		metricClusterPoolClustersInstalling.WithLabelValues(pool.Namespace, pool.Name).Set(float64(pool.Status.Installing))
+	}
+}
+
 func (mc *Calculator) calculateSelectorSyncSetMetrics(mcLog log.FieldLogger) {
 	mcLog.Debug("calculating metrics across all SyncSetInstances")
 	ssis := &hivev1.SyncSetInstanceList{}
@@ -308,7 +355,13 @@ func (mc *Calculator) calculateSelectorSyncSetMetrics(mcLog log.FieldLogger) {
 
 	ssInstancesTotal := 0
 	ssInstancesUnappliedTotal := 0
+	resourcesDriftedTotal := 0
 	for _, ssi := range ssis.Items {
+		for _, resource := range ssi.Status.Resources {
+			if cond := controllerutils.FindSyncCondition(resource.Conditions, hivev1.DriftedSyncCondition); cond != nil && cond.Status == corev1.ConditionTrue {
+				resourcesDriftedTotal++
+			}
+		}
 		if sss := ssi.Spec.SelectorSyncSetRef; sss != nil {
 			// Process SyncSetInstances with a SelectorSyncSet reference:
 			sssInstancesTotal[sss.Name]++
@@ -331,6 +384,7 @@ func (mc *Calculator) calculateSelectorSyncSetMetrics(mcLog log.FieldLogger) {
 	}
 	metricSyncSetsTotal.Set(float64(ssInstancesTotal))
 	metricSyncSetsUnappliedTotal.Set(float64(ssInstancesUnappliedTotal))
+	metricSyncSetResourcesDriftedTotal.Set(float64(resourcesDriftedTotal))
 }
 
 func processJobs(jobs []batchv1.Job) (runningTotal, succeededTotal, failedTotal map[string]int) {
@@ -0,0 +1,76 @@
+package remotemachineset
+
+import (
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	machineapi "github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+)
+
+const (
+	openStackProviderSpecKind = "OpenstackProviderSpec"
+)
+
+// OpenStackActuator encapsulates the pieces necessary to be able to generate
+// a list of MachineSets to sync to the remote cluster.
+type OpenStackActuator struct {
+	logger log.FieldLogger
+	// providerSpec is the OpenstackProviderSpec of a pre-existing MachineSet in the remote cluster. It is
+	// used as the base for generated MachineSets as it contains the image, networks and security groups
+	// that the installer created for the cluster.
+	providerSpec map[string]interface{}
+}
+
+var _ Actuator = &OpenStackActuator{}
+
+// NewOpenStackActuator is the constructor for building an OpenStackActuator
+func NewOpenStackActuator(remoteMachineSets []machineapi.MachineSet, logger log.FieldLogger) (*OpenStackActuator, error) {
+	providerSpec, err := findProviderSpec(remoteMachineSets, openStackProviderSpecKind, logger)
+	if err != nil {
+		logger.WithError(err).Warn("failed to find a provider spec to use for new machinesets")
+		return nil, err
+	}
+	actuator := &OpenStackActuator{
+		logger:       logger,
+		providerSpec: providerSpec,
+	}
+	return actuator, nil
+}
+
+// GenerateMachineSets satisfies the Actuator interface and will take a clusterDeployment and return a list of MachineSets
+// to sync to the remote cluster.
+func (a *OpenStackActuator) GenerateMachineSets(cd *hivev1.ClusterDeployment, pool *hivev1.MachinePool, logger log.FieldLogger) ([]*machineapi.MachineSet, bool, error) {
+	if cd.Spec.ClusterMetadata == nil {
+		return nil, false, errors.New("ClusterDeployment does not have cluster metadata")
+	}
+	if cd.Spec.Platform.OpenStack == nil {
+		return nil, false, errors.New("ClusterDeployment is not for OpenStack")
+	}
+	if pool.Spec.Platform.OpenStack == nil {
+		return nil, false, errors.New("MachinePool is not for OpenStack")
+	}
+
+	providerSpec, err := copyProviderSpec(a.providerSpec)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to copy provider spec")
+	}
+	providerSpec["flavor"] = pool.Spec.Platform.OpenStack.Flavor
+	if rootVolume := pool.Spec.Platform.OpenStack.RootVolume; rootVolume != nil {
+		providerSpec["rootVolume"] = map[string]interface{}{
+			"diskSize":   rootVolume.Size,
+			"volumeType": rootVolume.Type,
+			"sourceType": "image",
+			"sourceUUID": providerSpec["image"],
+		}
+	} else {
+		delete(providerSpec, "rootVolume")
+	}
+
+	mset, err := generateMachineSet(cd, pool, providerSpec)
+	if err != nil {
+		return nil, false, err
+	}
+	return []*machineapi.MachineSet{mset}, true, nil
+}
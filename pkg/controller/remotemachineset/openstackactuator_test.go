@@ -0,0 +1,174 @@
+package remotemachineset
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	machineapi "github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	hivev1openstack "github.com/openshift/hive/pkg/apis/hive/v1/openstack"
+)
+
+const (
+	testOpenStackImage  = "test-rhcos-image"
+	testOpenStackFlavor = "m1.large"
+)
+
+func TestOpenStackActuator(t *testing.T) {
+	tests := []struct {
+		name                 string
+		pool                 *hivev1.MachinePool
+		remoteMachineSets    []machineapi.MachineSet
+		expectedProviderSpec map[string]interface{}
+		expectedErr          bool
+	}{
+		{
+			name:              "generate machineset from worker machineset",
+			pool:              testOpenStackPool(),
+			remoteMachineSets: []machineapi.MachineSet{testOpenStackMachineSet("worker", workerRole, "m1.xlarge")},
+			expectedProviderSpec: map[string]interface{}{
+				"kind":   openStackProviderSpecKind,
+				"image":  testOpenStackImage,
+				"flavor": testOpenStackFlavor,
+			},
+		},
+		{
+			name: "prefer worker machineset",
+			pool: testOpenStackPool(),
+			remoteMachineSets: []machineapi.MachineSet{
+				func() machineapi.MachineSet {
+					ms := testOpenStackMachineSet("master", "master", "m1.xlarge")
+					ms.Spec.Template.Spec.ProviderSpec.Value = encodeOpenStackProviderSpec(map[string]interface{}{
+						"kind":  openStackProviderSpecKind,
+						"image": "master-image",
+					})
+					return ms
+				}(),
+				testOpenStackMachineSet("worker", workerRole, "m1.xlarge"),
+			},
+			expectedProviderSpec: map[string]interface{}{
+				"kind":   openStackProviderSpecKind,
+				"image":  testOpenStackImage,
+				"flavor": testOpenStackFlavor,
+			},
+		},
+		{
+			name: "generate machineset with root volume",
+			pool: func() *hivev1.MachinePool {
+				pool := testOpenStackPool()
+				pool.Spec.Platform.OpenStack.RootVolume = &hivev1openstack.RootVolume{
+					Size: 100,
+					Type: "performance",
+				}
+				return pool
+			}(),
+			remoteMachineSets: []machineapi.MachineSet{testOpenStackMachineSet("worker", workerRole, "m1.xlarge")},
+			expectedProviderSpec: map[string]interface{}{
+				"kind":   openStackProviderSpecKind,
+				"image":  testOpenStackImage,
+				"flavor": testOpenStackFlavor,
+				"rootVolume": map[string]interface{}{
+					"diskSize":   float64(100),
+					"volumeType": "performance",
+					"sourceType": "image",
+					"sourceUUID": testOpenStackImage,
+				},
+			},
+		},
+		{
+			name:              "no openstack machinesets",
+			pool:              testOpenStackPool(),
+			remoteMachineSets: []machineapi.MachineSet{},
+			expectedErr:       true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger := log.WithField("actuator", "openstackactuator")
+			actuator, err := NewOpenStackActuator(test.remoteMachineSets, logger)
+			if test.expectedErr {
+				assert.Error(t, err, "expected error for test case")
+				return
+			}
+			require.NoError(t, err, "unexpected error creating actuator")
+
+			generatedMachineSets, proceed, err := actuator.GenerateMachineSets(testOpenStackClusterDeployment(), test.pool, logger)
+			require.NoError(t, err, "unexpected error generating machinesets")
+			assert.True(t, proceed, "expected to proceed")
+			require.Len(t, generatedMachineSets, 1, "unexpected number of machinesets")
+
+			ms := generatedMachineSets[0]
+			assert.Equal(t, fmt.Sprintf("%s-%s", testInfraID, testPoolName), ms.Name, "unexpected machineset name")
+			assert.Equal(t, int32(3), *ms.Spec.Replicas, "unexpected replicas")
+			providerSpec := map[string]interface{}{}
+			require.NoError(t, json.Unmarshal(ms.Spec.Template.Spec.ProviderSpec.Value.Raw, &providerSpec), "unexpected error decoding provider spec")
+			assert.Equal(t, test.expectedProviderSpec, providerSpec, "unexpected provider spec")
+		})
+	}
+}
+
+func testOpenStackPool() *hivev1.MachinePool {
+	p := testMachinePool()
+	p.Spec.Platform = hivev1.MachinePoolPlatform{
+		OpenStack: &hivev1openstack.MachinePool{
+			Flavor: testOpenStackFlavor,
+		},
+	}
+	return p
+}
+
+func testOpenStackClusterDeployment() *hivev1.ClusterDeployment {
+	cd := testClusterDeployment()
+	cd.Spec.Platform = hivev1.Platform{
+		OpenStack: &hivev1openstack.Platform{
+			CredentialsSecretRef: corev1.LocalObjectReference{
+				Name: "openstack-credentials",
+			},
+			Cloud: "openstack",
+		},
+	}
+	return cd
+}
+
+func testOpenStackMachineSet(name, role, flavor string) machineapi.MachineSet {
+	return machineapi.MachineSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%s", testInfraID, name),
+			Namespace: "openshift-machine-api",
+		},
+		Spec: machineapi.MachineSetSpec{
+			Template: machineapi.MachineTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						machineRoleLabel: role,
+					},
+				},
+				Spec: machineapi.MachineSpec{
+					ProviderSpec: machineapi.ProviderSpec{
+						Value: encodeOpenStackProviderSpec(map[string]interface{}{
+							"kind":   openStackProviderSpecKind,
+							"image":  testOpenStackImage,
+							"flavor": flavor,
+						}),
+					},
+				},
+			},
+		},
+	}
+}
+
+func encodeOpenStackProviderSpec(spec map[string]interface{}) *runtime.RawExtension {
+	raw, _ := json.Marshal(spec)
+	return &runtime.RawExtension{Raw: raw}
+}
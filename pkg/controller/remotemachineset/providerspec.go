@@ -0,0 +1,119 @@
+package remotemachineset
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	machineapi "github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+)
+
+const (
+	machineRoleLabel = "machine.openshift.io/cluster-api-machine-role"
+)
+
+// findProviderSpec scans the pre-existing machinesets to find a provider spec of the given kind that can be used as the
+// base for new machinesets. The provider spec is returned as an unstructured map so that platforms whose machine
+// provider types are not available to Hive can still be handled. MachineSets for workers are preferred.
+func findProviderSpec(remoteMachineSets []machineapi.MachineSet, kind string, logger log.FieldLogger) (map[string]interface{}, error) {
+	var found map[string]interface{}
+	for _, ms := range remoteMachineSets {
+		if ms.Spec.Template.Spec.ProviderSpec.Value == nil {
+			continue
+		}
+		spec := map[string]interface{}{}
+		if err := json.Unmarshal(ms.Spec.Template.Spec.ProviderSpec.Value.Raw, &spec); err != nil {
+			logger.WithError(err).WithField("machineSet", ms.Name).Warn("error decoding provider spec, skipping MachineSet")
+			continue
+		}
+		if spec["kind"] != kind {
+			continue
+		}
+		if ms.Spec.Template.Labels[machineRoleLabel] == workerRole {
+			logger.WithField("fromRemoteMachineSet", ms.Name).Debug("resolved provider spec to use for new machinesets")
+			return spec, nil
+		}
+		if found == nil {
+			found = spec
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("unable to locate %s to use from pre-existing machine set", kind)
+	}
+	return found, nil
+}
+
+// copyProviderSpec returns a deep copy of the given unstructured provider spec.
+func copyProviderSpec(spec map[string]interface{}) (map[string]interface{}, error) {
+	raw, err := json.Marshal(spec)
+	if err != nil {
+		return nil, err
+	}
+	specCopy := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &specCopy); err != nil {
+		return nil, err
+	}
+	return specCopy, nil
+}
+
+// generateMachineSet builds a single worker MachineSet for the machine pool using the given provider spec. This
+// matches the MachineSets the installer creates for platforms without availability zones.
+func generateMachineSet(cd *hivev1.ClusterDeployment, pool *hivev1.MachinePool, providerSpec map[string]interface{}) (*machineapi.MachineSet, error) {
+	raw, err := json.Marshal(providerSpec)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode provider spec")
+	}
+
+	clusterID := cd.Spec.ClusterMetadata.InfraID
+	name := fmt.Sprintf("%s-%s", clusterID, pool.Spec.Name)
+
+	replicas := int32(0)
+	if pool.Spec.Replicas != nil {
+		replicas = int32(*pool.Spec.Replicas)
+	}
+
+	return &machineapi.MachineSet{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "machine.openshift.io/v1beta1",
+			Kind:       "MachineSet",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "openshift-machine-api",
+			Name:      name,
+			Labels: map[string]string{
+				"machine.openshift.io/cluster-api-cluster": clusterID,
+			},
+		},
+		Spec: machineapi.MachineSetSpec{
+			Replicas: &replicas,
+			Selector: metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"machine.openshift.io/cluster-api-machineset": name,
+					"machine.openshift.io/cluster-api-cluster":    clusterID,
+				},
+			},
+			Template: machineapi.MachineTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"machine.openshift.io/cluster-api-machineset": name,
+						"machine.openshift.io/cluster-api-cluster":    clusterID,
+						machineRoleLabel: workerRole,
+						"machine.openshift.io/cluster-api-machine-type": workerRole,
+					},
+				},
+				Spec: machineapi.MachineSpec{
+					ProviderSpec: machineapi.ProviderSpec{
+						Value: &runtime.RawExtension{Raw: raw},
+					},
+				},
+			},
+		},
+	}, nil
+}
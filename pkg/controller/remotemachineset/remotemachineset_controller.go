@@ -63,11 +63,15 @@ func Add(mgr manager.Manager) error {
 	}
 
 	// Create a new controller
-	c, err := controller.New("remotemachineset-controller", mgr, controller.Options{Reconciler: r, MaxConcurrentReconciles: controllerutils.GetConcurrentReconciles()})
+	c, err := controller.New("remotemachineset-controller", mgr, controller.Options{Reconciler: controllerutils.NewShardedReconciler(r), MaxConcurrentReconciles: controllerutils.GetConcurrentReconciles(controllerName)})
 	if err != nil {
 		return err
 	}
 
+	if err := controllerutils.SetQueueRateLimiter(c, controllerName); err != nil {
+		return err
+	}
+
 	// Watch for changes to MachinePools
 	err = c.Watch(&source.Kind{Type: &hivev1.MachinePool{}}, &handler.EnqueueRequestForObject{})
 	if err != nil {
@@ -810,6 +814,10 @@ func (r *ReconcileRemoteMachineSet) createActuator(cd *hivev1.ClusterDeployment,
 			return nil, err
 		}
 		return NewAzureActuator(creds, logger)
+	case cd.Spec.Platform.OpenStack != nil:
+		return NewOpenStackActuator(remoteMachineSets, logger)
+	case cd.Spec.Platform.VSphere != nil:
+		return NewVSphereActuator(remoteMachineSets, logger)
 	default:
 		return nil, errors.New("unsupported platform")
 	}
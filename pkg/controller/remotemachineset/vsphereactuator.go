@@ -0,0 +1,70 @@
+package remotemachineset
+
+import (
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	machineapi "github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+)
+
+const (
+	vsphereProviderSpecKind = "VSphereMachineProviderSpec"
+)
+
+// VSphereActuator encapsulates the pieces necessary to be able to generate
+// a list of MachineSets to sync to the remote cluster.
+type VSphereActuator struct {
+	logger log.FieldLogger
+	// providerSpec is the VSphereMachineProviderSpec of a pre-existing MachineSet in the remote cluster. It is
+	// used as the base for generated MachineSets as it contains the VM template, workspace and network
+	// that the installer configured for the cluster.
+	providerSpec map[string]interface{}
+}
+
+var _ Actuator = &VSphereActuator{}
+
+// NewVSphereActuator is the constructor for building a VSphereActuator
+func NewVSphereActuator(remoteMachineSets []machineapi.MachineSet, logger log.FieldLogger) (*VSphereActuator, error) {
+	providerSpec, err := findProviderSpec(remoteMachineSets, vsphereProviderSpecKind, logger)
+	if err != nil {
+		logger.WithError(err).Warn("failed to find a provider spec to use for new machinesets")
+		return nil, err
+	}
+	actuator := &VSphereActuator{
+		logger:       logger,
+		providerSpec: providerSpec,
+	}
+	return actuator, nil
+}
+
+// GenerateMachineSets satisfies the Actuator interface and will take a clusterDeployment and return a list of MachineSets
+// to sync to the remote cluster.
+func (a *VSphereActuator) GenerateMachineSets(cd *hivev1.ClusterDeployment, pool *hivev1.MachinePool, logger log.FieldLogger) ([]*machineapi.MachineSet, bool, error) {
+	if cd.Spec.ClusterMetadata == nil {
+		return nil, false, errors.New("ClusterDeployment does not have cluster metadata")
+	}
+	if cd.Spec.Platform.VSphere == nil {
+		return nil, false, errors.New("ClusterDeployment is not for vSphere")
+	}
+	if pool.Spec.Platform.VSphere == nil {
+		return nil, false, errors.New("MachinePool is not for vSphere")
+	}
+
+	providerSpec, err := copyProviderSpec(a.providerSpec)
+	if err != nil {
+		return nil, false, errors.Wrap(err, "failed to copy provider spec")
+	}
+	poolPlatform := pool.Spec.Platform.VSphere
+	providerSpec["numCPUs"] = poolPlatform.NumCPUs
+	providerSpec["numCoresPerSocket"] = poolPlatform.NumCoresPerSocket
+	providerSpec["memoryMiB"] = poolPlatform.MemoryMiB
+	providerSpec["diskGiB"] = poolPlatform.OSDisk.DiskSizeGB
+
+	mset, err := generateMachineSet(cd, pool, providerSpec)
+	if err != nil {
+		return nil, false, err
+	}
+	return []*machineapi.MachineSet{mset}, true, nil
+}
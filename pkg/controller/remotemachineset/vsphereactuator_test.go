@@ -0,0 +1,140 @@
+package remotemachineset
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	machineapi "github.com/openshift/cluster-api/pkg/apis/machine/v1beta1"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	hivev1vsphere "github.com/openshift/hive/pkg/apis/hive/v1/vsphere"
+)
+
+const (
+	testVSphereTemplate = "test-rhcos-template"
+)
+
+func TestVSphereActuator(t *testing.T) {
+	tests := []struct {
+		name                 string
+		remoteMachineSets    []machineapi.MachineSet
+		expectedProviderSpec map[string]interface{}
+		expectedErr          bool
+	}{
+		{
+			name:              "generate machineset from worker machineset",
+			remoteMachineSets: []machineapi.MachineSet{testVSphereMachineSet()},
+			expectedProviderSpec: map[string]interface{}{
+				"kind":              vsphereProviderSpecKind,
+				"template":          testVSphereTemplate,
+				"numCPUs":           float64(8),
+				"numCoresPerSocket": float64(4),
+				"memoryMiB":         float64(32768),
+				"diskGiB":           float64(200),
+			},
+		},
+		{
+			name:              "no vsphere machinesets",
+			remoteMachineSets: []machineapi.MachineSet{},
+			expectedErr:       true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			logger := log.WithField("actuator", "vsphereactuator")
+			actuator, err := NewVSphereActuator(test.remoteMachineSets, logger)
+			if test.expectedErr {
+				assert.Error(t, err, "expected error for test case")
+				return
+			}
+			require.NoError(t, err, "unexpected error creating actuator")
+
+			generatedMachineSets, proceed, err := actuator.GenerateMachineSets(testVSphereClusterDeployment(), testVSpherePool(), logger)
+			require.NoError(t, err, "unexpected error generating machinesets")
+			assert.True(t, proceed, "expected to proceed")
+			require.Len(t, generatedMachineSets, 1, "unexpected number of machinesets")
+
+			ms := generatedMachineSets[0]
+			assert.Equal(t, fmt.Sprintf("%s-%s", testInfraID, testPoolName), ms.Name, "unexpected machineset name")
+			assert.Equal(t, int32(3), *ms.Spec.Replicas, "unexpected replicas")
+			providerSpec := map[string]interface{}{}
+			require.NoError(t, json.Unmarshal(ms.Spec.Template.Spec.ProviderSpec.Value.Raw, &providerSpec), "unexpected error decoding provider spec")
+			assert.Equal(t, test.expectedProviderSpec, providerSpec, "unexpected provider spec")
+		})
+	}
+}
+
+func testVSpherePool() *hivev1.MachinePool {
+	p := testMachinePool()
+	p.Spec.Platform = hivev1.MachinePoolPlatform{
+		VSphere: &hivev1vsphere.MachinePool{
+			NumCPUs:           8,
+			NumCoresPerSocket: 4,
+			MemoryMiB:         32768,
+			OSDisk: hivev1vsphere.OSDisk{
+				DiskSizeGB: 200,
+			},
+		},
+	}
+	return p
+}
+
+func testVSphereClusterDeployment() *hivev1.ClusterDeployment {
+	cd := testClusterDeployment()
+	cd.Spec.Platform = hivev1.Platform{
+		VSphere: &hivev1vsphere.Platform{
+			VCenter: "test-vcenter",
+			CredentialsSecretRef: corev1.LocalObjectReference{
+				Name: "vsphere-credentials",
+			},
+			CertificatesSecretRef: corev1.LocalObjectReference{
+				Name: "vsphere-certificates",
+			},
+			Datacenter:       "test-datacenter",
+			DefaultDatastore: "test-datastore",
+			Network:          "test-network",
+		},
+	}
+	return cd
+}
+
+func testVSphereMachineSet() machineapi.MachineSet {
+	raw, _ := json.Marshal(map[string]interface{}{
+		"kind":              vsphereProviderSpecKind,
+		"template":          testVSphereTemplate,
+		"numCPUs":           4,
+		"numCoresPerSocket": 1,
+		"memoryMiB":         16384,
+		"diskGiB":           120,
+	})
+	return machineapi.MachineSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-worker", testInfraID),
+			Namespace: "openshift-machine-api",
+		},
+		Spec: machineapi.MachineSetSpec{
+			Template: machineapi.MachineTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						machineRoleLabel: workerRole,
+					},
+				},
+				Spec: machineapi.MachineSpec{
+					ProviderSpec: machineapi.ProviderSpec{
+						Value: &runtime.RawExtension{Raw: raw},
+					},
+				},
+			},
+		},
+	}
+}
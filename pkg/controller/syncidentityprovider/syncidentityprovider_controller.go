@@ -57,11 +57,15 @@ func NewReconciler(mgr manager.Manager) reconcile.Reconciler {
 // AddToManager adds a new Controller to mgr with r as the reconcile.Reconciler
 func AddToManager(mgr manager.Manager, r reconcile.Reconciler) error {
 	// Create a new controller
-	c, err := controller.New(controllerName+"-controller", mgr, controller.Options{Reconciler: r, MaxConcurrentReconciles: controllerutils.GetConcurrentReconciles()})
+	c, err := controller.New(controllerName+"-controller", mgr, controller.Options{Reconciler: controllerutils.NewShardedReconciler(r), MaxConcurrentReconciles: controllerutils.GetConcurrentReconciles(controllerName)})
 	if err != nil {
 		return err
 	}
 
+	if err := controllerutils.SetQueueRateLimiter(c, controllerName); err != nil {
+		return err
+	}
+
 	reconciler := r.(*ReconcileSyncIdentityProviders)
 
 	// Watch for changes to SyncIdentityProvider
@@ -233,6 +237,53 @@ func GenerateIdentityProviderSyncSetName(clusterDeploymentName string) string {
 	return apihelpers.GetResourceName(clusterDeploymentName, constants.IdentityProviderSuffix)
 }
 
+// IdentityProvidersApplied returns whether identity providers are synced to the cluster of a ClusterDeployment, and the
+// current list of identity providers has been applied to the cluster successfully.
+func IdentityProvidersApplied(c client.Client, cd *hivev1.ClusterDeployment) (bool, error) {
+	ss := &hivev1.SyncSet{}
+	err := c.Get(context.TODO(), types.NamespacedName{Name: GenerateIdentityProviderSyncSetName(cd.Name), Namespace: cd.Namespace}, ss)
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	if len(ss.Spec.Patches) != 1 {
+		return false, fmt.Errorf("identity provider syncset %s has %d patches", ss.Name, len(ss.Spec.Patches))
+	}
+	patch := ss.Spec.Patches[0]
+	idpPatch := identityProviderPatch{}
+	if err := json.Unmarshal([]byte(patch.Patch), &idpPatch); err != nil {
+		return false, fmt.Errorf("Failed unmarshaling identity provider list: %v", err)
+	}
+	if len(idpPatch.Spec.IdentityProviders) == 0 {
+		return false, nil
+	}
+	// The syncsetinstance controller records the hash of each patch it applies.
+	patchHash, err := controllerutils.GetChecksumOfObject(patch)
+	if err != nil {
+		return false, err
+	}
+
+	ssiList := &hivev1.SyncSetInstanceList{}
+	if err := c.List(context.TODO(), ssiList, client.InNamespace(cd.Namespace)); err != nil {
+		return false, err
+	}
+	for _, ssi := range ssiList.Items {
+		if ssi.Spec.ClusterDeploymentRef.Name != cd.Name || ssi.Spec.SyncSetRef == nil || ssi.Spec.SyncSetRef.Name != ss.Name {
+			continue
+		}
+		for _, patchStatus := range ssi.Status.Patches {
+			if patchStatus.Kind != patch.Kind || patchStatus.Name != patch.Name || patchStatus.Hash != patchHash {
+				continue
+			}
+			cond := controllerutils.FindSyncCondition(patchStatus.Conditions, hivev1.ApplySuccessSyncCondition)
+			return cond != nil && cond.Status == corev1.ConditionTrue, nil
+		}
+	}
+	return false, nil
+}
+
 func (r *ReconcileSyncIdentityProviders) syncIdentityProviders(cd *hivev1.ClusterDeployment) error {
 	contextLogger := addClusterDeploymentLoggerFields(r.logger, cd)
 
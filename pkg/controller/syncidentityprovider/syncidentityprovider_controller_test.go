@@ -10,6 +10,7 @@ import (
 	"github.com/openshift/hive/pkg/apis"
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
 	"github.com/openshift/hive/pkg/constants"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
 	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
@@ -482,6 +483,89 @@ func TestReconcile(t *testing.T) {
 	}
 }
 
+func TestIdentityProvidersApplied(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	idpSyncSet := syncSetWithIdentityProviders(githubIdentityProvider(sidpName))
+	patchHash, err := controllerutils.GetChecksumOfObject(idpSyncSet.Spec.Patches[0])
+	if err != nil {
+		t.Fatalf("unexpected error computing patch hash: %v", err)
+	}
+	syncSetInstance := func(hash string, applied corev1.ConditionStatus) *hivev1.SyncSetInstance {
+		return &hivev1.SyncSetInstance{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "someclusterdeployment-someclusterdeployment-idp-syncset",
+				Namespace: "default",
+			},
+			Spec: hivev1.SyncSetInstanceSpec{
+				ClusterDeploymentRef: corev1.LocalObjectReference{Name: "someclusterdeployment"},
+				SyncSetRef:           &corev1.LocalObjectReference{Name: idpSyncSet.Name},
+			},
+			Status: hivev1.SyncSetInstanceStatus{
+				Patches: []hivev1.SyncStatus{{
+					APIVersion: "config.openshift.io/v1",
+					Kind:       "OAuth",
+					Name:       "cluster",
+					Hash:       hash,
+					Conditions: []hivev1.SyncCondition{{
+						Type:   hivev1.ApplySuccessSyncCondition,
+						Status: applied,
+					}},
+				}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		existing []runtime.Object
+		expected bool
+	}{
+		{
+			name:     "no identity provider syncset",
+			existing: []runtime.Object{},
+		},
+		{
+			name:     "no identity providers",
+			existing: []runtime.Object{syncSetAsPointer(emptySyncSet())},
+		},
+		{
+			name:     "no syncset instance",
+			existing: []runtime.Object{syncSetAsPointer(idpSyncSet)},
+		},
+		{
+			name: "identity providers applied",
+			existing: []runtime.Object{
+				syncSetAsPointer(idpSyncSet),
+				syncSetInstance(patchHash, corev1.ConditionTrue),
+			},
+			expected: true,
+		},
+		{
+			name: "previous identity providers applied",
+			existing: []runtime.Object{
+				syncSetAsPointer(idpSyncSet),
+				syncSetInstance("previous-hash", corev1.ConditionTrue),
+			},
+		},
+		{
+			name: "identity providers failed to apply",
+			existing: []runtime.Object{
+				syncSetAsPointer(idpSyncSet),
+				syncSetInstance(patchHash, corev1.ConditionFalse),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			applied, err := IdentityProvidersApplied(fake.NewFakeClient(test.existing...), emptyClusterDeployment())
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, applied)
+		})
+	}
+}
+
 func assertSyncSetLabelsCorrect(t *testing.T, actual *hivev1.SyncSetList) {
 	for ix := range actual.Items {
 		labels := actual.Items[ix].Labels
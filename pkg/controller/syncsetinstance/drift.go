@@ -0,0 +1,306 @@
+package syncsetinstance
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+	hiveresource "github.com/openshift/hive/pkg/resource"
+)
+
+const (
+	driftDetectedReason = "DriftDetected"
+	noDriftReason       = "NoDrift"
+	driftRevertedReason = "DriftReverted"
+
+	// maxReportedDriftedFields is the maximum number of drifted fields listed in the message of the
+	// Drifted condition.
+	maxReportedDriftedFields = 10
+)
+
+// driftCheckEnabled returns whether resources are checked for drift with the given drift check mode.
+func driftCheckEnabled(mode hivev1.SyncSetDriftCheckMode) bool {
+	return mode == hivev1.ReportDriftCheckMode || mode == hivev1.AuditDriftCheckMode
+}
+
+// resourceDrift checks the live resource on the target cluster for changes to the fields set in the resource of
+// the syncset. It returns a description of the drift, or an empty string when the resource has not drifted.
+func (r *ReconcileSyncSetInstance) resourceDrift(raw []byte, status hivev1.SyncStatus, dynamicClient dynamic.Interface) (string, error) {
+	desired := &unstructured.Unstructured{}
+	if err := runtime.DecodeInto(unstructured.UnstructuredJSONScheme, raw, desired); err != nil {
+		return "", fmt.Errorf("cannot decode resource: %v", err)
+	}
+	gv, err := schema.ParseGroupVersion(status.APIVersion)
+	if err != nil {
+		return "", fmt.Errorf("cannot parse resource apiVersion: %v", err)
+	}
+	live, err := dynamicClient.Resource(gv.WithResource(status.Resource)).Namespace(status.Namespace).Get(status.Name, metav1.GetOptions{})
+	switch {
+	case errors.IsNotFound(err):
+		return "resource not found on target cluster", nil
+	case err != nil:
+		return "", fmt.Errorf("cannot get resource from target cluster: %v", err)
+	}
+	managedFields := live.GetManagedFields()
+	if len(managedFields) == 0 {
+		return "", fmt.Errorf("resource on target cluster has no managed fields")
+	}
+
+	fields := driftedFields(desired.Object, live.Object, newFieldOwners(managedFields))
+	if len(fields) == 0 {
+		return "", nil
+	}
+	if len(fields) > maxReportedDriftedFields {
+		fields = append(fields[:maxReportedDriftedFields], fmt.Sprintf("and %d more", len(fields)-maxReportedDriftedFields))
+	}
+	return fmt.Sprintf("fields changed on target cluster: %s", strings.Join(fields, ", ")), nil
+}
+
+// hiveFieldManagers are the field managers recorded in the managed fields of the objects applied by Hive. Client-side
+// apply does not set a field manager, so the API server records the name of the program in the user agent instead.
+var hiveFieldManagers = sets.NewString(
+	hiveresource.FieldManager,
+	strings.Split(rest.DefaultKubernetesUserAgent(), "/")[0],
+)
+
+// fieldOwners holds the sets of fields owned by Hive and by other field managers at one path of an object, as
+// recorded in the managed fields of the object.
+type fieldOwners struct {
+	hive   []metav1.Fields
+	others []metav1.Fields
+}
+
+func newFieldOwners(managedFields []metav1.ManagedFieldsEntry) fieldOwners {
+	owners := fieldOwners{}
+	for _, entry := range managedFields {
+		if entry.Fields == nil {
+			continue
+		}
+		if hiveFieldManagers.Has(entry.Manager) {
+			owners.hive = append(owners.hive, *entry.Fields)
+		} else {
+			owners.others = append(owners.others, *entry.Fields)
+		}
+	}
+	return owners
+}
+
+// child returns the owners of the field with the given name.
+func (o fieldOwners) child(name string) fieldOwners {
+	return fieldOwners{
+		hive:   childFields(o.hive, "f:"+name),
+		others: childFields(o.others, "f:"+name),
+	}
+}
+
+// ownedByHive returns whether Hive owns the field, and no other field manager owns any part of it that Hive does not.
+// Changing a field with an update transfers its ownership to the updater, so a field owned by Hive has not been
+// changed since Hive applied it.
+func (o fieldOwners) ownedByHive() bool {
+	if len(o.hive) == 0 {
+		return false
+	}
+	for _, fields := range o.others {
+		if !fieldsContained(fields, o.hive) {
+			return false
+		}
+	}
+	return true
+}
+
+func childFields(fieldsList []metav1.Fields, key string) []metav1.Fields {
+	var children []metav1.Fields
+	for _, fields := range fieldsList {
+		if child, ok := fields.Map[key]; ok {
+			children = append(children, child)
+		}
+	}
+	return children
+}
+
+// fieldsContained returns whether every field in the set is also in one of the sets of the list.
+func fieldsContained(fields metav1.Fields, fieldsList []metav1.Fields) bool {
+	for key, child := range fields.Map {
+		children := childFields(fieldsList, key)
+		if len(children) == 0 || !fieldsContained(child, children) {
+			return false
+		}
+	}
+	return true
+}
+
+// driftedFields returns the paths of the fields set in the desired object which Hive no longer owns in the live
+// object and which have a different value there. Only the labels and annotations of the object metadata are
+// checked, and the status is ignored.
+func driftedFields(desired, live map[string]interface{}, owners fieldOwners) []string {
+	drifted := []string{}
+	for _, key := range sortedKeys(desired) {
+		switch key {
+		case "apiVersion", "kind", "status":
+			continue
+		case "metadata":
+			desiredMetadata, ok := desired[key].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			liveMetadata, _ := live[key].(map[string]interface{})
+			metadataOwners := owners.child(key)
+			for _, metadataKey := range []string{"labels", "annotations"} {
+				if value, ok := desiredMetadata[metadataKey]; ok {
+					drifted = appendDriftedFields(drifted, ".metadata."+metadataKey, value, liveMetadata[metadataKey], metadataOwners.child(metadataKey))
+				}
+			}
+		case "stringData":
+			// stringData is write-only, it is merged into the data of secrets by the API server.
+			if desired["kind"] == "Secret" {
+				continue
+			}
+			drifted = appendDriftedFields(drifted, "."+key, desired[key], live[key], owners.child(key))
+		default:
+			drifted = appendDriftedFields(drifted, "."+key, desired[key], live[key], owners.child(key))
+		}
+	}
+	return drifted
+}
+
+// appendDriftedFields appends the paths of the fields of the desired value that Hive no longer owns and that differ
+// in the live value. Fields still owned by Hive are not compared, as the API server may normalise their values.
+func appendDriftedFields(drifted []string, path string, desired, live interface{}, owners fieldOwners) []string {
+	switch desiredValue := desired.(type) {
+	case map[string]interface{}:
+		liveValue, ok := live.(map[string]interface{})
+		if !ok {
+			return append(drifted, path)
+		}
+		for _, key := range sortedKeys(desiredValue) {
+			drifted = appendDriftedFields(drifted, path+"."+key, desiredValue[key], liveValue[key], owners.child(key))
+		}
+	case []interface{}:
+		liveValue, ok := live.([]interface{})
+		if !ok || len(liveValue) != len(desiredValue) {
+			// Removed items are not recorded in the managed fields, so lists are resized even when owned by Hive.
+			return append(drifted, path)
+		}
+		if owners.ownedByHive() {
+			return drifted
+		}
+		// The fields of list items are identified by keys that depend on the schema of the list, so the items of
+		// lists not owned by Hive are compared by value.
+		for i := range desiredValue {
+			drifted = appendDriftedFields(drifted, fmt.Sprintf("%s[%d]", path, i), desiredValue[i], liveValue[i], fieldOwners{})
+		}
+	case nil:
+		// A null field in the desired object is cleared on the target cluster.
+		if live != nil {
+			return append(drifted, path)
+		}
+	default:
+		if !owners.ownedByHive() && !scalarsEqual(desired, live) {
+			return append(drifted, path)
+		}
+	}
+	return drifted
+}
+
+// scalarsEqual compares scalar values, treating integer and floating point numbers with the same value as equal.
+// Values which differ but are both quantities, such as "1Gi" and "1073741824" or "500m" and 0.5, are equal when
+// their quantities are equal, as the API server normalises the quantities of resources such as container limits.
+func scalarsEqual(a, b interface{}) bool {
+	if af, ok := toFloat(a); ok {
+		if bf, ok := toFloat(b); ok {
+			return af == bf
+		}
+	}
+	if reflect.DeepEqual(a, b) {
+		return true
+	}
+	aq, ok := toQuantity(a)
+	if !ok {
+		return false
+	}
+	bq, ok := toQuantity(b)
+	return ok && aq.Cmp(bq) == 0
+}
+
+func toFloat(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+func toQuantity(value interface{}) (resource.Quantity, bool) {
+	var s string
+	switch v := value.(type) {
+	case string:
+		s = v
+	case int64:
+		s = strconv.FormatInt(v, 10)
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return resource.Quantity{}, false
+	}
+	q, err := resource.ParseQuantity(s)
+	return q, err == nil
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// setDriftedSyncCondition sets the Drifted condition from the result of a drift check. The condition is only
+// added once drift has been detected.
+func (r *ReconcileSyncSetInstance) setDriftedSyncCondition(resourceSyncConditions []hivev1.SyncCondition, drift string) []hivev1.SyncCondition {
+	if drift == "" {
+		return controllerutils.SetSyncCondition(
+			resourceSyncConditions,
+			hivev1.DriftedSyncCondition,
+			corev1.ConditionFalse,
+			noDriftReason,
+			"Resource matches the syncset",
+			// Keep the reason of a previous transition to false, e.g. reverted drift
+			controllerutils.UpdateConditionNever)
+	}
+	return controllerutils.SetSyncCondition(
+		resourceSyncConditions,
+		hivev1.DriftedSyncCondition,
+		corev1.ConditionTrue,
+		driftDetectedReason,
+		drift,
+		controllerutils.UpdateConditionIfReasonOrMessageChange)
+}
+
+// setDriftRevertedSyncCondition clears the Drifted condition after a drifted resource has been re-applied.
+func (r *ReconcileSyncSetInstance) setDriftRevertedSyncCondition(resourceSyncConditions []hivev1.SyncCondition) []hivev1.SyncCondition {
+	return controllerutils.SetSyncCondition(
+		resourceSyncConditions,
+		hivev1.DriftedSyncCondition,
+		corev1.ConditionFalse,
+		driftRevertedReason,
+		"Drifted fields were reverted by re-applying the resource",
+		controllerutils.UpdateConditionIfReasonOrMessageChange)
+}
@@ -0,0 +1,292 @@
+package syncsetinstance
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	hiveresource "github.com/openshift/hive/pkg/resource"
+)
+
+func TestDriftedFields(t *testing.T) {
+	tests := []struct {
+		name          string
+		desired       map[string]interface{}
+		live          map[string]interface{}
+		managedFields []metav1.ManagedFieldsEntry
+		expected      []string
+	}{
+		{
+			name: "no drift",
+			desired: map[string]interface{}{
+				"data": map[string]interface{}{"key": "value"},
+			},
+			live: map[string]interface{}{
+				"data": map[string]interface{}{"key": "value", "other": "value"},
+			},
+			expected: []string{},
+		},
+		{
+			name: "changed field",
+			desired: map[string]interface{}{
+				"data": map[string]interface{}{"key": "value"},
+			},
+			live: map[string]interface{}{
+				"data": map[string]interface{}{"key": "changed"},
+			},
+			expected: []string{".data.key"},
+		},
+		{
+			name: "removed field",
+			desired: map[string]interface{}{
+				"data": map[string]interface{}{"key1": "value", "key2": "value"},
+			},
+			live: map[string]interface{}{
+				"data": map[string]interface{}{"key1": "value"},
+			},
+			expected: []string{".data.key2"},
+		},
+		{
+			name: "changed list",
+			desired: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"ports": []interface{}{
+						map[string]interface{}{"port": int64(80)},
+						map[string]interface{}{"port": int64(443)},
+					},
+				},
+			},
+			live: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"ports": []interface{}{
+						map[string]interface{}{"port": int64(80), "protocol": "TCP"},
+						map[string]interface{}{"port": int64(8443), "protocol": "TCP"},
+					},
+				},
+			},
+			expected: []string{".spec.ports[1].port"},
+		},
+		{
+			name: "resized list",
+			desired: map[string]interface{}{
+				"spec": map[string]interface{}{"items": []interface{}{"a", "b"}},
+			},
+			live: map[string]interface{}{
+				"spec": map[string]interface{}{"items": []interface{}{"a"}},
+			},
+			expected: []string{".spec.items"},
+		},
+		{
+			name: "numbers of different types",
+			desired: map[string]interface{}{
+				"spec": map[string]interface{}{"replicas": float64(3)},
+			},
+			live: map[string]interface{}{
+				"spec": map[string]interface{}{"replicas": int64(3)},
+			},
+			expected: []string{},
+		},
+		{
+			name: "equal quantities",
+			desired: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"limits": map[string]interface{}{"cpu": "500m", "memory": "1073741824", "pods": int64(10)},
+				},
+			},
+			live: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"limits": map[string]interface{}{"cpu": float64(0.5), "memory": "1Gi", "pods": "10"},
+				},
+			},
+			expected: []string{},
+		},
+		{
+			name: "changed quantity",
+			desired: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"limits": map[string]interface{}{"memory": "1Gi"},
+				},
+			},
+			live: map[string]interface{}{
+				"spec": map[string]interface{}{
+					"limits": map[string]interface{}{"memory": "1G"},
+				},
+			},
+			expected: []string{".spec.limits.memory"},
+		},
+		{
+			name: "metadata other than labels and annotations ignored",
+			desired: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"name":        "foo",
+					"labels":      map[string]interface{}{"label": "value"},
+					"annotations": map[string]interface{}{"annotation": "value"},
+				},
+			},
+			live: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"name":            "foo",
+					"resourceVersion": "12345",
+					"labels":          map[string]interface{}{"label": "changed"},
+					"annotations":     map[string]interface{}{"annotation": "value", "other": "value"},
+				},
+			},
+			expected: []string{".metadata.labels.label"},
+		},
+		{
+			name: "status ignored",
+			desired: map[string]interface{}{
+				"status": map[string]interface{}{"phase": "Active"},
+			},
+			live: map[string]interface{}{
+				"status": map[string]interface{}{"phase": "Terminating"},
+			},
+			expected: []string{},
+		},
+		{
+			name: "secret string data ignored",
+			desired: map[string]interface{}{
+				"kind":       "Secret",
+				"stringData": map[string]interface{}{"key": "value"},
+			},
+			live: map[string]interface{}{
+				"kind": "Secret",
+				"data": map[string]interface{}{"key": "dmFsdWU="},
+			},
+			expected: []string{},
+		},
+		{
+			name: "changed field owned by hive",
+			desired: map[string]interface{}{
+				"spec": map[string]interface{}{"size": "1Gi"},
+			},
+			live: map[string]interface{}{
+				"spec": map[string]interface{}{"size": "1073741824"},
+			},
+			managedFields: []metav1.ManagedFieldsEntry{
+				testManagedFields(hiveresource.FieldManager, `{"f:spec":{"f:size":{}}}`),
+			},
+			expected: []string{},
+		},
+		{
+			name: "field changed by other manager",
+			desired: map[string]interface{}{
+				"data": map[string]interface{}{"key1": "value", "key2": "value"},
+			},
+			live: map[string]interface{}{
+				"data": map[string]interface{}{"key1": "value", "key2": "changed"},
+			},
+			managedFields: []metav1.ManagedFieldsEntry{
+				testManagedFields(hiveresource.FieldManager, `{"f:data":{"f:key1":{}}}`),
+				testManagedFields("kubectl", `{"f:data":{"f:key2":{}}}`),
+			},
+			expected: []string{".data.key2"},
+		},
+		{
+			name: "field owned by other manager with same value",
+			desired: map[string]interface{}{
+				"data": map[string]interface{}{"key": "value"},
+			},
+			live: map[string]interface{}{
+				"data": map[string]interface{}{"key": "value"},
+			},
+			managedFields: []metav1.ManagedFieldsEntry{
+				testManagedFields("kubectl", `{"f:data":{"f:key":{}}}`),
+			},
+			expected: []string{},
+		},
+		{
+			name: "field shared with other manager",
+			desired: map[string]interface{}{
+				"data": map[string]interface{}{"key": "value"},
+			},
+			live: map[string]interface{}{
+				"data": map[string]interface{}{"key": "normalized"},
+			},
+			managedFields: []metav1.ManagedFieldsEntry{
+				testManagedFields(hiveresource.FieldManager, `{"f:data":{"f:key":{}}}`),
+				testManagedFields("kubectl", `{"f:data":{"f:key":{}}}`),
+			},
+			expected: []string{},
+		},
+		{
+			name: "list owned by hive",
+			desired: map[string]interface{}{
+				"spec": map[string]interface{}{"items": []interface{}{"a", "b"}},
+			},
+			live: map[string]interface{}{
+				"spec": map[string]interface{}{"items": []interface{}{"a", "B"}},
+			},
+			managedFields: []metav1.ManagedFieldsEntry{
+				testManagedFields(hiveresource.FieldManager, `{"f:spec":{"f:items":{"v:\"a\"":{},"v:\"B\"":{}}}}`),
+			},
+			expected: []string{},
+		},
+		{
+			name: "list item changed by other manager",
+			desired: map[string]interface{}{
+				"spec": map[string]interface{}{"items": []interface{}{"a", "b"}},
+			},
+			live: map[string]interface{}{
+				"spec": map[string]interface{}{"items": []interface{}{"a", "c"}},
+			},
+			managedFields: []metav1.ManagedFieldsEntry{
+				testManagedFields(hiveresource.FieldManager, `{"f:spec":{"f:items":{"v:\"a\"":{}}}}`),
+				testManagedFields("kubectl", `{"f:spec":{"f:items":{"v:\"c\"":{}}}}`),
+			},
+			expected: []string{".spec.items[1]"},
+		},
+		{
+			name: "resized list owned by hive",
+			desired: map[string]interface{}{
+				"spec": map[string]interface{}{"items": []interface{}{"a", "b"}},
+			},
+			live: map[string]interface{}{
+				"spec": map[string]interface{}{"items": []interface{}{"a"}},
+			},
+			managedFields: []metav1.ManagedFieldsEntry{
+				testManagedFields(hiveresource.FieldManager, `{"f:spec":{"f:items":{"v:\"a\"":{}}}}`),
+			},
+			expected: []string{".spec.items"},
+		},
+		{
+			name: "label changed by other manager",
+			desired: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels": map[string]interface{}{"label1": "value", "label2": "value"},
+				},
+			},
+			live: map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels": map[string]interface{}{"label1": "normalized", "label2": "changed"},
+				},
+			},
+			managedFields: []metav1.ManagedFieldsEntry{
+				testManagedFields(hiveresource.FieldManager, `{"f:metadata":{"f:labels":{"f:label1":{}}}}`),
+				testManagedFields("kubectl", `{"f:metadata":{"f:labels":{"f:label2":{}}}}`),
+			},
+			expected: []string{".metadata.labels.label2"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, driftedFields(test.desired, test.live, newFieldOwners(test.managedFields)))
+		})
+	}
+}
+
+func testManagedFields(manager, fields string) metav1.ManagedFieldsEntry {
+	entry := metav1.ManagedFieldsEntry{
+		Manager:    manager,
+		Operation:  metav1.ManagedFieldsOperationUpdate,
+		APIVersion: "v1",
+		Fields:     &metav1.Fields{},
+	}
+	if err := json.Unmarshal([]byte(fields), entry.Fields); err != nil {
+		panic(err)
+	}
+	return entry
+}
@@ -51,19 +51,26 @@ import (
 	controllerutils "github.com/openshift/hive/pkg/controller/utils"
 	"github.com/openshift/hive/pkg/remoteclient"
 	hiveresource "github.com/openshift/hive/pkg/resource"
+	"github.com/openshift/hive/pkg/syncsettemplate"
 )
 
 const (
-	controllerName           = "syncsetinstance"
-	unknownObjectFoundReason = "UnknownObjectFound"
-	applySucceededReason     = "ApplySucceeded"
-	applyFailedReason        = "ApplyFailed"
-	deletionFailedReason     = "DeletionFailed"
-	defaultReapplyInterval   = 2 * time.Hour
-	reapplyIntervalEnvKey    = "SYNCSET_REAPPLY_INTERVAL"
-	secretsResource          = "secrets"
-	secretKind               = "Secret"
-	secretAPIVersion         = "v1"
+	controllerName                = "syncsetinstance"
+	unknownObjectFoundReason      = "UnknownObjectFound"
+	templateRenderFailedReason    = "TemplateRenderFailed"
+	templateRenderSucceededReason = "TemplateRenderSucceeded"
+	applySucceededReason          = "ApplySucceeded"
+	applyFailedReason             = "ApplyFailed"
+	applyConflictReason           = "ApplyConflict"
+	noApplyConflictReason         = "NoApplyConflict"
+	deletionFailedReason          = "DeletionFailed"
+	defaultReapplyInterval        = 2 * time.Hour
+	reapplyIntervalEnvKey         = "SYNCSET_REAPPLY_INTERVAL"
+	defaultDriftCheckInterval     = 10 * time.Minute
+	driftCheckIntervalEnvKey      = "SYNCSET_DRIFT_CHECK_INTERVAL"
+	secretsResource               = "secrets"
+	secretKind                    = "Secret"
+	secretAPIVersion              = "v1"
 )
 
 var (
@@ -92,17 +99,30 @@ func Add(mgr manager.Manager) error {
 		}
 	}
 	log.WithField("reapplyInterval", reapplyInterval).Info("Reapply interval set")
-	return AddToManager(mgr, NewReconciler(mgr, logger, reapplyInterval))
+	driftCheckInterval := defaultDriftCheckInterval
+	if envDriftCheckInterval := os.Getenv(driftCheckIntervalEnvKey); len(envDriftCheckInterval) > 0 {
+		var err error
+		driftCheckInterval, err = time.ParseDuration(envDriftCheckInterval)
+		if err != nil {
+			log.WithError(err).WithField("driftCheckInterval", envDriftCheckInterval).Errorf("unable to parse %s", driftCheckIntervalEnvKey)
+			return err
+		}
+	}
+	log.WithField("driftCheckInterval", driftCheckInterval).Info("Drift check interval set")
+	return AddToManager(mgr, NewReconciler(mgr, logger, reapplyInterval, driftCheckInterval))
 }
 
 // NewReconciler returns a new reconcile.Reconciler
-func NewReconciler(mgr manager.Manager, logger log.FieldLogger, reapplyInterval time.Duration) reconcile.Reconciler {
+func NewReconciler(mgr manager.Manager, logger log.FieldLogger, reapplyInterval, driftCheckInterval time.Duration) reconcile.Reconciler {
 	r := &ReconcileSyncSetInstance{
-		Client:          controllerutils.NewClientWithMetricsOrDie(mgr, controllerName),
-		scheme:          mgr.GetScheme(),
-		logger:          logger,
-		applierBuilder:  applierBuilderFunc,
-		reapplyInterval: reapplyInterval,
+		Client:             controllerutils.NewClientWithMetricsOrDie(mgr, controllerName),
+		scheme:             mgr.GetScheme(),
+		logger:             logger,
+		applierBuilder:     applierBuilderFunc,
+		reapplyInterval:    reapplyInterval,
+		driftCheckInterval: driftCheckInterval,
+
+		serverSideApplierBuilder: serverSideApplierBuilderFunc,
 	}
 	r.hash = r.resourceHash
 	r.remoteClusterAPIClientBuilder = func(cd *hivev1.ClusterDeployment) remoteclient.Builder {
@@ -116,14 +136,23 @@ func applierBuilderFunc(restConfig *rest.Config, logger log.FieldLogger) Applier
 	return hiveresource.NewHelperFromRESTConfig(restConfig, logger)
 }
 
+// serverSideApplierBuilderFunc returns an Applier which implements Info, Apply and Patch using server-side apply
+func serverSideApplierBuilderFunc(restConfig *rest.Config, logger log.FieldLogger) (Applier, error) {
+	return hiveresource.NewServerSideApplier(restConfig, logger)
+}
+
 // AddToManager adds a new Controller to mgr with r as the reconcile.Reconciler
 func AddToManager(mgr manager.Manager, r reconcile.Reconciler) error {
 	// Create a new controller
-	c, err := controller.New("syncsetinstance-controller", mgr, controller.Options{Reconciler: r, MaxConcurrentReconciles: controllerutils.GetConcurrentReconciles()})
+	c, err := controller.New("syncsetinstance-controller", mgr, controller.Options{Reconciler: controllerutils.NewShardedReconciler(r), MaxConcurrentReconciles: controllerutils.GetConcurrentReconciles(controllerName)})
 	if err != nil {
 		return err
 	}
 
+	if err := controllerutils.SetQueueRateLimiter(c, controllerName); err != nil {
+		return err
+	}
+
 	// Watch for changes to SyncSetInstance
 	err = c.Watch(&source.Kind{Type: &hivev1.SyncSetInstance{}}, &handler.EnqueueRequestForObject{})
 	if err != nil {
@@ -137,6 +166,14 @@ func AddToManager(mgr manager.Manager, r reconcile.Reconciler) error {
 	if err != nil {
 		return err
 	}
+
+	// Watch for changes to ConfigMaps holding the template parameters of syncsets
+	err = c.Watch(&source.Kind{Type: &corev1.ConfigMap{}}, &handler.EnqueueRequestsFromMapFunc{
+		ToRequests: handler.ToRequestsFunc(r.(*ReconcileSyncSetInstance).handleTemplateParameters),
+	})
+	if err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -167,6 +204,41 @@ func (r *ReconcileSyncSetInstance) handleClusterDeployment(a handler.MapObject)
 	return retval
 }
 
+// handleTemplateParameters maps a ConfigMap to the syncsetinstances in its namespace whose syncset or
+// selectorsyncset renders its resources with the ConfigMap as template parameters.
+func (r *ReconcileSyncSetInstance) handleTemplateParameters(a handler.MapObject) []reconcile.Request {
+	cm, ok := a.Object.(*corev1.ConfigMap)
+	if !ok {
+		return []reconcile.Request{}
+	}
+
+	syncSetInstanceList := &hivev1.SyncSetInstanceList{}
+	err := r.List(context.TODO(), syncSetInstanceList, client.InNamespace(cm.Namespace))
+	if err != nil {
+		r.logger.WithError(err).Error("cannot list syncSetInstances for template parameters configmap")
+		return []reconcile.Request{}
+	}
+
+	retval := []reconcile.Request{}
+	for i, syncSetInstance := range syncSetInstanceList.Items {
+		ssiLog := r.logger.WithField("syncSetInstance", syncSetInstance.Name).WithField("configmap", cm.Name)
+		spec, _, err := r.getSyncSetCommonSpec(&syncSetInstanceList.Items[i], ssiLog)
+		if err != nil || spec == nil {
+			continue
+		}
+		if spec.ResourceTemplateMode == hivev1.GoTemplateResourceTemplateMode &&
+			spec.TemplateParametersRef != nil && spec.TemplateParametersRef.Name == cm.Name {
+			retval = append(retval, reconcile.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      syncSetInstance.Name,
+					Namespace: syncSetInstance.Namespace,
+				},
+			})
+		}
+	}
+	return retval
+}
+
 var _ reconcile.Reconciler = &ReconcileSyncSetInstance{}
 
 // ReconcileSyncSetInstance reconciles a ClusterDeployment and the SyncSets associated with it
@@ -179,6 +251,12 @@ type ReconcileSyncSetInstance struct {
 	hash            func([]byte) string
 	reapplyInterval time.Duration
 
+	// driftCheckInterval is how often resources of syncsets with drift checks enabled are checked for drift
+	driftCheckInterval time.Duration
+
+	// serverSideApplierBuilder builds the Applier for syncsetinstances with the ServerSideApply resource apply mode
+	serverSideApplierBuilder func(*rest.Config, log.FieldLogger) (Applier, error)
+
 	// remoteClusterAPIClientBuilder is a function pointer to the function that gets a builder for building a client
 	// for the remote cluster's API server
 	remoteClusterAPIClientBuilder func(cd *hivev1.ClusterDeployment) remoteclient.Builder
@@ -279,8 +357,20 @@ func (r *ReconcileSyncSetInstance) Reconcile(request reconcile.Request) (reconci
 	ssiLog.Debug("applying sync set")
 	original := ssi.DeepCopy()
 
-	applier := r.applierBuilder(restConfig, ssiLog)
-	applyErr := r.applySyncSet(ssi, spec, dynamicClient, applier, ssiLog)
+	var applier Applier
+	if ssi.Spec.ResourceApplyMode == hivev1.ServerSideApplyResourceApplyMode {
+		applier, err = r.serverSideApplierBuilder(restConfig, ssiLog)
+		if err != nil {
+			ssiLog.WithError(err).Error("unable to build server-side applier")
+			return reconcile.Result{}, err
+		}
+	} else {
+		applier = r.applierBuilder(restConfig, ssiLog)
+	}
+	applyErr := r.renderSyncSetResources(ssi, cd, spec, ssiLog)
+	if applyErr == nil {
+		applyErr = r.applySyncSet(ssi, spec, dynamicClient, applier, ssiLog)
+	}
 	ssi.Status.Applied = applyErr == nil
 
 	err = r.updateSyncSetInstanceStatus(ssi, original, ssiLog)
@@ -294,6 +384,11 @@ func (r *ReconcileSyncSetInstance) Reconcile(request reconcile.Request) (reconci
 	}
 
 	reapplyDuration := ssiReapplyDuration(ssi, r.reapplyInterval)
+	// Requeue for the next drift check before resources are due to be re-applied. Drifted resources which are
+	// not re-applied in audit mode keep the reapply duration from becoming positive.
+	if driftCheckEnabled(spec.DriftCheckMode) && (reapplyDuration <= 0 || reapplyDuration > r.driftCheckInterval) {
+		reapplyDuration = r.driftCheckInterval
+	}
 	return reconcile.Result{RequeueAfter: reapplyDuration}, nil
 }
 
@@ -405,7 +500,7 @@ func (r *ReconcileSyncSetInstance) applySyncSet(ssi *hivev1.SyncSetInstance, spe
 		}
 	}()
 
-	if err := r.applySyncSetResources(ssi, spec.Resources, dynamicClient, h, ssiLog); err != nil {
+	if err := r.applySyncSetResources(ssi, spec.Resources, spec.DriftCheckMode, dynamicClient, h, ssiLog); err != nil {
 		return err
 	}
 	if err := r.applySyncSetPatches(ssi, spec.Patches, h, ssiLog); err != nil {
@@ -414,6 +509,58 @@ func (r *ReconcileSyncSetInstance) applySyncSet(ssi *hivev1.SyncSetInstance, spe
 	return r.applySyncSetSecretMappings(ssi, spec.Secrets, dynamicClient, h, ssiLog)
 }
 
+// renderSyncSetResources renders the resources of a syncset with the GoTemplate resource template mode for the
+// cluster. The rendered resources replace the resources of the spec, so that the rendered resources are hashed.
+func (r *ReconcileSyncSetInstance) renderSyncSetResources(ssi *hivev1.SyncSetInstance, cd *hivev1.ClusterDeployment, spec *hivev1.SyncSetCommonSpec, ssiLog log.FieldLogger) error {
+	if spec.ResourceTemplateMode != hivev1.GoTemplateResourceTemplateMode {
+		return nil
+	}
+	var parameters map[string]string
+	if ref := spec.TemplateParametersRef; ref != nil {
+		cm := &corev1.ConfigMap{}
+		if err := r.Get(context.TODO(), types.NamespacedName{Namespace: cd.Namespace, Name: ref.Name}, cm); err != nil {
+			ssiLog.WithError(err).WithField("configmap", ref.Name).Error("cannot get template parameters configmap")
+			ssi.Status.Conditions = r.setTemplateRenderFailedSyncCondition(ssi.Status.Conditions,
+				fmt.Sprintf("Unable to get template parameters ConfigMap %s: %v", ref.Name, err))
+			return err
+		}
+		parameters = cm.Data
+	}
+	data := syncsettemplate.NewData(cd, parameters)
+	rendered := make([]runtime.RawExtension, len(spec.Resources))
+	for i, resource := range spec.Resources {
+		raw, err := syncsettemplate.Render(resource.Raw, data)
+		if err != nil {
+			ssiLog.WithError(err).Warn("unable to render resource template")
+			ssi.Status.Conditions = r.setTemplateRenderFailedSyncCondition(ssi.Status.Conditions,
+				fmt.Sprintf("Unable to render SyncSet resource at index %v in resources: %v", i, err))
+			return err
+		}
+		rendered[i] = runtime.RawExtension{Raw: raw}
+	}
+	ssi.Status.Conditions = controllerutils.SetSyncCondition(
+		ssi.Status.Conditions,
+		hivev1.TemplateRenderFailedSyncCondition,
+		corev1.ConditionFalse,
+		templateRenderSucceededReason,
+		"All SyncSet resources rendered",
+		controllerutils.UpdateConditionIfReasonOrMessageChange,
+	)
+	spec.Resources = rendered
+	return nil
+}
+
+func (r *ReconcileSyncSetInstance) setTemplateRenderFailedSyncCondition(syncSetConditions []hivev1.SyncCondition, message string) []hivev1.SyncCondition {
+	return controllerutils.SetSyncCondition(
+		syncSetConditions,
+		hivev1.TemplateRenderFailedSyncCondition,
+		corev1.ConditionTrue,
+		templateRenderFailedReason,
+		message,
+		controllerutils.UpdateConditionIfReasonOrMessageChange,
+	)
+}
+
 func (r *ReconcileSyncSetInstance) deleteSyncSetResources(ssi *hivev1.SyncSetInstance, dynamicClient dynamic.Interface, ssiLog log.FieldLogger) error {
 	var lastError error
 	for index, resourceStatus := range ssi.Status.Resources {
@@ -547,7 +694,7 @@ func (r *ReconcileSyncSetInstance) needToReapply(applyTerm string, newStatus, ex
 }
 
 // applySyncSetResources evaluates resource objects from RawExtension and applies them to the cluster identified by kubeConfig
-func (r *ReconcileSyncSetInstance) applySyncSetResources(ssi *hivev1.SyncSetInstance, resources []runtime.RawExtension, dynamicClient dynamic.Interface, h Applier, ssiLog log.FieldLogger) error {
+func (r *ReconcileSyncSetInstance) applySyncSetResources(ssi *hivev1.SyncSetInstance, resources []runtime.RawExtension, driftCheckMode hivev1.SyncSetDriftCheckMode, dynamicClient dynamic.Interface, h Applier, ssiLog log.FieldLogger) error {
 	// determine if we can gather info for all resources
 	infos := []hiveresource.Info{}
 	for i, resource := range resources {
@@ -574,27 +721,49 @@ func (r *ReconcileSyncSetInstance) applySyncSetResources(ssi *hivev1.SyncSetInst
 			Hash:       r.hash(resource.Raw),
 		}
 
-		if rss := findSyncStatus(resourceSyncStatus, ssi.Status.Resources); rss == nil || r.needToReapply("resource", resourceSyncStatus, *rss, ssiLog) {
+		rss := findSyncStatus(resourceSyncStatus, ssi.Status.Resources)
+		var resourceSyncConditions []hivev1.SyncCondition
+		if rss != nil {
+			resourceSyncConditions = rss.Conditions
+		}
+
+		// Check resources that have been applied and have not changed in the syncset for drift
+		drift := ""
+		if driftCheckEnabled(driftCheckMode) && rss != nil && rss.Hash == resourceSyncStatus.Hash {
+			var driftErr error
+			drift, driftErr = r.resourceDrift(resource.Raw, resourceSyncStatus, dynamicClient)
+			if driftErr != nil {
+				ssiLog.WithError(driftErr).Warnf("unable to check resource %s/%s (%s) for drift", resourceSyncStatus.Namespace, resourceSyncStatus.Name, resourceSyncStatus.Kind)
+			} else {
+				resourceSyncConditions = r.setDriftedSyncCondition(resourceSyncConditions, drift)
+			}
+		}
+
+		switch {
+		case drift != "" && driftCheckMode == hivev1.AuditDriftCheckMode:
+			// Do not apply drifted resource until it changes in the syncset
+			ssiLog.Infof("resource %s/%s (%s) has drifted, will not re-apply in audit mode: %s", resourceSyncStatus.Namespace, resourceSyncStatus.Name, resourceSyncStatus.Kind, drift)
+			resourceSyncStatus.Conditions = resourceSyncConditions
+		case rss == nil || r.needToReapply("resource", resourceSyncStatus, *rss, ssiLog):
 			// Apply resource
 			ssiLog.Debugf("applying resource: %s/%s (%s)", resourceSyncStatus.Namespace, resourceSyncStatus.Name, resourceSyncStatus.Kind)
 			var applyResult hiveresource.ApplyResult
 			applyResult, applyErr = h.Apply(resource.Raw)
 
-			var resourceSyncConditions []hivev1.SyncCondition
-			if rss != nil {
-				resourceSyncConditions = rss.Conditions
-			}
 			resourceSyncStatus.Conditions = r.setApplySyncConditions(resourceSyncConditions, applyErr)
 
 			if applyErr != nil {
 				ssiLog.WithError(applyErr).Warnf("error applying resource %s/%s (%s)", resourceSyncStatus.Namespace, resourceSyncStatus.Name, resourceSyncStatus.Kind)
 			} else {
 				ssiLog.Debugf("resource %s/%s (%s): %s", resourceSyncStatus.Namespace, resourceSyncStatus.Name, resourceSyncStatus.Kind, applyResult)
+				if drift != "" {
+					resourceSyncStatus.Conditions = r.setDriftRevertedSyncCondition(resourceSyncStatus.Conditions)
+				}
 			}
-		} else {
+		default:
 			// Do not apply resource
 			ssiLog.Debugf("resource %s/%s (%s) has not changed, will not apply", resourceSyncStatus.Namespace, resourceSyncStatus.Name, resourceSyncStatus.Kind)
-			resourceSyncStatus.Conditions = rss.Conditions
+			resourceSyncStatus.Conditions = resourceSyncConditions
 		}
 
 		syncStatusList = append(syncStatusList, resourceSyncStatus)
@@ -618,8 +787,8 @@ func (r *ReconcileSyncSetInstance) applySyncSetResources(ssi *hivev1.SyncSetInst
 
 func (r *ReconcileSyncSetInstance) reconcileDeleted(deleteTerm string, applyMode hivev1.SyncSetResourceApplyMode, dynamicClient dynamic.Interface, existingStatusList, newStatusList []hivev1.SyncStatus, err error, ssiLog log.FieldLogger) []hivev1.SyncStatus {
 	ssiLog.Debugf("reconciling syncset %ss, existing: %d, actual: %d", deleteTerm, len(existingStatusList), len(newStatusList))
-	if applyMode == "" || applyMode == hivev1.UpsertResourceApplyMode {
-		ssiLog.Debugf("apply mode is upsert, remote %ss will not be deleted", deleteTerm)
+	if applyMode != hivev1.SyncResourceApplyMode {
+		ssiLog.Debugf("apply mode is not sync, remote %ss will not be deleted", deleteTerm)
 		return newStatusList
 	}
 
@@ -909,6 +1078,26 @@ func (r *ReconcileSyncSetInstance) setApplySyncConditions(resourceSyncConditions
 		reason,
 		message,
 		updateCondition)
+
+	// Report conflicts with other field managers found by server-side apply. The condition is only added
+	// once a conflict has been found.
+	if hiveresource.IsApplyConflict(err) {
+		resourceSyncConditions = controllerutils.SetSyncCondition(
+			resourceSyncConditions,
+			hivev1.ApplyConflictSyncCondition,
+			corev1.ConditionTrue,
+			applyConflictReason,
+			err.Error(),
+			controllerutils.UpdateConditionIfReasonOrMessageChange)
+	} else {
+		resourceSyncConditions = controllerutils.SetSyncCondition(
+			resourceSyncConditions,
+			hivev1.ApplyConflictSyncCondition,
+			corev1.ConditionFalse,
+			noApplyConflictReason,
+			"No conflicts with other field managers",
+			controllerutils.UpdateConditionIfReasonOrMessageChange)
+	}
 	return resourceSyncConditions
 }
 
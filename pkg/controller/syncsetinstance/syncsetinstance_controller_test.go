@@ -44,6 +44,7 @@ import (
 	"k8s.io/client-go/rest"
 
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/openshift/hive/pkg/apis"
@@ -72,20 +73,22 @@ func TestSyncSetReconcile(t *testing.T) {
 	tenMinutesAgo := time.Unix(metav1.NewTime(time.Now().Add(-10*time.Minute)).Unix(), 0)
 
 	tests := []struct {
-		name                   string
-		status                 hivev1.SyncSetInstanceStatus
-		syncSet                *hivev1.SyncSet
-		deletedSyncSet         *hivev1.SyncSet
-		selectorSyncSet        *hivev1.SelectorSyncSet
-		deletedSelectorSyncSet *hivev1.SelectorSyncSet
-		existingObjs           []runtime.Object
-		clusterDeployment      *hivev1.ClusterDeployment
-		validate               func(*testing.T, *hivev1.SyncSetInstance)
-		isDeleted              bool
-		expectDeleted          []deletedItemInfo
-		expectSSIDeleted       bool
-		expectErr              bool
-		expectApplied          bool
+		name                    string
+		status                  hivev1.SyncSetInstanceStatus
+		syncSet                 *hivev1.SyncSet
+		deletedSyncSet          *hivev1.SyncSet
+		selectorSyncSet         *hivev1.SelectorSyncSet
+		deletedSelectorSyncSet  *hivev1.SelectorSyncSet
+		existingObjs            []runtime.Object
+		remoteObjs              []runtime.Object
+		clusterDeployment       *hivev1.ClusterDeployment
+		validate                func(*testing.T, *hivev1.SyncSetInstance)
+		isDeleted               bool
+		expectDeleted           []deletedItemInfo
+		expectSSIDeleted        bool
+		expectErr               bool
+		expectApplied           bool
+		expectServerSideApplied bool
 	}{
 		{
 			name:    "Create single resource successfully",
@@ -189,6 +192,210 @@ func TestSyncSetReconcile(t *testing.T) {
 			},
 			expectErr: true,
 		},
+		{
+			name: "Apply resources with server-side apply",
+			syncSet: func() *hivev1.SyncSet {
+				ss := testSyncSetWithResources("foo",
+					testCM("cm1", "key1", "value1"),
+					testCM("cm2", "key2", "value2"),
+				)
+				ss.Spec.ResourceApplyMode = hivev1.ServerSideApplyResourceApplyMode
+				return ss
+			}(),
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				validateSyncSetInstanceStatus(t, ssi.Status, successfulResourceStatus(
+					testCM("cm1", "key1", "value1"),
+					testCM("cm2", "key2", "value2"),
+				))
+			},
+			expectApplied:           true,
+			expectServerSideApplied: true,
+		},
+		{
+			name: "Report conflicts found by server-side apply",
+			syncSet: func() *hivev1.SyncSet {
+				ss := testSyncSetWithResources("foo",
+					testCM("cm1", "key1", "value1"),
+					testCM("apply-conflict", "key2", "value2"),
+				)
+				ss.Spec.ResourceApplyMode = hivev1.ServerSideApplyResourceApplyMode
+				return ss
+			}(),
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				status := successfulResourceStatus(
+					testCM("cm1", "key1", "value1"),
+				)
+				conflictStatus := applyFailedResourceStatus("foo",
+					testCM("apply-conflict", "key2", "value2"),
+				)
+				conflictStatus.Resources[0].Conditions = append(conflictStatus.Resources[0].Conditions, hivev1.SyncCondition{
+					Type:   hivev1.ApplyConflictSyncCondition,
+					Status: corev1.ConditionTrue,
+				})
+				status.Resources = append(status.Resources, conflictStatus.Resources...)
+				validateSyncSetInstanceStatus(t, ssi.Status, status)
+			},
+			expectErr:               true,
+			expectServerSideApplied: true,
+		},
+		{
+			name: "Report drifted resource",
+			status: successfulResourceStatusWithTime(
+				[]runtime.Object{testCM("cm1", "key1", "value1")},
+				metav1.NewTime(tenMinutesAgo)),
+			syncSet: testSyncSetWithDriftCheckMode(hivev1.ReportDriftCheckMode,
+				testSyncSetWithResources("ss1", testCM("cm1", "key1", "value1"))),
+			remoteObjs: []runtime.Object{testCMManagedBy("kubectl", "cm1", "key1", "value***drifted")},
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				drifted := findDriftedCondition(t, ssi.Status.Resources[0])
+				assert.Equal(t, corev1.ConditionTrue, drifted.Status, "unexpected drifted condition status")
+				assert.Equal(t, "fields changed on target cluster: .data.key1, .metadata.annotations.hash", drifted.Message, "unexpected drifted condition message")
+				applied := controllerutils.FindSyncCondition(ssi.Status.Resources[0].Conditions, hivev1.ApplySuccessSyncCondition)
+				assert.Equal(t, tenMinutesAgo.Unix(), applied.LastProbeTime.Time.Unix(), "resource should not have been re-applied")
+			},
+			expectApplied: true,
+		},
+		{
+			name: "Report resource without drift",
+			status: successfulResourceStatusWithTime(
+				[]runtime.Object{testCM("cm1", "key1", "value1")},
+				metav1.NewTime(tenMinutesAgo)),
+			syncSet: testSyncSetWithDriftCheckMode(hivev1.ReportDriftCheckMode,
+				testSyncSetWithResources("ss1", testCM("cm1", "key1", "value1"))),
+			remoteObjs: []runtime.Object{testCMManagedBy(resource.FieldManager, "cm1", "key1", "value1")},
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				validateSyncSetInstanceStatus(t, ssi.Status, successfulResourceStatus(testCM("cm1", "key1", "value1")))
+			},
+			expectApplied: true,
+		},
+		{
+			name: "Report resource deleted from target cluster",
+			status: successfulResourceStatusWithTime(
+				[]runtime.Object{testCM("cm1", "key1", "value1")},
+				metav1.NewTime(tenMinutesAgo)),
+			syncSet: testSyncSetWithDriftCheckMode(hivev1.ReportDriftCheckMode,
+				testSyncSetWithResources("ss1", testCM("cm1", "key1", "value1"))),
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				drifted := findDriftedCondition(t, ssi.Status.Resources[0])
+				assert.Equal(t, corev1.ConditionTrue, drifted.Status, "unexpected drifted condition status")
+				assert.Equal(t, "resource not found on target cluster", drifted.Message, "unexpected drifted condition message")
+			},
+			expectApplied: true,
+		},
+		{
+			name: "Report resource without drift when changed by hive",
+			status: successfulResourceStatusWithTime(
+				[]runtime.Object{testCM("cm1", "key1", "value1")},
+				metav1.NewTime(tenMinutesAgo)),
+			syncSet: testSyncSetWithDriftCheckMode(hivev1.ReportDriftCheckMode,
+				testSyncSetWithResources("ss1", testCM("cm1", "key1", "value1"))),
+			remoteObjs: []runtime.Object{testCMManagedBy(resource.FieldManager, "cm1", "key1", "value***normalized")},
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				validateSyncSetInstanceStatus(t, ssi.Status, successfulResourceStatus(testCM("cm1", "key1", "value1")))
+			},
+			expectApplied: true,
+		},
+		{
+			name: "Skip drift check of resource without managed fields",
+			status: successfulResourceStatusWithTime(
+				[]runtime.Object{testCM("cm1", "key1", "value1")},
+				metav1.NewTime(tenMinutesAgo)),
+			syncSet: testSyncSetWithDriftCheckMode(hivev1.ReportDriftCheckMode,
+				testSyncSetWithResources("ss1", testCM("cm1", "key1", "value1"))),
+			remoteObjs: []runtime.Object{testCM("cm1", "key1", "value***drifted")},
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				validateSyncSetInstanceStatus(t, ssi.Status, successfulResourceStatus(testCM("cm1", "key1", "value1")))
+			},
+			expectApplied: true,
+		},
+		{
+			name: "Revert drifted resource when reapply interval has passed",
+			status: successfulResourceStatusWithTime(
+				[]runtime.Object{testCM("cm1", "key1", "value1")},
+				metav1.NewTime(time.Now().Add(-3*time.Hour))),
+			syncSet: testSyncSetWithDriftCheckMode(hivev1.ReportDriftCheckMode,
+				testSyncSetWithResources("ss1", testCM("cm1", "key1", "value1"))),
+			remoteObjs: []runtime.Object{testCMManagedBy("kubectl", "cm1", "key1", "value***drifted")},
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				drifted := findDriftedCondition(t, ssi.Status.Resources[0])
+				assert.Equal(t, corev1.ConditionFalse, drifted.Status, "unexpected drifted condition status")
+				assert.Equal(t, driftRevertedReason, drifted.Reason, "unexpected drifted condition reason")
+			},
+			expectApplied: true,
+		},
+		{
+			name: "Do not revert drifted resource in audit mode",
+			status: successfulResourceStatusWithTime(
+				[]runtime.Object{testCM("cm1", "key1", "value1")},
+				metav1.NewTime(time.Now().Add(-3*time.Hour))),
+			syncSet: testSyncSetWithDriftCheckMode(hivev1.AuditDriftCheckMode,
+				testSyncSetWithResources("ss1", testCM("cm1", "key1", "value1"))),
+			remoteObjs: []runtime.Object{testCMManagedBy("kubectl", "cm1", "key1", "value***drifted")},
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				drifted := findDriftedCondition(t, ssi.Status.Resources[0])
+				assert.Equal(t, corev1.ConditionTrue, drifted.Status, "unexpected drifted condition status")
+				applied := controllerutils.FindSyncCondition(ssi.Status.Resources[0].Conditions, hivev1.ApplySuccessSyncCondition)
+				assert.True(t, time.Since(applied.LastProbeTime.Time) > 2*time.Hour, "resource should not have been re-applied")
+			},
+			expectApplied: true,
+		},
+		{
+			name: "Re-apply drifted resource changed in syncset in audit mode",
+			status: successfulResourceStatusWithTime(
+				[]runtime.Object{testCM("cm1", "key1", "value1")},
+				metav1.NewTime(tenMinutesAgo)),
+			syncSet: testSyncSetWithDriftCheckMode(hivev1.AuditDriftCheckMode,
+				testSyncSetWithResources("ss1", testCM("cm1", "key1", "value***changed"))),
+			remoteObjs: []runtime.Object{testCMManagedBy("kubectl", "cm1", "key1", "value***drifted")},
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				validateSyncSetInstanceStatus(t, ssi.Status, successfulResourceStatus(testCM("cm1", "key1", "value***changed")))
+			},
+			expectApplied: true,
+		},
+		{
+			name: "Apply templated resources",
+			syncSet: func() *hivev1.SyncSet {
+				ss := testSyncSetWithResources("ss1",
+					testCM("cm1", "key1", "{{ .ClusterName }}"),
+					testCM("cm2", "key2", "{{ .Parameters.key2 }}"),
+				)
+				ss.Spec.ResourceTemplateMode = hivev1.GoTemplateResourceTemplateMode
+				ss.Spec.TemplateParametersRef = &corev1.LocalObjectReference{Name: "params"}
+				return ss
+			}(),
+			existingObjs: []runtime.Object{
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: "params", Namespace: testNamespace},
+					Data:       map[string]string{"key2": "value2"},
+				},
+			},
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				// The hash of the status is the hash of the rendered resource
+				validateSyncSetInstanceStatus(t, ssi.Status, successfulResourceStatus(
+					testCM("cm1", "key1", testName),
+					testCM("cm2", "key2", "value2"),
+				))
+			},
+			expectApplied: true,
+		},
+		{
+			name: "Report template render failure",
+			syncSet: func() *hivev1.SyncSet {
+				ss := testSyncSetWithResources("ss1",
+					testCM("cm1", "key1", "{{ .Parameters.missing }}"),
+				)
+				ss.Spec.ResourceTemplateMode = hivev1.GoTemplateResourceTemplateMode
+				return ss
+			}(),
+			validate: func(t *testing.T, ssi *hivev1.SyncSetInstance) {
+				cond := controllerutils.FindSyncCondition(ssi.Status.Conditions, hivev1.TemplateRenderFailedSyncCondition)
+				if assert.NotNil(t, cond, "expected template render failed condition") {
+					assert.Equal(t, corev1.ConditionTrue, cond.Status, "unexpected template render failed condition status")
+				}
+				assert.Empty(t, ssi.Status.Resources, "expected no resources to be applied")
+			},
+			expectErr: true,
+		},
 		{
 			name: "Stop applying resources when have annotation: hive.openshift.io/syncset-pause=true",
 			clusterDeployment: func() *hivev1.ClusterDeployment {
@@ -622,6 +829,9 @@ func TestSyncSetReconcile(t *testing.T) {
 			runtimeObjs = append(runtimeObjs, ssi)
 			fakeClient := fake.NewFakeClient(runtimeObjs...)
 			dynamicClient := &fakeDynamicClient{}
+			for _, obj := range test.remoteObjs {
+				dynamicClient.addObject(t, obj)
+			}
 
 			helper := &fakeHelper{t: t}
 
@@ -637,6 +847,7 @@ func TestSyncSetReconcile(t *testing.T) {
 				scheme:                        scheme.Scheme,
 				logger:                        log.WithField("controller", "syncset"),
 				applierBuilder:                helper.newHelper,
+				serverSideApplierBuilder:      helper.newServerSideApplier,
 				hash:                          fakeHashFunc(t),
 				remoteClusterAPIClientBuilder: func(*hivev1.ClusterDeployment) remoteclient.Builder { return mockRemoteClientBuilder },
 				reapplyInterval:               2 * time.Hour,
@@ -673,6 +884,7 @@ func TestSyncSetReconcile(t *testing.T) {
 			}
 
 			assert.Equal(t, test.expectApplied, result.Status.Applied)
+			assert.Equal(t, test.expectServerSideApplied, helper.serverSideApply, "unexpected use of server-side apply")
 		})
 	}
 }
@@ -774,6 +986,19 @@ func testSyncSetWithResources(name string, resources ...runtime.Object) *hivev1.
 	return ss
 }
 
+func testSyncSetWithDriftCheckMode(mode hivev1.SyncSetDriftCheckMode, ss *hivev1.SyncSet) *hivev1.SyncSet {
+	ss.Spec.DriftCheckMode = mode
+	return ss
+}
+
+func findDriftedCondition(t *testing.T, status hivev1.SyncStatus) *hivev1.SyncCondition {
+	cond := controllerutils.FindSyncCondition(status.Conditions, hivev1.DriftedSyncCondition)
+	if cond == nil {
+		t.Fatalf("drifted condition not found for resource %s/%s", status.Namespace, status.Name)
+	}
+	return cond
+}
+
 func testSyncSetWithPatches(name string, patches ...hivev1.SyncObjectPatch) *hivev1.SyncSet {
 	ss := &hivev1.SyncSet{
 		ObjectMeta: metav1.ObjectMeta{
@@ -881,6 +1106,27 @@ func testCM(name, key, value string) runtime.Object {
 	}
 }
 
+// testCMManagedBy returns a config map whose data and hash annotation are owned by the field manager.
+func testCMManagedBy(manager, name, key, value string) runtime.Object {
+	cm := testCM(name, key, value).(*corev1.ConfigMap)
+	cm.ManagedFields = []metav1.ManagedFieldsEntry{{
+		Manager:    manager,
+		Operation:  metav1.ManagedFieldsOperationUpdate,
+		APIVersion: "v1",
+		Fields: &metav1.Fields{Map: map[string]metav1.Fields{
+			"f:data": {Map: map[string]metav1.Fields{
+				"f:" + key: {Map: map[string]metav1.Fields{}},
+			}},
+			"f:metadata": {Map: map[string]metav1.Fields{
+				"f:annotations": {Map: map[string]metav1.Fields{
+					"f:hash": {Map: map[string]metav1.Fields{}},
+				}},
+			}},
+		}},
+	}}
+	return cm
+}
+
 func deletedItem(name, resource string) deletedItemInfo {
 	return deletedItemInfo{
 		name:      name,
@@ -1186,13 +1432,19 @@ func successfulPatchStatusWithTime(patches []hivev1.SyncObjectPatch, conditionTi
 }
 
 type fakeHelper struct {
-	t *testing.T
+	t               *testing.T
+	serverSideApply bool
 }
 
 func (f *fakeHelper) newHelper(*rest.Config, log.FieldLogger) Applier {
 	return f
 }
 
+func (f *fakeHelper) newServerSideApplier(*rest.Config, log.FieldLogger) (Applier, error) {
+	f.serverSideApply = true
+	return f, nil
+}
+
 func (f *fakeHelper) ApplyRuntimeObject(object runtime.Object, scheme *runtime.Scheme) (resource.ApplyResult, error) {
 	data, err := json.Marshal(object)
 	if err != nil {
@@ -1209,6 +1461,9 @@ func (f *fakeHelper) Apply(data []byte) (resource.ApplyResult, error) {
 	if info.Name == "apply-error" {
 		return "", fmt.Errorf("cannot apply resource")
 	}
+	if info.Name == "apply-conflict" {
+		return "", &resource.ApplyConflictError{Conflicts: []string{`conflict with "kubectl": .data.key2`}}
+	}
 	return resource.UnknownApplyResult, nil
 }
 
@@ -1404,6 +1659,15 @@ type deletedItemInfo struct {
 
 type fakeDynamicClient struct {
 	deletedItems []deletedItemInfo
+	objects      []*unstructured.Unstructured
+}
+
+func (c *fakeDynamicClient) addObject(t *testing.T, obj runtime.Object) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(obj)
+	if err != nil {
+		t.Fatalf("cannot convert object to unstructured: %v", err)
+	}
+	c.objects = append(c.objects, &unstructured.Unstructured{Object: content})
 }
 
 type fakeNamespaceableClient struct {
@@ -1459,7 +1723,12 @@ func (c *fakeNamespaceableClient) DeleteCollection(options *metav1.DeleteOptions
 }
 
 func (c *fakeNamespaceableClient) Get(name string, options metav1.GetOptions, subresources ...string) (*unstructured.Unstructured, error) {
-	return nil, nil
+	for _, obj := range c.client.objects {
+		if obj.GetName() == name && obj.GetNamespace() == c.namespace {
+			return obj, nil
+		}
+	}
+	return nil, errors.NewNotFound(c.resource.GroupResource(), name)
 }
 
 func (c *fakeNamespaceableClient) List(opts metav1.ListOptions) (*unstructured.UnstructuredList, error) {
@@ -1536,6 +1805,48 @@ func computeHash(data interface{}) string {
 	return fmt.Sprintf("%x", md5.Sum(b))
 }
 
+func TestHandleTemplateParameters(t *testing.T) {
+	templated := func(ss *hivev1.SyncSetCommonSpec, parametersRef string) {
+		ss.ResourceTemplateMode = hivev1.GoTemplateResourceTemplateMode
+		if parametersRef != "" {
+			ss.TemplateParametersRef = &corev1.LocalObjectReference{Name: parametersRef}
+		}
+	}
+	cd := testClusterDeployment()
+	ssWithParameters := testSyncSetWithResources("ss1", testCM("cm1", "key", "{{ .Parameters.key }}"))
+	templated(&ssWithParameters.Spec.SyncSetCommonSpec, "params")
+	ssWithOtherParameters := testSyncSetWithResources("ss2", testCM("cm2", "key", "{{ .Parameters.key }}"))
+	templated(&ssWithOtherParameters.Spec.SyncSetCommonSpec, "other-params")
+	ssNotTemplated := testSyncSetWithResources("ss3", testCM("cm3", "key", "value"))
+	ssNotTemplated.Spec.TemplateParametersRef = &corev1.LocalObjectReference{Name: "params"}
+	sssWithParameters := testSelectorSyncSetWithResources("sss1", testCM("cm4", "key", "{{ .Parameters.key }}"))
+	templated(&sssWithParameters.Spec.SyncSetCommonSpec, "params")
+
+	fakeClient := fake.NewFakeClient(
+		cd,
+		ssWithParameters,
+		ssWithOtherParameters,
+		ssNotTemplated,
+		sssWithParameters,
+		syncSetInstanceForSyncSet(cd, ssWithParameters),
+		syncSetInstanceForSyncSet(cd, ssWithOtherParameters),
+		syncSetInstanceForSyncSet(cd, ssNotTemplated),
+		syncSetInstanceForSelectorSyncSet(cd, sssWithParameters),
+	)
+	r := &ReconcileSyncSetInstance{
+		Client: fakeClient,
+		scheme: scheme.Scheme,
+		logger: log.WithField("controller", "syncset"),
+	}
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "params", Namespace: testNamespace}}
+	requests := r.handleTemplateParameters(handler.MapObject{Meta: cm, Object: cm})
+	expected := []reconcile.Request{
+		{NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: syncSetInstanceNameForSyncSet(cd, ssWithParameters)}},
+		{NamespacedName: types.NamespacedName{Namespace: testNamespace, Name: syncSetInstanceNameForSelectorSyncSet(cd, sssWithParameters)}},
+	}
+	assert.ElementsMatch(t, expected, requests, "unexpected syncsetinstances for template parameters")
+}
+
 func TestSSIReApplyDuration(t *testing.T) {
 	startProbe := time.Now()
 
@@ -35,12 +35,13 @@ func init() {
 
 // NewClientWithMetricsOrDie creates a new controller-runtime client with a wrapper which increments
 // metrics for requests by controller name, HTTP method, URL path, and whether or not the request was
-// to a remote cluster.. The client will re-use the managers cache. This should be used in
-// all Hive controllers.
+// to a remote cluster.. The client will re-use the managers cache, and is rate limited with the client
+// rate limits of the controller. This should be used in all Hive controllers.
 func NewClientWithMetricsOrDie(mgr manager.Manager, ctrlrName string) client.Client {
 	// Copy the rest config as we want our round trippers to be controller specific.
 	cfg := rest.CopyConfig(mgr.GetConfig())
 	AddControllerMetricsTransportWrapper(cfg, ctrlrName, false)
+	setClientRateLimits(cfg, ctrlrName)
 
 	options := client.Options{
 		Scheme: mgr.GetScheme(),
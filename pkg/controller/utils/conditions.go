@@ -331,6 +331,121 @@ func SetMachinePoolConditionWithChangeCheck(
 	return conditions, changed
 }
 
+// SetClusterClaimCondition sets a condition on a ClusterClaim resource's status
+func SetClusterClaimCondition(
+	conditions []hivev1.ClusterClaimCondition,
+	conditionType hivev1.ClusterClaimConditionType,
+	status corev1.ConditionStatus,
+	reason string,
+	message string,
+	updateConditionCheck UpdateConditionCheck,
+) []hivev1.ClusterClaimCondition {
+	newConditions, _ := SetClusterClaimConditionWithChangeCheck(
+		conditions,
+		conditionType,
+		status,
+		reason,
+		message,
+		updateConditionCheck,
+	)
+	return newConditions
+}
+
+// SetClusterClaimConditionWithChangeCheck sets a condition on a ClusterClaim resource's status.
+// It returns the conditions as well a boolean indicating whether there was a change made
+// to the conditions.
+func SetClusterClaimConditionWithChangeCheck(
+	conditions []hivev1.ClusterClaimCondition,
+	conditionType hivev1.ClusterClaimConditionType,
+	status corev1.ConditionStatus,
+	reason string,
+	message string,
+	updateConditionCheck UpdateConditionCheck,
+) ([]hivev1.ClusterClaimCondition, bool) {
+	changed := false
+	now := metav1.Now()
+	existingCondition := FindClusterClaimCondition(conditions, conditionType)
+	if existingCondition == nil {
+		if status == corev1.ConditionTrue {
+			conditions = append(
+				conditions,
+				hivev1.ClusterClaimCondition{
+					Type:               conditionType,
+					Status:             status,
+					Reason:             reason,
+					Message:            message,
+					LastTransitionTime: now,
+					LastProbeTime:      now,
+				},
+			)
+			changed = true
+		}
+	} else {
+		if shouldUpdateCondition(
+			existingCondition.Status, existingCondition.Reason, existingCondition.Message,
+			status, reason, message,
+			updateConditionCheck,
+		) {
+			if existingCondition.Status != status {
+				existingCondition.LastTransitionTime = now
+			}
+			existingCondition.Status = status
+			existingCondition.Reason = reason
+			existingCondition.Message = message
+			existingCondition.LastProbeTime = now
+			changed = true
+		}
+	}
+	return conditions, changed
+}
+
+// SetClusterImageSetConditionWithChangeCheck sets a condition on a ClusterImageSet resource's status.
+// It returns the conditions as well a boolean indicating whether there was a change made
+// to the conditions. Unlike most conditions, a false condition is added when the condition
+// does not exist yet.
+func SetClusterImageSetConditionWithChangeCheck(
+	conditions []hivev1.ClusterImageSetCondition,
+	conditionType hivev1.ClusterImageSetConditionType,
+	status corev1.ConditionStatus,
+	reason string,
+	message string,
+	updateConditionCheck UpdateConditionCheck,
+) ([]hivev1.ClusterImageSetCondition, bool) {
+	changed := false
+	now := metav1.Now()
+	existingCondition := FindClusterImageSetCondition(conditions, conditionType)
+	if existingCondition == nil {
+		conditions = append(
+			conditions,
+			hivev1.ClusterImageSetCondition{
+				Type:               conditionType,
+				Status:             status,
+				Reason:             reason,
+				Message:            message,
+				LastTransitionTime: now,
+				LastProbeTime:      now,
+			},
+		)
+		changed = true
+	} else {
+		if shouldUpdateCondition(
+			existingCondition.Status, existingCondition.Reason, existingCondition.Message,
+			status, reason, message,
+			updateConditionCheck,
+		) {
+			if existingCondition.Status != status {
+				existingCondition.LastTransitionTime = now
+			}
+			existingCondition.Status = status
+			existingCondition.Reason = reason
+			existingCondition.Message = message
+			existingCondition.LastProbeTime = now
+			changed = true
+		}
+	}
+	return conditions, changed
+}
+
 // FindClusterDeploymentCondition finds in the condition that has the
 // specified condition type in the given list. If none exists, then returns nil.
 func FindClusterDeploymentCondition(conditions []hivev1.ClusterDeploymentCondition, conditionType hivev1.ClusterDeploymentConditionType) *hivev1.ClusterDeploymentCondition {
@@ -385,3 +500,25 @@ func FindMachinePoolCondition(conditions []hivev1.MachinePoolCondition, conditio
 	}
 	return nil
 }
+
+// FindClusterClaimCondition finds in the condition that has the
+// specified condition type in the given list. If none exists, then returns nil.
+func FindClusterClaimCondition(conditions []hivev1.ClusterClaimCondition, conditionType hivev1.ClusterClaimConditionType) *hivev1.ClusterClaimCondition {
+	for i, condition := range conditions {
+		if condition.Type == conditionType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
+
+// FindClusterImageSetCondition finds in the condition that has the
+// specified condition type in the given list. If none exists, then returns nil.
+func FindClusterImageSetCondition(conditions []hivev1.ClusterImageSetCondition, conditionType hivev1.ClusterImageSetConditionType) *hivev1.ClusterImageSetCondition {
+	for i, condition := range conditions {
+		if condition.Type == conditionType {
+			return &conditions[i]
+		}
+	}
+	return nil
+}
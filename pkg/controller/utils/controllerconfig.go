@@ -0,0 +1,137 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/time/rate"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+
+	"github.com/openshift/hive/pkg/constants"
+)
+
+const (
+	defaultConcurrentReconciles = 5
+
+	// The defaults of the work queue rate limits match the default controller rate limiter of client-go.
+	defaultQueueQPS   = 10
+	defaultQueueBurst = 100
+)
+
+// GetConcurrentReconciles returns the number of goroutines the controller with the given name should use for
+// parallel processing of its queue. This is read from the environment set by the operator from HiveConfig, and
+// defaults to 5.
+func GetConcurrentReconciles(controllerName string) int {
+	return GetConcurrentReconcilesOrDefault(controllerName, defaultConcurrentReconciles)
+}
+
+// GetConcurrentReconcilesOrDefault returns the number of goroutines the controller with the given name should use for
+// parallel processing of its queue, defaulting to the given number.
+func GetConcurrentReconcilesOrDefault(controllerName string, defaultConcurrentReconciles int) int {
+	if value, ok := getControllerConfig(controllerName, constants.ConcurrentReconcilesEnvVarSuffix); ok {
+		return value
+	}
+	return defaultConcurrentReconciles
+}
+
+// setClientRateLimits sets the QPS and burst of the given rest config to the client rate limits of the controller
+// with the given name, if any.
+func setClientRateLimits(cfg *rest.Config, controllerName string) {
+	if qps, ok := getControllerConfig(controllerName, constants.ClientQPSEnvVarSuffix); ok {
+		cfg.QPS = float32(qps)
+	}
+	if burst, ok := getControllerConfig(controllerName, constants.ClientBurstEnvVarSuffix); ok {
+		cfg.Burst = burst
+	}
+}
+
+// SetQueueRateLimiter sets the rate limiter of the work queue of the given controller to the work queue rate limits
+// of the controller with the given name, if any. It must be called before the controller starts watching.
+func SetQueueRateLimiter(c controller.Controller, controllerName string) error {
+	return setQueueRateLimiter(c, controllerName)
+}
+
+func setQueueRateLimiter(c interface{}, controllerName string) error {
+	qps, qpsSet := getControllerConfig(controllerName, constants.QueueQPSEnvVarSuffix)
+	burst, burstSet := getControllerConfig(controllerName, constants.QueueBurstEnvVarSuffix)
+	if !qpsSet && !burstSet {
+		return nil
+	}
+	if !qpsSet {
+		qps = defaultQueueQPS
+	}
+	if !burstSet {
+		burst = defaultQueueBurst
+	}
+
+	// controller-runtime does not allow setting the rate limiter of a controller, so the rate limiter of its work
+	// queue is replaced. The work queue itself is kept so that its metrics are still reported.
+	// TODO: set controller.Options.RateLimiter instead once controller-runtime is updated to v0.5 or later, which
+	// added the option. TestSetQueueRateLimiterController fails if the Queue field of the controller goes away.
+	queue := reflect.ValueOf(c)
+	if queue.Kind() == reflect.Ptr {
+		queue = queue.Elem().FieldByName("Queue")
+	}
+	if !queue.IsValid() || !queue.CanSet() {
+		return fmt.Errorf("cannot set the rate limiter of the work queue of controller %s", controllerName)
+	}
+	rateLimitingQueue, ok := queue.Interface().(workqueue.RateLimitingInterface)
+	if !ok {
+		return fmt.Errorf("unexpected work queue type for controller %s", controllerName)
+	}
+	queue.Set(reflect.ValueOf(workqueue.RateLimitingInterface(&rateLimitedQueue{
+		RateLimitingInterface: rateLimitingQueue,
+		rateLimiter: workqueue.NewMaxOfRateLimiter(
+			workqueue.NewItemExponentialFailureRateLimiter(5*time.Millisecond, 1000*time.Second),
+			&workqueue.BucketRateLimiter{Limiter: rate.NewLimiter(rate.Limit(qps), burst)},
+		),
+	})))
+	log.WithField("controller", controllerName).WithField("qps", qps).WithField("burst", burst).Info("set work queue rate limits")
+	return nil
+}
+
+// rateLimitedQueue is a work queue which delays requeued items with its own rate limiter rather than with the rate
+// limiter of the wrapped queue.
+type rateLimitedQueue struct {
+	workqueue.RateLimitingInterface
+	rateLimiter workqueue.RateLimiter
+}
+
+func (q *rateLimitedQueue) AddRateLimited(item interface{}) {
+	q.RateLimitingInterface.AddAfter(item, q.rateLimiter.When(item))
+}
+
+func (q *rateLimitedQueue) Forget(item interface{}) {
+	q.rateLimiter.Forget(item)
+	q.RateLimitingInterface.Forget(item)
+}
+
+func (q *rateLimitedQueue) NumRequeues(item interface{}) int {
+	return q.rateLimiter.NumRequeues(item)
+}
+
+// getControllerConfig returns the value of a setting of the controller with the given name, falling back to the
+// default setting of all controllers. Invalid values are ignored.
+func getControllerConfig(controllerName, suffix string) (int, bool) {
+	for _, prefix := range []string{controllerName, constants.DefaultControllerConfigEnvVarPrefix} {
+		envVar := constants.ControllerConfigEnvVar(prefix, suffix)
+		value := os.Getenv(envVar)
+		if value == "" {
+			continue
+		}
+		i, err := strconv.Atoi(value)
+		if err != nil || i < 1 {
+			log.WithField("envVar", envVar).WithField("value", value).Warn("ignoring invalid controller setting")
+			continue
+		}
+		return i, true
+	}
+	return 0, false
+}
@@ -0,0 +1,162 @@
+package utils
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openshift/hive/pkg/constants"
+)
+
+func TestGetConcurrentReconciles(t *testing.T) {
+	cases := []struct {
+		name     string
+		env      map[string]string
+		expected int
+	}{
+		{
+			name:     "default",
+			expected: 5,
+		},
+		{
+			name:     "controller setting",
+			env:      map[string]string{"TESTCONTROLLER_CONCURRENT_RECONCILES": "20"},
+			expected: 20,
+		},
+		{
+			name:     "default setting",
+			env:      map[string]string{"DEFAULT_CONCURRENT_RECONCILES": "10"},
+			expected: 10,
+		},
+		{
+			name: "controller setting overrides default setting",
+			env: map[string]string{
+				"DEFAULT_CONCURRENT_RECONCILES":        "10",
+				"TESTCONTROLLER_CONCURRENT_RECONCILES": "20",
+			},
+			expected: 20,
+		},
+		{
+			name:     "other controller setting",
+			env:      map[string]string{"OTHERCONTROLLER_CONCURRENT_RECONCILES": "20"},
+			expected: 5,
+		},
+		{
+			name:     "invalid setting",
+			env:      map[string]string{"TESTCONTROLLER_CONCURRENT_RECONCILES": "0"},
+			expected: 5,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			setEnv(t, tc.env)
+			assert.Equal(t, tc.expected, GetConcurrentReconciles("testController"), "unexpected concurrent reconciles")
+		})
+	}
+}
+
+func TestSetClientRateLimits(t *testing.T) {
+	setEnv(t, map[string]string{
+		constants.ControllerConfigEnvVar("testController", constants.ClientQPSEnvVarSuffix):   "50",
+		constants.ControllerConfigEnvVar("testController", constants.ClientBurstEnvVarSuffix): "100",
+	})
+	cfg := &rest.Config{}
+	setClientRateLimits(cfg, "testController")
+	assert.Equal(t, float32(50), cfg.QPS, "unexpected QPS")
+	assert.Equal(t, 100, cfg.Burst, "unexpected burst")
+
+	cfg = &rest.Config{}
+	setClientRateLimits(cfg, "otherController")
+	assert.Zero(t, cfg.QPS, "unexpected QPS")
+	assert.Zero(t, cfg.Burst, "unexpected burst")
+}
+
+// fakeController has a work queue like the controllers of controller-runtime.
+type fakeController struct {
+	Queue workqueue.RateLimitingInterface
+}
+
+func TestSetQueueRateLimiter(t *testing.T) {
+	setEnv(t, map[string]string{
+		constants.ControllerConfigEnvVar("testController", constants.QueueQPSEnvVarSuffix):   "1",
+		constants.ControllerConfigEnvVar("testController", constants.QueueBurstEnvVarSuffix): "1",
+	})
+	queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer queue.ShutDown()
+	c := &fakeController{Queue: queue}
+	if err := setQueueRateLimiter(c, "testController"); !assert.NoError(t, err, "unexpected error") {
+		return
+	}
+	limited, ok := c.Queue.(*rateLimitedQueue)
+	if !assert.True(t, ok, "expected rate limited queue") {
+		return
+	}
+	assert.Equal(t, queue, limited.RateLimitingInterface, "expected original queue to be kept")
+	assert.Equal(t, 5*time.Millisecond, limited.rateLimiter.When("first"), "expected first item to be delayed by the item backoff only")
+	assert.InDelta(t, float64(time.Second), float64(limited.rateLimiter.When("second")), float64(100*time.Millisecond), "expected second item to be delayed by the QPS")
+}
+
+func TestSetQueueRateLimiterNotConfigured(t *testing.T) {
+	queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer queue.ShutDown()
+	c := &fakeController{Queue: queue}
+	assert.NoError(t, setQueueRateLimiter(c, "testController"), "unexpected error")
+	assert.Equal(t, queue, c.Queue, "expected queue to be unchanged")
+}
+
+// fakeManager is a manager with which controllers can be created but not started.
+type fakeManager struct {
+	manager.Manager
+}
+
+func (m *fakeManager) SetFields(interface{}) error                     { return nil }
+func (m *fakeManager) Add(manager.Runnable) error                      { return nil }
+func (m *fakeManager) GetCache() cache.Cache                           { return nil }
+func (m *fakeManager) GetConfig() *rest.Config                         { return &rest.Config{} }
+func (m *fakeManager) GetScheme() *runtime.Scheme                      { return runtime.NewScheme() }
+func (m *fakeManager) GetClient() client.Client                        { return nil }
+func (m *fakeManager) GetEventRecorderFor(string) record.EventRecorder { return nil }
+
+// TestSetQueueRateLimiterController checks that the rate limiter of the work queue of a controller created by the
+// vendored controller-runtime can be replaced. It fails if controller-runtime no longer exposes the work queue of
+// its controllers.
+func TestSetQueueRateLimiterController(t *testing.T) {
+	setEnv(t, map[string]string{
+		constants.ControllerConfigEnvVar("testController", constants.QueueQPSEnvVarSuffix): "1",
+	})
+	c, err := controller.New("test-controller", &fakeManager{}, controller.Options{
+		Reconciler: reconcile.Func(func(reconcile.Request) (reconcile.Result, error) { return reconcile.Result{}, nil }),
+	})
+	require.NoError(t, err, "unexpected error creating controller")
+	require.NoError(t, SetQueueRateLimiter(c, "testController"), "unexpected error setting rate limiter")
+	queue := reflect.ValueOf(c).Elem().FieldByName("Queue").Interface()
+	limited, ok := queue.(*rateLimitedQueue)
+	if assert.True(t, ok, "expected rate limited queue") {
+		limited.ShutDown()
+	}
+}
+
+func setEnv(t *testing.T, env map[string]string) {
+	for name, value := range env {
+		os.Setenv(name, value)
+	}
+	t.Cleanup(func() {
+		for name := range env {
+			os.Unsetenv(name)
+		}
+	})
+}
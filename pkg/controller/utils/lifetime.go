@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const (
+	// expiryRequeueDelay is how long after an expiry time objects are requeued, to allow for clock skew.
+	expiryRequeueDelay = 60 * time.Second
+)
+
+// IsExpired returns true if the lifetime beginning at the start time has elapsed, along with the time at which
+// the lifetime expires.
+func IsExpired(start time.Time, lifetime time.Duration) (bool, time.Time) {
+	expiry := start.Add(lifetime)
+	return time.Now().After(expiry), expiry
+}
+
+// RequeueAtExpiry updates the reconcile result so that the object is reconciled again just after the expiry time.
+// The result is left alone if it already requests an earlier requeue. Returns true if the result was changed.
+func RequeueAtExpiry(result *reconcile.Result, expiry time.Time) bool {
+	if result.Requeue && result.RequeueAfter <= 0 {
+		// Already requeueing immediately.
+		return false
+	}
+	requeueAfter := time.Until(expiry) + expiryRequeueDelay
+	if requeueAfter < result.RequeueAfter || result.RequeueAfter <= 0 {
+		result.RequeueAfter = requeueAfter
+		return true
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+)
+
+// maintenanceWindowStartTimeLayout is the layout of the start time of a maintenance window.
+const maintenanceWindowStartTimeLayout = "15:04"
+
+// ParseMaintenanceWindowDay parses a day of the week of a maintenance window, such as Saturday.
+func ParseMaintenanceWindowDay(day string) (time.Weekday, error) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.EqualFold(day, d.String()) {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid day of the week %q", day)
+}
+
+// ParseMaintenanceWindowStartTime parses the start time of a maintenance window, returning the offset of the start
+// time from midnight.
+func ParseMaintenanceWindowStartTime(startTime string) (time.Duration, error) {
+	t, err := time.Parse(maintenanceWindowStartTimeLayout, startTime)
+	if err != nil {
+		return 0, fmt.Errorf("invalid start time %q, expected HH:MM", startTime)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// InMaintenanceWindow returns whether the given time is within one of the maintenance windows, or when the next
+// maintenance window starts otherwise. A time is within the maintenance windows when there are no windows. Invalid
+// windows are ignored.
+func InMaintenanceWindow(windows []hivev1.MaintenanceWindow, now time.Time) (bool, time.Time) {
+	if len(windows) == 0 {
+		return true, time.Time{}
+	}
+	now = now.UTC()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	var next time.Time
+	for _, window := range windows {
+		offset, err := ParseMaintenanceWindowStartTime(window.StartTime)
+		if err != nil || window.Duration.Duration <= 0 {
+			continue
+		}
+		days := map[time.Weekday]bool{}
+		for _, day := range window.Days {
+			if d, err := ParseMaintenanceWindowDay(day); err == nil {
+				days[d] = true
+			}
+		}
+		if len(window.Days) > 0 && len(days) == 0 {
+			continue
+		}
+		// Windows that started up to a week ago may still be open, and one starts within the next week.
+		for i := -7; i <= 7; i++ {
+			start := midnight.AddDate(0, 0, i).Add(offset)
+			if len(days) > 0 && !days[start.Weekday()] {
+				continue
+			}
+			if !now.Before(start) && now.Before(start.Add(window.Duration.Duration)) {
+				return true, time.Time{}
+			}
+			if start.After(now) && (next.IsZero() || start.Before(next)) {
+				next = start
+			}
+		}
+	}
+	return false, next
+}
@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+)
+
+func TestInMaintenanceWindow(t *testing.T) {
+	// Saturday 4 July 2020, 12:00 UTC
+	saturdayNoon := time.Date(2020, time.July, 4, 12, 0, 0, 0, time.UTC)
+	window := func(startTime string, duration time.Duration, days ...string) hivev1.MaintenanceWindow {
+		return hivev1.MaintenanceWindow{Days: days, StartTime: startTime, Duration: metav1.Duration{Duration: duration}}
+	}
+	cases := []struct {
+		name         string
+		windows      []hivev1.MaintenanceWindow
+		now          time.Time
+		expectedOpen bool
+		expectedNext time.Time
+	}{
+		{
+			name:         "no windows",
+			now:          saturdayNoon,
+			expectedOpen: true,
+		},
+		{
+			name:         "daily window open",
+			windows:      []hivev1.MaintenanceWindow{window("11:30", time.Hour)},
+			now:          saturdayNoon,
+			expectedOpen: true,
+		},
+		{
+			name:         "daily window closed",
+			windows:      []hivev1.MaintenanceWindow{window("13:00", time.Hour)},
+			now:          saturdayNoon,
+			expectedNext: time.Date(2020, time.July, 4, 13, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "daily window ended",
+			windows:      []hivev1.MaintenanceWindow{window("10:00", 2*time.Hour)},
+			now:          saturdayNoon,
+			expectedNext: time.Date(2020, time.July, 5, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "window open since previous day",
+			windows:      []hivev1.MaintenanceWindow{window("22:00", 16*time.Hour, "friday")},
+			now:          saturdayNoon,
+			expectedOpen: true,
+		},
+		{
+			name:         "weekly window next week",
+			windows:      []hivev1.MaintenanceWindow{window("02:00", 4*time.Hour, "Saturday")},
+			now:          saturdayNoon,
+			expectedNext: time.Date(2020, time.July, 11, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "earliest of several windows",
+			windows: []hivev1.MaintenanceWindow{
+				window("02:00", 4*time.Hour, "Tuesday"),
+				window("20:00", 4*time.Hour, "Sunday", "Monday"),
+			},
+			now:          saturdayNoon,
+			expectedNext: time.Date(2020, time.July, 5, 20, 0, 0, 0, time.UTC),
+		},
+		{
+			name:         "time zone of now",
+			windows:      []hivev1.MaintenanceWindow{window("11:30", time.Hour)},
+			now:          saturdayNoon.In(time.FixedZone("UTC+2", 2*3600)),
+			expectedOpen: true,
+		},
+		{
+			name:    "invalid window ignored",
+			windows: []hivev1.MaintenanceWindow{window("noon", time.Hour), window("13:00", time.Hour, "Caturday")},
+			now:     saturdayNoon,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			open, next := InMaintenanceWindow(tc.windows, tc.now)
+			assert.Equal(t, tc.expectedOpen, open, "unexpected open window")
+			assert.True(t, tc.expectedNext.Equal(next), "unexpected next window: expected %v, got %v", tc.expectedNext, next)
+		})
+	}
+}
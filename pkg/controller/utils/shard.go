@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"fmt"
+	"hash/fnv"
+	"os"
+	"strconv"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openshift/hive/pkg/constants"
+)
+
+// Shard identifies the shard of the hive controllers run by the controller manager. Resources are assigned to shards
+// by the hash of their namespace, so that a ClusterDeployment and the resources depending on it, which share its
+// namespace, are reconciled by the same shard.
+type Shard struct {
+	// Index is the index of the shard, from 0 to Count-1.
+	Index int
+	// Count is the number of shards the hive controllers are split into.
+	Count int
+}
+
+var (
+	shard     Shard
+	shardOnce sync.Once
+)
+
+// GetShard returns the shard of the hive controllers run by the controller manager, as set in the environment by the
+// operator. The controller manager runs all namespaces in a single shard when the environment is not set.
+func GetShard() Shard {
+	shardOnce.Do(func() {
+		var err error
+		shard, err = shardFromEnv()
+		if err != nil {
+			log.WithError(err).Fatal("invalid controllers shard")
+		}
+	})
+	return shard
+}
+
+func shardFromEnv() (Shard, error) {
+	countValue := os.Getenv(constants.ControllersShardCountEnvVar)
+	if countValue == "" {
+		return Shard{Index: 0, Count: 1}, nil
+	}
+	count, err := strconv.Atoi(countValue)
+	if err != nil || count < 1 {
+		return Shard{}, fmt.Errorf("invalid %s: %q", constants.ControllersShardCountEnvVar, countValue)
+	}
+	indexValue := os.Getenv(constants.ControllersShardEnvVar)
+	index, err := strconv.Atoi(indexValue)
+	if err != nil || index < 0 || index >= count {
+		return Shard{}, fmt.Errorf("invalid %s: %q", constants.ControllersShardEnvVar, indexValue)
+	}
+	return Shard{Index: index, Count: count}, nil
+}
+
+// Sharded returns whether the hive controllers are split into more than one shard.
+func (s Shard) Sharded() bool {
+	return s.Count > 1
+}
+
+// Owns returns whether resources in the given namespace are reconciled by the shard. Cluster-scoped resources are
+// reconciled by the first shard.
+func (s Shard) Owns(namespace string) bool {
+	if !s.Sharded() {
+		return true
+	}
+	if namespace == "" {
+		return s.Index == 0
+	}
+	return ShardForNamespace(namespace, s.Count) == s.Index
+}
+
+// ShardForNamespace returns the index of the shard reconciling resources in the given namespace when the hive
+// controllers are split into the given number of shards.
+func ShardForNamespace(namespace string, count int) int {
+	h := fnv.New32a()
+	h.Write([]byte(namespace))
+	return int(h.Sum32() % uint32(count))
+}
+
+// NewShardedReconciler wraps the given reconciler so that it only reconciles requests for resources owned by the
+// shard of the controller manager. Requests for resources owned by other shards are dropped. The informers of every
+// shard still list and watch all namespaces: the API server cannot select objects by the hash of their namespace, and
+// the cache of the vendored controller-runtime can only be restricted to a single namespace.
+func NewShardedReconciler(r reconcile.Reconciler) reconcile.Reconciler {
+	s := GetShard()
+	if !s.Sharded() {
+		return r
+	}
+	return &shardedReconciler{Reconciler: r, shard: s}
+}
+
+type shardedReconciler struct {
+	reconcile.Reconciler
+	shard Shard
+}
+
+func (r *shardedReconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	if !r.shard.Owns(request.Namespace) {
+		return reconcile.Result{}, nil
+	}
+	return r.Reconciler.Reconcile(request)
+}
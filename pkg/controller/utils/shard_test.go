@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/openshift/hive/pkg/constants"
+)
+
+func TestShardFromEnv(t *testing.T) {
+	cases := []struct {
+		name          string
+		shard         string
+		count         string
+		expectedShard Shard
+		expectErr     bool
+	}{
+		{
+			name:          "unsharded",
+			expectedShard: Shard{Index: 0, Count: 1},
+		},
+		{
+			name:          "sharded",
+			shard:         "2",
+			count:         "3",
+			expectedShard: Shard{Index: 2, Count: 3},
+		},
+		{
+			name:      "shard out of range",
+			shard:     "3",
+			count:     "3",
+			expectErr: true,
+		},
+		{
+			name:      "missing shard",
+			count:     "3",
+			expectErr: true,
+		},
+		{
+			name:      "invalid count",
+			shard:     "0",
+			count:     "zero",
+			expectErr: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			os.Setenv(constants.ControllersShardEnvVar, tc.shard)
+			os.Setenv(constants.ControllersShardCountEnvVar, tc.count)
+			defer os.Unsetenv(constants.ControllersShardEnvVar)
+			defer os.Unsetenv(constants.ControllersShardCountEnvVar)
+			shard, err := shardFromEnv()
+			if tc.expectErr {
+				assert.Error(t, err, "expected error")
+				return
+			}
+			require.NoError(t, err, "unexpected error")
+			assert.Equal(t, tc.expectedShard, shard, "unexpected shard")
+		})
+	}
+}
+
+func TestShardOwns(t *testing.T) {
+	namespaces := make([]string, 100)
+	for i := range namespaces {
+		namespaces[i] = fmt.Sprintf("namespace-%d", i)
+	}
+	shards := []Shard{{Index: 0, Count: 3}, {Index: 1, Count: 3}, {Index: 2, Count: 3}}
+	for _, namespace := range namespaces {
+		owners := 0
+		for _, shard := range shards {
+			if shard.Owns(namespace) {
+				owners++
+			}
+		}
+		assert.Equal(t, 1, owners, "expected namespace %s to be owned by exactly one shard", namespace)
+	}
+	for _, shard := range shards {
+		assert.Equal(t, shard.Index == 0, shard.Owns(""), "expected cluster-scoped resources to be owned by the first shard")
+	}
+	assert.True(t, Shard{Index: 0, Count: 1}.Owns("namespace-0"), "expected single shard to own all namespaces")
+}
+
+type countingReconciler struct {
+	requests []reconcile.Request
+}
+
+func (r *countingReconciler) Reconcile(request reconcile.Request) (reconcile.Result, error) {
+	r.requests = append(r.requests, request)
+	return reconcile.Result{}, nil
+}
+
+func TestShardedReconciler(t *testing.T) {
+	shard := Shard{Index: 1, Count: 2}
+	r := &countingReconciler{}
+	sharded := &shardedReconciler{Reconciler: r, shard: shard}
+	var expected []reconcile.Request
+	for i := 0; i < 10; i++ {
+		request := reconcile.Request{}
+		request.Namespace = fmt.Sprintf("namespace-%d", i)
+		request.Name = "test"
+		if shard.Owns(request.Namespace) {
+			expected = append(expected, request)
+		}
+		_, err := sharded.Reconcile(request)
+		require.NoError(t, err, "unexpected error")
+	}
+	assert.NotEmpty(t, expected, "expected some namespaces in the shard")
+	assert.Equal(t, expected, r.requests, "unexpected reconciled requests")
+}
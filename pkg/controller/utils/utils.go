@@ -43,17 +43,6 @@ func DeleteFinalizer(object metav1.Object, finalizer string) {
 	object.SetFinalizers(finalizers.List())
 }
 
-const (
-	concurrentControllerReconciles = 5
-)
-
-// GetConcurrentReconciles returns the number of goroutines each controller should
-// use for parallel processing of their queue. For now this is a static value of 5.
-// In future this may be read from an env var set by the operator, and driven by HiveConfig.
-func GetConcurrentReconciles() int {
-	return concurrentControllerReconciles
-}
-
 // MergeJsons will merge the global and local pull secret and return it
 func MergeJsons(globalPullSecret string, localPullSecret string, cdLog log.FieldLogger) (string, error) {
 
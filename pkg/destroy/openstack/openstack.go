@@ -0,0 +1,437 @@
+// Package openstack destroys the resources of OpenStack clusters. The installer version vendored by Hive does not
+// provide an OpenStack destroyer, so the resources created by the installer for a cluster are destroyed here by the
+// cluster ID with which the installer tags them.
+package openstack
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gophercloud/gophercloud"
+	"github.com/gophercloud/gophercloud/openstack/blockstorage/v2/volumes"
+	"github.com/gophercloud/gophercloud/openstack/compute/v2/servers"
+	"github.com/gophercloud/gophercloud/openstack/imageservice/v2/images"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/floatingips"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/layer3/routers"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/extensions/security/groups"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/networks"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/ports"
+	"github.com/gophercloud/gophercloud/openstack/networking/v2/subnets"
+	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/containers"
+	"github.com/gophercloud/gophercloud/openstack/objectstorage/v1/objects"
+	"github.com/gophercloud/utils/openstack/clientconfig"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	"github.com/openshift/installer/pkg/destroy/providers"
+	"github.com/openshift/installer/pkg/types"
+)
+
+const (
+	// cinderCSIClusterMetadataKey is the metadata key with which the Cinder CSI driver tags the volumes it creates
+	// with the cluster ID.
+	cinderCSIClusterMetadataKey = "cinder.csi.openstack.org/cluster"
+
+	// routerInterfaceDeviceOwner is the device owner of the ports attaching subnets to routers.
+	routerInterfaceDeviceOwner = "network:router_interface"
+
+	pollInterval = 10 * time.Second
+)
+
+// ClusterUninstaller holds the various options for the cluster we want to delete.
+type ClusterUninstaller struct {
+	// Cloud is the cloud entry in clouds.yaml with the credentials for the cloud.
+	Cloud string
+	// Filter are the key/value pairs identifying the resources of the cluster.
+	Filter map[string]string
+	// InfraID is the infrastructure ID of the cluster.
+	InfraID string
+	Logger  logrus.FieldLogger
+
+	// pollInterval is the interval between passes over the remaining resources.
+	pollInterval time.Duration
+}
+
+// New returns an OpenStack destroyer from ClusterMetadata.
+func New(logger logrus.FieldLogger, metadata *types.ClusterMetadata) (providers.Destroyer, error) {
+	if metadata.OpenStack == nil {
+		return nil, errors.New("no OpenStack metadata for cluster")
+	}
+	// Every resource of the tenant would match an empty filter.
+	if len(metadata.OpenStack.Identifier) == 0 {
+		return nil, errors.New("no identifier of the resources of the cluster in the OpenStack metadata")
+	}
+	return &ClusterUninstaller{
+		Cloud:        metadata.OpenStack.Cloud,
+		Filter:       metadata.OpenStack.Identifier,
+		InfraID:      metadata.InfraID,
+		Logger:       logger,
+		pollInterval: pollInterval,
+	}, nil
+}
+
+// deleteFunc deletes the resources of a kind, and returns true once no resources of the kind are left.
+type deleteFunc func(o *ClusterUninstaller, logger logrus.FieldLogger) (bool, error)
+
+// Run is the entrypoint to start the uninstall process. Resources are deleted in passes until none are left, since
+// resources cannot be deleted while resources depending on them still exist.
+func (o *ClusterUninstaller) Run() error {
+	if len(o.Filter) == 0 {
+		return errors.New("refusing to destroy resources without a filter identifying the cluster")
+	}
+	deleteFuncs := []struct {
+		name    string
+		execute deleteFunc
+	}{
+		{name: "servers", execute: deleteServers},
+		{name: "floating IPs", execute: deleteFloatingIPs},
+		{name: "routers", execute: deleteRouters},
+		{name: "ports", execute: deletePorts},
+		{name: "security groups", execute: deleteSecurityGroups},
+		{name: "subnets", execute: deleteSubnets},
+		{name: "networks", execute: deleteNetworks},
+		{name: "volumes", execute: deleteVolumes},
+		{name: "images", execute: deleteImages},
+		{name: "containers", execute: deleteContainers},
+	}
+	return wait.PollImmediateInfinite(o.pollInterval, func() (bool, error) {
+		remaining := deleteFuncs[:0]
+		for _, f := range deleteFuncs {
+			logger := o.Logger.WithField("resources", f.name)
+			done, err := f.execute(o, logger)
+			if err != nil {
+				logger.WithError(err).Debug("error deleting resources, will retry")
+			}
+			if !done {
+				remaining = append(remaining, f)
+			}
+		}
+		deleteFuncs = remaining
+		return len(deleteFuncs) == 0, nil
+	})
+}
+
+func (o *ClusterUninstaller) serviceClient(service string) (*gophercloud.ServiceClient, error) {
+	return clientconfig.NewServiceClient(service, &clientconfig.ClientOpts{Cloud: o.Cloud})
+}
+
+// tagList returns the tags with which the installer tags the resources of the cluster, one for every key/value pair
+// of the filter.
+func (o *ClusterUninstaller) tagList() []string {
+	tags := make([]string, 0, len(o.Filter))
+	for k, v := range o.Filter {
+		tags = append(tags, fmt.Sprintf("%s=%s", k, v))
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// tags returns the Neutron tags query matching the resources tagged with all the tags of the cluster.
+func (o *ClusterUninstaller) tags() string {
+	return strings.Join(o.tagList(), ",")
+}
+
+// matchesFilter returns true if the metadata contains all the key/value pairs of the filter. Keys are compared
+// case-insensitively, since Swift normalizes the case of metadata keys. Nothing matches an empty filter.
+func (o *ClusterUninstaller) matchesFilter(metadata map[string]string) bool {
+	if len(o.Filter) == 0 {
+		return false
+	}
+	for k, v := range o.Filter {
+		found := false
+		for mk, mv := range metadata {
+			if strings.EqualFold(k, mk) && mv == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func deleteServers(o *ClusterUninstaller, logger logrus.FieldLogger) (bool, error) {
+	client, err := o.serviceClient("compute")
+	if err != nil {
+		return false, err
+	}
+	allPages, err := servers.List(client, servers.ListOpts{}).AllPages()
+	if err != nil {
+		return false, err
+	}
+	allServers, err := servers.ExtractServers(allPages)
+	if err != nil {
+		return false, err
+	}
+	done := true
+	for _, server := range allServers {
+		if !o.matchesFilter(server.Metadata) {
+			continue
+		}
+		// Servers are deleted asynchronously, so they are only gone once they are no longer listed.
+		done = false
+		logger.WithField("server", server.Name).Info("deleting server")
+		if err := servers.Delete(client, server.ID).ExtractErr(); err != nil && !isNotFound(err) {
+			return false, err
+		}
+	}
+	return done, nil
+}
+
+func deleteFloatingIPs(o *ClusterUninstaller, logger logrus.FieldLogger) (bool, error) {
+	client, err := o.serviceClient("network")
+	if err != nil {
+		return false, err
+	}
+	allPages, err := floatingips.List(client, floatingips.ListOpts{Tags: o.tags()}).AllPages()
+	if err != nil {
+		return false, err
+	}
+	allFloatingIPs, err := floatingips.ExtractFloatingIPs(allPages)
+	if err != nil {
+		return false, err
+	}
+	for _, fip := range allFloatingIPs {
+		logger.WithField("floatingIP", fip.FloatingIP).Info("deleting floating IP")
+		if err := floatingips.Delete(client, fip.ID).ExtractErr(); err != nil && !isNotFound(err) {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func deleteRouters(o *ClusterUninstaller, logger logrus.FieldLogger) (bool, error) {
+	client, err := o.serviceClient("network")
+	if err != nil {
+		return false, err
+	}
+	allPages, err := routers.List(client, routers.ListOpts{Tags: o.tags()}).AllPages()
+	if err != nil {
+		return false, err
+	}
+	allRouters, err := routers.ExtractRouters(allPages)
+	if err != nil {
+		return false, err
+	}
+	for _, router := range allRouters {
+		routerLogger := logger.WithField("router", router.Name)
+		// The subnets attached to the router have to be detached before the router can be deleted.
+		portPages, err := ports.List(client, ports.ListOpts{DeviceID: router.ID, DeviceOwner: routerInterfaceDeviceOwner}).AllPages()
+		if err != nil {
+			return false, err
+		}
+		interfaces, err := ports.ExtractPorts(portPages)
+		if err != nil {
+			return false, err
+		}
+		for _, port := range interfaces {
+			routerLogger.WithField("port", port.ID).Info("removing router interface")
+			if _, err := routers.RemoveInterface(client, router.ID, routers.RemoveInterfaceOpts{PortID: port.ID}).Extract(); err != nil && !isNotFound(err) {
+				return false, err
+			}
+		}
+		routerLogger.Info("deleting router")
+		if err := routers.Delete(client, router.ID).ExtractErr(); err != nil && !isNotFound(err) {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func deletePorts(o *ClusterUninstaller, logger logrus.FieldLogger) (bool, error) {
+	client, err := o.serviceClient("network")
+	if err != nil {
+		return false, err
+	}
+	allPages, err := ports.List(client, ports.ListOpts{Tags: o.tags()}).AllPages()
+	if err != nil {
+		return false, err
+	}
+	allPorts, err := ports.ExtractPorts(allPages)
+	if err != nil {
+		return false, err
+	}
+	for _, port := range allPorts {
+		logger.WithField("port", port.ID).Info("deleting port")
+		if err := ports.Delete(client, port.ID).ExtractErr(); err != nil && !isNotFound(err) {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func deleteSecurityGroups(o *ClusterUninstaller, logger logrus.FieldLogger) (bool, error) {
+	client, err := o.serviceClient("network")
+	if err != nil {
+		return false, err
+	}
+	allPages, err := groups.List(client, groups.ListOpts{Tags: o.tags()}).AllPages()
+	if err != nil {
+		return false, err
+	}
+	allGroups, err := groups.ExtractGroups(allPages)
+	if err != nil {
+		return false, err
+	}
+	for _, group := range allGroups {
+		logger.WithField("securityGroup", group.Name).Info("deleting security group")
+		if err := groups.Delete(client, group.ID).ExtractErr(); err != nil && !isNotFound(err) {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func deleteSubnets(o *ClusterUninstaller, logger logrus.FieldLogger) (bool, error) {
+	client, err := o.serviceClient("network")
+	if err != nil {
+		return false, err
+	}
+	allPages, err := subnets.List(client, subnets.ListOpts{Tags: o.tags()}).AllPages()
+	if err != nil {
+		return false, err
+	}
+	allSubnets, err := subnets.ExtractSubnets(allPages)
+	if err != nil {
+		return false, err
+	}
+	for _, subnet := range allSubnets {
+		logger.WithField("subnet", subnet.Name).Info("deleting subnet")
+		if err := subnets.Delete(client, subnet.ID).ExtractErr(); err != nil && !isNotFound(err) {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func deleteNetworks(o *ClusterUninstaller, logger logrus.FieldLogger) (bool, error) {
+	client, err := o.serviceClient("network")
+	if err != nil {
+		return false, err
+	}
+	allPages, err := networks.List(client, networks.ListOpts{Tags: o.tags()}).AllPages()
+	if err != nil {
+		return false, err
+	}
+	allNetworks, err := networks.ExtractNetworks(allPages)
+	if err != nil {
+		return false, err
+	}
+	for _, network := range allNetworks {
+		logger.WithField("network", network.Name).Info("deleting network")
+		if err := networks.Delete(client, network.ID).ExtractErr(); err != nil && !isNotFound(err) {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func deleteVolumes(o *ClusterUninstaller, logger logrus.FieldLogger) (bool, error) {
+	client, err := o.serviceClient("volume")
+	if err != nil {
+		return false, err
+	}
+	allPages, err := volumes.List(client, volumes.ListOpts{Metadata: map[string]string{cinderCSIClusterMetadataKey: o.InfraID}}).AllPages()
+	if err != nil {
+		return false, err
+	}
+	allVolumes, err := volumes.ExtractVolumes(allPages)
+	if err != nil {
+		return false, err
+	}
+	done := true
+	for _, volume := range allVolumes {
+		// Volumes are deleted asynchronously, so they are only gone once they are no longer listed.
+		done = false
+		logger.WithField("volume", volume.Name).Info("deleting volume")
+		if err := volumes.Delete(client, volume.ID, volumes.DeleteOpts{Cascade: true}).ExtractErr(); err != nil && !isNotFound(err) {
+			return false, err
+		}
+	}
+	return done, nil
+}
+
+func deleteImages(o *ClusterUninstaller, logger logrus.FieldLogger) (bool, error) {
+	client, err := o.serviceClient("image")
+	if err != nil {
+		return false, err
+	}
+	allPages, err := images.List(client, images.ListOpts{Tags: o.tagList()}).AllPages()
+	if err != nil {
+		return false, err
+	}
+	allImages, err := images.ExtractImages(allPages)
+	if err != nil {
+		return false, err
+	}
+	for _, image := range allImages {
+		logger.WithField("image", image.Name).Info("deleting image")
+		if err := images.Delete(client, image.ID).ExtractErr(); err != nil && !isNotFound(err) {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func deleteContainers(o *ClusterUninstaller, logger logrus.FieldLogger) (bool, error) {
+	client, err := o.serviceClient("object-store")
+	if err != nil {
+		// Object storage is optional in OpenStack clouds.
+		if _, ok := err.(*gophercloud.ErrEndpointNotFound); ok {
+			return true, nil
+		}
+		return false, err
+	}
+	allPages, err := containers.List(client, containers.ListOpts{}).AllPages()
+	if err != nil {
+		return false, err
+	}
+	allContainers, err := containers.ExtractNames(allPages)
+	if err != nil {
+		return false, err
+	}
+	for _, container := range allContainers {
+		metadata, err := containers.Get(client, container, nil).ExtractMetadata()
+		if err != nil {
+			if isNotFound(err) {
+				continue
+			}
+			return false, err
+		}
+		if !o.matchesFilter(metadata) {
+			continue
+		}
+		containerLogger := logger.WithField("container", container)
+		// Containers can only be deleted once they are empty.
+		objectPages, err := objects.List(client, container, objects.ListOpts{}).AllPages()
+		if err != nil {
+			return false, err
+		}
+		allObjects, err := objects.ExtractNames(objectPages)
+		if err != nil {
+			return false, err
+		}
+		for _, object := range allObjects {
+			containerLogger.WithField("object", object).Debug("deleting object")
+			if _, err := objects.Delete(client, container, object, nil).Extract(); err != nil && !isNotFound(err) {
+				return false, err
+			}
+		}
+		containerLogger.Info("deleting container")
+		if _, err := containers.Delete(client, container).Extract(); err != nil && !isNotFound(err) {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func isNotFound(err error) bool {
+	_, ok := err.(gophercloud.ErrDefault404)
+	return ok
+}
@@ -0,0 +1,86 @@
+package openstack
+
+import (
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/installer/pkg/destroy/providers"
+	"github.com/openshift/installer/pkg/types"
+	typesopenstack "github.com/openshift/installer/pkg/types/openstack"
+)
+
+func TestNew(t *testing.T) {
+	newDestroyer, ok := providers.Registry[typesopenstack.Name]
+	require.True(t, ok, "OpenStack destroyer not registered")
+	destroyer, err := newDestroyer(log.WithField("test", "TestNew"), &types.ClusterMetadata{
+		InfraID: "test-infra-id",
+		ClusterPlatformMetadata: types.ClusterPlatformMetadata{
+			OpenStack: &typesopenstack.Metadata{
+				Cloud:      "test-cloud",
+				Identifier: map[string]string{"openshiftClusterID": "test-infra-id"},
+			},
+		},
+	})
+	require.NoError(t, err, "unexpected error creating destroyer")
+	uninstaller, ok := destroyer.(*ClusterUninstaller)
+	require.True(t, ok, "unexpected destroyer type")
+	assert.Equal(t, "test-cloud", uninstaller.Cloud, "unexpected cloud")
+	assert.Equal(t, "openshiftClusterID=test-infra-id", uninstaller.tags(), "unexpected tags")
+
+	_, err = newDestroyer(log.WithField("test", "TestNew"), &types.ClusterMetadata{InfraID: "test-infra-id"})
+	assert.Error(t, err, "expected error without OpenStack metadata")
+
+	_, err = newDestroyer(log.WithField("test", "TestNew"), &types.ClusterMetadata{
+		InfraID: "test-infra-id",
+		ClusterPlatformMetadata: types.ClusterPlatformMetadata{
+			OpenStack: &typesopenstack.Metadata{Cloud: "test-cloud"},
+		},
+	})
+	assert.Error(t, err, "expected error without identifier")
+}
+
+func TestRunWithoutFilter(t *testing.T) {
+	o := &ClusterUninstaller{Logger: log.WithField("test", "TestRunWithoutFilter")}
+	assert.Error(t, o.Run(), "expected error destroying without filter")
+}
+
+func TestMatchesFilter(t *testing.T) {
+	cases := []struct {
+		name     string
+		metadata map[string]string
+		expected bool
+	}{
+		{
+			name:     "matching metadata",
+			metadata: map[string]string{"openshiftClusterID": "test-infra-id", "Name": "test-infra-id-master-0"},
+			expected: true,
+		},
+		{
+			name:     "normalized key",
+			metadata: map[string]string{"Openshiftclusterid": "test-infra-id"},
+			expected: true,
+		},
+		{
+			name:     "other cluster",
+			metadata: map[string]string{"openshiftClusterID": "other-infra-id"},
+		},
+		{
+			name: "no metadata",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			o := &ClusterUninstaller{Filter: map[string]string{"openshiftClusterID": "test-infra-id"}}
+			assert.Equal(t, tc.expected, o.matchesFilter(tc.metadata))
+		})
+	}
+}
+
+func TestMatchesEmptyFilter(t *testing.T) {
+	o := &ClusterUninstaller{}
+	assert.False(t, o.matchesFilter(map[string]string{"openshiftClusterID": "test-infra-id"}), "expected empty filter to match nothing")
+	assert.False(t, o.matchesFilter(nil), "expected empty filter to match nothing")
+}
@@ -0,0 +1,10 @@
+package openstack
+
+import (
+	"github.com/openshift/installer/pkg/destroy/providers"
+	typesopenstack "github.com/openshift/installer/pkg/types/openstack"
+)
+
+func init() {
+	providers.Registry[typesopenstack.Name] = New
+}
@@ -0,0 +1,10 @@
+package vsphere
+
+import (
+	"github.com/openshift/installer/pkg/destroy/providers"
+	typesvsphere "github.com/openshift/installer/pkg/types/vsphere"
+)
+
+func init() {
+	providers.Registry[typesvsphere.Name] = New
+}
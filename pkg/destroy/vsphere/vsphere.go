@@ -0,0 +1,220 @@
+// Package vsphere destroys the resources of vSphere clusters. The installer version vendored by Hive does not provide
+// a vSphere destroyer, so the virtual machines and folders that the installer tags for a cluster are destroyed here,
+// along with the tags and the tag category of the cluster.
+package vsphere
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vmware/govmomi/property"
+	"github.com/vmware/govmomi/vapi/rest"
+	"github.com/vmware/govmomi/vim25"
+	"github.com/vmware/govmomi/vim25/methods"
+	"github.com/vmware/govmomi/vim25/mo"
+	"github.com/vmware/govmomi/vim25/types"
+
+	"github.com/openshift/installer/pkg/destroy/providers"
+	installertypes "github.com/openshift/installer/pkg/types"
+	typesvsphere "github.com/openshift/installer/pkg/types/vsphere"
+)
+
+const (
+	categoryPath    = "/com/vmware/cis/tagging/category"
+	tagPath         = "/com/vmware/cis/tagging/tag"
+	associationPath = "/com/vmware/cis/tagging/tag-association"
+
+	virtualMachineType = "VirtualMachine"
+	folderType         = "Folder"
+)
+
+// ClusterUninstaller holds the various options for the cluster we want to delete.
+type ClusterUninstaller struct {
+	// VCenter is the domain name or IP address of the vCenter.
+	VCenter string
+	// Username is the name of the user to use to connect to the vCenter.
+	Username string
+	// Password is the password for the user to use to connect to the vCenter.
+	Password string
+	// InfraID is the infrastructure ID of the cluster, which is the name of the tag of its resources.
+	InfraID string
+	Logger  logrus.FieldLogger
+}
+
+// New returns a vSphere destroyer from ClusterMetadata.
+func New(logger logrus.FieldLogger, metadata *installertypes.ClusterMetadata) (providers.Destroyer, error) {
+	if metadata.VSphere == nil {
+		return nil, errors.New("no vSphere metadata for cluster")
+	}
+	return &ClusterUninstaller{
+		VCenter:  metadata.VSphere.VCenter,
+		Username: metadata.VSphere.Username,
+		Password: metadata.VSphere.Password,
+		InfraID:  metadata.InfraID,
+		Logger:   logger,
+	}, nil
+}
+
+// attachedObject is an object to which a tag is attached.
+type attachedObject struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// Run is the entrypoint to start the uninstall process.
+func (o *ClusterUninstaller) Run() error {
+	ctx := context.Background()
+	vimClient, restClient, err := typesvsphere.CreateVSphereClients(ctx, o.VCenter, o.Username, o.Password)
+	if err != nil {
+		return fmt.Errorf("failed to connect to vCenter %s: %v", o.VCenter, err)
+	}
+	defer restClient.Logout(ctx)
+
+	categoryID, err := o.findCategory(ctx, restClient)
+	if err != nil {
+		return err
+	}
+	if categoryID == "" {
+		o.Logger.Info("no tag category found for cluster, nothing to destroy")
+		return nil
+	}
+	var tagIDs []string
+	if err := restClient.Do(ctx, restClient.Resource(tagPath).WithID(categoryID).WithAction("list-tags-for-category").Request(http.MethodPost), &tagIDs); err != nil {
+		return fmt.Errorf("failed to list tags of category %s: %v", categoryID, err)
+	}
+
+	var vms, folders []types.ManagedObjectReference
+	for _, tagID := range tagIDs {
+		var objects []attachedObject
+		if err := restClient.Do(ctx, restClient.Resource(associationPath).WithID(tagID).WithAction("list-attached-objects").Request(http.MethodPost), &objects); err != nil {
+			return fmt.Errorf("failed to list objects attached to tag %s: %v", tagID, err)
+		}
+		for _, obj := range objects {
+			ref := types.ManagedObjectReference{Type: obj.Type, Value: obj.ID}
+			switch obj.Type {
+			case virtualMachineType:
+				vms = append(vms, ref)
+			case folderType:
+				folders = append(folders, ref)
+			default:
+				o.Logger.WithField("object", ref).Debug("skipping tagged object")
+			}
+		}
+	}
+
+	// Folders can only be destroyed once the virtual machines in them are gone.
+	for _, vm := range vms {
+		if err := o.destroyVirtualMachine(ctx, vimClient, vm); err != nil {
+			return err
+		}
+	}
+	for _, folder := range folders {
+		o.Logger.WithField("folder", folder.Value).Info("destroying folder")
+		if err := destroy(ctx, vimClient, folder); err != nil {
+			return fmt.Errorf("failed to destroy folder %s: %v", folder.Value, err)
+		}
+	}
+
+	for _, tagID := range tagIDs {
+		o.Logger.WithField("tag", tagID).Info("deleting tag")
+		if err := restClient.Do(ctx, restClient.Resource(tagPath).WithID(tagID).Request(http.MethodDelete), nil); err != nil {
+			return fmt.Errorf("failed to delete tag %s: %v", tagID, err)
+		}
+	}
+	o.Logger.WithField("category", categoryID).Info("deleting tag category")
+	if err := restClient.Do(ctx, restClient.Resource(categoryPath).WithID(categoryID).Request(http.MethodDelete), nil); err != nil {
+		return fmt.Errorf("failed to delete tag category %s: %v", categoryID, err)
+	}
+	return nil
+}
+
+// findCategory returns the ID of the tag category that the installer creates for the cluster, or an empty string if
+// there is no such category.
+func (o *ClusterUninstaller) findCategory(ctx context.Context, restClient *rest.Client) (string, error) {
+	name := categoryName(o.InfraID)
+	var categoryIDs []string
+	if err := restClient.Do(ctx, restClient.Resource(categoryPath).Request(http.MethodGet), &categoryIDs); err != nil {
+		return "", fmt.Errorf("failed to list tag categories: %v", err)
+	}
+	for _, id := range categoryIDs {
+		category := struct {
+			Name string `json:"name"`
+		}{}
+		if err := restClient.Do(ctx, restClient.Resource(categoryPath).WithID(id).Request(http.MethodGet), &category); err != nil {
+			return "", fmt.Errorf("failed to get tag category %s: %v", id, err)
+		}
+		if category.Name == name {
+			return id, nil
+		}
+	}
+	return "", nil
+}
+
+// destroyVirtualMachine powers off the virtual machine if it is running, and destroys it.
+func (o *ClusterUninstaller) destroyVirtualMachine(ctx context.Context, client *vim25.Client, ref types.ManagedObjectReference) error {
+	var vm mo.VirtualMachine
+	if err := property.DefaultCollector(client).RetrieveOne(ctx, ref, []string{"name", "runtime.powerState"}, &vm); err != nil {
+		return fmt.Errorf("failed to get virtual machine %s: %v", ref.Value, err)
+	}
+	logger := o.Logger.WithField("vm", vm.Name)
+	if vm.Runtime.PowerState == types.VirtualMachinePowerStatePoweredOn {
+		logger.Info("powering off virtual machine")
+		res, err := methods.PowerOffVM_Task(ctx, client, &types.PowerOffVM_Task{This: ref})
+		if err == nil {
+			err = waitForTask(ctx, client, res.Returnval)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to power off virtual machine %s: %v", vm.Name, err)
+		}
+	}
+	logger.Info("destroying virtual machine")
+	if err := destroy(ctx, client, ref); err != nil {
+		return fmt.Errorf("failed to destroy virtual machine %s: %v", vm.Name, err)
+	}
+	return nil
+}
+
+// destroy destroys a managed entity and waits for it to be gone.
+func destroy(ctx context.Context, client *vim25.Client, ref types.ManagedObjectReference) error {
+	res, err := methods.Destroy_Task(ctx, client, &types.Destroy_Task{This: ref})
+	if err != nil {
+		return err
+	}
+	return waitForTask(ctx, client, res.Returnval)
+}
+
+// waitForTask waits for the task to complete, and returns the error of the task if it failed.
+func waitForTask(ctx context.Context, client *vim25.Client, task types.ManagedObjectReference) error {
+	var taskErr error
+	err := property.Wait(ctx, property.DefaultCollector(client), task, []string{"info"}, func(changes []types.PropertyChange) bool {
+		for _, change := range changes {
+			info, ok := change.Val.(types.TaskInfo)
+			if !ok {
+				continue
+			}
+			switch info.State {
+			case types.TaskInfoStateSuccess:
+				return true
+			case types.TaskInfoStateError:
+				taskErr = errors.New("task failed")
+				if info.Error != nil {
+					taskErr = errors.New(info.Error.LocalizedMessage)
+				}
+				return true
+			}
+		}
+		return false
+	})
+	if err != nil {
+		return err
+	}
+	return taskErr
+}
+
+// categoryName returns the name of the tag category that the installer creates for the cluster.
+func categoryName(infraID string) string {
+	return "openshift-" + infraID
+}
@@ -0,0 +1,37 @@
+package vsphere
+
+import (
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/openshift/installer/pkg/destroy/providers"
+	"github.com/openshift/installer/pkg/types"
+	typesvsphere "github.com/openshift/installer/pkg/types/vsphere"
+)
+
+func TestNew(t *testing.T) {
+	newDestroyer, ok := providers.Registry[typesvsphere.Name]
+	require.True(t, ok, "vSphere destroyer not registered")
+	destroyer, err := newDestroyer(log.WithField("test", "TestNew"), &types.ClusterMetadata{
+		InfraID: "test-infra-id",
+		ClusterPlatformMetadata: types.ClusterPlatformMetadata{
+			VSphere: &typesvsphere.Metadata{
+				VCenter:  "vcenter.example.com",
+				Username: "test-user",
+				Password: "test-password",
+			},
+		},
+	})
+	require.NoError(t, err, "unexpected error creating destroyer")
+	uninstaller, ok := destroyer.(*ClusterUninstaller)
+	require.True(t, ok, "unexpected destroyer type")
+	assert.Equal(t, "vcenter.example.com", uninstaller.VCenter, "unexpected vCenter")
+	assert.Equal(t, "test-infra-id", uninstaller.InfraID, "unexpected infra ID")
+	assert.Equal(t, "openshift-test-infra-id", categoryName(uninstaller.InfraID), "unexpected tag category")
+
+	_, err = newDestroyer(log.WithField("test", "TestNew"), &types.ClusterMetadata{InfraID: "test-infra-id"})
+	assert.Error(t, err, "expected error without vSphere metadata")
+}
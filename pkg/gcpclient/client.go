@@ -3,6 +3,7 @@ package gcpclient
 import (
 	"context"
 	"io/ioutil"
+	"path"
 	"time"
 
 	"github.com/openshift/hive/pkg/constants"
@@ -39,6 +40,12 @@ type Client interface {
 	ListComputeZones(ListComputeZonesOptions) (*compute.ZoneList, error)
 
 	ListComputeImages(ListComputeImagesOptions) (*compute.ImageList, error)
+
+	ListComputeInstances(ListComputeInstancesOptions, func(*compute.InstanceAggregatedList) error) error
+
+	StopInstance(*compute.Instance) error
+
+	StartInstance(*compute.Instance) error
 }
 
 // ListManagedZonesOptions are the options for listing managed zones.
@@ -212,6 +219,34 @@ func (c *gcpClient) ListComputeImages(opts ListComputeImagesOptions) (*compute.I
 	return call.Do()
 }
 
+// ListComputeInstancesOptions are the options for listing compute instances.
+type ListComputeInstancesOptions struct {
+	Filter string
+}
+
+func (c *gcpClient) ListComputeInstances(opts ListComputeInstancesOptions, f func(*compute.InstanceAggregatedList) error) error {
+	ctx, cancel := contextWithTimeout(context.TODO())
+	defer cancel()
+
+	return c.computeClient.Instances.AggregatedList(c.projectName).Filter(opts.Filter).Pages(ctx, f)
+}
+
+func (c *gcpClient) StopInstance(instance *compute.Instance) error {
+	ctx, cancel := contextWithTimeout(context.TODO())
+	defer cancel()
+
+	_, err := c.computeClient.Instances.Stop(c.projectName, path.Base(instance.Zone), instance.Name).Context(ctx).Do()
+	return err
+}
+
+func (c *gcpClient) StartInstance(instance *compute.Instance) error {
+	ctx, cancel := contextWithTimeout(context.TODO())
+	defer cancel()
+
+	_, err := c.computeClient.Instances.Start(c.projectName, path.Base(instance.Zone), instance.Name).Context(ctx).Do()
+	return err
+}
+
 // NewClient creates our client wrapper object for interacting with GCP. The supplied byte slice contains the GCP creds.
 func NewClient(authJSON []byte) (Client, error) {
 	return newClient(authJSONPassthroughSource(authJSON))
@@ -383,12 +383,52 @@ func Convert_v1alpha1_ClusterImageSet_To_v1_ClusterImageSet(in *hiveapi.ClusterI
 	} else {
 		out.Spec.ReleaseImage = ""
 	}
+	out.Status.ReleaseImage = in.Status.ReleaseImage
+	out.Status.Version = in.Status.Version
+	out.Status.Channel = in.Status.Channel
+	out.Status.InstallerImage = in.Status.InstallerImage
+	out.Status.BareMetalInstallerImage = in.Status.BareMetalInstallerImage
+	out.Status.CLIImage = in.Status.CLIImage
+	if conds := in.Status.Conditions; conds != nil {
+		out.Status.Conditions = make([]hivev1.ClusterImageSetCondition, len(conds))
+		for i, inCond := range conds {
+			outCond := &out.Status.Conditions[i]
+			outCond.Type = hivev1.ClusterImageSetConditionType(inCond.Type)
+			outCond.Status = inCond.Status
+			outCond.LastProbeTime = inCond.LastProbeTime
+			outCond.LastTransitionTime = inCond.LastTransitionTime
+			outCond.Reason = inCond.Reason
+			outCond.Message = inCond.Message
+		}
+	} else {
+		out.Status.Conditions = nil
+	}
 	return nil
 }
 
 func Convert_v1_ClusterImageSet_To_v1alpha1_ClusterImageSet(in *hivev1.ClusterImageSet, out *hiveapi.ClusterImageSet, _ conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	out.Spec.ReleaseImage = pointer.StringPtr(in.Spec.ReleaseImage)
+	out.Status.ReleaseImage = in.Status.ReleaseImage
+	out.Status.Version = in.Status.Version
+	out.Status.Channel = in.Status.Channel
+	out.Status.InstallerImage = in.Status.InstallerImage
+	out.Status.BareMetalInstallerImage = in.Status.BareMetalInstallerImage
+	out.Status.CLIImage = in.Status.CLIImage
+	if conds := in.Status.Conditions; conds != nil {
+		out.Status.Conditions = make([]hiveapi.ClusterImageSetCondition, len(conds))
+		for i, inCond := range conds {
+			outCond := &out.Status.Conditions[i]
+			outCond.Type = hiveapi.ClusterImageSetConditionType(inCond.Type)
+			outCond.Status = inCond.Status
+			outCond.LastProbeTime = inCond.LastProbeTime
+			outCond.LastTransitionTime = inCond.LastTransitionTime
+			outCond.Reason = inCond.Reason
+			outCond.Message = inCond.Message
+		}
+	} else {
+		out.Status.Conditions = nil
+	}
 	return nil
 }
 
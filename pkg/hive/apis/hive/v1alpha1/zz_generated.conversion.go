@@ -260,6 +260,16 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddGeneratedConversionFunc((*v1alpha1.ClusterImageSetCondition)(nil), (*hive.ClusterImageSetCondition)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1alpha1_ClusterImageSetCondition_To_hive_ClusterImageSetCondition(a.(*v1alpha1.ClusterImageSetCondition), b.(*hive.ClusterImageSetCondition), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*hive.ClusterImageSetCondition)(nil), (*v1alpha1.ClusterImageSetCondition)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_hive_ClusterImageSetCondition_To_v1alpha1_ClusterImageSetCondition(a.(*hive.ClusterImageSetCondition), b.(*v1alpha1.ClusterImageSetCondition), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddGeneratedConversionFunc((*v1alpha1.ClusterImageSetList)(nil), (*hive.ClusterImageSetList)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1alpha1_ClusterImageSetList_To_hive_ClusterImageSetList(a.(*v1alpha1.ClusterImageSetList), b.(*hive.ClusterImageSetList), scope)
 	}); err != nil {
@@ -1589,6 +1599,36 @@ func Convert_hive_ClusterImageSet_To_v1alpha1_ClusterImageSet(in *hive.ClusterIm
 	return autoConvert_hive_ClusterImageSet_To_v1alpha1_ClusterImageSet(in, out, s)
 }
 
+func autoConvert_v1alpha1_ClusterImageSetCondition_To_hive_ClusterImageSetCondition(in *v1alpha1.ClusterImageSetCondition, out *hive.ClusterImageSetCondition, s conversion.Scope) error {
+	out.Type = hive.ClusterImageSetConditionType(in.Type)
+	out.Status = v1.ConditionStatus(in.Status)
+	out.LastProbeTime = in.LastProbeTime
+	out.LastTransitionTime = in.LastTransitionTime
+	out.Reason = in.Reason
+	out.Message = in.Message
+	return nil
+}
+
+// Convert_v1alpha1_ClusterImageSetCondition_To_hive_ClusterImageSetCondition is an autogenerated conversion function.
+func Convert_v1alpha1_ClusterImageSetCondition_To_hive_ClusterImageSetCondition(in *v1alpha1.ClusterImageSetCondition, out *hive.ClusterImageSetCondition, s conversion.Scope) error {
+	return autoConvert_v1alpha1_ClusterImageSetCondition_To_hive_ClusterImageSetCondition(in, out, s)
+}
+
+func autoConvert_hive_ClusterImageSetCondition_To_v1alpha1_ClusterImageSetCondition(in *hive.ClusterImageSetCondition, out *v1alpha1.ClusterImageSetCondition, s conversion.Scope) error {
+	out.Type = v1alpha1.ClusterImageSetConditionType(in.Type)
+	out.Status = v1.ConditionStatus(in.Status)
+	out.LastProbeTime = in.LastProbeTime
+	out.LastTransitionTime = in.LastTransitionTime
+	out.Reason = in.Reason
+	out.Message = in.Message
+	return nil
+}
+
+// Convert_hive_ClusterImageSetCondition_To_v1alpha1_ClusterImageSetCondition is an autogenerated conversion function.
+func Convert_hive_ClusterImageSetCondition_To_v1alpha1_ClusterImageSetCondition(in *hive.ClusterImageSetCondition, out *v1alpha1.ClusterImageSetCondition, s conversion.Scope) error {
+	return autoConvert_hive_ClusterImageSetCondition_To_v1alpha1_ClusterImageSetCondition(in, out, s)
+}
+
 func autoConvert_v1alpha1_ClusterImageSetList_To_hive_ClusterImageSetList(in *v1alpha1.ClusterImageSetList, out *hive.ClusterImageSetList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
 	out.Items = *(*[]hive.ClusterImageSet)(unsafe.Pointer(&in.Items))
@@ -1654,6 +1694,13 @@ func Convert_hive_ClusterImageSetSpec_To_v1alpha1_ClusterImageSetSpec(in *hive.C
 }
 
 func autoConvert_v1alpha1_ClusterImageSetStatus_To_hive_ClusterImageSetStatus(in *v1alpha1.ClusterImageSetStatus, out *hive.ClusterImageSetStatus, s conversion.Scope) error {
+	out.ReleaseImage = in.ReleaseImage
+	out.Version = in.Version
+	out.Channel = in.Channel
+	out.InstallerImage = in.InstallerImage
+	out.BareMetalInstallerImage = in.BareMetalInstallerImage
+	out.CLIImage = in.CLIImage
+	out.Conditions = *(*[]hive.ClusterImageSetCondition)(unsafe.Pointer(&in.Conditions))
 	return nil
 }
 
@@ -1663,6 +1710,13 @@ func Convert_v1alpha1_ClusterImageSetStatus_To_hive_ClusterImageSetStatus(in *v1
 }
 
 func autoConvert_hive_ClusterImageSetStatus_To_v1alpha1_ClusterImageSetStatus(in *hive.ClusterImageSetStatus, out *v1alpha1.ClusterImageSetStatus, s conversion.Scope) error {
+	out.ReleaseImage = in.ReleaseImage
+	out.Version = in.Version
+	out.Channel = in.Channel
+	out.InstallerImage = in.InstallerImage
+	out.BareMetalInstallerImage = in.BareMetalInstallerImage
+	out.CLIImage = in.CLIImage
+	out.Conditions = *(*[]v1alpha1.ClusterImageSetCondition)(unsafe.Pointer(&in.Conditions))
 	return nil
 }
 
@@ -45,6 +45,21 @@ else
   exit 1
 fi
 
+echo "1" > /common/success
+exit 0
+`
+	// releaseInfoScript is a minimal shell script we run in the cluster image set job to record the metadata of
+	// the release image of a ClusterImageSet. The global pull secret is used when there is one.
+	releaseInfoScript = `#/bin/bash
+echo "About to run oc adm release info"
+if oc adm release info --output=json ${PULL_SECRET:+--registry-config "${PULL_SECRET}"} "${RELEASE_IMAGE}" > /common/release-info.json 2> /common/error.log; then
+  echo "release info resolved successfully"
+else
+  echo "release info resolution failed"
+  echo "0" > /common/success
+  exit 1
+fi
+
 echo "1" > /common/success
 exit 0
 `
@@ -186,6 +201,126 @@ func GenerateImageSetJob(cd *hivev1.ClusterDeployment, releaseImage, serviceAcco
 	return job
 }
 
+// GenerateClusterImageSetJob creates a job to resolve the metadata of the release image of a ClusterImageSet. The
+// job records the metadata in the status of the ClusterImageSet. It runs in the given namespace, with the pull
+// secret of the given name when there is one.
+func GenerateClusterImageSetJob(imageSet *hivev1.ClusterImageSet, namespace, serviceAccountName, pullSecretName string, cli ImageSpec) *batchv1.Job {
+	logger := log.WithField("clusterimageset", imageSet.Name)
+
+	logger.Debug("generating cluster image set job")
+
+	env := []corev1.EnvVar{
+		{
+			Name:  "RELEASE_IMAGE",
+			Value: imageSet.Spec.ReleaseImage,
+		},
+	}
+
+	volumes := []corev1.Volume{
+		{
+			Name: "common",
+			VolumeSource: corev1.VolumeSource{
+				EmptyDir: &corev1.EmptyDirVolumeSource{},
+			},
+		},
+	}
+
+	volumeMounts := []corev1.VolumeMount{
+		{
+			Name:      "common",
+			MountPath: "/common",
+		},
+	}
+
+	if pullSecretName != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  "PULL_SECRET",
+			Value: "/run/release-pull-secret/" + corev1.DockerConfigJsonKey,
+		})
+		volumes = append(volumes, corev1.Volume{
+			Name: "pullsecret",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: pullSecretName,
+				},
+			},
+		})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      "pullsecret",
+			MountPath: "/run/release-pull-secret",
+		})
+	}
+
+	containers := []corev1.Container{
+		{
+			Name:            "release",
+			Image:           cli.Image,
+			ImagePullPolicy: cli.PullPolicy,
+			Env:             env,
+			Command:         []string{"/bin/sh", "-c"},
+			Args:            []string{releaseInfoScript},
+			VolumeMounts:    volumeMounts,
+		},
+		{
+			Name:            "hiveutil",
+			Image:           images.GetHiveImage(),
+			ImagePullPolicy: images.GetHiveImagePullPolicy(),
+			Env:             env,
+			Command:         []string{"/usr/bin/hiveutil"},
+			Args: []string{
+				"update-cluster-image-set",
+				"--work-dir",
+				"/common",
+				"--log-level",
+				"debug",
+				"--cluster-image-set-name",
+				imageSet.Name,
+				"--release-image",
+				imageSet.Spec.ReleaseImage,
+			},
+			VolumeMounts: volumeMounts,
+		},
+	}
+
+	completions := int32(1)
+	deadline := int64((24 * time.Hour).Seconds())
+	backoffLimit := int32(123456)
+	labels := map[string]string{
+		ImagesetJobLabel:                   "true",
+		constants.ClusterImageSetNameLabel: imageSet.Name,
+		constants.JobTypeLabel:             constants.JobTypeImageSet,
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GetClusterImageSetJobName(imageSet.Name),
+			Namespace: namespace,
+			Labels:    labels,
+		},
+		Spec: batchv1.JobSpec{
+			Completions:           &completions,
+			ActiveDeadlineSeconds: &deadline,
+			BackoffLimit:          &backoffLimit,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: labels,
+				},
+				Spec: corev1.PodSpec{
+					RestartPolicy:      corev1.RestartPolicyOnFailure,
+					Containers:         containers,
+					Volumes:            volumes,
+					ServiceAccountName: serviceAccountName,
+				},
+			},
+		},
+	}
+}
+
+// GetClusterImageSetJobName returns the expected name of the job resolving the metadata of a ClusterImageSet.
+func GetClusterImageSetJobName(imageSetName string) string {
+	return apihelpers.GetResourceName(imageSetName, "release-info")
+}
+
 // GetImageSetJobName returns the expected name of the imageset job for a ClusterImageSet.
 func GetImageSetJobName(cdName string) string {
 	return apihelpers.GetResourceName(cdName, "imageset")
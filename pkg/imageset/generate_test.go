@@ -26,6 +26,45 @@ func TestGenerateImageSetJob(t *testing.T) {
 	validateJob(t, job)
 }
 
+func TestGenerateClusterImageSetJob(t *testing.T) {
+	job := GenerateClusterImageSetJob(testImageSet(), "hive", "test-service-account", "global-pull-secret", testCLIImageSpec)
+	if job.Name != GetClusterImageSetJobName(testImageSet().Name) {
+		t.Errorf("unexpected job name: %s", job.Name)
+	}
+	if job.Namespace != "hive" {
+		t.Errorf("unexpected job namespace: %s", job.Namespace)
+	}
+	if len(job.Spec.Template.Spec.Containers) != 2 {
+		t.Errorf("unexpected number of containers")
+	}
+	if !hasVariable(job, "RELEASE_IMAGE") {
+		t.Errorf("missing RELEASE_IMAGE environment variable")
+	}
+	if !hasVariable(job, "PULL_SECRET") {
+		t.Errorf("missing PULL_SECRET env var")
+	}
+	if !hasVolume(job, "pullsecret") {
+		t.Errorf("missing pull secret volume")
+	}
+}
+
+func TestGenerateClusterImageSetJobWithoutPullSecret(t *testing.T) {
+	job := GenerateClusterImageSetJob(testImageSet(), "hive", "test-service-account", "", testCLIImageSpec)
+	if !hasVolume(job, "common") {
+		t.Errorf("missing common volume")
+	}
+	if hasVolume(job, "pullsecret") {
+		t.Errorf("unexpected pull secret volume")
+	}
+	for _, c := range job.Spec.Template.Spec.Containers {
+		for _, e := range c.Env {
+			if e.Name == "PULL_SECRET" {
+				t.Errorf("unexpected PULL_SECRET env var in container %s", c.Name)
+			}
+		}
+	}
+}
+
 func testClusterDeployment() *hivev1.ClusterDeployment {
 	cd := &hivev1.ClusterDeployment{}
 	cd.Name = "test-cluster-deployment"
@@ -0,0 +1,284 @@
+package imageset
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/blang/semver"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+)
+
+const (
+	releaseImageResolvedReason         = "ReleaseImageResolved"
+	releaseImageResolvedMessage        = "The metadata of the release image is resolved."
+	releaseImageResolutionFailedReason = "ReleaseImageResolutionFailed"
+
+	// releaseChannelsMetadataKey is the key of the release metadata listing the upgrade graph channels of the release.
+	releaseChannelsMetadataKey = "io.openshift.upgrades.graph.release.channels"
+)
+
+// releaseInfo is the part of the output of 'oc adm release info --output=json' recorded in the status of a
+// ClusterImageSet.
+type releaseInfo struct {
+	Metadata *struct {
+		Version  string            `json:"version"`
+		Metadata map[string]string `json:"metadata"`
+	} `json:"metadata"`
+	References *struct {
+		Spec struct {
+			Tags []struct {
+				Name string `json:"name"`
+				From *struct {
+					Name string `json:"name"`
+				} `json:"from"`
+			} `json:"tags"`
+		} `json:"spec"`
+	} `json:"references"`
+}
+
+// imageFor returns the image of the release for the given component, or an empty string if the release has no such
+// component.
+func (r *releaseInfo) imageFor(name string) string {
+	if r.References == nil {
+		return ""
+	}
+	for _, tag := range r.References.Spec.Tags {
+		if tag.Name == name && tag.From != nil {
+			return tag.From.Name
+		}
+	}
+	return ""
+}
+
+// UpdateClusterImageSetOptions contains options for running the command to update the status of a cluster image set
+type UpdateClusterImageSetOptions struct {
+	ClusterImageSetName string
+	ReleaseImage        string
+	LogLevel            string
+	WorkDir             string
+	log                 log.FieldLogger
+	client              client.Client
+}
+
+// NewUpdateClusterImageSetCommand returns a command to update the status of a cluster image set with the metadata
+// of its release image.
+func NewUpdateClusterImageSetCommand() *cobra.Command {
+	opt := &UpdateClusterImageSetOptions{}
+	cmd := &cobra.Command{
+		Use:   "update-cluster-image-set OPTIONS",
+		Short: "Updates the status of a clusterimageset based on results from 'oc adm release info'",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := opt.Complete(); err != nil {
+				log.WithError(err).Error("cannot complete command")
+				return
+			}
+
+			if err := opt.Validate(); err != nil {
+				log.WithError(err).Error("invalid command options")
+				return
+			}
+
+			if err := opt.Run(); err != nil {
+				log.WithError(err).Fatal("runtime error")
+			}
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.StringVar(&opt.LogLevel, "log-level", "info", "log level, one of: debug, info, warn, error, fatal, panic")
+	flags.StringVar(&opt.WorkDir, "work-dir", "/common", "directory to use for all input and output")
+	flags.StringVar(&opt.ClusterImageSetName, "cluster-image-set-name", "", "name of ClusterImageSet to update")
+	flags.StringVar(&opt.ReleaseImage, "release-image", "", "release image that was resolved")
+	return cmd
+}
+
+// Complete sets remaining fields on the UpdateClusterImageSetOptions based on command options and arguments.
+func (o *UpdateClusterImageSetOptions) Complete() error {
+	level, err := log.ParseLevel(o.LogLevel)
+	if err != nil {
+		log.WithError(err).Error("cannot parse log level")
+		return err
+	}
+	logger := log.New()
+	logger.SetLevel(level)
+	logger.SetFormatter(&log.TextFormatter{FullTimestamp: true})
+	o.log = log.NewEntry(logger)
+
+	absPath, err := filepath.Abs(o.WorkDir)
+	if err != nil {
+		o.log.WithError(err).Error("error finding absolute workdir path")
+		return err
+	}
+	o.WorkDir = absPath
+	if _, err := os.Stat(o.WorkDir); err != nil {
+		o.log.WithError(err).WithField("workdir", o.WorkDir).Error("cannot access workdir")
+		return err
+	}
+
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	kubeconfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{})
+	cfg, err := kubeconfig.ClientConfig()
+	if err != nil {
+		log.WithError(err).Error("Cannot obtain client config")
+		return err
+	}
+	o.client, err = getClient(cfg)
+	if err != nil {
+		log.WithError(err).Error("Cannot obtain API client")
+		return err
+	}
+	return nil
+}
+
+// Validate ensures the given options and arguments are valid.
+func (o *UpdateClusterImageSetOptions) Validate() error {
+	if o.ClusterImageSetName == "" {
+		return fmt.Errorf("--cluster-image-set-name is required")
+	}
+	if o.ReleaseImage == "" {
+		return fmt.Errorf("--release-image is required")
+	}
+	return nil
+}
+
+// Run updates the status of the given ClusterImageSet based on the results of 'oc adm release info'.
+// This command does the following:
+// 1. Wait for the existence of ${workdir}/success
+// 2. Determine based on the contents of that file whether the command was successful or not
+// 3. If successful, obtain the release metadata from ${workdir}/release-info.json
+// 4. If failed, obtain error log from ${workdir}/error.log
+// 5. Update the status of the ClusterImageSet based on those results
+func (o *UpdateClusterImageSetOptions) Run() error {
+	success, err := waitForResult(o.WorkDir, o.log)
+	if err != nil {
+		return err
+	}
+
+	if !success {
+		o.log.Debug("the oc release info command failed")
+		errorLog, err := ioutil.ReadFile(path.Join(o.WorkDir, "error.log"))
+		if err != nil {
+			o.log.WithError(err).Error("could not read error log")
+			return err
+		}
+		o.log.Debugf("the contents of the error log: %s", errorLog)
+		return o.updateStatus(func(status *hivev1.ClusterImageSetStatus) {
+			o.setValidCondition(status, corev1.ConditionFalse, releaseImageResolutionFailedReason,
+				fmt.Sprintf("Failed to resolve release image: %s", strings.TrimSpace(string(errorLog))))
+		})
+	}
+
+	o.log.Debug("the oc release info command was successful")
+	releaseInfoBytes, err := ioutil.ReadFile(path.Join(o.WorkDir, "release-info.json"))
+	if err != nil {
+		o.log.WithError(err).Error("could not read release info")
+		return err
+	}
+	info := &releaseInfo{}
+	if err := json.Unmarshal(releaseInfoBytes, info); err != nil {
+		o.log.WithError(err).Error("could not parse release info")
+		return o.updateStatus(func(status *hivev1.ClusterImageSetStatus) {
+			o.setValidCondition(status, corev1.ConditionFalse, releaseImageResolutionFailedReason,
+				fmt.Sprintf("Failed to parse release info: %v", err))
+		})
+	}
+
+	installerImage := info.imageFor("installer")
+	cliImage := info.imageFor("cli")
+	var version string
+	var metadata map[string]string
+	if info.Metadata != nil {
+		version = info.Metadata.Version
+		metadata = info.Metadata.Metadata
+	}
+	if version == "" || installerImage == "" || cliImage == "" {
+		return o.updateStatus(func(status *hivev1.ClusterImageSetStatus) {
+			o.setValidCondition(status, corev1.ConditionFalse, releaseImageResolutionFailedReason,
+				"The release image does not specify a version, an installer image and a cli image")
+		})
+	}
+
+	return o.updateStatus(func(status *hivev1.ClusterImageSetStatus) {
+		status.Version = version
+		status.Channel = releaseChannel(version, metadata)
+		status.InstallerImage = installerImage
+		status.BareMetalInstallerImage = info.imageFor("baremetal-installer")
+		status.CLIImage = cliImage
+		o.setValidCondition(status, corev1.ConditionTrue, releaseImageResolvedReason, releaseImageResolvedMessage)
+	})
+}
+
+// updateStatus records the release image in the status of the ClusterImageSet along with the changes made by update.
+// The metadata of a previous release image is cleared.
+func (o *UpdateClusterImageSetOptions) updateStatus(update func(status *hivev1.ClusterImageSetStatus)) error {
+	imageSet := &hivev1.ClusterImageSet{}
+	logger := o.log.WithField("clusterimageset", o.ClusterImageSetName)
+	logger.Debug("fetching clusterimageset")
+	if err := o.client.Get(context.TODO(), types.NamespacedName{Name: o.ClusterImageSetName}, imageSet); err != nil {
+		logger.WithError(err).Error("failed to get ClusterImageSet")
+		return err
+	}
+	if imageSet.Spec.ReleaseImage != o.ReleaseImage {
+		logger.WithField("releaseImage", imageSet.Spec.ReleaseImage).Info("release image of the clusterimageset has changed, not updating status")
+		return nil
+	}
+	if imageSet.Status.ReleaseImage != o.ReleaseImage {
+		imageSet.Status = hivev1.ClusterImageSetStatus{
+			ReleaseImage: o.ReleaseImage,
+			Conditions:   imageSet.Status.Conditions,
+		}
+	}
+	update(&imageSet.Status)
+	logger.Debug("updating clusterimageset status")
+	return o.client.Status().Update(context.TODO(), imageSet)
+}
+
+func (o *UpdateClusterImageSetOptions) setValidCondition(status *hivev1.ClusterImageSetStatus, conditionStatus corev1.ConditionStatus, reason, message string) {
+	status.Conditions, _ = controllerutils.SetClusterImageSetConditionWithChangeCheck(
+		status.Conditions,
+		hivev1.ClusterImageSetValidCondition,
+		conditionStatus,
+		reason,
+		message,
+		controllerutils.UpdateConditionAlways)
+}
+
+// releaseChannel returns the upgrade graph channel of a release. This is the stable channel of the minor version of
+// the release, as set by the installer, unless the release metadata lists channels that do not include it.
+func releaseChannel(version string, metadata map[string]string) string {
+	var channel string
+	if v, err := semver.Parse(version); err == nil {
+		channel = fmt.Sprintf("stable-%d.%d", v.Major, v.Minor)
+	}
+	var channels []string
+	for _, c := range strings.Split(metadata[releaseChannelsMetadataKey], ",") {
+		if c = strings.TrimSpace(c); c != "" {
+			channels = append(channels, c)
+		}
+	}
+	if len(channels) == 0 {
+		return channel
+	}
+	for _, c := range channels {
+		if c == channel {
+			return channel
+		}
+	}
+	return channels[0]
+}
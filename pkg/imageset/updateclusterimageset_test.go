@@ -0,0 +1,208 @@
+package imageset
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/openshift/hive/pkg/apis"
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+)
+
+const (
+	testReleaseInfo = `{
+  "image": "test-release-image",
+  "digest": "sha256:0123",
+  "metadata": {
+    "kind": "cincinnati-metadata-v0",
+    "version": "4.5.2",
+    "metadata": {
+      "io.openshift.upgrades.graph.release.channels": "candidate-4.5,fast-4.5,stable-4.5"
+    }
+  },
+  "references": {
+    "kind": "ImageStream",
+    "spec": {
+      "tags": [
+        {"name": "baremetal-installer", "from": {"kind": "DockerImage", "name": "quay.io/release@sha256:bm"}},
+        {"name": "cli", "from": {"kind": "DockerImage", "name": "quay.io/release@sha256:cli"}},
+        {"name": "installer", "from": {"kind": "DockerImage", "name": "quay.io/release@sha256:installer"}}
+      ]
+    }
+  }
+}`
+)
+
+func TestUpdateClusterImageSetCommand(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+
+	tests := []struct {
+		name           string
+		existing       *hivev1.ClusterImageSet
+		releaseImage   string
+		files          map[string]string
+		expectedStatus hivev1.ClusterImageSetStatus
+		expectedValid  corev1.ConditionStatus
+		expectedReason string
+	}{
+		{
+			name:     "successful execution",
+			existing: testImageSet(),
+			files: map[string]string{
+				"success":           "1",
+				"release-info.json": testReleaseInfo,
+			},
+			expectedStatus: hivev1.ClusterImageSetStatus{
+				ReleaseImage:            "test-release-image",
+				Version:                 "4.5.2",
+				Channel:                 "stable-4.5",
+				InstallerImage:          "quay.io/release@sha256:installer",
+				BareMetalInstallerImage: "quay.io/release@sha256:bm",
+				CLIImage:                "quay.io/release@sha256:cli",
+			},
+			expectedValid:  corev1.ConditionTrue,
+			expectedReason: releaseImageResolvedReason,
+		},
+		{
+			name:     "failure execution",
+			existing: testImageSet(),
+			files: map[string]string{
+				"success":   "0",
+				"error.log": testErrorMessage,
+			},
+			expectedStatus: hivev1.ClusterImageSetStatus{ReleaseImage: "test-release-image"},
+			expectedValid:  corev1.ConditionFalse,
+			expectedReason: releaseImageResolutionFailedReason,
+		},
+		{
+			name: "failure clears metadata of previous release image",
+			existing: func() *hivev1.ClusterImageSet {
+				imageSet := testImageSet()
+				imageSet.Status = hivev1.ClusterImageSetStatus{
+					ReleaseImage:   "previous-release-image",
+					Version:        "4.5.1",
+					InstallerImage: "quay.io/release@sha256:previous",
+				}
+				return imageSet
+			}(),
+			files: map[string]string{
+				"success":   "0",
+				"error.log": testErrorMessage,
+			},
+			expectedStatus: hivev1.ClusterImageSetStatus{ReleaseImage: "test-release-image"},
+			expectedValid:  corev1.ConditionFalse,
+			expectedReason: releaseImageResolutionFailedReason,
+		},
+		{
+			name:     "release without installer",
+			existing: testImageSet(),
+			files: map[string]string{
+				"success":           "1",
+				"release-info.json": `{"metadata": {"version": "4.5.2"}}`,
+			},
+			expectedStatus: hivev1.ClusterImageSetStatus{ReleaseImage: "test-release-image"},
+			expectedValid:  corev1.ConditionFalse,
+			expectedReason: releaseImageResolutionFailedReason,
+		},
+		{
+			name: "release image changed",
+			existing: func() *hivev1.ClusterImageSet {
+				imageSet := testImageSet()
+				imageSet.Spec.ReleaseImage = "new-release-image"
+				return imageSet
+			}(),
+			files: map[string]string{
+				"success":           "1",
+				"release-info.json": testReleaseInfo,
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			client := fake.NewFakeClient(test.existing)
+			workDir, err := ioutil.TempDir("", "test-update")
+			require.NoError(t, err, "error creating test directory")
+			defer os.RemoveAll(workDir)
+			for name, content := range test.files {
+				require.NoError(t, ioutil.WriteFile(path.Join(workDir, name), []byte(content), 0666), "error writing file")
+			}
+			opt := UpdateClusterImageSetOptions{
+				ClusterImageSetName: testImageSet().Name,
+				ReleaseImage:        testImageSet().Spec.ReleaseImage,
+				WorkDir:             workDir,
+				log:                 log.WithField("test", test.name),
+				client:              client,
+			}
+
+			require.NoError(t, opt.Run(), "unexpected error")
+
+			imageSet := &hivev1.ClusterImageSet{}
+			require.NoError(t, client.Get(context.TODO(), types.NamespacedName{Name: testImageSet().Name}, imageSet))
+			condition := controllerutils.FindClusterImageSetCondition(imageSet.Status.Conditions, hivev1.ClusterImageSetValidCondition)
+			imageSet.Status.Conditions = nil
+			assert.Equal(t, test.expectedStatus, imageSet.Status, "unexpected status")
+			if test.expectedValid == "" {
+				assert.Nil(t, condition, "unexpected valid condition")
+				return
+			}
+			if assert.NotNil(t, condition, "missing valid condition") {
+				assert.Equal(t, test.expectedValid, condition.Status, "unexpected valid condition status")
+				assert.Equal(t, test.expectedReason, condition.Reason, "unexpected valid condition reason")
+			}
+		})
+	}
+}
+
+func TestReleaseChannel(t *testing.T) {
+	cases := []struct {
+		name     string
+		version  string
+		channels string
+		expected string
+	}{
+		{
+			name:     "no channels in metadata",
+			version:  "4.5.2",
+			expected: "stable-4.5",
+		},
+		{
+			name:     "stable channel in metadata",
+			version:  "4.5.2",
+			channels: "candidate-4.5, fast-4.5, stable-4.5",
+			expected: "stable-4.5",
+		},
+		{
+			name:     "no stable channel in metadata",
+			version:  "4.6.0-rc.1",
+			channels: "candidate-4.6",
+			expected: "candidate-4.6",
+		},
+		{
+			name:    "invalid version",
+			version: "latest",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			metadata := map[string]string{}
+			if tc.channels != "" {
+				metadata[releaseChannelsMetadataKey] = tc.channels
+			}
+			assert.Equal(t, tc.expected, releaseChannel(tc.version, metadata))
+		})
+	}
+}
@@ -148,32 +148,8 @@ func (o *UpdateInstallerImageOptions) Validate() error {
 // 4. If failed, obtain error log from ${workdir}/error.log
 // 3. Update the status of the ClusterImageSet based on those results
 func (o *UpdateInstallerImageOptions) Run() error {
-	successFileName := path.Join(o.WorkDir, "success")
-	success := false
-	o.log.Debug("starting to wait for success result")
-	err := wait.PollImmediate(pollInterval, releaseInfoCommandTimeout, func() (bool, error) {
-		_, err := os.Stat(successFileName)
-		if err != nil {
-			if !os.IsNotExist(err) {
-				o.log.WithError(err).Warningf("unexpected error trying to stat %s", successFileName)
-			}
-			return false, nil
-		}
-		result, err := ioutil.ReadFile(successFileName)
-		if err != nil {
-			o.log.WithError(err).Warningf("unexpected error trying to read %s", successFileName)
-			return false, nil
-		}
-		if len(string(result)) == 0 {
-			// keep waiting for content
-			return false, nil
-		}
-		o.log.Debugf("contents of success file: %s", strings.TrimSpace(string(result)))
-		success = strings.TrimSpace(string(result)) == "1"
-		return true, nil
-	})
+	success, err := waitForResult(o.WorkDir, o.log)
 	if err != nil {
-		o.log.WithError(err).Error("timed out waiting for result")
 		return err
 	}
 
@@ -259,6 +235,40 @@ func (o *UpdateInstallerImageOptions) setImageResolutionErrorCondition(errorLog
 	return o.client.Status().Update(context.TODO(), cd)
 }
 
+// waitForResult waits for the oc release info command to write its result to ${workdir}/success, and returns
+// whether the command was successful.
+func waitForResult(workDir string, logger log.FieldLogger) (bool, error) {
+	successFileName := path.Join(workDir, "success")
+	success := false
+	logger.Debug("starting to wait for success result")
+	err := wait.PollImmediate(pollInterval, releaseInfoCommandTimeout, func() (bool, error) {
+		_, err := os.Stat(successFileName)
+		if err != nil {
+			if !os.IsNotExist(err) {
+				logger.WithError(err).Warningf("unexpected error trying to stat %s", successFileName)
+			}
+			return false, nil
+		}
+		result, err := ioutil.ReadFile(successFileName)
+		if err != nil {
+			logger.WithError(err).Warningf("unexpected error trying to read %s", successFileName)
+			return false, nil
+		}
+		if len(string(result)) == 0 {
+			// keep waiting for content
+			return false, nil
+		}
+		logger.Debugf("contents of success file: %s", strings.TrimSpace(string(result)))
+		success = strings.TrimSpace(string(result)) == "1"
+		return true, nil
+	})
+	if err != nil {
+		logger.WithError(err).Error("timed out waiting for result")
+		return false, err
+	}
+	return success, nil
+}
+
 func getClient(kubeConfig *rest.Config) (client.Client, error) {
 	clientScheme := scheme.Scheme
 	apis.AddToScheme(clientScheme)
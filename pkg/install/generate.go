@@ -27,6 +27,10 @@ const (
 	azureAuthFile              = azureAuthDir + "/osServicePrincipal.json"
 	gcpAuthDir                 = "/.gcp"
 	gcpAuthFile                = gcpAuthDir + "/" + constants.GCPCredentialsName
+	openStackCloudsDir         = "/etc/openstack"
+	openStackCloudsFile        = openStackCloudsDir + "/" + constants.OpenStackCredentialsName
+	vsphereCertificatesDir     = "/vsphere-certificates"
+	systemCertificatesDirs     = "/etc/ssl/certs:/etc/pki/tls/certs"
 
 	// SSHPrivateKeyDir is the directory where the generated Job will mount the ssh secret to
 	SSHPrivateKeyDir = "/sshkeys"
@@ -36,6 +40,9 @@ const (
 
 	// SSHSecretPrivateKeyName is the key name holding the private key in the SSH secret
 	SSHSecretPrivateKeyName = "ssh-privatekey"
+
+	// InstallManagerContainerName is the name of the container of the install pod running the install manager
+	InstallManagerContainerName = "hive"
 )
 
 var (
@@ -171,6 +178,27 @@ func InstallerPodSpec(
 			Name:  "GOOGLE_CREDENTIALS",
 			Value: gcpAuthFile,
 		})
+	case cd.Spec.Platform.OpenStack != nil:
+		volumes = append(volumes, corev1.Volume{
+			Name: "openstack",
+			VolumeSource: corev1.VolumeSource{
+				Secret: &corev1.SecretVolumeSource{
+					SecretName: cd.Spec.Platform.OpenStack.CredentialsSecretRef.Name,
+				},
+			},
+		})
+		volumeMounts = append(volumeMounts, corev1.VolumeMount{
+			Name:      "openstack",
+			MountPath: openStackCloudsDir,
+		})
+		env = append(env, corev1.EnvVar{
+			Name:  "OS_CLIENT_CONFIG_FILE",
+			Value: openStackCloudsFile,
+		})
+	case cd.Spec.Platform.VSphere != nil:
+		env = append(env, vsphereCredentialsEnvVars(cd.Spec.Platform.VSphere.CredentialsSecretRef)...)
+		volumes = append(volumes, vsphereCertificatesVolume(cd.Spec.Platform.VSphere.CertificatesSecretRef))
+		volumeMounts = append(volumeMounts, vsphereCertificatesVolumeMount())
 	}
 
 	if releaseImage != "" {
@@ -302,7 +330,7 @@ func InstallerPodSpec(
 			VolumeMounts: volumeMounts,
 		},
 		{
-			Name:            "hive",
+			Name:            InstallManagerContainerName,
 			Image:           images.GetHiveImage(),
 			ImagePullPolicy: images.GetHiveImagePullPolicy(),
 			Env:             append(env, cd.Spec.Provisioning.InstallerEnv...),
@@ -421,6 +449,10 @@ func GenerateUninstallerJobForDeprovision(
 		completeAzureDeprovisionJob(req, job)
 	case req.Spec.Platform.GCP != nil:
 		completeGCPDeprovisionJob(req, job)
+	case req.Spec.Platform.OpenStack != nil:
+		completeOpenStackDeprovisionJob(req, job)
+	case req.Spec.Platform.VSphere != nil:
+		completeVSphereDeprovisionJob(req, job)
 	default:
 		return nil, errors.New("deprovision requests currently not supported for platform")
 	}
@@ -565,3 +597,119 @@ func completeGCPDeprovisionJob(req *hivev1.ClusterDeprovision, job *batchv1.Job)
 	job.Spec.Template.Spec.Containers = containers
 	job.Spec.Template.Spec.Volumes = volumes
 }
+
+func completeOpenStackDeprovisionJob(req *hivev1.ClusterDeprovision, job *batchv1.Job) {
+	volumes := []corev1.Volume{}
+	volumeMounts := []corev1.VolumeMount{}
+	env := []corev1.EnvVar{}
+	volumes = append(volumes, corev1.Volume{
+		Name: "openstack",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: req.Spec.Platform.OpenStack.CredentialsSecretRef.Name,
+			},
+		},
+	})
+	volumeMounts = append(volumeMounts, corev1.VolumeMount{
+		Name:      "openstack",
+		MountPath: openStackCloudsDir,
+	})
+	env = append(env, corev1.EnvVar{
+		Name:  "OS_CLIENT_CONFIG_FILE",
+		Value: openStackCloudsFile,
+	})
+	containers := []corev1.Container{
+		{
+			Name:            "deprovision",
+			Image:           images.GetHiveImage(),
+			ImagePullPolicy: images.GetHiveImagePullPolicy(),
+			Env:             env,
+			Command:         []string{"/usr/bin/hiveutil"},
+			Args: []string{
+				"deprovision",
+				"openstack",
+				"--loglevel",
+				"debug",
+				"--cloud",
+				req.Spec.Platform.OpenStack.Cloud,
+				req.Spec.InfraID,
+			},
+			VolumeMounts: volumeMounts,
+		},
+	}
+	job.Spec.Template.Spec.Containers = containers
+	job.Spec.Template.Spec.Volumes = volumes
+}
+
+func completeVSphereDeprovisionJob(req *hivev1.ClusterDeprovision, job *batchv1.Job) {
+	containers := []corev1.Container{
+		{
+			Name:            "deprovision",
+			Image:           images.GetHiveImage(),
+			ImagePullPolicy: images.GetHiveImagePullPolicy(),
+			Env:             vsphereCredentialsEnvVars(req.Spec.Platform.VSphere.CredentialsSecretRef),
+			Command:         []string{"/usr/bin/hiveutil"},
+			Args: []string{
+				"deprovision",
+				"vsphere",
+				"--loglevel",
+				"debug",
+				"--vsphere-vcenter",
+				req.Spec.Platform.VSphere.VCenter,
+				req.Spec.InfraID,
+			},
+			VolumeMounts: []corev1.VolumeMount{vsphereCertificatesVolumeMount()},
+		},
+	}
+	job.Spec.Template.Spec.Containers = containers
+	job.Spec.Template.Spec.Volumes = []corev1.Volume{vsphereCertificatesVolume(req.Spec.Platform.VSphere.CertificatesSecretRef)}
+}
+
+// vsphereCredentialsEnvVars returns the environment variables used to pass the vSphere username and password
+// from the credentials secret to a container.
+func vsphereCredentialsEnvVars(credentialsSecretRef corev1.LocalObjectReference) []corev1.EnvVar {
+	return []corev1.EnvVar{
+		{
+			Name: constants.VSphereUsernameEnvVar,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: credentialsSecretRef,
+					Key:                  constants.UsernameSecretKey,
+				},
+			},
+		},
+		{
+			Name: constants.VSpherePasswordEnvVar,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: credentialsSecretRef,
+					Key:                  constants.PasswordSecretKey,
+				},
+			},
+		},
+		{
+			// Trust the vCenter CA certificates in addition to the system certificates. Setting SSL_CERT_DIR
+			// replaces the default certificate directories, so they have to be listed as well.
+			Name:  "SSL_CERT_DIR",
+			Value: systemCertificatesDirs + ":" + vsphereCertificatesDir,
+		},
+	}
+}
+
+func vsphereCertificatesVolume(certificatesSecretRef corev1.LocalObjectReference) corev1.Volume {
+	return corev1.Volume{
+		Name: "vsphere-certificates",
+		VolumeSource: corev1.VolumeSource{
+			Secret: &corev1.SecretVolumeSource{
+				SecretName: certificatesSecretRef.Name,
+			},
+		},
+	}
+}
+
+func vsphereCertificatesVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      "vsphere-certificates",
+		MountPath: vsphereCertificatesDir,
+	}
+}
@@ -21,6 +21,28 @@ func TestGenerateDeprovision(t *testing.T) {
 	assert.NotNil(t, job)
 }
 
+func TestGenerateDeprovisionVSphere(t *testing.T) {
+	dr := testClusterDeprovision()
+	dr.Spec.Platform = hivev1.ClusterDeprovisionPlatform{
+		VSphere: &hivev1.VSphereClusterDeprovision{
+			CredentialsSecretRef:  corev1.LocalObjectReference{Name: "vsphere-creds"},
+			CertificatesSecretRef: corev1.LocalObjectReference{Name: "vsphere-certs"},
+			VCenter:               "vcenter.example.com",
+		},
+	}
+	job, err := GenerateUninstallerJobForDeprovision(dr)
+	assert.Nil(t, err)
+	if assert.Len(t, job.Spec.Template.Spec.Containers, 1) {
+		var sslCertDir string
+		for _, envVar := range job.Spec.Template.Spec.Containers[0].Env {
+			if envVar.Name == "SSL_CERT_DIR" {
+				sslCertDir = envVar.Value
+			}
+		}
+		assert.Equal(t, "/etc/ssl/certs:/etc/pki/tls/certs:/vsphere-certificates", sslCertDir, "system certificates must be trusted in addition to the vCenter certificates")
+	}
+}
+
 func testClusterDeprovision() *hivev1.ClusterDeprovision {
 	return &hivev1.ClusterDeprovision{
 		ObjectMeta: metav1.ObjectMeta{
@@ -0,0 +1,258 @@
+// Package installlogarchive provides access to the S3-compatible object store in which the logs of installs are
+// archived.
+package installlogarchive
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+)
+
+const (
+	// InstallerConsoleLog is the name of the log of the console output of the installer.
+	InstallerConsoleLog = "openshift-install-console.log"
+
+	// InstallerLog is the name of the full log of the installer, written to .openshift_install.log.
+	InstallerLog = "openshift_install.log"
+
+	// GatherArtifacts is the name of the archive of the logs gathered after a failed install.
+	GatherArtifacts = "gather.tar.gz"
+
+	defaultRegion = "us-east-1"
+
+	credsSecretIDKey     = "aws_access_key_id"
+	credsSecretAccessKey = "aws_secret_access_key"
+)
+
+// Logs are the names of the logs of a provision stored in the install log archive.
+var Logs = []string{InstallerConsoleLog, InstallerLog, GatherArtifacts}
+
+// Uploads are the objects of the install log archive to which the install manager uploads the logs of a provision.
+type Uploads struct {
+	// Bucket is the bucket holding the objects.
+	Bucket string `json:"bucket"`
+	// Objects are the objects to upload the logs to.
+	Objects []Upload `json:"objects"`
+}
+
+// Upload is an object of the install log archive to which a log is uploaded.
+type Upload struct {
+	// Log is the name of the log uploaded to the object.
+	Log string `json:"log"`
+	// Key is the key of the object.
+	Key string `json:"key"`
+	// URL is the presigned URL with which the log is uploaded.
+	URL string `json:"url"`
+}
+
+// ConfigFromEnvironment returns the configuration of the install log archive set in the environment by the operator
+// from HiveConfig, or nil if there is no install log archive.
+func ConfigFromEnvironment() (*hivev1.InstallLogArchiveConfig, error) {
+	bucket := os.Getenv(constants.InstallLogArchiveBucketEnvVar)
+	if bucket == "" {
+		return nil, nil
+	}
+	config := &hivev1.InstallLogArchiveConfig{
+		Endpoint: os.Getenv(constants.InstallLogArchiveEndpointEnvVar),
+		Region:   os.Getenv(constants.InstallLogArchiveRegionEnvVar),
+		Bucket:   bucket,
+		Prefix:   os.Getenv(constants.InstallLogArchivePrefixEnvVar),
+		CredentialsSecretRef: corev1.LocalObjectReference{
+			Name: os.Getenv(constants.InstallLogArchiveCredentialsSecretEnvVar),
+		},
+	}
+	if retention := os.Getenv(constants.InstallLogArchiveRetentionEnvVar); retention != "" {
+		d, err := time.ParseDuration(retention)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %v", constants.InstallLogArchiveRetentionEnvVar, err)
+		}
+		config.Retention = &metav1.Duration{Duration: d}
+	}
+	if err := ValidateConfig(config); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// ValidateConfig returns an error if the configuration of the install log archive is not valid. A prefix is required
+// with a retention, so that pruning the archive cannot delete objects of the bucket that do not hold install logs.
+func ValidateConfig(config *hivev1.InstallLogArchiveConfig) error {
+	if config.Retention != nil && strings.Trim(config.Prefix, "/") == "" {
+		return fmt.Errorf("a prefix is required for the install log archive when a retention is set")
+	}
+	return nil
+}
+
+// NewClient returns a client of the install log archive, authenticated with the credentials secret of the archive
+// in the given namespace.
+func NewClient(c client.Client, config *hivev1.InstallLogArchiveConfig, namespace string) (s3iface.S3API, error) {
+	secret := &corev1.Secret{}
+	if err := c.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: config.CredentialsSecretRef.Name}, secret); err != nil {
+		return nil, err
+	}
+	return NewClientFromSecret(secret, config)
+}
+
+// NewClientFromSecret returns a client of the install log archive, authenticated with the given credentials secret.
+func NewClientFromSecret(secret *corev1.Secret, config *hivev1.InstallLogArchiveConfig) (s3iface.S3API, error) {
+	accessKeyID, ok := secret.Data[credsSecretIDKey]
+	if !ok {
+		return nil, fmt.Errorf("install log archive credentials secret %s did not contain key %s", secret.Name, credsSecretIDKey)
+	}
+	secretAccessKey, ok := secret.Data[credsSecretAccessKey]
+	if !ok {
+		return nil, fmt.Errorf("install log archive credentials secret %s did not contain key %s", secret.Name, credsSecretAccessKey)
+	}
+	region := config.Region
+	if region == "" {
+		region = defaultRegion
+	}
+	awsConfig := &aws.Config{
+		Region:      aws.String(region),
+		Credentials: credentials.NewStaticCredentials(string(accessKeyID), string(secretAccessKey), ""),
+	}
+	if config.Endpoint != "" {
+		awsConfig.Endpoint = aws.String(config.Endpoint)
+		awsConfig.S3ForcePathStyle = aws.Bool(true)
+	}
+	s, err := session.NewSession(awsConfig)
+	if err != nil {
+		return nil, err
+	}
+	return s3.New(s), nil
+}
+
+// ProvisionPrefix returns the prefix of the keys of the objects holding the logs of a provision.
+func ProvisionPrefix(config *hivev1.InstallLogArchiveConfig, namespace, provisionName string) string {
+	return path.Join(config.Prefix, namespace, provisionName) + "/"
+}
+
+// PresignUploads returns the objects to which the logs of a provision are uploaded, with presigned URLs valid for
+// the given duration.
+func PresignUploads(s3Client s3iface.S3API, config *hivev1.InstallLogArchiveConfig, namespace, provisionName string, expiry time.Duration) (*Uploads, error) {
+	uploads := &Uploads{Bucket: config.Bucket}
+	for _, log := range Logs {
+		key := ProvisionPrefix(config, namespace, provisionName) + log
+		req, _ := s3Client.PutObjectRequest(&s3.PutObjectInput{
+			Bucket: aws.String(config.Bucket),
+			Key:    aws.String(key),
+		})
+		url, err := req.Presign(expiry)
+		if err != nil {
+			return nil, err
+		}
+		uploads.Objects = append(uploads.Objects, Upload{Log: log, Key: key, URL: url})
+	}
+	return uploads, nil
+}
+
+// UploadsEnvVar returns the environment variable used to pass the uploads to the install manager.
+func UploadsEnvVar(uploads *Uploads) (corev1.EnvVar, error) {
+	b, err := json.Marshal(uploads)
+	if err != nil {
+		return corev1.EnvVar{}, err
+	}
+	return corev1.EnvVar{Name: constants.InstallLogArchiveUploadsEnvVar, Value: string(b)}, nil
+}
+
+// UploadsFromEnvironment returns the uploads passed to the install manager, or nil if logs are not archived.
+func UploadsFromEnvironment() (*Uploads, error) {
+	value := os.Getenv(constants.InstallLogArchiveUploadsEnvVar)
+	if value == "" {
+		return nil, nil
+	}
+	uploads := &Uploads{}
+	if err := json.Unmarshal([]byte(value), uploads); err != nil {
+		return nil, fmt.Errorf("invalid value for %s: %v", constants.InstallLogArchiveUploadsEnvVar, err)
+	}
+	return uploads, nil
+}
+
+// Put uploads the content of the file to the presigned URL of an object.
+func Put(httpClient *http.Client, url string, file *os.File) error {
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPut, url, file)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = info.Size()
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected response uploading object: %s", resp.Status)
+	}
+	return nil
+}
+
+// Prune deletes the objects under the prefix of the install log archive that were last modified before the given
+// time, and returns the number of objects deleted.
+func Prune(s3Client s3iface.S3API, config *hivev1.InstallLogArchiveConfig, before time.Time) (int, error) {
+	prefix := strings.Trim(config.Prefix, "/")
+	if prefix == "" {
+		return 0, fmt.Errorf("refusing to prune install log archive without a prefix")
+	}
+	var expired []*s3.ObjectIdentifier
+	input := &s3.ListObjectsV2Input{
+		Bucket: aws.String(config.Bucket),
+		Prefix: aws.String(prefix + "/"),
+	}
+	err := s3Client.ListObjectsV2Pages(input, func(page *s3.ListObjectsV2Output, _ bool) bool {
+		for _, obj := range page.Contents {
+			if obj.LastModified != nil && obj.LastModified.Before(before) {
+				expired = append(expired, &s3.ObjectIdentifier{Key: obj.Key})
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return 0, err
+	}
+	deleted := 0
+	// At most 1000 objects can be deleted with a single request.
+	for len(expired) > 0 {
+		n := len(expired)
+		if n > 1000 {
+			n = 1000
+		}
+		out, err := s3Client.DeleteObjects(&s3.DeleteObjectsInput{
+			Bucket: aws.String(config.Bucket),
+			Delete: &s3.Delete{Objects: expired[:n], Quiet: aws.Bool(true)},
+		})
+		if err != nil {
+			return deleted, err
+		}
+		deleted += n - len(out.Errors)
+		if len(out.Errors) > 0 {
+			e := out.Errors[0]
+			return deleted, fmt.Errorf("failed to delete %d objects, including %s: %s", len(out.Errors), aws.StringValue(e.Key), aws.StringValue(e.Message))
+		}
+		expired = expired[n:]
+	}
+	return deleted, nil
+}
@@ -0,0 +1,233 @@
+package installlogarchive
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+)
+
+const (
+	testNamespace     = "test-namespace"
+	testProvisionName = "test-provision"
+	testBucket        = "test-bucket"
+)
+
+func testSecret() *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "archive-creds"},
+		Data: map[string][]byte{
+			credsSecretIDKey:     []byte("access-key"),
+			credsSecretAccessKey: []byte("secret-key"),
+		},
+	}
+}
+
+func TestPresignUploadsAndPut(t *testing.T) {
+	uploaded := map[string]string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || r.URL.Query().Get("X-Amz-Signature") == "" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		uploaded[r.URL.Path] = string(body)
+	}))
+	defer server.Close()
+
+	config := &hivev1.InstallLogArchiveConfig{
+		Endpoint: server.URL,
+		Bucket:   testBucket,
+		Prefix:   "logs/",
+	}
+	s3Client, err := NewClientFromSecret(testSecret(), config)
+	require.NoError(t, err, "unexpected error creating client")
+
+	uploads, err := PresignUploads(s3Client, config, testNamespace, testProvisionName, time.Hour)
+	require.NoError(t, err, "unexpected error presigning uploads")
+	assert.Equal(t, testBucket, uploads.Bucket, "unexpected bucket")
+	require.Len(t, uploads.Objects, len(Logs), "unexpected number of uploads")
+	for i, upload := range uploads.Objects {
+		assert.Equal(t, Logs[i], upload.Log, "unexpected log")
+		assert.Equal(t, fmt.Sprintf("logs/%s/%s/%s", testNamespace, testProvisionName, Logs[i]), upload.Key, "unexpected key")
+		u, err := url.Parse(upload.URL)
+		require.NoError(t, err, "unexpected error parsing presigned URL")
+		assert.Equal(t, fmt.Sprintf("/%s/%s", testBucket, upload.Key), u.Path, "expected path-style URL")
+	}
+
+	file, err := ioutil.TempFile("", "install-log")
+	require.NoError(t, err, "unexpected error creating log file")
+	defer os.Remove(file.Name())
+	_, err = file.WriteString("install log")
+	require.NoError(t, err, "unexpected error writing log file")
+	_, err = file.Seek(0, 0)
+	require.NoError(t, err, "unexpected error rewinding log file")
+
+	upload := uploads.Objects[0]
+	require.NoError(t, Put(http.DefaultClient, upload.URL, file), "unexpected error uploading log")
+	assert.Equal(t, "install log", uploaded[fmt.Sprintf("/%s/%s", testBucket, upload.Key)], "unexpected uploaded log")
+}
+
+func TestNewClientFromSecretMissingKey(t *testing.T) {
+	secret := testSecret()
+	delete(secret.Data, credsSecretAccessKey)
+	_, err := NewClientFromSecret(secret, &hivev1.InstallLogArchiveConfig{Bucket: testBucket})
+	assert.Error(t, err, "expected error for secret without secret access key")
+}
+
+func TestUploadsEnvVar(t *testing.T) {
+	uploads := &Uploads{
+		Bucket:  testBucket,
+		Objects: []Upload{{Log: InstallerLog, Key: "key", URL: "https://example.com/key"}},
+	}
+	envVar, err := UploadsEnvVar(uploads)
+	require.NoError(t, err, "unexpected error creating env var")
+	os.Setenv(envVar.Name, envVar.Value)
+	defer os.Unsetenv(envVar.Name)
+	actual, err := UploadsFromEnvironment()
+	require.NoError(t, err, "unexpected error reading uploads")
+	assert.Equal(t, uploads, actual, "unexpected uploads")
+}
+
+func TestConfigFromEnvironment(t *testing.T) {
+	cases := []struct {
+		name           string
+		env            map[string]string
+		expectedConfig *hivev1.InstallLogArchiveConfig
+		expectError    bool
+	}{
+		{
+			name: "no archive",
+		},
+		{
+			name: "archive",
+			env: map[string]string{
+				constants.InstallLogArchiveEndpointEnvVar:          "http://minio:9000",
+				constants.InstallLogArchiveBucketEnvVar:            testBucket,
+				constants.InstallLogArchivePrefixEnvVar:            "logs",
+				constants.InstallLogArchiveCredentialsSecretEnvVar: "archive-creds",
+				constants.InstallLogArchiveRetentionEnvVar:         "168h0m0s",
+			},
+			expectedConfig: &hivev1.InstallLogArchiveConfig{
+				Endpoint:             "http://minio:9000",
+				Bucket:               testBucket,
+				Prefix:               "logs",
+				CredentialsSecretRef: corev1.LocalObjectReference{Name: "archive-creds"},
+				Retention:            &metav1.Duration{Duration: 7 * 24 * time.Hour},
+			},
+		},
+		{
+			name: "invalid retention",
+			env: map[string]string{
+				constants.InstallLogArchiveBucketEnvVar:    testBucket,
+				constants.InstallLogArchivePrefixEnvVar:    "logs",
+				constants.InstallLogArchiveRetentionEnvVar: "a week",
+			},
+			expectError: true,
+		},
+		{
+			name: "retention without prefix",
+			env: map[string]string{
+				constants.InstallLogArchiveBucketEnvVar:    testBucket,
+				constants.InstallLogArchivePrefixEnvVar:    "/",
+				constants.InstallLogArchiveRetentionEnvVar: "168h0m0s",
+			},
+			expectError: true,
+		},
+		{
+			name: "no retention without prefix",
+			env: map[string]string{
+				constants.InstallLogArchiveBucketEnvVar:            testBucket,
+				constants.InstallLogArchiveCredentialsSecretEnvVar: "archive-creds",
+			},
+			expectedConfig: &hivev1.InstallLogArchiveConfig{
+				Bucket:               testBucket,
+				CredentialsSecretRef: corev1.LocalObjectReference{Name: "archive-creds"},
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			for k, v := range tc.env {
+				os.Setenv(k, v)
+				defer os.Unsetenv(k)
+			}
+			config, err := ConfigFromEnvironment()
+			if tc.expectError {
+				assert.Error(t, err, "expected error reading config")
+				return
+			}
+			require.NoError(t, err, "unexpected error reading config")
+			assert.Equal(t, tc.expectedConfig, config, "unexpected config")
+		})
+	}
+}
+
+type fakeS3Client struct {
+	s3iface.S3API
+	objects []*s3.Object
+	deleted []string
+}
+
+func (c *fakeS3Client) ListObjectsV2Pages(input *s3.ListObjectsV2Input, fn func(*s3.ListObjectsV2Output, bool) bool) error {
+	var contents []*s3.Object
+	for _, obj := range c.objects {
+		if strings.HasPrefix(*obj.Key, aws.StringValue(input.Prefix)) {
+			contents = append(contents, obj)
+		}
+	}
+	fn(&s3.ListObjectsV2Output{Contents: contents}, true)
+	return nil
+}
+
+func (c *fakeS3Client) DeleteObjects(input *s3.DeleteObjectsInput) (*s3.DeleteObjectsOutput, error) {
+	for _, obj := range input.Delete.Objects {
+		c.deleted = append(c.deleted, *obj.Key)
+	}
+	return &s3.DeleteObjectsOutput{}, nil
+}
+
+func TestPrune(t *testing.T) {
+	now := time.Now()
+	s3Client := &fakeS3Client{
+		objects: []*s3.Object{
+			{Key: aws.String("logs/ns/old/openshift_install.log"), LastModified: aws.Time(now.Add(-48 * time.Hour))},
+			{Key: aws.String("logs/ns/new/openshift_install.log"), LastModified: aws.Time(now.Add(-time.Hour))},
+			{Key: aws.String("other/ns/old/openshift_install.log"), LastModified: aws.Time(now.Add(-48 * time.Hour))},
+		},
+	}
+	config := &hivev1.InstallLogArchiveConfig{Bucket: testBucket, Prefix: "logs"}
+	deleted, err := Prune(s3Client, config, now.Add(-24*time.Hour))
+	require.NoError(t, err, "unexpected error pruning archive")
+	assert.Equal(t, 1, deleted, "unexpected number of deleted objects")
+	assert.Equal(t, []string{"logs/ns/old/openshift_install.log"}, s3Client.deleted, "unexpected deleted objects")
+}
+
+func TestPruneWithoutPrefix(t *testing.T) {
+	s3Client := &fakeS3Client{
+		objects: []*s3.Object{
+			{Key: aws.String("ns/old/openshift_install.log"), LastModified: aws.Time(time.Now().Add(-48 * time.Hour))},
+		},
+	}
+	config := &hivev1.InstallLogArchiveConfig{Bucket: testBucket}
+	_, err := Prune(s3Client, config, time.Now())
+	assert.Error(t, err, "expected error pruning archive without prefix")
+	assert.Empty(t, s3Client.deleted, "unexpected deleted objects")
+}
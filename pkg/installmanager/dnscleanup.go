@@ -1,19 +1,26 @@
 package installmanager
 
 import (
+	"context"
+	"os"
+	"strings"
+
 	awsclient "github.com/openshift/hive/pkg/awsclient"
+	"github.com/openshift/hive/pkg/azureclient"
 
 	log "github.com/sirupsen/logrus"
 
+	azuredns "github.com/Azure/azure-sdk-for-go/services/dns/mgmt/2017-10-01/dns"
+	"github.com/Azure/go-autorest/autorest/to"
 	awssdk "github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/route53"
 )
 
-// cleanupDNSZone queries the Route53 zone and deletes any A records found. Other record
+// cleanupAWSDNSZone queries the Route53 zone and deletes any A records found. Other record
 // types may be added in the future, but right now this is the only one we're seeing
 // leak and conflict.
 // May no longer be necessary once https://jira.coreos.com/browse/CORS-1195 is fixed.
-func cleanupDNSZone(dnsZoneID, region string, logger log.FieldLogger) error {
+func cleanupAWSDNSZone(dnsZoneID, region string, logger log.FieldLogger) error {
 	zoneLogger := logger.WithField("dnsZoneID", dnsZoneID)
 	zoneLogger.Info("cleaning up DNSZone")
 
@@ -54,3 +61,41 @@ func cleanupDNSZone(dnsZoneID, region string, logger log.FieldLogger) error {
 	zoneLogger.Info("DNSZone A records deleted")
 	return nil
 }
+
+// cleanupAzureDNSZone queries the Azure DNS zone and deletes any A and CNAME records found. These are the records
+// created for the API and ingress of the cluster, which would otherwise conflict with those of the next install
+// attempt.
+func cleanupAzureDNSZone(resourceGroupName, zoneName string, logger log.FieldLogger) error {
+	zoneLogger := logger.WithField("resourceGroupName", resourceGroupName).WithField("zoneName", zoneName)
+	zoneLogger.Info("cleaning up DNSZone")
+
+	azureClient, err := azureclient.NewClientFromFile(os.Getenv("AZURE_AUTH_LOCATION"))
+	if err != nil {
+		return err
+	}
+	ctx := context.TODO()
+	page, err := azureClient.ListRecordSetsByZone(ctx, resourceGroupName, zoneName, "")
+	if err != nil {
+		return err
+	}
+	var recordSets []azuredns.RecordSet
+	for page.NotDone() {
+		recordSets = append(recordSets, page.Values()...)
+		if err := page.NextWithContext(ctx); err != nil {
+			return err
+		}
+	}
+	for _, r := range recordSets {
+		recordType := azuredns.RecordType(strings.TrimPrefix(to.String(r.Type), "Microsoft.Network/dnszones/"))
+		if recordType != azuredns.A && recordType != azuredns.CNAME {
+			continue
+		}
+		zoneLogger.WithFields(log.Fields{"name": to.String(r.Name), "type": recordType}).Infof("deleting %s record", recordType)
+		if err := azureClient.DeleteRecordSet(ctx, resourceGroupName, zoneName, to.String(r.Name), recordType); err != nil {
+			logger.WithError(err).WithField("recordset", to.String(r.Name)).Warn("error deleting recordset")
+			return err
+		}
+	}
+	zoneLogger.Info("DNSZone A and CNAME records deleted")
+	return nil
+}
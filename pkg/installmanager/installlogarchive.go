@@ -0,0 +1,163 @@
+package installmanager
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/installlogarchive"
+)
+
+// archiveInstallLogs uploads the full logs of the install to the install log archive and links the uploaded objects
+// from the ClusterProvision. The logs are archived on a best effort basis, failures are not fatal.
+func (m *InstallManager) archiveInstallLogs(provision *hivev1.ClusterProvision, scrubInstallLog bool) {
+	if m.installLogArchiveUploads == nil {
+		return
+	}
+	m.log.Info("archiving install logs")
+	var objects []string
+	for _, upload := range m.installLogArchiveUploads.Objects {
+		logger := m.log.WithField("log", upload.Log).WithField("key", upload.Key)
+		var uploaded bool
+		var err error
+		switch upload.Log {
+		case installlogarchive.InstallerConsoleLog:
+			uploaded, err = m.uploadLogFile(upload.URL, installerConsoleLogFilePath, scrubInstallLog)
+		case installlogarchive.InstallerLog:
+			uploaded, err = m.uploadLogFile(upload.URL, filepath.Join(m.WorkDir, installerFullLogFile), scrubInstallLog)
+		case installlogarchive.GatherArtifacts:
+			uploaded, err = m.uploadGatheredLogs(upload.URL)
+		default:
+			logger.Warn("skipping unknown log")
+			continue
+		}
+		if err != nil {
+			logger.WithError(err).Warn("error archiving log")
+			continue
+		}
+		if uploaded {
+			logger.Info("archived log")
+			objects = append(objects, upload.Key)
+		}
+	}
+	if len(objects) == 0 {
+		return
+	}
+	if err := m.updateClusterProvision(
+		provision,
+		m,
+		func(provision *hivev1.ClusterProvision) {
+			provision.Spec.InstallLogArchive = &hivev1.InstallLogArchiveReference{
+				Bucket:  m.installLogArchiveUploads.Bucket,
+				Objects: objects,
+			}
+		},
+	); err != nil {
+		m.log.WithError(err).Warning("error updating cluster provision with install log archive")
+	}
+}
+
+// uploadLogFile uploads a log file to the presigned URL of an object, scrubbing it first if requested. It returns
+// false if the log file does not exist.
+func (m *InstallManager) uploadLogFile(url, path string, scrub bool) (bool, error) {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return false, nil
+	}
+	if scrub {
+		logBytes, err := ioutil.ReadFile(path)
+		if err != nil {
+			return false, err
+		}
+		tmpFile, err := ioutil.TempFile("", "install-log")
+		if err != nil {
+			return false, err
+		}
+		defer os.Remove(tmpFile.Name())
+		defer tmpFile.Close()
+		if _, err := tmpFile.WriteString(cleanupLogOutput(string(logBytes))); err != nil {
+			return false, err
+		}
+		if _, err := tmpFile.Seek(0, io.SeekStart); err != nil {
+			return false, err
+		}
+		return true, installlogarchive.Put(http.DefaultClient, url, tmpFile)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+	return true, installlogarchive.Put(http.DefaultClient, url, file)
+}
+
+// uploadGatheredLogs uploads a gzipped tarball of the logs gathered by this install to the presigned URL of an
+// object. It returns false if no logs were gathered.
+func (m *InstallManager) uploadGatheredLogs(url string) (bool, error) {
+	if len(m.gatheredLogs) == 0 {
+		return false, nil
+	}
+	tmpFile, err := ioutil.TempFile("", "gather")
+	if err != nil {
+		return false, err
+	}
+	defer os.Remove(tmpFile.Name())
+	defer tmpFile.Close()
+	if err := m.writeGatheredLogs(tmpFile); err != nil {
+		return false, errors.Wrap(err, "error creating tarball of gathered logs")
+	}
+	if _, err := tmpFile.Seek(0, io.SeekStart); err != nil {
+		return false, err
+	}
+	return true, installlogarchive.Put(http.DefaultClient, url, tmpFile)
+}
+
+// writeGatheredLogs writes a gzipped tarball of the logs gathered by this install, with paths relative to LogsDir.
+func (m *InstallManager) writeGatheredLogs(w io.Writer) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	for _, gathered := range m.gatheredLogs {
+		if err := filepath.Walk(gathered, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			if !info.Mode().IsRegular() && !info.IsDir() {
+				return nil
+			}
+			name, err := filepath.Rel(m.LogsDir, path)
+			if err != nil {
+				return err
+			}
+			header, err := tar.FileInfoHeader(info, "")
+			if err != nil {
+				return err
+			}
+			header.Name = filepath.ToSlash(name)
+			if err := tw.WriteHeader(header); err != nil {
+				return err
+			}
+			if info.IsDir() {
+				return nil
+			}
+			file, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+			_, err = io.Copy(tw, file)
+			return err
+		}); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -27,6 +28,10 @@ import (
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
 	"github.com/openshift/hive/pkg/constants"
 	controllerutils "github.com/openshift/hive/pkg/controller/utils"
+	_ "github.com/openshift/hive/pkg/destroy/openstack" // registers the OpenStack destroyer
+	_ "github.com/openshift/hive/pkg/destroy/vsphere"   // registers the vSphere destroyer
+	"github.com/openshift/hive/pkg/installlogarchive"
+	"github.com/openshift/hive/pkg/remoteclient"
 	"github.com/openshift/hive/pkg/resource"
 
 	corev1 "k8s.io/api/core/v1"
@@ -55,8 +60,11 @@ import (
 	"github.com/openshift/installer/pkg/destroy/aws"
 	"github.com/openshift/installer/pkg/destroy/azure"
 	"github.com/openshift/installer/pkg/destroy/gcp"
+	"github.com/openshift/installer/pkg/destroy/providers"
 	installertypes "github.com/openshift/installer/pkg/types"
 	installertypesgcp "github.com/openshift/installer/pkg/types/gcp"
+	installertypesopenstack "github.com/openshift/installer/pkg/types/openstack"
+	installertypesvsphere "github.com/openshift/installer/pkg/types/vsphere"
 )
 
 const (
@@ -107,6 +115,15 @@ type InstallManager struct {
 	readInstallerLog         func(*hivev1.ClusterProvision, *InstallManager, bool) (string, error)
 	waitForProvisioningStage func(*hivev1.ClusterProvision, *InstallManager) error
 	isGatherLogsEnabled      func() bool
+
+	// remoteProxyURL is the proxy through which to connect to the API server of the cluster being installed.
+	remoteProxyURL *url.URL
+
+	// installLogArchiveUploads are the objects to which the logs of the provision are uploaded, if any.
+	installLogArchiveUploads *installlogarchive.Uploads
+
+	// gatheredLogs are the files and directories in LogsDir holding the logs gathered by this install.
+	gatheredLogs []string
 }
 
 // NewInstallManagerCommand is the entrypoint to create the 'install-manager' subcommand
@@ -167,6 +184,13 @@ func (m *InstallManager) Complete(args []string) error {
 	m.cleanupFailedProvision = cleanupFailedProvision
 	m.waitForProvisioningStage = waitForProvisioningStage
 
+	uploads, err := installlogarchive.UploadsFromEnvironment()
+	if err != nil {
+		log.WithError(err).Error("cannot read install log archive uploads")
+		return err
+	}
+	m.installLogArchiveUploads = uploads
+
 	// Set log level
 	level, err := log.ParseLevel(m.LogLevel)
 	if err != nil {
@@ -230,6 +254,17 @@ func (m *InstallManager) Run() error {
 		os.Exit(0)
 	}
 
+	remoteProxy, err := remoteclient.GetProxy(m.DynamicClient, cd)
+	if err != nil {
+		m.log.WithError(err).Fatal("error looking up proxy for cluster API server")
+	}
+	if remoteProxy != nil {
+		m.remoteProxyURL, err = remoteProxy.LocalURL()
+		if err != nil {
+			m.log.WithError(err).Fatal("error starting proxy for cluster API server")
+		}
+	}
+
 	// sshKeyPaths will contain paths to all ssh keys in use
 	var sshKeyPaths []string
 
@@ -249,6 +284,9 @@ func (m *InstallManager) Run() error {
 		scrubInstallLog = !disable
 	}
 
+	// Archive whatever logs the install produced however it ends, including failures before the installer runs.
+	defer m.archiveInstallLogs(provision, scrubInstallLog)
+
 	// setup the bare metal provisioning libvirt ssh key if possible
 	if cd.Spec.Platform.BareMetal != nil {
 		libvirtSSHKeyPath, libvirtSSHAgentSetupErr := m.initSSHKey(constants.LibvirtSSHPrivKeyPathEnvVar)
@@ -523,7 +561,7 @@ func cleanupFailedProvision(dynClient client.Client, cd *hivev1.ClusterDeploymen
 				// Shouldn't really be possible as we block install until DNS is ready:
 				return fmt.Errorf("DNSZone %s has no ZoneID set", dnsZone.Name)
 			}
-			return cleanupDNSZone(*dnsZone.Status.AWS.ZoneID, cd.Spec.Platform.AWS.Region, logger)
+			return cleanupAWSDNSZone(*dnsZone.Status.AWS.ZoneID, cd.Spec.Platform.AWS.Region, logger)
 		}
 		return nil
 	case cd.Spec.Platform.Azure != nil:
@@ -540,7 +578,30 @@ func cleanupFailedProvision(dynClient client.Client, cd *hivev1.ClusterDeploymen
 		uninstaller.GraphAuthorizer = session.GraphAuthorizer
 		uninstaller.Authorizer = session.Authorizer
 
-		return uninstaller.Run()
+		if err := uninstaller.Run(); err != nil {
+			return err
+		}
+
+		// If we're managing DNS for this cluster, lookup the DNSZone and cleanup
+		// any leftover records that would conflict with the next install attempt.
+		if cd.Spec.ManageDNS {
+			dnsZone := &hivev1.DNSZone{}
+			dnsZoneNamespacedName := types.NamespacedName{Namespace: cd.Namespace, Name: controllerutils.DNSZoneName(cd.Name)}
+			err := dynClient.Get(context.TODO(), dnsZoneNamespacedName, dnsZone)
+			if err != nil {
+				logger.WithError(err).Error("error looking up managed dnszone")
+				return err
+			}
+			if dnsZone.Spec.Azure == nil || dnsZone.Status.Azure == nil {
+				return fmt.Errorf("found non-Azure DNSZone for Azure ClusterDeployment")
+			}
+			if dnsZone.Status.Azure.ZoneName == nil {
+				// Shouldn't really be possible as we block install until DNS is ready:
+				return fmt.Errorf("DNSZone %s has no ZoneName set", dnsZone.Name)
+			}
+			return cleanupAzureDNSZone(dnsZone.Spec.Azure.ResourceGroupName, *dnsZone.Status.Azure.ZoneName, logger)
+		}
+		return nil
 	case cd.Spec.Platform.GCP != nil:
 		credsFile := os.Getenv("GOOGLE_CREDENTIALS")
 		projectID, err := gcpclient.ProjectIDFromFile(credsFile)
@@ -561,6 +622,47 @@ func cleanupFailedProvision(dynClient client.Client, cd *hivev1.ClusterDeploymen
 			return err
 		}
 		return uninstaller.Run()
+	case cd.Spec.Platform.OpenStack != nil:
+		metadata := &installertypes.ClusterMetadata{
+			InfraID: infraID,
+			ClusterPlatformMetadata: installertypes.ClusterPlatformMetadata{
+				OpenStack: &installertypesopenstack.Metadata{
+					Cloud: cd.Spec.Platform.OpenStack.Cloud,
+					Identifier: map[string]string{
+						"openshiftClusterID": infraID,
+					},
+				},
+			},
+		}
+		newUninstaller, ok := providers.Registry[installertypesopenstack.Name]
+		if !ok {
+			return errors.New("no uninstaller available for OpenStack")
+		}
+		uninstaller, err := newUninstaller(logger, metadata)
+		if err != nil {
+			return err
+		}
+		return uninstaller.Run()
+	case cd.Spec.Platform.VSphere != nil:
+		metadata := &installertypes.ClusterMetadata{
+			InfraID: infraID,
+			ClusterPlatformMetadata: installertypes.ClusterPlatformMetadata{
+				VSphere: &installertypesvsphere.Metadata{
+					VCenter:  cd.Spec.Platform.VSphere.VCenter,
+					Username: os.Getenv(constants.VSphereUsernameEnvVar),
+					Password: os.Getenv(constants.VSpherePasswordEnvVar),
+				},
+			},
+		}
+		newUninstaller, ok := providers.Registry[installertypesvsphere.Name]
+		if !ok {
+			return errors.New("no uninstaller available for vSphere")
+		}
+		uninstaller, err := newUninstaller(logger, metadata)
+		if err != nil {
+			return err
+		}
+		return uninstaller.Run()
 	default:
 		logger.Warn("unknown platform for re-try cleanup")
 		return errors.New("unknown platform for re-try cleanup")
@@ -608,7 +710,7 @@ func (m *InstallManager) provisionCluster() error {
 	if err := m.runOpenShiftInstallCommand("create", "cluster"); err != nil {
 		if m.isBootstrapComplete() {
 			m.log.WithError(err).Warn("provisioning cluster failed after completing bootstrapping, waiting longer for install to complete")
-			err = m.runOpenShiftInstallCommand("wait-for", "install-complete")
+			err = m.runOpenShiftInstallCommandWithEnv(m.remoteClusterEnv(), "wait-for", "install-complete")
 		}
 		if err != nil {
 			m.log.WithError(err).Error("error provisioning cluster")
@@ -619,9 +721,16 @@ func (m *InstallManager) provisionCluster() error {
 }
 
 func (m *InstallManager) runOpenShiftInstallCommand(args ...string) error {
+	return m.runOpenShiftInstallCommandWithEnv(nil, args...)
+}
+
+// runOpenShiftInstallCommandWithEnv runs the openshift-install binary with the given environment. The environment
+// of the install manager is used when env is nil.
+func (m *InstallManager) runOpenShiftInstallCommandWithEnv(env []string, args ...string) error {
 	m.log.WithField("args", args).Info("running openshift-install binary")
 	cmd := exec.Command("./openshift-install", args...)
 	cmd.Dir = m.WorkDir
+	cmd.Env = env
 
 	// save the commands' stdout/stderr to a file
 	stdOutAndErrOutput, err := os.OpenFile(installerConsoleLogFilePath, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0666)
@@ -788,10 +897,13 @@ func (m *InstallManager) gatherClusterLogs(cd *hivev1.ClusterDeployment) error {
 	m.log.Info("attempting to gather logs with oc adm must-gather")
 	destDir := filepath.Join(m.LogsDir, fmt.Sprintf("%s-must-gather", time.Now().Format("20060102150405")))
 	cmd := exec.Command(filepath.Join(m.WorkDir, "oc"), "adm", "must-gather", "--dest-dir", destDir)
-	cmd.Env = os.Environ()
+	cmd.Env = m.remoteClusterEnv()
 	cmd.Env = append(cmd.Env, fmt.Sprintf("KUBECONFIG=%s", filepath.Join(m.WorkDir, "auth", "kubeconfig")))
 	stdout, err := cmd.Output()
 	m.log.Infof("must-gather output: %s", stdout)
+	if isDirNonEmpty(destDir) {
+		m.gatheredLogs = append(m.gatheredLogs, destDir)
+	}
 	return err
 }
 
@@ -956,6 +1068,7 @@ func (m *InstallManager) gatherBootstrapNodeLogs(cd *hivev1.ClusterDeployment, n
 			return err
 		}
 		m.log.Infof("moved %s to %s", lb, m.LogsDir)
+		m.gatheredLogs = append(m.gatheredLogs, filepath.Join(m.LogsDir, filepath.Base(lb)))
 	}
 	m.log.Info("bootstrap node log gathering complete")
 
@@ -1010,9 +1123,24 @@ func (m *InstallManager) isBootstrapComplete() bool {
 	defer cancel()
 	cmd := exec.CommandContext(ctx, "./openshift-install", "wait-for", "bootstrap-complete")
 	cmd.Dir = m.WorkDir
+	cmd.Env = m.remoteClusterEnv()
 	return cmd.Run() == nil
 }
 
+// remoteClusterEnv returns the environment for commands which connect to the API server of the cluster being
+// installed. The commands connect through the proxy of the ClusterDeployment, if any. Other commands are not run
+// through the proxy, since they also connect to the APIs of the cloud provider.
+func (m *InstallManager) remoteClusterEnv() []string {
+	env := os.Environ()
+	if m.remoteProxyURL != nil {
+		env = append(env,
+			fmt.Sprintf("HTTPS_PROXY=%s", m.remoteProxyURL),
+			fmt.Sprintf("HTTP_PROXY=%s", m.remoteProxyURL),
+		)
+	}
+	return env
+}
+
 func uploadAdminKubeconfig(provision *hivev1.ClusterProvision, m *InstallManager) (*corev1.Secret, error) {
 	m.log.Infoln("uploading admin kubeconfig")
 	fullPath := filepath.Join(m.WorkDir, adminKubeConfigRelativePath)
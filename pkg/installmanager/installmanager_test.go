@@ -1,10 +1,16 @@
 package installmanager
 
 import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
 	"context"
 	"fmt"
 	"github.com/stretchr/testify/require"
+	"io"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path"
 	"path/filepath"
@@ -22,13 +28,17 @@ import (
 	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/utils/pointer"
 
+	"github.com/openshift/installer/pkg/destroy/providers"
 	installertypes "github.com/openshift/installer/pkg/types"
+	installertypesopenstack "github.com/openshift/installer/pkg/types/openstack"
+	installertypesvsphere "github.com/openshift/installer/pkg/types/vsphere"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	"github.com/openshift/hive/pkg/apis"
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
 	"github.com/openshift/hive/pkg/constants"
+	"github.com/openshift/hive/pkg/installlogarchive"
 )
 
 const (
@@ -92,6 +102,7 @@ func TestInstallManager(t *testing.T) {
 		expectPasswordSecret          bool
 		expectProvisionMetadataUpdate bool
 		expectProvisionLogUpdate      bool
+		expectArchivedLogs            bool
 		expectError                   bool
 	}{
 		{
@@ -130,6 +141,13 @@ func TestInstallManager(t *testing.T) {
 			failedKubeconfigSave: true,
 			expectError:          true,
 		},
+		{
+			name:                 "archive logs after fatal error",
+			existing:             []runtime.Object{testClusterDeployment(), testClusterProvision()},
+			failedKubeconfigSave: true,
+			expectArchivedLogs:   true,
+			expectError:          true,
+		},
 		{
 			name:                    "failed admin username/password save", // fatal error
 			existing:                []runtime.Object{testClusterDeployment(), testClusterProvision()},
@@ -235,6 +253,19 @@ func TestInstallManager(t *testing.T) {
 			// We don't want to run the uninstaller, so stub it out
 			im.cleanupFailedProvision = alwaysSucceedCleanupFailedProvision
 
+			if test.expectArchivedLogs {
+				server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+				defer server.Close()
+				im.installLogArchiveUploads = &installlogarchive.Uploads{
+					Bucket: "install-logs",
+					Objects: []installlogarchive.Upload{{
+						Log: installlogarchive.InstallerConsoleLog,
+						Key: "logs/" + installlogarchive.InstallerConsoleLog,
+						URL: server.URL + "/logs/" + installlogarchive.InstallerConsoleLog,
+					}},
+				}
+			}
+
 			err = im.Run()
 
 			if test.expectError {
@@ -321,6 +352,14 @@ func TestInstallManager(t *testing.T) {
 			} else {
 				assert.Nil(t, provision.Spec.InstallLog, "expected install log to be empty")
 			}
+
+			if test.expectArchivedLogs {
+				if assert.NotNil(t, provision.Spec.InstallLogArchive, "expected install log archive to be set") {
+					assert.Equal(t, []string{"logs/" + installlogarchive.InstallerConsoleLog}, provision.Spec.InstallLogArchive.Objects, "unexpected archived logs")
+				}
+			} else {
+				assert.Nil(t, provision.Spec.InstallLogArchive, "expected install log archive to be empty")
+			}
 		})
 	}
 }
@@ -771,3 +810,125 @@ func Test_pasteInPullSecret(t *testing.T) {
 		})
 	}
 }
+
+func TestArchiveInstallLogs(t *testing.T) {
+	apis.AddToScheme(scheme.Scheme)
+	tests := []struct {
+		name            string
+		gatherLogs      bool
+		failUploads     bool
+		expectedObjects []string
+	}{
+		{
+			name:            "installer logs",
+			expectedObjects: []string{"logs/" + installlogarchive.InstallerConsoleLog, "logs/" + installlogarchive.InstallerLog},
+		},
+		{
+			name:       "installer logs and gathered logs",
+			gatherLogs: true,
+			expectedObjects: []string{
+				"logs/" + installlogarchive.InstallerConsoleLog,
+				"logs/" + installlogarchive.InstallerLog,
+				"logs/" + installlogarchive.GatherArtifacts,
+			},
+		},
+		{
+			name:        "failed uploads", // non-fatal
+			failUploads: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			uploaded := map[string][]byte{}
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if test.failUploads {
+					w.WriteHeader(http.StatusForbidden)
+					return
+				}
+				body, _ := ioutil.ReadAll(r.Body)
+				uploaded[strings.TrimPrefix(r.URL.Path, "/")] = body
+			}))
+			defer server.Close()
+
+			tempDir, err := ioutil.TempDir("", "installmanagertest")
+			require.NoError(t, err, "unexpected error creating temp dir")
+			defer os.RemoveAll(tempDir)
+			defer os.Remove(installerConsoleLogFilePath)
+
+			workDir := filepath.Join(tempDir, "output")
+			logsDir := filepath.Join(tempDir, "logs")
+			require.NoError(t, os.MkdirAll(workDir, 0755), "unexpected error creating work dir")
+			require.NoError(t, os.MkdirAll(filepath.Join(logsDir, "must-gather"), 0755), "unexpected error creating logs dir")
+			require.NoError(t, ioutil.WriteFile(installerConsoleLogFilePath, []byte("console log\n"), 0644), "unexpected error writing console log")
+			require.NoError(t, ioutil.WriteFile(filepath.Join(workDir, installerFullLogFile), []byte("full log\nadmin password: secret\n"), 0644), "unexpected error writing full log")
+			require.NoError(t, ioutil.WriteFile(filepath.Join(logsDir, "log-bundle-1.tar.gz"), []byte("bundle"), 0644), "unexpected error writing log bundle")
+			require.NoError(t, ioutil.WriteFile(filepath.Join(logsDir, "must-gather", "must-gather.log"), []byte("must-gather"), 0644), "unexpected error writing must-gather log")
+			require.NoError(t, ioutil.WriteFile(filepath.Join(logsDir, "old-log-bundle.tar.gz"), []byte("old bundle"), 0644), "unexpected error writing old log bundle")
+
+			fakeClient := fake.NewFakeClient(testClusterProvision())
+			im := InstallManager{
+				log:                    log.WithField("test", test.name),
+				WorkDir:                workDir,
+				LogsDir:                logsDir,
+				ClusterProvisionName:   testProvisionName,
+				Namespace:              testNamespace,
+				DynamicClient:          fakeClient,
+				updateClusterProvision: updateClusterProvisionWithRetries,
+				installLogArchiveUploads: &installlogarchive.Uploads{
+					Bucket: "install-logs",
+				},
+			}
+			for _, l := range installlogarchive.Logs {
+				im.installLogArchiveUploads.Objects = append(im.installLogArchiveUploads.Objects, installlogarchive.Upload{
+					Log: l,
+					Key: "logs/" + l,
+					URL: server.URL + "/logs/" + l,
+				})
+			}
+			if test.gatherLogs {
+				im.gatheredLogs = []string{filepath.Join(logsDir, "log-bundle-1.tar.gz"), filepath.Join(logsDir, "must-gather")}
+			}
+
+			provision := testClusterProvision()
+			im.archiveInstallLogs(provision, true)
+
+			require.NoError(t, fakeClient.Get(context.Background(), types.NamespacedName{Namespace: testNamespace, Name: testProvisionName}, provision))
+			if len(test.expectedObjects) == 0 {
+				assert.Nil(t, provision.Spec.InstallLogArchive, "expected install log archive to be empty")
+				return
+			}
+			if assert.NotNil(t, provision.Spec.InstallLogArchive, "expected install log archive to be set") {
+				assert.Equal(t, "install-logs", provision.Spec.InstallLogArchive.Bucket, "unexpected bucket")
+				assert.Equal(t, test.expectedObjects, provision.Spec.InstallLogArchive.Objects, "unexpected objects")
+			}
+			assert.Equal(t, "console log\n", string(uploaded["logs/"+installlogarchive.InstallerConsoleLog]), "unexpected console log")
+			assert.Equal(t, "full log\nREDACTED LINE OF OUTPUT\n", string(uploaded["logs/"+installlogarchive.InstallerLog]), "expected full log to be scrubbed")
+
+			if !test.gatherLogs {
+				return
+			}
+			gz, err := gzip.NewReader(bytes.NewReader(uploaded["logs/"+installlogarchive.GatherArtifacts]))
+			require.NoError(t, err, "unexpected error reading gathered logs")
+			tr := tar.NewReader(gz)
+			var names []string
+			for {
+				header, err := tr.Next()
+				if err == io.EOF {
+					break
+				}
+				require.NoError(t, err, "unexpected error reading gathered logs")
+				names = append(names, header.Name)
+			}
+			assert.Equal(t, []string{"log-bundle-1.tar.gz", "must-gather", "must-gather/must-gather.log"}, names, "unexpected gathered logs")
+		})
+	}
+}
+
+func TestDestroyersRegistered(t *testing.T) {
+	// The destroyers of these platforms are looked up in the registry of destroyers when cleaning up failed
+	// provisions, so they must be registered by the imports of the install manager.
+	for _, platform := range []string{installertypesopenstack.Name, installertypesvsphere.Name} {
+		_, ok := providers.Registry[platform]
+		assert.True(t, ok, "no destroyer registered for %s", platform)
+	}
+}
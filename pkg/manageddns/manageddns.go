@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"io/ioutil"
 	"os"
+	"strings"
 
 	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
 	"github.com/openshift/hive/pkg/constants"
@@ -29,3 +30,16 @@ func ReadManagedDomainsFile() ([]hivev1.ManageDNSConfig, error) {
 
 	return domains, nil
 }
+
+// FindManagedDomain returns the managed domain configuration of which the given domain is a direct child, or nil if the
+// domain is not a child of any of the managed domains.
+func FindManagedDomain(managedDomains []hivev1.ManageDNSConfig, domain string) *hivev1.ManageDNSConfig {
+	for i, md := range managedDomains {
+		for _, managedDomain := range md.Domains {
+			if childPart := strings.TrimSuffix(domain, "."+managedDomain); childPart != domain && !strings.Contains(childPart, ".") {
+				return &managedDomains[i]
+			}
+		}
+	}
+	return nil
+}
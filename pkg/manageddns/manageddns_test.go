@@ -0,0 +1,55 @@
+package manageddns
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+)
+
+func TestFindManagedDomain(t *testing.T) {
+	managedDomains := []hivev1.ManageDNSConfig{
+		{
+			Domains: []string{"aws.example.com"},
+			AWS:     &hivev1.ManageDNSAWSConfig{},
+		},
+		{
+			Domains: []string{"onprem.example.com", "lab.example.com"},
+			RFC2136: &hivev1.ManageDNSRFC2136Config{Server: "dns.example.com"},
+		},
+	}
+	cases := []struct {
+		name     string
+		domain   string
+		expected *hivev1.ManageDNSConfig
+	}{
+		{
+			name:     "child of first managed domain",
+			domain:   "cluster.aws.example.com",
+			expected: &managedDomains[0],
+		},
+		{
+			name:     "child of second domain of managed domain",
+			domain:   "cluster.lab.example.com",
+			expected: &managedDomains[1],
+		},
+		{
+			name:   "managed domain itself",
+			domain: "lab.example.com",
+		},
+		{
+			name:   "grandchild of managed domain",
+			domain: "apps.cluster.lab.example.com",
+		},
+		{
+			name:   "suffix of managed domain",
+			domain: "cluster.otherlab.example.com",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, FindManagedDomain(managedDomains, tc.domain), "unexpected managed domain")
+		})
+	}
+}
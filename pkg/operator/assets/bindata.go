@@ -7,13 +7,16 @@
 // config/apiserver/service-account.yaml
 // config/apiserver/service.yaml
 // config/hiveadmission/apiservice.yaml
+// config/hiveadmission/clusterclaim-webhook.yaml
 // config/hiveadmission/clusterdeployment-webhook.yaml
 // config/hiveadmission/clusterimageset-webhook.yaml
 // config/hiveadmission/clusterprovision-webhook.yaml
+// config/hiveadmission/clusterupgradepolicy-webhook.yaml
 // config/hiveadmission/deployment.yaml
 // config/hiveadmission/dnszones-webhook.yaml
 // config/hiveadmission/hiveadmission_rbac_role.yaml
 // config/hiveadmission/hiveadmission_rbac_role_binding.yaml
+// config/hiveadmission/installfailurerule-webhook.yaml
 // config/hiveadmission/machinepool-webhook.yaml
 // config/hiveadmission/selectorsyncset-webhook.yaml
 // config/hiveadmission/service-account.yaml
@@ -28,16 +31,23 @@
 // config/rbac/hive_frontend_role.yaml
 // config/rbac/hive_frontend_role_binding.yaml
 // config/rbac/hive_frontend_serviceaccount.yaml
+// config/rbac/hive_imageset_role.yaml
+// config/rbac/hive_imageset_role_binding.yaml
+// config/rbac/hive_imageset_serviceaccount.yaml
 // config/rbac/hive_reader_role.yaml
 // config/rbac/hive_reader_role_binding.yaml
 // config/crds/hive_v1_checkpoint.yaml
+// config/crds/hive_v1_clusterclaim.yaml
 // config/crds/hive_v1_clusterdeployment.yaml
 // config/crds/hive_v1_clusterdeprovision.yaml
 // config/crds/hive_v1_clusterimageset.yaml
+// config/crds/hive_v1_clusterpool.yaml
 // config/crds/hive_v1_clusterprovision.yaml
 // config/crds/hive_v1_clusterstate.yaml
+// config/crds/hive_v1_clusterupgradepolicy.yaml
 // config/crds/hive_v1_dnszone.yaml
 // config/crds/hive_v1_hiveconfig.yaml
+// config/crds/hive_v1_installfailurerule.yaml
 // config/crds/hive_v1_machinepool.yaml
 // config/crds/hive_v1_machinepoolnamelease.yaml
 // config/crds/hive_v1_selectorsyncidentityprovider.yaml
@@ -45,7 +55,7 @@
 // config/crds/hive_v1_syncidentityprovider.yaml
 // config/crds/hive_v1_syncset.yaml
 // config/crds/hive_v1_syncsetinstance.yaml
-// config/configmaps/install-log-regexes-configmap.yaml
+// config/installfailurerules/installfailurerules.yaml
 // DO NOT EDIT!
 
 package assets
@@ -355,6 +365,47 @@ func configHiveadmissionApiserviceYaml() (*asset, error) {
 	return a, nil
 }
 
+var _configHiveadmissionClusterclaimWebhookYaml = []byte(`---
+apiVersion: admissionregistration.k8s.io/v1beta1
+kind: ValidatingWebhookConfiguration
+metadata:
+  name: clusterclaimvalidators.admission.hive.openshift.io
+webhooks:
+- name: clusterclaimvalidators.admission.hive.openshift.io
+  clientConfig:
+    service:
+      # reach the webhook via the registered aggregated API
+      namespace: default
+      name: kubernetes
+      path: /apis/admission.hive.openshift.io/v1/clusterclaimvalidators
+  rules:
+  - operations:
+    - CREATE
+    - UPDATE
+    apiGroups:
+    - hive.openshift.io
+    apiVersions:
+    - v1
+    resources:
+    - clusterclaims
+  failurePolicy: Fail
+`)
+
+func configHiveadmissionClusterclaimWebhookYamlBytes() ([]byte, error) {
+	return _configHiveadmissionClusterclaimWebhookYaml, nil
+}
+
+func configHiveadmissionClusterclaimWebhookYaml() (*asset, error) {
+	bytes, err := configHiveadmissionClusterclaimWebhookYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "config/hiveadmission/clusterclaim-webhook.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 var _configHiveadmissionClusterdeploymentWebhookYaml = []byte(`---
 apiVersion: admissionregistration.k8s.io/v1beta1
 kind: ValidatingWebhookConfiguration
@@ -478,6 +529,47 @@ func configHiveadmissionClusterprovisionWebhookYaml() (*asset, error) {
 	return a, nil
 }
 
+var _configHiveadmissionClusterupgradepolicyWebhookYaml = []byte(`---
+apiVersion: admissionregistration.k8s.io/v1beta1
+kind: ValidatingWebhookConfiguration
+metadata:
+  name: clusterupgradepolicyvalidators.admission.hive.openshift.io
+webhooks:
+- name: clusterupgradepolicyvalidators.admission.hive.openshift.io
+  clientConfig:
+    service:
+      # reach the webhook via the registered aggregated API
+      namespace: default
+      name: kubernetes
+      path: /apis/admission.hive.openshift.io/v1/clusterupgradepolicyvalidators
+  rules:
+  - operations:
+    - CREATE
+    - UPDATE
+    apiGroups:
+    - hive.openshift.io
+    apiVersions:
+    - v1
+    resources:
+    - clusterupgradepolicies
+  failurePolicy: Fail
+`)
+
+func configHiveadmissionClusterupgradepolicyWebhookYamlBytes() ([]byte, error) {
+	return _configHiveadmissionClusterupgradepolicyWebhookYaml, nil
+}
+
+func configHiveadmissionClusterupgradepolicyWebhookYaml() (*asset, error) {
+	bytes, err := configHiveadmissionClusterupgradepolicyWebhookYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "config/hiveadmission/clusterupgradepolicy-webhook.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 var _configHiveadmissionDeploymentYaml = []byte(`---
 # to create the namespace-reservation-server
 apiVersion: apps/v1
@@ -681,6 +773,47 @@ func configHiveadmissionHiveadmission_rbac_role_bindingYaml() (*asset, error) {
 	return a, nil
 }
 
+var _configHiveadmissionInstallfailureruleWebhookYaml = []byte(`---
+apiVersion: admissionregistration.k8s.io/v1beta1
+kind: ValidatingWebhookConfiguration
+metadata:
+  name: installfailurerulevalidators.admission.hive.openshift.io
+webhooks:
+- name: installfailurerulevalidators.admission.hive.openshift.io
+  clientConfig:
+    service:
+      # reach the webhook via the registered aggregated API
+      namespace: default
+      name: kubernetes
+      path: /apis/admission.hive.openshift.io/v1/installfailurerulevalidators
+  rules:
+  - operations:
+    - CREATE
+    - UPDATE
+    apiGroups:
+    - hive.openshift.io
+    apiVersions:
+    - v1
+    resources:
+    - installfailurerules
+  failurePolicy: Fail
+`)
+
+func configHiveadmissionInstallfailureruleWebhookYamlBytes() ([]byte, error) {
+	return _configHiveadmissionInstallfailureruleWebhookYaml, nil
+}
+
+func configHiveadmissionInstallfailureruleWebhookYaml() (*asset, error) {
+	bytes, err := configHiveadmissionInstallfailureruleWebhookYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "config/hiveadmission/installfailurerule-webhook.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 var _configHiveadmissionMachinepoolWebhookYaml = []byte(`---
 apiVersion: admissionregistration.k8s.io/v1beta1
 kind: ValidatingWebhookConfiguration
@@ -995,6 +1128,8 @@ rules:
   - syncsets
   - syncsetinstances
   - clusterdeprovisions
+  - clusterpools
+  - clusterclaims
   # TODO: remove once v1alpha1 compat removed
   - clusterdeprovisionrequests
   - clusterstates
@@ -1006,7 +1141,9 @@ rules:
   - hive.openshift.io
   resources:
   - clusterimagesets
+  - clusterupgradepolicies
   - hiveconfigs
+  - installfailurerules
   - selectorsyncsets
   - selectorsyncidentityproviders
   verbs:
@@ -1023,7 +1160,9 @@ rules:
   - clusterdeployments
   - clusterimagesets
   - clusterprovisions
+  - clusterupgradepolicies
   - dnszones
+  - installfailurerules
   - machinepools
   - selectorsyncsets
   - syncsets
@@ -1146,11 +1285,20 @@ rules:
   - ""
   resources:
   - pods
+  verbs:
+  - get
+  - list
+  - watch
+- apiGroups:
+  - ""
+  resources:
   - namespaces
   verbs:
   - get
   - list
   - watch
+  - create
+  - delete
 - apiGroups:
   - rbac.authorization.k8s.io
   resources:
@@ -1178,6 +1326,50 @@ rules:
   - update
   - patch
   - delete
+- apiGroups:
+  - hive.openshift.io
+  resources:
+  - clusterclaims
+  - clusterclaims/status
+  - clusterclaims/finalizers
+  verbs:
+  - get
+  - list
+  - watch
+  - update
+  - patch
+  - delete
+- apiGroups:
+  - hive.openshift.io
+  resources:
+  - clusterpools
+  - clusterpools/status
+  - clusterpools/finalizers
+  verbs:
+  - get
+  - list
+  - watch
+  - update
+  - patch
+- apiGroups:
+  - hive.openshift.io
+  resources:
+  - installfailurerules
+  verbs:
+  - get
+  - list
+  - watch
+- apiGroups:
+  - hive.openshift.io
+  resources:
+  - clusterupgradepolicies
+  - clusterupgradepolicies/status
+  verbs:
+  - get
+  - list
+  - watch
+  - update
+  - patch
 - apiGroups:
   - hive.openshift.io
   resources:
@@ -1503,6 +1695,8 @@ rules:
   - selectorsyncsets
   - syncsets
   - clusterdeprovisions
+  - clusterpools
+  - clusterclaims
   # TODO: remove once v1alpha1 compat removed
   - clusterdeprovisionrequests
   - clusterstates
@@ -1518,7 +1712,9 @@ rules:
   - hive.openshift.io
   resources:
   - clusterimagesets
+  - clusterupgradepolicies
   - hiveconfigs
+  - installfailurerules
   verbs:
   - get
   - list
@@ -1594,6 +1790,95 @@ func configRbacHive_frontend_serviceaccountYaml() (*asset, error) {
 	return a, nil
 }
 
+var _configRbacHive_imageset_roleYaml = []byte(`# hive-imageset is the role of the jobs resolving the release images of ClusterImageSets, which record the
+# metadata of the release images in the status of the ClusterImageSets.
+apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRole
+metadata:
+  name: hive-imageset
+rules:
+- apiGroups:
+  - hive.openshift.io
+  resources:
+  - clusterimagesets
+  verbs:
+  - get
+- apiGroups:
+  - hive.openshift.io
+  resources:
+  - clusterimagesets/status
+  verbs:
+  - get
+  - update
+`)
+
+func configRbacHive_imageset_roleYamlBytes() ([]byte, error) {
+	return _configRbacHive_imageset_roleYaml, nil
+}
+
+func configRbacHive_imageset_roleYaml() (*asset, error) {
+	bytes, err := configRbacHive_imageset_roleYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "config/rbac/hive_imageset_role.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _configRbacHive_imageset_role_bindingYaml = []byte(`apiVersion: rbac.authorization.k8s.io/v1
+kind: ClusterRoleBinding
+metadata:
+  creationTimestamp: null
+  name: hive-imageset
+roleRef:
+  apiGroup: rbac.authorization.k8s.io
+  kind: ClusterRole
+  name: hive-imageset
+subjects:
+- kind: ServiceAccount
+  name: hive-imageset
+  namespace: hive
+`)
+
+func configRbacHive_imageset_role_bindingYamlBytes() ([]byte, error) {
+	return _configRbacHive_imageset_role_bindingYaml, nil
+}
+
+func configRbacHive_imageset_role_bindingYaml() (*asset, error) {
+	bytes, err := configRbacHive_imageset_role_bindingYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "config/rbac/hive_imageset_role_binding.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _configRbacHive_imageset_serviceaccountYaml = []byte(`apiVersion: v1
+kind: ServiceAccount
+metadata:
+  name: hive-imageset
+  namespace: hive
+`)
+
+func configRbacHive_imageset_serviceaccountYamlBytes() ([]byte, error) {
+	return _configRbacHive_imageset_serviceaccountYaml, nil
+}
+
+func configRbacHive_imageset_serviceaccountYaml() (*asset, error) {
+	bytes, err := configRbacHive_imageset_serviceaccountYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "config/rbac/hive_imageset_serviceaccount.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 var _configRbacHive_reader_roleYaml = []byte(`# hive-admin is a role intended for hive administrators who need to be able to debug
 # cluster installations, and modify hive configuration.
 apiVersion: rbac.authorization.k8s.io/v1
@@ -1631,6 +1916,8 @@ rules:
   - syncsets
   - syncsetinstances
   - clusterdeprovisions
+  - clusterpools
+  - clusterclaims
   # TODO: remove once v1alpha1 compat removed
   - clusterdeprovisionrequests
   - clusterstates
@@ -1642,7 +1929,9 @@ rules:
   - hive.openshift.io
   resources:
   - clusterimagesets
+  - clusterupgradepolicies
   - hiveconfigs
+  - installfailurerules
   verbs:
   - get
   - list
@@ -1779,39 +2068,31 @@ func configCrdsHive_v1_checkpointYaml() (*asset, error) {
 	return a, nil
 }
 
-var _configCrdsHive_v1_clusterdeploymentYaml = []byte(`apiVersion: apiextensions.k8s.io/v1beta1
+var _configCrdsHive_v1_clusterclaimYaml = []byte(`apiVersion: apiextensions.k8s.io/v1beta1
 kind: CustomResourceDefinition
 metadata:
   creationTimestamp: null
   labels:
     controller-tools.k8s.io: "1.0"
-  name: clusterdeployments.hive.openshift.io
+  name: clusterclaims.hive.openshift.io
 spec:
   additionalPrinterColumns:
-  - JSONPath: .spec.clusterName
-    name: ClusterName
-    type: string
-  - JSONPath: .metadata.labels.hive\.openshift\.io/cluster-type
-    name: ClusterType
+  - JSONPath: .spec.clusterPoolName
+    name: Pool
     type: string
-  - JSONPath: .spec.baseDomain
-    name: BaseDomain
+  - JSONPath: .status.conditions[?(@.type=='Pending')].reason
+    name: Pending
     type: string
-  - JSONPath: .spec.installed
-    name: Installed
-    type: boolean
-  - JSONPath: .spec.clusterMetadata.infraID
-    name: InfraID
+  - JSONPath: .spec.namespace
+    name: ClusterNamespace
     type: string
   - JSONPath: .metadata.creationTimestamp
     name: Age
     type: date
   group: hive.openshift.io
   names:
-    kind: ClusterDeployment
-    plural: clusterdeployments
-    shortNames:
-    - cd
+    kind: ClusterClaim
+    plural: clusterclaims
   scope: Namespaced
   subresources:
     status: {}
@@ -1832,45 +2113,184 @@ spec:
           type: object
         spec:
           properties:
-            baseDomain:
-              description: BaseDomain is the base domain to which the cluster should
-                belong.
+            clusterPoolName:
+              description: ClusterPoolName is the name of the cluster pool from which
+                to claim a cluster. The pool must be in the same namespace as the
+                claim.
               type: string
-            certificateBundles:
-              description: CertificateBundles is a list of certificate bundles associated
-                with this cluster
+            lifetime:
+              description: Lifetime is the maximum lifetime of the claim after it
+                is assigned a cluster. If the claim still exists when the lifetime
+                has elapsed, the claim will be deleted by Hive, deprovisioning the
+                claimed cluster.
+              type: string
+            namespace:
+              description: Namespace is the namespace containing the ClusterDeployment
+                (name will match the namespace) of the claimed cluster. This field
+                will be set by Hive as soon as a suitable cluster can be found.
+              type: string
+            subjects:
+              description: Subjects hold references to which to authorize access to
+                the claimed cluster.
               items:
-                properties:
-                  certificateSecretRef:
-                    description: CertificateSecretRef is the reference to the secret
-                      that contains the certificate bundle. If the certificate bundle
-                      is to be generated, it will be generated with the name in this
-                      reference. Otherwise, it is expected that the secret should
-                      exist in the same namespace as the ClusterDeployment
-                    type: object
-                  generate:
-                    description: Generate indicates whether this bundle should have
-                      real certificates generated for it.
-                    type: boolean
-                  name:
-                    description: Name is an identifier that must be unique within
-                      the bundle and must be referenced by an ingress or by the control
-                      plane serving certs
-                    type: string
-                required:
-                - name
                 type: object
               type: array
-            clusterMetadata:
-              description: ClusterMetadata contains metadata information about the
-                installed cluster.
-              properties:
-                adminKubeconfigSecretRef:
-                  description: AdminKubeconfigSecretRef references the secret containing
-                    the admin kubeconfig for this cluster.
-                  type: object
-                adminPasswordSecretRef:
-                  description: AdminPasswordSecretRef references the secret containing
+          required:
+          - clusterPoolName
+          type: object
+        status:
+          properties:
+            conditions:
+              description: Conditions includes more detailed status for the cluster
+                claim.
+              items:
+                properties:
+                  lastProbeTime:
+                    description: LastProbeTime is the last time we probed the condition.
+                    format: date-time
+                    type: string
+                  lastTransitionTime:
+                    description: LastTransitionTime is the last time the condition
+                      transitioned from one status to another.
+                    format: date-time
+                    type: string
+                  message:
+                    description: Message is a human-readable message indicating details
+                      about last transition.
+                    type: string
+                  reason:
+                    description: Reason is a unique, one-word, CamelCase reason for
+                      the condition's last transition.
+                    type: string
+                  status:
+                    description: Status is the status of the condition.
+                    type: string
+                  type:
+                    description: Type is the type of the condition.
+                    type: string
+                type: object
+              type: array
+          type: object
+  version: v1
+status:
+  acceptedNames:
+    kind: ""
+    plural: ""
+  conditions: []
+  storedVersions: []
+`)
+
+func configCrdsHive_v1_clusterclaimYamlBytes() ([]byte, error) {
+	return _configCrdsHive_v1_clusterclaimYaml, nil
+}
+
+func configCrdsHive_v1_clusterclaimYaml() (*asset, error) {
+	bytes, err := configCrdsHive_v1_clusterclaimYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "config/crds/hive_v1_clusterclaim.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _configCrdsHive_v1_clusterdeploymentYaml = []byte(`apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  creationTimestamp: null
+  labels:
+    controller-tools.k8s.io: "1.0"
+  name: clusterdeployments.hive.openshift.io
+spec:
+  additionalPrinterColumns:
+  - JSONPath: .spec.clusterName
+    name: ClusterName
+    type: string
+  - JSONPath: .metadata.labels.hive\.openshift\.io/cluster-type
+    name: ClusterType
+    type: string
+  - JSONPath: .spec.baseDomain
+    name: BaseDomain
+    type: string
+  - JSONPath: .spec.installed
+    name: Installed
+    type: boolean
+  - JSONPath: .spec.clusterMetadata.infraID
+    name: InfraID
+    type: string
+  - JSONPath: .status.powerState
+    name: PowerState
+    type: string
+  - JSONPath: .metadata.creationTimestamp
+    name: Age
+    type: date
+  group: hive.openshift.io
+  names:
+    kind: ClusterDeployment
+    plural: clusterdeployments
+    shortNames:
+    - cd
+  scope: Namespaced
+  subresources:
+    status: {}
+  validation:
+    openAPIV3Schema:
+      properties:
+        apiVersion:
+          description: 'APIVersion defines the versioned schema of this representation
+            of an object. Servers should convert recognized schemas to the latest
+            internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources'
+          type: string
+        kind:
+          description: 'Kind is a string value representing the REST resource this
+            object represents. Servers may infer this from the endpoint the client
+            submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds'
+          type: string
+        metadata:
+          type: object
+        spec:
+          properties:
+            baseDomain:
+              description: BaseDomain is the base domain to which the cluster should
+                belong.
+              type: string
+            certificateBundles:
+              description: CertificateBundles is a list of certificate bundles associated
+                with this cluster
+              items:
+                properties:
+                  certificateSecretRef:
+                    description: CertificateSecretRef is the reference to the secret
+                      that contains the certificate bundle. If the certificate bundle
+                      is to be generated, it will be generated with the name in this
+                      reference. Otherwise, it is expected that the secret should
+                      exist in the same namespace as the ClusterDeployment
+                    type: object
+                  generate:
+                    description: Generate indicates whether this bundle should have
+                      real certificates generated for it. Certificates are generated
+                      from the ACME server configured in HiveConfig.
+                    type: boolean
+                  name:
+                    description: Name is an identifier that must be unique within
+                      the bundle and must be referenced by an ingress or by the control
+                      plane serving certs
+                    type: string
+                required:
+                - name
+                type: object
+              type: array
+            clusterMetadata:
+              description: ClusterMetadata contains metadata information about the
+                installed cluster.
+              properties:
+                adminKubeconfigSecretRef:
+                  description: AdminKubeconfigSecretRef references the secret containing
+                    the admin kubeconfig for this cluster.
+                  type: object
+                adminPasswordSecretRef:
+                  description: AdminPasswordSecretRef references the secret containing
                     the admin username/password which can be used to login to this
                     cluster.
                   type: object
@@ -1879,6 +2299,13 @@ spec:
                     cluster generated during installation. Used for reporting metrics
                     among other places.
                   type: string
+                hiveManagedKubeconfigSecretRef:
+                  description: HiveManagedKubeconfigSecretRef references the secret
+                    containing the kubeconfig minted by Hive for this cluster after
+                    install. When set, Hive connects to the cluster with this kubeconfig
+                    instead of the admin kubeconfig, so the admin kubeconfig secret
+                    may be stored away or deleted.
+                  type: object
                 infraID:
                   description: InfraID is an identifier for this cluster generated
                     during installation and used for tagging/naming resources in cloud
@@ -1890,6 +2317,22 @@ spec:
                 used for subdomains, some resource tagging, and other instances where
                 a friendly name for the cluster is useful.
               type: string
+            clusterPoolRef:
+              description: ClusterPoolRef is a reference to the ClusterPool that this
+                ClusterDeployment originated from.
+              properties:
+                claimName:
+                  description: ClaimName is the name of the ClusterClaim that claimed
+                    the cluster from the pool.
+                  type: string
+                namespace:
+                  description: Namespace is the namespace where the ClusterPool resides.
+                  type: string
+                poolName:
+                  description: PoolName is the name of the ClusterPool for which the
+                    cluster was created.
+                  type: string
+              type: object
             controlPlaneConfig:
               description: ControlPlaneConfig contains additional configuration for
                 the target cluster's control plane
@@ -1905,6 +2348,49 @@ spec:
                     use the override URL for further communications with the API server
                     of the remote cluster.
                   type: string
+                proxy:
+                  description: Proxy is the optional proxy through which Hive will
+                    connect to the API server of the remote cluster. This allows Hive
+                    to manage clusters whose API server can only be reached through
+                    a corporate proxy, or through an SSH bastion host.
+                  properties:
+                    credentialsSecretRef:
+                      description: CredentialsSecretRef refers to a secret in the
+                        namespace of the ClusterDeployment with the "username" and
+                        "password" used to authenticate with the proxy at the URL.
+                      type: object
+                    sshBastion:
+                      description: SSHBastion is the SSH bastion host through which
+                        connections to the API server are tunneled.
+                      properties:
+                        host:
+                          description: Host is the host of the bastion, with an optional
+                            port which defaults to 22.
+                          type: string
+                        knownHosts:
+                          description: KnownHosts are the known_hosts entries with
+                            the host keys of the bastion. Connections to the bastion
+                            are only made when the bastion presents one of these host
+                            keys. Hashed host names are not supported.
+                          items:
+                            type: string
+                          type: array
+                        privateKeySecretRef:
+                          description: PrivateKeySecretRef refers to a secret in the
+                            namespace of the ClusterDeployment with the SSH private
+                            key used to log in to the bastion. The SSH private key
+                            is expected to be in the secret data under the "ssh-privatekey"
+                            key.
+                          type: object
+                        user:
+                          description: User is the user to log in to the bastion as.
+                          type: string
+                      type: object
+                    url:
+                      description: URL is the URL of the proxy. The http, https and
+                        socks5 schemes are supported.
+                      type: string
+                  type: object
                 servingCertificates:
                   description: ServingCertificates specifies serving certificates
                     for the control plane
@@ -1966,6 +2452,49 @@ spec:
             installed:
               description: Installed is true if the cluster has been installed
               type: boolean
+            kubeadminPassword:
+              description: KubeadminPassword configures the management of the kubeadmin
+                user of the cluster once it is installed.
+              properties:
+                removeAfterIdentityProviders:
+                  description: RemoveAfterIdentityProviders dictates if the kubeadmin
+                    user is removed from the cluster once the identity providers of
+                    the SyncIdentityProviders and SelectorSyncIdentityProviders matching
+                    the cluster have been applied successfully.
+                  type: boolean
+                rotationInterval:
+                  description: RotationInterval is how often the kubeadmin password
+                    is rotated. The password is not rotated on a schedule when unset.
+                  type: string
+                rotationRequestedAt:
+                  description: RotationRequestedAt requests a rotation of the kubeadmin
+                    password. The password is rotated once this time has passed, unless
+                    it has been rotated since.
+                  format: date-time
+                  type: string
+              type: object
+            maintenanceWindows:
+              description: MaintenanceWindows are the recurring periods of time during
+                which an upgrade of the cluster may be started. Upgrades may be started
+                at any time when no maintenance window is specified.
+              items:
+                properties:
+                  days:
+                    description: Days are the days of the week on which the window
+                      starts, such as Saturday. The window starts every day when no
+                      day is specified.
+                    items:
+                      type: string
+                    type: array
+                  duration:
+                    description: Duration is how long the window lasts.
+                    type: string
+                  startTime:
+                    description: StartTime is the time of day at which the window
+                      starts, in UTC, formatted as HH:MM.
+                    type: string
+                type: object
+              type: array
             manageDNS:
               description: ManageDNS specifies whether a DNSZone should be created
                 and managed automatically for this ClusterDeployment
@@ -2032,7 +2561,72 @@ spec:
                         will be created.
                       type: string
                   type: object
+                openstack:
+                  description: OpenStack is the configuration used when installing
+                    on OpenStack
+                  properties:
+                    cloud:
+                      description: Cloud will be used to indicate the OS_CLOUD value
+                        to use the right section from the clouds.yaml in the CredentialsSecretRef.
+                      type: string
+                    credentialsSecretRef:
+                      description: CredentialsSecretRef refers to a secret that contains
+                        the OpenStack account access credentials. The credentials
+                        are expected in a clouds.yaml file stored under the "clouds.yaml"
+                        key of the secret.
+                      type: object
+                    trunkSupport:
+                      description: TrunkSupport indicates whether or not to use trunk
+                        ports in your OpenShift cluster.
+                      type: boolean
+                  type: object
+                vsphere:
+                  description: VSphere is the configuration used when installing on
+                    vSphere
+                  properties:
+                    certificatesSecretRef:
+                      description: CertificatesSecretRef refers to a secret that contains
+                        the vSphere CA certificates necessary for communicating with
+                        the VCenter.
+                      type: object
+                    cluster:
+                      description: Cluster is the name of the cluster virtual machines
+                        will be cloned into.
+                      type: string
+                    credentialsSecretRef:
+                      description: CredentialsSecretRef refers to a secret that contains
+                        the vSphere account access credentials. The username and password
+                        are expected under the "username" and "password" keys of the
+                        secret.
+                      type: object
+                    datacenter:
+                      description: Datacenter is the name of the datacenter to use
+                        in the vCenter.
+                      type: string
+                    defaultDatastore:
+                      description: DefaultDatastore is the default datastore to use
+                        for provisioning volumes.
+                      type: string
+                    folder:
+                      description: Folder is the name of the folder that will be used
+                        and/or created for virtual machines.
+                      type: string
+                    network:
+                      description: Network specifies the name of the network to be
+                        used by the cluster.
+                      type: string
+                    vCenter:
+                      description: VCenter is the domain name or IP address of the
+                        vCenter.
+                      type: string
+                  type: object
               type: object
+            powerState:
+              description: PowerState indicates whether a cluster should be running
+                or hibernating. When omitted, PowerState defaults to the Running state.
+              enum:
+              - Running;Hibernating
+              type: string
             preserveOnDelete:
               description: PreserveOnDelete allows the user to disconnect a cluster
                 from Hive without deprovisioning it
@@ -2099,6 +2693,36 @@ spec:
               description: PullSecretRef is the reference to the secret to use when
                 pulling images.
               type: object
+            rfc2136TSIGKeySecretRef:
+              description: RFC2136TSIGKeySecretRef references a secret containing
+                the TSIG key used to update the zone of the cluster when DNS is managed
+                in a domain hosted on a DNS server updated through RFC2136 dynamic
+                updates. The key must only be allowed to update the zone of the cluster.
+                It is required to manage DNS in such a domain.
+              type: object
+            upgrade:
+              description: Upgrade requests an upgrade of the installed cluster to
+                another release.
+              properties:
+                force:
+                  description: Force upgrades the cluster even if the release image
+                    fails verification, or the cluster reports that the upgrade is
+                    not supported.
+                  type: boolean
+                imageSetRef:
+                  description: ImageSetRef is a reference to a ClusterImageSet with
+                    the release image to upgrade the cluster to.
+                  properties:
+                    name:
+                      description: Name is the name of the ClusterImageSet that this
+                        refers to
+                      type: string
+                  type: object
+                releaseImage:
+                  description: ReleaseImage is the release image to upgrade the cluster
+                    to. It takes precedence over ImageSetRef.
+                  type: string
+              type: object
           required:
           - clusterName
           - baseDomain
@@ -2121,6 +2745,14 @@ spec:
                   name:
                     description: Name of the certificate bundle
                     type: string
+                  notAfter:
+                    description: NotAfter is the expiry time of the generated certificate.
+                    format: date-time
+                    type: string
+                  orderURL:
+                    description: OrderURL is the URL of the pending order of a certificate
+                      from the ACME server while the certificate bundle is being generated.
+                    type: string
                 type: object
               type: array
             cliImage:
@@ -2333,6 +2965,24 @@ spec:
               description: InstallerImage is the name of the installer image to use
                 when installing the target cluster
               type: string
+            kubeadminPassword:
+              description: KubeadminPassword is the status of the management of the
+                kubeadmin user of the cluster.
+              properties:
+                lastRotationTime:
+                  description: LastRotationTime is the last time the kubeadmin password
+                    was rotated.
+                  format: date-time
+                  type: string
+                removed:
+                  description: Removed is true once the kubeadmin user has been removed
+                    from the cluster.
+                  type: boolean
+              type: object
+            powerState:
+              description: PowerState is the observed power state of the cluster.
+                It is only set once a cluster has been hibernated.
+              type: string
             provisionRef:
               description: ProvisionRef is a reference to the last ClusterProvision
                 created for the deployment
@@ -2455,6 +3105,34 @@ spec:
                       description: Region is the GCP region for this deprovision
                       type: string
                   type: object
+                openstack:
+                  description: OpenStack contains OpenStack-specific deprovision settings
+                  properties:
+                    cloud:
+                      description: Cloud is the section in the clouds.yaml secret
+                        to use for auth/connectivity.
+                      type: string
+                    credentialsSecretRef:
+                      description: CredentialsSecretRef is the OpenStack account credentials
+                        to use for deprovisioning the cluster
+                      type: object
+                  type: object
+                vsphere:
+                  description: VSphere contains vSphere-specific deprovision settings
+                  properties:
+                    certificatesSecretRef:
+                      description: CertificatesSecretRef refers to a secret that contains
+                        the vSphere CA certificates necessary for communicating with
+                        the VCenter.
+                      type: object
+                    credentialsSecretRef:
+                      description: CredentialsSecretRef is the vSphere account credentials
+                        to use for deprovisioning the cluster
+                      type: object
+                    vCenter:
+                      description: VCenter is the vSphere vCenter hostname.
+                      type: string
+                  type: object
               type: object
           type: object
         status:
@@ -2499,6 +3177,12 @@ spec:
   - JSONPath: .spec.releaseImage
     name: Release
     type: string
+  - JSONPath: .status.version
+    name: Version
+    type: string
+  - JSONPath: .status.conditions[?(@.type=='Valid')].status
+    name: Valid
+    type: string
   group: hive.openshift.io
   names:
     kind: ClusterImageSet
@@ -2531,30 +3215,330 @@ spec:
               type: string
           type: object
         status:
-          type: object
-  version: v1
-status:
-  acceptedNames:
-    kind: ""
-    plural: ""
-  conditions: []
-  storedVersions: []
-`)
-
-func configCrdsHive_v1_clusterimagesetYamlBytes() ([]byte, error) {
-	return _configCrdsHive_v1_clusterimagesetYaml, nil
-}
-
-func configCrdsHive_v1_clusterimagesetYaml() (*asset, error) {
-	bytes, err := configCrdsHive_v1_clusterimagesetYamlBytes()
-	if err != nil {
-		return nil, err
-	}
-
-	info := bindataFileInfo{name: "config/crds/hive_v1_clusterimageset.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
-	a := &asset{bytes: bytes, info: info}
-	return a, nil
-}
+          properties:
+            bareMetalInstallerImage:
+              description: BareMetalInstallerImage is the installer image of the release
+                used for bare metal installs, referenced by digest.
+              type: string
+            channel:
+              description: Channel is the upgrade graph channel of the release, such
+                as stable-4.5.
+              type: string
+            cliImage:
+              description: CLIImage is the cli image of the release, referenced by
+                digest.
+              type: string
+            conditions:
+              description: Conditions includes more detailed status for the cluster
+                image set.
+              items:
+                properties:
+                  lastProbeTime:
+                    description: LastProbeTime is the last time we probed the condition.
+                    format: date-time
+                    type: string
+                  lastTransitionTime:
+                    description: LastTransitionTime is the last time the condition
+                      transitioned from one status to another.
+                    format: date-time
+                    type: string
+                  message:
+                    description: Message is a human-readable message indicating details
+                      about last transition.
+                    type: string
+                  reason:
+                    description: Reason is a unique, one-word, CamelCase reason for
+                      the condition's last transition.
+                    type: string
+                  status:
+                    description: Status is the status of the condition.
+                    type: string
+                  type:
+                    description: Type is the type of the condition.
+                    type: string
+                type: object
+              type: array
+            installerImage:
+              description: InstallerImage is the installer image of the release, referenced
+                by digest.
+              type: string
+            releaseImage:
+              description: ReleaseImage is the release image from which the metadata
+                in the status was resolved.
+              type: string
+            version:
+              description: Version is the OpenShift version of the release.
+              type: string
+          type: object
+  version: v1
+status:
+  acceptedNames:
+    kind: ""
+    plural: ""
+  conditions: []
+  storedVersions: []
+`)
+
+func configCrdsHive_v1_clusterimagesetYamlBytes() ([]byte, error) {
+	return _configCrdsHive_v1_clusterimagesetYaml, nil
+}
+
+func configCrdsHive_v1_clusterimagesetYaml() (*asset, error) {
+	bytes, err := configCrdsHive_v1_clusterimagesetYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "config/crds/hive_v1_clusterimageset.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _configCrdsHive_v1_clusterpoolYaml = []byte(`apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  creationTimestamp: null
+  labels:
+    controller-tools.k8s.io: "1.0"
+  name: clusterpools.hive.openshift.io
+spec:
+  additionalPrinterColumns:
+  - JSONPath: .spec.size
+    name: Size
+    type: integer
+  - JSONPath: .status.ready
+    name: Ready
+    type: integer
+  - JSONPath: .status.installing
+    name: Installing
+    type: integer
+  - JSONPath: .spec.baseDomain
+    name: BaseDomain
+    type: string
+  - JSONPath: .spec.imageSetRef.name
+    name: ImageSet
+    type: string
+  group: hive.openshift.io
+  names:
+    kind: ClusterPool
+    plural: clusterpools
+    shortNames:
+    - cp
+  scope: Namespaced
+  subresources:
+    scale:
+      specReplicasPath: .spec.size
+      statusReplicasPath: .status.size
+    status: {}
+  validation:
+    openAPIV3Schema:
+      properties:
+        apiVersion:
+          description: 'APIVersion defines the versioned schema of this representation
+            of an object. Servers should convert recognized schemas to the latest
+            internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources'
+          type: string
+        kind:
+          description: 'Kind is a string value representing the REST resource this
+            object represents. Servers may infer this from the endpoint the client
+            submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds'
+          type: string
+        metadata:
+          type: object
+        spec:
+          properties:
+            baseDomain:
+              description: BaseDomain is the base domain to use for all clusters created
+                in this pool.
+              type: string
+            imageSetRef:
+              description: ImageSetRef is a reference to a ClusterImageSet. The release
+                image specified in the ClusterImageSet will be used by clusters created
+                for this cluster pool.
+              properties:
+                name:
+                  description: Name is the name of the ClusterImageSet that this refers
+                    to
+                  type: string
+              type: object
+            platform:
+              description: Platform encompasses the desired platform for the cluster.
+              properties:
+                aws:
+                  description: AWS is the configuration used when installing on AWS.
+                  properties:
+                    credentialsSecretRef:
+                      description: CredentialsSecretRef refers to a secret that contains
+                        the AWS account access credentials.
+                      type: object
+                    region:
+                      description: Region specifies the AWS region where the cluster
+                        will be created.
+                      type: string
+                    userTags:
+                      description: UserTags specifies additional tags for AWS resources
+                        created for the cluster.
+                      type: object
+                  type: object
+                azure:
+                  description: Azure is the configuration used when installing on
+                    Azure.
+                  properties:
+                    baseDomainResourceGroupName:
+                      description: BaseDomainResourceGroupName specifies the resource
+                        group where the azure DNS zone for the base domain is found
+                      type: string
+                    credentialsSecretRef:
+                      description: CredentialsSecretRef refers to a secret that contains
+                        the Azure account access credentials.
+                      type: object
+                    region:
+                      description: Region specifies the Azure region where the cluster
+                        will be created.
+                      type: string
+                  type: object
+                bareMetal:
+                  description: BareMetal is the configuration used when installing
+                    on bare metal.
+                  properties:
+                    libvirtSSHPrivateKeySecretRef:
+                      description: LibvirtSSHPrivateKeySecretRef is the reference
+                        to the secret that contains the private SSH key to use for
+                        access to the libvirt provisioning host. The SSH private key
+                        is expected to be in the secret data under the "ssh-privatekey"
+                        key.
+                      type: object
+                  type: object
+                gcp:
+                  description: GCP is the configuration used when installing on Google
+                    Cloud Platform.
+                  properties:
+                    credentialsSecretRef:
+                      description: CredentialsSecretRef refers to a secret that contains
+                        the GCP account access credentials.
+                      type: object
+                    region:
+                      description: Region specifies the GCP region where the cluster
+                        will be created.
+                      type: string
+                  type: object
+                openstack:
+                  description: OpenStack is the configuration used when installing
+                    on OpenStack
+                  properties:
+                    cloud:
+                      description: Cloud will be used to indicate the OS_CLOUD value
+                        to use the right section from the clouds.yaml in the CredentialsSecretRef.
+                      type: string
+                    credentialsSecretRef:
+                      description: CredentialsSecretRef refers to a secret that contains
+                        the OpenStack account access credentials. The credentials
+                        are expected in a clouds.yaml file stored under the "clouds.yaml"
+                        key of the secret.
+                      type: object
+                    trunkSupport:
+                      description: TrunkSupport indicates whether or not to use trunk
+                        ports in your OpenShift cluster.
+                      type: boolean
+                  type: object
+                vsphere:
+                  description: VSphere is the configuration used when installing on
+                    vSphere
+                  properties:
+                    certificatesSecretRef:
+                      description: CertificatesSecretRef refers to a secret that contains
+                        the vSphere CA certificates necessary for communicating with
+                        the VCenter.
+                      type: object
+                    cluster:
+                      description: Cluster is the name of the cluster virtual machines
+                        will be cloned into.
+                      type: string
+                    credentialsSecretRef:
+                      description: CredentialsSecretRef refers to a secret that contains
+                        the vSphere account access credentials. The username and password
+                        are expected under the "username" and "password" keys of the
+                        secret.
+                      type: object
+                    datacenter:
+                      description: Datacenter is the name of the datacenter to use
+                        in the vCenter.
+                      type: string
+                    defaultDatastore:
+                      description: DefaultDatastore is the default datastore to use
+                        for provisioning volumes.
+                      type: string
+                    folder:
+                      description: Folder is the name of the folder that will be used
+                        and/or created for virtual machines.
+                      type: string
+                    network:
+                      description: Network specifies the name of the network to be
+                        used by the cluster.
+                      type: string
+                    vCenter:
+                      description: VCenter is the domain name or IP address of the
+                        vCenter.
+                      type: string
+                  type: object
+              type: object
+            pullSecretRef:
+              description: PullSecretRef is the reference to the secret to use when
+                pulling images.
+              type: object
+            size:
+              description: Size is the number of unclaimed clusters to keep installed
+                and ready in the pool.
+              format: int32
+              minimum: 0
+              type: integer
+          required:
+          - platform
+          - size
+          - baseDomain
+          - imageSetRef
+          type: object
+        status:
+          properties:
+            installing:
+              description: Installing is the number of unclaimed clusters that are
+                still being installed.
+              format: int32
+              type: integer
+            ready:
+              description: Ready is the number of unclaimed clusters that have been
+                installed and are ready to be claimed.
+              format: int32
+              type: integer
+            size:
+              description: Size is the number of unclaimed clusters that have been
+                created for the pool.
+              format: int32
+              type: integer
+          type: object
+  version: v1
+status:
+  acceptedNames:
+    kind: ""
+    plural: ""
+  conditions: []
+  storedVersions: []
+`)
+
+func configCrdsHive_v1_clusterpoolYamlBytes() ([]byte, error) {
+	return _configCrdsHive_v1_clusterpoolYaml, nil
+}
+
+func configCrdsHive_v1_clusterpoolYaml() (*asset, error) {
+	bytes, err := configCrdsHive_v1_clusterpoolYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "config/crds/hive_v1_clusterpool.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
 
 var _configCrdsHive_v1_clusterprovisionYaml = []byte(`apiVersion: apiextensions.k8s.io/v1beta1
 kind: CustomResourceDefinition
@@ -2627,6 +3611,21 @@ spec:
             installLog:
               description: InstallLog is the log from the installer.
               type: string
+            installLogArchive:
+              description: InstallLogArchive references the logs of the provision
+                stored in the install log archive configured in HiveConfig.
+              properties:
+                bucket:
+                  description: Bucket is the bucket of the install log archive holding
+                    the objects.
+                  type: string
+                objects:
+                  description: Objects are the keys of the objects holding the logs
+                    of the provision.
+                  items:
+                    type: string
+                  type: array
+              type: object
             metadata:
               description: Metadata is the metadata.json generated by the installer,
                 providing metadata information about the cluster created.
@@ -2679,6 +3678,36 @@ spec:
                     type: string
                 type: object
               type: array
+            installFailure:
+              description: InstallFailure is the classification of the failure of
+                the install, set when the provision fails.
+              properties:
+                failingOperators:
+                  description: FailingOperators are the names of the cluster operators
+                    that failed to become available when the install failed waiting
+                    for the cluster operators.
+                  items:
+                    type: string
+                  type: array
+                message:
+                  description: Message is a human-readable message describing the
+                    failure.
+                  type: string
+                reason:
+                  description: Reason is a unique, one-word, CamelCase reason for
+                    the failure.
+                  type: string
+                retryable:
+                  description: Retryable is true when a new provision may succeed
+                    where this provision failed. Provisions are not retried after
+                    failures which are not retryable, such as quota or permission
+                    errors.
+                  type: boolean
+                stage:
+                  description: Stage is the stage of the install that the installer
+                    was in when it failed.
+                  type: string
+              type: object
             jobRef:
               description: JobRef is the reference to the job performing the provision.
               type: object
@@ -2746,44 +3775,212 @@ spec:
                 in the target cluster
               items:
                 properties:
-                  conditions:
-                    description: Conditions is the set of conditions in the status
-                      of the cluster operator on the target cluster
-                    items:
-                      properties:
-                        lastTransitionTime:
-                          description: lastTransitionTime is the time of the last
-                            update to the current status object.
-                          format: date-time
-                          type: string
-                        message:
-                          description: message provides additional information about
-                            the current condition. This is only to be consumed by
-                            humans.
-                          type: string
-                        reason:
-                          description: reason is the reason for the condition's last
-                            transition.  Reasons are CamelCase
-                          type: string
-                        status:
-                          description: status of the condition, one of True, False,
-                            Unknown.
-                          type: string
-                        type:
-                          description: type specifies the state of the operator's
-                            reconciliation functionality.
-                          type: string
-                      type: object
-                    type: array
+                  conditions:
+                    description: Conditions is the set of conditions in the status
+                      of the cluster operator on the target cluster
+                    items:
+                      properties:
+                        lastTransitionTime:
+                          description: lastTransitionTime is the time of the last
+                            update to the current status object.
+                          format: date-time
+                          type: string
+                        message:
+                          description: message provides additional information about
+                            the current condition. This is only to be consumed by
+                            humans.
+                          type: string
+                        reason:
+                          description: reason is the reason for the condition's last
+                            transition.  Reasons are CamelCase
+                          type: string
+                        status:
+                          description: status of the condition, one of True, False,
+                            Unknown.
+                          type: string
+                        type:
+                          description: type specifies the state of the operator's
+                            reconciliation functionality.
+                          type: string
+                      type: object
+                    type: array
+                  name:
+                    description: Name is the name of the cluster operator
+                    type: string
+                type: object
+              type: array
+            lastUpdated:
+              description: LastUpdated is the last time that operator state was updated
+              format: date-time
+              type: string
+          type: object
+  version: v1
+status:
+  acceptedNames:
+    kind: ""
+    plural: ""
+  conditions: []
+  storedVersions: []
+`)
+
+func configCrdsHive_v1_clusterstateYamlBytes() ([]byte, error) {
+	return _configCrdsHive_v1_clusterstateYaml, nil
+}
+
+func configCrdsHive_v1_clusterstateYaml() (*asset, error) {
+	bytes, err := configCrdsHive_v1_clusterstateYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "config/crds/hive_v1_clusterstate.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
+var _configCrdsHive_v1_clusterupgradepolicyYaml = []byte(`apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  creationTimestamp: null
+  labels:
+    controller-tools.k8s.io: "1.0"
+  name: clusterupgradepolicies.hive.openshift.io
+spec:
+  additionalPrinterColumns:
+  - JSONPath: .spec.imageSetRef.name
+    name: ImageSet
+    type: string
+  - JSONPath: .status.clusterDeployments
+    name: Clusters
+    type: integer
+  - JSONPath: .status.upgradedClusterDeployments
+    name: Upgraded
+    type: integer
+  - JSONPath: .status.currentWave
+    name: Wave
+    type: string
+  group: hive.openshift.io
+  names:
+    kind: ClusterUpgradePolicy
+    plural: clusterupgradepolicies
+    shortNames:
+    - cup
+  scope: Cluster
+  subresources:
+    status: {}
+  validation:
+    openAPIV3Schema:
+      properties:
+        apiVersion:
+          description: 'APIVersion defines the versioned schema of this representation
+            of an object. Servers should convert recognized schemas to the latest
+            internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources'
+          type: string
+        kind:
+          description: 'Kind is a string value representing the REST resource this
+            object represents. Servers may infer this from the endpoint the client
+            submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds'
+          type: string
+        metadata:
+          type: object
+        spec:
+          properties:
+            clusterDeploymentSelector:
+              description: ClusterDeploymentSelector is a LabelSelector indicating
+                which clusters the ClusterUpgradePolicy applies to in any namespace.
+              type: object
+            imageSetRef:
+              description: ImageSetRef is a reference to the ClusterImageSet with
+                the release to upgrade the selected clusters to.
+              properties:
+                name:
+                  description: Name is the name of the ClusterImageSet that this refers
+                    to
+                  type: string
+              type: object
+            waves:
+              description: Waves are the groups of selected clusters that are upgraded
+                one after the other. A cluster belongs to the first wave that selects
+                it, and clusters selected by none of the waves are not upgraded. A
+                wave starts once all the clusters of the previous wave have been upgraded,
+                or have failed within the limit of failures of the wave. All the selected
+                clusters are upgraded in a single wave with no limits when no wave
+                is specified.
+              items:
+                properties:
+                  clusterDeploymentSelector:
+                    description: ClusterDeploymentSelector is a LabelSelector indicating
+                      which of the clusters selected by the policy belong to the wave.
+                      An empty selector selects all the remaining clusters.
+                    type: object
+                  maxFailures:
+                    description: MaxFailures is the number of clusters of the wave
+                      whose upgrade may fail before the rollout is stopped.
+                    format: int32
+                    type: integer
+                  maxParallel:
+                    description: MaxParallel is the maximum number of clusters of
+                      the wave that are upgrading at the same time. All the clusters
+                      of the wave are upgraded at the same time when zero.
+                    format: int32
+                    type: integer
+                  name:
+                    description: Name is the name of the wave, unique within the policy.
+                    type: string
+                type: object
+              type: array
+          type: object
+        status:
+          properties:
+            clusterDeployments:
+              description: ClusterDeployments is the number of installed clusters
+                selected by the policy.
+              format: int32
+              type: integer
+            currentWave:
+              description: CurrentWave is the name of the wave being rolled out, or
+                of the wave that stopped the rollout.
+              type: string
+            upgradedClusterDeployments:
+              description: UpgradedClusterDeployments is the number of selected clusters
+                that have completed the upgrade to the release of the policy.
+              format: int32
+              type: integer
+            waves:
+              description: Waves is the progress of the rollout of each wave.
+              items:
+                properties:
+                  clusterDeployments:
+                    description: ClusterDeployments is the number of clusters in the
+                      wave.
+                    format: int32
+                    type: integer
+                  failedClusterDeployments:
+                    description: FailedClusterDeployments is the number of clusters
+                      of the wave whose upgrade has failed.
+                    format: int32
+                    type: integer
+                  message:
+                    description: Message explains the state of the wave.
+                    type: string
                   name:
-                    description: Name is the name of the cluster operator
+                    description: Name is the name of the wave.
                     type: string
+                  state:
+                    description: State is the state of the rollout of the wave.
+                    type: string
+                  upgradedClusterDeployments:
+                    description: UpgradedClusterDeployments is the number of clusters
+                      of the wave that have completed the upgrade.
+                    format: int32
+                    type: integer
+                  upgradingClusterDeployments:
+                    description: UpgradingClusterDeployments is the number of clusters
+                      of the wave that are upgrading.
+                    format: int32
+                    type: integer
                 type: object
               type: array
-            lastUpdated:
-              description: LastUpdated is the last time that operator state was updated
-              format: date-time
-              type: string
           type: object
   version: v1
 status:
@@ -2794,17 +3991,17 @@ status:
   storedVersions: []
 `)
 
-func configCrdsHive_v1_clusterstateYamlBytes() ([]byte, error) {
-	return _configCrdsHive_v1_clusterstateYaml, nil
+func configCrdsHive_v1_clusterupgradepolicyYamlBytes() ([]byte, error) {
+	return _configCrdsHive_v1_clusterupgradepolicyYaml, nil
 }
 
-func configCrdsHive_v1_clusterstateYaml() (*asset, error) {
-	bytes, err := configCrdsHive_v1_clusterstateYamlBytes()
+func configCrdsHive_v1_clusterupgradepolicyYaml() (*asset, error) {
+	bytes, err := configCrdsHive_v1_clusterupgradepolicyYamlBytes()
 	if err != nil {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "config/crds/hive_v1_clusterstate.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	info := bindataFileInfo{name: "config/crds/hive_v1_clusterupgradepolicy.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -2868,6 +4065,20 @@ spec:
                     This defaults to us-east-1. For AWS China, use cn-northwest-1.
                   type: string
               type: object
+            azure:
+              description: Azure specifies Azure-specific cloud configuration
+              properties:
+                credentialsSecretRef:
+                  description: CredentialsSecretRef references a secret that will
+                    be used to authenticate with Azure DNS. It will need permission
+                    to create and manage DNS zones in the resource group. Secret should
+                    have a key named 'osServicePrincipal.json'.
+                  type: object
+                resourceGroupName:
+                  description: ResourceGroupName specifies the Azure resource group
+                    in which the DNS zone is created.
+                  type: string
+              type: object
             gcp:
               description: GCP specifies GCP-specific cloud configuration
               properties:
@@ -2883,6 +4094,22 @@ spec:
               description: LinkToParentDomain specifies whether DNS records should
                 be automatically created to link this DNSZone with a parent domain.
               type: boolean
+            rfc2136:
+              description: RFC2136 specifies the configuration for a DNS server updated
+                through RFC2136 dynamic updates
+              properties:
+                server:
+                  description: Server is the address of the DNS server hosting the
+                    zone, with an optional port which defaults to 53.
+                  type: string
+                tsigKeySecretRef:
+                  description: TSIGKeySecretRef references a secret containing the
+                    TSIG key used to authenticate the dynamic updates and zone transfers.
+                    Secret should have keys named 'keyName' and 'secret', holding
+                    the name and the base64-encoded secret of the key, and may have
+                    a key named 'algorithm', which defaults to hmac-sha256.
+                  type: object
+              type: object
             zone:
               description: Zone is the DNS zone to host
               type: string
@@ -2897,6 +4124,14 @@ spec:
                   description: ZoneID is the ID of the zone in AWS
                   type: string
               type: object
+            azure:
+              description: AzureDNSZoneStatus contains status information specific
+                to Azure
+              properties:
+                zoneName:
+                  description: ZoneName is the name of the zone in Azure DNS
+                  type: string
+              type: object
             conditions:
               description: Conditions includes more detailed status for the DNSZone
               items:
@@ -3006,6 +4241,36 @@ spec:
           type: object
         spec:
           properties:
+            acme:
+              description: ACME configures the ACME certificate authority issuing
+                the ClusterDeployment certificate bundles which are to be generated.
+                Certificate bundles are not generated when ACME is not configured.
+              properties:
+                accountKeySecretRef:
+                  description: AccountKeySecretRef references a secret in the 'hive'
+                    namespace holding the private key of the ACME account in a 'tls.key'
+                    key. The secret is created with a new key if it does not exist.
+                  type: object
+                caSecretRef:
+                  description: CASecretRef references a secret in the 'hive' namespace
+                    holding, in a 'ca.crt' key, the certificate authority of the ACME
+                    server, when the ACME server is not trusted by the system certificate
+                    authorities.
+                  type: object
+                directoryURL:
+                  description: DirectoryURL is the URL of the directory of the ACME
+                    server, such as https://acme-v02.api.letsencrypt.org/directory.
+                  type: string
+                email:
+                  description: Email is the contact address registered with the ACME
+                    account.
+                  type: string
+                renewBefore:
+                  description: RenewBefore is how long before their expiry the generated
+                    certificates are renewed. It is capped at half the lifetime of
+                    the certificates. The default is 30 days.
+                  type: string
+              type: object
             additionalCertificateAuthoritiesSecretRef:
               description: AdditionalCertificateAuthoritiesSecretRef is a list of
                 references to secrets in the 'hive' namespace that contain an additional
@@ -3038,6 +4303,122 @@ spec:
                       type: boolean
                   type: object
               type: object
+            certificateExpiryWarningWindow:
+              description: CertificateExpiryWarningWindow is how long before their
+                expiry the certificates served by the control plane and ingresses
+                of ClusterDeployments are reported by the CertificateExpiring condition.
+                The default is 14 days.
+              type: string
+            controllerShards:
+              description: ControllerShards is the number of shards the hive controllers
+                are split into. Each shard is deployed as a separate hive-controllers-shard-N
+                deployment with its own leader election, and reconciles only the resources
+                in the namespaces which hash into the shard. ClusterDeployments and
+                the resources depending on them, such as SyncSetInstances, MachinePools
+                and ClusterProvisions, share a namespace and so are reconciled by
+                the same shard. Changing the number of shards restarts all shards,
+                rebalancing the namespaces across them. The default is a single hive-controllers
+                deployment reconciling all namespaces.
+              format: int32
+              minimum: 1
+              type: integer
+            controllersConfig:
+              description: ControllersConfig configures the concurrency and rate limits
+                of the hive controllers.
+              properties:
+                controllers:
+                  description: Controllers specifies the settings of individual controllers.
+                  items:
+                    properties:
+                      config:
+                        description: Config is the settings of the controller.
+                        properties:
+                          clientBurst:
+                            description: ClientBurst is the number of queries the
+                              controller's client may make to the API server of the
+                              hub cluster in a burst above ClientQPS. The default
+                              is the client-go default of 10.
+                            format: int32
+                            minimum: 1
+                            type: integer
+                          clientQPS:
+                            description: ClientQPS is the number of queries per second
+                              the controller's client may make to the API server of
+                              the hub cluster. The default is the client-go default
+                              of 5.
+                            format: int32
+                            minimum: 1
+                            type: integer
+                          concurrentReconciles:
+                            description: ConcurrentReconciles is the maximum number
+                              of reconciles run by the controller at the same time.
+                              The default is 5.
+                            format: int32
+                            minimum: 1
+                            type: integer
+                          queueBurst:
+                            description: QueueBurst is the number of requeued items
+                              the work queue of the controller may release in a burst
+                              above QueueQPS. The default is 100.
+                            format: int32
+                            minimum: 1
+                            type: integer
+                          queueQPS:
+                            description: QueueQPS is the number of requeued items
+                              per second the work queue of the controller releases
+                              for reconciling. The default is 10.
+                            format: int32
+                            minimum: 1
+                            type: integer
+                        type: object
+                      name:
+                        description: Name is the name of the controller, for example
+                          syncsetinstance or dnsendpoint. Names are matched ignoring
+                          case.
+                        type: string
+                    type: object
+                  type: array
+                default:
+                  description: Default specifies the settings used by the controllers
+                    for settings not specified for the controller in Controllers.
+                  properties:
+                    clientBurst:
+                      description: ClientBurst is the number of queries the controller's
+                        client may make to the API server of the hub cluster in a
+                        burst above ClientQPS. The default is the client-go default
+                        of 10.
+                      format: int32
+                      minimum: 1
+                      type: integer
+                    clientQPS:
+                      description: ClientQPS is the number of queries per second the
+                        controller's client may make to the API server of the hub
+                        cluster. The default is the client-go default of 5.
+                      format: int32
+                      minimum: 1
+                      type: integer
+                    concurrentReconciles:
+                      description: ConcurrentReconciles is the maximum number of reconciles
+                        run by the controller at the same time. The default is 5.
+                      format: int32
+                      minimum: 1
+                      type: integer
+                    queueBurst:
+                      description: QueueBurst is the number of requeued items the
+                        work queue of the controller may release in a burst above
+                        QueueQPS. The default is 100.
+                      format: int32
+                      minimum: 1
+                      type: integer
+                    queueQPS:
+                      description: QueueQPS is the number of requeued items per second
+                        the work queue of the controller releases for reconciling.
+                        The default is 10.
+                      format: int32
+                      minimum: 1
+                      type: integer
+                  type: object
+              type: object
             deprovisionsDisabled:
               description: DeprovisionsDisabled can be set to true to block deprovision
                 jobs from running.
@@ -3065,6 +4446,61 @@ spec:
               description: HiveAPIEnabled is a boolean controlling whether or not
                 the Hive operator will start up the v1alpha1 aggregated API server.
               type: boolean
+            hiveManagedKubeconfig:
+              description: HiveManagedKubeconfig configures the credentials minted
+                by Hive on installed clusters for its own use.
+              properties:
+                enabled:
+                  description: Enabled dictates if Hive mints its own kubeconfigs
+                    for installed clusters. If not specified, the default is disabled.
+                  type: boolean
+                rotationInterval:
+                  description: RotationInterval is how often a new client certificate
+                    is issued for the kubeconfig minted by Hive. Client certificates
+                    are also renewed once 80% of their validity has passed. The default
+                    is 7 days.
+                  type: string
+              type: object
+            installLogArchive:
+              description: InstallLogArchive configures an S3-compatible object store
+                in which the logs of installs are archived. The installer logs and
+                the logs gathered after failed installs are uploaded there by the
+                install pods, and referenced by the ClusterProvisions.
+              properties:
+                bucket:
+                  description: Bucket is the bucket in which the logs are stored.
+                    The bucket must exist.
+                  type: string
+                credentialsSecretRef:
+                  description: CredentialsSecretRef references a secret in the 'hive'
+                    namespace holding the access key of the object store in 'aws_access_key_id'
+                    and 'aws_secret_access_key' keys. The credentials are not shared
+                    with the install pods, which are given presigned URLs to upload
+                    the logs of their provision instead.
+                  type: object
+                endpoint:
+                  description: Endpoint is the URL of the S3-compatible service, such
+                    as https://minio.example.com:9000. Objects are addressed with
+                    path-style URLs when an endpoint is specified. If not specified,
+                    AWS S3 is used.
+                  type: string
+                prefix:
+                  description: Prefix is prepended to the keys of the objects holding
+                    the logs. The logs of a provision are stored under <prefix>/<namespace>/<clusterprovision
+                    name>/. A prefix is required when a retention is set.
+                  type: string
+                region:
+                  description: Region is the region of the bucket. The default is
+                    us-east-1.
+                  type: string
+                retention:
+                  description: Retention is how long the logs are kept in the object
+                    store. Older objects under the prefix are deleted by Hive, so
+                    the prefix must not hold anything else. If not specified, logs
+                    are kept until they are deleted from the object store by other
+                    means.
+                  type: string
+              type: object
             logLevel:
               description: LogLevel is the level of logging to use for the Hive controllers.
                 Acceptable levels, from coarsest to finest, are panic, fatal, error,
@@ -3100,6 +4536,22 @@ spec:
                           This defaults to us-east-1. For AWS China, use cn-northwest-1.
                         type: string
                     type: object
+                  azure:
+                    description: Azure contains Azure-specific settings for external
+                      DNS
+                    properties:
+                      credentialsSecretRef:
+                        description: CredentialsSecretRef references a secret that
+                          will be used to authenticate with Azure DNS. It will need
+                          permission to manage entries in each of the managed domains
+                          listed in the parent ManageDNSConfig object. Secret should
+                          have a key named 'osServicePrincipal.json'.
+                        type: object
+                      resourceGroupName:
+                        description: ResourceGroupName specifies the Azure resource
+                          group containing the DNS zones for the managed domains.
+                        type: string
+                    type: object
                   domains:
                     description: Domains is the list of domains that hive will be
                       managing entries for with the provided credentials.
@@ -3118,8 +4570,75 @@ spec:
                           The credentials must specify the project to use.
                         type: object
                     type: object
+                  rfc2136:
+                    description: RFC2136 contains the settings for managing DNS on
+                      a DNS server through RFC2136 dynamic updates
+                    properties:
+                      server:
+                        description: Server is the address of the DNS server hosting
+                          the zones of the managed domains, with an optional port
+                          which defaults to 53.
+                        type: string
+                      tsigKeySecretRef:
+                        description: TSIGKeySecretRef references a secret containing
+                          the TSIG key used to authenticate the dynamic updates and
+                          zone transfers. It will need permission to manage NS records
+                          in each of the managed domains listed in the parent ManageDNSConfig
+                          object. Secret should have keys named 'keyName' and 'secret',
+                          holding the name and the base64-encoded secret of the key,
+                          and may have a key named 'algorithm', which defaults to
+                          hmac-sha256.
+                        type: object
+                    type: object
                 type: object
               type: array
+            releaseImagePolicy:
+              description: ReleaseImagePolicy restricts the release images from which
+                clusters can be installed or to which they can be upgraded. The policy
+                is enforced when a ClusterDeployment is created or its upgrade is
+                requested, and again before the installer image of a cluster is resolved
+                and before a cluster is upgraded.
+              properties:
+                allowedRegistries:
+                  description: AllowedRegistries are the registries from which release
+                    images can be pulled, such as quay.io or registry.example.com:5000.
+                    An entry can also restrict release images to the repositories
+                    under a path of a registry, such as quay.io/openshift-release-dev.
+                    When empty, release images can be pulled from any registry.
+                  items:
+                    type: string
+                  type: array
+                requireDigest:
+                  description: RequireDigest requires release images to be referenced
+                    by digest rather than by tag, so that the content of a release
+                    image cannot change after it has been allowed.
+                  type: boolean
+                signatureVerification:
+                  description: SignatureVerification requires release images to be
+                    signed by a trusted GPG key. Release images must be referenced
+                    by digest when signature verification is configured.
+                  properties:
+                    publicKeysSecretRef:
+                      description: PublicKeysSecretRef references a secret in the
+                        'hive' namespace holding the ASCII-armored GPG public keys
+                        trusted to sign release images. Each key of the secret holds
+                        one or more public keys.
+                      type: object
+                    signatureStores:
+                      description: SignatureStores are the URLs of the signature stores
+                        from which the signatures of release images are fetched, such
+                        as https://mirror.openshift.com/pub/openshift-v4/signatures/openshift/release.
+                      items:
+                        type: string
+                      type: array
+                  type: object
+              type: object
+            syncSetDriftCheckInterval:
+              description: SyncSetDriftCheckInterval is a string duration indicating
+                how often SyncSet resources with drift checks enabled are checked
+                for drift on the target cluster. The default drift check interval
+                is ten minutes.
+              type: string
             syncSetReapplyInterval:
               description: SyncSetReapplyInterval is a string duration indicating
                 how much time must pass before SyncSet resources will be reapplied.
@@ -3167,6 +4686,107 @@ func configCrdsHive_v1_hiveconfigYaml() (*asset, error) {
 	return a, nil
 }
 
+var _configCrdsHive_v1_installfailureruleYaml = []byte(`apiVersion: apiextensions.k8s.io/v1beta1
+kind: CustomResourceDefinition
+metadata:
+  creationTimestamp: null
+  labels:
+    controller-tools.k8s.io: "1.0"
+  name: installfailurerules.hive.openshift.io
+spec:
+  additionalPrinterColumns:
+  - JSONPath: .spec.installFailingReason
+    name: Reason
+    type: string
+  - JSONPath: .spec.priority
+    name: Priority
+    type: integer
+  group: hive.openshift.io
+  names:
+    kind: InstallFailureRule
+    plural: installfailurerules
+    shortNames:
+    - ifr
+  scope: Cluster
+  subresources:
+    status: {}
+  validation:
+    openAPIV3Schema:
+      properties:
+        apiVersion:
+          description: 'APIVersion defines the versioned schema of this representation
+            of an object. Servers should convert recognized schemas to the latest
+            internal value, and may reject unrecognized values. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#resources'
+          type: string
+        kind:
+          description: 'Kind is a string value representing the REST resource this
+            object represents. Servers may infer this from the endpoint the client
+            submits requests to. Cannot be updated. In CamelCase. More info: https://git.k8s.io/community/contributors/devel/api-conventions.md#types-kinds'
+          type: string
+        metadata:
+          type: object
+        spec:
+          properties:
+            installFailingMessage:
+              description: InstallFailingMessage is the message reported for an install
+                failure matching the rule.
+              type: string
+            installFailingReason:
+              description: InstallFailingReason is the reason reported for an install
+                failure matching the rule.
+              type: string
+            platforms:
+              description: Platforms limits the rule to installs on the given platforms.
+                Valid values are aws, azure, gcp, openstack, vsphere and baremetal.
+                The rule applies to installs on all platforms when empty.
+              items:
+                type: string
+              type: array
+            priority:
+              description: Priority determines the order in which the rules are matched
+                against an install log. Rules with a higher priority are matched first.
+                Rules with the same priority are matched in order of their names.
+                Matching stops at the first rule that matches.
+              format: int32
+              type: integer
+            retryable:
+              description: Retryable indicates whether a new install attempt may succeed
+                after an install failure matching the rule. Defaults to true.
+              type: boolean
+            searchRegexStrings:
+              description: SearchRegexStrings are the regular expressions searched
+                for in the install log. The rule matches if any one of the regular
+                expressions matches.
+              items:
+                type: string
+              type: array
+          type: object
+        status:
+          type: object
+  version: v1
+status:
+  acceptedNames:
+    kind: ""
+    plural: ""
+  conditions: []
+  storedVersions: []
+`)
+
+func configCrdsHive_v1_installfailureruleYamlBytes() ([]byte, error) {
+	return _configCrdsHive_v1_installfailureruleYaml, nil
+}
+
+func configCrdsHive_v1_installfailureruleYaml() (*asset, error) {
+	bytes, err := configCrdsHive_v1_installfailureruleYamlBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "config/crds/hive_v1_installfailurerule.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	a := &asset{bytes: bytes, info: info}
+	return a, nil
+}
+
 var _configCrdsHive_v1_machinepoolYaml = []byte(`apiVersion: apiextensions.k8s.io/v1beta1
 kind: CustomResourceDefinition
 metadata:
@@ -3309,6 +4929,58 @@ spec:
                         type: string
                       type: array
                   type: object
+                openstack:
+                  description: OpenStack is the configuration used when installing
+                    on OpenStack.
+                  properties:
+                    flavor:
+                      description: Flavor defines the OpenStack Nova flavor. eg. m1.large
+                        The json key here differs from the installer, which uses "type",
+                        to stay consistent with the "flavor" field on the resulting
+                        MachineSet.
+                      type: string
+                    rootVolume:
+                      description: RootVolume defines the root volume for instances
+                        in the machine pool. The instances use ephemeral disks if
+                        not set.
+                      properties:
+                        size:
+                          description: Size defines the size of the volume in gibibytes
+                            (GiB). Required
+                          format: int64
+                          type: integer
+                        type:
+                          description: Type defines the type of the volume. Required
+                          type: string
+                      type: object
+                  type: object
+                vsphere:
+                  description: VSphere is the configuration used when installing on
+                    vSphere.
+                  properties:
+                    coresPerSocket:
+                      description: NumCoresPerSocket is the number of cores per socket
+                        in a vm. The number of vCPUs on the vm will be NumCPUs/NumCoresPerSocket.
+                      format: int32
+                      type: integer
+                    cpus:
+                      description: NumCPUs is the total number of virtual processor
+                        cores to assign a vm.
+                      format: int32
+                      type: integer
+                    memoryMB:
+                      description: Memory is the size of a VM's memory in MB.
+                      format: int64
+                      type: integer
+                    osDisk:
+                      description: OSDisk defines the storage for instance.
+                      properties:
+                        diskSizeGB:
+                          description: DiskSizeGB defines the size of disk in GB.
+                          format: int32
+                          type: integer
+                      type: object
+                  type: object
               type: object
             replicas:
               description: Replicas is the count of machines for this machine pool.
@@ -4075,6 +5747,15 @@ spec:
               description: ClusterDeploymentSelector is a LabelSelector indicating
                 which clusters the SelectorSyncSet applies to in any namespace.
               type: object
+            driftCheckMode:
+              description: DriftCheckMode indicates if the Resource drift check mode
+                is "disabled" (default), "report" or "audit". DriftCheckMode "disabled"
+                indicates that resources are not checked for drift. DriftCheckMode
+                "report" indicates that fields of the live resources are compared
+                against the fields of the Resources and drifted resources are reported.
+                DriftCheckMode "audit" indicates that drifted resources are reported
+                and are not re-applied.
+              type: string
             patches:
               description: Patches is the list of patches to apply.
               items:
@@ -4104,8 +5785,17 @@ spec:
               type: array
             resourceApplyMode:
               description: ResourceApplyMode indicates if the Resource apply mode
-                is "upsert" (default) or "sync". ApplyMode "upsert" indicates create
-                and update. ApplyMode "sync" indicates create, update and delete.
+                is "upsert" (default), "sync" or "serverSideApply". ApplyMode "upsert"
+                indicates create and update. ApplyMode "sync" indicates create, update
+                and delete. ApplyMode "serverSideApply" indicates create and update
+                with server-side apply.
+              type: string
+            resourceTemplateMode:
+              description: ResourceTemplateMode indicates if the Resource template
+                mode is "none" (default) or "goTemplate". TemplateMode "none" indicates
+                that resources are applied as they are. TemplateMode "goTemplate"
+                indicates that the string values of resources are rendered as Go text/template
+                templates with the fields of the ClusterDeployment and the TemplateParameters.
               type: string
             resources:
               description: Resources is the list of objects to sync from RawExtension
@@ -4146,6 +5836,11 @@ spec:
                     type: object
                 type: object
               type: array
+            templateParametersRef:
+              description: TemplateParametersRef is a reference to a ConfigMap in
+                the namespace of the ClusterDeployment. The data of the ConfigMap
+                is available to the templates of the Resources as .Parameters.
+              type: object
           type: object
         status:
           type: object
@@ -4778,6 +6473,15 @@ spec:
               items:
                 type: object
               type: array
+            driftCheckMode:
+              description: DriftCheckMode indicates if the Resource drift check mode
+                is "disabled" (default), "report" or "audit". DriftCheckMode "disabled"
+                indicates that resources are not checked for drift. DriftCheckMode
+                "report" indicates that fields of the live resources are compared
+                against the fields of the Resources and drifted resources are reported.
+                DriftCheckMode "audit" indicates that drifted resources are reported
+                and are not re-applied.
+              type: string
             patches:
               description: Patches is the list of patches to apply.
               items:
@@ -4807,8 +6511,17 @@ spec:
               type: array
             resourceApplyMode:
               description: ResourceApplyMode indicates if the Resource apply mode
-                is "upsert" (default) or "sync". ApplyMode "upsert" indicates create
-                and update. ApplyMode "sync" indicates create, update and delete.
+                is "upsert" (default), "sync" or "serverSideApply". ApplyMode "upsert"
+                indicates create and update. ApplyMode "sync" indicates create, update
+                and delete. ApplyMode "serverSideApply" indicates create and update
+                with server-side apply.
+              type: string
+            resourceTemplateMode:
+              description: ResourceTemplateMode indicates if the Resource template
+                mode is "none" (default) or "goTemplate". TemplateMode "none" indicates
+                that resources are applied as they are. TemplateMode "goTemplate"
+                indicates that the string values of resources are rendered as Go text/template
+                templates with the fields of the ClusterDeployment and the TemplateParameters.
               type: string
             resources:
               description: Resources is the list of objects to sync from RawExtension
@@ -4849,6 +6562,11 @@ spec:
                     type: object
                 type: object
               type: array
+            templateParametersRef:
+              description: TemplateParametersRef is a reference to a ConfigMap in
+                the namespace of the ClusterDeployment. The data of the ConfigMap
+                is available to the templates of the Resources as .Parameters.
+              type: object
           required:
           - clusterDeploymentRefs
           type: object
@@ -4918,8 +6636,10 @@ spec:
               type: object
             resourceApplyMode:
               description: ResourceApplyMode indicates if the resource apply mode
-                is "upsert" (default) or "sync". ApplyMode "upsert" indicates create
-                and update. ApplyMode "sync" indicates create, update and delete.
+                is "upsert" (default), "sync" or "serverSideApply". ApplyMode "upsert"
+                indicates create and update. ApplyMode "sync" indicates create, update
+                and delete. ApplyMode "serverSideApply" indicates create and update
+                with server-side apply.
               type: string
             selectorSyncSetRef:
               description: SelectorSyncSetRef is a reference to the selectorsyncset
@@ -5186,71 +6906,237 @@ func configCrdsHive_v1_syncsetinstanceYaml() (*asset, error) {
 	return a, nil
 }
 
-var _configConfigmapsInstallLogRegexesConfigmapYaml = []byte(`apiVersion: v1
-kind: ConfigMap
+var _configInstallfailurerulesInstallfailurerulesYaml = []byte(`# Default InstallFailureRules used to classify install failures. Rules matching errors
+# specific to a platform have a higher priority than the generic rules so they are
+# matched first.
+apiVersion: hive.openshift.io/v1
+kind: InstallFailureRule
 metadata:
-  name: install-log-regexes
-  namespace: hive
-data:
-  regexes: |
-    # AWS Specific:
-    - name: AWSNATGatewayLimitExceeded
-      searchRegexStrings:
-      - "NatGatewayLimitExceeded"
-      installFailingReason: AWSNATGatewayLimitExceeded
-      installFailingMessage: AWS NAT gateway limit exceeded
-    - name: DNSAlreadyExists
-      searchRegexStrings:
-      - "aws_route53_record.*Error building changeset:.*Tried to create resource record set.*but it already exists"
-      installFailingReason: DNSAlreadyExists
-      installFailingMessage: DNS record already exists
-    - name: PendingVerification
-      searchRegexStrings:
-      - "PendingVerification: Your request for accessing resources in this region is being validated"
-      installFailingReason: PendingVerification
-      installFailingMessage: Account pending verification for region
-    - name: NoMatchingRoute53Zone
-      searchRegexStrings:
-      - "data.aws_route53_zone.public: no matching Route53Zone found"
-      installFailingReason: NoMatchingRoute53Zone
-      installFailingMessage: No matching Route53Zone found
-    - name: KubeAPIWaitTimeout
-      searchRegexStrings:
-      - "waiting for Kubernetes API: context deadline exceeded"
-      installFailingReason: KubeAPIWaitTimeout
-      installFailingMessage: Timeout waiting for the Kubernetes API to begin responding
-    - name: MonitoringOperatorStillUpdating
-      searchRegexStrings:
-      - "failed to initialize the cluster: Cluster operator monitoring is still updating"
-      installFailingReason: MonitoringOperatorStillUpdating
-      installFailingMessage: Timeout waiting for the monitoring operator to become ready
-    # Bare Metal
-    - name: LibvirtSSHKeyPermissionDenied
-      searchRegexStrings:
-      - "platform.baremetal.libvirtURI: Internal error: could not connect to libvirt: virError.Code=38, Domain=7, Message=.Cannot recv data: Permission denied"
-      installFailingReason: LibvirtSSHKeyPermissionDenied
-      installFailingMessage: "Permission denied connecting to libvirt host, check SSH key configuration and pass phrase"
-    # Processing stops at the first match, so this more generic
-    # message about the connection failure must always come after the
-    # more specific message for LibvirtSSHKeyPermissionDenied.
-    - name: LibvirtConnectionFailed
-      searchRegexStrings:
-      - "could not connect to libvirt"
-      installFailingReason: LibvirtConnectionFailed
-      installFailingMessage: "Could not connect to libvirt host"
+  name: aws-nat-gateway-limit-exceeded
+spec:
+  priority: 10
+  platforms:
+  - aws
+  searchRegexStrings:
+  - "NatGatewayLimitExceeded"
+  installFailingReason: AWSNATGatewayLimitExceeded
+  installFailingMessage: AWS NAT gateway limit exceeded
+  retryable: false
+---
+apiVersion: hive.openshift.io/v1
+kind: InstallFailureRule
+metadata:
+  name: aws-vpc-limit-exceeded
+spec:
+  priority: 10
+  platforms:
+  - aws
+  searchRegexStrings:
+  - "VpcLimitExceeded"
+  installFailingReason: AWSVPCLimitExceeded
+  installFailingMessage: AWS VPC limit exceeded
+  retryable: false
+---
+apiVersion: hive.openshift.io/v1
+kind: InstallFailureRule
+metadata:
+  name: aws-eip-limit-exceeded
+spec:
+  priority: 10
+  platforms:
+  - aws
+  searchRegexStrings:
+  - "AddressLimitExceeded"
+  installFailingReason: AWSEIPLimitExceeded
+  installFailingMessage: AWS Elastic IP address limit exceeded
+  retryable: false
+---
+apiVersion: hive.openshift.io/v1
+kind: InstallFailureRule
+metadata:
+  name: aws-instance-limit-exceeded
+spec:
+  priority: 10
+  platforms:
+  - aws
+  searchRegexStrings:
+  - "InstanceLimitExceeded"
+  - "VcpuLimitExceeded"
+  installFailingReason: AWSInstanceLimitExceeded
+  installFailingMessage: AWS instance limit exceeded
+  retryable: false
+---
+apiVersion: hive.openshift.io/v1
+kind: InstallFailureRule
+metadata:
+  name: aws-insufficient-permissions
+spec:
+  priority: 10
+  platforms:
+  - aws
+  searchRegexStrings:
+  - "UnauthorizedOperation: You are not authorized to perform this operation"
+  - "AccessDenied: User: .* is not authorized to perform"
+  installFailingReason: AWSInsufficientPermissions
+  installFailingMessage: AWS credentials are missing permissions required to install the cluster
+  retryable: false
+---
+apiVersion: hive.openshift.io/v1
+kind: InstallFailureRule
+metadata:
+  name: dns-already-exists
+spec:
+  priority: 10
+  platforms:
+  - aws
+  searchRegexStrings:
+  - "aws_route53_record.*Error building changeset:.*Tried to create resource record set.*but it already exists"
+  installFailingReason: DNSAlreadyExists
+  installFailingMessage: DNS record already exists
+---
+apiVersion: hive.openshift.io/v1
+kind: InstallFailureRule
+metadata:
+  name: pending-verification
+spec:
+  priority: 10
+  platforms:
+  - aws
+  searchRegexStrings:
+  - "PendingVerification: Your request for accessing resources in this region is being validated"
+  installFailingReason: PendingVerification
+  installFailingMessage: Account pending verification for region
+---
+apiVersion: hive.openshift.io/v1
+kind: InstallFailureRule
+metadata:
+  name: no-matching-route53-zone
+spec:
+  priority: 10
+  platforms:
+  - aws
+  searchRegexStrings:
+  - "data.aws_route53_zone.public: no matching Route53Zone found"
+  installFailingReason: NoMatchingRoute53Zone
+  installFailingMessage: No matching Route53Zone found
+---
+apiVersion: hive.openshift.io/v1
+kind: InstallFailureRule
+metadata:
+  name: kube-api-wait-timeout
+spec:
+  searchRegexStrings:
+  - "waiting for Kubernetes API: context deadline exceeded"
+  installFailingReason: KubeAPIWaitTimeout
+  installFailingMessage: Timeout waiting for the Kubernetes API to begin responding
+---
+apiVersion: hive.openshift.io/v1
+kind: InstallFailureRule
+metadata:
+  name: monitoring-operator-still-updating
+spec:
+  searchRegexStrings:
+  - "failed to initialize the cluster: Cluster operator monitoring is still updating"
+  installFailingReason: MonitoringOperatorStillUpdating
+  installFailingMessage: Timeout waiting for the monitoring operator to become ready
+---
+apiVersion: hive.openshift.io/v1
+kind: InstallFailureRule
+metadata:
+  name: gcp-quota-exceeded
+spec:
+  priority: 10
+  platforms:
+  - gcp
+  searchRegexStrings:
+  - "googleapi: Error 403: Quota .* exceeded"
+  - "QUOTA_EXCEEDED"
+  installFailingReason: GCPQuotaExceeded
+  installFailingMessage: GCP quota exceeded
+  retryable: false
+---
+apiVersion: hive.openshift.io/v1
+kind: InstallFailureRule
+metadata:
+  name: gcp-insufficient-permissions
+spec:
+  priority: 10
+  platforms:
+  - gcp
+  searchRegexStrings:
+  - "googleapi: Error 403: Required '.*' permission"
+  installFailingReason: GCPInsufficientPermissions
+  installFailingMessage: GCP credentials are missing permissions required to install the cluster
+  retryable: false
+---
+apiVersion: hive.openshift.io/v1
+kind: InstallFailureRule
+metadata:
+  name: azure-quota-exceeded
+spec:
+  priority: 10
+  platforms:
+  - azure
+  searchRegexStrings:
+  - "Operation results in exceeding quota limits"
+  installFailingReason: AzureQuotaExceeded
+  installFailingMessage: Azure quota exceeded
+  retryable: false
+---
+apiVersion: hive.openshift.io/v1
+kind: InstallFailureRule
+metadata:
+  name: azure-insufficient-permissions
+spec:
+  priority: 10
+  platforms:
+  - azure
+  searchRegexStrings:
+  - "AuthorizationFailed"
+  installFailingReason: AzureInsufficientPermissions
+  installFailingMessage: Azure credentials are missing permissions required to install the cluster
+  retryable: false
+---
+# Matching stops at the first match, so this rule must have a higher priority than the
+# more generic libvirt-connection-failed rule.
+apiVersion: hive.openshift.io/v1
+kind: InstallFailureRule
+metadata:
+  name: libvirt-ssh-key-permission-denied
+spec:
+  priority: 20
+  platforms:
+  - baremetal
+  searchRegexStrings:
+  - "platform.baremetal.libvirtURI: Internal error: could not connect to libvirt: virError.Code=38, Domain=7, Message=.Cannot recv data: Permission denied"
+  installFailingReason: LibvirtSSHKeyPermissionDenied
+  installFailingMessage: "Permission denied connecting to libvirt host, check SSH key configuration and pass phrase"
+---
+apiVersion: hive.openshift.io/v1
+kind: InstallFailureRule
+metadata:
+  name: libvirt-connection-failed
+spec:
+  priority: 10
+  platforms:
+  - baremetal
+  searchRegexStrings:
+  - "could not connect to libvirt"
+  installFailingReason: LibvirtConnectionFailed
+  installFailingMessage: Could not connect to libvirt host
 `)
 
-func configConfigmapsInstallLogRegexesConfigmapYamlBytes() ([]byte, error) {
-	return _configConfigmapsInstallLogRegexesConfigmapYaml, nil
+func configInstallfailurerulesInstallfailurerulesYamlBytes() ([]byte, error) {
+	return _configInstallfailurerulesInstallfailurerulesYaml, nil
 }
 
-func configConfigmapsInstallLogRegexesConfigmapYaml() (*asset, error) {
-	bytes, err := configConfigmapsInstallLogRegexesConfigmapYamlBytes()
+func configInstallfailurerulesInstallfailurerulesYaml() (*asset, error) {
+	bytes, err := configInstallfailurerulesInstallfailurerulesYamlBytes()
 	if err != nil {
 		return nil, err
 	}
 
-	info := bindataFileInfo{name: "config/configmaps/install-log-regexes-configmap.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
+	info := bindataFileInfo{name: "config/installfailurerules/installfailurerules.yaml", size: 0, mode: os.FileMode(0), modTime: time.Unix(0, 0)}
 	a := &asset{bytes: bytes, info: info}
 	return a, nil
 }
@@ -5314,13 +7200,16 @@ var _bindata = map[string]func() (*asset, error){
 	"config/apiserver/service-account.yaml":                     configApiserverServiceAccountYaml,
 	"config/apiserver/service.yaml":                             configApiserverServiceYaml,
 	"config/hiveadmission/apiservice.yaml":                      configHiveadmissionApiserviceYaml,
+	"config/hiveadmission/clusterclaim-webhook.yaml":            configHiveadmissionClusterclaimWebhookYaml,
 	"config/hiveadmission/clusterdeployment-webhook.yaml":       configHiveadmissionClusterdeploymentWebhookYaml,
 	"config/hiveadmission/clusterimageset-webhook.yaml":         configHiveadmissionClusterimagesetWebhookYaml,
 	"config/hiveadmission/clusterprovision-webhook.yaml":        configHiveadmissionClusterprovisionWebhookYaml,
+	"config/hiveadmission/clusterupgradepolicy-webhook.yaml":    configHiveadmissionClusterupgradepolicyWebhookYaml,
 	"config/hiveadmission/deployment.yaml":                      configHiveadmissionDeploymentYaml,
 	"config/hiveadmission/dnszones-webhook.yaml":                configHiveadmissionDnszonesWebhookYaml,
 	"config/hiveadmission/hiveadmission_rbac_role.yaml":         configHiveadmissionHiveadmission_rbac_roleYaml,
 	"config/hiveadmission/hiveadmission_rbac_role_binding.yaml": configHiveadmissionHiveadmission_rbac_role_bindingYaml,
+	"config/hiveadmission/installfailurerule-webhook.yaml":      configHiveadmissionInstallfailureruleWebhookYaml,
 	"config/hiveadmission/machinepool-webhook.yaml":             configHiveadmissionMachinepoolWebhookYaml,
 	"config/hiveadmission/selectorsyncset-webhook.yaml":         configHiveadmissionSelectorsyncsetWebhookYaml,
 	"config/hiveadmission/service-account.yaml":                 configHiveadmissionServiceAccountYaml,
@@ -5335,16 +7224,23 @@ var _bindata = map[string]func() (*asset, error){
 	"config/rbac/hive_frontend_role.yaml":                       configRbacHive_frontend_roleYaml,
 	"config/rbac/hive_frontend_role_binding.yaml":               configRbacHive_frontend_role_bindingYaml,
 	"config/rbac/hive_frontend_serviceaccount.yaml":             configRbacHive_frontend_serviceaccountYaml,
+	"config/rbac/hive_imageset_role.yaml":                       configRbacHive_imageset_roleYaml,
+	"config/rbac/hive_imageset_role_binding.yaml":               configRbacHive_imageset_role_bindingYaml,
+	"config/rbac/hive_imageset_serviceaccount.yaml":             configRbacHive_imageset_serviceaccountYaml,
 	"config/rbac/hive_reader_role.yaml":                         configRbacHive_reader_roleYaml,
 	"config/rbac/hive_reader_role_binding.yaml":                 configRbacHive_reader_role_bindingYaml,
 	"config/crds/hive_v1_checkpoint.yaml":                       configCrdsHive_v1_checkpointYaml,
+	"config/crds/hive_v1_clusterclaim.yaml":                     configCrdsHive_v1_clusterclaimYaml,
 	"config/crds/hive_v1_clusterdeployment.yaml":                configCrdsHive_v1_clusterdeploymentYaml,
 	"config/crds/hive_v1_clusterdeprovision.yaml":               configCrdsHive_v1_clusterdeprovisionYaml,
 	"config/crds/hive_v1_clusterimageset.yaml":                  configCrdsHive_v1_clusterimagesetYaml,
+	"config/crds/hive_v1_clusterpool.yaml":                      configCrdsHive_v1_clusterpoolYaml,
 	"config/crds/hive_v1_clusterprovision.yaml":                 configCrdsHive_v1_clusterprovisionYaml,
 	"config/crds/hive_v1_clusterstate.yaml":                     configCrdsHive_v1_clusterstateYaml,
+	"config/crds/hive_v1_clusterupgradepolicy.yaml":             configCrdsHive_v1_clusterupgradepolicyYaml,
 	"config/crds/hive_v1_dnszone.yaml":                          configCrdsHive_v1_dnszoneYaml,
 	"config/crds/hive_v1_hiveconfig.yaml":                       configCrdsHive_v1_hiveconfigYaml,
+	"config/crds/hive_v1_installfailurerule.yaml":               configCrdsHive_v1_installfailureruleYaml,
 	"config/crds/hive_v1_machinepool.yaml":                      configCrdsHive_v1_machinepoolYaml,
 	"config/crds/hive_v1_machinepoolnamelease.yaml":             configCrdsHive_v1_machinepoolnameleaseYaml,
 	"config/crds/hive_v1_selectorsyncidentityprovider.yaml":     configCrdsHive_v1_selectorsyncidentityproviderYaml,
@@ -5352,7 +7248,7 @@ var _bindata = map[string]func() (*asset, error){
 	"config/crds/hive_v1_syncidentityprovider.yaml":             configCrdsHive_v1_syncidentityproviderYaml,
 	"config/crds/hive_v1_syncset.yaml":                          configCrdsHive_v1_syncsetYaml,
 	"config/crds/hive_v1_syncsetinstance.yaml":                  configCrdsHive_v1_syncsetinstanceYaml,
-	"config/configmaps/install-log-regexes-configmap.yaml":      configConfigmapsInstallLogRegexesConfigmapYaml,
+	"config/installfailurerules/installfailurerules.yaml":       configInstallfailurerulesInstallfailurerulesYaml,
 }
 
 // AssetDir returns the file names below a certain
@@ -5405,18 +7301,19 @@ var _bintree = &bintree{nil, map[string]*bintree{
 			"service-account.yaml":           {configApiserverServiceAccountYaml, map[string]*bintree{}},
 			"service.yaml":                   {configApiserverServiceYaml, map[string]*bintree{}},
 		}},
-		"configmaps": {nil, map[string]*bintree{
-			"install-log-regexes-configmap.yaml": {configConfigmapsInstallLogRegexesConfigmapYaml, map[string]*bintree{}},
-		}},
 		"crds": {nil, map[string]*bintree{
 			"hive_v1_checkpoint.yaml":                   {configCrdsHive_v1_checkpointYaml, map[string]*bintree{}},
+			"hive_v1_clusterclaim.yaml":                 {configCrdsHive_v1_clusterclaimYaml, map[string]*bintree{}},
 			"hive_v1_clusterdeployment.yaml":            {configCrdsHive_v1_clusterdeploymentYaml, map[string]*bintree{}},
 			"hive_v1_clusterdeprovision.yaml":           {configCrdsHive_v1_clusterdeprovisionYaml, map[string]*bintree{}},
 			"hive_v1_clusterimageset.yaml":              {configCrdsHive_v1_clusterimagesetYaml, map[string]*bintree{}},
+			"hive_v1_clusterpool.yaml":                  {configCrdsHive_v1_clusterpoolYaml, map[string]*bintree{}},
 			"hive_v1_clusterprovision.yaml":             {configCrdsHive_v1_clusterprovisionYaml, map[string]*bintree{}},
 			"hive_v1_clusterstate.yaml":                 {configCrdsHive_v1_clusterstateYaml, map[string]*bintree{}},
+			"hive_v1_clusterupgradepolicy.yaml":         {configCrdsHive_v1_clusterupgradepolicyYaml, map[string]*bintree{}},
 			"hive_v1_dnszone.yaml":                      {configCrdsHive_v1_dnszoneYaml, map[string]*bintree{}},
 			"hive_v1_hiveconfig.yaml":                   {configCrdsHive_v1_hiveconfigYaml, map[string]*bintree{}},
+			"hive_v1_installfailurerule.yaml":           {configCrdsHive_v1_installfailureruleYaml, map[string]*bintree{}},
 			"hive_v1_machinepool.yaml":                  {configCrdsHive_v1_machinepoolYaml, map[string]*bintree{}},
 			"hive_v1_machinepoolnamelease.yaml":         {configCrdsHive_v1_machinepoolnameleaseYaml, map[string]*bintree{}},
 			"hive_v1_selectorsyncidentityprovider.yaml": {configCrdsHive_v1_selectorsyncidentityproviderYaml, map[string]*bintree{}},
@@ -5427,19 +7324,25 @@ var _bintree = &bintree{nil, map[string]*bintree{
 		}},
 		"hiveadmission": {nil, map[string]*bintree{
 			"apiservice.yaml":                      {configHiveadmissionApiserviceYaml, map[string]*bintree{}},
+			"clusterclaim-webhook.yaml":            {configHiveadmissionClusterclaimWebhookYaml, map[string]*bintree{}},
 			"clusterdeployment-webhook.yaml":       {configHiveadmissionClusterdeploymentWebhookYaml, map[string]*bintree{}},
 			"clusterimageset-webhook.yaml":         {configHiveadmissionClusterimagesetWebhookYaml, map[string]*bintree{}},
 			"clusterprovision-webhook.yaml":        {configHiveadmissionClusterprovisionWebhookYaml, map[string]*bintree{}},
+			"clusterupgradepolicy-webhook.yaml":    {configHiveadmissionClusterupgradepolicyWebhookYaml, map[string]*bintree{}},
 			"deployment.yaml":                      {configHiveadmissionDeploymentYaml, map[string]*bintree{}},
 			"dnszones-webhook.yaml":                {configHiveadmissionDnszonesWebhookYaml, map[string]*bintree{}},
 			"hiveadmission_rbac_role.yaml":         {configHiveadmissionHiveadmission_rbac_roleYaml, map[string]*bintree{}},
 			"hiveadmission_rbac_role_binding.yaml": {configHiveadmissionHiveadmission_rbac_role_bindingYaml, map[string]*bintree{}},
+			"installfailurerule-webhook.yaml":      {configHiveadmissionInstallfailureruleWebhookYaml, map[string]*bintree{}},
 			"machinepool-webhook.yaml":             {configHiveadmissionMachinepoolWebhookYaml, map[string]*bintree{}},
 			"selectorsyncset-webhook.yaml":         {configHiveadmissionSelectorsyncsetWebhookYaml, map[string]*bintree{}},
 			"service-account.yaml":                 {configHiveadmissionServiceAccountYaml, map[string]*bintree{}},
 			"service.yaml":                         {configHiveadmissionServiceYaml, map[string]*bintree{}},
 			"syncset-webhook.yaml":                 {configHiveadmissionSyncsetWebhookYaml, map[string]*bintree{}},
 		}},
+		"installfailurerules": {nil, map[string]*bintree{
+			"installfailurerules.yaml": {configInstallfailurerulesInstallfailurerulesYaml, map[string]*bintree{}},
+		}},
 		"manager": {nil, map[string]*bintree{
 			"deployment.yaml": {configManagerDeploymentYaml, map[string]*bintree{}},
 			"service.yaml":    {configManagerServiceYaml, map[string]*bintree{}},
@@ -5452,6 +7355,9 @@ var _bintree = &bintree{nil, map[string]*bintree{
 			"hive_frontend_role.yaml":            {configRbacHive_frontend_roleYaml, map[string]*bintree{}},
 			"hive_frontend_role_binding.yaml":    {configRbacHive_frontend_role_bindingYaml, map[string]*bintree{}},
 			"hive_frontend_serviceaccount.yaml":  {configRbacHive_frontend_serviceaccountYaml, map[string]*bintree{}},
+			"hive_imageset_role.yaml":            {configRbacHive_imageset_roleYaml, map[string]*bintree{}},
+			"hive_imageset_role_binding.yaml":    {configRbacHive_imageset_role_bindingYaml, map[string]*bintree{}},
+			"hive_imageset_serviceaccount.yaml":  {configRbacHive_imageset_serviceaccountYaml, map[string]*bintree{}},
 			"hive_reader_role.yaml":              {configRbacHive_reader_roleYaml, map[string]*bintree{}},
 			"hive_reader_role_binding.yaml":      {configRbacHive_reader_role_bindingYaml, map[string]*bintree{}},
 		}},
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 
 	log "github.com/sirupsen/logrus"
 
@@ -14,6 +15,7 @@ import (
 	"github.com/openshift/hive/pkg/constants"
 	hiveconstants "github.com/openshift/hive/pkg/constants"
 	"github.com/openshift/hive/pkg/controller/images"
+	"github.com/openshift/hive/pkg/installlogarchive"
 	"github.com/openshift/hive/pkg/operator/assets"
 	"github.com/openshift/hive/pkg/operator/util"
 	"github.com/openshift/hive/pkg/resource"
@@ -27,6 +29,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/kubernetes/scheme"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -86,6 +89,33 @@ func (r *ReconcileHiveConfig) deployHive(hLog log.FieldLogger, h *resource.Helpe
 		hiveContainer.Env = append(hiveContainer.Env, syncsetReapplyIntervalEnvVar)
 	}
 
+	if syncSetDriftCheckInterval := instance.Spec.SyncSetDriftCheckInterval; syncSetDriftCheckInterval != "" {
+		syncsetDriftCheckIntervalEnvVar := corev1.EnvVar{
+			Name:  "SYNCSET_DRIFT_CHECK_INTERVAL",
+			Value: syncSetDriftCheckInterval,
+		}
+
+		hiveContainer.Env = append(hiveContainer.Env, syncsetDriftCheckIntervalEnvVar)
+	}
+
+	hiveContainer.Env = append(hiveContainer.Env, controllersConfigEnvVars(instance.Spec.ControllersConfig)...)
+	hiveContainer.Env = append(hiveContainer.Env, acmeEnvVars(instance.Spec.ACME)...)
+	hiveContainer.Env = append(hiveContainer.Env, releaseImagePolicyEnvVars(instance.Spec.ReleaseImagePolicy)...)
+	if archive := instance.Spec.InstallLogArchive; archive != nil {
+		if err := installlogarchive.ValidateConfig(archive); err != nil {
+			hLog.WithError(err).Error("invalid install log archive configuration")
+			return err
+		}
+	}
+	hiveContainer.Env = append(hiveContainer.Env, installLogArchiveEnvVars(instance.Spec.InstallLogArchive)...)
+
+	if window := instance.Spec.CertificateExpiryWarningWindow; window != nil {
+		hiveContainer.Env = append(hiveContainer.Env, corev1.EnvVar{
+			Name:  constants.CertificateExpiryWarningWindowEnvVar,
+			Value: window.Duration.String(),
+		})
+	}
+
 	addManagedDomainsVolume(&hiveDeployment.Spec.Template.Spec, mdConfigMap.Name)
 
 	// By default we will try to gather logs on failed installs:
@@ -112,6 +142,20 @@ func (r *ReconcileHiveConfig) deployHive(hLog log.FieldLogger, h *resource.Helpe
 		hiveContainer.Env = append(hiveContainer.Env, tmpEnvVar)
 	}
 
+	if instance.Spec.HiveManagedKubeconfig.Enabled {
+		hLog.Info("hive-managed kubeconfigs enabled")
+		hiveContainer.Env = append(hiveContainer.Env, corev1.EnvVar{
+			Name:  hiveconstants.HiveManagedKubeconfigEnvVar,
+			Value: "true",
+		})
+		if interval := instance.Spec.HiveManagedKubeconfig.RotationInterval; interval != nil {
+			hiveContainer.Env = append(hiveContainer.Env, corev1.EnvVar{
+				Name:  hiveconstants.HiveManagedKubeconfigRotationIntervalEnvVar,
+				Value: interval.Duration.String(),
+			})
+		}
+	}
+
 	if instance.Spec.DeprovisionsDisabled != nil && *instance.Spec.DeprovisionsDisabled {
 		hLog.Info("deprovisions disabled in hiveconfig")
 		tmpEnvVar := corev1.EnvVar{
@@ -142,12 +186,9 @@ func (r *ReconcileHiveConfig) deployHive(hLog log.FieldLogger, h *resource.Helpe
 		hiveDeployment.Spec.Replicas = &replicas
 	}
 
-	result, err := h.ApplyRuntimeObject(hiveDeployment, scheme.Scheme)
-	if err != nil {
-		hLog.WithError(err).Error("error applying deployment")
+	if err := r.deployControllersShards(hLog, h, instance, hiveDeployment); err != nil {
 		return err
 	}
-	hLog.Infof("deployment applied (%s)", result)
 
 	applyAssets := []string{
 		"config/manager/service.yaml",
@@ -156,26 +197,34 @@ func (r *ReconcileHiveConfig) deployHive(hLog log.FieldLogger, h *resource.Helpe
 		"config/rbac/hive_frontend_role_binding.yaml",
 		"config/rbac/hive_frontend_serviceaccount.yaml",
 
+		"config/rbac/hive_imageset_role.yaml",
+		"config/rbac/hive_imageset_role_binding.yaml",
+		"config/rbac/hive_imageset_serviceaccount.yaml",
+
 		// Due to bug with OLM not updating CRDs on upgrades, we are re-applying
 		// the latest in the operator to ensure updates roll out.
 		// TODO: Attempt removing this once Hive is running purely on 4.x,
 		// as it requires a significant privilege escalation we would rather
 		// leave in the hands of OLM.
 		"config/crds/hive_v1_checkpoint.yaml",
+		"config/crds/hive_v1_clusterclaim.yaml",
 		"config/crds/hive_v1_clusterdeployment.yaml",
 		"config/crds/hive_v1_clusterdeprovision.yaml",
 		"config/crds/hive_v1_clusterimageset.yaml",
+		"config/crds/hive_v1_clusterpool.yaml",
 		"config/crds/hive_v1_clusterprovision.yaml",
 		"config/crds/hive_v1_clusterstate.yaml",
+		"config/crds/hive_v1_clusterupgradepolicy.yaml",
 		"config/crds/hive_v1_dnszone.yaml",
 		"config/crds/hive_v1_hiveconfig.yaml",
+		"config/crds/hive_v1_installfailurerule.yaml",
 		"config/crds/hive_v1_selectorsyncidentityprovider.yaml",
 		"config/crds/hive_v1_selectorsyncset.yaml",
 		"config/crds/hive_v1_syncidentityprovider.yaml",
 		"config/crds/hive_v1_syncset.yaml",
 		"config/crds/hive_v1_syncsetinstance.yaml",
 
-		"config/configmaps/install-log-regexes-configmap.yaml",
+		"config/installfailurerules/installfailurerules.yaml",
 	}
 
 	// In very rare cases we use OpenShift specific types which will not apply if running on
@@ -188,7 +237,7 @@ func (r *ReconcileHiveConfig) deployHive(hLog log.FieldLogger, h *resource.Helpe
 	}
 
 	for _, a := range applyAssets {
-		err = util.ApplyAsset(h, a, hLog)
+		err := util.ApplyAsset(h, a, hLog)
 		if err != nil {
 			return err
 		}
@@ -197,7 +246,7 @@ func (r *ReconcileHiveConfig) deployHive(hLog log.FieldLogger, h *resource.Helpe
 	if r.runningOnOpenShift(hLog) {
 		hLog.Info("deploying OpenShift specific assets")
 		for _, a := range openshiftSpecificAssets {
-			err = util.ApplyAsset(h, a, hLog)
+			err := util.ApplyAsset(h, a, hLog)
 			if err != nil {
 				return err
 			}
@@ -236,6 +285,182 @@ func (r *ReconcileHiveConfig) deployHive(hLog log.FieldLogger, h *resource.Helpe
 	return nil
 }
 
+// deployControllersShards deploys the hive controllers as a single deployment, or as one deployment per shard when
+// HiveConfig splits the controllers into shards. Deployments for shards which are no longer needed are removed.
+func (r *ReconcileHiveConfig) deployControllersShards(hLog log.FieldLogger, h *resource.Helper, instance *hivev1.HiveConfig, hiveDeployment *appsv1.Deployment) error {
+	deployments := []*appsv1.Deployment{hiveDeployment}
+	if shardCount := int(instance.Spec.ControllerShards); shardCount > 1 {
+		hLog.WithField("shardCount", shardCount).Info("deploying sharded hive controllers")
+		deployments = make([]*appsv1.Deployment, shardCount)
+		for i := range deployments {
+			deployments[i] = controllersShardDeployment(hiveDeployment, i, shardCount)
+		}
+	}
+
+	deploymentNames := sets.NewString()
+	for _, deployment := range deployments {
+		result, err := h.ApplyRuntimeObject(deployment, scheme.Scheme)
+		if err != nil {
+			hLog.WithError(err).WithField("deployment", deployment.Name).Error("error applying deployment")
+			return err
+		}
+		hLog.WithField("deployment", deployment.Name).Infof("deployment applied (%s)", result)
+		deploymentNames.Insert(deployment.Name)
+	}
+
+	existingDeployments := &appsv1.DeploymentList{}
+	if err := r.List(context.TODO(), existingDeployments, client.InNamespace(constants.HiveNamespace)); err != nil {
+		hLog.WithError(err).Error("error listing deployments")
+		return err
+	}
+	for i, deployment := range existingDeployments.Items {
+		if deploymentNames.Has(deployment.Name) {
+			continue
+		}
+		if _, isShard := deployment.Labels[constants.ControllersShardLabel]; !isShard && deployment.Name != hiveDeployment.Name {
+			continue
+		}
+		if err := r.Delete(context.TODO(), &existingDeployments.Items[i]); err != nil && !errors.IsNotFound(err) {
+			hLog.WithError(err).WithField("deployment", deployment.Name).Error("error deleting obsolete hive controllers deployment")
+			return err
+		}
+		hLog.WithField("deployment", deployment.Name).Info("deleted obsolete hive controllers deployment")
+	}
+	return nil
+}
+
+// controllersConfigEnvVars returns the environment variables which pass the concurrency and rate limit settings of
+// the controllers to the controller manager.
+func controllersConfigEnvVars(controllersConfig *hivev1.ControllersConfig) []corev1.EnvVar {
+	if controllersConfig == nil {
+		return nil
+	}
+	var envVars []corev1.EnvVar
+	if controllersConfig.Default != nil {
+		envVars = append(envVars, controllerConfigEnvVars(constants.DefaultControllerConfigEnvVarPrefix, controllersConfig.Default)...)
+	}
+	for i := range controllersConfig.Controllers {
+		controllerConfig := &controllersConfig.Controllers[i]
+		envVars = append(envVars, controllerConfigEnvVars(controllerConfig.Name, &controllerConfig.Config)...)
+	}
+	return envVars
+}
+
+func controllerConfigEnvVars(controllerName string, controllerConfig *hivev1.ControllerConfig) []corev1.EnvVar {
+	settings := []struct {
+		suffix string
+		value  *int32
+	}{
+		{suffix: constants.ConcurrentReconcilesEnvVarSuffix, value: controllerConfig.ConcurrentReconciles},
+		{suffix: constants.ClientQPSEnvVarSuffix, value: controllerConfig.ClientQPS},
+		{suffix: constants.ClientBurstEnvVarSuffix, value: controllerConfig.ClientBurst},
+		{suffix: constants.QueueQPSEnvVarSuffix, value: controllerConfig.QueueQPS},
+		{suffix: constants.QueueBurstEnvVarSuffix, value: controllerConfig.QueueBurst},
+	}
+	var envVars []corev1.EnvVar
+	for _, setting := range settings {
+		if setting.value == nil {
+			continue
+		}
+		envVars = append(envVars, corev1.EnvVar{
+			Name:  constants.ControllerConfigEnvVar(controllerName, setting.suffix),
+			Value: strconv.Itoa(int(*setting.value)),
+		})
+	}
+	return envVars
+}
+
+// acmeEnvVars returns the environment variables which pass the settings of the ACME server generating certificate
+// bundles to the controller manager.
+func acmeEnvVars(acme *hivev1.ACMEConfig) []corev1.EnvVar {
+	if acme == nil {
+		return nil
+	}
+	envVars := []corev1.EnvVar{
+		{Name: constants.ACMEDirectoryURLEnvVar, Value: acme.DirectoryURL},
+		{Name: constants.ACMEAccountKeySecretEnvVar, Value: acme.AccountKeySecretRef.Name},
+	}
+	if acme.Email != "" {
+		envVars = append(envVars, corev1.EnvVar{Name: constants.ACMEEmailEnvVar, Value: acme.Email})
+	}
+	if acme.CASecretRef != nil {
+		envVars = append(envVars, corev1.EnvVar{Name: constants.ACMECASecretEnvVar, Value: acme.CASecretRef.Name})
+	}
+	if acme.RenewBefore != nil {
+		envVars = append(envVars, corev1.EnvVar{Name: constants.ACMERenewBeforeEnvVar, Value: acme.RenewBefore.Duration.String()})
+	}
+	return envVars
+}
+
+// releaseImagePolicyEnvVars returns the environment variables used to tell the controller manager and the admission
+// webhooks the release image policy.
+func releaseImagePolicyEnvVars(policy *hivev1.ReleaseImagePolicy) []corev1.EnvVar {
+	if policy == nil {
+		return nil
+	}
+	var envVars []corev1.EnvVar
+	if len(policy.AllowedRegistries) > 0 {
+		envVars = append(envVars, corev1.EnvVar{Name: constants.ReleaseImageAllowedRegistriesEnvVar, Value: strings.Join(policy.AllowedRegistries, ",")})
+	}
+	if policy.RequireDigest {
+		envVars = append(envVars, corev1.EnvVar{Name: constants.ReleaseImageRequireDigestEnvVar, Value: "true"})
+	}
+	if verification := policy.SignatureVerification; verification != nil {
+		envVars = append(envVars,
+			corev1.EnvVar{Name: constants.ReleaseImagePublicKeysSecretEnvVar, Value: verification.PublicKeysSecretRef.Name},
+			corev1.EnvVar{Name: constants.ReleaseImageSignatureStoresEnvVar, Value: strings.Join(verification.SignatureStores, ",")},
+		)
+	}
+	return envVars
+}
+
+// installLogArchiveEnvVars returns the environment variables which pass the settings of the object store in which
+// install logs are archived to the controller manager.
+func installLogArchiveEnvVars(archive *hivev1.InstallLogArchiveConfig) []corev1.EnvVar {
+	if archive == nil {
+		return nil
+	}
+	envVars := []corev1.EnvVar{
+		{Name: constants.InstallLogArchiveBucketEnvVar, Value: archive.Bucket},
+		{Name: constants.InstallLogArchiveCredentialsSecretEnvVar, Value: archive.CredentialsSecretRef.Name},
+	}
+	if archive.Endpoint != "" {
+		envVars = append(envVars, corev1.EnvVar{Name: constants.InstallLogArchiveEndpointEnvVar, Value: archive.Endpoint})
+	}
+	if archive.Region != "" {
+		envVars = append(envVars, corev1.EnvVar{Name: constants.InstallLogArchiveRegionEnvVar, Value: archive.Region})
+	}
+	if archive.Prefix != "" {
+		envVars = append(envVars, corev1.EnvVar{Name: constants.InstallLogArchivePrefixEnvVar, Value: archive.Prefix})
+	}
+	if archive.Retention != nil {
+		envVars = append(envVars, corev1.EnvVar{Name: constants.InstallLogArchiveRetentionEnvVar, Value: archive.Retention.Duration.String()})
+	}
+	return envVars
+}
+
+// controllersShardDeployment returns the deployment of the hive controllers for the given shard.
+func controllersShardDeployment(hiveDeployment *appsv1.Deployment, index, count int) *appsv1.Deployment {
+	shard := strconv.Itoa(index)
+	deployment := hiveDeployment.DeepCopy()
+	deployment.Name = fmt.Sprintf("%s-shard-%d", hiveDeployment.Name, index)
+	deployment.Labels[constants.ControllersShardLabel] = shard
+	deployment.Spec.Selector.MatchLabels[constants.ControllersShardLabel] = shard
+	deployment.Spec.Template.Labels[constants.ControllersShardLabel] = shard
+	container := &deployment.Spec.Template.Spec.Containers[0]
+	container.Env = append(container.Env,
+		corev1.EnvVar{
+			Name:  constants.ControllersShardEnvVar,
+			Value: shard,
+		},
+		corev1.EnvVar{
+			Name:  constants.ControllersShardCountEnvVar,
+			Value: strconv.Itoa(count),
+		},
+	)
+	return deployment
+}
+
 func (r *ReconcileHiveConfig) includeAdditionalCAs(hLog log.FieldLogger, h *resource.Helper, instance *hivev1.HiveConfig, hiveDeployment *appsv1.Deployment) error {
 	additionalCA := &bytes.Buffer{}
 	for _, clientCARef := range instance.Spec.AdditionalCertificateAuthoritiesSecretRef {
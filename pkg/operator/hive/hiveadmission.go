@@ -79,6 +79,9 @@ func (r *ReconcileHiveConfig) deployHiveAdmission(hLog log.FieldLogger, h *resou
 
 	addManagedDomainsVolume(&hiveAdmDeployment.Spec.Template.Spec, mdConfigMap.Name)
 
+	hiveAdmContainer := &hiveAdmDeployment.Spec.Template.Spec.Containers[0]
+	hiveAdmContainer.Env = append(hiveAdmContainer.Env, releaseImagePolicyEnvVars(instance.Spec.ReleaseImagePolicy)...)
+
 	result, err := h.ApplyRuntimeObject(hiveAdmDeployment, scheme.Scheme)
 	if err != nil {
 		hLog.WithError(err).Error("error applying deployment")
@@ -93,10 +96,13 @@ func (r *ReconcileHiveConfig) deployHiveAdmission(hLog log.FieldLogger, h *resou
 	webhooks := map[string]runtime.Object{}
 	validatingWebhooks := []*admregv1.ValidatingWebhookConfiguration{}
 	for _, yaml := range []string{
+		"config/hiveadmission/clusterclaim-webhook.yaml",
 		"config/hiveadmission/clusterdeployment-webhook.yaml",
 		"config/hiveadmission/clusterimageset-webhook.yaml",
 		"config/hiveadmission/clusterprovision-webhook.yaml",
+		"config/hiveadmission/clusterupgradepolicy-webhook.yaml",
 		"config/hiveadmission/dnszones-webhook.yaml",
+		"config/hiveadmission/installfailurerule-webhook.yaml",
 		"config/hiveadmission/machinepool-webhook.yaml",
 		"config/hiveadmission/syncset-webhook.yaml",
 		"config/hiveadmission/selectorsyncset-webhook.yaml",
@@ -0,0 +1,107 @@
+package releaseimage
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+)
+
+const (
+	defaultRegistry   = "docker.io"
+	defaultRepository = "library"
+)
+
+var digestRegexp = regexp.MustCompile(`^[a-z0-9]+(?:[.+_-][a-z0-9]+)*:[a-fA-F0-9]{32,}$`)
+
+// PolicyFromEnvironment returns the release image policy set in the environment by the operator from HiveConfig, or
+// nil if there is no policy.
+func PolicyFromEnvironment() (*hivev1.ReleaseImagePolicy, error) {
+	policy := &hivev1.ReleaseImagePolicy{}
+	for _, registry := range strings.Split(os.Getenv(constants.ReleaseImageAllowedRegistriesEnvVar), ",") {
+		if registry = strings.TrimSpace(registry); registry != "" {
+			policy.AllowedRegistries = append(policy.AllowedRegistries, registry)
+		}
+	}
+	if requireDigest := os.Getenv(constants.ReleaseImageRequireDigestEnvVar); requireDigest != "" {
+		var err error
+		if policy.RequireDigest, err = strconv.ParseBool(requireDigest); err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %v", constants.ReleaseImageRequireDigestEnvVar, err)
+		}
+	}
+	if secretName := os.Getenv(constants.ReleaseImagePublicKeysSecretEnvVar); secretName != "" {
+		verification := &hivev1.ReleaseImageSignatureVerification{
+			PublicKeysSecretRef: corev1.LocalObjectReference{Name: secretName},
+		}
+		for _, store := range strings.Split(os.Getenv(constants.ReleaseImageSignatureStoresEnvVar), ",") {
+			if store = strings.TrimSpace(store); store == "" {
+				continue
+			}
+			if u, err := url.Parse(store); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+				return nil, fmt.Errorf("invalid signature store URL in %s: %s", constants.ReleaseImageSignatureStoresEnvVar, store)
+			}
+			verification.SignatureStores = append(verification.SignatureStores, store)
+		}
+		if len(verification.SignatureStores) == 0 {
+			return nil, fmt.Errorf("%s is required with %s", constants.ReleaseImageSignatureStoresEnvVar, constants.ReleaseImagePublicKeysSecretEnvVar)
+		}
+		policy.SignatureVerification = verification
+	}
+	if len(policy.AllowedRegistries) == 0 && !policy.RequireDigest && policy.SignatureVerification == nil {
+		return nil, nil
+	}
+	return policy, nil
+}
+
+// CheckPolicy returns an error describing why the release image is not allowed by the policy, or nil if the release
+// image is allowed. All release images are allowed by a nil policy. The signatures required by the policy are not
+// checked, see SignatureVerifier.
+func CheckPolicy(policy *hivev1.ReleaseImagePolicy, releaseImage string) error {
+	if policy == nil {
+		return nil
+	}
+	name, digest := splitImage(releaseImage)
+	if (policy.RequireDigest || policy.SignatureVerification != nil) && digest == "" {
+		return fmt.Errorf("release image %s must be referenced by digest", releaseImage)
+	}
+	if digest != "" && !digestRegexp.MatchString(digest) {
+		return fmt.Errorf("release image %s has an invalid digest", releaseImage)
+	}
+	if len(policy.AllowedRegistries) == 0 {
+		return nil
+	}
+	for _, registry := range policy.AllowedRegistries {
+		registry = strings.TrimSuffix(registry, "/")
+		if name == registry || strings.HasPrefix(name, registry+"/") {
+			return nil
+		}
+	}
+	return fmt.Errorf("release image %s is not from an allowed registry: %s", releaseImage, strings.Join(policy.AllowedRegistries, ", "))
+}
+
+// splitImage splits an image reference into its fully qualified name, including its registry, and its digest.
+// Images without a registry are pulled from docker.io.
+func splitImage(image string) (name, digest string) {
+	name = image
+	if i := strings.Index(name, "@"); i >= 0 {
+		name, digest = name[:i], name[i+1:]
+	}
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name = name[:i]
+	}
+	parts := strings.SplitN(name, "/", 2)
+	switch {
+	case len(parts) == 1:
+		name = strings.Join([]string{defaultRegistry, defaultRepository, name}, "/")
+	case !strings.ContainsAny(parts[0], ".:") && parts[0] != "localhost":
+		name = defaultRegistry + "/" + name
+	}
+	return name, digest
+}
@@ -0,0 +1,197 @@
+package releaseimage
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+)
+
+const testDigest = "sha256:0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"
+
+func TestCheckPolicy(t *testing.T) {
+	cases := []struct {
+		name         string
+		policy       *hivev1.ReleaseImagePolicy
+		releaseImage string
+		expectError  bool
+	}{
+		{
+			name:         "no policy",
+			releaseImage: "example.com/release:latest",
+		},
+		{
+			name:         "allowed registry",
+			policy:       &hivev1.ReleaseImagePolicy{AllowedRegistries: []string{"example.com", "quay.io"}},
+			releaseImage: "quay.io/openshift-release-dev/ocp-release:4.5.2-x86_64",
+		},
+		{
+			name:         "registry not allowed",
+			policy:       &hivev1.ReleaseImagePolicy{AllowedRegistries: []string{"quay.io"}},
+			releaseImage: "example.com/openshift-release-dev/ocp-release:4.5.2-x86_64",
+			expectError:  true,
+		},
+		{
+			name:         "registry with port",
+			policy:       &hivev1.ReleaseImagePolicy{AllowedRegistries: []string{"registry.example.com:5000"}},
+			releaseImage: "registry.example.com:5000/ocp/release:4.5.2",
+		},
+		{
+			name:         "registry prefix of another registry",
+			policy:       &hivev1.ReleaseImagePolicy{AllowedRegistries: []string{"quay.io"}},
+			releaseImage: "quay.io.example.com/ocp/release:4.5.2",
+			expectError:  true,
+		},
+		{
+			name:         "allowed repository path",
+			policy:       &hivev1.ReleaseImagePolicy{AllowedRegistries: []string{"quay.io/openshift-release-dev/"}},
+			releaseImage: "quay.io/openshift-release-dev/ocp-release:4.5.2-x86_64",
+		},
+		{
+			name:         "repository path not allowed",
+			policy:       &hivev1.ReleaseImagePolicy{AllowedRegistries: []string{"quay.io/openshift-release-dev"}},
+			releaseImage: "quay.io/someone/ocp-release:4.5.2-x86_64",
+			expectError:  true,
+		},
+		{
+			name:         "image named like an allowed registry",
+			policy:       &hivev1.ReleaseImagePolicy{AllowedRegistries: []string{"quay.io"}},
+			releaseImage: "quay.io:latest",
+			expectError:  true,
+		},
+		{
+			name:         "docker.io image without registry",
+			policy:       &hivev1.ReleaseImagePolicy{AllowedRegistries: []string{"docker.io/openshift"}},
+			releaseImage: "openshift/origin-release:v4.5",
+		},
+		{
+			name:         "digest required",
+			policy:       &hivev1.ReleaseImagePolicy{RequireDigest: true},
+			releaseImage: "quay.io/openshift-release-dev/ocp-release:4.5.2-x86_64",
+			expectError:  true,
+		},
+		{
+			name:         "digest present",
+			policy:       &hivev1.ReleaseImagePolicy{AllowedRegistries: []string{"quay.io"}, RequireDigest: true},
+			releaseImage: "quay.io/openshift-release-dev/ocp-release@" + testDigest,
+		},
+		{
+			name:         "digest with tag",
+			policy:       &hivev1.ReleaseImagePolicy{AllowedRegistries: []string{"quay.io"}, RequireDigest: true},
+			releaseImage: "quay.io/openshift-release-dev/ocp-release:4.5.2@" + testDigest,
+		},
+		{
+			name:         "invalid digest",
+			policy:       &hivev1.ReleaseImagePolicy{RequireDigest: true},
+			releaseImage: "quay.io/openshift-release-dev/ocp-release@sha256:latest",
+			expectError:  true,
+		},
+		{
+			name:         "digest required for signature verification",
+			policy:       &hivev1.ReleaseImagePolicy{SignatureVerification: &hivev1.ReleaseImageSignatureVerification{}},
+			releaseImage: "quay.io/openshift-release-dev/ocp-release:4.5.2-x86_64",
+			expectError:  true,
+		},
+		{
+			name:         "digest present for signature verification",
+			policy:       &hivev1.ReleaseImagePolicy{SignatureVerification: &hivev1.ReleaseImageSignatureVerification{}},
+			releaseImage: "quay.io/openshift-release-dev/ocp-release@" + testDigest,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CheckPolicy(tc.policy, tc.releaseImage)
+			if tc.expectError {
+				assert.Error(t, err, "expected release image to be rejected")
+			} else {
+				assert.NoError(t, err, "expected release image to be allowed")
+			}
+		})
+	}
+}
+
+func TestPolicyFromEnvironment(t *testing.T) {
+	cases := []struct {
+		name              string
+		allowedRegistries string
+		requireDigest     string
+		publicKeysSecret  string
+		signatureStores   string
+		expectedPolicy    *hivev1.ReleaseImagePolicy
+		expectError       bool
+	}{
+		{
+			name: "no policy",
+		},
+		{
+			name:              "allowed registries",
+			allowedRegistries: "quay.io, registry.example.com:5000,",
+			expectedPolicy: &hivev1.ReleaseImagePolicy{
+				AllowedRegistries: []string{"quay.io", "registry.example.com:5000"},
+			},
+		},
+		{
+			name:           "digest required",
+			requireDigest:  "true",
+			expectedPolicy: &hivev1.ReleaseImagePolicy{RequireDigest: true},
+		},
+		{
+			name:          "digest not required",
+			requireDigest: "false",
+		},
+		{
+			name:          "invalid digest requirement",
+			requireDigest: "sometimes",
+			expectError:   true,
+		}, {
+			name:             "signature verification",
+			publicKeysSecret: "release-keys",
+			signatureStores:  "https://mirror.openshift.com/pub/openshift-v4/signatures/openshift/release, http://signatures.example.com",
+			expectedPolicy: &hivev1.ReleaseImagePolicy{
+				SignatureVerification: &hivev1.ReleaseImageSignatureVerification{
+					PublicKeysSecretRef: corev1.LocalObjectReference{Name: "release-keys"},
+					SignatureStores: []string{
+						"https://mirror.openshift.com/pub/openshift-v4/signatures/openshift/release",
+						"http://signatures.example.com",
+					},
+				},
+			},
+		},
+		{
+			name:             "signature verification without stores",
+			publicKeysSecret: "release-keys",
+			expectError:      true,
+		},
+		{
+			name:             "invalid signature store",
+			publicKeysSecret: "release-keys",
+			signatureStores:  "signatures.example.com",
+			expectError:      true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			os.Setenv(constants.ReleaseImageAllowedRegistriesEnvVar, tc.allowedRegistries)
+			os.Setenv(constants.ReleaseImageRequireDigestEnvVar, tc.requireDigest)
+			defer os.Unsetenv(constants.ReleaseImageAllowedRegistriesEnvVar)
+			defer os.Unsetenv(constants.ReleaseImageRequireDigestEnvVar)
+			os.Setenv(constants.ReleaseImagePublicKeysSecretEnvVar, tc.publicKeysSecret)
+			os.Setenv(constants.ReleaseImageSignatureStoresEnvVar, tc.signatureStores)
+			defer os.Unsetenv(constants.ReleaseImagePublicKeysSecretEnvVar)
+			defer os.Unsetenv(constants.ReleaseImageSignatureStoresEnvVar)
+			policy, err := PolicyFromEnvironment()
+			if tc.expectError {
+				assert.Error(t, err, "expected error reading policy")
+				return
+			}
+			require.NoError(t, err, "unexpected error reading policy")
+			assert.Equal(t, tc.expectedPolicy, policy, "unexpected policy")
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package releaseimage
+
+import (
+	"bytes"
+	"context"
+	// Register the hash functions of signatures.
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/openpgp"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+)
+
+const (
+	// maxSignatures is the number of signatures of a release image fetched from each signature store at most.
+	maxSignatures = 16
+
+	// signatureType is the type of the signatures of release images, as in the containers/image "atomic" signatures.
+	signatureType = "atomic container signature"
+
+	signatureFetchTimeout = 30 * time.Second
+)
+
+// SignatureNotVerifiedError is returned when no signature of a release image could be verified with the trusted
+// public keys. Any other error returned when verifying signatures is transient.
+type SignatureNotVerifiedError struct {
+	ReleaseImage string
+	Reasons      []string
+}
+
+func (e *SignatureNotVerifiedError) Error() string {
+	msg := fmt.Sprintf("release image %s has no signature verified by the trusted public keys", e.ReleaseImage)
+	if len(e.Reasons) > 0 {
+		msg += ": " + strings.Join(e.Reasons, "; ")
+	}
+	return msg
+}
+
+// signature is the signed content of a release image signature.
+type signature struct {
+	Critical struct {
+		Type  string `json:"type"`
+		Image struct {
+			DockerManifestDigest string `json:"docker-manifest-digest"`
+		} `json:"image"`
+	} `json:"critical"`
+}
+
+// SignatureVerifier verifies the signatures of release images required by a release image policy. The signatures
+// are fetched from the signature stores of the policy and verified with the public keys in the secret of the policy.
+type SignatureVerifier struct {
+	client       client.Client
+	httpClient   *http.Client
+	verification *hivev1.ReleaseImageSignatureVerification
+
+	lock sync.Mutex
+	// verified maps the release images with verified signatures to the resource version of the public keys secret
+	// they were verified with, so that signatures are fetched again when the trusted keys change.
+	verified map[string]string
+}
+
+// NewSignatureVerifier returns a verifier for the signatures required by the release image policy, or nil if the
+// policy does not require signatures.
+func NewSignatureVerifier(c client.Client, policy *hivev1.ReleaseImagePolicy) *SignatureVerifier {
+	if policy == nil || policy.SignatureVerification == nil {
+		return nil
+	}
+	return &SignatureVerifier{
+		client:       c,
+		httpClient:   &http.Client{Timeout: signatureFetchTimeout},
+		verification: policy.SignatureVerification,
+		verified:     map[string]string{},
+	}
+}
+
+// Verify returns nil if the release image has a signature verified by the trusted public keys, or a
+// *SignatureNotVerifiedError if it has none. The release image must be referenced by digest.
+func (v *SignatureVerifier) Verify(releaseImage string) error {
+	secret := &corev1.Secret{}
+	if err := v.client.Get(
+		context.TODO(),
+		types.NamespacedName{Namespace: constants.HiveNamespace, Name: v.verification.PublicKeysSecretRef.Name},
+		secret,
+	); err != nil {
+		return fmt.Errorf("cannot read the release image public keys secret: %v", err)
+	}
+	v.lock.Lock()
+	resourceVersion, verified := v.verified[releaseImage]
+	v.lock.Unlock()
+	if verified && resourceVersion == secret.ResourceVersion {
+		return nil
+	}
+	keyring, err := readKeyring(secret)
+	if err != nil {
+		return err
+	}
+	if err := verifySignatures(v.httpClient, keyring, v.verification.SignatureStores, releaseImage); err != nil {
+		return err
+	}
+	v.lock.Lock()
+	v.verified[releaseImage] = secret.ResourceVersion
+	v.lock.Unlock()
+	return nil
+}
+
+// readKeyring reads the ASCII armored public keys in every key of the secret.
+func readKeyring(secret *corev1.Secret) (openpgp.EntityList, error) {
+	var keyring openpgp.EntityList
+	for key, data := range secret.Data {
+		entities, err := openpgp.ReadArmoredKeyRing(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("cannot read public keys from key %s of secret %s: %v", key, secret.Name, err)
+		}
+		keyring = append(keyring, entities...)
+	}
+	if len(keyring) == 0 {
+		return nil, fmt.Errorf("secret %s has no public keys", secret.Name)
+	}
+	return keyring, nil
+}
+
+// verifySignatures looks for a signature of the release image verified by the keyring in the signature stores. The
+// signatures of an image are stored at <store>/<algorithm>=<digest>/signature-<n>, n counting from 1.
+func verifySignatures(httpClient *http.Client, keyring openpgp.EntityList, stores []string, releaseImage string) error {
+	_, digest := splitImage(releaseImage)
+	if digest == "" {
+		return &SignatureNotVerifiedError{ReleaseImage: releaseImage, Reasons: []string{"the release image is not referenced by digest"}}
+	}
+	notVerified := &SignatureNotVerifiedError{ReleaseImage: releaseImage}
+	var fetchErr error
+	for _, store := range stores {
+		baseURL := fmt.Sprintf("%s/%s", strings.TrimSuffix(store, "/"), strings.Replace(digest, ":", "=", 1))
+		for i := 1; i <= maxSignatures; i++ {
+			url := fmt.Sprintf("%s/signature-%d", baseURL, i)
+			data, err := fetchSignature(httpClient, url)
+			if err != nil {
+				fetchErr = err
+				break
+			}
+			if data == nil {
+				break
+			}
+			if err := verifySignature(keyring, data, digest); err != nil {
+				notVerified.Reasons = append(notVerified.Reasons, fmt.Sprintf("%s: %v", url, err))
+				continue
+			}
+			return nil
+		}
+	}
+	// A store that could not be reached may hold a valid signature, so only report the release image as not
+	// verified once every store has been searched.
+	if fetchErr != nil {
+		return fetchErr
+	}
+	return notVerified
+}
+
+// fetchSignature returns the signature at the URL, or nil if there is no signature at the URL.
+func fetchSignature(httpClient *http.Client, url string) ([]byte, error) {
+	resp, err := httpClient.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("cannot fetch signature %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusForbidden:
+		// Stores backed by object storage return 403 for missing objects.
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("cannot fetch signature %s: %s", url, resp.Status)
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("cannot fetch signature %s: %v", url, err)
+	}
+	return data, nil
+}
+
+// verifySignature verifies that the signed message was signed by a key in the keyring and that it signs the digest.
+func verifySignature(keyring openpgp.EntityList, data []byte, digest string) error {
+	md, err := openpgp.ReadMessage(bytes.NewReader(data), keyring, nil, nil)
+	if err != nil {
+		return fmt.Errorf("invalid signature: %v", err)
+	}
+	if !md.IsSigned || md.SignedBy == nil {
+		return fmt.Errorf("not signed by a trusted key")
+	}
+	content, err := ioutil.ReadAll(md.UnverifiedBody)
+	if err != nil {
+		return fmt.Errorf("invalid signature: %v", err)
+	}
+	// The signature is only checked once the whole body has been read.
+	if md.SignatureError != nil {
+		return fmt.Errorf("invalid signature: %v", md.SignatureError)
+	}
+	sig := &signature{}
+	if err := json.Unmarshal(content, sig); err != nil {
+		return fmt.Errorf("invalid signature content: %v", err)
+	}
+	if sig.Critical.Type != signatureType {
+		return fmt.Errorf("unexpected signature type %q", sig.Critical.Type)
+	}
+	if sig.Critical.Image.DockerManifestDigest != digest {
+		return fmt.Errorf("signature is for digest %s", sig.Critical.Image.DockerManifestDigest)
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package releaseimage
+
+import (
+	"bytes"
+	"crypto"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/openpgp"
+	"golang.org/x/crypto/openpgp/armor"
+	"golang.org/x/crypto/openpgp/packet"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+)
+
+const (
+	testReleaseImage  = "quay.io/openshift-release-dev/ocp-release@" + testDigest
+	testKeysSecret    = "release-keys"
+	testSignaturePath = "/sha256=0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef/signature-"
+)
+
+func TestSignatureVerifier(t *testing.T) {
+	trusted := testEntity(t)
+	untrusted := testEntity(t)
+	otherDigest := "sha256:" + strings.Repeat("f", 64)
+	cases := []struct {
+		name         string
+		releaseImage string
+		stores       [][]string
+		expectError  bool
+		expectNotVer bool
+	}{
+		{
+			name:         "valid signature",
+			releaseImage: testReleaseImage,
+			stores:       [][]string{{testSign(t, trusted, testDigest)}},
+		},
+		{
+			name:         "second signature valid",
+			releaseImage: testReleaseImage,
+			stores:       [][]string{{testSign(t, untrusted, testDigest), testSign(t, trusted, testDigest)}},
+		},
+		{
+			name:         "valid signature in second store",
+			releaseImage: testReleaseImage,
+			stores:       [][]string{{}, {testSign(t, trusted, testDigest)}},
+		},
+		{
+			name:         "no signatures",
+			releaseImage: testReleaseImage,
+			stores:       [][]string{{}},
+			expectError:  true,
+			expectNotVer: true,
+		},
+		{
+			name:         "signature for other digest",
+			releaseImage: testReleaseImage,
+			stores:       [][]string{{testSign(t, trusted, otherDigest)}},
+			expectError:  true,
+			expectNotVer: true,
+		},
+		{
+			name:         "untrusted signature",
+			releaseImage: testReleaseImage,
+			stores:       [][]string{{testSign(t, untrusted, testDigest)}},
+			expectError:  true,
+			expectNotVer: true,
+		},
+		{
+			name:         "corrupt signature",
+			releaseImage: testReleaseImage,
+			stores:       [][]string{{"not a signature"}},
+			expectError:  true,
+			expectNotVer: true,
+		},
+		{
+			name:         "release image with tag",
+			releaseImage: "quay.io/openshift-release-dev/ocp-release:4.5.2-x86_64",
+			stores:       [][]string{{testSign(t, trusted, testDigest)}},
+			expectError:  true,
+			expectNotVer: true,
+		},
+		{
+			name:         "store unavailable",
+			releaseImage: testReleaseImage,
+			stores:       [][]string{nil},
+			expectError:  true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var storeURLs []string
+			for _, signatures := range tc.stores {
+				server := testStore(signatures)
+				defer server.Close()
+				storeURLs = append(storeURLs, server.URL)
+			}
+			v := NewSignatureVerifier(
+				fake.NewFakeClient(testKeysSecretWith(t, trusted)),
+				&hivev1.ReleaseImagePolicy{
+					SignatureVerification: &hivev1.ReleaseImageSignatureVerification{
+						PublicKeysSecretRef: corev1.LocalObjectReference{Name: testKeysSecret},
+						SignatureStores:     storeURLs,
+					},
+				},
+			)
+			err := v.Verify(tc.releaseImage)
+			if !tc.expectError {
+				assert.NoError(t, err, "expected release image to be verified")
+				return
+			}
+			require.Error(t, err, "expected release image not to be verified")
+			_, notVerified := err.(*SignatureNotVerifiedError)
+			assert.Equal(t, tc.expectNotVer, notVerified, "unexpected error type: %v", err)
+		})
+	}
+}
+
+func TestSignatureVerifierCache(t *testing.T) {
+	trusted := testEntity(t)
+	signatures := []string{testSign(t, trusted, testDigest)}
+	server := testStore(signatures)
+	defer server.Close()
+	v := NewSignatureVerifier(
+		fake.NewFakeClient(testKeysSecretWith(t, trusted)),
+		&hivev1.ReleaseImagePolicy{
+			SignatureVerification: &hivev1.ReleaseImageSignatureVerification{
+				PublicKeysSecretRef: corev1.LocalObjectReference{Name: testKeysSecret},
+				SignatureStores:     []string{server.URL},
+			},
+		},
+	)
+	require.NoError(t, v.Verify(testReleaseImage), "expected release image to be verified")
+	server.Close()
+	assert.NoError(t, v.Verify(testReleaseImage), "expected verified release image to be cached")
+}
+
+func TestNewSignatureVerifierWithoutVerification(t *testing.T) {
+	assert.Nil(t, NewSignatureVerifier(fake.NewFakeClient(), nil), "expected no verifier without policy")
+	assert.Nil(t, NewSignatureVerifier(fake.NewFakeClient(), &hivev1.ReleaseImagePolicy{RequireDigest: true}),
+		"expected no verifier without signature verification")
+}
+
+// testStore serves the signatures of testDigest, or fails every request if signatures is nil.
+func testStore(signatures []string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if signatures == nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		for i, sig := range signatures {
+			if r.URL.Path == fmt.Sprintf("%s%d", testSignaturePath, i+1) {
+				w.Write([]byte(sig))
+				return
+			}
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+}
+
+func testEntity(t *testing.T) *openpgp.Entity {
+	entity, err := openpgp.NewEntity("Release", "", "release@example.com", &packet.Config{DefaultHash: crypto.SHA256})
+	require.NoError(t, err, "cannot create test key")
+	return entity
+}
+
+func testKeysSecretWith(t *testing.T, entity *openpgp.Entity) *corev1.Secret {
+	buf := &bytes.Buffer{}
+	w, err := armor.Encode(buf, openpgp.PublicKeyType, nil)
+	require.NoError(t, err, "cannot armor test key")
+	require.NoError(t, entity.Serialize(w), "cannot serialize test key")
+	require.NoError(t, w.Close(), "cannot armor test key")
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: constants.HiveNamespace, Name: testKeysSecret},
+		Data:       map[string][]byte{"release.asc": buf.Bytes()},
+	}
+}
+
+func testSign(t *testing.T, entity *openpgp.Entity, digest string) string {
+	buf := &bytes.Buffer{}
+	w, err := openpgp.Sign(buf, entity, nil, nil)
+	require.NoError(t, err, "cannot sign test signature")
+	fmt.Fprintf(w, `{"critical":{"type":%q,"image":{"docker-manifest-digest":%q},"identity":{"docker-reference":"quay.io/openshift-release-dev/ocp-release"}},"optional":{}}`, signatureType, digest)
+	require.NoError(t, w.Close(), "cannot sign test signature")
+	return buf.String()
+}
@@ -0,0 +1,104 @@
+package remoteclient
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/rest"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+)
+
+const (
+	// cacheSyncTimeout is how long a read from the informer cache of a remote cluster waits for the informer of the
+	// kind read to sync.
+	cacheSyncTimeout = 30 * time.Second
+)
+
+// remoteCache is the informer cache of a remote cluster, shared by the cached clients of all controllers. The
+// informers of the cache run until the cache is stopped.
+type remoteCache struct {
+	cache       cache.Cache
+	scheme      *runtime.Scheme
+	stop        chan struct{}
+	syncTimeout time.Duration
+}
+
+// newRemoteCache creates and starts an informer cache for the remote cluster of the given config. An informer is
+// started for each kind the first time that the kind is read from the cache.
+func newRemoteCache(cfg *rest.Config, scheme *runtime.Scheme, restMapper meta.RESTMapper) (*remoteCache, error) {
+	c, err := cache.New(cfg, cache.Options{Scheme: scheme, Mapper: restMapper})
+	if err != nil {
+		return nil, err
+	}
+	rc := &remoteCache{
+		cache:       c,
+		scheme:      scheme,
+		stop:        make(chan struct{}),
+		syncTimeout: cacheSyncTimeout,
+	}
+	go c.Start(rc.stop)
+	return rc, nil
+}
+
+// stopInformers stops the informers of the cache.
+func (c *remoteCache) stopInformers() {
+	close(c.stop)
+}
+
+// Get reads the object from the cache, once the informer of its kind has synced.
+func (c *remoteCache) Get(ctx context.Context, key client.ObjectKey, obj runtime.Object) error {
+	gvk, err := apiutil.GVKForObject(obj, c.scheme)
+	if err != nil {
+		return err
+	}
+	if err := c.waitForSync(gvk); err != nil {
+		return err
+	}
+	return c.cache.Get(ctx, key, obj)
+}
+
+// List reads the objects from the cache, once the informer of their kind has synced.
+func (c *remoteCache) List(ctx context.Context, list runtime.Object, opts ...client.ListOptionFunc) error {
+	gvk, err := apiutil.GVKForObject(list, c.scheme)
+	if err != nil {
+		return err
+	}
+	gvk.Kind = strings.TrimSuffix(gvk.Kind, "List")
+	if err := c.waitForSync(gvk); err != nil {
+		return err
+	}
+	return c.cache.List(ctx, list, opts...)
+}
+
+// waitForSync waits for the informer of the kind to sync. The informer cache itself waits until the informer syncs
+// or the cache is stopped, which would block reconciles for as long as the remote cluster cannot be listed.
+func (c *remoteCache) waitForSync(gvk schema.GroupVersionKind) error {
+	synced := make(chan error, 1)
+	go func() {
+		informer, err := c.cache.GetInformerForKind(gvk)
+		if err != nil {
+			synced <- err
+			return
+		}
+		if !toolscache.WaitForCacheSync(c.stop, informer.HasSynced) {
+			synced <- errors.Errorf("informer for %s stopped before syncing", gvk)
+			return
+		}
+		synced <- nil
+	}()
+	select {
+	case err := <-synced:
+		return err
+	case <-time.After(c.syncTimeout):
+		return errors.Errorf("timed out waiting for informer for %s to sync", gvk)
+	}
+}
@@ -0,0 +1,104 @@
+package remoteclient
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestRemoteCache(t *testing.T) {
+	cases := []struct {
+		name        string
+		listFails   bool
+		stopped     bool
+		expectError bool
+	}{
+		{
+			name: "synced",
+		},
+		{
+			name:        "list fails",
+			listFails:   true,
+			expectError: true,
+		},
+		{
+			name:        "stopped",
+			stopped:     true,
+			expectError: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newFakeConfigMapServer(t, tc.listFails)
+			defer server.Close()
+			restMapper := meta.NewDefaultRESTMapper(nil)
+			restMapper.Add(schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}, meta.RESTScopeNamespace)
+			c, err := newRemoteCache(&rest.Config{Host: server.URL}, scheme.Scheme, restMapper)
+			require.NoError(t, err, "unexpected error creating cache")
+			c.syncTimeout = time.Second
+			if tc.stopped {
+				c.stopInformers()
+			} else {
+				defer c.stopInformers()
+			}
+
+			cm := &corev1.ConfigMap{}
+			err = c.Get(context.Background(), client.ObjectKey{Namespace: "test-namespace", Name: "test-cm"}, cm)
+			if tc.expectError {
+				assert.Error(t, err, "expected error reading from cache")
+				return
+			}
+			require.NoError(t, err, "unexpected error reading from cache")
+			assert.Equal(t, "test-value", cm.Data["test-key"], "unexpected config map")
+			cms := &corev1.ConfigMapList{}
+			require.NoError(t, c.List(context.Background(), cms), "unexpected error listing from cache")
+			assert.Len(t, cms.Items, 1, "unexpected config maps")
+		})
+	}
+}
+
+// newFakeConfigMapServer serves a list of one config map, or fails to list config maps if listFails is set. Watches
+// are kept open without events until the client disconnects.
+func newFakeConfigMapServer(t *testing.T, listFails bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/configmaps" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.URL.Query().Get("watch") == "true" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusOK)
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+			return
+		}
+		if listFails {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		list := &corev1.ConfigMapList{
+			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "ConfigMapList"},
+			ListMeta: metav1.ListMeta{ResourceVersion: "1"},
+			Items: []corev1.ConfigMap{{
+				ObjectMeta: metav1.ObjectMeta{Namespace: "test-namespace", Name: "test-cm", ResourceVersion: "1"},
+				Data:       map[string]string{"test-key": "test-value"},
+			}},
+		}
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(list), "unexpected error writing response")
+	}))
+}
@@ -5,9 +5,11 @@
 package mock
 
 import (
+	x509 "crypto/x509"
 	gomock "github.com/golang/mock/gomock"
 	remoteclient "github.com/openshift/hive/pkg/remoteclient"
 	dynamic "k8s.io/client-go/dynamic"
+	kubernetes "k8s.io/client-go/kubernetes"
 	rest "k8s.io/client-go/rest"
 	reflect "reflect"
 	client "sigs.k8s.io/controller-runtime/pkg/client"
@@ -51,6 +53,21 @@ func (mr *MockBuilderMockRecorder) Build() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Build", reflect.TypeOf((*MockBuilder)(nil).Build))
 }
 
+// BuildCached mocks base method
+func (m *MockBuilder) BuildCached() (client.Client, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BuildCached")
+	ret0, _ := ret[0].(client.Client)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BuildCached indicates an expected call of BuildCached
+func (mr *MockBuilderMockRecorder) BuildCached() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuildCached", reflect.TypeOf((*MockBuilder)(nil).BuildCached))
+}
+
 // BuildDynamic mocks base method
 func (m *MockBuilder) BuildDynamic() (dynamic.Interface, error) {
 	m.ctrl.T.Helper()
@@ -66,6 +83,21 @@ func (mr *MockBuilderMockRecorder) BuildDynamic() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuildDynamic", reflect.TypeOf((*MockBuilder)(nil).BuildDynamic))
 }
 
+// BuildKubeClient mocks base method
+func (m *MockBuilder) BuildKubeClient() (kubernetes.Interface, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BuildKubeClient")
+	ret0, _ := ret[0].(kubernetes.Interface)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BuildKubeClient indicates an expected call of BuildKubeClient
+func (mr *MockBuilderMockRecorder) BuildKubeClient() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BuildKubeClient", reflect.TypeOf((*MockBuilder)(nil).BuildKubeClient))
+}
+
 // Unreachable mocks base method
 func (m *MockBuilder) Unreachable() bool {
 	m.ctrl.T.Helper()
@@ -110,6 +142,21 @@ func (mr *MockBuilderMockRecorder) RESTConfig() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RESTConfig", reflect.TypeOf((*MockBuilder)(nil).RESTConfig))
 }
 
+// ServingCertificates mocks base method
+func (m *MockBuilder) ServingCertificates() ([]*x509.Certificate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ServingCertificates")
+	ret0, _ := ret[0].([]*x509.Certificate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ServingCertificates indicates an expected call of ServingCertificates
+func (mr *MockBuilderMockRecorder) ServingCertificates() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ServingCertificates", reflect.TypeOf((*MockBuilder)(nil).ServingCertificates))
+}
+
 // UsePrimaryAPIURL mocks base method
 func (m *MockBuilder) UsePrimaryAPIURL() remoteclient.Builder {
 	m.ctrl.T.Helper()
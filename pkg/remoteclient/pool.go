@@ -0,0 +1,282 @@
+package remoteclient
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/openshift/hive/pkg/controller/utils"
+)
+
+const (
+	// defaultIdleTimeout is how long the clients for a remote cluster are kept in the pool without being used.
+	defaultIdleTimeout = 30 * time.Minute
+
+	// sweepInterval is how often the pool is swept for idle clients.
+	sweepInterval = time.Minute
+
+	// restMapperControllerName is the controller name used in the metrics of the discovery requests made by the
+	// REST mappers, which are shared by the clients of all controllers.
+	restMapperControllerName = "remoteclient-rest-mapper"
+
+	// cacheControllerName is the controller name used in the metrics of the requests made by the informer caches,
+	// which are shared by the cached clients of all controllers.
+	cacheControllerName = "remoteclient-cache"
+
+	evictionReasonIdle               = "idle"
+	evictionReasonKubeconfigChanged  = "kubeconfig_changed"
+	evictionReasonProxyChanged       = "proxy_changed"
+	evictionReasonUnreachableChanged = "unreachable_changed"
+)
+
+var (
+	metricRemoteClientCacheHits = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "hive_remote_client_cache_hits_total",
+		Help: "Counter incremented each time the clients for a remote cluster are found in the cache.",
+	}, []string{"controller"})
+	metricRemoteClientCacheMisses = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "hive_remote_client_cache_misses_total",
+		Help: "Counter incremented each time the clients for a remote cluster are not found in the cache.",
+	}, []string{"controller"})
+	metricRemoteClientCacheEvictions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "hive_remote_client_cache_evictions_total",
+		Help: "Counter incremented each time the clients for a remote cluster are evicted from the cache.",
+	}, []string{"reason"})
+	metricRemoteClientCacheSize = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "hive_remote_client_cache_size",
+		Help: "Number of remote clusters with clients in the cache.",
+	})
+
+	// defaultPool is the process-wide pool of remote clients used by the builders returned by NewBuilder.
+	defaultPool = newPool(clock.RealClock{}, defaultIdleTimeout)
+)
+
+func init() {
+	metrics.Registry.MustRegister(metricRemoteClientCacheHits)
+	metrics.Registry.MustRegister(metricRemoteClientCacheMisses)
+	metrics.Registry.MustRegister(metricRemoteClientCacheEvictions)
+	metrics.Registry.MustRegister(metricRemoteClientCacheSize)
+}
+
+// pool caches the REST config and clients for remote clusters, so that the admin kubeconfig of a cluster is not
+// parsed and its clients are not rebuilt, including the discovery of the API of the cluster, on every reconcile.
+// The connections to a cluster are shared by the clients of all controllers through the transport cache of
+// client-go, which is keyed by the TLS config. Each entry also holds an informer cache for the cluster, shared by
+// the cached clients of all controllers, whose informers are stopped when the entry is evicted.
+type pool struct {
+	mu          sync.Mutex
+	clock       clock.Clock
+	idleTimeout time.Duration
+	entries     map[poolKey]*poolEntry
+	lastSweep   time.Time
+}
+
+// poolKey identifies the clients for a remote cluster connecting to either an API URL override or the default
+// API URL. The default API URL is the server of the kubeconfig, which is covered by the version of the entry.
+type poolKey struct {
+	uid types.UID
+	// apiURLOverride is the API URL override used to connect to the cluster, or empty when connecting to the
+	// default API URL.
+	apiURLOverride string
+}
+
+// poolEntryVersion identifies the inputs from which the clients of a remote cluster were built.
+type poolEntryVersion struct {
+	kubeconfigSecretName      string
+	kubeconfigResourceVersion string
+	proxy                     string
+	unreachable               bool
+}
+
+// evictionReason returns the reason for evicting an entry of the given version when the current version is v, or
+// an empty string when the versions match.
+func (v poolEntryVersion) evictionReason(entryVersion poolEntryVersion) string {
+	switch {
+	case entryVersion.kubeconfigSecretName != v.kubeconfigSecretName,
+		entryVersion.kubeconfigResourceVersion != v.kubeconfigResourceVersion:
+		return evictionReasonKubeconfigChanged
+	case entryVersion.proxy != v.proxy:
+		return evictionReasonProxyChanged
+	case entryVersion.unreachable != v.unreachable:
+		return evictionReasonUnreachableChanged
+	}
+	return ""
+}
+
+// poolEntry holds the clients for a remote cluster built from a version of the admin kubeconfig secret.
+type poolEntry struct {
+	version  poolEntryVersion
+	config   *rest.Config
+	lastUsed time.Time
+
+	mu         sync.Mutex
+	clients    map[string]*controllerClients
+	restMapper *dynamicRESTMapper
+	cache      *remoteCache
+	evicted    bool
+}
+
+// controllerClients are the clients for a remote cluster used by a controller. The clients are specific to a
+// controller so that the metrics of their requests are labeled with the controller name.
+type controllerClients struct {
+	client       client.Client
+	cachedClient client.Client
+	dynamic      dynamic.Interface
+	kubeClient   kubernetes.Interface
+}
+
+func newPool(clock clock.Clock, idleTimeout time.Duration) *pool {
+	return &pool{
+		clock:       clock,
+		idleTimeout: idleTimeout,
+		entries:     map[poolKey]*poolEntry{},
+		lastSweep:   clock.Now(),
+	}
+}
+
+// get returns the entry for the given key if it was built from the given version of the admin kubeconfig secret,
+// the proxy settings and the reachability of the cluster. Entries built from another version are evicted.
+func (p *pool) get(key poolKey, version poolEntryVersion, controllerName string) *poolEntry {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sweep()
+	entry, ok := p.entries[key]
+	if !ok {
+		metricRemoteClientCacheMisses.WithLabelValues(controllerName).Inc()
+		return nil
+	}
+	if reason := version.evictionReason(entry.version); reason != "" {
+		p.evict(key, reason)
+		metricRemoteClientCacheMisses.WithLabelValues(controllerName).Inc()
+		return nil
+	}
+	metricRemoteClientCacheHits.WithLabelValues(controllerName).Inc()
+	entry.lastUsed = p.clock.Now()
+	return entry
+}
+
+// add adds an entry for the given key with the given REST config. If another builder has added an entry for the
+// key in the meantime, that entry is returned instead so that clients are not built twice.
+func (p *pool) add(key poolKey, version poolEntryVersion, config *rest.Config) *poolEntry {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if entry, ok := p.entries[key]; ok {
+		reason := version.evictionReason(entry.version)
+		if reason == "" {
+			entry.lastUsed = p.clock.Now()
+			return entry
+		}
+		p.evict(key, reason)
+	}
+	entry := &poolEntry{
+		version:  version,
+		config:   config,
+		lastUsed: p.clock.Now(),
+		clients:  map[string]*controllerClients{},
+	}
+	p.entries[key] = entry
+	metricRemoteClientCacheSize.Set(float64(len(p.entries)))
+	return entry
+}
+
+// sweep evicts the entries which have not been used within the idle timeout. It must be called with the lock
+// held.
+func (p *pool) sweep() {
+	now := p.clock.Now()
+	if now.Sub(p.lastSweep) < sweepInterval {
+		return
+	}
+	p.lastSweep = now
+	for key, entry := range p.entries {
+		if now.Sub(entry.lastUsed) > p.idleTimeout {
+			p.evict(key, evictionReasonIdle)
+		}
+	}
+}
+
+// evict removes the entry for the given key. It must be called with the lock held.
+func (p *pool) evict(key poolKey, reason string) {
+	entry, ok := p.entries[key]
+	if !ok {
+		return
+	}
+	log.WithField("uid", key.uid).WithField("apiURLOverride", key.apiURLOverride).WithField("reason", reason).Debug("evicting remote clients")
+	delete(p.entries, key)
+	entry.stopCache()
+	metricRemoteClientCacheEvictions.WithLabelValues(reason).Inc()
+	metricRemoteClientCacheSize.Set(float64(len(p.entries)))
+}
+
+// withClients calls fn with the clients of the entry for the given controller, holding the lock of the entry so
+// that the clients are built only once.
+func (e *poolEntry) withClients(controllerName string, fn func(*controllerClients) error) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	clients, ok := e.clients[controllerName]
+	if !ok {
+		clients = &controllerClients{}
+		e.clients[controllerName] = clients
+	}
+	return fn(clients)
+}
+
+// restMapperLocked returns the REST mapper shared by the clients of the entry, building it with the given clock on
+// first use. It must be called with the lock of the entry held, such as from the function passed to withClients.
+func (e *poolEntry) restMapperLocked(clock clock.Clock) (*dynamicRESTMapper, error) {
+	if e.restMapper == nil {
+		cfg := rest.CopyConfig(e.config)
+		utils.AddControllerMetricsTransportWrapper(cfg, restMapperControllerName, true)
+		restMapper, err := newDynamicRESTMapper(cfg, clock)
+		if err != nil {
+			return nil, err
+		}
+		e.restMapper = restMapper
+	}
+	return e.restMapper, nil
+}
+
+// cacheLocked returns the informer cache shared by the cached clients of the entry, starting it on first use. It
+// must be called with the lock of the entry held, such as from the function passed to withClients.
+func (e *poolEntry) cacheLocked(clock clock.Clock) (*remoteCache, error) {
+	if e.cache == nil {
+		if e.evicted {
+			return nil, errors.New("remote clients have been evicted")
+		}
+		restMapper, err := e.restMapperLocked(clock)
+		if err != nil {
+			return nil, err
+		}
+		scheme, err := buildRemoteScheme()
+		if err != nil {
+			return nil, err
+		}
+		cfg := rest.CopyConfig(e.config)
+		utils.AddControllerMetricsTransportWrapper(cfg, cacheControllerName, true)
+		if e.cache, err = newRemoteCache(cfg, scheme, restMapper); err != nil {
+			return nil, err
+		}
+	}
+	return e.cache, nil
+}
+
+// stopCache stops the informers of the cache of an evicted entry. Cached clients built from the entry before it
+// was evicted fail to read kinds whose informers had not been started.
+func (e *poolEntry) stopCache() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.evicted = true
+	if e.cache != nil {
+		e.cache.stopInformers()
+	}
+}
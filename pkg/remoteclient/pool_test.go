@@ -0,0 +1,138 @@
+package remoteclient
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+)
+
+func TestPool(t *testing.T) {
+	cases := []struct {
+		name          string
+		modify        func(t *testing.T, c client.Client, cd *hivev1.ClusterDeployment, fakeClock *clock.FakeClock)
+		expectRebuilt bool
+		// expectedEntries is the number of entries in the pool after the change, defaulting to 1.
+		expectedEntries int
+	}{
+		{
+			name:          "cached",
+			expectRebuilt: false,
+		},
+		{
+			name: "kubeconfig changed",
+			modify: func(t *testing.T, c client.Client, cd *hivev1.ClusterDeployment, fakeClock *clock.FakeClock) {
+				secret := &corev1.Secret{}
+				err := c.Get(context.Background(), client.ObjectKey{Namespace: testNamespace, Name: testKubeconfigSecretName}, secret)
+				require.NoError(t, err, "unexpected error getting kubeconfig secret")
+				// The fake client does not update the resource version
+				secret.ResourceVersion = "2"
+				err = c.Update(context.Background(), secret)
+				require.NoError(t, err, "unexpected error updating kubeconfig secret")
+			},
+			expectRebuilt: true,
+		},
+		{
+			name: "proxy changed",
+			modify: func(t *testing.T, c client.Client, cd *hivev1.ClusterDeployment, fakeClock *clock.FakeClock) {
+				cd.Spec.ControlPlaneConfig.Proxy = &hivev1.ControlPlaneProxy{URL: "http://proxy.example.com:3128"}
+			},
+			expectRebuilt: true,
+		},
+		{
+			name: "API URL override changed",
+			modify: func(t *testing.T, c client.Client, cd *hivev1.ClusterDeployment, fakeClock *clock.FakeClock) {
+				setAPIURLOverride(cd, "https://api-override.hive-cluster.example.com:6443")
+				setOverrideActive(cd)
+			},
+			expectRebuilt: true,
+			// The clients for the default API URL are kept until they are idle
+			expectedEntries: 2,
+		},
+		{
+			name: "became unreachable",
+			modify: func(t *testing.T, c client.Client, cd *hivev1.ClusterDeployment, fakeClock *clock.FakeClock) {
+				setUnreachable(cd, true)
+			},
+			expectRebuilt: true,
+		},
+		{
+			name: "used within idle timeout",
+			modify: func(t *testing.T, c client.Client, cd *hivev1.ClusterDeployment, fakeClock *clock.FakeClock) {
+				fakeClock.Step(defaultIdleTimeout - time.Minute)
+			},
+			expectRebuilt: false,
+		},
+		{
+			name: "idle",
+			modify: func(t *testing.T, c client.Client, cd *hivev1.ClusterDeployment, fakeClock *clock.FakeClock) {
+				fakeClock.Step(defaultIdleTimeout + time.Minute)
+			},
+			expectRebuilt: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cd := testClusterDeployment()
+			cd.UID = types.UID("test-uid")
+			kubeconfigSecret := testKubeconfigSecret(t)
+			kubeconfigSecret.ResourceVersion = "1"
+			c := fakeClient(cd, kubeconfigSecret)
+			fakeClock := clock.NewFakeClock(time.Now())
+			p := newPool(fakeClock, defaultIdleTimeout)
+			b := &builder{c: c, cd: cd, controllerName: "test-controller-name", urlToUse: activeURL, pool: p}
+
+			first, err := b.BuildKubeClient()
+			require.NoError(t, err, "unexpected error building client")
+			if tc.modify != nil {
+				tc.modify(t, c, cd, fakeClock)
+			}
+			second, err := b.BuildKubeClient()
+			require.NoError(t, err, "unexpected error building client")
+
+			if tc.expectRebuilt {
+				assert.True(t, first != second, "expected client to be rebuilt")
+			} else {
+				assert.True(t, first == second, "expected client to be cached")
+			}
+			expectedEntries := tc.expectedEntries
+			if expectedEntries == 0 {
+				expectedEntries = 1
+			}
+			assert.Len(t, p.entries, expectedEntries, "unexpected number of pool entries")
+			_, ok := p.entries[poolKey{uid: cd.UID, apiURLOverride: cd.Spec.ControlPlaneConfig.APIURLOverride}]
+			assert.True(t, ok, "expected pool entry for the API URL in use")
+		})
+	}
+}
+
+func TestPoolEvictionStopsCache(t *testing.T) {
+	p := newPool(clock.NewFakeClock(time.Now()), defaultIdleTimeout)
+	key := poolKey{uid: types.UID("test-uid")}
+	entry := p.add(key, poolEntryVersion{}, &rest.Config{})
+	entry.cache = &remoteCache{stop: make(chan struct{})}
+	stop := entry.cache.stop
+
+	p.mu.Lock()
+	p.evict(key, evictionReasonIdle)
+	p.mu.Unlock()
+
+	select {
+	case <-stop:
+	default:
+		t.Error("expected informers to be stopped")
+	}
+	entry.cache = nil
+	_, err := entry.cacheLocked(p.clock)
+	assert.Error(t, err, "expected no cache to be started for an evicted entry")
+}
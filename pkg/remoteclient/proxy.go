@@ -0,0 +1,436 @@
+package remoteclient
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/clock"
+	utilnet "k8s.io/apimachinery/pkg/util/net"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+)
+
+const (
+	proxyUsernameKey  = "username"
+	proxyPasswordKey  = "password"
+	sshPrivateKeyKey  = "ssh-privatekey"
+	defaultSSHPort    = "22"
+	sshConnectTimeout = 30 * time.Second
+)
+
+// Proxy is the proxy through which to connect to the API server of a remote cluster.
+type Proxy struct {
+	// URL is the URL, including any credentials, of the HTTP(S) or SOCKS5 proxy. It is nil when connections are
+	// tunneled through an SSH bastion instead.
+	URL *url.URL
+
+	bastion *sshBastion
+
+	// version changes whenever the proxy settings or the secrets they refer to change.
+	version string
+}
+
+// sshBastion is an SSH bastion host through which connections are tunneled.
+type sshBastion struct {
+	addr   string
+	config *ssh.ClientConfig
+}
+
+// GetProxy returns the proxy through which to connect to the API server of the remote cluster associated with the
+// specified ClusterDeployment. It returns nil when no proxy is configured.
+func GetProxy(c client.Client, cd *hivev1.ClusterDeployment) (*Proxy, error) {
+	proxy := cd.Spec.ControlPlaneConfig.Proxy
+	switch {
+	case proxy == nil:
+		return nil, nil
+	case proxy.SSHBastion != nil:
+		return sshBastionProxy(c, cd.Namespace, proxy.SSHBastion)
+	case proxy.URL != "":
+		return urlProxy(c, cd.Namespace, proxy)
+	default:
+		return nil, nil
+	}
+}
+
+// urlProxy returns the proxy at the URL of the proxy settings.
+func urlProxy(c client.Client, namespace string, proxy *hivev1.ControlPlaneProxy) (*Proxy, error) {
+	proxyURL, err := url.Parse(proxy.URL)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse proxy URL")
+	}
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, errors.Errorf("unsupported proxy URL scheme %q", proxyURL.Scheme)
+	}
+	version := proxy.URL
+	if proxy.CredentialsSecretRef != nil {
+		credentialsSecret := &corev1.Secret{}
+		if err := c.Get(
+			context.Background(),
+			client.ObjectKey{Namespace: namespace, Name: proxy.CredentialsSecretRef.Name},
+			credentialsSecret,
+		); err != nil {
+			return nil, errors.Wrap(err, "could not get proxy credentials secret")
+		}
+		username, ok := credentialsSecret.Data[proxyUsernameKey]
+		if !ok {
+			return nil, errors.Errorf("proxy credentials secret does not contain %q data", proxyUsernameKey)
+		}
+		password, ok := credentialsSecret.Data[proxyPasswordKey]
+		if !ok {
+			return nil, errors.Errorf("proxy credentials secret does not contain %q data", proxyPasswordKey)
+		}
+		proxyURL.User = url.UserPassword(string(username), string(password))
+		version += "@" + credentialsSecret.ResourceVersion
+	}
+	return &Proxy{URL: proxyURL, version: version}, nil
+}
+
+// sshBastionProxy returns the proxy tunneling connections through the SSH bastion.
+func sshBastionProxy(c client.Client, namespace string, bastion *hivev1.SSHBastion) (*Proxy, error) {
+	addr := bastion.Host
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, defaultSSHPort)
+	}
+	keySecret := &corev1.Secret{}
+	if err := c.Get(
+		context.Background(),
+		client.ObjectKey{Namespace: namespace, Name: bastion.PrivateKeySecretRef.Name},
+		keySecret,
+	); err != nil {
+		return nil, errors.Wrap(err, "could not get SSH bastion private key secret")
+	}
+	keyData, ok := keySecret.Data[sshPrivateKeyKey]
+	if !ok {
+		return nil, errors.Errorf("SSH bastion private key secret does not contain %q data", sshPrivateKeyKey)
+	}
+	signer, err := ssh.ParsePrivateKey(keyData)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not parse SSH bastion private key")
+	}
+	hostKeys, err := knownHostKeys(addr, bastion.KnownHosts)
+	if err != nil {
+		return nil, err
+	}
+	config := &ssh.ClientConfig{
+		User:            bastion.User,
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		HostKeyCallback: hostKeyCallback(hostKeys),
+		Timeout:         sshConnectTimeout,
+	}
+	for _, key := range hostKeys {
+		if !sets.NewString(config.HostKeyAlgorithms...).Has(key.Type()) {
+			config.HostKeyAlgorithms = append(config.HostKeyAlgorithms, key.Type())
+		}
+	}
+	version := fmt.Sprintf("ssh://%s@%s@%s\n%s", bastion.User, addr, keySecret.ResourceVersion, strings.Join(bastion.KnownHosts, "\n"))
+	return &Proxy{bastion: &sshBastion{addr: addr, config: config}, version: version}, nil
+}
+
+// knownHostKeys returns the host keys of the given address in the known_hosts entries. Entries with markers, such as
+// revoked keys and certificate authorities, are ignored.
+func knownHostKeys(addr string, knownHosts []string) ([]ssh.PublicKey, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	if port != defaultSSHPort {
+		host = "[" + host + "]:" + port
+	}
+	var keys []ssh.PublicKey
+	for _, entry := range knownHosts {
+		for rest := []byte(entry); len(rest) > 0; {
+			var (
+				marker string
+				hosts  []string
+				key    ssh.PublicKey
+			)
+			marker, hosts, key, _, rest, err = ssh.ParseKnownHosts(rest)
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, errors.Wrap(err, "could not parse SSH bastion known hosts")
+			}
+			if marker != "" {
+				continue
+			}
+			for _, h := range hosts {
+				if h == host {
+					keys = append(keys, key)
+					break
+				}
+			}
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errors.Errorf("SSH bastion known hosts do not contain a host key for %s", host)
+	}
+	return keys, nil
+}
+
+// hostKeyCallback accepts any of the given host keys.
+func hostKeyCallback(hostKeys []ssh.PublicKey) ssh.HostKeyCallback {
+	return func(hostname string, _ net.Addr, key ssh.PublicKey) error {
+		for _, hostKey := range hostKeys {
+			if bytes.Equal(hostKey.Marshal(), key.Marshal()) {
+				return nil
+			}
+		}
+		return errors.Errorf("host key of SSH bastion %s is not a known host key", hostname)
+	}
+}
+
+// proxyTransports is the process-wide cache of the transports used to connect to remote clusters through proxies.
+var proxyTransports = newProxyTransportCache(clock.RealClock{})
+
+// proxyTransportCache caches the transports to proxies, so that connections through a proxy are reused rather than
+// a new transport, with its own pool of connections, being created every time a REST config is built for a remote
+// cluster. Transports which have not been used within the idle timeout are dropped, along with their SSH tunnel.
+type proxyTransportCache struct {
+	mu         sync.Mutex
+	clock      clock.Clock
+	transports map[proxyTransportKey]*proxyTransport
+}
+
+// proxyTransportKey identifies a transport by the version of the proxy and the TLS settings of the connections to the
+// API server, which differ between remote clusters using the same proxy.
+type proxyTransportKey struct {
+	proxy      string
+	insecure   bool
+	serverName string
+	caFile     string
+	caData     string
+	certFile   string
+	certData   string
+	keyFile    string
+	keyData    string
+}
+
+type proxyTransport struct {
+	transport *http.Transport
+	tunnel    *sshTunnel
+	lastUsed  time.Time
+}
+
+func newProxyTransportCache(clock clock.Clock) *proxyTransportCache {
+	return &proxyTransportCache{
+		clock:      clock,
+		transports: map[proxyTransportKey]*proxyTransport{},
+	}
+}
+
+// get returns the transport connecting through the given proxy with the TLS settings of the given REST config,
+// creating it if needed.
+func (c *proxyTransportCache) get(cfg *rest.Config, proxy *Proxy) (*http.Transport, error) {
+	key := proxyTransportKey{
+		proxy:      proxy.version,
+		insecure:   cfg.Insecure,
+		serverName: cfg.ServerName,
+		caFile:     cfg.CAFile,
+		caData:     string(cfg.CAData),
+		certFile:   cfg.CertFile,
+		certData:   string(cfg.CertData),
+		keyFile:    cfg.KeyFile,
+		keyData:    string(cfg.KeyData),
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.clock.Now()
+	for k, t := range c.transports {
+		if now.Sub(t.lastUsed) > defaultIdleTimeout {
+			t.transport.CloseIdleConnections()
+			if t.tunnel != nil {
+				t.tunnel.close()
+			}
+			delete(c.transports, k)
+		}
+	}
+	if t, ok := c.transports[key]; ok {
+		t.lastUsed = now
+		return t.transport, nil
+	}
+	tlsConfig, err := rest.TLSConfigFor(cfg)
+	if err != nil {
+		return nil, err
+	}
+	t := &proxyTransport{lastUsed: now}
+	dialContext := (&net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	var proxyFunc func(*http.Request) (*url.URL, error)
+	if proxy.bastion != nil {
+		t.tunnel = newSSHTunnel(proxy.bastion)
+		dialContext = t.tunnel.DialContext
+	} else {
+		proxyFunc = http.ProxyURL(proxy.URL)
+	}
+	t.transport = utilnet.SetTransportDefaults(&http.Transport{
+		Proxy:               proxyFunc,
+		TLSClientConfig:     tlsConfig,
+		TLSHandshakeTimeout: 10 * time.Second,
+		IdleConnTimeout:     90 * time.Second,
+		DialContext:         dialContext,
+	})
+	c.transports[key] = t
+	return t.transport, nil
+}
+
+// ConfigureProxy configures the given REST config to connect through the given proxy. The TLS settings of the
+// config are moved into a dedicated transport, since client-go does not allow setting both a transport and TLS
+// settings, and its cache of transports does not take the proxy into account.
+func ConfigureProxy(cfg *rest.Config, proxy *Proxy) error {
+	transport, err := proxyTransports.get(cfg, proxy)
+	if err != nil {
+		return err
+	}
+	cfg.Transport = transport
+	cfg.TLSClientConfig = rest.TLSClientConfig{}
+	return nil
+}
+
+// LocalURL returns the URL of a proxy for commands connecting to the API server on their own, such as those run by
+// the install pod. Proxies at a URL are used directly. For SSH bastions, an HTTP proxy tunneling CONNECT requests
+// through the bastion is started on the loopback interface, and runs for the lifetime of the process.
+func (p *Proxy) LocalURL() (*url.URL, error) {
+	return p.localURL(nil)
+}
+
+// localURL returns the URL of a proxy for commands connecting to the API server on their own. The HTTP proxy started
+// for an SSH bastion runs until the stop channel is closed.
+func (p *Proxy) localURL(stop <-chan struct{}) (*url.URL, error) {
+	if p.bastion == nil {
+		return p.URL, nil
+	}
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, errors.Wrap(err, "could not listen for local proxy")
+	}
+	tunnel := newSSHTunnel(p.bastion)
+	server := &http.Server{Handler: tunnel}
+	go server.Serve(listener)
+	go func() {
+		<-stop
+		server.Close()
+		tunnel.close()
+	}()
+	return &url.URL{Scheme: "http", Host: listener.Addr().String()}, nil
+}
+
+// sshTunnel dials connections through an SSH bastion. The connections share a single SSH connection to the bastion,
+// which is established on the first dial and reestablished on the next dial after it is lost.
+type sshTunnel struct {
+	bastion *sshBastion
+	mu      sync.Mutex
+	client  *ssh.Client
+}
+
+func newSSHTunnel(bastion *sshBastion) *sshTunnel {
+	return &sshTunnel{bastion: bastion}
+}
+
+// DialContext dials the address from the bastion.
+func (t *sshTunnel) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	sshClient, err := t.sshClient(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not connect to SSH bastion")
+	}
+	return sshClient.Dial(network, addr)
+}
+
+// sshClient returns the SSH connection to the bastion, connecting to the bastion if there is no connection.
+func (t *sshTunnel) sshClient(ctx context.Context) (*ssh.Client, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.client != nil {
+		return t.client, nil
+	}
+	conn, err := (&net.Dialer{Timeout: sshConnectTimeout}).DialContext(ctx, "tcp", t.bastion.addr)
+	if err != nil {
+		return nil, err
+	}
+	// Bound the SSH handshake, which is otherwise only bounded by the TCP keepalive of the connection.
+	conn.SetDeadline(time.Now().Add(sshConnectTimeout))
+	sshConn, chans, reqs, err := ssh.NewClientConn(conn, t.bastion.addr, t.bastion.config)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	conn.SetDeadline(time.Time{})
+	sshClient := ssh.NewClient(sshConn, chans, reqs)
+	go func() {
+		sshClient.Wait()
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		if t.client == sshClient {
+			t.client = nil
+		}
+	}()
+	t.client = sshClient
+	return sshClient, nil
+}
+
+// close closes the SSH connection to the bastion, along with all connections tunneled through it.
+func (t *sshTunnel) close() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.client != nil {
+		t.client.Close()
+		t.client = nil
+	}
+}
+
+// ServeHTTP serves CONNECT requests by tunneling the connections to their hosts through the bastion.
+func (t *sshTunnel) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodConnect {
+		http.Error(w, "only CONNECT requests are supported", http.StatusMethodNotAllowed)
+		return
+	}
+	upstream, err := t.DialContext(r.Context(), "tcp", r.Host)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		upstream.Close()
+		http.Error(w, "connection cannot be hijacked", http.StatusInternalServerError)
+		return
+	}
+	downstream, _, err := hijacker.Hijack()
+	if err != nil {
+		upstream.Close()
+		return
+	}
+	if _, err := io.WriteString(downstream, "HTTP/1.1 200 Connection established\r\n\r\n"); err != nil {
+		upstream.Close()
+		downstream.Close()
+		return
+	}
+	go pipe(upstream, downstream)
+	go pipe(downstream, upstream)
+}
+
+// pipe copies from src to dst, closing both connections once either side is done.
+func pipe(dst, src net.Conn) {
+	io.Copy(dst, src)
+	dst.Close()
+	src.Close()
+}
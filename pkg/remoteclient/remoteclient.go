@@ -4,11 +4,21 @@ package remoteclient
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"sync"
+	"time"
 
 	"github.com/pkg/errors"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -23,16 +33,29 @@ import (
 	"github.com/openshift/hive/pkg/controller/utils"
 )
 
-const adminKubeconfigKey = "kubeconfig"
+const (
+	kubeconfigKey = "kubeconfig"
+
+	servingCertificatesTimeout = 30 * time.Second
+)
 
 // Builder is used to build API clients to the remote cluster
 type Builder interface {
 	// Build will return a static kubeclient for the remote cluster.
 	Build() (client.Client, error)
 
+	// BuildCached will return a kubeclient for the remote cluster that reads typed objects from informers shared by
+	// all controllers, and writes to the remote cluster directly. The informer of a kind is started the first time
+	// that the kind is read, and runs until the clients of the cluster are evicted from the pool. Builders that do
+	// not use the pool return the same client as Build.
+	BuildCached() (client.Client, error)
+
 	// BuildDynamic will return a dynamic kubeclient for the remote cluster.
 	BuildDynamic() (dynamic.Interface, error)
 
+	// BuildKubeClient will return a kubernetes client for the remote cluster.
+	BuildKubeClient() (kubernetes.Interface, error)
+
 	// Unreachable returns true if Hive has not been able to reach the remote cluster.
 	// Note that this function will not attempt to reach the remote cluster. It only checks the current conditions on
 	// the ClusterDeployment to determine if the remote cluster is reachable.
@@ -44,6 +67,10 @@ type Builder interface {
 	// RESTConfig returns the config for a REST client that connects to the remote cluster.
 	RESTConfig() (*rest.Config, error)
 
+	// ServingCertificates returns the certificate chain served by the API server of the remote cluster. The chain is
+	// returned whether or not it is trusted by the admin kubeconfig.
+	ServingCertificates() ([]*x509.Certificate, error)
+
 	// UsePrimaryAPIURL will use the primary API URL. If there is an API URL override, then that is the primary.
 	// Otherwise, the primary is the default API URL.
 	UsePrimaryAPIURL() Builder
@@ -54,9 +81,22 @@ type Builder interface {
 }
 
 // NewBuilder creates a new Builder for creating a client to connect to the remote cluster associated with the specified
-// ClusterDeployment.
+// ClusterDeployment. The clients are cached in a process-wide pool and are rebuilt when the admin kubeconfig secret
+// changes or when the cluster becomes reachable or unreachable.
 // The controllerName is needed for metrics.
 func NewBuilder(c client.Client, cd *hivev1.ClusterDeployment, controllerName string) Builder {
+	return &builder{
+		c:              c,
+		cd:             cd,
+		controllerName: controllerName,
+		urlToUse:       activeURL,
+		pool:           defaultPool,
+	}
+}
+
+// NewUncachedBuilder creates a new Builder like NewBuilder, except that new clients are built on every call. Building a
+// client discovers the API of the remote cluster, so this should be used to check connectivity to the remote cluster.
+func NewUncachedBuilder(c client.Client, cd *hivev1.ClusterDeployment, controllerName string) Builder {
 	return &builder{
 		c:              c,
 		cd:             cd,
@@ -70,6 +110,7 @@ type builder struct {
 	cd             *hivev1.ClusterDeployment
 	controllerName string
 	urlToUse       int
+	pool           *pool
 }
 
 const (
@@ -78,50 +119,139 @@ const (
 	secondaryURL
 )
 
+var (
+	remoteSchemeOnce sync.Once
+	remoteScheme     *runtime.Scheme
+	remoteSchemeErr  error
+)
+
 func (b *builder) Unreachable() bool {
 	cond := utils.FindClusterDeploymentCondition(b.cd.Status.Conditions, hivev1.UnreachableCondition)
 	return cond != nil && cond.Status == corev1.ConditionTrue
 }
 
 func (b *builder) Build() (client.Client, error) {
-	cfg, err := b.RESTConfig()
-	if err != nil {
-		return nil, err
+	if b.pool == nil {
+		cfg, err := b.RESTConfig()
+		if err != nil {
+			return nil, err
+		}
+		return newClient(cfg, nil)
 	}
 
-	scheme, err := machineapi.SchemeBuilder.Build()
+	entry, err := b.poolEntry()
 	if err != nil {
 		return nil, err
 	}
+	var c client.Client
+	err = entry.withClients(b.controllerName, func(clients *controllerClients) error {
+		if clients.client == nil {
+			restMapper, err := entry.restMapperLocked(b.pool.clock)
+			if err != nil {
+				return err
+			}
+			if clients.client, err = newClient(b.controllerConfig(entry.config), restMapper); err != nil {
+				return err
+			}
+		}
+		c = clients.client
+		return nil
+	})
+	return c, err
+}
 
-	autoscalingv1.SchemeBuilder.AddToScheme(scheme)
-	autoscalingv1beta1.SchemeBuilder.AddToScheme(scheme)
-
-	if err := openshiftapiv1.Install(scheme); err != nil {
-		return nil, err
+func (b *builder) BuildCached() (client.Client, error) {
+	if b.pool == nil {
+		return b.Build()
 	}
 
-	if err := routev1.Install(scheme); err != nil {
+	entry, err := b.poolEntry()
+	if err != nil {
 		return nil, err
 	}
-
-	return client.New(cfg, client.Options{
-		Scheme: scheme,
+	var c client.Client
+	err = entry.withClients(b.controllerName, func(clients *controllerClients) error {
+		if clients.cachedClient == nil {
+			restMapper, err := entry.restMapperLocked(b.pool.clock)
+			if err != nil {
+				return err
+			}
+			remoteCache, err := entry.cacheLocked(b.pool.clock)
+			if err != nil {
+				return err
+			}
+			if clients.client == nil {
+				if clients.client, err = newClient(b.controllerConfig(entry.config), restMapper); err != nil {
+					return err
+				}
+			}
+			clients.cachedClient = &client.DelegatingClient{
+				Reader: &client.DelegatingReader{
+					CacheReader:  remoteCache,
+					ClientReader: clients.client,
+				},
+				Writer:       clients.client,
+				StatusClient: clients.client,
+			}
+		}
+		c = clients.cachedClient
+		return nil
 	})
+	return c, err
 }
 
 func (b *builder) BuildDynamic() (dynamic.Interface, error) {
-	cfg, err := b.RESTConfig()
+	if b.pool == nil {
+		cfg, err := b.RESTConfig()
+		if err != nil {
+			return nil, err
+		}
+		return dynamic.NewForConfig(cfg)
+	}
+
+	entry, err := b.poolEntry()
 	if err != nil {
 		return nil, err
 	}
+	var c dynamic.Interface
+	err = entry.withClients(b.controllerName, func(clients *controllerClients) error {
+		if clients.dynamic == nil {
+			var err error
+			if clients.dynamic, err = dynamic.NewForConfig(b.controllerConfig(entry.config)); err != nil {
+				return err
+			}
+		}
+		c = clients.dynamic
+		return nil
+	})
+	return c, err
+}
+
+func (b *builder) BuildKubeClient() (kubernetes.Interface, error) {
+	if b.pool == nil {
+		cfg, err := b.RESTConfig()
+		if err != nil {
+			return nil, err
+		}
+		return kubernetes.NewForConfig(cfg)
+	}
 
-	client, err := dynamic.NewForConfig(cfg)
+	entry, err := b.poolEntry()
 	if err != nil {
 		return nil, err
 	}
-
-	return client, nil
+	var c kubernetes.Interface
+	err = entry.withClients(b.controllerName, func(clients *controllerClients) error {
+		if clients.kubeClient == nil {
+			var err error
+			if clients.kubeClient, err = kubernetes.NewForConfig(b.controllerConfig(entry.config)); err != nil {
+				return err
+			}
+		}
+		c = clients.kubeClient
+		return nil
+	})
+	return c, err
 }
 
 func (b *builder) UsePrimaryAPIURL() Builder {
@@ -135,6 +265,127 @@ func (b *builder) UseSecondaryAPIURL() Builder {
 }
 
 func (b *builder) RESTConfig() (*rest.Config, error) {
+	if b.pool == nil {
+		kubeconfigSecret, err := b.kubeconfigSecret()
+		if err != nil {
+			return nil, err
+		}
+		proxy, err := GetProxy(b.c, b.cd)
+		if err != nil {
+			return nil, err
+		}
+		cfg, err := b.baseRESTConfig(kubeconfigSecret, proxy)
+		if err != nil {
+			return nil, err
+		}
+		return b.controllerConfig(cfg), nil
+	}
+
+	entry, err := b.poolEntry()
+	if err != nil {
+		return nil, err
+	}
+	return b.controllerConfig(entry.config), nil
+}
+
+func (b *builder) ServingCertificates() ([]*x509.Certificate, error) {
+	cfg, err := b.RESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	// Only the TLS handshake matters, so connect anonymously and without verifying the served certificates.
+	cfg = rest.AnonymousClientConfig(cfg)
+	if transport, ok := cfg.Transport.(*http.Transport); ok {
+		// Proxied configs carry their TLS settings in their transport.
+		tlsConfig := &tls.Config{InsecureSkipVerify: true}
+		if transport.TLSClientConfig != nil {
+			tlsConfig.ServerName = transport.TLSClientConfig.ServerName
+		}
+		transport = transport.Clone()
+		transport.TLSClientConfig = tlsConfig
+		cfg.Transport = transport
+	} else {
+		cfg.TLSClientConfig = rest.TLSClientConfig{Insecure: true, ServerName: cfg.ServerName}
+	}
+	transport, err := rest.TransportFor(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	apiURL, _, err := rest.DefaultServerURL(cfg.Host, "", schema.GroupVersion{}, true)
+	if err != nil {
+		return nil, err
+	}
+	apiURL.Path = "/version"
+	httpClient := &http.Client{Transport: transport, Timeout: servingCertificatesTimeout}
+	resp, err := httpClient.Get(apiURL.String())
+	if err != nil {
+		return nil, errors.Wrap(err, "could not connect to API server")
+	}
+	resp.Body.Close()
+	if resp.TLS == nil || len(resp.TLS.PeerCertificates) == 0 {
+		return nil, errors.New("API server did not serve any certificates")
+	}
+	return resp.TLS.PeerCertificates, nil
+}
+
+func (b *builder) APIURL() (string, error) {
+	cfg, err := b.RESTConfig()
+	if err != nil {
+		return "", err
+	}
+	return cfg.Host, nil
+}
+
+// poolEntry returns the entry of the pool for the remote cluster, adding an entry built from the admin kubeconfig
+// secret when there is no entry for the current version of the secret and proxy settings.
+func (b *builder) poolEntry() (*poolEntry, error) {
+	kubeconfigSecret, err := b.kubeconfigSecret()
+	if err != nil {
+		return nil, err
+	}
+	proxy, err := GetProxy(b.c, b.cd)
+	if err != nil {
+		return nil, err
+	}
+	proxyVersion := ""
+	if proxy != nil {
+		proxyVersion = proxy.version
+	}
+	key := poolKey{uid: b.cd.UID}
+	if b.useOverrideURL() {
+		key.apiURLOverride = b.cd.Spec.ControlPlaneConfig.APIURLOverride
+	}
+	version := poolEntryVersion{
+		kubeconfigSecretName:      kubeconfigSecret.Name,
+		kubeconfigResourceVersion: kubeconfigSecret.ResourceVersion,
+		proxy:                     proxyVersion,
+		unreachable:               b.Unreachable(),
+	}
+	if entry := b.pool.get(key, version, b.controllerName); entry != nil {
+		return entry, nil
+	}
+	cfg, err := b.baseRESTConfig(kubeconfigSecret, proxy)
+	if err != nil {
+		return nil, err
+	}
+	return b.pool.add(key, version, cfg), nil
+}
+
+// kubeconfigSecret returns the secret of the kubeconfig used to connect to the remote cluster. The kubeconfig minted by
+// Hive is preferred over the admin kubeconfig, which is only used until Hive has minted its own or if the secret of the
+// kubeconfig minted by Hive is lost.
+func (b *builder) kubeconfigSecret() (*corev1.Secret, error) {
+	if ref := b.cd.Spec.ClusterMetadata.HiveManagedKubeconfigSecretRef; ref != nil && ref.Name != "" {
+		kubeconfigSecret := &corev1.Secret{}
+		err := b.c.Get(context.Background(), client.ObjectKey{Namespace: b.cd.Namespace, Name: ref.Name}, kubeconfigSecret)
+		switch {
+		case err == nil:
+			return kubeconfigSecret, nil
+		case !apierrors.IsNotFound(err):
+			return nil, errors.Wrap(err, "could not get hive-managed kubeconfig secret")
+		}
+	}
 	kubeconfigSecret := &corev1.Secret{}
 	if err := b.c.Get(
 		context.Background(),
@@ -143,9 +394,16 @@ func (b *builder) RESTConfig() (*rest.Config, error) {
 	); err != nil {
 		return nil, errors.Wrap(err, "could not get admin kubeconfig secret")
 	}
-	kubeconfigData, ok := kubeconfigSecret.Data[adminKubeconfigKey]
+	return kubeconfigSecret, nil
+}
+
+// baseRESTConfig returns the REST config for the remote cluster from the kubeconfig secret, connecting to the
+// API URL in use by the builder through the given proxy, if any. The config is shared by all controllers and must not
+// be modified.
+func (b *builder) baseRESTConfig(kubeconfigSecret *corev1.Secret, proxy *Proxy) (*rest.Config, error) {
+	kubeconfigData, ok := kubeconfigSecret.Data[kubeconfigKey]
 	if !ok {
-		return nil, errors.Errorf("admin kubeconfig secret does not contain %q data", adminKubeconfigKey)
+		return nil, errors.Errorf("kubeconfig secret %s does not contain %q data", kubeconfigSecret.Name, kubeconfigKey)
 	}
 
 	config, err := clientcmd.Load(kubeconfigData)
@@ -158,33 +416,83 @@ func (b *builder) RESTConfig() (*rest.Config, error) {
 		return nil, err
 	}
 
-	utils.AddControllerMetricsTransportWrapper(cfg, b.controllerName, true)
+	if b.useOverrideURL() {
+		cfg.Host = b.cd.Spec.ControlPlaneConfig.APIURLOverride
+	}
 
-	if override := b.cd.Spec.ControlPlaneConfig.APIURLOverride; override != "" {
-		useOverrideURL := false
-		switch b.urlToUse {
-		case activeURL:
-			useOverrideURL = b.apiURLOverrideActive()
-		case primaryURL:
-			useOverrideURL = true
-		}
-		if useOverrideURL {
-			cfg.Host = b.cd.Spec.ControlPlaneConfig.APIURLOverride
+	if proxy != nil {
+		if err := ConfigureProxy(cfg, proxy); err != nil {
+			return nil, errors.Wrap(err, "could not configure proxy")
 		}
 	}
 
 	return cfg, nil
 }
 
-func (b *builder) APIURL() (string, error) {
-	cfg, err := b.RESTConfig()
-	if err != nil {
-		return "", err
+// controllerConfig returns a copy of the given REST config with metrics for the requests made by the controller.
+func (b *builder) controllerConfig(cfg *rest.Config) *rest.Config {
+	cfg = rest.CopyConfig(cfg)
+	utils.AddControllerMetricsTransportWrapper(cfg, b.controllerName, true)
+	return cfg
+}
+
+func (b *builder) useOverrideURL() bool {
+	if b.cd.Spec.ControlPlaneConfig.APIURLOverride == "" {
+		return false
 	}
-	return cfg.Host, nil
+	switch b.urlToUse {
+	case activeURL:
+		return b.apiURLOverrideActive()
+	case primaryURL:
+		return true
+	}
+	return false
 }
 
 func (b *builder) apiURLOverrideActive() bool {
 	cond := utils.FindClusterDeploymentCondition(b.cd.Status.Conditions, hivev1.ActiveAPIURLOverrideCondition)
 	return cond != nil && cond.Status == corev1.ConditionTrue
 }
+
+// newClient returns a client for the remote cluster of the given config. The client maps kinds to resources with the
+// given REST mapper, or with a mapper from a new discovery of the API of the cluster when the mapper is nil.
+func newClient(cfg *rest.Config, restMapper meta.RESTMapper) (client.Client, error) {
+	scheme, err := buildRemoteScheme()
+	if err != nil {
+		return nil, err
+	}
+	options := client.Options{
+		Scheme: scheme,
+	}
+	if restMapper != nil {
+		options.Mapper = restMapper
+	}
+	return client.New(cfg, options)
+}
+
+// buildRemoteScheme returns the scheme for clients of remote clusters. The scheme is built once and shared by all
+// clients.
+func buildRemoteScheme() (*runtime.Scheme, error) {
+	remoteSchemeOnce.Do(func() {
+		scheme, err := machineapi.SchemeBuilder.Build()
+		if err != nil {
+			remoteSchemeErr = err
+			return
+		}
+
+		autoscalingv1.SchemeBuilder.AddToScheme(scheme)
+		autoscalingv1beta1.SchemeBuilder.AddToScheme(scheme)
+
+		if err := openshiftapiv1.Install(scheme); err != nil {
+			remoteSchemeErr = err
+			return
+		}
+
+		if err := routev1.Install(scheme); err != nil {
+			remoteSchemeErr = err
+			return
+		}
+		remoteScheme = scheme
+	})
+	return remoteScheme, remoteSchemeErr
+}
@@ -1,7 +1,10 @@
 package remoteclient
 
 import (
+	"fmt"
 	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"path/filepath"
 	"testing"
 
@@ -32,11 +35,26 @@ func TestNewBuilder(t *testing.T) {
 		cd:             cd,
 		controllerName: controllerName,
 		urlToUse:       activeURL,
+		pool:           defaultPool,
 	}
 	actual := NewBuilder(c, cd, controllerName)
 	assert.Equal(t, expected, actual, "unexpected builder")
 }
 
+func TestNewUncachedBuilder(t *testing.T) {
+	cd := testClusterDeployment()
+	c := fakeClient(cd)
+	controllerName := "test-controller-name"
+	expected := &builder{
+		c:              c,
+		cd:             cd,
+		controllerName: controllerName,
+		urlToUse:       activeURL,
+	}
+	actual := NewUncachedBuilder(c, cd, controllerName)
+	assert.Equal(t, expected, actual, "unexpected builder")
+}
+
 func Test_builder_APIURL(t *testing.T) {
 	cd := testClusterDeployment()
 	kubeconfigSecret := testKubeconfigSecret(t)
@@ -48,6 +66,70 @@ func Test_builder_APIURL(t *testing.T) {
 	assert.Equal(t, expected, actual, "unexpected API URL")
 }
 
+func Test_builder_HiveManagedKubeconfig(t *testing.T) {
+	const hiveManagedAPIURL = "https://api.hive-managed.example.com:6443"
+	cases := []struct {
+		name              string
+		hiveManagedRef    bool
+		hiveManagedSecret bool
+		expectedAPIURL    string
+	}{
+		{
+			name:           "no hive-managed kubeconfig",
+			expectedAPIURL: apiURL,
+		},
+		{
+			name:              "hive-managed kubeconfig",
+			hiveManagedRef:    true,
+			hiveManagedSecret: true,
+			expectedAPIURL:    hiveManagedAPIURL,
+		},
+		{
+			name:           "missing hive-managed kubeconfig secret",
+			hiveManagedRef: true,
+			expectedAPIURL: apiURL,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			cd := testClusterDeployment()
+			objects := []runtime.Object{cd, testKubeconfigSecret(t)}
+			if tc.hiveManagedRef {
+				cd.Spec.ClusterMetadata.HiveManagedKubeconfigSecretRef = &corev1.LocalObjectReference{Name: "test-hive-managed-kubeconfig"}
+			}
+			if tc.hiveManagedSecret {
+				objects = append(objects, &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{
+						Namespace: testNamespace,
+						Name:      "test-hive-managed-kubeconfig",
+					},
+					Data: map[string][]byte{kubeconfigKey: []byte(fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: cluster
+  cluster:
+    server: %s
+contexts:
+- name: hive-managed
+  context:
+    cluster: cluster
+    user: hive-managed
+current-context: hive-managed
+users:
+- name: hive-managed
+  user:
+    token: test-token
+`, hiveManagedAPIURL))},
+				})
+			}
+			builder := NewBuilder(fakeClient(objects...), cd, "test-controller-name")
+			actual, err := builder.APIURL()
+			assert.NoError(t, err, "unexpected error getting API URL")
+			assert.Equal(t, tc.expectedAPIURL, actual, "unexpected API URL")
+		})
+	}
+}
+
 func Test_builder_RESTConfig(t *testing.T) {
 	cases := []struct {
 		name           string
@@ -220,6 +302,46 @@ func Test_builder_Build(t *testing.T) {
 	}
 }
 
+func Test_builder_ServingCertificates(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	cd := testClusterDeployment()
+	// The kubeconfig does not trust the certificate of the server.
+	kubeconfig := fmt.Sprintf(`apiVersion: v1
+kind: Config
+clusters:
+- name: cluster
+  cluster:
+    server: %s
+contexts:
+- name: admin
+  context:
+    cluster: cluster
+    user: admin
+current-context: admin
+users:
+- name: admin
+  user:
+    token: test-token
+`, server.URL)
+	kubeconfigSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: testNamespace,
+			Name:      testKubeconfigSecretName,
+		},
+		Data: map[string][]byte{kubeconfigKey: []byte(kubeconfig)},
+	}
+	c := fakeClient(cd, kubeconfigSecret)
+	builder := NewUncachedBuilder(c, cd, "test-controller-name")
+	certificates, err := builder.ServingCertificates()
+	if assert.NoError(t, err, "unexpected error getting serving certificates") && assert.Len(t, certificates, 1, "expected one certificate") {
+		assert.Equal(t, server.Certificate().Raw, certificates[0].Raw, "unexpected serving certificate")
+	}
+}
+
 func fakeClient(objects ...runtime.Object) client.Client {
 	scheme := runtime.NewScheme()
 	hivev1.AddToScheme(scheme)
@@ -278,6 +400,6 @@ func testKubeconfigSecret(t *testing.T) *corev1.Secret {
 			Namespace: testNamespace,
 			Name:      testKubeconfigSecretName,
 		},
-		Data: map[string][]byte{adminKubeconfigKey: kubeconfig},
+		Data: map[string][]byte{kubeconfigKey: kubeconfig},
 	}
 }
@@ -0,0 +1,131 @@
+package remoteclient
+
+import (
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+)
+
+// rediscoveryInterval is the minimum interval between discoveries of the API of a remote cluster when mapping kinds
+// that were not found, such as the kinds of CustomResourceDefinitions created after the clients were cached.
+const rediscoveryInterval = 30 * time.Second
+
+// dynamicRESTMapper maps kinds to resources from the API of a remote cluster. The API is discovered again when a kind
+// is not found, at most once per rediscovery interval, so that cached clients can use kinds added to the cluster
+// after they were built.
+type dynamicRESTMapper struct {
+	mu              sync.Mutex
+	clock           clock.Clock
+	discoveryClient discovery.DiscoveryInterface
+	mapper          meta.RESTMapper
+	discovered      time.Time
+}
+
+var _ meta.RESTMapper = &dynamicRESTMapper{}
+
+// newDynamicRESTMapper returns a REST mapper for the remote cluster of the given config, discovering its API.
+func newDynamicRESTMapper(cfg *rest.Config, clock clock.Clock) (*dynamicRESTMapper, error) {
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	m := &dynamicRESTMapper{
+		clock:           clock,
+		discoveryClient: discoveryClient,
+	}
+	if err := m.discover(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// discover discovers the API of the remote cluster. It must be called with the lock held.
+func (m *dynamicRESTMapper) discover() error {
+	groupResources, err := restmapper.GetAPIGroupResources(m.discoveryClient)
+	if err != nil {
+		return err
+	}
+	m.mapper = restmapper.NewDiscoveryRESTMapper(groupResources)
+	m.discovered = m.clock.Now()
+	return nil
+}
+
+// withRediscovery calls fn with the current mapper. If fn fails because a kind or resource was not found, the API
+// is discovered again and fn is retried, unless the API was discovered within the rediscovery interval.
+func (m *dynamicRESTMapper) withRediscovery(fn func(meta.RESTMapper) error) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	err := fn(m.mapper)
+	if !meta.IsNoMatchError(err) || m.clock.Since(m.discovered) < rediscoveryInterval {
+		return err
+	}
+	if discoverErr := m.discover(); discoverErr != nil {
+		log.WithError(discoverErr).Warn("could not discover the API of the remote cluster")
+		return err
+	}
+	return fn(m.mapper)
+}
+
+func (m *dynamicRESTMapper) KindFor(resource schema.GroupVersionResource) (gvk schema.GroupVersionKind, err error) {
+	err = m.withRediscovery(func(mapper meta.RESTMapper) error {
+		gvk, err = mapper.KindFor(resource)
+		return err
+	})
+	return
+}
+
+func (m *dynamicRESTMapper) KindsFor(resource schema.GroupVersionResource) (gvks []schema.GroupVersionKind, err error) {
+	err = m.withRediscovery(func(mapper meta.RESTMapper) error {
+		gvks, err = mapper.KindsFor(resource)
+		return err
+	})
+	return
+}
+
+func (m *dynamicRESTMapper) ResourceFor(input schema.GroupVersionResource) (gvr schema.GroupVersionResource, err error) {
+	err = m.withRediscovery(func(mapper meta.RESTMapper) error {
+		gvr, err = mapper.ResourceFor(input)
+		return err
+	})
+	return
+}
+
+func (m *dynamicRESTMapper) ResourcesFor(input schema.GroupVersionResource) (gvrs []schema.GroupVersionResource, err error) {
+	err = m.withRediscovery(func(mapper meta.RESTMapper) error {
+		gvrs, err = mapper.ResourcesFor(input)
+		return err
+	})
+	return
+}
+
+func (m *dynamicRESTMapper) RESTMapping(gk schema.GroupKind, versions ...string) (mapping *meta.RESTMapping, err error) {
+	err = m.withRediscovery(func(mapper meta.RESTMapper) error {
+		mapping, err = mapper.RESTMapping(gk, versions...)
+		return err
+	})
+	return
+}
+
+func (m *dynamicRESTMapper) RESTMappings(gk schema.GroupKind, versions ...string) (mappings []*meta.RESTMapping, err error) {
+	err = m.withRediscovery(func(mapper meta.RESTMapper) error {
+		mappings, err = mapper.RESTMappings(gk, versions...)
+		return err
+	})
+	return
+}
+
+func (m *dynamicRESTMapper) ResourceSingularizer(resource string) (singular string, err error) {
+	err = m.withRediscovery(func(mapper meta.RESTMapper) error {
+		singular, err = mapper.ResourceSingularizer(resource)
+		return err
+	})
+	return
+}
@@ -0,0 +1,95 @@
+package remoteclient
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/client-go/rest"
+)
+
+// fakeDiscoveryServer serves the discovery of the core API group and, once added, of the example.com API group.
+type fakeDiscoveryServer struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	discoveries int
+	withGroup   bool
+}
+
+func newFakeDiscoveryServer(t *testing.T) *fakeDiscoveryServer {
+	s := &fakeDiscoveryServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		var obj interface{}
+		switch r.URL.Path {
+		case "/api":
+			s.discoveries++
+			obj = &metav1.APIVersions{Versions: []string{"v1"}}
+		case "/apis":
+			groups := &metav1.APIGroupList{}
+			if s.withGroup {
+				version := metav1.GroupVersionForDiscovery{GroupVersion: "example.com/v1", Version: "v1"}
+				groups.Groups = append(groups.Groups, metav1.APIGroup{
+					Name:             "example.com",
+					Versions:         []metav1.GroupVersionForDiscovery{version},
+					PreferredVersion: version,
+				})
+			}
+			obj = groups
+		case "/api/v1":
+			obj = &metav1.APIResourceList{
+				GroupVersion: "v1",
+				APIResources: []metav1.APIResource{{Name: "configmaps", Namespaced: true, Kind: "ConfigMap"}},
+			}
+		case "/apis/example.com/v1":
+			obj = &metav1.APIResourceList{
+				GroupVersion: "example.com/v1",
+				APIResources: []metav1.APIResource{{Name: "widgets", Namespaced: true, Kind: "Widget"}},
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(obj), "unexpected error writing response")
+	}))
+	return s
+}
+
+func TestDynamicRESTMapper(t *testing.T) {
+	server := newFakeDiscoveryServer(t)
+	defer server.Close()
+	fakeClock := clock.NewFakeClock(time.Now())
+	widget := schema.GroupKind{Group: "example.com", Kind: "Widget"}
+
+	mapper, err := newDynamicRESTMapper(&rest.Config{Host: server.URL}, fakeClock)
+	require.NoError(t, err, "unexpected error creating REST mapper")
+	mapping, err := mapper.RESTMapping(schema.GroupKind{Kind: "ConfigMap"}, "v1")
+	require.NoError(t, err, "unexpected error mapping kind")
+	assert.Equal(t, "configmaps", mapping.Resource.Resource, "unexpected resource")
+
+	server.mu.Lock()
+	server.withGroup = true
+	server.mu.Unlock()
+	_, err = mapper.RESTMapping(widget, "v1")
+	assert.True(t, meta.IsNoMatchError(err), "expected no match within the rediscovery interval, got %v", err)
+	assert.Equal(t, 1, server.discoveries, "API must not be discovered again within the rediscovery interval")
+
+	fakeClock.Step(rediscoveryInterval)
+	mapping, err = mapper.RESTMapping(widget, "v1")
+	require.NoError(t, err, "unexpected error mapping kind added after discovery")
+	assert.Equal(t, "widgets", mapping.Resource.Resource, "unexpected resource")
+	assert.Equal(t, 2, server.discoveries, "expected API to be discovered again")
+}
@@ -0,0 +1,91 @@
+package resource
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/restmapper"
+)
+
+const (
+	// restMapperIdleTimeout is how long the REST mapper of a cluster is kept without being used.
+	restMapperIdleTimeout = 30 * time.Minute
+
+	// rediscoveryInterval is the minimum interval between discoveries of the API resources of a cluster when
+	// mapping kinds that were not found, such as the kinds of newly created CustomResourceDefinitions.
+	rediscoveryInterval = 30 * time.Second
+)
+
+// restMappers is the process-wide cache of the REST mappers of the clusters that objects are applied to.
+var restMappers = newRESTMapperCache(clock.RealClock{})
+
+// restMapperCache caches the REST mappers of clusters, keyed by the host of their API server, so that the API
+// resources of a cluster are not discovered for every applier.
+type restMapperCache struct {
+	mu      sync.Mutex
+	clock   clock.Clock
+	mappers map[string]*cachedRESTMapper
+}
+
+func newRESTMapperCache(clock clock.Clock) *restMapperCache {
+	return &restMapperCache{
+		clock:   clock,
+		mappers: map[string]*cachedRESTMapper{},
+	}
+}
+
+// get returns the REST mapper for the cluster with the given API server host. Mappers which have not been used
+// within the idle timeout are dropped.
+func (c *restMapperCache) get(host string) *cachedRESTMapper {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.clock.Now()
+	for h, m := range c.mappers {
+		if now.Sub(m.lastUsed) > restMapperIdleTimeout {
+			delete(c.mappers, h)
+		}
+	}
+	m, ok := c.mappers[host]
+	if !ok {
+		m = &cachedRESTMapper{clock: c.clock}
+		c.mappers[host] = m
+	}
+	m.lastUsed = now
+	return m
+}
+
+// cachedRESTMapper maps kinds to resources from the API resources of a cluster discovered on first use. The API
+// resources are discovered again when a kind is not found, at most once per rediscovery interval.
+type cachedRESTMapper struct {
+	mu         sync.Mutex
+	clock      clock.Clock
+	mapper     meta.RESTMapper
+	discovered time.Time
+	lastUsed   time.Time
+}
+
+// restMapping returns the REST mapping of the given kind, discovering the API resources of the cluster with the
+// given discovery client when needed.
+func (m *cachedRESTMapper) restMapping(gvk schema.GroupVersionKind, discoveryClient discovery.DiscoveryInterface) (*meta.RESTMapping, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.mapper != nil {
+		mapping, err := m.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+		if !meta.IsNoMatchError(err) || m.clock.Since(m.discovered) < rediscoveryInterval {
+			return mapping, err
+		}
+	}
+	groupResources, err := restmapper.GetAPIGroupResources(discoveryClient)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not discover API resources")
+	}
+	m.mapper = restmapper.NewDiscoveryRESTMapper(groupResources)
+	m.discovered = m.clock.Now()
+	return m.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+}
@@ -74,10 +74,14 @@ func (p *jsonPrinter) PrintObj(obj runtime.Object, w io.Writer) error {
 // that don't have a timeCreated timestamp. With the default serializer, they output a
 // `timeCreated: null` which always causes a mismatch with whatever's already in the server.
 func (r *Helper) Serialize(obj runtime.Object, scheme *runtime.Scheme) ([]byte, error) {
+	return serialize(obj, scheme, r.logger)
+}
+
+func serialize(obj runtime.Object, scheme *runtime.Scheme, logger log.FieldLogger) ([]byte, error) {
 	printer := printers.NewTypeSetter(scheme).ToPrinter(&jsonPrinter{})
 	buf := &bytes.Buffer{}
 	if err := printer.PrintObj(obj, buf); err != nil {
-		r.logger.WithError(err).Errorf("cannot serialize runtime object of type %T", obj)
+		logger.WithError(err).Errorf("cannot serialize runtime object of type %T", obj)
 		return nil, err
 	}
 	return buf.Bytes(), nil
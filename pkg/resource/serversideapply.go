@@ -0,0 +1,205 @@
+package resource
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	// FieldManager is the name of the field manager used by Hive when applying objects with server-side apply.
+	FieldManager = "hive"
+)
+
+// ApplyConflictError is returned by the ServerSideApplier when fields of the applied object are owned by
+// other field managers.
+type ApplyConflictError struct {
+	// Conflicts describe the conflicting fields and the field managers that own them.
+	Conflicts []string
+	err       error
+}
+
+func (e *ApplyConflictError) Error() string {
+	if len(e.Conflicts) == 0 {
+		return e.err.Error()
+	}
+	return fmt.Sprintf("conflicts with other field managers: %s", strings.Join(e.Conflicts, "; "))
+}
+
+// IsApplyConflict returns whether the error is an ApplyConflictError.
+func IsApplyConflict(err error) bool {
+	_, ok := err.(*ApplyConflictError)
+	return ok
+}
+
+// ServerSideApplier applies and patches objects using server-side apply through a dynamic client, as opposed to
+// the Helper which runs the kubectl commands.
+type ServerSideApplier struct {
+	logger          log.FieldLogger
+	dynamicClient   dynamic.Interface
+	discoveryClient discovery.DiscoveryInterface
+	mapper          *cachedRESTMapper
+}
+
+// NewServerSideApplier returns a new object that allows apply and patch operations using server-side apply. The
+// REST mapper of the cluster is shared by all the appliers for the cluster.
+func NewServerSideApplier(restConfig *rest.Config, logger log.FieldLogger) (*ServerSideApplier, error) {
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create dynamic client")
+	}
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	if err != nil {
+		return nil, errors.Wrap(err, "could not create discovery client")
+	}
+	return &ServerSideApplier{
+		logger:          logger,
+		dynamicClient:   dynamicClient,
+		discoveryClient: discoveryClient,
+		mapper:          restMappers.get(restConfig.Host),
+	}, nil
+}
+
+// Info determines the name/namespace and type of the passed in resource bytes
+func (a *ServerSideApplier) Info(obj []byte) (*Info, error) {
+	_, info, _, err := a.decode(obj)
+	return info, err
+}
+
+// Apply applies the given resource bytes using server-side apply with the Hive field manager. The object is not
+// read before it is applied, so the result is ConfiguredApplyResult whenever the apply patch was submitted, unless
+// the server does not create objects through server-side apply and the object had to be created.
+func (a *ServerSideApplier) Apply(obj []byte) (ApplyResult, error) {
+	u, info, mapping, err := a.decode(obj)
+	if err != nil {
+		return "", err
+	}
+	data, err := u.MarshalJSON()
+	if err != nil {
+		return "", errors.Wrap(err, "could not serialize object")
+	}
+	resourceClient := a.resourceClient(mapping, info.Namespace)
+	_, err = resourceClient.Patch(info.Name, types.ApplyPatchType, data, metav1.PatchOptions{FieldManager: FieldManager})
+	switch {
+	case err == nil:
+		return ConfiguredApplyResult, nil
+	case apierrors.IsNotFound(err):
+		// Servers that only apply to existing objects return NotFound for objects that do not exist yet.
+		if _, err := resourceClient.Create(u, metav1.CreateOptions{FieldManager: FieldManager}); err != nil {
+			return "", a.applyError(err, info)
+		}
+		return CreatedApplyResult, nil
+	default:
+		return "", a.applyError(err, info)
+	}
+}
+
+// ApplyRuntimeObject serializes an object and applies it using server-side apply with the Hive field manager.
+func (a *ServerSideApplier) ApplyRuntimeObject(obj runtime.Object, scheme *runtime.Scheme) (ApplyResult, error) {
+	data, err := serialize(obj, scheme, a.logger)
+	if err != nil {
+		return "", err
+	}
+	return a.Apply(data)
+}
+
+// Patch patches the given resource with the Hive field manager. The patch type is one of "strategic", "merge" or
+// "json", defaulting to "strategic".
+func (a *ServerSideApplier) Patch(name types.NamespacedName, kind, apiVersion string, patch []byte, patchType string) error {
+	if patchType == "" {
+		patchType = "strategic"
+	}
+	pt, ok := patchTypes[patchType]
+	if !ok {
+		return fmt.Errorf("Invalid patch type: %s. Valid patch types are 'strategic', 'merge' or 'json'", patchType)
+	}
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		a.logger.WithError(err).WithField("groupVersion", apiVersion).Error("cannot parse group version")
+		return err
+	}
+	mapping, err := a.restMapping(gv.WithKind(kind))
+	if err != nil {
+		return err
+	}
+	_, err = a.resourceClient(mapping, name.Namespace).Patch(name.Name, pt, patch, metav1.PatchOptions{FieldManager: FieldManager})
+	return err
+}
+
+func (a *ServerSideApplier) decode(obj []byte) (*unstructured.Unstructured, *Info, *meta.RESTMapping, error) {
+	u := &unstructured.Unstructured{}
+	if err := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(obj), len(obj)).Decode(&u.Object); err != nil {
+		a.logger.WithError(err).Error("Failed to decode resource")
+		return nil, nil, nil, fmt.Errorf("could not get info from passed resource: %v", err)
+	}
+	gvk := u.GroupVersionKind()
+	if gvk.Kind == "" || gvk.Version == "" {
+		return nil, nil, nil, fmt.Errorf("could not get info from passed resource: missing apiVersion or kind")
+	}
+	mapping, err := a.restMapping(gvk)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		u.SetNamespace("")
+	}
+	info := &Info{
+		Name:       u.GetName(),
+		Namespace:  u.GetNamespace(),
+		Kind:       mapping.GroupVersionKind.Kind,
+		APIVersion: mapping.GroupVersionKind.GroupVersion().String(),
+		Resource:   mapping.Resource.Resource,
+	}
+	return u, info, mapping, nil
+}
+
+func (a *ServerSideApplier) restMapping(gvk schema.GroupVersionKind) (*meta.RESTMapping, error) {
+	mapping, err := a.mapper.restMapping(gvk, a.discoveryClient)
+	if err != nil {
+		a.logger.WithError(err).WithField("gvk", gvk).Error("Failed to map resource")
+		return nil, fmt.Errorf("could not get info from passed resource: %v", err)
+	}
+	return mapping, nil
+}
+
+func (a *ServerSideApplier) resourceClient(mapping *meta.RESTMapping, namespace string) dynamic.ResourceInterface {
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		return a.dynamicClient.Resource(mapping.Resource).Namespace(namespace)
+	}
+	return a.dynamicClient.Resource(mapping.Resource)
+}
+
+// applyError converts a conflict returned by server-side apply into an ApplyConflictError.
+func (a *ServerSideApplier) applyError(err error, info *Info) error {
+	if !apierrors.IsConflict(err) {
+		return err
+	}
+	conflictErr := &ApplyConflictError{err: err}
+	if status, ok := err.(apierrors.APIStatus); ok && status.Status().Details != nil {
+		for _, cause := range status.Status().Details.Causes {
+			if cause.Type == metav1.CauseTypeFieldManagerConflict {
+				conflictErr.Conflicts = append(conflictErr.Conflicts, cause.Message)
+			}
+		}
+	}
+	a.logger.WithField("resource", fmt.Sprintf("%s/%s", info.Namespace, info.Name)).
+		WithField("kind", info.Kind).
+		WithField("conflicts", conflictErr.Conflicts).
+		Warn("server-side apply conflicts with other field managers")
+	return conflictErr
+}
@@ -0,0 +1,227 @@
+package resource
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/clock"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	testConfigMap = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: test-config
+  namespace: test-namespace
+data:
+  key: value
+`
+	testConfigMapPath = "/api/v1/namespaces/test-namespace/configmaps/test-config"
+)
+
+// fakeAPIServer serves the discovery of the core API group and records the requests for objects.
+type fakeAPIServer struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	requests    []string
+	discoveries int
+	// patchStatus is the status of the response to apply patches, with the status of the response as body
+	// when it is not OK.
+	patchStatus *metav1.Status
+}
+
+func newFakeAPIServer(t *testing.T) *fakeAPIServer {
+	s := &fakeAPIServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api":
+			s.discoveries++
+			writeJSON(t, w, http.StatusOK, &metav1.APIVersions{Versions: []string{"v1"}})
+			return
+		case "/apis":
+			writeJSON(t, w, http.StatusOK, &metav1.APIGroupList{})
+			return
+		case "/api/v1":
+			writeJSON(t, w, http.StatusOK, &metav1.APIResourceList{
+				GroupVersion: "v1",
+				APIResources: []metav1.APIResource{
+					{Name: "configmaps", Namespaced: true, Kind: "ConfigMap", Verbs: metav1.Verbs{"create", "get", "patch"}},
+					{Name: "namespaces", Kind: "Namespace", Verbs: metav1.Verbs{"create", "get", "patch"}},
+				},
+			})
+			return
+		}
+		s.requests = append(s.requests, r.Method+" "+r.URL.Path)
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err, "unexpected error reading request")
+		switch r.Method {
+		case http.MethodPatch:
+			assert.Equal(t, "application/apply-patch+yaml", r.Header.Get("Content-Type"), "unexpected patch type")
+			assert.Equal(t, FieldManager, r.URL.Query().Get("fieldManager"), "unexpected field manager")
+			if s.patchStatus != nil {
+				status := *s.patchStatus
+				status.TypeMeta = metav1.TypeMeta{Kind: "Status", APIVersion: "v1"}
+				writeJSON(t, w, int(status.Code), &status)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+			w.Write(body)
+		case http.MethodPost:
+			assert.Equal(t, FieldManager, r.URL.Query().Get("fieldManager"), "unexpected field manager")
+			w.WriteHeader(http.StatusCreated)
+			w.Write(body)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	return s
+}
+
+func writeJSON(t *testing.T, w http.ResponseWriter, status int, obj interface{}) {
+	w.WriteHeader(status)
+	require.NoError(t, json.NewEncoder(w).Encode(obj), "unexpected error writing response")
+}
+
+func TestServerSideApplierApply(t *testing.T) {
+	cases := []struct {
+		name             string
+		patchStatus      *metav1.Status
+		expectedResult   ApplyResult
+		expectedRequests []string
+		expectConflict   bool
+		expectError      bool
+	}{
+		{
+			name:             "applied",
+			expectedResult:   ConfiguredApplyResult,
+			expectedRequests: []string{"PATCH " + testConfigMapPath},
+		},
+		{
+			name: "created when apply does not create",
+			patchStatus: &metav1.Status{
+				Status: metav1.StatusFailure,
+				Code:   http.StatusNotFound,
+				Reason: metav1.StatusReasonNotFound,
+			},
+			expectedResult:   CreatedApplyResult,
+			expectedRequests: []string{"PATCH " + testConfigMapPath, "POST /api/v1/namespaces/test-namespace/configmaps"},
+		},
+		{
+			name: "conflict",
+			patchStatus: &metav1.Status{
+				Status: metav1.StatusFailure,
+				Code:   http.StatusConflict,
+				Reason: metav1.StatusReasonConflict,
+				Details: &metav1.StatusDetails{
+					Causes: []metav1.StatusCause{{
+						Type:    metav1.CauseTypeFieldManagerConflict,
+						Message: `conflict with "kubectl": .data.key`,
+						Field:   ".data.key",
+					}},
+				},
+			},
+			expectedRequests: []string{"PATCH " + testConfigMapPath},
+			expectConflict:   true,
+			expectError:      true,
+		},
+		{
+			name: "forbidden",
+			patchStatus: &metav1.Status{
+				Status: metav1.StatusFailure,
+				Code:   http.StatusForbidden,
+				Reason: metav1.StatusReasonForbidden,
+			},
+			expectedRequests: []string{"PATCH " + testConfigMapPath},
+			expectError:      true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			server := newFakeAPIServer(t)
+			defer server.Close()
+			server.patchStatus = tc.patchStatus
+			applier, err := NewServerSideApplier(&rest.Config{Host: server.URL}, log.WithField("test", t.Name()))
+			require.NoError(t, err, "unexpected error creating applier")
+
+			result, err := applier.Apply([]byte(testConfigMap))
+			if tc.expectError {
+				assert.Error(t, err, "expected error applying object")
+			} else {
+				assert.NoError(t, err, "unexpected error applying object")
+			}
+			assert.Equal(t, tc.expectConflict, IsApplyConflict(err), "unexpected conflict")
+			if tc.expectConflict {
+				assert.Contains(t, err.Error(), `conflict with "kubectl": .data.key`, "expected conflicting field in error")
+			}
+			assert.Equal(t, tc.expectedResult, result, "unexpected apply result")
+			assert.Equal(t, tc.expectedRequests, server.requests, "objects must be applied without being read first")
+		})
+	}
+}
+
+func TestServerSideApplierInfo(t *testing.T) {
+	server := newFakeAPIServer(t)
+	defer server.Close()
+	applier, err := NewServerSideApplier(&rest.Config{Host: server.URL}, log.WithField("test", t.Name()))
+	require.NoError(t, err, "unexpected error creating applier")
+
+	info, err := applier.Info([]byte(testConfigMap))
+	require.NoError(t, err, "unexpected error getting info")
+	assert.Equal(t, &Info{
+		Name:       "test-config",
+		Namespace:  "test-namespace",
+		Kind:       "ConfigMap",
+		APIVersion: "v1",
+		Resource:   "configmaps",
+	}, info, "unexpected info")
+
+	_, err = applier.Info([]byte("apiVersion: example.com/v1\nkind: Unknown\nmetadata:\n  name: test\n"))
+	assert.Error(t, err, "expected error for unknown kind")
+}
+
+func TestRESTMapperCache(t *testing.T) {
+	server := newFakeAPIServer(t)
+	defer server.Close()
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(&rest.Config{Host: server.URL})
+	require.NoError(t, err, "unexpected error creating discovery client")
+	fakeClock := clock.NewFakeClock(time.Now())
+	cache := newRESTMapperCache(fakeClock)
+	configMapGVK := schema.GroupVersionKind{Version: "v1", Kind: "ConfigMap"}
+	unknownGVK := schema.GroupVersionKind{Group: "example.com", Version: "v1", Kind: "Unknown"}
+
+	mapper := cache.get(server.URL)
+	_, err = mapper.restMapping(configMapGVK, discoveryClient)
+	require.NoError(t, err, "unexpected error mapping kind")
+	_, err = cache.get(server.URL).restMapping(configMapGVK, discoveryClient)
+	require.NoError(t, err, "unexpected error mapping kind")
+	assert.Equal(t, 1, server.discoveries, "API resources must be discovered once per cluster")
+
+	_, err = mapper.restMapping(unknownGVK, discoveryClient)
+	assert.Error(t, err, "expected error mapping unknown kind")
+	assert.Equal(t, 1, server.discoveries, "API resources must not be discovered again within the rediscovery interval")
+
+	fakeClock.Step(rediscoveryInterval)
+	_, err = mapper.restMapping(unknownGVK, discoveryClient)
+	assert.Error(t, err, "expected error mapping unknown kind")
+	assert.Equal(t, 2, server.discoveries, "API resources must be discovered again for unknown kinds")
+
+	fakeClock.Step(restMapperIdleTimeout + time.Second)
+	assert.False(t, cache.get(server.URL) == mapper, "idle mappers must be dropped")
+}
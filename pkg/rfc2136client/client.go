@@ -0,0 +1,197 @@
+package rfc2136client
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/miekg/dns"
+	"github.com/pkg/errors"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+//go:generate mockgen -source=./client.go -destination=./mock/client_generated.go -package=mock
+
+const (
+	// tsigKeyNameKey is the key of the TSIG key name in the TSIG key secret.
+	tsigKeyNameKey = "keyName"
+	// tsigAlgorithmKey is the key of the TSIG algorithm in the TSIG key secret. It defaults to hmac-sha256.
+	tsigAlgorithmKey = "algorithm"
+	// tsigSecretKey is the key of the base64-encoded TSIG secret in the TSIG key secret.
+	tsigSecretKey = "secret"
+
+	defaultPort    = "53"
+	tsigFudge      = 300
+	requestTimeout = 30 * time.Second
+)
+
+// Client is a wrapper object for querying and updating a DNS server through RFC2136 dynamic updates, authenticated
+// with a TSIG key, to allow for easier mocking/testing.
+type Client interface {
+	// GetNameServers queries the DNS server for the name servers of the specified zone. It returns nil when the
+	// DNS server is not authoritative for the zone.
+	GetNameServers(zone string) ([]string, error)
+
+	// ListRecords transfers all of the records of the specified zone from the DNS server.
+	ListRecords(zone string) ([]dns.RR, error)
+
+	// ReplaceRecords replaces the records with the specified name and type in the specified zone with records
+	// having the specified values.
+	ReplaceRecords(zone string, name string, rrType uint16, ttl uint32, values []string) error
+
+	// DeleteRecords deletes the records with the specified name and type in the specified zone.
+	DeleteRecords(zone string, name string, rrType uint16) error
+}
+
+type rfc2136Client struct {
+	server        string
+	tsigKeyName   string
+	tsigAlgorithm string
+	tsigSecret    string
+}
+
+func (c *rfc2136Client) GetNameServers(zone string) ([]string, error) {
+	m := new(dns.Msg)
+	m.SetQuestion(dns.Fqdn(zone), dns.TypeNS)
+	r, err := c.exchange(m)
+	if err != nil {
+		return nil, err
+	}
+	switch r.Rcode {
+	case dns.RcodeSuccess:
+	case dns.RcodeNameError, dns.RcodeRefused, dns.RcodeNotAuth:
+		return nil, nil
+	default:
+		return nil, errors.Errorf("unexpected response code %s", dns.RcodeToString[r.Rcode])
+	}
+	// A server that is not authoritative for the zone answers with a referral to the name servers of the zone.
+	if !r.Authoritative {
+		return nil, nil
+	}
+	nameServers := []string{}
+	for _, rr := range r.Answer {
+		if ns, ok := rr.(*dns.NS); ok && dns.Fqdn(zone) == ns.Hdr.Name {
+			nameServers = append(nameServers, ns.Ns)
+		}
+	}
+	return nameServers, nil
+}
+
+func (c *rfc2136Client) ListRecords(zone string) ([]dns.RR, error) {
+	m := new(dns.Msg)
+	m.SetAxfr(dns.Fqdn(zone))
+	c.setTsig(m)
+	t := &dns.Transfer{
+		DialTimeout:  requestTimeout,
+		ReadTimeout:  requestTimeout,
+		WriteTimeout: requestTimeout,
+	}
+	if c.tsigKeyName != "" {
+		t.TsigSecret = map[string]string{c.tsigKeyName: c.tsigSecret}
+	}
+	envelopes, err := t.In(m, c.server)
+	if err != nil {
+		return nil, err
+	}
+	var records []dns.RR
+	for e := range envelopes {
+		if e.Error != nil {
+			return nil, e.Error
+		}
+		records = append(records, e.RR...)
+	}
+	return records, nil
+}
+
+func (c *rfc2136Client) ReplaceRecords(zone string, name string, rrType uint16, ttl uint32, values []string) error {
+	records := make([]dns.RR, len(values))
+	for i, v := range values {
+		rr, err := dns.NewRR(fmt.Sprintf("%s %d IN %s %s", dns.Fqdn(name), ttl, dns.TypeToString[rrType], v))
+		if err != nil {
+			return errors.Wrapf(err, "invalid record value %q", v)
+		}
+		records[i] = rr
+	}
+	m := new(dns.Msg)
+	m.SetUpdate(dns.Fqdn(zone))
+	m.RemoveRRset([]dns.RR{c.rrsetHeader(name, rrType)})
+	m.Insert(records)
+	return c.update(m)
+}
+
+func (c *rfc2136Client) DeleteRecords(zone string, name string, rrType uint16) error {
+	m := new(dns.Msg)
+	m.SetUpdate(dns.Fqdn(zone))
+	m.RemoveRRset([]dns.RR{c.rrsetHeader(name, rrType)})
+	return c.update(m)
+}
+
+func (c *rfc2136Client) rrsetHeader(name string, rrType uint16) dns.RR {
+	return &dns.ANY{Hdr: dns.RR_Header{Name: dns.Fqdn(name), Rrtype: rrType, Class: dns.ClassANY}}
+}
+
+func (c *rfc2136Client) update(m *dns.Msg) error {
+	r, err := c.exchange(m)
+	if err != nil {
+		return err
+	}
+	if r.Rcode != dns.RcodeSuccess {
+		return errors.Errorf("dynamic update failed with response code %s", dns.RcodeToString[r.Rcode])
+	}
+	return nil
+}
+
+// exchange sends the message to the DNS server over TCP, so that large responses are not truncated.
+func (c *rfc2136Client) exchange(m *dns.Msg) (*dns.Msg, error) {
+	c.setTsig(m)
+	client := &dns.Client{
+		Net:     "tcp",
+		Timeout: requestTimeout,
+	}
+	if c.tsigKeyName != "" {
+		client.TsigSecret = map[string]string{c.tsigKeyName: c.tsigSecret}
+	}
+	r, _, err := client.Exchange(m, c.server)
+	return r, err
+}
+
+func (c *rfc2136Client) setTsig(m *dns.Msg) {
+	if c.tsigKeyName != "" {
+		m.SetTsig(c.tsigKeyName, c.tsigAlgorithm, tsigFudge, time.Now().Unix())
+	}
+}
+
+// NewClient creates our client wrapper object for sending queries and dynamic updates to the specified DNS server,
+// which is a host with an optional port. When the TSIG key name is empty, the requests are not signed.
+func NewClient(server, tsigKeyName, tsigAlgorithm, tsigSecret string) Client {
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, defaultPort)
+	}
+	if tsigAlgorithm == "" {
+		tsigAlgorithm = dns.HmacSHA256
+	}
+	c := &rfc2136Client{
+		server:        server,
+		tsigAlgorithm: dns.Fqdn(tsigAlgorithm),
+		tsigSecret:    tsigSecret,
+	}
+	if tsigKeyName != "" {
+		c.tsigKeyName = dns.Fqdn(tsigKeyName)
+	}
+	return c
+}
+
+// NewClientFromSecret creates our client wrapper object for sending queries and dynamic updates to the specified
+// DNS server. The TSIG key is read from the specified secret.
+func NewClientFromSecret(server string, secret *corev1.Secret) (Client, error) {
+	keyName, ok := secret.Data[tsigKeyNameKey]
+	if !ok {
+		return nil, errors.Errorf("TSIG key secret does not contain %q data", tsigKeyNameKey)
+	}
+	tsigSecret, ok := secret.Data[tsigSecretKey]
+	if !ok {
+		return nil, errors.Errorf("TSIG key secret does not contain %q data", tsigSecretKey)
+	}
+	return NewClient(server, string(keyName), string(secret.Data[tsigAlgorithmKey]), string(tsigSecret)), nil
+}
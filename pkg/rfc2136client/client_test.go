@@ -0,0 +1,168 @@
+package rfc2136client
+
+import (
+	"testing"
+
+	"github.com/miekg/dns"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/openshift/hive/pkg/test/dnsserver"
+)
+
+func testClient(server *dnsserver.Server) Client {
+	return NewClient(server.Addr, dnsserver.TSIGKeyName, "", dnsserver.TSIGSecret)
+}
+
+func TestGetNameServers(t *testing.T) {
+	server := dnsserver.Start(t, map[string][]string{
+		"example.com": {"ns1.example.com", "ns2.example.com"},
+	})
+	require.NoError(t, testClient(server).ReplaceRecords("example.com", "delegated.example.com", dns.TypeNS, 60, []string{"ns.other.com."}))
+
+	cases := []struct {
+		name                string
+		zone                string
+		expectedNameServers []string
+	}{
+		{
+			name:                "hosted zone",
+			zone:                "example.com",
+			expectedNameServers: []string{"ns1.example.com.", "ns2.example.com."},
+		},
+		{
+			name: "delegated zone",
+			zone: "delegated.example.com",
+		},
+		{
+			name: "missing zone in hosted zone",
+			zone: "missing.example.com",
+		},
+		{
+			name: "zone not hosted",
+			zone: "other.com",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			nameServers, err := testClient(server).GetNameServers(tc.zone)
+			require.NoError(t, err, "unexpected error getting name servers")
+			assert.Equal(t, tc.expectedNameServers, nameServers, "unexpected name servers")
+		})
+	}
+}
+
+func TestUpdates(t *testing.T) {
+	server := dnsserver.Start(t, map[string][]string{
+		"example.com": {"ns1.example.com"},
+	})
+	c := testClient(server)
+
+	err := c.ReplaceRecords("example.com", "sub.example.com", dns.TypeNS, 60, []string{"ns-a.other.com", "ns-b.other.com."})
+	require.NoError(t, err, "unexpected error creating records")
+	assert.Equal(t, []string{"ns-a.other.com.", "ns-b.other.com."}, server.Records("sub.example.com", dns.TypeNS), "unexpected records after create")
+
+	err = c.ReplaceRecords("example.com", "sub.example.com", dns.TypeNS, 60, []string{"ns-c.other.com"})
+	require.NoError(t, err, "unexpected error replacing records")
+	assert.Equal(t, []string{"ns-c.other.com."}, server.Records("sub.example.com", dns.TypeNS), "unexpected records after replace")
+
+	records, err := c.ListRecords("example.com")
+	require.NoError(t, err, "unexpected error listing records")
+	var names []string
+	for _, rr := range records {
+		if rr.Header().Rrtype == dns.TypeNS {
+			names = append(names, rr.Header().Name)
+		}
+	}
+	assert.Equal(t, []string{"example.com.", "sub.example.com."}, names, "unexpected NS records listed")
+
+	err = c.DeleteRecords("example.com", "sub.example.com", dns.TypeNS)
+	require.NoError(t, err, "unexpected error deleting records")
+	assert.Empty(t, server.Records("sub.example.com", dns.TypeNS), "unexpected records after delete")
+
+	err = c.DeleteRecords("other.com", "sub.other.com", dns.TypeNS)
+	assert.Error(t, err, "expected error updating zone that is not hosted")
+}
+
+func TestUnauthenticatedUpdate(t *testing.T) {
+	server := dnsserver.Start(t, map[string][]string{
+		"example.com": {"ns1.example.com"},
+	})
+	cases := []struct {
+		name   string
+		client Client
+	}{
+		{
+			name:   "unsigned",
+			client: NewClient(server.Addr, "", "", ""),
+		},
+		{
+			name:   "wrong secret",
+			client: NewClient(server.Addr, dnsserver.TSIGKeyName, "", "d3Jvbmctc2VjcmV0"),
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.client.ReplaceRecords("example.com", "sub.example.com", dns.TypeNS, 60, []string{"ns.other.com"})
+			assert.Error(t, err, "expected error from unauthenticated update")
+			assert.Empty(t, server.Records("sub.example.com", dns.TypeNS), "unexpected records after unauthenticated update")
+		})
+	}
+}
+
+func TestNewClientFromSecret(t *testing.T) {
+	cases := []struct {
+		name              string
+		data              map[string]string
+		expectedClient    *rfc2136Client
+		expectedErrorText string
+	}{
+		{
+			name: "default algorithm",
+			data: map[string]string{"keyName": "test-key", "secret": "dGVzdA=="},
+			expectedClient: &rfc2136Client{
+				server:        "ns.example.com:53",
+				tsigKeyName:   "test-key.",
+				tsigAlgorithm: dns.HmacSHA256,
+				tsigSecret:    "dGVzdA==",
+			},
+		},
+		{
+			name: "algorithm",
+			data: map[string]string{"keyName": "test-key", "secret": "dGVzdA==", "algorithm": "hmac-sha512"},
+			expectedClient: &rfc2136Client{
+				server:        "ns.example.com:53",
+				tsigKeyName:   "test-key.",
+				tsigAlgorithm: dns.HmacSHA512,
+				tsigSecret:    "dGVzdA==",
+			},
+		},
+		{
+			name:              "missing key name",
+			data:              map[string]string{"secret": "dGVzdA=="},
+			expectedErrorText: `TSIG key secret does not contain "keyName" data`,
+		},
+		{
+			name:              "missing secret",
+			data:              map[string]string{"keyName": "test-key"},
+			expectedErrorText: `TSIG key secret does not contain "secret" data`,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			secret := &corev1.Secret{Data: map[string][]byte{}}
+			for k, v := range tc.data {
+				secret.Data[k] = []byte(v)
+			}
+			c, err := NewClientFromSecret("ns.example.com", secret)
+			if tc.expectedErrorText != "" {
+				assert.EqualError(t, err, tc.expectedErrorText, "unexpected error")
+				return
+			}
+			require.NoError(t, err, "unexpected error creating client")
+			assert.Equal(t, tc.expectedClient, c, "unexpected client")
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package syncsettemplate
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+	"github.com/openshift/hive/pkg/constants"
+)
+
+// Data is the data available to templated SyncSet resources.
+type Data struct {
+	// ClusterName is the name of the ClusterDeployment.
+	ClusterName string
+	// ClusterNamespace is the namespace of the ClusterDeployment.
+	ClusterNamespace string
+	// InfraID is the infrastructure ID of the installed cluster.
+	InfraID string
+	// ClusterID is the cluster ID of the installed cluster.
+	ClusterID string
+	// BaseDomain is the base domain of the cluster.
+	BaseDomain string
+	// Platform is the value of the cluster platform label of the ClusterDeployment.
+	Platform string
+	// Region is the value of the cluster region label of the ClusterDeployment.
+	Region string
+	// Annotations are the annotations of the ClusterDeployment with the SyncSetTemplateAnnotationPrefix, keyed by
+	// the remainder of the annotation key.
+	Annotations map[string]string
+	// Parameters is the data of the ConfigMap referenced by the TemplateParametersRef of the SyncSet.
+	Parameters map[string]string
+}
+
+// NewData returns the template data for the given ClusterDeployment and template parameters.
+func NewData(cd *hivev1.ClusterDeployment, parameters map[string]string) *Data {
+	data := &Data{
+		ClusterName:      cd.Name,
+		ClusterNamespace: cd.Namespace,
+		BaseDomain:       cd.Spec.BaseDomain,
+		Platform:         cd.Labels[hivev1.HiveClusterPlatformLabel],
+		Region:           cd.Labels[hivev1.HiveClusterRegionLabel],
+		Annotations:      map[string]string{},
+		Parameters:       parameters,
+	}
+	if data.Parameters == nil {
+		data.Parameters = map[string]string{}
+	}
+	if cd.Spec.ClusterMetadata != nil {
+		data.InfraID = cd.Spec.ClusterMetadata.InfraID
+		data.ClusterID = cd.Spec.ClusterMetadata.ClusterID
+	}
+	for key, value := range cd.Annotations {
+		if strings.HasPrefix(key, constants.SyncSetTemplateAnnotationPrefix) {
+			data.Annotations[strings.TrimPrefix(key, constants.SyncSetTemplateAnnotationPrefix)] = value
+		}
+	}
+	return data
+}
+
+// Render renders the templates in the string values of the given JSON resource with the given data, and returns
+// the rendered JSON resource. Referencing a missing annotation or parameter is an error.
+func Render(raw []byte, data *Data) ([]byte, error) {
+	obj, err := unmarshal(raw)
+	if err != nil {
+		return nil, err
+	}
+	rendered, err := walkStrings(obj, "", func(path, value string) (string, error) {
+		tmpl, err := parse(path, value)
+		if err != nil {
+			return "", err
+		}
+		buf := &bytes.Buffer{}
+		if err := tmpl.Execute(buf, data); err != nil {
+			return "", err
+		}
+		return buf.String(), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(rendered)
+}
+
+// Validate parses the templates in the string values of the given JSON resource, and returns the first parse
+// error found.
+func Validate(raw []byte) error {
+	obj, err := unmarshal(raw)
+	if err != nil {
+		return err
+	}
+	_, err = walkStrings(obj, "", func(path, value string) (string, error) {
+		_, err := parse(path, value)
+		return value, err
+	})
+	return err
+}
+
+// unmarshal unmarshals a JSON resource, keeping numbers as they are.
+func unmarshal(raw []byte) (interface{}, error) {
+	var obj interface{}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&obj); err != nil {
+		return nil, fmt.Errorf("cannot unmarshal resource: %v", err)
+	}
+	return obj, nil
+}
+
+func parse(path, value string) (*template.Template, error) {
+	return template.New(path).Option("missingkey=error").Parse(value)
+}
+
+// walkStrings calls fn on each string value in obj, and returns a copy of obj with the string values replaced
+// by the results of fn.
+func walkStrings(obj interface{}, path string, fn func(path, value string) (string, error)) (interface{}, error) {
+	switch v := obj.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		result := make(map[string]interface{}, len(v))
+		for _, key := range keys {
+			walked, err := walkStrings(v[key], path+"."+key, fn)
+			if err != nil {
+				return nil, err
+			}
+			result[key] = walked
+		}
+		return result, nil
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, value := range v {
+			walked, err := walkStrings(value, fmt.Sprintf("%s[%d]", path, i), fn)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = walked
+		}
+		return result, nil
+	case string:
+		return fn(path, v)
+	default:
+		return v, nil
+	}
+}
@@ -0,0 +1,92 @@
+package syncsettemplate
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	hivev1 "github.com/openshift/hive/pkg/apis/hive/v1"
+)
+
+func TestRender(t *testing.T) {
+	cd := &hivev1.ClusterDeployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: "test-namespace",
+			Labels: map[string]string{
+				hivev1.HiveClusterPlatformLabel: "aws",
+				hivev1.HiveClusterRegionLabel:   "us-east-1",
+			},
+			Annotations: map[string]string{
+				"template.hive.openshift.io/hostname": "api",
+				"other-annotation":                    "other",
+			},
+		},
+		Spec: hivev1.ClusterDeploymentSpec{
+			BaseDomain: "example.com",
+			ClusterMetadata: &hivev1.ClusterMetadata{
+				InfraID:   "test-cluster-abcde",
+				ClusterID: "test-cluster-id",
+			},
+		},
+	}
+	tests := []struct {
+		name        string
+		resource    string
+		parameters  map[string]string
+		expected    string
+		expectedErr string
+	}{
+		{
+			name:     "no templates",
+			resource: `{"apiVersion":"v1","kind":"ConfigMap","data":{"key":"value"},"spec":{"replicas":3}}`,
+			expected: `{"apiVersion":"v1","data":{"key":"value"},"kind":"ConfigMap","spec":{"replicas":3}}`,
+		},
+		{
+			name:     "cluster deployment fields",
+			resource: `{"data":{"name":"{{ .ClusterName }}/{{ .ClusterNamespace }}","ids":["{{ .InfraID }}","{{ .ClusterID }}"],"location":"{{ .Platform }}/{{ .Region }}"}}`,
+			expected: `{"data":{"ids":["test-cluster-abcde","test-cluster-id"],"location":"aws/us-east-1","name":"test-cluster/test-namespace"}}`,
+		},
+		{
+			name:     "annotations",
+			resource: `{"data":{"host":"{{ .Annotations.hostname }}.{{ .BaseDomain }}"}}`,
+			expected: `{"data":{"host":"api.example.com"}}`,
+		},
+		{
+			name:        "missing annotation",
+			resource:    `{"data":{"host":"{{ .Annotations.missing }}"}}`,
+			expectedErr: `template: .data.host:1:15: executing ".data.host" at <.Annotations.missing>: map has no entry for key "missing"`,
+		},
+		{
+			name:       "parameters",
+			resource:   `{"data":{"url":"{{ .Parameters.url }}"}}`,
+			parameters: map[string]string{"url": "https://example.com/\"quoted\""},
+			expected:   `{"data":{"url":"https://example.com/\"quoted\""}}`,
+		},
+		{
+			name:        "parse error",
+			resource:    `{"data":{"key":"{{ .ClusterName }"}}`,
+			expectedErr: `template: .data.key:1: unexpected "}" in operand`,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			actual, err := Render([]byte(test.resource), NewData(cd, test.parameters))
+			if test.expectedErr != "" {
+				assert.EqualError(t, err, test.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, test.expected, string(actual))
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Validate([]byte(`{"data":{"key":"{{ .Parameters.key }}"}}`)))
+	assert.EqualError(t, Validate([]byte(`{"data":{"key":"{{ undefined }}"}}`)), `template: .data.key:1: function "undefined" not defined`)
+	assert.Error(t, Validate([]byte(`not json`)))
+}
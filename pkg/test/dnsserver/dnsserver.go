@@ -0,0 +1,226 @@
+package dnsserver
+
+import (
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+const (
+	// TSIGKeyName is the name of the TSIG key accepted by the server.
+	TSIGKeyName = "hive-test-key."
+	// TSIGSecret is the base64-encoded secret of the TSIG key accepted by the server.
+	TSIGSecret = "aGl2ZS10ZXN0LXNlY3JldA=="
+)
+
+// Server is an in-process authoritative DNS server, serving queries, zone transfers and RFC2136 dynamic updates
+// over TCP for use in tests. Zone transfers and dynamic updates must be signed with the TSIG key of the server.
+type Server struct {
+	// Addr is the address on which the server is listening.
+	Addr string
+
+	server *dns.Server
+
+	mu      sync.Mutex
+	records map[string][]dns.RR
+}
+
+// Start starts a server hosting the given zones, each with a SOA record and NS records for the given name servers.
+// The server is shut down when the test completes.
+func Start(t *testing.T, zones map[string][]string) *Server {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("could not listen: %v", err)
+	}
+	s := &Server{
+		Addr:    listener.Addr().String(),
+		records: map[string][]dns.RR{},
+	}
+	for zone, nameServers := range zones {
+		s.AddZone(zone, nameServers...)
+	}
+	started := make(chan struct{})
+	s.server = &dns.Server{
+		Listener:          listener,
+		Handler:           dns.HandlerFunc(s.serveDNS),
+		TsigSecret:        map[string]string{TSIGKeyName: TSIGSecret},
+		NotifyStartedFunc: func() { close(started) },
+		// The default accept function rejects dynamic updates.
+		MsgAcceptFunc: func(dns.Header) dns.MsgAcceptAction { return dns.MsgAccept },
+	}
+	go s.server.ActivateAndServe()
+	<-started
+	t.Cleanup(func() { s.server.Shutdown() })
+	return s
+}
+
+// AddZone adds a zone to the server with a SOA record and NS records for the given name servers.
+func (s *Server) AddZone(zone string, nameServers ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	zone = dns.Fqdn(zone)
+	soa, _ := dns.NewRR(zone + " 3600 IN SOA ns." + zone + " hostmaster." + zone + " 1 3600 600 86400 60")
+	records := []dns.RR{soa}
+	for _, ns := range nameServers {
+		records = append(records, &dns.NS{
+			Hdr: dns.RR_Header{Name: zone, Rrtype: dns.TypeNS, Class: dns.ClassINET, Ttl: 3600},
+			Ns:  dns.Fqdn(ns),
+		})
+	}
+	s.records[zone] = records
+}
+
+// Records returns the records of the given name and type in the zones of the server, sorted by value.
+func (s *Server) Records(name string, rrType uint16) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var values []string
+	for _, records := range s.records {
+		for _, rr := range records {
+			if rr.Header().Name == dns.Fqdn(name) && rr.Header().Rrtype == rrType {
+				values = append(values, strings.TrimPrefix(rr.String(), rr.Header().String()))
+			}
+		}
+	}
+	sort.Strings(values)
+	return values
+}
+
+func (s *Server) serveDNS(w dns.ResponseWriter, req *dns.Msg) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m := new(dns.Msg)
+	m.SetReply(req)
+	tsig := req.IsTsig()
+	switch {
+	case tsig != nil && w.TsigStatus() != nil:
+		m.Rcode = dns.RcodeNotAuth
+	case len(req.Question) != 1:
+		m.Rcode = dns.RcodeFormatError
+	case req.Opcode == dns.OpcodeUpdate:
+		if tsig == nil {
+			m.Rcode = dns.RcodeRefused
+			break
+		}
+		m.Rcode = s.update(dns.Fqdn(req.Question[0].Name), req.Ns)
+	case req.Question[0].Qtype == dns.TypeAXFR:
+		if tsig == nil {
+			m.Rcode = dns.RcodeRefused
+			break
+		}
+		records, ok := s.records[dns.Fqdn(req.Question[0].Name)]
+		if !ok {
+			m.Rcode = dns.RcodeNotAuth
+			break
+		}
+		m.Answer = append(append(m.Answer, records...), records[0])
+	default:
+		s.query(m, req.Question[0])
+	}
+	if tsig != nil {
+		m.SetTsig(tsig.Hdr.Name, tsig.Algorithm, 300, time.Now().Unix())
+	}
+	w.WriteMsg(m)
+}
+
+func (s *Server) update(zone string, updates []dns.RR) int {
+	records, ok := s.records[zone]
+	if !ok {
+		return dns.RcodeNotAuth
+	}
+	for _, u := range updates {
+		h := u.Header()
+		if !dns.IsSubDomain(zone, h.Name) {
+			return dns.RcodeNotZone
+		}
+		switch h.Class {
+		case dns.ClassANY:
+			// Delete an RRset, or all RRsets of a name for the ANY type
+			kept := records[:0]
+			for _, rr := range records {
+				if rr.Header().Name != h.Name || (h.Rrtype != dns.TypeANY && rr.Header().Rrtype != h.Rrtype) || rr.Header().Rrtype == dns.TypeSOA {
+					kept = append(kept, rr)
+				}
+			}
+			records = kept
+		case dns.ClassNONE:
+			// Delete an RR from an RRset
+			kept := records[:0]
+			for _, rr := range records {
+				if !isDuplicate(rr, u) {
+					kept = append(kept, rr)
+				}
+			}
+			records = kept
+		default:
+			// Add an RR to an RRset
+			exists := false
+			for _, rr := range records {
+				if dns.IsDuplicate(rr, u) {
+					exists = true
+					break
+				}
+			}
+			if !exists {
+				records = append(records, dns.Copy(u))
+			}
+		}
+	}
+	s.records[zone] = records
+	return dns.RcodeSuccess
+}
+
+func (s *Server) query(m *dns.Msg, q dns.Question) {
+	name := dns.Fqdn(q.Name)
+	zone := ""
+	for z := range s.records {
+		if dns.IsSubDomain(z, name) && len(z) > len(zone) {
+			zone = z
+		}
+	}
+	if zone == "" {
+		m.Rcode = dns.RcodeRefused
+		return
+	}
+	records := s.records[zone]
+	// Refer queries below a delegation to the name servers of the delegated zone.
+	for _, rr := range records {
+		if rr.Header().Rrtype == dns.TypeNS && rr.Header().Name != zone && dns.IsSubDomain(rr.Header().Name, name) {
+			for _, ns := range records {
+				if ns.Header().Rrtype == dns.TypeNS && ns.Header().Name == rr.Header().Name {
+					m.Ns = append(m.Ns, ns)
+				}
+			}
+			return
+		}
+	}
+	m.Authoritative = true
+	nameExists := false
+	for _, rr := range records {
+		if rr.Header().Name != name {
+			continue
+		}
+		nameExists = true
+		if rr.Header().Rrtype == q.Qtype {
+			m.Answer = append(m.Answer, rr)
+		}
+	}
+	if len(m.Answer) == 0 {
+		m.Ns = []dns.RR{records[0]}
+		if !nameExists {
+			m.Rcode = dns.RcodeNameError
+		}
+	}
+}
+
+// isDuplicate returns whether the RR to delete from an RRset, which has class NONE, matches the given RR.
+func isDuplicate(rr dns.RR, toDelete dns.RR) bool {
+	c := dns.Copy(toDelete)
+	c.Header().Class = rr.Header().Class
+	return dns.IsDuplicate(rr, c)
+}